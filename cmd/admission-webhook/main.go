@@ -0,0 +1,322 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	stdlog "log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prometheus-operator/prometheus-operator/pkg/admission"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+	"github.com/prometheus-operator/prometheus-operator/pkg/versionutil"
+
+	rbacproxytls "github.com/brancz/kube-rbac-proxy/pkg/tls"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/version"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	logLevelAll   = "all"
+	logLevelDebug = "debug"
+	logLevelInfo  = "info"
+	logLevelWarn  = "warn"
+	logLevelError = "error"
+	logLevelNone  = "none"
+)
+
+const (
+	logFormatLogfmt = "logfmt"
+	logFormatJSON   = "json"
+)
+
+const defaultTLSDir = "/etc/tls/private"
+
+const (
+	// shutdownGracePeriod is the time during which the webhook keeps serving
+	// requests after /readyz started failing, giving time to the endpoint to
+	// be removed from the Service.
+	shutdownGracePeriod = 5 * time.Second
+	// shutdownTimeout is the maximum time waiting for the in-flight admission
+	// requests when stopping the server.
+	shutdownTimeout = 20 * time.Second
+)
+
+var (
+	availableLogLevels = []string{
+		logLevelAll,
+		logLevelDebug,
+		logLevelInfo,
+		logLevelWarn,
+		logLevelError,
+		logLevelNone,
+	}
+	availableLogFormats = []string{
+		logFormatLogfmt,
+		logFormatJSON,
+	}
+
+	listenAddress string
+	serverTLS     operator.TLSServerConfig
+	logLevel      string
+	logFormat     string
+
+	rawTLSCipherSuites string
+	rawHandlers        string
+
+	admissionFlags *admission.Flags
+
+	flagset = flag.CommandLine
+)
+
+func init() {
+	flagset.StringVar(&listenAddress, "web.listen-address", ":8443", "Address on which to expose the admission webhook and the metrics.")
+	flagset.StringVar(&serverTLS.CertFile, "web.cert-file", defaultTLSDir+"/tls.crt", "Cert file to be used for the webhook server. TLS is disabled if both the cert and key files are empty.")
+	flagset.StringVar(&serverTLS.KeyFile, "web.key-file", defaultTLSDir+"/tls.key", "Private key matching the cert file to be used for the webhook server.")
+	flagset.StringVar(&serverTLS.ClientCAFile, "web.client-ca-file", "", "Client CA certificate file used to verify the client certificates. Client certificates aren't verified if empty.")
+	flagset.DurationVar(&serverTLS.ReloadInterval, "web.tls-reload-interval", time.Minute, "The interval at which to watch for TLS certificate changes.")
+	flagset.StringVar(&serverTLS.MinVersion, "web.tls-min-version", "VersionTLS13",
+		"Minimum TLS version supported. Value must match version names from https://golang.org/pkg/crypto/tls/#pkg-constants.")
+	flagset.StringVar(&rawTLSCipherSuites, "web.tls-cipher-suites", "", "Comma-separated list of cipher suites for the server."+
+		" Values are from tls package constants (https://golang.org/pkg/crypto/tls/#pkg-constants)."+
+		" If omitted, the default Go cipher suites will be used."+
+		" Note that TLS 1.3 ciphersuites are not configurable.")
+	flagset.StringVar(&logLevel, "log-level", logLevelInfo, fmt.Sprintf("Log level to use. Possible values: %s", strings.Join(availableLogLevels, ", ")))
+	flagset.StringVar(&logFormat, "log-format", logFormatLogfmt, fmt.Sprintf("Log format to use. Possible values: %s", strings.Join(availableLogFormats, ", ")))
+	flagset.StringVar(&rawHandlers, "handlers", strings.Join(admission.AvailableHandlers, ","), fmt.Sprintf("Comma-separated list of the enabled handlers. Possible values: %s", strings.Join(admission.AvailableHandlers, ", ")))
+	admissionFlags = admission.NewFlags(flagset, true)
+}
+
+func serve(srv *http.Server, listener net.Listener, logger log.Logger) func() error {
+	return func() error {
+		level.Info(logger).Log("msg", "Starting insecure server on "+listener.Addr().String())
+		if err := srv.Serve(listener); err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	}
+}
+
+func serveTLS(srv *http.Server, listener net.Listener, logger log.Logger) func() error {
+	return func() error {
+		level.Info(logger).Log("msg", "Starting secure server on "+listener.Addr().String())
+		if err := srv.ServeTLS(listener, "", ""); err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	}
+}
+
+func parseHandlers(raw string) ([]string, error) {
+	var handlers []string
+	for _, h := range strings.Split(raw, ",") {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+
+		var found bool
+		for _, available := range admission.AvailableHandlers {
+			if h == available {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown handler %q, possible values: %s", h, strings.Join(admission.AvailableHandlers, ", "))
+		}
+
+		handlers = append(handlers, h)
+	}
+
+	if len(handlers) == 0 {
+		return nil, fmt.Errorf("at least one handler must be enabled")
+	}
+
+	return handlers, nil
+}
+
+func Main() int {
+	versionutil.RegisterFlags()
+	// No need to check for errors because Parse would exit on error.
+	_ = flagset.Parse(os.Args[1:])
+
+	if versionutil.ShouldPrintVersion() {
+		versionutil.Print(os.Stdout, "admission-webhook")
+		return 0
+	}
+
+	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stdout))
+	if logFormat == logFormatJSON {
+		logger = log.NewJSONLogger(log.NewSyncWriter(os.Stdout))
+	}
+	switch logLevel {
+	case logLevelAll:
+		logger = level.NewFilter(logger, level.AllowAll())
+	case logLevelDebug:
+		logger = level.NewFilter(logger, level.AllowDebug())
+	case logLevelInfo:
+		logger = level.NewFilter(logger, level.AllowInfo())
+	case logLevelWarn:
+		logger = level.NewFilter(logger, level.AllowWarn())
+	case logLevelError:
+		logger = level.NewFilter(logger, level.AllowError())
+	case logLevelNone:
+		logger = level.NewFilter(logger, level.AllowNone())
+	default:
+		fmt.Fprintf(os.Stderr, "log level %v unknown, %v are possible values", logLevel, availableLogLevels)
+		return 1
+	}
+	logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+	logger = log.With(logger, "caller", log.DefaultCaller)
+
+	level.Info(logger).Log("msg", "Starting admission webhook", "version", version.Info())
+	level.Info(logger).Log("build_context", version.BuildContext())
+
+	handlers, err := parseHandlers(rawHandlers)
+	if err != nil {
+		fmt.Fprint(os.Stderr, "invalid handlers: ", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	admissionCfg, closeAdmission, err := admissionFlags.Config(ctx, "admission-webhook", log.With(logger, "component", "admissionwebhook"))
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		return 1
+	}
+	defer closeAdmission()
+	admissionCfg.Handlers = handlers
+
+	if rawTLSCipherSuites != "" {
+		serverTLS.CipherSuites = strings.Split(rawTLSCipherSuites, ",")
+	}
+	tlsConfig, err := operator.NewTLSConfig(logger, serverTLS.CertFile, serverTLS.KeyFile,
+		serverTLS.ClientCAFile, serverTLS.MinVersion, serverTLS.CipherSuites)
+	if err != nil {
+		fmt.Fprint(os.Stderr, "invalid TLS config", err)
+		return 1
+	}
+
+	wg, ctx := errgroup.WithContext(ctx)
+
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		version.NewCollector("prometheus_operator_admission_webhook"),
+	)
+
+	admit := admission.New(log.With(logger, "component", "admissionwebhook"), admissionCfg)
+	admit.RegisterMetrics(r)
+
+	mux := http.NewServeMux()
+	admit.Register(mux)
+	admit.RegisterHealth(mux)
+	mux.Handle("/metrics", promhttp.HandlerFor(r, promhttp.HandlerOpts{}))
+
+	if tlsConfig != nil {
+		certReloader, err := rbacproxytls.NewCertReloader(
+			serverTLS.CertFile,
+			serverTLS.KeyFile,
+			serverTLS.ReloadInterval,
+		)
+		if err != nil {
+			fmt.Fprint(os.Stderr, "failed to initialize certificate reloader", err)
+			return 1
+		}
+
+		tlsConfig.GetCertificate = certReloader.GetCertificate
+		r.MustRegister(operator.NewCertificateExpiryCollector(logger, certReloader.GetCertificate))
+
+		wg.Go(func() error {
+			for {
+				// certReloader.Watch will wait ReloadInterval, so this is not
+				// a hot loop
+				if err := certReloader.Watch(ctx); err != nil {
+					level.Warn(logger).Log("msg", "error watching certificate reloader",
+						"err", err)
+				} else {
+					return nil
+				}
+			}
+		})
+	}
+
+	l, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		fmt.Fprint(os.Stderr, "listening failed", listenAddress, err)
+		return 1
+	}
+
+	srv := &http.Server{
+		Handler:   mux,
+		TLSConfig: tlsConfig,
+		ErrorLog:  stdlog.New(log.NewStdlibAdapter(logger), "", stdlog.Lshortfile),
+	}
+	if srv.TLSConfig == nil {
+		wg.Go(serve(srv, l, logger))
+	} else {
+		wg.Go(serveTLS(srv, l, logger))
+	}
+
+	term := make(chan os.Signal, 1)
+	signal.Notify(term, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case <-term:
+		level.Info(logger).Log("msg", "Received SIGTERM, exiting gracefully...")
+	case <-ctx.Done():
+	}
+
+	// Fail the readiness probe first and keep serving during the grace
+	// period, then stop the server once the in-flight requests are done.
+	if err := admit.Shutdown(ctx, shutdownGracePeriod); err != nil {
+		level.Warn(logger).Log("msg", "Admission webhook shutdown error", "err", err)
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		level.Warn(logger).Log("msg", "Server shutdown error", "err", err)
+	}
+
+	cancel()
+	if err := wg.Wait(); err != nil {
+		level.Warn(logger).Log("msg", "Unhandled error received. Exiting...", "err", err)
+		return 1
+	}
+
+	return 0
+}
+
+func main() {
+	os.Exit(Main())
+}
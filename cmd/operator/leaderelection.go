@@ -0,0 +1,190 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// The durations match the defaults of the Kubernetes controllers.
+const (
+	defaultLeaderElectionLeaseDuration = 15 * time.Second
+	defaultLeaderElectionRenewDeadline = 10 * time.Second
+	defaultLeaderElectionRetryPeriod   = 2 * time.Second
+)
+
+// leaderElectionConfig holds the settings of the lease-based leader election
+// between the replicas of the operator.
+type leaderElectionConfig struct {
+	Enabled       bool
+	Namespace     string
+	LeaseName     string
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+type leaderElectionMetrics struct {
+	isLeader    prometheus.Gauge
+	transitions prometheus.Counter
+}
+
+func newLeaderElectionMetrics(r prometheus.Registerer) *leaderElectionMetrics {
+	m := &leaderElectionMetrics{
+		isLeader: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "prometheus_operator_leader_election_is_leader",
+			Help: "Whether the operator instance is the current leader (1) or not (0).",
+		}),
+		transitions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_operator_leader_election_transitions_total",
+			Help: "Number of times the operator instance acquired the leadership.",
+		}),
+	}
+
+	r.MustRegister(m.isLeader, m.transitions)
+
+	return m
+}
+
+// leaderElectionNamespace returns the namespace of the Lease object. It
+// defaults to the namespace of the operator's pod when not configured.
+func leaderElectionNamespace(namespace string) (string, error) {
+	if namespace != "" {
+		return namespace, nil
+	}
+
+	b, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to detect the namespace of the operator, use --leader-election-namespace: %w", err)
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// runWithLeaderElection calls run once the operator instance acquires the
+// leadership. It returns when ctx is canceled, when run returns or when the
+// leadership is lost. In the latter case, an error is returned so that the
+// operator exits instead of reconciling concurrently with the new leader.
+// The lease is released when ctx is canceled or when run returns.
+func runWithLeaderElection(
+	ctx context.Context,
+	config leaderElectionConfig,
+	kclient kubernetes.Interface,
+	logger log.Logger,
+	metrics *leaderElectionMetrics,
+	run func(context.Context) error,
+) error {
+	namespace, err := leaderElectionNamespace(config.Namespace)
+	if err != nil {
+		return err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to get the hostname: %w", err)
+	}
+	identity := hostname + "_" + string(uuid.NewUUID())
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      config.LeaseName,
+			Namespace: namespace,
+		},
+		Client: kclient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	electionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		runErr = make(chan error, 1)
+		// lost is set when the leadership stops while neither ctx is
+		// canceled nor run has returned. OnStoppedLeading is called from
+		// elector.Run which makes the access safe.
+		lost bool
+	)
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   config.LeaseDuration,
+		RenewDeadline:   config.RenewDeadline,
+		RetryPeriod:     config.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            config.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				level.Info(logger).Log("msg", "leadership acquired", "identity", identity)
+				metrics.isLeader.Set(1)
+				metrics.transitions.Inc()
+
+				runErr <- run(ctx)
+				// Release the lease when the controllers stop on their own.
+				cancel()
+			},
+			OnStoppedLeading: func() {
+				metrics.isLeader.Set(0)
+				if electionCtx.Err() == nil {
+					lost = true
+					level.Warn(logger).Log("msg", "leadership lost", "identity", identity)
+					return
+				}
+				level.Info(logger).Log("msg", "leadership released", "identity", identity)
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					level.Info(logger).Log("msg", "new leader elected", "leader", leader)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("invalid leader election configuration: %w", err)
+	}
+
+	level.Info(logger).Log("msg", "waiting for the leadership", "lease", namespace+"/"+config.LeaseName, "identity", identity)
+	elector.Run(electionCtx)
+
+	select {
+	case err := <-runErr:
+		if err != nil {
+			return err
+		}
+	default:
+	}
+
+	if lost {
+		return errors.New("leadership lost")
+	}
+
+	return nil
+}
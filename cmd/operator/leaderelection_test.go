@@ -0,0 +1,161 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testLeaderElectionConfig() leaderElectionConfig {
+	return leaderElectionConfig{
+		Enabled:       true,
+		Namespace:     "default",
+		LeaseName:     "prometheus-operator",
+		LeaseDuration: 2 * time.Second,
+		RenewDeadline: time.Second,
+		RetryPeriod:   100 * time.Millisecond,
+	}
+}
+
+func TestRunWithLeaderElection(t *testing.T) {
+	kclient := fake.NewSimpleClientset()
+	metrics := newLeaderElectionMetrics(prometheus.NewRegistry())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	leading := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- runWithLeaderElection(ctx, testLeaderElectionConfig(), kclient, log.NewNopLogger(), metrics, func(ctx context.Context) error {
+			close(leading)
+			<-ctx.Done()
+			return nil
+		})
+	}()
+
+	select {
+	case <-leading:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the leadership")
+	}
+
+	if v := testutil.ToFloat64(metrics.isLeader); v != 1 {
+		t.Fatalf("expected is_leader to be 1, got %v", v)
+	}
+	if v := testutil.ToFloat64(metrics.transitions); v != 1 {
+		t.Fatalf("expected 1 transition, got %v", v)
+	}
+
+	lease, err := kclient.CoordinationV1().Leases("default").Get(context.Background(), "prometheus-operator", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the lease: %v", err)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+		t.Fatal("expected the lease to have a holder")
+	}
+
+	cancel()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("expected no error on shutdown, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the leader election to stop")
+	}
+
+	if v := testutil.ToFloat64(metrics.isLeader); v != 0 {
+		t.Fatalf("expected is_leader to be 0, got %v", v)
+	}
+}
+
+func TestRunWithLeaderElectionError(t *testing.T) {
+	kclient := fake.NewSimpleClientset()
+	metrics := newLeaderElectionMetrics(prometheus.NewRegistry())
+
+	expected := errors.New("controller failure")
+	err := runWithLeaderElection(context.Background(), testLeaderElectionConfig(), kclient, log.NewNopLogger(), metrics, func(context.Context) error {
+		return expected
+	})
+	if !errors.Is(err, expected) {
+		t.Fatalf("expected %v, got %v", expected, err)
+	}
+}
+
+func TestRunWithLeaderElectionCleanExit(t *testing.T) {
+	kclient := fake.NewSimpleClientset()
+	metrics := newLeaderElectionMetrics(prometheus.NewRegistry())
+
+	err := runWithLeaderElection(context.Background(), testLeaderElectionConfig(), kclient, log.NewNopLogger(), metrics, func(context.Context) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error when the controllers stop, got %v", err)
+	}
+}
+
+func TestRunWithLeaderElectionLost(t *testing.T) {
+	kclient := fake.NewSimpleClientset()
+	metrics := newLeaderElectionMetrics(prometheus.NewRegistry())
+
+	leading := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- runWithLeaderElection(context.Background(), testLeaderElectionConfig(), kclient, log.NewNopLogger(), metrics, func(ctx context.Context) error {
+			close(leading)
+			<-ctx.Done()
+			return nil
+		})
+	}()
+
+	select {
+	case <-leading:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the leadership")
+	}
+
+	// Another instance takes over the lease.
+	lease, err := kclient.CoordinationV1().Leases("default").Get(context.Background(), "prometheus-operator", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the lease: %v", err)
+	}
+	holder, duration, now := "other", int32(3600), metav1.NewMicroTime(time.Now())
+	lease.Spec.HolderIdentity = &holder
+	lease.Spec.LeaseDurationSeconds = &duration
+	lease.Spec.AcquireTime = &now
+	lease.Spec.RenewTime = &now
+	if _, err := kclient.CoordinationV1().Leases("default").Update(context.Background(), lease, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update the lease: %v", err)
+	}
+
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Fatal("expected an error when the leadership is lost, got none")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the leadership to be lost")
+	}
+}
@@ -49,6 +49,7 @@ import (
 	"golang.org/x/sync/errgroup"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
 	klog "k8s.io/klog/v2"
 )
 
@@ -70,6 +71,10 @@ const (
 	defaultOperatorTLSDir = "/etc/tls/private"
 )
 
+// shutdownTimeout is the maximum time waiting for the in-flight requests when
+// stopping the web server.
+const shutdownTimeout = 20 * time.Second
+
 const (
 	defaultReloaderCPU    = "100m"
 	defaultReloaderMemory = "50Mi"
@@ -81,6 +86,9 @@ var (
 	prometheusNs   = namespaces{}
 	alertmanagerNs = namespaces{}
 	thanosRulerNs  = namespaces{}
+
+	crossNamespaceInhibitionNs = namespaces{}
+	amConfigSecretNs           = namespaces{}
 )
 
 type namespaces map[string]struct{}
@@ -144,8 +152,17 @@ var (
 	}
 	cfg = operator.Config{}
 
-	rawTLSCipherSuites string
-	serverTLS          bool
+	admissionFlags *admission.Flags
+
+	rawTLSCipherSuites         string
+	serverTLS                  bool
+	alertmanagerConfigPreview  bool
+	alertmanagerReloadStrategy string
+	leaderElection             = leaderElectionConfig{
+		LeaseDuration: defaultLeaderElectionLeaseDuration,
+		RenewDeadline: defaultLeaderElectionRenewDeadline,
+		RetryPeriod:   defaultLeaderElectionRetryPeriod,
+	}
 
 	flagset = flag.CommandLine
 )
@@ -165,6 +182,7 @@ func init() {
 		" Values are from tls package constants (https://golang.org/pkg/crypto/tls/#pkg-constants)."+
 		"If omitted, the default Go cipher suites will be used."+
 		"Note that TLS 1.3 ciphersuites are not configurable.")
+	flagset.BoolVar(&alertmanagerConfigPreview, "web.enable-alertmanager-config-preview", false, "Serve the configuration generated for the Alertmanager resources, with the credentials redacted, under /apis/monitoring.coreos.com/v1/namespaces/<namespace>/alertmanagers/<name>/config.")
 	flagset.StringVar(&cfg.Host, "apiserver", "", "API Server addr, e.g. ' - NOT RECOMMENDED FOR PRODUCTION - http://127.0.0.1:8080'. Omit parameter to run in on-cluster mode and utilize the service account token.")
 	flagset.StringVar(&cfg.TLSConfig.CertFile, "cert-file", "", " - NOT RECOMMENDED FOR PRODUCTION - Path to public TLS certificate file.")
 	flagset.StringVar(&cfg.TLSConfig.KeyFile, "key-file", "", "- NOT RECOMMENDED FOR PRODUCTION - Path to private TLS certificate file.")
@@ -180,14 +198,24 @@ func init() {
 	flagset.StringVar(&cfg.ReloaderConfig.CPULimit, "config-reloader-cpu-limit", defaultReloaderCPU, "Config Reloader CPU limit. Value \"0\" disables it and causes no limit to be configured. Flag overrides `--config-reloader-cpu` for the CPU limit")
 	flagset.StringVar(&cfg.ReloaderConfig.MemoryRequest, "config-reloader-memory-request", defaultReloaderMemory, "Config Reloader Memory request. Value \"0\" disables it and causes no request to be configured. Flag overrides `--config-reloader-memory` for the memory request")
 	flagset.StringVar(&cfg.ReloaderConfig.MemoryLimit, "config-reloader-memory-limit", defaultReloaderMemory, "Config Reloader Memory limit. Value \"0\" disables it and causes no limit to be configured. Flag overrides `--config-reloader-memory` for the memory limit")
+	flagset.StringVar(&cfg.ThanosSidecarConfig.CPURequest, "thanos-sidecar-cpu-request", "", "Default CPU request of the Thanos sidecar containers. The value defined in the Prometheus resource takes precedence. No request is configured by default.")
+	flagset.StringVar(&cfg.ThanosSidecarConfig.CPULimit, "thanos-sidecar-cpu-limit", "", "Default CPU limit of the Thanos sidecar containers. The value defined in the Prometheus resource takes precedence. No limit is configured by default.")
+	flagset.StringVar(&cfg.ThanosSidecarConfig.MemoryRequest, "thanos-sidecar-memory-request", "", "Default memory request of the Thanos sidecar containers. The value defined in the Prometheus resource takes precedence. No request is configured by default.")
+	flagset.StringVar(&cfg.ThanosSidecarConfig.MemoryLimit, "thanos-sidecar-memory-limit", "", "Default memory limit of the Thanos sidecar containers. The value defined in the Prometheus resource takes precedence. No limit is configured by default.")
 	flagset.StringVar(&cfg.AlertmanagerDefaultBaseImage, "alertmanager-default-base-image", operator.DefaultAlertmanagerBaseImage, "Alertmanager default base image (path without tag/version)")
 	flagset.StringVar(&cfg.PrometheusDefaultBaseImage, "prometheus-default-base-image", operator.DefaultPrometheusBaseImage, "Prometheus default base image (path without tag/version)")
 	flagset.StringVar(&cfg.ThanosDefaultBaseImage, "thanos-default-base-image", operator.DefaultThanosBaseImage, "Thanos default base image (path without tag/version)")
 	flagset.Var(ns, "namespaces", "Namespaces to scope the interaction of the Prometheus Operator and the apiserver (allow list). This is mutually exclusive with --deny-namespaces.")
 	flagset.Var(deniedNs, "deny-namespaces", "Namespaces not to scope the interaction of the Prometheus Operator (deny list). This is mutually exclusive with --namespaces.")
 	flagset.Var(prometheusNs, "prometheus-instance-namespaces", "Namespaces where Prometheus custom resources and corresponding Secrets, Configmaps and StatefulSets are watched/created. If set this takes precedence over --namespaces or --deny-namespaces for Prometheus custom resources.")
+	flagset.Var(crossNamespaceInhibitionNs, "alertmanager-config-cross-namespace-inhibition-namespaces", "Namespaces whose AlertmanagerConfig resources may define inhibition rules with crossNamespace: true, matching the alerts from all namespaces. The AlertmanagerConfig resources from other namespaces defining such rules are rejected.")
+	flagset.Var(amConfigSecretNs, "alertmanager-config-secret-namespaces", "Namespaces from which the receivers of AlertmanagerConfig resources may reference Secrets and ConfigMaps with secretNamespace. The AlertmanagerConfig resources referencing other namespaces are rejected.")
+	flagset.StringVar(&alertmanagerReloadStrategy, "alertmanager-reload-strategy", string(operator.SidecarReloadStrategy), "Strategy used to reload the configuration of the Alertmanager pods. With 'sidecar', the pods run a config-reloader sidecar. With 'operator', the operator calls the reload endpoint of the pods over the pod network and the pods don't run the config-reloader sidecar.")
 	flagset.Var(alertmanagerNs, "alertmanager-instance-namespaces", "Namespaces where Alertmanager custom resources and corresponding StatefulSets are watched/created. If set this takes precedence over --namespaces or --deny-namespaces for Alertmanager custom resources.")
 	flagset.Var(thanosRulerNs, "thanos-ruler-instance-namespaces", "Namespaces where ThanosRuler custom resources and corresponding StatefulSets are watched/created. If set this takes precedence over --namespaces or --deny-namespaces for ThanosRuler custom resources.")
+	flagset.IntVar(&cfg.Namespaces.Sharding.Shards, "namespace-shards", 1, "Number of operator instances sharing the namespaces. Each instance only reconciles the Prometheus, PrometheusAgent, Alertmanager and ThanosRuler resources of the namespaces assigned to its shard.")
+	flagset.IntVar(&cfg.Namespaces.Sharding.Index, "namespace-shard-index", 0, "Index of the shard managed by the operator instance, from 0 to --namespace-shards minus 1.")
+	flagset.StringVar(&cfg.Namespaces.Sharding.Label, "namespace-shard-label", "", "Label of the namespaces whose value is the index of the shard managing them. Namespaces without this label are assigned to a shard by hashing their name.")
 	flagset.Var(&cfg.Labels, "labels", "Labels to be add to all resources created by the operator")
 	flagset.StringVar(&cfg.LocalHost, "localhost", "localhost", "EXPERIMENTAL (could be removed in future releases) - Host used to communicate between local services on a pod. Fixes issues where localhost resolves incorrectly.")
 	flagset.StringVar(&cfg.ClusterDomain, "cluster-domain", "", "The domain of the cluster. This is used to generate service FQDNs. If this is not specified, DNS search domain expansion is used instead.")
@@ -197,6 +225,23 @@ func init() {
 	flagset.StringVar(&cfg.AlertManagerSelector, "alertmanager-instance-selector", "", "Label selector to filter AlertManager Custom Resources to watch.")
 	flagset.StringVar(&cfg.ThanosRulerSelector, "thanos-ruler-instance-selector", "", "Label selector to filter ThanosRuler Custom Resources to watch.")
 	flagset.StringVar(&cfg.SecretListWatchSelector, "secret-field-selector", "", "Field selector to filter Secrets to watch")
+	flagset.StringVar(&cfg.SecretLabelSelector, "secret-label-selector", "", "Label selector to filter Secrets to watch. Only the changes of the matching Secrets trigger the reconciliation of the Prometheus and Alertmanager resources referencing them.")
+	flagset.BoolVar(&cfg.MetadataOnlyInformers, "metadata-only-informers", false, "Cache only the metadata of the watched Secrets and ConfigMaps to reduce the memory usage of the operator.")
+	flagset.BoolVar(&cfg.DisallowAdminAPI, "prometheus-disallow-admin-api", false, "Disable the web admin API of the Prometheus servers even if the Prometheus resource enables it.")
+	flagset.BoolVar(&cfg.DisallowRemoteWriteReceiver, "prometheus-disallow-remote-write-receiver", false, "Disable the remote write receiver of the Prometheus servers even if the Prometheus resource enables it.")
+	admissionFlags = admission.NewFlags(flagset, false)
+	flagset.BoolVar(&leaderElection.Enabled, "leader-elect", false, "Enable leader election so that only one replica of the operator reconciles the resources at a time. The other replicas keep serving the web endpoints and take over when the leader goes away.")
+	flagset.StringVar(&leaderElection.Namespace, "leader-election-namespace", "", "Namespace of the Lease object used for leader election. Defaults to the namespace of the operator's pod.")
+	flagset.StringVar(&leaderElection.LeaseName, "leader-election-id", "prometheus-operator", "Name of the Lease object used for leader election.")
+	flagset.IntVar(&cfg.PrometheusWorkers, "prometheus-workers", 1, "Number of Prometheus and PrometheusAgent resources reconciled concurrently.")
+	flagset.IntVar(&cfg.AlertmanagerWorkers, "alertmanager-workers", 1, "Number of Alertmanager resources reconciled concurrently.")
+	flagset.IntVar(&cfg.ThanosRulerWorkers, "thanos-ruler-workers", 1, "Number of ThanosRuler resources reconciled concurrently.")
+	flagset.DurationVar(&cfg.WorkqueueRateLimiter.BaseDelay, "workqueue-base-delay", operator.DefaultWorkqueueBaseDelay, "Initial delay before retrying the reconciliation of a resource which failed. The delay doubles on every subsequent failure. (default 5ms).")
+	flagset.DurationVar(&cfg.WorkqueueRateLimiter.MaxDelay, "workqueue-max-delay", operator.DefaultWorkqueueMaxDelay, "Maximum delay before retrying the reconciliation of a resource which failed. (default 16m40s).")
+	flagset.Float64Var(&cfg.WorkqueueRateLimiter.QPS, "workqueue-qps", 10, "Maximum number of resources per second requeued by each controller.")
+	flagset.IntVar(&cfg.WorkqueueRateLimiter.Burst, "workqueue-burst", 100, "Maximum burst of resources requeued by each controller.")
+	flagset.BoolVar(&cfg.GarbageCollection, "garbage-collection", false, "Periodically delete the Secrets, ConfigMaps, Services and StatefulSets generated by the operator whose owning Prometheus, PrometheusAgent, Alertmanager or ThanosRuler resource doesn't exist or doesn't need them anymore.")
+	flagset.BoolVar(&cfg.GarbageCollectionDryRun, "garbage-collection-dry-run", false, "Only log and count the orphaned objects found by the garbage collection without deleting them. Requires --garbage-collection.")
 }
 
 func Main() int {
@@ -254,6 +299,34 @@ func Main() int {
 		return 1
 	}
 
+	// Check validity of Thanos sidecar resource values given to flags
+	for _, q := range []struct {
+		name  string
+		value string
+	}{
+		{"CPU request", cfg.ThanosSidecarConfig.CPURequest},
+		{"CPU limit", cfg.ThanosSidecarConfig.CPULimit},
+		{"Memory request", cfg.ThanosSidecarConfig.MemoryRequest},
+		{"Memory limit", cfg.ThanosSidecarConfig.MemoryLimit},
+	} {
+		if q.value == "" {
+			continue
+		}
+
+		if _, err := resource.ParseQuantity(q.value); err != nil {
+			fmt.Fprintf(os.Stderr, "The %s specified for the Thanos sidecar \"%v\" is not a valid quantity! %v\n", q.name, q.value, err)
+			return 1
+		}
+	}
+
+	switch operator.ReloadStrategy(alertmanagerReloadStrategy) {
+	case operator.SidecarReloadStrategy, operator.OperatorReloadStrategy:
+		cfg.AlertmanagerReloadStrategy = operator.ReloadStrategy(alertmanagerReloadStrategy)
+	default:
+		fmt.Fprintf(os.Stderr, "Alertmanager reload strategy %q unknown, %q and %q are possible values\n", alertmanagerReloadStrategy, operator.SidecarReloadStrategy, operator.OperatorReloadStrategy)
+		return 1
+	}
+
 	// Above level 6, the k8s client would log bearer tokens in clear-text.
 	klog.ClampLevel(6)
 	klog.SetLogger(log.With(logger, "component", "k8s_client_runtime"))
@@ -266,6 +339,26 @@ func Main() int {
 		return 1
 	}
 
+	if err := cfg.Namespaces.Sharding.Validate(); err != nil {
+		fmt.Fprint(os.Stderr, "invalid namespace sharding: ", err, "\n")
+		return 1
+	}
+
+	if cfg.PrometheusWorkers < 1 || cfg.AlertmanagerWorkers < 1 || cfg.ThanosRulerWorkers < 1 {
+		fmt.Fprint(os.Stderr, "--prometheus-workers, --alertmanager-workers and --thanos-ruler-workers must be greater than 0.\n")
+		return 1
+	}
+
+	if err := cfg.WorkqueueRateLimiter.Validate(); err != nil {
+		fmt.Fprint(os.Stderr, "invalid workqueue rate limiting: ", err, "\n")
+		return 1
+	}
+
+	if cfg.GarbageCollectionDryRun && !cfg.GarbageCollection {
+		fmt.Fprint(os.Stderr, "--garbage-collection-dry-run requires --garbage-collection.\n")
+		return 1
+	}
+
 	cfg.Namespaces.AllowList = ns
 	if len(cfg.Namespaces.AllowList) == 0 {
 		cfg.Namespaces.AllowList[v1.NamespaceAll] = struct{}{}
@@ -275,6 +368,8 @@ func Main() int {
 	cfg.Namespaces.PrometheusAllowList = prometheusNs
 	cfg.Namespaces.AlertmanagerAllowList = alertmanagerNs
 	cfg.Namespaces.ThanosRulerAllowList = thanosRulerNs
+	cfg.CrossNamespaceInhibitionNamespaces = crossNamespaceInhibitionNs
+	cfg.AlertmanagerConfigSecretNamespaces = amConfigSecretNs
 
 	if len(cfg.Namespaces.PrometheusAllowList) == 0 {
 		cfg.Namespaces.PrometheusAllowList = cfg.Namespaces.AllowList
@@ -293,6 +388,7 @@ func Main() int {
 	r := prometheus.NewRegistry()
 
 	k8sutil.MustRegisterClientGoMetrics(r)
+	k8sutil.MustRegisterWorkqueueMetrics(r)
 
 	po, err := prometheuscontroller.New(ctx, cfg, log.With(logger, "component", "prometheusoperator"), r)
 	if err != nil {
@@ -322,8 +418,23 @@ func Main() int {
 		cancel()
 		return 1
 	}
-	admit := admission.New(log.With(logger, "component", "admissionwebhook"))
+	admissionCfg, closeAdmission, err := admissionFlags.Config(ctx, "prometheus-operator", log.With(logger, "component", "admissionwebhook"))
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		cancel()
+		return 1
+	}
+	defer closeAdmission()
+	admissionCfg.RuleLister = po
+	admissionCfg.NamespaceRuleLister = po
+	if len(amConfigSecretNs) > 0 {
+		admissionCfg.SecretAccessReviewer = ao
+	}
+	admit := admission.New(log.With(logger, "component", "admissionwebhook"), admissionCfg)
 
+	if alertmanagerConfigPreview {
+		web.EnableAlertmanagerConfigPreview(ao)
+	}
 	web.Register(mux)
 	admit.Register(mux)
 	l, err := net.Listen("tcp", cfg.ListenAddress)
@@ -347,28 +458,13 @@ func Main() int {
 		}
 	}
 
-	validationTriggeredCounter := prometheus.NewCounter(prometheus.CounterOpts{
-		Name: "prometheus_operator_rule_validation_triggered_total",
-		Help: "Number of times a prometheusRule object triggered validation",
-	})
-
-	validationErrorsCounter := prometheus.NewCounter(prometheus.CounterOpts{
-		Name: "prometheus_operator_rule_validation_errors_total",
-		Help: "Number of errors that occurred while validating a prometheusRules object",
-	})
-
 	r.MustRegister(
 		collectors.NewGoCollector(),
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
-		validationTriggeredCounter,
-		validationErrorsCounter,
 		version.NewCollector("prometheus_operator"),
 	)
 
-	admit.RegisterMetrics(
-		validationTriggeredCounter,
-		validationErrorsCounter,
-	)
+	admit.RegisterMetrics(r)
 
 	mux.Handle("/metrics", promhttp.HandlerFor(r, promhttp.HandlerOpts{}))
 	mux.Handle("/debug/pprof/", http.HandlerFunc(pprof.Index))
@@ -377,12 +473,60 @@ func Main() int {
 	mux.Handle("/debug/pprof/symbol", http.HandlerFunc(pprof.Symbol))
 	mux.Handle("/debug/pprof/trace", http.HandlerFunc(pprof.Trace))
 
-	wg.Go(func() error { return po.Run(ctx) })
-	wg.Go(func() error { return ao.Run(ctx) })
-	wg.Go(func() error { return to.Run(ctx) })
+	// The informers run on every replica because the admission webhook and
+	// the configuration preview read their caches. Only the reconciliation
+	// workers and the garbage collectors are gated by the leader election.
+	// The informers must outlive startInformers hence the errgroup isn't
+	// bound to a context (which would be canceled when Wait returns).
+	startInformers := func(ctx context.Context) error {
+		var wg errgroup.Group
+		wg.Go(func() error { return po.StartInformers(ctx) })
+		wg.Go(func() error { return ao.StartInformers(ctx) })
+		wg.Go(func() error { return to.StartInformers(ctx) })
+		return wg.Wait()
+	}
+
+	runControllers := func(ctx context.Context) error {
+		wg, ctx := errgroup.WithContext(ctx)
+		wg.Go(func() error { return po.Run(ctx) })
+		wg.Go(func() error { return ao.Run(ctx) })
+		wg.Go(func() error { return to.Run(ctx) })
+		return wg.Wait()
+	}
+
+	if leaderElection.Enabled {
+		restConfig, err := k8sutil.NewClusterConfig(cfg.Host, cfg.TLSInsecure, &cfg.TLSConfig)
+		if err != nil {
+			fmt.Fprint(os.Stderr, "instantiating cluster config failed: ", err)
+			cancel()
+			return 1
+		}
+
+		kclient, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			fmt.Fprint(os.Stderr, "instantiating kubernetes client failed: ", err)
+			cancel()
+			return 1
+		}
+
+		leaderElectionMetrics := newLeaderElectionMetrics(r)
+		wg.Go(func() error {
+			if err := startInformers(ctx); err != nil {
+				return err
+			}
+			return runWithLeaderElection(ctx, leaderElection, kclient, log.With(logger, "component", "leaderelection"), leaderElectionMetrics, runControllers)
+		})
+	} else {
+		wg.Go(func() error {
+			if err := startInformers(ctx); err != nil {
+				return err
+			}
+			return runControllers(ctx)
+		})
+	}
 
 	if tlsConfig != nil {
-		r, err := rbacproxytls.NewCertReloader(
+		certReloader, err := rbacproxytls.NewCertReloader(
 			cfg.ServerTLSConfig.CertFile,
 			cfg.ServerTLSConfig.KeyFile,
 			cfg.ServerTLSConfig.ReloadInterval,
@@ -393,13 +537,14 @@ func Main() int {
 			return 1
 		}
 
-		tlsConfig.GetCertificate = r.GetCertificate
+		tlsConfig.GetCertificate = certReloader.GetCertificate
+		r.MustRegister(operator.NewCertificateExpiryCollector(logger, certReloader.GetCertificate))
 
 		wg.Go(func() error {
 			for {
-				// r.Watch will wait ReloadInterval, so this is not
+				// certReloader.Watch will wait ReloadInterval, so this is not
 				// a hot loop
-				if err := r.Watch(ctx); err != nil {
+				if err := certReloader.Watch(ctx); err != nil {
 					level.Warn(logger).Log("msg", "error watching certificate reloader",
 						"err", err)
 				} else {
@@ -431,7 +576,11 @@ func Main() int {
 	case <-ctx.Done():
 	}
 
-	if err := srv.Shutdown(ctx); err != nil {
+	// Let the in-flight requests (including the admission requests) complete
+	// before exiting.
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
 		level.Warn(logger).Log("msg", "Server shutdown error", "err", err)
 	}
 
@@ -95,6 +95,24 @@ func resolveBoolExpr(expr ast.Expr) (string, error) {
 	return "", errors.New("No Identifier to Resolve")
 }
 
+func resolveIntExpr(expr ast.Expr) (string, error) {
+	exprLit, ok := expr.(*ast.BasicLit)
+	if ok && exprLit.Kind == token.INT {
+		return exprLit.Value, nil
+	}
+
+	return "", errors.New("No Integer Literal to Resolve")
+}
+
+func resolveFloatExpr(expr ast.Expr) (string, error) {
+	exprLit, ok := expr.(*ast.BasicLit)
+	if ok && (exprLit.Kind == token.FLOAT || exprLit.Kind == token.INT) {
+		return exprLit.Value, nil
+	}
+
+	return "", errors.New("No Float Literal to Resolve")
+}
+
 func resolveConstStringExpr(expr ast.Expr) (string, error) {
 
 	switch exprCast := expr.(type) {
@@ -190,6 +208,26 @@ func operatorCodeToDoc(paths []string) ([]FlagDoc, error) {
 							if err != nil {
 								return flagDocs, err
 							}
+						case "IntVar":
+							argName = unquoteLiteral(exprCall.Args[1].(*ast.BasicLit).Value)
+							argDefaultValue, err = resolveIntExpr(exprCall.Args[2])
+							if err != nil {
+								return flagDocs, err
+							}
+							argDescription, err = resolveConstStringExpr(exprCall.Args[3])
+							if err != nil {
+								return flagDocs, err
+							}
+						case "Float64Var":
+							argName = unquoteLiteral(exprCall.Args[1].(*ast.BasicLit).Value)
+							argDefaultValue, err = resolveFloatExpr(exprCall.Args[2])
+							if err != nil {
+								return flagDocs, err
+							}
+							argDescription, err = resolveConstStringExpr(exprCall.Args[3])
+							if err != nil {
+								return flagDocs, err
+							}
 						case "BoolVar":
 							argName = unquoteLiteral(exprCall.Args[1].(*ast.BasicLit).Value)
 							argDefaultValue, err = resolveBoolExpr(exprCall.Args[2])
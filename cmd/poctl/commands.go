@@ -0,0 +1,341 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringclient "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+// maxEvents is the maximum number of warning events shown by the status
+// command.
+const maxEvents = 10
+
+type cli struct {
+	kclient kubernetes.Interface
+	mclient monitoringclient.Interface
+
+	// Namespace of the Prometheus object.
+	namespace string
+	watched   watchedNamespaces
+
+	out io.Writer
+}
+
+// selected lists the resources selected by the Prometheus object.
+func (c *cli) selected(ctx context.Context, name string) error {
+	p, err := c.mclient.MonitoringV1().Prometheuses(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get the Prometheus object")
+	}
+
+	namespaces, err := c.listNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, kind := range []string{monitoringv1.ServiceMonitorsKind, monitoringv1.PodMonitorsKind, monitoringv1.PrometheusRuleKind} {
+		rs, err := newResourceSelector(p, kind)
+		if err != nil {
+			return err
+		}
+
+		objs, err := c.listObjects(ctx, rs.kind)
+		if err != nil {
+			return err
+		}
+
+		selected, err := rs.selected(p, objs, namespaces, c.watched)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(c.out, "%ss (%d):\n", rs.kind, len(selected))
+		for _, k := range selected {
+			fmt.Fprintf(c.out, "  %s\n", k)
+		}
+	}
+
+	return nil
+}
+
+// explain prints the reasons why the resource isn't selected by the
+// Prometheus object or, when it is selected, why it may not produce any
+// target.
+func (c *cli) explain(ctx context.Context, name, kind, key string) error {
+	p, err := c.mclient.MonitoringV1().Prometheuses(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get the Prometheus object")
+	}
+
+	rs, err := newResourceSelector(p, kind)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.SplitN(key, "/", 2)
+	if len(parts) != 2 {
+		return errors.Errorf("invalid resource %q (expected <namespace>/<name>)", key)
+	}
+	ns := parts[0]
+
+	obj, err := c.getObject(ctx, rs.kind, ns, parts[1])
+	if err != nil {
+		return err
+	}
+
+	nsObj, err := c.kclient.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the %q namespace", ns)
+	}
+
+	reasons, err := rs.explain(p, obj, nsObj, c.watched)
+	if err != nil {
+		return err
+	}
+
+	if len(reasons) > 0 {
+		fmt.Fprintf(c.out, "%s %s isn't selected by Prometheus %s/%s:\n", rs.kind, key, p.Namespace, p.Name)
+		printList(c.out, reasons)
+		return nil
+	}
+
+	fmt.Fprintf(c.out, "%s %s is selected by Prometheus %s/%s.\n", rs.kind, key, p.Namespace, p.Name)
+
+	events, err := c.warningEvents(ctx, p)
+	if err != nil {
+		return err
+	}
+	if rejected := rejectionMessages(events, rs.kind, key); len(rejected) > 0 {
+		fmt.Fprintf(c.out, "The operator rejected the %s:\n", rs.kind)
+		printList(c.out, rejected)
+	}
+
+	var portReasons []string
+	switch o := obj.(type) {
+	case *monitoringv1.ServiceMonitor:
+		services, err := c.kclient.CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return errors.Wrap(err, "failed to list the Services")
+		}
+
+		portReasons, err = serviceMonitorPortReasons(o, services.Items)
+		if err != nil {
+			return err
+		}
+	case *monitoringv1.PodMonitor:
+		pods, err := c.kclient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return errors.Wrap(err, "failed to list the Pods")
+		}
+
+		portReasons, err = podMonitorPortReasons(o, pods.Items)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(portReasons) > 0 {
+		fmt.Fprintf(c.out, "Some endpoints of the %s may not produce any target:\n", rs.kind)
+		printList(c.out, portReasons)
+	}
+
+	return nil
+}
+
+// status prints the conditions and the last warning events of the
+// Prometheus object.
+func (c *cli) status(ctx context.Context, name string) error {
+	p, err := c.mclient.MonitoringV1().Prometheuses(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get the Prometheus object")
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "CONDITION\tSTATUS\tREASON\tLAST TRANSITION\tMESSAGE")
+	if p.Status != nil {
+		for _, cond := range p.Status.Conditions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", cond.Type, cond.Status, cond.Reason, cond.LastTransitionTime.UTC().Format("2006-01-02T15:04:05Z"), cond.Message)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	events, err := c.warningEvents(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.out)
+	w = tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LAST SEEN\tREASON\tCOUNT\tMESSAGE")
+	for _, ev := range lastEvents(events, maxEvents) {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", eventTime(ev).UTC().Format("2006-01-02T15:04:05Z"), ev.Reason, ev.Count, ev.Message)
+	}
+
+	return w.Flush()
+}
+
+func (c *cli) listNamespaces(ctx context.Context) (map[string]*v1.Namespace, error) {
+	list, err := c.kclient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the namespaces")
+	}
+
+	namespaces := make(map[string]*v1.Namespace, len(list.Items))
+	for i := range list.Items {
+		namespaces[list.Items[i].Name] = &list.Items[i]
+	}
+
+	return namespaces, nil
+}
+
+func (c *cli) listObjects(ctx context.Context, kind string) ([]metav1.Object, error) {
+	var objs []metav1.Object
+
+	switch kind {
+	case monitoringv1.ServiceMonitorsKind:
+		list, err := c.mclient.MonitoringV1().ServiceMonitors(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list the ServiceMonitors")
+		}
+		for _, o := range list.Items {
+			objs = append(objs, o)
+		}
+	case monitoringv1.PodMonitorsKind:
+		list, err := c.mclient.MonitoringV1().PodMonitors(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list the PodMonitors")
+		}
+		for _, o := range list.Items {
+			objs = append(objs, o)
+		}
+	case monitoringv1.PrometheusRuleKind:
+		list, err := c.mclient.MonitoringV1().PrometheusRules(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list the PrometheusRules")
+		}
+		for _, o := range list.Items {
+			objs = append(objs, o)
+		}
+	}
+
+	return objs, nil
+}
+
+func (c *cli) getObject(ctx context.Context, kind, ns, name string) (metav1.Object, error) {
+	var (
+		obj metav1.Object
+		err error
+	)
+
+	switch kind {
+	case monitoringv1.ServiceMonitorsKind:
+		obj, err = c.mclient.MonitoringV1().ServiceMonitors(ns).Get(ctx, name, metav1.GetOptions{})
+	case monitoringv1.PodMonitorsKind:
+		obj, err = c.mclient.MonitoringV1().PodMonitors(ns).Get(ctx, name, metav1.GetOptions{})
+	case monitoringv1.PrometheusRuleKind:
+		obj, err = c.mclient.MonitoringV1().PrometheusRules(ns).Get(ctx, name, metav1.GetOptions{})
+	}
+
+	if apierrors.IsNotFound(err) {
+		return nil, errors.Errorf("%s %s/%s doesn't exist", kind, ns, name)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s %s/%s", kind, ns, name)
+	}
+
+	return obj, nil
+}
+
+// warningEvents returns the warning events emitted by the operator on the
+// Prometheus object.
+func (c *cli) warningEvents(ctx context.Context, p *monitoringv1.Prometheus) ([]v1.Event, error) {
+	list, err := c.kclient.CoreV1().Events(p.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{
+			"involvedObject.kind": monitoringv1.PrometheusesKind,
+			"involvedObject.name": p.Name,
+			"type":                v1.EventTypeWarning,
+		}).String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the events")
+	}
+
+	return list.Items, nil
+}
+
+// rejectionMessages returns the messages of the events reporting that the
+// resource was rejected by the operator, the most recent first.
+func rejectionMessages(events []v1.Event, kind, key string) []string {
+	prefix := fmt.Sprintf("%s %s was rejected: ", kind, key)
+
+	var msgs []string
+	for _, ev := range lastEvents(events, len(events)) {
+		if ev.Reason != operator.ResourceRejectedReason || !strings.HasPrefix(ev.Message, prefix) {
+			continue
+		}
+		msgs = append(msgs, strings.TrimPrefix(ev.Message, prefix))
+	}
+
+	return msgs
+}
+
+// lastEvents returns at most n events, the most recent first.
+func lastEvents(events []v1.Event, n int) []v1.Event {
+	res := make([]v1.Event, len(events))
+	copy(res, events)
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return eventTime(res[i]).After(eventTime(res[j]).Time)
+	})
+
+	if len(res) > n {
+		res = res[:n]
+	}
+
+	return res
+}
+
+func eventTime(ev v1.Event) metav1.Time {
+	if !ev.LastTimestamp.IsZero() {
+		return ev.LastTimestamp
+	}
+
+	return metav1.NewTime(ev.EventTime.Time)
+}
+
+func printList(out io.Writer, items []string) {
+	for _, item := range items {
+		fmt.Fprintf(out, "  - %s\n", item)
+	}
+}
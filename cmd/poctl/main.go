@@ -0,0 +1,155 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// poctl inspects the resources managed by the Prometheus operator.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	monitoringclient "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
+	"github.com/prometheus-operator/prometheus-operator/pkg/versionutil"
+)
+
+const usage = `Usage: poctl [flags] <command> [arguments]
+
+Commands:
+  selected <prometheus>
+        List the ServiceMonitors, PodMonitors and PrometheusRules selected by the Prometheus object.
+  explain <prometheus> <kind> <namespace>/<name>
+        Explain why a ServiceMonitor, PodMonitor or PrometheusRule isn't selected by the Prometheus object.
+  status <prometheus>
+        Show the conditions and the last warning events of the Prometheus object.
+
+Flags:
+`
+
+type namespaces map[string]struct{}
+
+// Set implements the flag.Value interface.
+func (n namespaces) Set(value string) error {
+	if n == nil {
+		return errors.New("expected n of type namespaces to be initialized")
+	}
+	for _, ns := range strings.Split(value, ",") {
+		n[ns] = struct{}{}
+	}
+	return nil
+}
+
+// String implements the flag.Value interface.
+func (n namespaces) String() string {
+	var ns []string
+	for k := range n {
+		ns = append(ns, k)
+	}
+	return strings.Join(ns, ",")
+}
+
+func main() {
+	var (
+		kubeconfig  string
+		kubeContext string
+		namespace   string
+		allowedNs   = namespaces{}
+		deniedNs    = namespaces{}
+	)
+
+	versionutil.RegisterFlags()
+	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig file. Defaults to the KUBECONFIG environment variable or ~/.kube/config.")
+	flag.StringVar(&kubeContext, "context", "", "Name of the kubeconfig context to use.")
+	flag.StringVar(&namespace, "namespace", "default", "Namespace of the Prometheus object.")
+	flag.Var(allowedNs, "namespaces", "Value of the --namespaces flag of the operator (allow list of the watched namespaces).")
+	flag.Var(deniedNs, "deny-namespaces", "Value of the --deny-namespaces flag of the operator (deny list of the watched namespaces).")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if versionutil.ShouldPrintVersion() {
+		versionutil.Print(os.Stdout, "poctl")
+		os.Exit(0)
+	}
+	log.SetFlags(0)
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	).ClientConfig()
+	if err != nil {
+		log.Fatalf("failed to load the kubeconfig: %v", err)
+	}
+
+	kclient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		log.Fatalf("failed to create the Kubernetes client: %v", err)
+	}
+
+	mclient, err := monitoringclient.NewForConfig(cfg)
+	if err != nil {
+		log.Fatalf("failed to create the monitoring client: %v", err)
+	}
+
+	c := &cli{
+		kclient:   kclient,
+		mclient:   mclient,
+		namespace: namespace,
+		watched:   watchedNamespaces{allow: allowedNs, deny: deniedNs},
+		out:       os.Stdout,
+	}
+
+	ctx := context.Background()
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "selected":
+		if len(args) != 1 {
+			log.Fatal("usage: poctl selected <prometheus>")
+		}
+		err = c.selected(ctx, args[0])
+	case "explain":
+		if len(args) != 3 {
+			log.Fatal("usage: poctl explain <prometheus> <kind> <namespace>/<name>")
+		}
+		err = c.explain(ctx, args[0], args[1], args[2])
+	case "status":
+		if len(args) != 1 {
+			log.Fatal("usage: poctl status <prometheus>")
+		}
+		err = c.status(ctx, args[0])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+// watchedNamespaces mirrors the --namespaces and --deny-namespaces flags of
+// the operator.
+type watchedNamespaces struct {
+	allow, deny map[string]struct{}
+}
+
+// watches returns true if the operator watches the resources of the
+// namespace.
+func (w watchedNamespaces) watches(ns string) bool {
+	if len(w.allow) > 0 {
+		_, found := w.allow[ns]
+		return found
+	}
+
+	_, found := w.deny[ns]
+	return !found
+}
+
+// resourceSelector holds the selectors of a Prometheus object for one kind
+// of resource.
+type resourceSelector struct {
+	kind string
+	// Name of the selector fields in the Prometheus spec.
+	field      string
+	selector   *metav1.LabelSelector
+	nsSelector *metav1.LabelSelector
+}
+
+// newResourceSelector returns the selectors of the Prometheus object for the
+// given kind.
+func newResourceSelector(p *monitoringv1.Prometheus, kind string) (*resourceSelector, error) {
+	switch strings.ToLower(kind) {
+	case "servicemonitor", "servicemonitors":
+		return &resourceSelector{
+			kind:       monitoringv1.ServiceMonitorsKind,
+			field:      "serviceMonitor",
+			selector:   p.Spec.ServiceMonitorSelector,
+			nsSelector: p.Spec.ServiceMonitorNamespaceSelector,
+		}, nil
+	case "podmonitor", "podmonitors":
+		return &resourceSelector{
+			kind:       monitoringv1.PodMonitorsKind,
+			field:      "podMonitor",
+			selector:   p.Spec.PodMonitorSelector,
+			nsSelector: p.Spec.PodMonitorNamespaceSelector,
+		}, nil
+	case "prometheusrule", "prometheusrules", "rule", "rules":
+		return &resourceSelector{
+			kind:       monitoringv1.PrometheusRuleKind,
+			field:      "rule",
+			selector:   p.Spec.RuleSelector,
+			nsSelector: p.Spec.RuleNamespaceSelector,
+		}, nil
+	}
+
+	return nil, errors.Errorf("unsupported kind %q (expected servicemonitor, podmonitor or prometheusrule)", kind)
+}
+
+// explain returns the reasons why the object living in the ns namespace
+// isn't selected by the Prometheus object. It returns nil when the object is
+// selected.
+func (rs *resourceSelector) explain(p *monitoringv1.Prometheus, obj metav1.Object, ns *v1.Namespace, watched watchedNamespaces) ([]string, error) {
+	var reasons []string
+
+	if !watched.watches(ns.Name) {
+		reasons = append(reasons, fmt.Sprintf("the operator doesn't watch the %q namespace (see the --namespaces and --deny-namespaces flags of the operator)", ns.Name))
+	}
+
+	if rs.nsSelector == nil {
+		// If the namespace selector is nil, only the Prometheus namespace
+		// is considered.
+		if ns.Name != p.Namespace {
+			reasons = append(reasons, fmt.Sprintf("%sNamespaceSelector isn't defined: only the %q namespace is selected", rs.field, p.Namespace))
+		}
+	} else {
+		s, err := metav1.LabelSelectorAsSelector(rs.nsSelector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %sNamespaceSelector", rs.field)
+		}
+
+		if !s.Matches(labels.Set(ns.Labels)) {
+			reasons = append(reasons, fmt.Sprintf("the labels of the %q namespace (%s) don't match %sNamespaceSelector (%s)", ns.Name, formatLabels(ns.Labels), rs.field, metav1.FormatLabelSelector(rs.nsSelector)))
+		}
+	}
+
+	if rs.selector == nil {
+		reasons = append(reasons, fmt.Sprintf("%sSelector isn't defined: no %s is selected", rs.field, rs.kind))
+		return reasons, nil
+	}
+
+	s, err := metav1.LabelSelectorAsSelector(rs.selector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %sSelector", rs.field)
+	}
+
+	if !s.Matches(labels.Set(obj.GetLabels())) {
+		reasons = append(reasons, fmt.Sprintf("the labels of the %s (%s) don't match %sSelector (%s)", rs.kind, formatLabels(obj.GetLabels()), rs.field, metav1.FormatLabelSelector(rs.selector)))
+	}
+
+	return reasons, nil
+}
+
+// selected returns the <namespace>/<name> keys of the objects selected by
+// the Prometheus object, sorted alphabetically.
+func (rs *resourceSelector) selected(p *monitoringv1.Prometheus, objs []metav1.Object, namespaces map[string]*v1.Namespace, watched watchedNamespaces) ([]string, error) {
+	var res []string
+	for _, obj := range objs {
+		reasons, err := rs.explain(p, obj, namespaceOrEmpty(namespaces, obj.GetNamespace()), watched)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(reasons) == 0 {
+			res = append(res, obj.GetNamespace()+"/"+obj.GetName())
+		}
+	}
+	sort.Strings(res)
+
+	return res, nil
+}
+
+// serviceMonitorPortReasons returns the reasons why the endpoints of the
+// ServiceMonitor don't match the ports of the Services in the cluster.
+func serviceMonitorPortReasons(sm *monitoringv1.ServiceMonitor, services []v1.Service) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&sm.Spec.Selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid selector")
+	}
+
+	ports := map[string]struct{}{}
+	var matched int
+	for _, svc := range services {
+		if !monitorSelectsNamespace(sm.Spec.NamespaceSelector, sm.Namespace, svc.Namespace) || !selector.Matches(labels.Set(svc.Labels)) {
+			continue
+		}
+
+		matched++
+		for _, port := range svc.Spec.Ports {
+			ports[port.Name] = struct{}{}
+		}
+	}
+
+	if matched == 0 {
+		return []string{fmt.Sprintf("no Service matches the selector (%s) of the ServiceMonitor", metav1.FormatLabelSelector(&sm.Spec.Selector))}, nil
+	}
+
+	var reasons []string
+	for i, ep := range sm.Spec.Endpoints {
+		if ep.Port == "" {
+			continue
+		}
+
+		if _, found := ports[ep.Port]; !found {
+			reasons = append(reasons, fmt.Sprintf("endpoint %d: none of the %d selected Service(s) has a port named %q", i, matched, ep.Port))
+		}
+	}
+
+	return reasons, nil
+}
+
+// podMonitorPortReasons returns the reasons why the endpoints of the
+// PodMonitor don't match the container ports of the Pods in the cluster.
+func podMonitorPortReasons(pm *monitoringv1.PodMonitor, pods []v1.Pod) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&pm.Spec.Selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid selector")
+	}
+
+	ports := map[string]struct{}{}
+	var matched int
+	for _, pod := range pods {
+		if !monitorSelectsNamespace(pm.Spec.NamespaceSelector, pm.Namespace, pod.Namespace) || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+
+		matched++
+		for _, c := range pod.Spec.Containers {
+			for _, port := range c.Ports {
+				ports[port.Name] = struct{}{}
+			}
+		}
+	}
+
+	if matched == 0 {
+		return []string{fmt.Sprintf("no Pod matches the selector (%s) of the PodMonitor", metav1.FormatLabelSelector(&pm.Spec.Selector))}, nil
+	}
+
+	var reasons []string
+	for i, ep := range pm.Spec.PodMetricsEndpoints {
+		if ep.Port == "" {
+			continue
+		}
+
+		if _, found := ports[ep.Port]; !found {
+			reasons = append(reasons, fmt.Sprintf("endpoint %d: none of the %d selected Pod(s) has a container port named %q", i, matched, ep.Port))
+		}
+	}
+
+	return reasons, nil
+}
+
+// monitorSelectsNamespace returns true if the namespace selector of a
+// monitor living in the monitorNs namespace selects the ns namespace.
+func monitorSelectsNamespace(sel monitoringv1.NamespaceSelector, monitorNs, ns string) bool {
+	if sel.Any {
+		return true
+	}
+
+	if len(sel.MatchNames) == 0 {
+		return ns == monitorNs
+	}
+
+	for _, n := range sel.MatchNames {
+		if n == ns {
+			return true
+		}
+	}
+
+	return false
+}
+
+func namespaceOrEmpty(namespaces map[string]*v1.Namespace, name string) *v1.Namespace {
+	if ns, found := namespaces[name]; found {
+		return ns
+	}
+
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func formatLabels(lset map[string]string) string {
+	if len(lset) == 0 {
+		return "no labels"
+	}
+
+	return labels.Set(lset).String()
+}
@@ -0,0 +1,274 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+func TestExplain(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s", Namespace: "monitoring"},
+		Spec: monitoringv1.PrometheusSpec{
+			ServiceMonitorSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "a"},
+			},
+			ServiceMonitorNamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"monitoring": "true"},
+			},
+			PodMonitorSelector: &metav1.LabelSelector{},
+		},
+	}
+
+	monitoredNs := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app", Labels: map[string]string{"monitoring": "true"}}}
+	otherNs := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+
+	for _, tc := range []struct {
+		name     string
+		kind     string
+		labels   map[string]string
+		ns       *v1.Namespace
+		watched  watchedNamespaces
+		expected []string
+	}{
+		{
+			name:   "selected ServiceMonitor",
+			kind:   "servicemonitor",
+			labels: map[string]string{"team": "a"},
+			ns:     monitoredNs,
+		},
+		{
+			name:   "label mismatch",
+			kind:   "servicemonitor",
+			labels: map[string]string{"team": "b"},
+			ns:     monitoredNs,
+			expected: []string{
+				`the labels of the ServiceMonitor (team=b) don't match serviceMonitorSelector (team=a)`,
+			},
+		},
+		{
+			name:   "namespace mismatch",
+			kind:   "servicemonitor",
+			labels: map[string]string{"team": "a"},
+			ns:     otherNs,
+			expected: []string{
+				`the labels of the "other" namespace (no labels) don't match serviceMonitorNamespaceSelector (monitoring=true)`,
+			},
+		},
+		{
+			name:    "namespace not watched",
+			kind:    "servicemonitor",
+			labels:  map[string]string{"team": "a"},
+			ns:      monitoredNs,
+			watched: watchedNamespaces{deny: map[string]struct{}{"app": {}}},
+			expected: []string{
+				`the operator doesn't watch the "app" namespace (see the --namespaces and --deny-namespaces flags of the operator)`,
+			},
+		},
+		{
+			name:    "namespace not in the allow list",
+			kind:    "servicemonitor",
+			labels:  map[string]string{"team": "a"},
+			ns:      monitoredNs,
+			watched: watchedNamespaces{allow: map[string]struct{}{"monitoring": {}}},
+			expected: []string{
+				`the operator doesn't watch the "app" namespace (see the --namespaces and --deny-namespaces flags of the operator)`,
+			},
+		},
+		{
+			name: "PodMonitor outside of the Prometheus namespace",
+			kind: "podmonitor",
+			ns:   monitoredNs,
+			expected: []string{
+				`podMonitorNamespaceSelector isn't defined: only the "monitoring" namespace is selected`,
+			},
+		},
+		{
+			name: "no rule selector",
+			kind: "prometheusrule",
+			ns:   &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring"}},
+			expected: []string{
+				`ruleSelector isn't defined: no PrometheusRule is selected`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rs, err := newResourceSelector(p, tc.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obj := &metav1.ObjectMeta{Name: "foo", Namespace: tc.ns.Name, Labels: tc.labels}
+			reasons, err := rs.explain(p, obj, tc.ns, tc.watched)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, reasons); diff != "" {
+				t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewResourceSelectorUnknownKind(t *testing.T) {
+	if _, err := newResourceSelector(&monitoringv1.Prometheus{}, "probe"); err == nil {
+		t.Fatal("expected error, got none")
+	}
+}
+
+func TestServiceMonitorPortReasons(t *testing.T) {
+	services := []v1.Service{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "app", Labels: map[string]string{"app": "foo"}},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{Name: "web"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "other", Labels: map[string]string{"app": "foo"}},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{Name: "metrics"}},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		spec     monitoringv1.ServiceMonitorSpec
+		expected []string
+	}{
+		{
+			name: "matching port",
+			spec: monitoringv1.ServiceMonitorSpec{
+				Selector:  metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+				Endpoints: []monitoringv1.Endpoint{{Port: "web"}},
+			},
+		},
+		{
+			name: "missing port",
+			spec: monitoringv1.ServiceMonitorSpec{
+				Selector:  metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+				Endpoints: []monitoringv1.Endpoint{{Port: "web"}, {Port: "metrics"}},
+			},
+			expected: []string{
+				`endpoint 1: none of the 1 selected Service(s) has a port named "metrics"`,
+			},
+		},
+		{
+			name: "port in another namespace",
+			spec: monitoringv1.ServiceMonitorSpec{
+				Selector:          metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+				NamespaceSelector: monitoringv1.NamespaceSelector{MatchNames: []string{"other"}},
+				Endpoints:         []monitoringv1.Endpoint{{Port: "metrics"}},
+			},
+		},
+		{
+			name: "no matching service",
+			spec: monitoringv1.ServiceMonitorSpec{
+				Selector:  metav1.LabelSelector{MatchLabels: map[string]string{"app": "bar"}},
+				Endpoints: []monitoringv1.Endpoint{{Port: "web"}},
+			},
+			expected: []string{
+				`no Service matches the selector (app=bar) of the ServiceMonitor`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sm := &monitoringv1.ServiceMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "app"},
+				Spec:       tc.spec,
+			}
+
+			reasons, err := serviceMonitorPortReasons(sm, services)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, reasons); diff != "" {
+				t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPodMonitorPortReasons(t *testing.T) {
+	pods := []v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-0", Namespace: "app", Labels: map[string]string{"app": "foo"}},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "app", Ports: []v1.ContainerPort{{Name: "web"}}},
+				},
+			},
+		},
+	}
+
+	pm := &monitoringv1.PodMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "app"},
+		Spec: monitoringv1.PodMonitorSpec{
+			Selector:            metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+			PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{{Port: "web"}, {Port: "metrics"}},
+		},
+	}
+
+	reasons, err := podMonitorPortReasons(pm, pods)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{`endpoint 1: none of the 1 selected Pod(s) has a container port named "metrics"`}
+	if diff := cmp.Diff(expected, reasons); diff != "" {
+		t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestRejectionMessages(t *testing.T) {
+	events := []v1.Event{
+		{
+			Reason:        operator.ResourceRejectedReason,
+			Message:       "ServiceMonitor app/foo was rejected: missing secret",
+			LastTimestamp: metav1.NewTime(time.Unix(1000, 0)),
+		},
+		{
+			Reason:        operator.ResourceRejectedReason,
+			Message:       "ServiceMonitor app/foo was rejected: invalid proxy URL",
+			LastTimestamp: metav1.NewTime(time.Unix(2000, 0)),
+		},
+		{
+			Reason:        operator.ResourceRejectedReason,
+			Message:       "ServiceMonitor app/bar was rejected: missing secret",
+			LastTimestamp: metav1.NewTime(time.Unix(3000, 0)),
+		},
+		{
+			Reason:        operator.ConfigGenerationFailedReason,
+			Message:       "ServiceMonitor app/foo was rejected: unrelated",
+			LastTimestamp: metav1.NewTime(time.Unix(4000, 0)),
+		},
+	}
+
+	expected := []string{"invalid proxy URL", "missing secret"}
+	if diff := cmp.Diff(expected, rejectionMessages(events, monitoringv1.ServiceMonitorsKind, "app/foo")); diff != "" {
+		t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+	}
+}
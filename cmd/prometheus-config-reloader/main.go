@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"regexp"
 	"strings"
@@ -52,6 +53,11 @@ const (
 
 	statefulsetOrdinalEnvvar            = "STATEFULSET_ORDINAL_NUMBER"
 	statefulsetOrdinalFromEnvvarDefault = "POD_NAME"
+
+	// The basic authentication credentials for the reload URL are passed as
+	// environment variables to keep them out of the command line.
+	reloadBasicAuthUsernameEnvvar = "RELOAD_BASIC_AUTH_USERNAME"
+	reloadBasicAuthPasswordEnvvar = "RELOAD_BASIC_AUTH_PASSWORD"
 )
 
 var (
@@ -145,6 +151,10 @@ func main() {
 		}
 	}
 
+	if username := os.Getenv(reloadBasicAuthUsernameEnvvar); username != "" {
+		(*reloadURL).User = url.UserPassword(username, os.Getenv(reloadBasicAuthPasswordEnvvar))
+	}
+
 	level.Info(logger).Log("msg", "Starting prometheus-config-reloader", "version", version.Info())
 	level.Info(logger).Log("build_context", version.BuildContext())
 
@@ -30,9 +30,11 @@ function(params) {
   '0alertmanagerCustomResourceDefinition': import 'alertmanager-crd.libsonnet',
   '0alertmanagerConfigCustomResourceDefinition': import 'alertmanagerconfig-crd.libsonnet',
   '0prometheusCustomResourceDefinition': import 'prometheus-crd.libsonnet',
+  '0prometheusagentCustomResourceDefinition': import 'prometheusagent-crd.libsonnet',
   '0servicemonitorCustomResourceDefinition': import 'servicemonitor-crd.libsonnet',
   '0podmonitorCustomResourceDefinition': import 'podmonitor-crd.libsonnet',
   '0probeCustomResourceDefinition': import 'probe-crd.libsonnet',
+  '0scrapeconfigCustomResourceDefinition': import 'scrapeconfig-crd.libsonnet',
   '0prometheusruleCustomResourceDefinition': import 'prometheusrule-crd.libsonnet',
   '0thanosrulerCustomResourceDefinition': import 'thanosruler-crd.libsonnet',
 
@@ -68,14 +70,20 @@ function(params) {
         resources: [
           'alertmanagers',
           'alertmanagers/finalizers',
+          'alertmanagers/status',
           'alertmanagerconfigs',
           'prometheuses',
           'prometheuses/finalizers',
+          'prometheuses/status',
+          'prometheusagents',
+          'prometheusagents/finalizers',
           'thanosrulers',
           'thanosrulers/finalizers',
+          'thanosrulers/status',
           'servicemonitors',
           'podmonitors',
           'probes',
+          'scrapeconfigs',
           'prometheusrules',
         ],
         verbs: ['*'],
@@ -102,7 +110,7 @@ function(params) {
           'services/finalizers',
           'endpoints',
         ],
-        verbs: ['get', 'create', 'update', 'delete'],
+        verbs: ['get', 'list', 'create', 'update', 'delete'],
       },
       {
         apiGroups: [''],
@@ -119,6 +127,26 @@ function(params) {
         resources: ['ingresses'],
         verbs: ['get', 'list', 'watch'],
       },
+      {
+        apiGroups: ['discovery.k8s.io'],
+        resources: ['endpointslices'],
+        verbs: ['get', 'list', 'watch'],
+      },
+      {
+        apiGroups: [''],
+        resources: ['events'],
+        verbs: ['create', 'patch'],
+      },
+      {
+        apiGroups: ['coordination.k8s.io'],
+        resources: ['leases'],
+        verbs: ['get', 'create', 'update'],
+      },
+      {
+        apiGroups: ['authorization.k8s.io'],
+        resources: ['subjectaccessreviews'],
+        verbs: ['create'],
+      },
     ],
   },
 
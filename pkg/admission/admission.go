@@ -15,71 +15,336 @@
 package admission
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/prometheus-operator/prometheus-operator/pkg/alertmanager"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	promoperator "github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
 	v1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes/scheme"
 )
 
 const (
-	addFirstAnnotationPatch      = `{ "op": "add", "path": "/metadata/annotations", "value": {"prometheus-operator-validated": "true"}}`
-	addAdditionalAnnotationPatch = `{ "op": "add", "path": "/metadata/annotations/prometheus-operator-validated", "value": "true" }`
-	errUnmarshalAdmission        = "Cannot unmarshal admission request"
-	errUnmarshalRules            = "Cannot unmarshal rules from spec"
+	// DefaultMutationAnnotation and DefaultMutationAnnotationValue define the
+	// annotation added by default to the mutated PrometheusRules.
+	DefaultMutationAnnotation      = "prometheus-operator-validated"
+	DefaultMutationAnnotationValue = "true"
+
+	// DefaultMaxRequestBodySize is the default maximum size of the request
+	// bodies. It leaves room for admission reviews holding both the old and
+	// new versions of objects close to the etcd size limit.
+	DefaultMaxRequestBodySize = 6 << 20
+
+	errUnmarshalAdmission  = "Cannot unmarshal admission request"
+	errUnmarshalRules      = "Cannot unmarshal rules from spec"
+	errUnmarshalConfig     = "Cannot unmarshal config from spec"
+	errUnmarshalProbe      = "Cannot unmarshal probe from spec"
+	errUnmarshalMonitor    = "Cannot unmarshal monitor from spec"
+	errUnmarshalPrometheus = "Cannot unmarshal prometheus from spec"
+	errUnmarshalScrape     = "Cannot unmarshal scrape config from spec"
+
+	prometheusRuleValidatePath     = "/admission-prometheusrules/validate"
+	prometheusRuleMutatePath       = "/admission-prometheusrules/mutate"
+	alertmanagerConfigValidatePath = "/admission-alertmanagerconfigs/validate"
+	probeValidatePath              = "/admission-probes/validate"
+	serviceMonitorValidatePath     = "/admission-servicemonitors/validate"
+	podMonitorValidatePath         = "/admission-podmonitors/validate"
+	prometheusValidatePath         = "/admission-prometheuses/validate"
+	scrapeConfigValidatePath       = "/admission-scrapeconfigs/validate"
+
+	// PrometheusRulesHandler, AlertmanagerConfigsHandler, ProbesHandler,
+	// ServiceMonitorsHandler, PodMonitorsHandler, PrometheusesHandler,
+	// ScrapeConfigsHandler and ConversionHandler identify the handlers which
+	// can be enabled.
+	PrometheusRulesHandler     = "prometheusrules"
+	AlertmanagerConfigsHandler = "alertmanagerconfigs"
+	ProbesHandler              = "probes"
+	ServiceMonitorsHandler     = "servicemonitors"
+	PodMonitorsHandler         = "podmonitors"
+	PrometheusesHandler        = "prometheuses"
+	ScrapeConfigsHandler       = "scrapeconfigs"
+	ConversionHandler          = "conversion"
+
+	// ruleTestsAnnotation is the annotation holding the unit tests which
+	// are executed against the rules of the PrometheusRule object.
+	ruleTestsAnnotation = "monitoring.coreos.com/rule-tests"
 )
 
 var (
-	deserializer = scheme.Codecs.UniversalDeserializer()
+	// AvailableHandlers lists the handlers served by the webhook.
+	AvailableHandlers = []string{
+		PrometheusRulesHandler,
+		AlertmanagerConfigsHandler,
+		ProbesHandler,
+		ServiceMonitorsHandler,
+		PodMonitorsHandler,
+		PrometheusesHandler,
+		ScrapeConfigsHandler,
+		ConversionHandler,
+	}
+
 	ruleResource = metav1.GroupVersionResource{
 		Group:    "monitoring.coreos.com",
 		Version:  "v1",
 		Resource: "prometheusrules",
 	}
+	alertmanagerConfigResource = metav1.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1alpha1",
+		Resource: "alertmanagerconfigs",
+	}
+	probeResource = metav1.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1",
+		Resource: "probes",
+	}
+	serviceMonitorResource = metav1.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1",
+		Resource: "servicemonitors",
+	}
+	podMonitorResource = metav1.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1",
+		Resource: "podmonitors",
+	}
+	prometheusResource = metav1.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1",
+		Resource: "prometheuses",
+	}
+	scrapeConfigResource = metav1.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1alpha1",
+		Resource: "scrapeconfigs",
+	}
 )
 
 // Admission is a validating and mutating webhook that ensures PrometheusRules pushed into the cluster will be
-// valid when loaded by a Prometheus
+// valid when loaded by a Prometheus, that Probes, ServiceMonitors and
+// PodMonitors will generate a valid Prometheus configuration, that
+// Prometheuses and ScrapeConfigs are semantically valid and that
+// AlertmanagerConfigs will be valid when loaded by an Alertmanager. It also
+// serves the conversion webhook for the AlertmanagerConfig CRD.
 type Admission struct {
-	validationErrorsCounter    prometheus.Counter
-	validationTriggeredCounter prometheus.Counter
-	logger                     log.Logger
+	validationErrorsCounter           *prometheus.CounterVec
+	validationTriggeredCounter        *prometheus.CounterVec
+	amConfValidationErrorsCounter     *prometheus.CounterVec
+	amConfValidationTriggeredCounter  *prometheus.CounterVec
+	probeValidationErrorsCounter      *prometheus.CounterVec
+	probeValidationTriggeredCounter   *prometheus.CounterVec
+	monitorValidationErrorsCounter    *prometheus.CounterVec
+	monitorValidationTriggeredCounter *prometheus.CounterVec
+	promValidationErrorsCounter       *prometheus.CounterVec
+	promValidationTriggeredCounter    *prometheus.CounterVec
+	scValidationErrorsCounter         *prometheus.CounterVec
+	scValidationTriggeredCounter      *prometheus.CounterVec
+	inFlightRequestsGauge             prometheus.Gauge
+	throttledRequestsCounter          *prometheus.CounterVec
+	oversizedRequestsCounter          prometheus.Counter
+	logger                            log.Logger
+	config                            Config
+	throttler                         *throttler
+	auditMtx                          sync.Mutex
+	readiness                         readiness
+}
+
+// RuleLister lists the PrometheusRules which are selected together with a
+// given PrometheusRule.
+type RuleLister interface {
+	// ListSelectedRules returns, for each Prometheus object selecting the
+	// PrometheusRule, the other PrometheusRules selected by the same object.
+	ListSelectedRules(*monitoringv1.PrometheusRule) (map[string][]*monitoringv1.PrometheusRule, error)
+}
+
+// SecretAccessReviewer verifies the permissions of the users on the Secrets
+// and ConfigMaps.
+type SecretAccessReviewer interface {
+	// CanReadSecrets returns whether the user can read the Secrets and
+	// ConfigMaps of the namespace.
+	CanReadSecrets(ctx context.Context, user authenticationv1.UserInfo, namespace string) (bool, error)
+}
+
+// Config defines the configuration of the admission webhook.
+type Config struct {
+	// RuleChecks defines the action taken when a PrometheusRule fails one of
+	// the additional checks.
+	RuleChecks RuleChecks
+	// RulePolicy defines the requirements enforced on the alerting rules.
+	RulePolicy RulePolicy
+	// PromQLCompatibility defines the Prometheus version targeted by the
+	// PrometheusRules.
+	PromQLCompatibility PromQLCompatibility
+	// MetricNamePolicy defines the metric names which can be queried by the
+	// PrometheusRules.
+	MetricNamePolicy MetricNamePolicy
+	// RuleQuota defines the limits on the number of groups and rules of the
+	// PrometheusRules.
+	RuleQuota RuleQuota
+	// NamespaceRuleLister lists the PrometheusRules of the namespace of the
+	// validated PrometheusRule. It is required to enforce the
+	// MaxRulesPerNamespace quota which is skipped otherwise.
+	NamespaceRuleLister NamespaceRuleLister
+	// PolicyEvaluators evaluate custom policies against the PrometheusRules.
+	// Any violation denies the resource.
+	PolicyEvaluators []PolicyEvaluator
+	// RuleFileSizeBudget is the maximum size in bytes of the rule file
+	// generated from a PrometheusRule. It is used by the rule-file-size check.
+	RuleFileSizeBudget int
+	// RuleLister lists the PrometheusRules selected by the same Prometheus
+	// objects as the validated PrometheusRule. It is required by the
+	// duplicate-rule-names check which is skipped otherwise.
+	RuleLister RuleLister
+	// SecretAccessReviewer verifies that the users creating or updating
+	// AlertmanagerConfigs whose receivers reference Secrets from another
+	// namespace can read them. If nil, the AlertmanagerConfigs referencing
+	// other namespaces are admitted without verification.
+	SecretAccessReviewer SecretAccessReviewer
+	// MutationAnnotation and MutationAnnotationValue define the annotation
+	// added to the mutated PrometheusRules. If empty, DefaultMutationAnnotation
+	// and DefaultMutationAnnotationValue are used.
+	MutationAnnotation      string
+	MutationAnnotationValue string
+	// MutationPatches are static JSON patch operations applied to the
+	// mutated PrometheusRules in addition to the annotation.
+	MutationPatches JSONPatches
+	// EnforcedNamespaceLabel is the label enforced on the mutated
+	// PrometheusRules with the namespace of the object: it is added to the
+	// rule labels and injected as a matcher into the rule expressions. It has
+	// the same semantics as the enforcedNamespaceLabel field of the Prometheus
+	// resource, for the rules evaluated by Prometheus servers shared between
+	// tenants.
+	EnforcedNamespaceLabel string
+	// CanonicalizeRules enables the rewriting of the mutated PrometheusRules
+	// into a canonical form: sorted groups, normalized durations and
+	// expressions without trailing whitespace.
+	CanonicalizeRules bool
+	// MaxConcurrentRequests is the maximum number of requests processed
+	// concurrently. Requests exceeding the limit are rejected with a 429
+	// status. Zero means no limit.
+	MaxConcurrentRequests int
+	// ClientRateLimit is the maximum number of requests per second accepted
+	// from a single client, with bursts of up to ClientRateBurst requests.
+	// Requests exceeding the limit are rejected with a 429 status. Zero means
+	// no limit.
+	ClientRateLimit float64
+	ClientRateBurst int
+	// MaxRequestBodySize is the maximum size in bytes of the request bodies.
+	// Larger requests are rejected with a 413 status. If zero,
+	// DefaultMaxRequestBodySize is used.
+	MaxRequestBodySize int
+	// TracerProvider traces the admission requests. If nil, the requests
+	// aren't traced.
+	TracerProvider trace.TracerProvider
+	// AuditLog receives a JSON line for every admission decision. If nil,
+	// the decisions aren't recorded.
+	AuditLog io.Writer
+	// Handlers lists the handlers registered by Register (see
+	// AvailableHandlers). If empty, all the handlers are registered.
+	Handlers []string
 }
 
-func New(logger log.Logger) *Admission {
-	return &Admission{logger: logger}
+func New(logger log.Logger, config Config) *Admission {
+	a := &Admission{
+		logger:    logger,
+		config:    config,
+		throttler: newThrottler(config.MaxConcurrentRequests, config.ClientRateLimit, config.ClientRateBurst),
+	}
+	a.initMetrics()
+
+	return a
 }
 
 func (a *Admission) Register(mux *http.ServeMux) {
-	mux.HandleFunc("/admission-prometheusrules/validate", a.servePrometheusRulesValidate)
-	mux.HandleFunc("/admission-prometheusrules/mutate", a.servePrometheusRulesMutate)
+	if a.handlerEnabled(PrometheusRulesHandler) {
+		mux.HandleFunc(prometheusRuleValidatePath, a.throttle(a.servePrometheusRulesValidate))
+		mux.HandleFunc(prometheusRuleMutatePath, a.throttle(a.servePrometheusRulesMutate))
+	}
+	if a.handlerEnabled(AlertmanagerConfigsHandler) {
+		mux.HandleFunc(alertmanagerConfigValidatePath, a.throttle(a.serveAlertmanagerConfigValidate))
+	}
+	if a.handlerEnabled(ProbesHandler) {
+		mux.HandleFunc(probeValidatePath, a.throttle(a.serveProbeValidate))
+	}
+	if a.handlerEnabled(ServiceMonitorsHandler) {
+		mux.HandleFunc(serviceMonitorValidatePath, a.throttle(a.serveServiceMonitorValidate))
+	}
+	if a.handlerEnabled(PodMonitorsHandler) {
+		mux.HandleFunc(podMonitorValidatePath, a.throttle(a.servePodMonitorValidate))
+	}
+	if a.handlerEnabled(PrometheusesHandler) {
+		mux.HandleFunc(prometheusValidatePath, a.throttle(a.servePrometheusValidate))
+	}
+	if a.handlerEnabled(ScrapeConfigsHandler) {
+		mux.HandleFunc(scrapeConfigValidatePath, a.throttle(a.serveScrapeConfigValidate))
+	}
+	if a.handlerEnabled(ConversionHandler) {
+		mux.HandleFunc(conversionPath, a.throttle(a.serveConvert))
+	}
 }
 
-func (a *Admission) RegisterMetrics(validationTriggeredCounter, validationErrorsCounter prometheus.Counter) {
-	a.validationTriggeredCounter = validationTriggeredCounter
-	a.validationErrorsCounter = validationErrorsCounter
+func (a *Admission) handlerEnabled(name string) bool {
+	if len(a.config.Handlers) == 0 {
+		return true
+	}
+
+	for _, h := range a.config.Handlers {
+		if h == name {
+			return true
+		}
+	}
+
+	return false
 }
 
-type admitFunc func(ar v1.AdmissionReview) *v1.AdmissionResponse
+type admitFunc func(ctx context.Context, ar v1.AdmissionReview) *v1.AdmissionResponse
 
 func (a *Admission) servePrometheusRulesMutate(w http.ResponseWriter, r *http.Request) {
-	a.serveAdmission(w, r, a.mutatePrometheusRules)
+	a.serveAdmission(w, r, "mutate", a.mutatePrometheusRules)
 }
 
 func (a *Admission) servePrometheusRulesValidate(w http.ResponseWriter, r *http.Request) {
-	a.serveAdmission(w, r, a.validatePrometheusRules)
+	a.serveAdmission(w, r, "validate", a.validatePrometheusRules)
+}
+
+func (a *Admission) serveAlertmanagerConfigValidate(w http.ResponseWriter, r *http.Request) {
+	a.serveAdmission(w, r, "validate", a.validateAlertmanagerConfig)
+}
+
+func (a *Admission) serveProbeValidate(w http.ResponseWriter, r *http.Request) {
+	a.serveAdmission(w, r, "validate", a.validateProbe)
+}
+
+func (a *Admission) serveServiceMonitorValidate(w http.ResponseWriter, r *http.Request) {
+	a.serveAdmission(w, r, "validate", a.validateServiceMonitor)
+}
+
+func (a *Admission) servePodMonitorValidate(w http.ResponseWriter, r *http.Request) {
+	a.serveAdmission(w, r, "validate", a.validatePodMonitor)
 }
 
-func toAdmissionResponseFailure(message string, errors []error) *v1.AdmissionResponse {
+func (a *Admission) servePrometheusValidate(w http.ResponseWriter, r *http.Request) {
+	a.serveAdmission(w, r, "validate", a.validatePrometheus)
+}
+
+func (a *Admission) serveScrapeConfigValidate(w http.ResponseWriter, r *http.Request) {
+	a.serveAdmission(w, r, "validate", a.validateScrapeConfig)
+}
+
+func toAdmissionResponseFailure(message, resource string, errors []error) *v1.AdmissionResponse {
 	r := &v1.AdmissionResponse{
 		Result: &metav1.Status{
 			Details: &metav1.StatusDetails{
@@ -91,114 +356,215 @@ func toAdmissionResponseFailure(message string, errors []error) *v1.AdmissionRes
 	r.Result.Message = message
 
 	for _, err := range errors {
-		r.Result.Details.Name = "prometheusrules"
+		r.Result.Details.Name = resource
 		r.Result.Details.Causes = append(r.Result.Details.Causes, metav1.StatusCause{Message: err.Error()})
 	}
 
 	return r
 }
 
-func (a *Admission) serveAdmission(w http.ResponseWriter, r *http.Request, admit admitFunc) {
-	var body []byte
+// toAdmissionResponseFieldFailure is like toAdmissionResponseFailure but it
+// reports the path of the invalid field in every cause.
+func toAdmissionResponseFieldFailure(message, resource string, errs []*promoperator.FieldError) *v1.AdmissionResponse {
+	r := toAdmissionResponseFailure(message, resource, nil)
+	r.Result.Details.Name = resource
+
+	for _, err := range errs {
+		r.Result.Details.Causes = append(r.Result.Details.Causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: err.Err.Error(),
+			Field:   err.Field,
+		})
+	}
+
+	return r
+}
+
+// readRequestBody returns a buffer from the pool holding the body of the
+// request, decompressed if it is gzip-encoded. The caller must return the
+// buffer to the pool with putBuffer. If the request has no body, is too large
+// or isn't JSON-encoded, it replies with an error and returns nil.
+func (a *Admission) readRequestBody(w http.ResponseWriter, r *http.Request) *bytes.Buffer {
+	limit := int64(a.config.MaxRequestBodySize)
+	if limit <= 0 {
+		limit = DefaultMaxRequestBodySize
+	}
+
+	buf := getBuffer()
 	if r.Body != nil {
-		if data, err := ioutil.ReadAll(r.Body); err == nil {
-			body = data
+		// The limit applies to both the compressed and the decompressed body.
+		cr := &countingReader{r: http.MaxBytesReader(w, r.Body, limit)}
+		err := decodeBody(buf, cr, r.Header.Get("Content-Encoding"), limit)
+		if (err != nil && cr.n >= limit) || int64(buf.Len()) > limit {
+			putBuffer(buf)
+			a.oversizedRequestsCounter.Inc()
+			level.Warn(a.logger).Log("msg", "request body too large", "limit", limit)
+			http.Error(w, fmt.Sprintf("request body exceeds the limit of %d bytes", limit), http.StatusRequestEntityTooLarge)
+			return nil
+		}
+
+		switch {
+		case err == errUnsupportedEncoding:
+			putBuffer(buf)
+			level.Warn(a.logger).Log("msg", err, "encoding", r.Header.Get("Content-Encoding"))
+			http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+			return nil
+		case err != nil:
+			putBuffer(buf)
+			level.Warn(a.logger).Log("msg", "Cannot read request body", "err", err)
+			http.Error(w, fmt.Sprintf("cannot read request body: %v", err), http.StatusBadRequest)
+			return nil
 		}
 	}
 
-	if len(body) == 0 {
+	if buf.Len() == 0 {
+		putBuffer(buf)
 		level.Warn(a.logger).Log("msg", "request has no body")
 		http.Error(w, "request has no body", http.StatusBadRequest)
-		return
+		return nil
 	}
 
 	contentType := r.Header.Get("Content-Type")
 	if contentType != "application/json" {
+		putBuffer(buf)
 		level.Warn(a.logger).Log("msg", fmt.Sprintf("invalid Content-Type %s, want `application/json`", contentType))
 		http.Error(w, "invalid Content-Type, want `application/json`", http.StatusUnsupportedMediaType)
-		return
+		return nil
 	}
 
-	level.Debug(a.logger).Log("msg", "Received request", "content", string(body))
+	return buf
+}
+
+// serveAdmission decodes the AdmissionReview, runs the admit function and
+// writes the response. The request is traced with one span per phase, the
+// admit function being traced as the given operation.
+func (a *Admission) serveAdmission(w http.ResponseWriter, r *http.Request, operation string, admit admitFunc) {
+	span, ctx := a.startRequestSpan(r)
+	defer span.End()
+
+	buf := a.readRequestBody(w, r)
+	if buf == nil {
+		return
+	}
+	defer putBuffer(buf)
 
-	requestedAdmissionReview := v1.AdmissionReview{}
-	responseAdmissionReview := v1.AdmissionReview{}
+	level.Debug(a.logger).Log("msg", "Received request", "content", lazyString(buf.Bytes()))
 
-	if _, _, err := deserializer.Decode(body, nil, &requestedAdmissionReview); err != nil {
+	// Both admission.k8s.io/v1 and admission.k8s.io/v1beta1 requests are
+	// supported. The review is processed as v1 and the response is sent back
+	// with the version of the request.
+	var response *v1.AdmissionResponse
+	decodeSpan, _ := a.startSpan(ctx, "decode")
+	requestedAdmissionReview, gvk, err := decodeAdmissionReview(buf.Bytes())
+	decodeSpan.End()
+	if err != nil {
 		level.Warn(a.logger).Log("msg", "Unable to deserialize request", "err", err)
-		responseAdmissionReview.Response = toAdmissionResponseFailure("Unable to deserialize request", []error{err})
+		recordError(span, err)
+		response = toAdmissionResponseFailure("Unable to deserialize request", "", []error{err})
 	} else {
-		responseAdmissionReview.Response = admit(requestedAdmissionReview)
+		admitSpan, admitCtx := a.startSpan(ctx, operation)
+		response = admit(admitCtx, requestedAdmissionReview)
+		admitSpan.End()
+		response.UID = requestedAdmissionReview.Request.UID
+		a.audit(requestedAdmissionReview.Request, response)
 	}
+	setAdmissionAttributes(span, requestedAdmissionReview.Request, response)
 
-	responseAdmissionReview.Response.UID = requestedAdmissionReview.Request.UID
-	responseAdmissionReview.APIVersion = requestedAdmissionReview.APIVersion
-	responseAdmissionReview.Kind = requestedAdmissionReview.Kind
-
-	respBytes, err := json.Marshal(responseAdmissionReview)
+	respBytes, err := encodeAdmissionReview(response, gvk)
 
-	level.Debug(a.logger).Log("msg", "sending response", "content", string(respBytes))
+	level.Debug(a.logger).Log("msg", "sending response", "content", lazyString(respBytes))
 
 	if err != nil {
 		level.Error(a.logger).Log("msg", "Cannot serialize response", "err", err)
 		http.Error(w, fmt.Sprintf("could not serialize response: %v", err), http.StatusInternalServerError)
+		return
 	}
-	if _, err := w.Write(respBytes); err != nil {
-		level.Error(a.logger).Log("msg", "Cannot write response", "err", err)
-		http.Error(w, fmt.Sprintf("could not write response: %v", err), http.StatusInternalServerError)
-	}
+
+	a.writeResponse(w, r, respBytes)
 }
 
-func (a *Admission) mutatePrometheusRules(ar v1.AdmissionReview) *v1.AdmissionResponse {
+func (a *Admission) mutatePrometheusRules(ctx context.Context, ar v1.AdmissionReview) *v1.AdmissionResponse {
 	level.Debug(a.logger).Log("msg", "Mutating prometheusrules")
 
 	if ar.Request.Resource != ruleResource {
 		err := fmt.Errorf("expected resource to be %v, but received %v", ruleResource, ar.Request.Resource)
 		level.Warn(a.logger).Log("err", err)
-		return toAdmissionResponseFailure("Unexpected resource kind", []error{err})
+		return toAdmissionResponseFailure("Unexpected resource kind", ruleResource.Resource, []error{err})
 	}
 
-	rule := &PrometheusRules{}
+	// The object is decoded once, the rule groups keeping the raw values of
+	// the labels and annotations.
+	rule := &mutatedPrometheusRule{}
 	if err := json.Unmarshal(ar.Request.Object.Raw, rule); err != nil {
-		level.Info(a.logger).Log("msg", errUnmarshalAdmission, "err", err)
-		return toAdmissionResponseFailure(errUnmarshalAdmission, []error{err})
+		level.Info(a.logger).Log("msg", errUnmarshalRules, "err", err)
+		return toAdmissionResponseFailure(errUnmarshalRules, ruleResource.Resource, []error{err})
 	}
 
-	patches, err := generatePatchesForNonStringLabelsAnnotations(rule.Spec.Raw)
-	if err != nil {
-		level.Info(a.logger).Log("msg", errUnmarshalRules, "err", err)
-		return toAdmissionResponseFailure(errUnmarshalRules, []error{err})
+	span, _ := a.startSpan(ctx, "generate-patches")
+	defer span.End()
+
+	var patches []string
+	if a.config.CanonicalizeRules {
+		// The groups are reordered first, the other patches refer to the
+		// reordered groups.
+		patches = generateCanonicalizationPatches(&rule.Spec)
 	}
+	patches = append(patches, generatePatchesForNonStringLabelsAnnotations(&rule.Spec)...)
 
-	reviewResponse := &v1.AdmissionResponse{Allowed: true}
+	if a.config.EnforcedNamespaceLabel != "" {
+		nsPatches, err := generateNamespaceLabelPatches(&rule.Spec, a.config.EnforcedNamespaceLabel, ar.Request.Namespace)
+		if err != nil {
+			level.Info(a.logger).Log("msg", "Cannot enforce the namespace label", "err", err)
+			return toAdmissionResponseFailure("Cannot enforce the namespace label", ruleResource.Resource, []error{err})
+		}
+		patches = append(patches, nsPatches...)
+	}
 
-	if len(rule.Annotations) == 0 {
-		patches = append(patches, addFirstAnnotationPatch)
-	} else {
-		patches = append(patches, addAdditionalAnnotationPatch)
+	key, value := a.config.MutationAnnotation, a.config.MutationAnnotationValue
+	if key == "" {
+		key, value = DefaultMutationAnnotation, DefaultMutationAnnotationValue
+	}
+
+	annotationPatch, err := generateAnnotationPatch(rule.Annotations, key, value)
+	if err != nil {
+		level.Info(a.logger).Log("msg", "Cannot generate annotation patch", "err", err)
+		return toAdmissionResponseFailure("Cannot generate annotation patch", ruleResource.Resource, []error{err})
 	}
+	patches = append(patches, annotationPatch)
+	patches = append(patches, a.config.MutationPatches...)
+
+	reviewResponse := &v1.AdmissionResponse{Allowed: true}
 	pt := v1.PatchTypeJSONPatch
 	reviewResponse.PatchType = &pt
 	reviewResponse.Patch = []byte(fmt.Sprintf("[%s]", strings.Join(patches, ",")))
 	return reviewResponse
 }
 
-func (a *Admission) validatePrometheusRules(ar v1.AdmissionReview) *v1.AdmissionResponse {
-	a.validationTriggeredCounter.Inc()
+func (a *Admission) validatePrometheusRules(ctx context.Context, ar v1.AdmissionReview) *v1.AdmissionResponse {
+	a.validationTriggeredCounter.WithLabelValues(ar.Request.Namespace, ruleResource.Resource).Inc()
+	errorsCounter := a.validationErrorsCounter.WithLabelValues(ar.Request.Namespace, ruleResource.Resource)
 	level.Debug(a.logger).Log("msg", "Validating prometheusrules")
 
 	if ar.Request.Resource != ruleResource {
 		err := fmt.Errorf("expected resource to be %v, but received %v", ruleResource, ar.Request.Resource)
 		level.Warn(a.logger).Log("err", err)
-		a.validationErrorsCounter.Inc()
-		return toAdmissionResponseFailure("Unexpected resource kind", []error{err})
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Unexpected resource kind", ruleResource.Resource, []error{err})
+	}
+
+	if ar.Request.Operation == v1.Delete {
+		resp := a.validateDeletion(ar, ruleResource.Resource)
+		if !resp.Allowed {
+			errorsCounter.Inc()
+		}
+		return resp
 	}
 
 	promRule := &monitoringv1.PrometheusRule{}
 	if err := json.Unmarshal(ar.Request.Object.Raw, promRule); err != nil {
 		level.Info(a.logger).Log("msg", errUnmarshalRules, "err", err)
-		a.validationErrorsCounter.Inc()
-		return toAdmissionResponseFailure(errUnmarshalRules, []error{err})
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure(errUnmarshalRules, ruleResource.Resource, []error{err})
 	}
 
 	errors := promoperator.ValidateRule(promRule.Spec)
@@ -209,8 +575,401 @@ func (a *Admission) validatePrometheusRules(ar v1.AdmissionReview) *v1.Admission
 			level.Info(a.logger).Log("msg", m, "err", err)
 		}
 
-		a.validationErrorsCounter.Inc()
-		return toAdmissionResponseFailure("Rules are not valid", errors)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Rules are not valid", ruleResource.Resource, errors)
+	}
+
+	if errors := promoperator.ValidateRuleTemplates(promRule.Spec); len(errors) != 0 {
+		const m = "Invalid rule templates"
+		for _, err := range errors {
+			level.Info(a.logger).Log("msg", m, "err", err)
+		}
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Rule templates are not valid", ruleResource.Resource, errors)
+	}
+
+	if errors := a.config.PromQLCompatibility.validate(promRule.Spec); len(errors) != 0 {
+		const m = "Rules use PromQL features unsupported by the Prometheus version"
+		for _, err := range errors {
+			level.Info(a.logger).Log("msg", m, "err", err)
+		}
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure(m, ruleResource.Resource, errors)
+	}
+
+	if errors := a.config.MetricNamePolicy.validate(promRule.Spec); len(errors) != 0 {
+		const m = "Rules query forbidden metrics"
+		for _, err := range errors {
+			level.Info(a.logger).Log("msg", m, "err", err)
+		}
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure(m, ruleResource.Resource, errors)
+	}
+
+	if errors := a.config.RuleQuota.validate(promRule, a.config.NamespaceRuleLister); len(errors) != 0 {
+		const m = "Rules exceed the quota"
+		for _, err := range errors {
+			level.Info(a.logger).Log("msg", m, "err", err)
+		}
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure(m, ruleResource.Resource, errors)
+	}
+
+	content, err := promoperator.GenerateContent(promRule.Spec, a.logger)
+	if err != nil {
+		level.Info(a.logger).Log("msg", "Cannot generate rule file", "err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Cannot generate rule file", ruleResource.Resource, []error{err})
+	}
+
+	if len(content) > promoperator.MaxRuleFileSize() {
+		err := fmt.Errorf("the generated rule file is %d bytes which exceeds the limit of %d bytes", len(content), promoperator.MaxRuleFileSize())
+		level.Info(a.logger).Log("msg", "Rules are too large", "err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Rules are too large", ruleResource.Resource, []error{err})
+	}
+
+	if errors := a.config.RulePolicy.validate(promRule.Spec); len(errors) != 0 {
+		const m = "Rules don't comply with the policy"
+		for _, err := range errors {
+			level.Info(a.logger).Log("msg", m, "err", err)
+		}
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure(m, ruleResource.Resource, errors)
+	}
+
+	if errors := a.evaluatePolicies(ctx, promRule); len(errors) != 0 {
+		const m = "Rules violate the custom policies"
+		for _, err := range errors {
+			level.Info(a.logger).Log("msg", m, "err", err)
+		}
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure(m, ruleResource.Resource, errors)
+	}
+
+	if tests, ok := promRule.Annotations[ruleTestsAnnotation]; ok {
+		errors := promoperator.RunRuleTests(promRule.Spec, tests)
+		if len(errors) != 0 {
+			const m = "Rule unit tests failed"
+			for _, err := range errors {
+				level.Info(a.logger).Log("msg", m, "err", err)
+			}
+
+			errorsCounter.Inc()
+			return toAdmissionResponseFailure("Rule unit tests failed", ruleResource.Resource, errors)
+		}
+	}
+
+	denials, warnings := a.runRuleChecks(promRule, content)
+	if len(denials) != 0 {
+		const m = "Rule checks failed"
+		for _, err := range denials {
+			level.Info(a.logger).Log("msg", m, "err", err)
+		}
+
+		errorsCounter.Inc()
+		resp := toAdmissionResponseFailure("Rules failed checks", ruleResource.Resource, denials)
+		resp.Warnings = warnings
+		return resp
+	}
+
+	return &v1.AdmissionResponse{Allowed: true, Warnings: warnings}
+}
+
+func (a *Admission) validateAlertmanagerConfig(ctx context.Context, ar v1.AdmissionReview) *v1.AdmissionResponse {
+	a.amConfValidationTriggeredCounter.WithLabelValues(ar.Request.Namespace, alertmanagerConfigResource.Resource).Inc()
+	errorsCounter := a.amConfValidationErrorsCounter.WithLabelValues(ar.Request.Namespace, alertmanagerConfigResource.Resource)
+	level.Debug(a.logger).Log("msg", "Validating alertmanagerconfigs")
+
+	if ar.Request.Resource != alertmanagerConfigResource {
+		err := fmt.Errorf("expected resource to be %v, but received %v", alertmanagerConfigResource, ar.Request.Resource)
+		level.Warn(a.logger).Log("err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Unexpected resource kind", alertmanagerConfigResource.Resource, []error{err})
+	}
+
+	if ar.Request.Operation == v1.Delete {
+		resp := a.validateDeletion(ar, alertmanagerConfigResource.Resource)
+		if !resp.Allowed {
+			errorsCounter.Inc()
+		}
+		return resp
+	}
+
+	amConf := &monitoringv1alpha1.AlertmanagerConfig{}
+	if err := json.Unmarshal(ar.Request.Object.Raw, amConf); err != nil {
+		level.Info(a.logger).Log("msg", errUnmarshalConfig, "err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure(errUnmarshalConfig, alertmanagerConfigResource.Resource, []error{err})
+	}
+
+	if err := alertmanager.ValidateAlertmanagerConfig(amConf); err != nil {
+		const m = "Invalid config"
+		level.Debug(a.logger).Log("msg", m, "content", amConf.Spec)
+		level.Info(a.logger).Log("msg", m, "err", err)
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("AlertmanagerConfig is not valid", alertmanagerConfigResource.Resource, []error{err})
+	}
+
+	if errs := a.checkReceiverSecretAccess(ctx, ar.Request.UserInfo, ar.Request.Namespace, amConf); len(errs) != 0 {
+		level.Info(a.logger).Log("msg", "Forbidden secret namespace", "err", errs[0])
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("AlertmanagerConfig references secrets which can't be read", alertmanagerConfigResource.Resource, errs)
+	}
+
+	return &v1.AdmissionResponse{Allowed: true}
+}
+
+// checkReceiverSecretAccess verifies that the user can read the Secrets and
+// ConfigMaps from the namespaces referenced by the receivers of the
+// AlertmanagerConfig, other than the namespace of the object.
+func (a *Admission) checkReceiverSecretAccess(ctx context.Context, user authenticationv1.UserInfo, namespace string, amConf *monitoringv1alpha1.AlertmanagerConfig) []error {
+	if a.config.SecretAccessReviewer == nil {
+		return nil
+	}
+
+	var errs []error
+	for i, receiver := range amConf.Spec.Receivers {
+		if receiver.SecretNamespace == "" || receiver.SecretNamespace == namespace {
+			continue
+		}
+
+		allowed, err := a.config.SecretAccessReviewer.CanReadSecrets(ctx, user, receiver.SecretNamespace)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("receivers[%d]: failed to review access to namespace %q: %v", i, receiver.SecretNamespace, err))
+			continue
+		}
+
+		if !allowed {
+			errs = append(errs, fmt.Errorf("receivers[%d]: user %q can't read the secrets of namespace %q", i, user.Username, receiver.SecretNamespace))
+		}
+	}
+
+	return errs
+}
+
+func (a *Admission) validateProbe(ctx context.Context, ar v1.AdmissionReview) *v1.AdmissionResponse {
+	a.probeValidationTriggeredCounter.WithLabelValues(ar.Request.Namespace, probeResource.Resource).Inc()
+	errorsCounter := a.probeValidationErrorsCounter.WithLabelValues(ar.Request.Namespace, probeResource.Resource)
+	level.Debug(a.logger).Log("msg", "Validating probes")
+
+	if ar.Request.Resource != probeResource {
+		err := fmt.Errorf("expected resource to be %v, but received %v", probeResource, ar.Request.Resource)
+		level.Warn(a.logger).Log("err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Unexpected resource kind", probeResource.Resource, []error{err})
+	}
+
+	if ar.Request.Operation == v1.Delete {
+		resp := a.validateDeletion(ar, probeResource.Resource)
+		if !resp.Allowed {
+			errorsCounter.Inc()
+		}
+		return resp
+	}
+
+	probe := &monitoringv1.Probe{}
+	if err := json.Unmarshal(ar.Request.Object.Raw, probe); err != nil {
+		level.Info(a.logger).Log("msg", errUnmarshalProbe, "err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure(errUnmarshalProbe, probeResource.Resource, []error{err})
+	}
+
+	if err := promoperator.ValidateProbe(probe); err != nil {
+		const m = "Invalid probe"
+		level.Debug(a.logger).Log("msg", m, "content", probe.Spec)
+		level.Info(a.logger).Log("msg", m, "err", err)
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Probe is not valid", probeResource.Resource, []error{err})
+	}
+
+	return &v1.AdmissionResponse{Allowed: true}
+}
+
+func (a *Admission) validateServiceMonitor(ctx context.Context, ar v1.AdmissionReview) *v1.AdmissionResponse {
+	a.monitorValidationTriggeredCounter.WithLabelValues(ar.Request.Namespace, serviceMonitorResource.Resource).Inc()
+	errorsCounter := a.monitorValidationErrorsCounter.WithLabelValues(ar.Request.Namespace, serviceMonitorResource.Resource)
+	level.Debug(a.logger).Log("msg", "Validating servicemonitors")
+
+	if ar.Request.Resource != serviceMonitorResource {
+		err := fmt.Errorf("expected resource to be %v, but received %v", serviceMonitorResource, ar.Request.Resource)
+		level.Warn(a.logger).Log("err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Unexpected resource kind", serviceMonitorResource.Resource, []error{err})
+	}
+
+	if ar.Request.Operation == v1.Delete {
+		resp := a.validateDeletion(ar, serviceMonitorResource.Resource)
+		if !resp.Allowed {
+			errorsCounter.Inc()
+		}
+		return resp
+	}
+
+	sm := &monitoringv1.ServiceMonitor{}
+	if err := json.Unmarshal(ar.Request.Object.Raw, sm); err != nil {
+		level.Info(a.logger).Log("msg", errUnmarshalMonitor, "err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure(errUnmarshalMonitor, serviceMonitorResource.Resource, []error{err})
+	}
+
+	if err := promoperator.ValidateServiceMonitor(sm); err != nil {
+		const m = "Invalid service monitor"
+		level.Debug(a.logger).Log("msg", m, "content", sm.Spec)
+		level.Info(a.logger).Log("msg", m, "err", err)
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("ServiceMonitor is not valid", serviceMonitorResource.Resource, []error{err})
+	}
+
+	return &v1.AdmissionResponse{Allowed: true}
+}
+
+func (a *Admission) validatePodMonitor(ctx context.Context, ar v1.AdmissionReview) *v1.AdmissionResponse {
+	a.monitorValidationTriggeredCounter.WithLabelValues(ar.Request.Namespace, podMonitorResource.Resource).Inc()
+	errorsCounter := a.monitorValidationErrorsCounter.WithLabelValues(ar.Request.Namespace, podMonitorResource.Resource)
+	level.Debug(a.logger).Log("msg", "Validating podmonitors")
+
+	if ar.Request.Resource != podMonitorResource {
+		err := fmt.Errorf("expected resource to be %v, but received %v", podMonitorResource, ar.Request.Resource)
+		level.Warn(a.logger).Log("err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Unexpected resource kind", podMonitorResource.Resource, []error{err})
+	}
+
+	if ar.Request.Operation == v1.Delete {
+		resp := a.validateDeletion(ar, podMonitorResource.Resource)
+		if !resp.Allowed {
+			errorsCounter.Inc()
+		}
+		return resp
+	}
+
+	pm := &monitoringv1.PodMonitor{}
+	if err := json.Unmarshal(ar.Request.Object.Raw, pm); err != nil {
+		level.Info(a.logger).Log("msg", errUnmarshalMonitor, "err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure(errUnmarshalMonitor, podMonitorResource.Resource, []error{err})
+	}
+
+	if err := promoperator.ValidatePodMonitor(pm); err != nil {
+		const m = "Invalid pod monitor"
+		level.Debug(a.logger).Log("msg", m, "content", pm.Spec)
+		level.Info(a.logger).Log("msg", m, "err", err)
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("PodMonitor is not valid", podMonitorResource.Resource, []error{err})
+	}
+
+	denials, warnings := a.runDeprecatedFieldsCheck(deprecatedPodMonitorFields(pm))
+	if len(denials) != 0 {
+		const m = "PodMonitor checks failed"
+		for _, err := range denials {
+			level.Info(a.logger).Log("msg", m, "err", err)
+		}
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("PodMonitor failed checks", podMonitorResource.Resource, denials)
+	}
+
+	return &v1.AdmissionResponse{Allowed: true, Warnings: warnings}
+}
+
+func (a *Admission) validatePrometheus(ctx context.Context, ar v1.AdmissionReview) *v1.AdmissionResponse {
+	a.promValidationTriggeredCounter.WithLabelValues(ar.Request.Namespace, prometheusResource.Resource).Inc()
+	errorsCounter := a.promValidationErrorsCounter.WithLabelValues(ar.Request.Namespace, prometheusResource.Resource)
+	level.Debug(a.logger).Log("msg", "Validating prometheuses")
+
+	if ar.Request.Resource != prometheusResource {
+		err := fmt.Errorf("expected resource to be %v, but received %v", prometheusResource, ar.Request.Resource)
+		level.Warn(a.logger).Log("err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Unexpected resource kind", prometheusResource.Resource, []error{err})
+	}
+
+	if ar.Request.Operation == v1.Delete {
+		resp := a.validateDeletion(ar, prometheusResource.Resource)
+		if !resp.Allowed {
+			errorsCounter.Inc()
+		}
+		return resp
+	}
+
+	p := &monitoringv1.Prometheus{}
+	if err := json.Unmarshal(ar.Request.Object.Raw, p); err != nil {
+		level.Info(a.logger).Log("msg", errUnmarshalPrometheus, "err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure(errUnmarshalPrometheus, prometheusResource.Resource, []error{err})
+	}
+
+	if err := promoperator.ValidatePrometheus(p); err != nil {
+		const m = "Invalid prometheus"
+		level.Debug(a.logger).Log("msg", m, "content", p.Spec)
+		level.Info(a.logger).Log("msg", m, "err", err)
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Prometheus is not valid", prometheusResource.Resource, []error{err})
+	}
+
+	denials, warnings := a.runDeprecatedFieldsCheck(deprecatedPrometheusFields(p))
+	if len(denials) != 0 {
+		const m = "Prometheus checks failed"
+		for _, err := range denials {
+			level.Info(a.logger).Log("msg", m, "err", err)
+		}
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Prometheus failed checks", prometheusResource.Resource, denials)
+	}
+
+	return &v1.AdmissionResponse{Allowed: true, Warnings: warnings}
+}
+
+func (a *Admission) validateScrapeConfig(ctx context.Context, ar v1.AdmissionReview) *v1.AdmissionResponse {
+	a.scValidationTriggeredCounter.WithLabelValues(ar.Request.Namespace, scrapeConfigResource.Resource).Inc()
+	errorsCounter := a.scValidationErrorsCounter.WithLabelValues(ar.Request.Namespace, scrapeConfigResource.Resource)
+	level.Debug(a.logger).Log("msg", "Validating scrapeconfigs")
+
+	if ar.Request.Resource != scrapeConfigResource {
+		err := fmt.Errorf("expected resource to be %v, but received %v", scrapeConfigResource, ar.Request.Resource)
+		level.Warn(a.logger).Log("err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure("Unexpected resource kind", scrapeConfigResource.Resource, []error{err})
+	}
+
+	if ar.Request.Operation == v1.Delete {
+		resp := a.validateDeletion(ar, scrapeConfigResource.Resource)
+		if !resp.Allowed {
+			errorsCounter.Inc()
+		}
+		return resp
+	}
+
+	sc := &monitoringv1alpha1.ScrapeConfig{}
+	if err := json.Unmarshal(ar.Request.Object.Raw, sc); err != nil {
+		level.Info(a.logger).Log("msg", errUnmarshalScrape, "err", err)
+		errorsCounter.Inc()
+		return toAdmissionResponseFailure(errUnmarshalScrape, scrapeConfigResource.Resource, []error{err})
+	}
+
+	if errs := promoperator.ScrapeConfigFieldErrors(sc); len(errs) > 0 {
+		const m = "Invalid scrape config"
+		level.Debug(a.logger).Log("msg", m, "content", sc.Spec)
+		for _, err := range errs {
+			level.Info(a.logger).Log("msg", m, "err", err)
+		}
+
+		errorsCounter.Inc()
+		return toAdmissionResponseFieldFailure("ScrapeConfig is not valid", scrapeConfigResource.Resource, errs)
 	}
 
 	return &v1.AdmissionResponse{Allowed: true}
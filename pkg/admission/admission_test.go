@@ -16,20 +16,28 @@ package admission
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 
 	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
-	"github.com/prometheus/client_golang/prometheus"
+	promoperator "github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"k8s.io/api/admission/v1"
+	"k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func TestMutateRule(t *testing.T) {
@@ -54,6 +62,86 @@ func TestMutateRuleNoAnnotations(t *testing.T) {
 	}
 }
 
+func TestMutateRuleCustomPatches(t *testing.T) {
+	testCases := []struct {
+		name                string
+		annotations         map[string]string
+		expectedAnnotations map[string]string
+		expectedLabels      map[string]string
+	}{
+		{
+			name: "Test no annotations",
+			expectedAnnotations: map[string]string{
+				"example.com/validated": "yes",
+			},
+			expectedLabels: map[string]string{
+				"team": "infra",
+			},
+		},
+		{
+			name: "Test existing annotations",
+			annotations: map[string]string{
+				"foo": "bar",
+			},
+			expectedAnnotations: map[string]string{
+				"foo":                   "bar",
+				"example.com/validated": "yes",
+			},
+			expectedLabels: map[string]string{
+				"team": "infra",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api()
+			a.config.MutationAnnotation = "example.com/validated"
+			a.config.MutationAnnotationValue = "yes"
+			if err := a.config.MutationPatches.Set(`[{"op":"add","path":"/metadata/labels","value":{"team":"infra"}}]`); err != nil {
+				t.Fatal(err)
+			}
+
+			ts := server(a.servePrometheusRulesMutate)
+			t.Cleanup(ts.Close)
+
+			request := buildAdmissionReview(t, ruleResource, "PrometheusRule", v1.Create, reviewObject{
+				annotations: tc.annotations,
+				spec:        `{"groups":[{"name":"test.rules","rules":[{"alert":"Test","expr":"vector(1)"}]}]}`,
+			})
+			resp := send(t, ts, request)
+
+			patch, err := jsonpatch.DecodePatch(resp.Response.Patch)
+			if err != nil {
+				t.Fatalf("Expected a valid patch, got %v", err)
+			}
+
+			rev := v1.AdmissionReview{}
+			if err := json.Unmarshal(request, &rev); err != nil {
+				t.Fatal(err)
+			}
+
+			raw, err := patch.Apply(rev.Request.Object.Raw)
+			if err != nil {
+				t.Fatalf("Expected to successfully apply patch, got %v", err)
+			}
+
+			var obj metav1.PartialObjectMetadata
+			if err := json.Unmarshal(raw, &obj); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedAnnotations, obj.Annotations) {
+				t.Fatalf("expected annotations %v, got %v", tc.expectedAnnotations, obj.Annotations)
+			}
+
+			if !reflect.DeepEqual(tc.expectedLabels, obj.Labels) {
+				t.Fatalf("expected labels %v, got %v", tc.expectedLabels, obj.Labels)
+			}
+		})
+	}
+}
+
 func TestAdmitGoodRule(t *testing.T) {
 	ts := server(api().servePrometheusRulesValidate)
 	defer ts.Close()
@@ -124,6 +212,439 @@ func TestAdmitBadRuleWithBooleanInAnnotations(t *testing.T) {
 	}
 }
 
+func TestAdmitBadRuleTemplates(t *testing.T) {
+	ts := server(api().servePrometheusRulesValidate)
+	t.Cleanup(ts.Close)
+
+	resp := send(t, ts, buildAdmissionReview(t, ruleResource, "PrometheusRule", v1.Create, reviewObject{
+		spec: `{"groups":[{"name":"test.rules","rules":[{"alert":"Test","annotations":{"message":"{{ .Lables.instance }} is down"},"expr":"up == 0"}]}]}`,
+	}))
+	if resp.Response.Allowed {
+		t.Fatal("Expected admission to not be allowed but it was")
+	}
+
+	if act := resp.Response.Result.Details.Causes[0].Message; !strings.Contains(act, "can't evaluate field Lables") {
+		t.Fatalf("Expected error about the unknown field, got %q", act)
+	}
+}
+
+func TestAdmitRuleWithUnitTests(t *testing.T) {
+	testCases := []struct {
+		name        string
+		tests       string
+		expectedErr bool
+	}{
+		{
+			name: "Test passing unit tests",
+			tests: `
+tests:
+- interval: 1m
+  input_series:
+  - series: 'up{job="node"}'
+    values: '0 0 0 0 0 0 0'
+  alert_rule_test:
+  - eval_time: 6m
+    alertname: Test
+    exp_alerts:
+    - exp_labels:
+        severity: critical
+        job: node
+      exp_annotations:
+        message: Test rule
+`,
+			expectedErr: false,
+		},
+		{
+			name: "Test failing unit tests",
+			tests: `
+tests:
+- interval: 1m
+  input_series:
+  - series: 'up{job="node"}'
+    values: '1 1 1 1 1 1 1'
+  alert_rule_test:
+  - eval_time: 6m
+    alertname: Test
+    exp_alerts:
+    - exp_labels:
+        severity: critical
+        job: node
+`,
+			expectedErr: true,
+		},
+		{
+			name:        "Test invalid unit tests",
+			tests:       `tests: {}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := server(api().servePrometheusRulesValidate)
+			t.Cleanup(ts.Close)
+
+			resp := send(t, ts, buildAdmissionReview(t, ruleResource, "PrometheusRule", v1.Create, reviewObject{
+				annotations: map[string]string{ruleTestsAnnotation: tc.tests},
+				spec:        `{"groups":[{"name":"test.rules","rules":[{"alert":"Test","annotations":{"message":"Test rule"},"expr":"up == 0","for":"5m","labels":{"severity":"critical"}}]}]}`,
+			}))
+			if resp.Response.Allowed == tc.expectedErr {
+				t.Fatalf(
+					"Unexpected admission result, wanted %v but got %v - (%s)",
+					!tc.expectedErr, resp.Response.Allowed, resp.Response.Result)
+			}
+		})
+	}
+}
+
+func TestAdmitRuleWithChecks(t *testing.T) {
+	testCases := []struct {
+		name             string
+		checks           RuleChecks
+		expectedAllowed  bool
+		expectedWarnings int
+	}{
+		{
+			name:            "Test no checks",
+			expectedAllowed: true,
+		},
+		{
+			name:             "Test warning",
+			checks:           RuleChecks{CheckMissingFor: ActionWarn},
+			expectedAllowed:  true,
+			expectedWarnings: 1,
+		},
+		{
+			name:            "Test denial",
+			checks:          RuleChecks{CheckMissingFor: ActionDeny},
+			expectedAllowed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api()
+			a.config.RuleChecks = tc.checks
+			ts := server(a.servePrometheusRulesValidate)
+			t.Cleanup(ts.Close)
+
+			resp := send(t, ts, buildAdmissionReview(t, ruleResource, "PrometheusRule", v1.Create, reviewObject{
+				spec: `{"groups":[{"name":"test.rules","rules":[{"alert":"Test","expr":"up == 0"}]}]}`,
+			}))
+			if resp.Response.Allowed != tc.expectedAllowed {
+				t.Fatalf("expected allowed to be %v, got %v - (%s)", tc.expectedAllowed, resp.Response.Allowed, resp.Response.Result)
+			}
+
+			if len(resp.Response.Warnings) != tc.expectedWarnings {
+				t.Fatalf("expected %d warnings, got %v", tc.expectedWarnings, resp.Response.Warnings)
+			}
+		})
+	}
+}
+
+func TestAdmitRuleFileSize(t *testing.T) {
+	spec := func(size int) string {
+		return fmt.Sprintf(`{"groups":[{"name":"test.rules","rules":[{"alert":"Test","expr":"vector(1)","annotations":{"description":%q}}]}]}`, strings.Repeat("a", size))
+	}
+
+	testCases := []struct {
+		name             string
+		spec             string
+		checks           RuleChecks
+		budget           int
+		expectedAllowed  bool
+		expectedWarnings int
+	}{
+		{
+			name:            "Test small rule file",
+			spec:            spec(100),
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test rule file larger than the ConfigMap limit",
+			spec:            spec(promoperator.MaxRuleFileSize()),
+			expectedAllowed: false,
+		},
+		{
+			name:             "Test rule file larger than the budget with warning",
+			spec:             spec(2000),
+			checks:           RuleChecks{CheckRuleFileSize: ActionWarn},
+			budget:           1000,
+			expectedAllowed:  true,
+			expectedWarnings: 1,
+		},
+		{
+			name:            "Test rule file larger than the budget with denial",
+			spec:            spec(2000),
+			checks:          RuleChecks{CheckRuleFileSize: ActionDeny},
+			budget:          1000,
+			expectedAllowed: false,
+		},
+		{
+			name:            "Test rule file smaller than the budget",
+			spec:            spec(100),
+			checks:          RuleChecks{CheckRuleFileSize: ActionDeny},
+			budget:          1000,
+			expectedAllowed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api()
+			a.config.RuleChecks = tc.checks
+			a.config.RuleFileSizeBudget = tc.budget
+			ts := server(a.servePrometheusRulesValidate)
+			t.Cleanup(ts.Close)
+
+			resp := send(t, ts, buildAdmissionReview(t, ruleResource, "PrometheusRule", v1.Create, reviewObject{spec: tc.spec}))
+			if resp.Response.Allowed != tc.expectedAllowed {
+				t.Fatalf("expected allowed to be %v, got %v - (%s)", tc.expectedAllowed, resp.Response.Allowed, resp.Response.Result)
+			}
+
+			if len(resp.Response.Warnings) != tc.expectedWarnings {
+				t.Fatalf("expected %d warnings, got %v", tc.expectedWarnings, resp.Response.Warnings)
+			}
+		})
+	}
+}
+
+func TestProtectedDeletion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		serve           func(*Admission) serveFunc
+		resource        metav1.GroupVersionResource
+		kind            string
+		annotations     map[string]string
+		expectedAllowed bool
+	}{
+		{
+			name:            "Test unprotected PrometheusRule",
+			serve:           func(a *Admission) serveFunc { return a.servePrometheusRulesValidate },
+			resource:        ruleResource,
+			kind:            "PrometheusRule",
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test protected PrometheusRule",
+			serve:           func(a *Admission) serveFunc { return a.servePrometheusRulesValidate },
+			resource:        ruleResource,
+			kind:            "PrometheusRule",
+			annotations:     map[string]string{protectedAnnotation: "true"},
+			expectedAllowed: false,
+		},
+		{
+			name:     "Test protected PrometheusRule with confirmation",
+			serve:    func(a *Admission) serveFunc { return a.servePrometheusRulesValidate },
+			resource: ruleResource,
+			kind:     "PrometheusRule",
+			annotations: map[string]string{
+				protectedAnnotation:       "true",
+				confirmDeletionAnnotation: "true",
+			},
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test protected AlertmanagerConfig",
+			serve:           func(a *Admission) serveFunc { return a.serveAlertmanagerConfigValidate },
+			resource:        alertmanagerConfigResource,
+			kind:            "AlertmanagerConfig",
+			annotations:     map[string]string{protectedAnnotation: "true"},
+			expectedAllowed: false,
+		},
+		{
+			name:            "Test protected Probe",
+			serve:           func(a *Admission) serveFunc { return a.serveProbeValidate },
+			resource:        probeResource,
+			kind:            "Probe",
+			annotations:     map[string]string{protectedAnnotation: "true"},
+			expectedAllowed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := server(tc.serve(api()))
+			t.Cleanup(ts.Close)
+
+			resp := send(t, ts, buildAdmissionReview(t, tc.resource, tc.kind, v1.Delete, reviewObject{annotations: tc.annotations}))
+			if resp.Response.Allowed != tc.expectedAllowed {
+				t.Fatalf("expected allowed to be %v, got %v - (%s)", tc.expectedAllowed, resp.Response.Allowed, resp.Response.Result)
+			}
+
+			if !tc.expectedAllowed && resp.Response.Result.Code != http.StatusForbidden {
+				t.Fatalf("expected status code %d, got %d", http.StatusForbidden, resp.Response.Result.Code)
+			}
+		})
+	}
+}
+
+func TestAdmissionReviewV1beta1(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		request         []byte
+		serve           func(*Admission) serveFunc
+		expectedAllowed bool
+		expectedPatch   bool
+	}{
+		{
+			name:            "validate good rule",
+			request:         goodRulesWithAnnotations,
+			serve:           func(a *Admission) serveFunc { return a.servePrometheusRulesValidate },
+			expectedAllowed: true,
+		},
+		{
+			name:    "validate bad rule",
+			request: badRulesNoAnnotations,
+			serve:   func(a *Admission) serveFunc { return a.servePrometheusRulesValidate },
+		},
+		{
+			name:            "mutate rule",
+			request:         goodRulesWithAnnotations,
+			serve:           func(a *Admission) serveFunc { return a.servePrometheusRulesMutate },
+			expectedAllowed: true,
+			expectedPatch:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := server(tc.serve(api()))
+			t.Cleanup(ts.Close)
+
+			request := bytes.Replace(tc.request, []byte(`"admission.k8s.io/v1"`), []byte(`"admission.k8s.io/v1beta1"`), 1)
+			resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(request))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			rev := &v1beta1.AdmissionReview{}
+			if err := json.NewDecoder(resp.Body).Decode(rev); err != nil {
+				t.Fatalf("unable to parse webhook response: %s", err)
+			}
+
+			if rev.APIVersion != "admission.k8s.io/v1beta1" || rev.Kind != "AdmissionReview" {
+				t.Fatalf("expected admission.k8s.io/v1beta1 AdmissionReview, got %s %s", rev.APIVersion, rev.Kind)
+			}
+
+			if rev.Response.UID != "87c5df7f-5090-11e9-b9b4-02425473f309" {
+				t.Fatalf("unexpected UID %q", rev.Response.UID)
+			}
+
+			if rev.Response.Allowed != tc.expectedAllowed {
+				t.Fatalf("expected allowed to be %v, got %v", tc.expectedAllowed, rev.Response.Allowed)
+			}
+
+			if tc.expectedPatch && (len(rev.Response.Patch) == 0 || rev.Response.PatchType == nil || *rev.Response.PatchType != v1beta1.PatchTypeJSONPatch) {
+				t.Fatalf("expected a JSON patch, got %q", rev.Response.Patch)
+			}
+		})
+	}
+}
+
+func TestRequestBodySizeLimit(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		limit          int
+		expectedStatus int
+	}{
+		{
+			name:           "within limit",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "too large",
+			limit:          len(goodRulesWithAnnotations) - 1,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api()
+			a.config.MaxRequestBodySize = tc.limit
+			ts := server(a.servePrometheusRulesValidate)
+			t.Cleanup(ts.Close)
+
+			resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(goodRulesWithAnnotations))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			expectedRejections := 0.0
+			if tc.expectedStatus == http.StatusRequestEntityTooLarge {
+				expectedRejections = 1
+			}
+			if v := testutil.ToFloat64(a.oversizedRequestsCounter); v != expectedRejections {
+				t.Fatalf("expected %v rejected requests, got %v", expectedRejections, v)
+			}
+		})
+	}
+}
+
+func TestValidationMetrics(t *testing.T) {
+	a := api()
+	ts := server(a.servePrometheusRulesValidate)
+	t.Cleanup(ts.Close)
+
+	send(t, ts, goodRulesWithAnnotations)
+	send(t, ts, badRulesNoAnnotations)
+
+	if v := testutil.ToFloat64(a.validationTriggeredCounter.WithLabelValues("monitoring", "prometheusrules")); v != 2 {
+		t.Fatalf("expected 2 triggered validations, got %v", v)
+	}
+
+	if v := testutil.ToFloat64(a.validationErrorsCounter.WithLabelValues("monitoring", "prometheusrules")); v != 1 {
+		t.Fatalf("expected 1 validation error, got %v", v)
+	}
+
+	if n := testutil.CollectAndCount(a.validationErrorsCounter); n != 1 {
+		t.Fatalf("expected a single labelled series, got %d", n)
+	}
+}
+
+func TestRegisterHandlers(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		handlers   []string
+		registered []string
+		missing    []string
+	}{
+		{
+			name:       "all handlers by default",
+			registered: []string{prometheusRuleValidatePath, prometheusRuleMutatePath, alertmanagerConfigValidatePath, probeValidatePath, serviceMonitorValidatePath, podMonitorValidatePath, prometheusValidatePath, scrapeConfigValidatePath, conversionPath},
+			missing:    []string{healthzPath, readyzPath},
+		},
+		{
+			name:       "prometheusrules only",
+			handlers:   []string{PrometheusRulesHandler},
+			registered: []string{prometheusRuleValidatePath, prometheusRuleMutatePath},
+			missing:    []string{alertmanagerConfigValidatePath, probeValidatePath, serviceMonitorValidatePath, podMonitorValidatePath, prometheusValidatePath, scrapeConfigValidatePath, conversionPath, healthzPath, readyzPath},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api()
+			a.config.Handlers = tc.handlers
+			mux := http.NewServeMux()
+			a.Register(mux)
+
+			for _, path := range tc.registered {
+				if _, pattern := mux.Handler(httptest.NewRequest(http.MethodPost, path, nil)); pattern != path {
+					t.Fatalf("expected %s to be registered", path)
+				}
+			}
+
+			for _, path := range tc.missing {
+				if _, pattern := mux.Handler(httptest.NewRequest(http.MethodPost, path, nil)); pattern != "" {
+					t.Fatalf("expected %s not to be registered", path)
+				}
+			}
+		})
+	}
+}
+
 func TestMutateNonStringsToStrings(t *testing.T) {
 	request := nonStringsInLabelsAnnotations
 	ts := server(api().servePrometheusRulesMutate)
@@ -138,7 +659,7 @@ func TestMutateNonStringsToStrings(t *testing.T) {
 		t.Fatal(err, "Expected a valid patch")
 	}
 	rev := v1.AdmissionReview{}
-	deserializer.Decode(nonStringsInLabelsAnnotations, nil, &rev)
+	json.Unmarshal(nonStringsInLabelsAnnotations, &rev)
 	rev.Request.Object.Raw, err = patchObj.Apply(rev.Request.Object.Raw)
 	if err != nil {
 		fmt.Println(string(resp.Response.Patch))
@@ -157,20 +678,755 @@ func TestMutateNonStringsToStrings(t *testing.T) {
 	}
 }
 
-func api() *Admission {
-	validationTriggered := prometheus.NewCounter(prometheus.CounterOpts{
-		Name: "prometheus_operator_rule_validation_triggered_total",
-		Help: "Number of times a prometheusRule object triggered validation",
-	})
+func TestAlertmanagerConfigAdmission(t *testing.T) {
+	testCases := []struct {
+		name           string
+		spec           string
+		expectAdmitted bool
+	}{
+		{
+			name: "Test reject on duplicate receiver",
+			spec: `{
+  "route": {
+    "receiver": "wechat-example"
+  },
+  "receivers": [
+    {
+      "name": "wechat-example"
+    },
+    {
+      "name": "wechat-example"
+    }
+  ]
+}`,
+			expectAdmitted: false,
+		},
+		{
+			name: "Test reject on unknown receiver in route",
+			spec: `{
+  "route": {
+    "receiver": "does-not-exist"
+  },
+  "receivers": [
+    {
+      "name": "wechat-example"
+    }
+  ]
+}`,
+			expectAdmitted: false,
+		},
+		{
+			name: "Test reject on invalid route duration",
+			spec: `{
+  "route": {
+    "receiver": "wechat-example",
+    "groupWait": "30seconds"
+  },
+  "receivers": [
+    {
+      "name": "wechat-example"
+    }
+  ]
+}`,
+			expectAdmitted: false,
+		},
+		{
+			name: "Test reject on invalid regex matcher",
+			spec: `{
+  "route": {
+    "receiver": "wechat-example",
+    "matchers": [
+      {
+        "name": "severity",
+        "value": "(critical",
+        "regex": true
+      }
+    ]
+  },
+  "receivers": [
+    {
+      "name": "wechat-example"
+    }
+  ]
+}`,
+			expectAdmitted: false,
+		},
+		{
+			name: "Test reject on invalid inhibit rule matcher",
+			spec: `{
+  "inhibitRules": [
+    {
+      "sourceMatch": [
+        {
+          "name": "invalid-label",
+          "value": "critical"
+        }
+      ]
+    }
+  ]
+}`,
+			expectAdmitted: false,
+		},
+		{
+			name: "Test happy path",
+			spec: `{
+  "route": {
+    "receiver": "wechat-example",
+    "groupBy": ["job"],
+    "groupWait": "30s",
+    "groupInterval": "5m",
+    "repeatInterval": "12h",
+    "matchers": [
+      {
+        "name": "severity",
+        "value": "critical|warning",
+        "regex": true
+      }
+    ]
+  },
+  "receivers": [
+    {
+      "name": "wechat-example",
+      "wechatConfigs": [
+        {
+          "apiURL": "http://wechatserver:8080/",
+          "corpID": "wechat-corpid",
+          "apiSecret": {
+            "name": "wechat-config",
+            "key": "apiSecret"
+          }
+        }
+      ]
+    }
+  ],
+  "inhibitRules": [
+    {
+      "sourceMatch": [
+        {
+          "name": "severity",
+          "value": "critical"
+        }
+      ],
+      "targetMatch": [
+        {
+          "name": "severity",
+          "value": "warning"
+        }
+      ],
+      "equal": ["job"]
+    }
+  ]
+}`,
+			expectAdmitted: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := server(api().serveAlertmanagerConfigValidate)
+			t.Cleanup(ts.Close)
+
+			resp := send(t, ts, buildAdmissionReview(t, alertmanagerConfigResource, "AlertmanagerConfig", v1.Create, reviewObject{spec: tc.spec}))
+			if resp.Response.Allowed != tc.expectAdmitted {
+				t.Errorf("Unexpected admission result, wanted %v but got %v - (warnings=%v) - (details=%v)",
+					tc.expectAdmitted, resp.Response.Allowed, resp.Response.Warnings, resp.Response.Result.Details)
+			}
+		})
+	}
+}
+
+// fakeSecretAccessReviewer allows the access to the namespaces set to true.
+type fakeSecretAccessReviewer map[string]bool
+
+func (r fakeSecretAccessReviewer) CanReadSecrets(_ context.Context, user authenticationv1.UserInfo, namespace string) (bool, error) {
+	if user.Username != "kubernetes-admin" {
+		return false, fmt.Errorf("unexpected user %q", user.Username)
+	}
+
+	return r[namespace], nil
+}
+
+func TestAlertmanagerConfigSecretNamespaceAdmission(t *testing.T) {
+	spec := func(secretNamespace string) string {
+		return fmt.Sprintf(`{
+  "route": {
+    "receiver": "pagerduty"
+  },
+  "receivers": [
+    {
+      "name": "pagerduty",
+      "secretNamespace": %q
+    }
+  ]
+}`, secretNamespace)
+	}
+
+	for _, tc := range []struct {
+		name           string
+		reviewer       SecretAccessReviewer
+		spec           string
+		expectAdmitted bool
+	}{
+		{
+			name:           "own namespace",
+			reviewer:       fakeSecretAccessReviewer{},
+			spec:           spec("monitoring"),
+			expectAdmitted: true,
+		},
+		{
+			name:           "readable namespace",
+			reviewer:       fakeSecretAccessReviewer{"shared-credentials": true},
+			spec:           spec("shared-credentials"),
+			expectAdmitted: true,
+		},
+		{
+			name:           "unreadable namespace",
+			reviewer:       fakeSecretAccessReviewer{"shared-credentials": true},
+			spec:           spec("kube-system"),
+			expectAdmitted: false,
+		},
+		{
+			name:           "no reviewer",
+			spec:           spec("kube-system"),
+			expectAdmitted: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api()
+			a.config.SecretAccessReviewer = tc.reviewer
+			ts := server(a.serveAlertmanagerConfigValidate)
+			t.Cleanup(ts.Close)
+
+			resp := send(t, ts, buildAdmissionReview(t, alertmanagerConfigResource, "AlertmanagerConfig", v1.Create, reviewObject{spec: tc.spec}))
+			if resp.Response.Allowed != tc.expectAdmitted {
+				t.Errorf("Unexpected admission result, wanted %v but got %v - (details=%v)",
+					tc.expectAdmitted, resp.Response.Allowed, resp.Response.Result)
+			}
+		})
+	}
+}
+
+func TestProbeAdmission(t *testing.T) {
+	testCases := []struct {
+		name           string
+		spec           string
+		expectAdmitted bool
+	}{
+		{
+			name: "Test reject on missing prober URL",
+			spec: `{
+  "module": "http_2xx",
+  "targets": {
+    "staticConfig": {
+      "static": ["https://example.com"]
+    }
+  }
+}`,
+			expectAdmitted: false,
+		},
+		{
+			name: "Test reject on prober URL with scheme",
+			spec: `{
+  "prober": {
+    "url": "http://blackbox-exporter:9115"
+  },
+  "module": "http_2xx",
+  "targets": {
+    "staticConfig": {
+      "static": ["https://example.com"]
+    }
+  }
+}`,
+			expectAdmitted: false,
+		},
+		{
+			name: "Test reject on missing targets",
+			spec: `{
+  "prober": {
+    "url": "blackbox-exporter:9115"
+  },
+  "module": "http_2xx"
+}`,
+			expectAdmitted: false,
+		},
+		{
+			name: "Test reject on invalid relabeling",
+			spec: `{
+  "prober": {
+    "url": "blackbox-exporter:9115"
+  },
+  "module": "http_2xx",
+  "targets": {
+    "staticConfig": {
+      "static": ["https://example.com"],
+      "relabelingConfigs": [
+        {
+          "action": "hashmod",
+          "sourceLabels": ["__address__"],
+          "targetLabel": "__tmp_hash"
+        }
+      ]
+    }
+  }
+}`,
+			expectAdmitted: false,
+		},
+		{
+			name: "Test happy path",
+			spec: `{
+  "prober": {
+    "url": "blackbox-exporter:9115",
+    "path": "/probe"
+  },
+  "module": "http_2xx",
+  "interval": "30s",
+  "targets": {
+    "staticConfig": {
+      "static": ["https://example.com"],
+      "labels": {
+        "environment": "prod"
+      },
+      "relabelingConfigs": [
+        {
+          "sourceLabels": ["__param_target"],
+          "targetLabel": "instance"
+        }
+      ]
+    }
+  }
+}`,
+			expectAdmitted: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := server(api().serveProbeValidate)
+			t.Cleanup(ts.Close)
+
+			resp := send(t, ts, buildAdmissionReview(t, probeResource, "Probe", v1.Create, reviewObject{spec: tc.spec}))
+			if resp.Response.Allowed != tc.expectAdmitted {
+				t.Errorf("Unexpected admission result, wanted %v but got %v - (details=%v)",
+					tc.expectAdmitted, resp.Response.Allowed, resp.Response.Result.Details)
+			}
+		})
+	}
+}
+
+func TestMonitorAdmission(t *testing.T) {
+	a := api()
+	for _, tc := range []struct {
+		name           string
+		kind           string
+		resource       metav1.GroupVersionResource
+		endpoints      string
+		serve          serveFunc
+		expectAdmitted bool
+	}{
+		{
+			name:     "Test valid service monitor",
+			kind:     "ServiceMonitor",
+			resource: serviceMonitorResource,
+			endpoints: `"endpoints": [{
+  "port": "web",
+  "interval": "30s",
+  "relabelings": [{"sourceLabels": ["__meta_kubernetes_pod_node_name"], "targetLabel": "node"}],
+  "metricRelabelings": [{"action": "drop", "sourceLabels": ["__name__"], "regex": "go_.*"}]
+}]`,
+			serve:          a.serveServiceMonitorValidate,
+			expectAdmitted: true,
+		},
+		{
+			name:     "Test reject service monitor on invalid regex",
+			kind:     "ServiceMonitor",
+			resource: serviceMonitorResource,
+			endpoints: `"endpoints": [{
+  "port": "web",
+  "metricRelabelings": [{"action": "drop", "sourceLabels": ["__name__"], "regex": "(go_.*"}]
+}]`,
+			serve: a.serveServiceMonitorValidate,
+		},
+		{
+			name:     "Test reject service monitor on missing target label",
+			kind:     "ServiceMonitor",
+			resource: serviceMonitorResource,
+			endpoints: `"endpoints": [{
+  "port": "web",
+  "relabelings": [{"action": "replace", "sourceLabels": ["__meta_kubernetes_pod_node_name"]}]
+}]`,
+			serve: a.serveServiceMonitorValidate,
+		},
+		{
+			name:     "Test valid pod monitor",
+			kind:     "PodMonitor",
+			resource: podMonitorResource,
+			endpoints: `"podMetricsEndpoints": [{
+  "port": "web",
+  "relabelings": [{"action": "labeldrop", "regex": "pod_template_hash"}]
+}]`,
+			serve:          a.servePodMonitorValidate,
+			expectAdmitted: true,
+		},
+		{
+			name:     "Test reject pod monitor on invalid target label",
+			kind:     "PodMonitor",
+			resource: podMonitorResource,
+			endpoints: `"podMetricsEndpoints": [{
+  "port": "web",
+  "relabelings": [{"action": "hashmod", "sourceLabels": ["__address__"], "modulus": 2, "targetLabel": "$1"}]
+}]`,
+			serve: a.servePodMonitorValidate,
+		},
+		{
+			name:     "Test reject pod monitor sent to the service monitor handler",
+			kind:     "PodMonitor",
+			resource: podMonitorResource,
+			endpoints: `"podMetricsEndpoints": [{
+  "port": "web"
+}]`,
+			serve: a.serveServiceMonitorValidate,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := server(tc.serve)
+			t.Cleanup(ts.Close)
+
+			resp := send(t, ts, buildAdmissionReview(t, tc.resource, tc.kind, v1.Create, reviewObject{spec: fmt.Sprintf(`{"selector": {}, %s}`, tc.endpoints)}))
+			if resp.Response.Allowed != tc.expectAdmitted {
+				t.Errorf("Unexpected admission result, wanted %v but got %v - (details=%v)",
+					tc.expectAdmitted, resp.Response.Allowed, resp.Response.Result.Details)
+			}
+		})
+	}
+}
+
+func TestPrometheusAdmission(t *testing.T) {
+	a := api()
+	for _, tc := range []struct {
+		name           string
+		spec           string
+		expectAdmitted bool
+	}{
+		{
+			name:           "Test valid prometheus",
+			spec:           `{"retentionSize": "10GB"}`,
+			expectAdmitted: true,
+		},
+		{
+			name:           "Test valid prometheus without retention size",
+			spec:           `{}`,
+			expectAdmitted: true,
+		},
+		{
+			name: "Test reject prometheus on invalid retention size",
+			spec: `{"retentionSize": "10Gi"}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := server(a.servePrometheusValidate)
+			t.Cleanup(ts.Close)
+
+			resp := send(t, ts, buildAdmissionReview(t, prometheusResource, "Prometheus", v1.Create, reviewObject{spec: tc.spec}))
+			if resp.Response.Allowed != tc.expectAdmitted {
+				t.Errorf("Unexpected admission result, wanted %v but got %v - (details=%v)",
+					tc.expectAdmitted, resp.Response.Allowed, resp.Response.Result.Details)
+			}
+		})
+	}
+}
+
+func TestPrometheusAdmissionDeprecatedFields(t *testing.T) {
+	a := api()
+	a.config.RuleChecks = RuleChecks{CheckDeprecatedFields: ActionWarn}
+
+	ts := server(a.servePrometheusValidate)
+	t.Cleanup(ts.Close)
+
+	resp := send(t, ts, buildAdmissionReview(t, prometheusResource, "Prometheus", v1.Create, reviewObject{spec: `{"baseImage": "quay.io/prometheus/prometheus"}`}))
+	if !resp.Response.Allowed {
+		t.Fatalf("expected the object to be admitted, got %v", resp.Response.Result)
+	}
+
+	expected := []string{"field 'baseImage' is deprecated, use 'image' instead (deprecated-fields)"}
+	if !reflect.DeepEqual(resp.Response.Warnings, expected) {
+		t.Fatalf("expected warnings %v, got %v", expected, resp.Response.Warnings)
+	}
+}
+
+func TestScrapeConfigAdmission(t *testing.T) {
+	a := api()
+	for _, tc := range []struct {
+		name           string
+		spec           string
+		expectAdmitted bool
+		expectedFields []string
+	}{
+		{
+			name: "Test valid scrape config",
+			spec: `{
+  "staticConfigs": [{"targets": ["example.com:9100"], "labels": {"env": "prod"}}],
+  "relabelings": [{"sourceLabels": ["__address__"], "targetLabel": "instance"}],
+  "authorization": {"type": "Bearer", "credentials": {"name": "secret", "key": "token"}},
+  "scrapeInterval": "30s"
+}`,
+			expectAdmitted: true,
+		},
+		{
+			name: "Test reject scrape config with invalid fields",
+			spec: `{
+  "staticConfigs": [{"targets": ["example.com:9100", "http://example.com:9100/metrics"]}],
+  "httpSDConfigs": [{"url": "/targets"}],
+  "relabelings": [{"action": "hashmod", "targetLabel": "shard"}],
+  "authorization": {"type": "Basic", "credentials": {"name": "secret", "key": "token"}}
+}`,
+			expectedFields: []string{
+				"spec.staticConfigs[0].targets[1]",
+				"spec.httpSDConfigs[0].url",
+				"spec.relabelings[0]",
+				"spec.authorization",
+			},
+		},
+		{
+			name: "Test reject scrape config with invalid TLS config",
+			spec: `{
+  "tlsConfig": {"cert": {"secret": {"name": "secret", "key": "cert"}}}
+}`,
+			expectedFields: []string{"spec.tlsConfig"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := server(a.serveScrapeConfigValidate)
+			t.Cleanup(ts.Close)
+
+			resp := send(t, ts, buildAdmissionReview(t, scrapeConfigResource, "ScrapeConfig", v1.Create, reviewObject{spec: tc.spec}))
+			if resp.Response.Allowed != tc.expectAdmitted {
+				t.Fatalf("Unexpected admission result, wanted %v but got %v - (details=%v)",
+					tc.expectAdmitted, resp.Response.Allowed, resp.Response.Result.Details)
+			}
+
+			if tc.expectAdmitted {
+				return
+			}
+
+			var fields []string
+			for _, c := range resp.Response.Result.Details.Causes {
+				if c.Type != metav1.CauseTypeFieldValueInvalid {
+					t.Fatalf("expected cause type %q, got %q", metav1.CauseTypeFieldValueInvalid, c.Type)
+				}
+				fields = append(fields, c.Field)
+			}
+
+			if !reflect.DeepEqual(tc.expectedFields, fields) {
+				t.Fatalf("expected fields %v, got %v", tc.expectedFields, fields)
+			}
+		})
+	}
+}
+
+func TestAlertmanagerConfigConversion(t *testing.T) {
+	testCases := []struct {
+		name              string
+		desiredAPIVersion string
+		object            string
+		expectSuccess     bool
+		expectMatcher     map[string]interface{}
+	}{
+		{
+			name:              "Test conversion from v1alpha1 to v1beta1",
+			desiredAPIVersion: "monitoring.coreos.com/v1beta1",
+			object: `{
+  "apiVersion": "monitoring.coreos.com/v1alpha1",
+  "kind": "AlertmanagerConfig",
+  "metadata": {"name": "test", "namespace": "default"},
+  "spec": {
+    "route": {
+      "receiver": "webhook",
+      "matchers": [{"name": "job", "value": "node|kubelet", "regex": true}]
+    },
+    "receivers": [{
+      "name": "webhook",
+      "webhookConfigs": [{"urlSecret": {"name": "webhook", "key": "url"}}]
+    }]
+  }
+}`,
+			expectSuccess: true,
+			expectMatcher: map[string]interface{}{"name": "job", "value": "node|kubelet", "matchType": "=~"},
+		},
+		{
+			name:              "Test conversion from v1beta1 to v1alpha1",
+			desiredAPIVersion: "monitoring.coreos.com/v1alpha1",
+			object: `{
+  "apiVersion": "monitoring.coreos.com/v1beta1",
+  "kind": "AlertmanagerConfig",
+  "metadata": {"name": "test", "namespace": "default"},
+  "spec": {
+    "route": {
+      "receiver": "webhook",
+      "matchers": [{"name": "job", "value": "node|kubelet", "matchType": "=~"}]
+    },
+    "receivers": [{
+      "name": "webhook",
+      "webhookConfigs": [{"urlSecret": {"name": "webhook", "key": "url"}}]
+    }]
+  }
+}`,
+			expectSuccess: true,
+			expectMatcher: map[string]interface{}{"name": "job", "value": "node|kubelet", "regex": true},
+		},
+		{
+			name:              "Test same version",
+			desiredAPIVersion: "monitoring.coreos.com/v1alpha1",
+			object: `{
+  "apiVersion": "monitoring.coreos.com/v1alpha1",
+  "kind": "AlertmanagerConfig",
+  "metadata": {"name": "test", "namespace": "default"},
+  "spec": {
+    "route": {
+      "receiver": "webhook",
+      "matchers": [{"name": "job", "value": "node"}]
+    }
+  }
+}`,
+			expectSuccess: true,
+			expectMatcher: map[string]interface{}{"name": "job", "value": "node"},
+		},
+		{
+			name:              "Test failure on negative matcher",
+			desiredAPIVersion: "monitoring.coreos.com/v1alpha1",
+			object: `{
+  "apiVersion": "monitoring.coreos.com/v1beta1",
+  "kind": "AlertmanagerConfig",
+  "metadata": {"name": "test", "namespace": "default"},
+  "spec": {
+    "route": {
+      "receiver": "webhook",
+      "matchers": [{"name": "job", "value": "node", "matchType": "!="}]
+    }
+  }
+}`,
+		},
+		{
+			name:              "Test failure on unsupported kind",
+			desiredAPIVersion: "monitoring.coreos.com/v1beta1",
+			object: `{
+  "apiVersion": "monitoring.coreos.com/v1",
+  "kind": "Probe",
+  "metadata": {"name": "test", "namespace": "default"}
+}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := server(api().serveConvert)
+			t.Cleanup(ts.Close)
+
+			resp := sendConversionReview(t, ts, buildConversionReview(tc.desiredAPIVersion, tc.object))
+			if resp.Response == nil {
+				t.Fatal("expected response, got none")
+			}
+
+			if resp.Response.UID != "87c5df7f-5090-11e9-b9b4-02425473f309" {
+				t.Fatalf("unexpected UID %q", resp.Response.UID)
+			}
+
+			if !tc.expectSuccess {
+				if resp.Response.Result.Status != metav1.StatusFailure {
+					t.Fatalf("expected failure, got %q", resp.Response.Result.Status)
+				}
+				return
+			}
+
+			if resp.Response.Result.Status != metav1.StatusSuccess {
+				t.Fatalf("expected success, got %q (%s)", resp.Response.Result.Status, resp.Response.Result.Message)
+			}
+
+			if len(resp.Response.ConvertedObjects) != 1 {
+				t.Fatalf("expected 1 converted object, got %d", len(resp.Response.ConvertedObjects))
+			}
 
-	validationErrors := prometheus.NewCounter(prometheus.CounterOpts{
-		Name: "prometheus_operator_rule_validation_errors_total",
-		Help: "Number of errors that occurred while validating a prometheusRules object",
+			var obj struct {
+				APIVersion string `json:"apiVersion"`
+				Metadata   struct {
+					Name string `json:"name"`
+				} `json:"metadata"`
+				Spec struct {
+					Route struct {
+						Matchers []map[string]interface{} `json:"matchers"`
+					} `json:"route"`
+				} `json:"spec"`
+			}
+			if err := json.Unmarshal(resp.Response.ConvertedObjects[0].Raw, &obj); err != nil {
+				t.Fatal(err)
+			}
+
+			if obj.APIVersion != tc.desiredAPIVersion {
+				t.Fatalf("expected apiVersion %q, got %q", tc.desiredAPIVersion, obj.APIVersion)
+			}
+
+			if obj.Metadata.Name != "test" {
+				t.Fatalf("expected name %q, got %q", "test", obj.Metadata.Name)
+			}
+
+			if len(obj.Spec.Route.Matchers) != 1 || !reflect.DeepEqual(obj.Spec.Route.Matchers[0], tc.expectMatcher) {
+				t.Fatalf("expected matchers %v, got %v", []map[string]interface{}{tc.expectMatcher}, obj.Spec.Route.Matchers)
+			}
+		})
+	}
+}
+
+func BenchmarkServeAdmission(b *testing.B) {
+	var rules []string
+	for i := 0; i < 1000; i++ {
+		rules = append(rules, fmt.Sprintf(
+			`{"alert":"Alert%d","expr":"rate(http_requests_total{job=\"job%d\"}[5m]) > 10","for":"5m","labels":{"severity":"warning","priority":%d},"annotations":{"summary":"Too many requests for job%d"}}`,
+			i, i, i%5, i,
+		))
+	}
+	request := buildAdmissionReview(b, ruleResource, "PrometheusRule", v1.Create, reviewObject{
+		spec: fmt.Sprintf(`{"groups":[{"name":"bench.rules","rules":[%s]}]}`, strings.Join(rules, ",")),
 	})
+
+	for _, bc := range []struct {
+		name  string
+		serve func(*Admission) serveFunc
+	}{
+		{
+			name:  "validate",
+			serve: func(a *Admission) serveFunc { return a.servePrometheusRulesValidate },
+		},
+		{
+			name:  "mutate",
+			serve: func(a *Admission) serveFunc { return a.servePrometheusRulesMutate },
+		},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			serve := bc.serve(api())
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(request)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(request))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				serve(w, req)
+
+				if w.Code != http.StatusOK {
+					b.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+				}
+			}
+		})
+	}
+}
+
+func api() *Admission {
 	a := &Admission{
-		logger:                     log.NewLogfmtLogger(log.NewSyncWriter(os.Stdout)),
-		validationErrorsCounter:    validationErrors,
-		validationTriggeredCounter: validationTriggered}
+		logger:    log.NewLogfmtLogger(log.NewSyncWriter(os.Stdout)),
+		throttler: newThrottler(0, 0, 0)}
+	a.initMetrics()
 	a.logger = level.NewFilter(a.logger, level.AllowNone())
 	return a
 }
@@ -200,6 +1456,108 @@ func send(t *testing.T, ts *httptest.Server, rules []byte) *v1.AdmissionReview {
 	return rev
 }
 
+func sendConversionReview(t *testing.T, ts *httptest.Server, review []byte) *apiextensionsv1.ConversionReview {
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(review))
+	if err != nil {
+		t.Fatalf("Publish() returned an error: %s", err)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ioutil.ReadAll(resp.Body) returned an error: %s", err)
+	}
+
+	rev := &apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(body, rev); err != nil {
+		t.Fatalf("unable to parse webhook response: %s", err)
+	}
+
+	return rev
+}
+
+func buildConversionReview(desiredAPIVersion, object string) []byte {
+	tmpl := `
+{
+  "kind": "ConversionReview",
+  "apiVersion": "apiextensions.k8s.io/v1",
+  "request": {
+    "uid": "87c5df7f-5090-11e9-b9b4-02425473f309",
+    "desiredAPIVersion": "%s",
+    "objects": [%s]
+  }
+}`
+	return []byte(fmt.Sprintf(tmpl, desiredAPIVersion, object))
+}
+
+// reviewObject is the object sent in the admission reviews built by
+// buildAdmissionReview.
+type reviewObject struct {
+	annotations map[string]string
+	spec        string
+}
+
+// buildAdmissionReview returns the admission review of the operation on the
+// "test" object of the given resource. The object is sent as the old object
+// for the deletions.
+func buildAdmissionReview(tb testing.TB, resource metav1.GroupVersionResource, kind string, operation v1.Operation, object reviewObject) []byte {
+	tb.Helper()
+
+	obj, err := json.Marshal(struct {
+		metav1.TypeMeta   `json:",inline"`
+		metav1.ObjectMeta `json:"metadata"`
+		Spec              json.RawMessage `json:"spec,omitempty"`
+	}{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       kind,
+			APIVersion: resource.Group + "/" + resource.Version,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Namespace:   "monitoring",
+			Annotations: object.annotations,
+		},
+		Spec: json.RawMessage(object.spec),
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	req := &v1.AdmissionRequest{
+		UID: "87c5df7f-5090-11e9-b9b4-02425473f309",
+		Kind: metav1.GroupVersionKind{
+			Group:   resource.Group,
+			Version: resource.Version,
+			Kind:    kind,
+		},
+		Resource:  resource,
+		Namespace: "monitoring",
+		Name:      "test",
+		Operation: operation,
+		UserInfo: authenticationv1.UserInfo{
+			Username: "kubernetes-admin",
+			Groups:   []string{"system:masters", "system:authenticated"},
+		},
+	}
+	if operation == v1.Delete {
+		req.OldObject = runtime.RawExtension{Raw: obj}
+	} else {
+		req.Object = runtime.RawExtension{Raw: obj}
+	}
+
+	b, err := json.Marshal(v1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "AdmissionReview",
+			APIVersion: "admission.k8s.io/v1",
+		},
+		Request: req,
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return b
+}
+
 var goodRulesWithAnnotations = []byte(`
 {
   "kind": "AdmissionReview",
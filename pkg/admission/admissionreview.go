@@ -0,0 +1,92 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/admission/v1"
+	"k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var supportedAdmissionReviewVersions = map[schema.GroupVersion]struct{}{
+	v1.SchemeGroupVersion:      {},
+	v1beta1.SchemeGroupVersion: {},
+}
+
+// decodeAdmissionReview decodes an admission.k8s.io/v1 or
+// admission.k8s.io/v1beta1 AdmissionReview. Both versions share the same
+// serialization so the review is decoded once as v1 and returned together
+// with the GroupVersionKind of the request.
+func decodeAdmissionReview(body []byte) (v1.AdmissionReview, schema.GroupVersionKind, error) {
+	var review v1.AdmissionReview
+	if err := json.Unmarshal(body, &review); err != nil {
+		return v1.AdmissionReview{}, schema.GroupVersionKind{}, err
+	}
+
+	gvk := review.GroupVersionKind()
+	if _, found := supportedAdmissionReviewVersions[gvk.GroupVersion()]; !found || gvk.Kind != "AdmissionReview" {
+		return v1.AdmissionReview{}, schema.GroupVersionKind{}, errors.Errorf("unsupported object %v", gvk)
+	}
+
+	if review.Request == nil {
+		return v1.AdmissionReview{}, schema.GroupVersionKind{}, errors.New("admission review has no request")
+	}
+
+	return review, gvk, nil
+}
+
+// encodeAdmissionReview returns the JSON-encoded AdmissionReview holding the
+// response for the given GroupVersionKind. It defaults to
+// admission.k8s.io/v1 if the GroupVersionKind is empty.
+func encodeAdmissionReview(response *v1.AdmissionResponse, gvk schema.GroupVersionKind) ([]byte, error) {
+	if gvk.Empty() {
+		gvk = v1.SchemeGroupVersion.WithKind("AdmissionReview")
+	}
+
+	if gvk.GroupVersion() == v1beta1.SchemeGroupVersion {
+		review := v1beta1.AdmissionReview{
+			Response: convertAdmissionResponseToV1beta1(response),
+		}
+		review.SetGroupVersionKind(gvk)
+		return json.Marshal(review)
+	}
+
+	review := v1.AdmissionReview{
+		Response: response,
+	}
+	review.SetGroupVersionKind(gvk)
+	return json.Marshal(review)
+}
+
+func convertAdmissionResponseToV1beta1(in *v1.AdmissionResponse) *v1beta1.AdmissionResponse {
+	out := &v1beta1.AdmissionResponse{
+		UID:              in.UID,
+		Allowed:          in.Allowed,
+		Result:           in.Result,
+		Patch:            in.Patch,
+		AuditAnnotations: in.AuditAnnotations,
+		Warnings:         in.Warnings,
+	}
+
+	if in.PatchType != nil {
+		pt := v1beta1.PatchType(*in.PatchType)
+		out.PatchType = &pt
+	}
+
+	return out
+}
@@ -15,6 +15,8 @@
 package admission
 
 import (
+	"encoding/json"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -29,15 +31,41 @@ type PrometheusRules struct {
 	Spec runtime.RawExtension `json:"spec"`
 }
 
+// mutatedPrometheusRule holds the fields of a PrometheusRule required to
+// generate the mutation patches.
+type mutatedPrometheusRule struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              RuleGroups `json:"spec"`
+}
+
 type RuleGroups struct {
 	Groups []RuleGroup `json:"groups"`
 }
 
 type RuleGroup struct {
-	Rules []Rule `json:"rules"`
+	Name     string `json:"name"`
+	Interval string `json:"interval,omitempty"`
+	Rules    []Rule `json:"rules"`
+
+	// raw holds the JSON representation of the group, including the fields
+	// which aren't decoded.
+	raw json.RawMessage
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (g *RuleGroup) UnmarshalJSON(b []byte) error {
+	type plain RuleGroup
+	if err := json.Unmarshal(b, (*plain)(g)); err != nil {
+		return err
+	}
+
+	g.raw = append(g.raw[:0], b...)
+	return nil
 }
 
 type Rule struct {
+	Expr        interface{}            `json:"expr,omitempty"`
+	For         string                 `json:"for,omitempty"`
 	Labels      map[string]interface{} `json:"labels,omitempty"`
 	Annotations map[string]interface{} `json:"annotations,omitempty"`
 }
@@ -0,0 +1,91 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-kit/log/level"
+	v1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+)
+
+// AuditEvent records an admission decision.
+type AuditEvent struct {
+	Time      time.Time                 `json:"time"`
+	UID       string                    `json:"uid"`
+	Resource  string                    `json:"resource"`
+	Namespace string                    `json:"namespace,omitempty"`
+	Name      string                    `json:"name,omitempty"`
+	Operation string                    `json:"operation"`
+	UserInfo  authenticationv1.UserInfo `json:"userInfo"`
+	DryRun    bool                      `json:"dryRun,omitempty"`
+	Allowed   bool                      `json:"allowed"`
+	Message   string                    `json:"message,omitempty"`
+	Causes    []string                  `json:"causes,omitempty"`
+	Warnings  []string                  `json:"warnings,omitempty"`
+}
+
+func newAuditEvent(now time.Time, req *v1.AdmissionRequest, resp *v1.AdmissionResponse) AuditEvent {
+	ev := AuditEvent{
+		Time:      now.UTC(),
+		UID:       string(req.UID),
+		Resource:  req.Resource.Resource,
+		Namespace: req.Namespace,
+		Name:      req.Name,
+		Operation: string(req.Operation),
+		UserInfo:  req.UserInfo,
+		Allowed:   resp.Allowed,
+		Warnings:  resp.Warnings,
+	}
+
+	if req.DryRun != nil {
+		ev.DryRun = *req.DryRun
+	}
+
+	if resp.Result != nil {
+		ev.Message = resp.Result.Message
+		if resp.Result.Details != nil {
+			for _, c := range resp.Result.Details.Causes {
+				ev.Causes = append(ev.Causes, c.Message)
+			}
+		}
+	}
+
+	return ev
+}
+
+// audit writes the admission decision as a JSON line to the audit log, if
+// configured.
+func (a *Admission) audit(req *v1.AdmissionRequest, resp *v1.AdmissionResponse) {
+	if a.config.AuditLog == nil {
+		return
+	}
+
+	b, err := json.Marshal(newAuditEvent(time.Now(), req, resp))
+	if err != nil {
+		level.Warn(a.logger).Log("msg", "Cannot serialize audit event", "err", err)
+		return
+	}
+	b = append(b, '\n')
+
+	a.auditMtx.Lock()
+	defer a.auditMtx.Unlock()
+
+	if _, err := a.config.AuditLog.Write(b); err != nil {
+		level.Warn(a.logger).Log("msg", "Cannot write audit event", "err", err)
+	}
+}
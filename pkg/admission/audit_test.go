@@ -0,0 +1,87 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		request         []byte
+		expectedAllowed bool
+		expectedCauses  int
+	}{
+		{
+			name:            "allowed",
+			request:         goodRulesWithAnnotations,
+			expectedAllowed: true,
+		},
+		{
+			name:           "denied",
+			request:        badRulesNoAnnotations,
+			expectedCauses: 2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			a := api()
+			a.config.AuditLog = &buf
+
+			ts := server(a.servePrometheusRulesValidate)
+			t.Cleanup(ts.Close)
+
+			send(t, ts, tc.request)
+
+			var ev AuditEvent
+			dec := json.NewDecoder(&buf)
+			if err := dec.Decode(&ev); err != nil {
+				t.Fatalf("unable to parse audit event: %v", err)
+			}
+
+			if dec.More() {
+				t.Fatal("expected a single audit event")
+			}
+
+			if ev.UID != "87c5df7f-5090-11e9-b9b4-02425473f309" {
+				t.Fatalf("unexpected UID %q", ev.UID)
+			}
+
+			if ev.Resource != "prometheusrules" || ev.Operation != "CREATE" {
+				t.Fatalf("unexpected resource %q or operation %q", ev.Resource, ev.Operation)
+			}
+
+			if ev.UserInfo.Username != "kubernetes-admin" {
+				t.Fatalf("unexpected user %q", ev.UserInfo.Username)
+			}
+
+			if !reflect.DeepEqual(ev.UserInfo.Groups, []string{"system:masters", "system:authenticated"}) {
+				t.Fatalf("unexpected groups %v", ev.UserInfo.Groups)
+			}
+
+			if ev.Allowed != tc.expectedAllowed {
+				t.Fatalf("expected allowed to be %v, got %v", tc.expectedAllowed, ev.Allowed)
+			}
+
+			if len(ev.Causes) != tc.expectedCauses {
+				t.Fatalf("expected %d causes, got %v", tc.expectedCauses, ev.Causes)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/common/model"
+)
+
+// generateCanonicalizationPatches returns the JSON patch operations
+// rewriting the rule groups into their canonical form:
+// * the groups are sorted by name,
+// * the durations are formatted with the largest units (e.g. 60s becomes 1m),
+// * the trailing whitespace of the expression lines is removed.
+//
+// The groups are sorted in place so that the indices of the patches generated
+// afterwards match the reordered groups.
+func generateCanonicalizationPatches(groups *RuleGroups) []string {
+	var patches []string
+
+	if !sort.SliceIsSorted(groups.Groups, func(i, j int) bool { return groups.Groups[i].Name < groups.Groups[j].Name }) {
+		sort.SliceStable(groups.Groups, func(i, j int) bool { return groups.Groups[i].Name < groups.Groups[j].Name })
+
+		raw := make([]json.RawMessage, 0, len(groups.Groups))
+		for _, g := range groups.Groups {
+			raw = append(raw, g.raw)
+		}
+		patches = append(patches, patchOperation{Op: "replace", Path: "/spec/groups", Value: raw}.String())
+	}
+
+	for gi, g := range groups.Groups {
+		if d, ok := canonicalDuration(g.Interval); ok {
+			patches = append(patches, patchOperation{
+				Op:    "replace",
+				Path:  fmt.Sprintf("/spec/groups/%d/interval", gi),
+				Value: d,
+			}.String())
+		}
+
+		for ri, r := range g.Rules {
+			if d, ok := canonicalDuration(r.For); ok {
+				patches = append(patches, patchOperation{
+					Op:    "replace",
+					Path:  fmt.Sprintf("/spec/groups/%d/rules/%d/for", gi, ri),
+					Value: d,
+				}.String())
+			}
+
+			if expr, ok := r.Expr.(string); ok {
+				if trimmed := trimExpression(expr); trimmed != expr {
+					patches = append(patches, patchOperation{
+						Op:    "replace",
+						Path:  fmt.Sprintf("/spec/groups/%d/rules/%d/expr", gi, ri),
+						Value: trimmed,
+					}.String())
+				}
+			}
+		}
+	}
+
+	return patches
+}
+
+// canonicalDuration returns the canonical form of the duration and whether
+// it differs from the given value. Invalid durations are left to the
+// validation.
+func canonicalDuration(s string) (string, bool) {
+	if s == "" {
+		return "", false
+	}
+
+	d, err := model.ParseDuration(s)
+	if err != nil {
+		return "", false
+	}
+
+	canonical := d.String()
+	return canonical, canonical != s
+}
+
+func trimExpression(expr string) string {
+	lines := strings.Split(expr, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t\r")
+	}
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
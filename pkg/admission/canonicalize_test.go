@@ -0,0 +1,112 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestCanonicalizeRules(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		enabled  bool
+		spec     string
+		expected monitoringv1.PrometheusRuleSpec
+	}{
+		{
+			name: "disabled",
+			spec: `{"groups":[{"name":"b","interval":"60s","rules":[{"alert":"B","expr":"up  ","for":"120s"}]},{"name":"a","rules":[{"record":"a","expr":"vector(1)"}]}]}`,
+			expected: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{
+					{Name: "b", Interval: "60s", Rules: []monitoringv1.Rule{{Alert: "B", Expr: intstr.FromString("up  "), For: "120s"}}},
+					{Name: "a", Rules: []monitoringv1.Rule{{Record: "a", Expr: intstr.FromString("vector(1)")}}},
+				},
+			},
+		},
+		{
+			name:    "canonical",
+			enabled: true,
+			spec:    `{"groups":[{"name":"a","interval":"1m","rules":[{"alert":"A","expr":"up","for":"5m"}]}]}`,
+			expected: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{
+					{Name: "a", Interval: "1m", Rules: []monitoringv1.Rule{{Alert: "A", Expr: intstr.FromString("up"), For: "5m"}}},
+				},
+			},
+		},
+		{
+			name:    "rewritten",
+			enabled: true,
+			spec:    `{"groups":[{"name":"b","interval":"60s","partial_response_strategy":"warn","rules":[{"alert":"B","expr":"sum(up)  \n  by (job) \n","for":"90s","labels":{"severity":1}}]},{"name":"a","rules":[{"record":"a","expr":"vector(1)"}]}]}`,
+			expected: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{
+					{Name: "a", Rules: []monitoringv1.Rule{{Record: "a", Expr: intstr.FromString("vector(1)")}}},
+					{
+						Name:                    "b",
+						Interval:                "1m",
+						PartialResponseStrategy: "warn",
+						Rules: []monitoringv1.Rule{{
+							Alert:  "B",
+							Expr:   intstr.FromString("sum(up)\n  by (job)"),
+							For:    "1m30s",
+							Labels: map[string]string{"severity": "1"},
+						}},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api()
+			a.config.CanonicalizeRules = tc.enabled
+
+			ts := server(a.servePrometheusRulesMutate)
+			t.Cleanup(ts.Close)
+
+			request := buildAdmissionReview(t, ruleResource, "PrometheusRule", v1.Create, reviewObject{spec: tc.spec})
+			resp := send(t, ts, request)
+
+			patch, err := jsonpatch.DecodePatch(resp.Response.Patch)
+			if err != nil {
+				t.Fatalf("Expected a valid patch, got %v", err)
+			}
+
+			rev := v1.AdmissionReview{}
+			if err := json.Unmarshal(request, &rev); err != nil {
+				t.Fatal(err)
+			}
+
+			raw, err := patch.Apply(rev.Request.Object.Raw)
+			if err != nil {
+				t.Fatalf("Expected to successfully apply patch, got %v", err)
+			}
+
+			var rule monitoringv1.PrometheusRule
+			if err := json.Unmarshal(raw, &rule); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tc.expected, rule.Spec) {
+				t.Fatalf("expected spec %+v, got %+v", tc.expected, rule.Spec)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+// Action defines how the admission webhook handles a failed check.
+type Action string
+
+const (
+	// ActionDeny rejects the resource.
+	ActionDeny Action = "deny"
+	// ActionWarn accepts the resource and returns a warning to the client.
+	ActionWarn Action = "warn"
+	// ActionIgnore skips the check.
+	ActionIgnore Action = "ignore"
+)
+
+const (
+	// CheckMissingFor verifies that alerting rules define a 'for' duration.
+	CheckMissingFor = "missing-for"
+	// CheckBroadExpr verifies that the rule expressions don't select series
+	// without specifying a metric name.
+	CheckBroadExpr = "broad-expr"
+	// CheckDuplicateRuleNames verifies that the alerting and recording rule
+	// names aren't already defined by other PrometheusRules selected by the
+	// same Prometheus objects.
+	CheckDuplicateRuleNames = "duplicate-rule-names"
+	// CheckRuleFileSize verifies that the rule file generated from the
+	// PrometheusRule doesn't exceed the configured budget.
+	CheckRuleFileSize = "rule-file-size"
+	// CheckDeprecatedFields verifies that the Prometheus and PodMonitor
+	// objects don't set deprecated fields.
+	CheckDeprecatedFields = "deprecated-fields"
+)
+
+// ruleCheck verifies a PrometheusRule given the rule file generated from it.
+type ruleCheck struct {
+	name  string
+	check func(*Admission, *monitoringv1.PrometheusRule, string) []string
+}
+
+// ruleChecks is the list of the checks applied to PrometheusRules.
+var ruleChecks = []ruleCheck{
+	{name: CheckMissingFor, check: checkMissingFor},
+	{name: CheckBroadExpr, check: checkBroadExpr},
+	{name: CheckDuplicateRuleNames, check: checkDuplicateRuleNames},
+	{name: CheckRuleFileSize, check: checkRuleFileSize},
+}
+
+// RuleChecks maps the check names to the action taken when the check fails.
+// Checks which aren't configured are ignored.
+type RuleChecks map[string]Action
+
+// String implements the flag.Value interface.
+func (rc RuleChecks) String() string {
+	s := make([]string, 0, len(rc))
+	for name, action := range rc {
+		s = append(s, fmt.Sprintf("%s=%s", name, action))
+	}
+	sort.Strings(s)
+
+	return strings.Join(s, ",")
+}
+
+// Set implements the flag.Value interface.
+func (rc RuleChecks) Set(value string) error {
+	if rc == nil {
+		return errors.New("expected rc of type RuleChecks to be initialized")
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		sp := strings.SplitN(pair, "=", 2)
+		if len(sp) != 2 {
+			return errors.Errorf("invalid check %q, expected <check>=<action>", pair)
+		}
+
+		name, action := sp[0], Action(sp[1])
+		if !isCheck(name) {
+			return errors.Errorf("unknown check %q", name)
+		}
+
+		switch action {
+		case ActionDeny, ActionWarn, ActionIgnore:
+		default:
+			return errors.Errorf("invalid action %q for check %q, expected one of %q, %q or %q", action, name, ActionDeny, ActionWarn, ActionIgnore)
+		}
+
+		rc[name] = action
+	}
+
+	return nil
+}
+
+func isCheck(name string) bool {
+	if name == CheckDeprecatedFields {
+		return true
+	}
+
+	for _, c := range ruleChecks {
+		if c.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *Admission) checkEnabled(name string) bool {
+	action, ok := a.config.RuleChecks[name]
+	return ok && action != ActionIgnore
+}
+
+// checkFailures applies the action configured for the check to the failure
+// messages and returns the denials and the warnings.
+func (a *Admission) checkFailures(name string, msgs []string) ([]error, []string) {
+	var (
+		denials  []error
+		warnings []string
+	)
+
+	for _, msg := range msgs {
+		msg = fmt.Sprintf("%s (%s)", msg, name)
+		if a.config.RuleChecks[name] == ActionDeny {
+			denials = append(denials, errors.New(msg))
+			continue
+		}
+		warnings = append(warnings, msg)
+	}
+
+	return denials, warnings
+}
+
+// runRuleChecks executes the configured checks against the PrometheusRule
+// and the rule file generated from it, and returns the denials and the
+// warnings.
+func (a *Admission) runRuleChecks(promRule *monitoringv1.PrometheusRule, content string) ([]error, []string) {
+	var (
+		denials  []error
+		warnings []string
+	)
+
+	for _, c := range ruleChecks {
+		if !a.checkEnabled(c.name) {
+			continue
+		}
+
+		d, w := a.checkFailures(c.name, c.check(a, promRule, content))
+		denials = append(denials, d...)
+		warnings = append(warnings, w...)
+	}
+
+	return denials, warnings
+}
+
+// runDeprecatedFieldsCheck executes the deprecated-fields check given the
+// deprecated fields set in the object and returns the denials and the
+// warnings.
+func (a *Admission) runDeprecatedFieldsCheck(msgs []string) ([]error, []string) {
+	if !a.checkEnabled(CheckDeprecatedFields) {
+		return nil, nil
+	}
+
+	return a.checkFailures(CheckDeprecatedFields, msgs)
+}
+
+// deprecatedPrometheusFields returns a message for every deprecated field set
+// in the Prometheus object.
+func deprecatedPrometheusFields(p *monitoringv1.Prometheus) []string {
+	var msgs []string
+	if p.Spec.BaseImage != "" {
+		msgs = append(msgs, "field 'baseImage' is deprecated, use 'image' instead")
+	}
+
+	if p.Spec.Tag != "" {
+		msgs = append(msgs, "field 'tag' is deprecated, use 'image' instead")
+	}
+
+	if p.Spec.SHA != "" {
+		msgs = append(msgs, "field 'sha' is deprecated, use 'image' instead")
+	}
+
+	if p.Spec.Storage != nil && p.Spec.Storage.DisableMountSubPath {
+		msgs = append(msgs, "field 'storage.disableMountSubPath' is deprecated, the subPath usage will be disabled by default in a future release")
+	}
+
+	return msgs
+}
+
+// deprecatedPodMonitorFields returns a message for every deprecated field set
+// in the PodMonitor object.
+func deprecatedPodMonitorFields(pm *monitoringv1.PodMonitor) []string {
+	var msgs []string
+	for i, ep := range pm.Spec.PodMetricsEndpoints {
+		if ep.TargetPort != nil {
+			msgs = append(msgs, fmt.Sprintf("field 'podMetricsEndpoints[%d].targetPort' is deprecated, use 'port' instead", i))
+		}
+	}
+
+	return msgs
+}
+
+func ruleName(r monitoringv1.Rule) string {
+	if r.Alert != "" {
+		return r.Alert
+	}
+
+	return r.Record
+}
+
+func checkMissingFor(_ *Admission, promRule *monitoringv1.PrometheusRule, _ string) []string {
+	var msgs []string
+	for _, g := range promRule.Spec.Groups {
+		for _, r := range g.Rules {
+			if r.Alert == "" || r.For != "" {
+				continue
+			}
+
+			msgs = append(msgs, fmt.Sprintf("group %q, rule %q: alerting rule has no 'for' duration", g.Name, r.Alert))
+		}
+	}
+
+	return msgs
+}
+
+func checkBroadExpr(_ *Admission, promRule *monitoringv1.PrometheusRule, _ string) []string {
+	var msgs []string
+	for _, g := range promRule.Spec.Groups {
+		for _, r := range g.Rules {
+			expr, err := parser.ParseExpr(r.Expr.String())
+			if err != nil {
+				// Invalid expressions are reported by the rule validation.
+				continue
+			}
+
+			var selectors []string
+			parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+				vs, ok := node.(*parser.VectorSelector)
+				if !ok || hasMetricName(vs) {
+					return nil
+				}
+
+				selectors = append(selectors, vs.String())
+				return nil
+			})
+
+			for _, s := range selectors {
+				msgs = append(msgs, fmt.Sprintf("group %q, rule %q: selector %s doesn't specify a metric name", g.Name, ruleName(r), s))
+			}
+		}
+	}
+
+	return msgs
+}
+
+func hasMetricName(vs *parser.VectorSelector) bool {
+	if vs.Name != "" {
+		return true
+	}
+
+	for _, m := range vs.LabelMatchers {
+		if m.Name == labels.MetricName && m.Type == labels.MatchEqual {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkDuplicateRuleNames(a *Admission, promRule *monitoringv1.PrometheusRule, _ string) []string {
+	if a.config.RuleLister == nil {
+		return nil
+	}
+
+	selected, err := a.config.RuleLister.ListSelectedRules(promRule)
+	if err != nil {
+		level.Warn(a.logger).Log("msg", "Failed to list the selected PrometheusRules", "err", err)
+		return nil
+	}
+
+	names := map[string]struct{}{}
+	for _, g := range promRule.Spec.Groups {
+		for _, r := range g.Rules {
+			names[ruleName(r)] = struct{}{}
+		}
+	}
+
+	// Sort the Prometheus keys to return stable results.
+	keys := make([]string, 0, len(selected))
+	for k := range selected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var msgs []string
+	for _, k := range keys {
+		for _, other := range selected[k] {
+			duplicates := map[string]struct{}{}
+			for _, g := range other.Spec.Groups {
+				for _, r := range g.Rules {
+					n := ruleName(r)
+					if _, found := names[n]; found {
+						duplicates[n] = struct{}{}
+					}
+				}
+			}
+
+			dups := make([]string, 0, len(duplicates))
+			for n := range duplicates {
+				dups = append(dups, n)
+			}
+			sort.Strings(dups)
+
+			for _, n := range dups {
+				msgs = append(msgs, fmt.Sprintf("rule %q is already defined by PrometheusRule %s/%s, selected by Prometheus %s", n, other.Namespace, other.Name, k))
+			}
+		}
+	}
+
+	return msgs
+}
+
+func checkRuleFileSize(a *Admission, _ *monitoringv1.PrometheusRule, content string) []string {
+	if a.config.RuleFileSizeBudget <= 0 {
+		return nil
+	}
+
+	if len(content) <= a.config.RuleFileSizeBudget {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("the generated rule file is %d bytes which exceeds the budget of %d bytes", len(content), a.config.RuleFileSizeBudget)}
+}
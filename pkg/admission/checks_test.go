@@ -0,0 +1,310 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestRuleChecksSet(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		value    string
+		expected RuleChecks
+		ok       bool
+	}{
+		{
+			name:  "valid",
+			value: "missing-for=warn,broad-expr=deny",
+			expected: RuleChecks{
+				CheckMissingFor: ActionWarn,
+				CheckBroadExpr:  ActionDeny,
+			},
+			ok: true,
+		},
+		{
+			name:  "deprecated fields",
+			value: "deprecated-fields=warn",
+			expected: RuleChecks{
+				CheckDeprecatedFields: ActionWarn,
+			},
+			ok: true,
+		},
+		{
+			name:  "unknown check",
+			value: "foo=warn",
+		},
+		{
+			name:  "unknown action",
+			value: "missing-for=block",
+		},
+		{
+			name:  "missing action",
+			value: "missing-for",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := RuleChecks{}
+			err := rc.Set(tc.value)
+
+			if !tc.ok {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.expected, rc) {
+				t.Fatalf("expected %v, got %v", tc.expected, rc)
+			}
+		})
+	}
+}
+
+func TestRuleChecksRun(t *testing.T) {
+	spec := monitoringv1.PrometheusRuleSpec{
+		Groups: []monitoringv1.RuleGroup{
+			{
+				Name: "test.rules",
+				Rules: []monitoringv1.Rule{
+					{
+						Alert: "NoFor",
+						Expr:  intstr.FromString(`up == 0`),
+					},
+					{
+						Alert: "Broad",
+						Expr:  intstr.FromString(`count({job="node"}) > 1000`),
+						For:   "5m",
+					},
+					{
+						Record: "job:requests:rate5m",
+						Expr:   intstr.FromString(`sum by (job) (rate({__name__="http_requests_total"}[5m]))`),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		checks   RuleChecks
+		denials  int
+		warnings int
+	}{
+		{
+			name: "no checks",
+		},
+		{
+			name: "ignore",
+			checks: RuleChecks{
+				CheckMissingFor: ActionIgnore,
+				CheckBroadExpr:  ActionIgnore,
+			},
+		},
+		{
+			name: "warn",
+			checks: RuleChecks{
+				CheckMissingFor: ActionWarn,
+				CheckBroadExpr:  ActionWarn,
+			},
+			warnings: 2,
+		},
+		{
+			name: "deny and warn",
+			checks: RuleChecks{
+				CheckMissingFor: ActionWarn,
+				CheckBroadExpr:  ActionDeny,
+			},
+			denials:  1,
+			warnings: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api()
+			a.config.RuleChecks = tc.checks
+			denials, warnings := a.runRuleChecks(&monitoringv1.PrometheusRule{Spec: spec}, "")
+
+			if len(denials) != tc.denials {
+				t.Fatalf("expected %d denials, got %d: %v", tc.denials, len(denials), denials)
+			}
+
+			if len(warnings) != tc.warnings {
+				t.Fatalf("expected %d warnings, got %d: %v", tc.warnings, len(warnings), warnings)
+			}
+		})
+	}
+}
+
+func TestDeprecatedFieldsCheck(t *testing.T) {
+	targetPort := intstr.FromInt(8080)
+	p := &monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			BaseImage: "quay.io/prometheus/prometheus",
+			Tag:       "v2.30.0",
+			Storage:   &monitoringv1.StorageSpec{DisableMountSubPath: true},
+		},
+	}
+	pm := &monitoringv1.PodMonitor{
+		Spec: monitoringv1.PodMonitorSpec{
+			PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{
+				{Port: "web"},
+				{TargetPort: &targetPort},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		checks   RuleChecks
+		msgs     []string
+		denials  int
+		warnings int
+	}{
+		{
+			name: "no checks",
+			msgs: deprecatedPrometheusFields(p),
+		},
+		{
+			name:   "ignore",
+			checks: RuleChecks{CheckDeprecatedFields: ActionIgnore},
+			msgs:   deprecatedPrometheusFields(p),
+		},
+		{
+			name:     "warn",
+			checks:   RuleChecks{CheckDeprecatedFields: ActionWarn},
+			msgs:     deprecatedPrometheusFields(p),
+			warnings: 3,
+		},
+		{
+			name:    "deny",
+			checks:  RuleChecks{CheckDeprecatedFields: ActionDeny},
+			msgs:    deprecatedPodMonitorFields(pm),
+			denials: 1,
+		},
+		{
+			name:   "no deprecated field",
+			checks: RuleChecks{CheckDeprecatedFields: ActionDeny},
+			msgs:   deprecatedPrometheusFields(&monitoringv1.Prometheus{}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api()
+			a.config.RuleChecks = tc.checks
+			denials, warnings := a.runDeprecatedFieldsCheck(tc.msgs)
+
+			if len(denials) != tc.denials {
+				t.Fatalf("expected %d denials, got %d: %v", tc.denials, len(denials), denials)
+			}
+
+			if len(warnings) != tc.warnings {
+				t.Fatalf("expected %d warnings, got %d: %v", tc.warnings, len(warnings), warnings)
+			}
+		})
+	}
+
+	expected := "field 'podMetricsEndpoints[1].targetPort' is deprecated, use 'port' instead"
+	if msgs := deprecatedPodMonitorFields(pm); !reflect.DeepEqual(msgs, []string{expected}) {
+		t.Fatalf("expected %q, got %v", expected, msgs)
+	}
+}
+
+type fakeRuleLister map[string][]*monitoringv1.PrometheusRule
+
+func (f fakeRuleLister) ListSelectedRules(*monitoringv1.PrometheusRule) (map[string][]*monitoringv1.PrometheusRule, error) {
+	return f, nil
+}
+
+func TestDuplicateRuleNames(t *testing.T) {
+	newRule := func(name string, rules ...monitoringv1.Rule) *monitoringv1.PrometheusRule {
+		return &monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{
+					{
+						Name:  "group",
+						Rules: rules,
+					},
+				},
+			},
+		}
+	}
+
+	promRule := newRule(
+		"test",
+		monitoringv1.Rule{Alert: "InstanceDown", Expr: intstr.FromString("up == 0")},
+		monitoringv1.Rule{Record: "job:up:sum", Expr: intstr.FromString("sum by (job) (up)")},
+	)
+
+	for _, tc := range []struct {
+		name     string
+		lister   RuleLister
+		expected []string
+	}{
+		{
+			name: "no lister",
+		},
+		{
+			name: "no duplicates",
+			lister: fakeRuleLister{
+				"default/k8s": {
+					newRule("other", monitoringv1.Rule{Alert: "TargetDown", Expr: intstr.FromString("up == 0")}),
+				},
+			},
+		},
+		{
+			name: "duplicates",
+			lister: fakeRuleLister{
+				"default/k8s": {
+					newRule("other",
+						monitoringv1.Rule{Alert: "InstanceDown", Expr: intstr.FromString("up == 0")},
+						monitoringv1.Rule{Alert: "InstanceDown", Expr: intstr.FromString("up == 0")},
+						monitoringv1.Rule{Record: "job:up:sum", Expr: intstr.FromString("sum by (job) (up)")},
+					),
+				},
+				"monitoring/main": {
+					newRule("another", monitoringv1.Rule{Alert: "InstanceDown", Expr: intstr.FromString("up == 0")}),
+				},
+			},
+			expected: []string{
+				`rule "InstanceDown" is already defined by PrometheusRule default/other, selected by Prometheus default/k8s`,
+				`rule "job:up:sum" is already defined by PrometheusRule default/other, selected by Prometheus default/k8s`,
+				`rule "InstanceDown" is already defined by PrometheusRule default/another, selected by Prometheus monitoring/main`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api()
+			a.config.RuleLister = tc.lister
+
+			msgs := checkDuplicateRuleNames(a, promRule, "")
+			if !reflect.DeepEqual(tc.expected, msgs) {
+				t.Fatalf("expected %v, got %v", tc.expected, msgs)
+			}
+		})
+	}
+}
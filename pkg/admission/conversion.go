@@ -0,0 +1,145 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	monitoringv1beta1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1beta1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const conversionPath = "/convert"
+
+// serveConvert handles the ConversionReview requests sent by the Kubernetes
+// API server to convert custom resources between API versions.
+func (a *Admission) serveConvert(w http.ResponseWriter, r *http.Request) {
+	buf := a.readRequestBody(w, r)
+	if buf == nil {
+		return
+	}
+	defer putBuffer(buf)
+
+	level.Debug(a.logger).Log("msg", "Received conversion request", "content", lazyString(buf.Bytes()))
+
+	review := apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(buf.Bytes(), &review); err != nil {
+		level.Warn(a.logger).Log("msg", "Unable to deserialize conversion request", "err", err)
+		http.Error(w, fmt.Sprintf("unable to deserialize request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if review.Request == nil {
+		level.Warn(a.logger).Log("msg", "conversion review has no request")
+		http.Error(w, "conversion review has no request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = a.convertObjects(review.Request)
+	review.Request = nil
+
+	respBytes, err := json.Marshal(review)
+	if err != nil {
+		level.Error(a.logger).Log("msg", "Cannot serialize response", "err", err)
+		http.Error(w, fmt.Sprintf("could not serialize response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	level.Debug(a.logger).Log("msg", "sending conversion response", "content", lazyString(respBytes))
+
+	a.writeResponse(w, r, respBytes)
+}
+
+func (a *Admission) convertObjects(req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	resp := &apiextensionsv1.ConversionResponse{UID: req.UID}
+
+	for i, obj := range req.Objects {
+		converted, err := convertObject(obj.Raw, req.DesiredAPIVersion)
+		if err != nil {
+			level.Info(a.logger).Log("msg", "Conversion failed", "desiredAPIVersion", req.DesiredAPIVersion, "err", err)
+			resp.Result = metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: fmt.Sprintf("object[%d]: %v", i, err),
+			}
+			return resp
+		}
+
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+
+	resp.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return resp
+}
+
+// convertObject converts the serialized object to the desired API version.
+// Only AlertmanagerConfig objects are supported for now.
+func convertObject(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	var tm metav1.TypeMeta
+	if err := json.Unmarshal(raw, &tm); err != nil {
+		return nil, errors.Wrap(err, "failed to decode object")
+	}
+
+	if tm.Kind != monitoringv1alpha1.AlertmanagerConfigKind {
+		return nil, errors.Errorf("unsupported kind %q", tm.Kind)
+	}
+
+	if tm.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+
+	var (
+		v1alpha1 = monitoringv1alpha1.SchemeGroupVersion.String()
+		v1beta1  = monitoringv1beta1.SchemeGroupVersion.String()
+		out      interface{}
+	)
+
+	switch {
+	case tm.APIVersion == v1alpha1 && desiredAPIVersion == v1beta1:
+		src := &monitoringv1alpha1.AlertmanagerConfig{}
+		if err := json.Unmarshal(raw, src); err != nil {
+			return nil, errors.Wrap(err, "failed to decode object")
+		}
+
+		dst := &monitoringv1beta1.AlertmanagerConfig{}
+		if err := dst.ConvertFrom(src); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %s/%s", src.Namespace, src.Name)
+		}
+		out = dst
+
+	case tm.APIVersion == v1beta1 && desiredAPIVersion == v1alpha1:
+		src := &monitoringv1beta1.AlertmanagerConfig{}
+		if err := json.Unmarshal(raw, src); err != nil {
+			return nil, errors.Wrap(err, "failed to decode object")
+		}
+
+		dst := &monitoringv1alpha1.AlertmanagerConfig{}
+		if err := src.ConvertTo(dst); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %s/%s", src.Namespace, src.Name)
+		}
+		out = dst
+
+	default:
+		return nil, errors.Errorf("unsupported conversion from %q to %q", tm.APIVersion, desiredAPIVersion)
+	}
+
+	return json.Marshal(out)
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+	v1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// protectedAnnotation marks the resources which can't be deleted unless
+	// the deletion is confirmed.
+	protectedAnnotation = "monitoring.coreos.com/protected"
+	// confirmDeletionAnnotation confirms the deletion of a protected resource.
+	confirmDeletionAnnotation = "monitoring.coreos.com/confirm-deletion"
+)
+
+// validateDeletion denies the deletion of protected resources which don't
+// carry the deletion confirmation annotation.
+func (a *Admission) validateDeletion(ar v1.AdmissionReview, resource string) *v1.AdmissionResponse {
+	obj := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(ar.Request.OldObject.Raw, obj); err != nil {
+		level.Info(a.logger).Log("msg", errUnmarshalAdmission, "err", err)
+		return toAdmissionResponseFailure(errUnmarshalAdmission, resource, []error{err})
+	}
+
+	if obj.Annotations[protectedAnnotation] != "true" || obj.Annotations[confirmDeletionAnnotation] == "true" {
+		return &v1.AdmissionResponse{Allowed: true}
+	}
+
+	err := fmt.Errorf(
+		"%s/%s is protected by the %q annotation, set the %q annotation to \"true\" to confirm the deletion",
+		obj.Namespace, obj.Name, protectedAnnotation, confirmDeletionAnnotation,
+	)
+	level.Info(a.logger).Log("msg", "Deletion of protected resource denied", "err", err)
+
+	r := toAdmissionResponseFailure("Resource is protected against deletion", resource, []error{err})
+	r.Result.Reason = metav1.StatusReasonForbidden
+	r.Result.Code = http.StatusForbidden
+	return r
+}
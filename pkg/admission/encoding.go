@@ -0,0 +1,140 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+)
+
+// maxPooledBufferSize is the capacity above which the buffers aren't returned
+// to the pool to avoid retaining the memory used by exceptionally large
+// requests.
+const maxPooledBufferSize = 2 << 20
+
+var (
+	errUnsupportedEncoding = errors.New("unsupported Content-Encoding, want `gzip` or none")
+
+	bufferPool = sync.Pool{
+		New: func() interface{} { return new(bytes.Buffer) },
+	}
+)
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// lazyString defers the conversion of the bytes to a string until the value
+// is logged.
+type lazyString []byte
+
+func (l lazyString) String() string {
+	return string(l)
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// decodeBody reads the request body into the buffer according to its content
+// encoding. The decompressed body is read up to limit+1 bytes so that the
+// caller can detect bodies exceeding the limit.
+func decodeBody(buf *bytes.Buffer, r io.Reader, encoding string, limit int64) error {
+	switch encoding {
+	case "", "identity":
+		_, err := buf.ReadFrom(r)
+		return err
+	case "gzip":
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return errors.Wrap(err, "invalid gzip body")
+		}
+		defer gz.Close()
+
+		if _, err := buf.ReadFrom(io.LimitReader(gz, limit+1)); err != nil {
+			return errors.Wrap(err, "invalid gzip body")
+		}
+		return nil
+	default:
+		return errUnsupportedEncoding
+	}
+}
+
+// acceptsGzip returns true if the client accepts gzip-encoded responses.
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		parts := strings.Split(enc, ";")
+		if strings.TrimSpace(parts[0]) != "gzip" {
+			continue
+		}
+
+		for _, p := range parts[1:] {
+			if strings.ReplaceAll(p, " ", "") == "q=0" {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// writeResponse writes the response body, compressed with gzip if the client
+// accepts it.
+func (a *Admission) writeResponse(w http.ResponseWriter, r *http.Request, body []byte) {
+	if !acceptsGzip(r) {
+		if _, err := w.Write(body); err != nil {
+			level.Error(a.logger).Log("msg", "Cannot write response", "err", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Add("Vary", "Accept-Encoding")
+
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(body); err != nil {
+		level.Error(a.logger).Log("msg", "Cannot write response", "err", err)
+		return
+	}
+
+	if err := gz.Close(); err != nil {
+		level.Error(a.logger).Log("msg", "Cannot write response", "err", err)
+	}
+}
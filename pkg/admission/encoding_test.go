@@ -0,0 +1,150 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	v1 "k8s.io/api/admission/v1"
+)
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestGzipEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		body             []byte
+		contentEncoding  string
+		acceptEncoding   string
+		limit            int
+		expectedStatus   int
+		expectedEncoding string
+	}{
+		{
+			name:           "plain request and response",
+			body:           goodRulesWithAnnotations,
+			acceptEncoding: "identity",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:             "gzip request and response",
+			body:             gzipBytes(t, goodRulesWithAnnotations),
+			contentEncoding:  "gzip",
+			acceptEncoding:   "gzip",
+			expectedStatus:   http.StatusOK,
+			expectedEncoding: "gzip",
+		},
+		{
+			name:             "gzip response only",
+			body:             goodRulesWithAnnotations,
+			acceptEncoding:   "deflate, gzip;q=0.5",
+			expectedStatus:   http.StatusOK,
+			expectedEncoding: "gzip",
+		},
+		{
+			name:            "invalid gzip body",
+			body:            goodRulesWithAnnotations,
+			contentEncoding: "gzip",
+			acceptEncoding:  "identity",
+			expectedStatus:  http.StatusBadRequest,
+		},
+		{
+			name:            "unsupported encoding",
+			body:            goodRulesWithAnnotations,
+			contentEncoding: "br",
+			acceptEncoding:  "identity",
+			expectedStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:            "decompressed body too large",
+			body:            gzipBytes(t, goodRulesWithAnnotations),
+			contentEncoding: "gzip",
+			acceptEncoding:  "identity",
+			limit:           len(goodRulesWithAnnotations) - 1,
+			expectedStatus:  http.StatusRequestEntityTooLarge,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api()
+			a.config.MaxRequestBodySize = tc.limit
+			ts := server(a.servePrometheusRulesValidate)
+			t.Cleanup(ts.Close)
+
+			req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewReader(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			if tc.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", tc.contentEncoding)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if enc := resp.Header.Get("Content-Encoding"); enc != tc.expectedEncoding {
+				t.Fatalf("expected Content-Encoding %q, got %q", tc.expectedEncoding, enc)
+			}
+
+			var body io.Reader = resp.Body
+			if tc.expectedEncoding == "gzip" {
+				gz, err := gzip.NewReader(resp.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = gz
+			}
+
+			rev := &v1.AdmissionReview{}
+			if err := json.NewDecoder(body).Decode(rev); err != nil {
+				t.Fatalf("unable to parse webhook response: %s", err)
+			}
+
+			if !rev.Response.Allowed {
+				t.Fatalf("expected admission to be allowed but it was not: %v", rev.Response.Result)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"flag"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+)
+
+// tracerShutdownTimeout is the maximum time spent flushing the pending spans
+// on shutdown.
+const tracerShutdownTimeout = 5 * time.Second
+
+// Flags holds the command-line flags of the admission webhook shared by the
+// operator and the standalone admission-webhook binary.
+type Flags struct {
+	config Config
+
+	requiredRuleLabels      string
+	requiredRuleAnnotations string
+	promQLFeatures          string
+	opaURL                  string
+	auditLog                string
+	tracingEndpoint         string
+	tracingSamplingRatio    float64
+}
+
+// NewFlags returns the flags of the admission webhook. When standalone is
+// true, the flags depending on the resources watched by the operator (e.g.
+// --admission.max-rules-per-namespace) aren't registered.
+func NewFlags(fs *flag.FlagSet, standalone bool) *Flags {
+	f := &Flags{
+		config: Config{
+			RuleChecks: RuleChecks{},
+		},
+	}
+
+	checks := "missing-for, broad-expr, duplicate-rule-names, rule-file-size, deprecated-fields"
+	if standalone {
+		checks = "missing-for, broad-expr, rule-file-size, deprecated-fields"
+	}
+
+	fs.Var(f.config.RuleChecks, "admission.rule-checks", "Comma-separated list of <check>=<action> pairs defining how the admission webhook handles the resources failing additional checks, e.g. 'missing-for=warn,broad-expr=deny'. Possible checks: "+checks+". Possible actions: deny, warn, ignore. Checks which aren't listed are ignored.")
+	fs.IntVar(&f.config.RuleFileSizeBudget, "admission.rule-file-size-budget", 0, "Maximum size in bytes of the rule file generated from a PrometheusRule, enforced by the rule-file-size check of the admission webhook. Rule files exceeding the ConfigMap size limit are always rejected.")
+	fs.StringVar(&f.requiredRuleLabels, "admission.required-rule-labels", "", "Comma-separated list of labels that every alerting rule must define to be accepted by the admission webhook.")
+	fs.StringVar(&f.requiredRuleAnnotations, "admission.required-rule-annotations", "", "Comma-separated list of annotations that every alerting rule must define to be accepted by the admission webhook.")
+	fs.Var(&f.config.MetricNamePolicy.Allowed, "admission.allowed-metric-names", "Regular expression matching the metric names which can be queried by the PrometheusRules accepted by the admission webhook. Can be repeated. If set, the selectors without a metric name are rejected. All the metric names are allowed if empty.")
+	fs.Var(&f.config.MetricNamePolicy.Denied, "admission.denied-metric-names", "Regular expression matching the metric names which can't be queried by the PrometheusRules accepted by the admission webhook. Can be repeated. Takes precedence over --admission.allowed-metric-names.")
+	fs.IntVar(&f.config.RuleQuota.MaxGroups, "admission.max-rule-groups", 0, "Maximum number of groups per PrometheusRule accepted by the admission webhook. 0 means no limit.")
+	fs.IntVar(&f.config.RuleQuota.MaxRulesPerGroup, "admission.max-rules-per-group", 0, "Maximum number of rules per group accepted by the admission webhook. 0 means no limit.")
+	if !standalone {
+		fs.IntVar(&f.config.RuleQuota.MaxRulesPerNamespace, "admission.max-rules-per-namespace", 0, "Maximum number of rules defined by all the PrometheusRules of a namespace accepted by the admission webhook. 0 means no limit.")
+	}
+	fs.StringVar(&f.config.PromQLCompatibility.Version, "admission.promql-version", "", "Version of the Prometheus servers loading the PrometheusRules. The admission webhook rejects the expressions using PromQL features unavailable in this version. All the features are accepted if empty.")
+	fs.StringVar(&f.promQLFeatures, "admission.promql-enable-features", "", "Comma-separated list of the feature flags enabled on the Prometheus servers loading the PrometheusRules. Possible values: promql-at-modifier, promql-negative-offset.")
+	fs.StringVar(&f.opaURL, "admission.opa-url", "", "URL of the Open Policy Agent document evaluated by the admission webhook against the PrometheusRules (e.g. 'http://opa:8181/v1/data/prometheusrules/deny'). The document must return the list of policy violations.")
+	fs.StringVar(&f.config.MutationAnnotation, "admission.mutation-annotation", DefaultMutationAnnotation, "Annotation added by the mutating admission webhook to the validated PrometheusRules.")
+	fs.StringVar(&f.config.MutationAnnotationValue, "admission.mutation-annotation-value", DefaultMutationAnnotationValue, "Value of the annotation added by the mutating admission webhook to the validated PrometheusRules.")
+	fs.IntVar(&f.config.MaxConcurrentRequests, "admission.max-concurrent-requests", 0, "Maximum number of requests processed concurrently by the admission webhook. Requests exceeding the limit are rejected with a 429 status. 0 means no limit.")
+	fs.Float64Var(&f.config.ClientRateLimit, "admission.client-rate-limit", 0, "Maximum number of requests per second accepted by the admission webhook from a single client. Requests exceeding the limit are rejected with a 429 status. 0 means no limit.")
+	fs.IntVar(&f.config.ClientRateBurst, "admission.client-rate-burst", 10, "Maximum burst of requests accepted by the admission webhook from a single client when the rate limit is enabled.")
+	fs.IntVar(&f.config.MaxRequestBodySize, "admission.max-request-body-size", 0, "Maximum size in bytes of the requests accepted by the admission and conversion webhooks. Larger requests are rejected with a 413 status. 0 means the default limit of 6MiB.")
+	fs.Var(&f.config.MutationPatches, "admission.mutation-patches", "JSON array of additional patch operations (RFC 6902) applied by the mutating admission webhook to the validated PrometheusRules. Can be repeated.")
+	fs.BoolVar(&f.config.CanonicalizeRules, "admission.canonicalize-rules", false, "Rewrite the PrometheusRules mutated by the admission webhook into a canonical form: groups sorted by name, normalized durations (e.g. '60s' becomes '1m') and expressions without trailing whitespace.")
+	fs.StringVar(&f.config.EnforcedNamespaceLabel, "admission.enforced-namespace-label", "", "Label enforced by the mutating admission webhook on the PrometheusRules with the namespace of the object. The label is added to the rule labels and injected as a matcher into the rule expressions, like the enforcedNamespaceLabel field of the Prometheus resource. Disabled if empty.")
+	fs.StringVar(&f.auditLog, "admission.audit-log", "", "Path of the file receiving a JSON line for every decision of the admission webhook, or '-' for the standard output. The decisions aren't recorded if empty.")
+	fs.StringVar(&f.tracingEndpoint, "tracing.endpoint", "", "OTLP/HTTP endpoint of the collector receiving the traces of the admission webhook requests (e.g. 'http://otel-collector:4318/v1/traces'). Tracing is disabled if empty.")
+	fs.Float64Var(&f.tracingSamplingRatio, "tracing.sampling-ratio", 1, "Ratio of the admission webhook requests which are traced, between 0 and 1.")
+
+	return f
+}
+
+// Config returns the configuration of the admission webhook built from the
+// parsed flags. The returned function releases the audit log and flushes the
+// pending spans, it must be called on shutdown.
+func (f *Flags) Config(ctx context.Context, serviceName string, logger log.Logger) (Config, func(), error) {
+	cfg := f.config
+	var closers []func()
+	closeAll := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+
+	if f.requiredRuleLabels != "" {
+		cfg.RulePolicy.RequiredLabels = strings.Split(f.requiredRuleLabels, ",")
+	}
+	if f.requiredRuleAnnotations != "" {
+		cfg.RulePolicy.RequiredAnnotations = strings.Split(f.requiredRuleAnnotations, ",")
+	}
+	if f.promQLFeatures != "" {
+		cfg.PromQLCompatibility.EnabledFeatures = strings.Split(f.promQLFeatures, ",")
+	}
+	if err := cfg.PromQLCompatibility.Validate(); err != nil {
+		return Config{}, nil, errors.Wrap(err, "invalid PromQL compatibility")
+	}
+	if f.opaURL != "" {
+		cfg.PolicyEvaluators = append(cfg.PolicyEvaluators, NewOPAEvaluator(f.opaURL))
+	}
+
+	if f.tracingEndpoint != "" {
+		tp, err := NewTracerProvider(ctx, serviceName, f.tracingEndpoint, f.tracingSamplingRatio)
+		if err != nil {
+			return Config{}, nil, errors.Wrap(err, "instantiating tracer failed")
+		}
+		closers = append(closers, func() {
+			ctx, cancel := context.WithTimeout(context.Background(), tracerShutdownTimeout)
+			defer cancel()
+			if err := tp.Shutdown(ctx); err != nil {
+				level.Warn(logger).Log("msg", "failed to flush the traces", "err", err)
+			}
+		})
+		cfg.TracerProvider = tp
+	}
+
+	switch f.auditLog {
+	case "":
+	case "-":
+		cfg.AuditLog = os.Stdout
+	default:
+		file, err := os.OpenFile(f.auditLog, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			closeAll()
+			return Config{}, nil, errors.Wrap(err, "opening admission audit log failed")
+		}
+		closers = append(closers, func() { file.Close() })
+		cfg.AuditLog = file
+	}
+
+	return cfg, closeAll, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/go-kit/log"
+)
+
+func TestFlags(t *testing.T) {
+	for _, tc := range []struct {
+		standalone bool
+		args       []string
+		expectErr  bool
+	}{
+		{
+			args: []string{
+				"--admission.required-rule-labels=severity,team",
+				"--admission.promql-version=2.25.0",
+				"--admission.promql-enable-features=promql-at-modifier",
+				"--admission.max-rules-per-namespace=10",
+				"--tracing.sampling-ratio=0.5",
+			},
+		},
+		{
+			standalone: true,
+			args: []string{
+				"--admission.required-rule-labels=severity,team",
+				"--admission.promql-version=2.25.0",
+				"--admission.promql-enable-features=promql-at-modifier",
+				"--tracing.sampling-ratio=0.5",
+			},
+		},
+		{
+			// The standalone webhook doesn't watch the PrometheusRules.
+			standalone: true,
+			args:       []string{"--admission.max-rules-per-namespace=10"},
+			expectErr:  true,
+		},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		f := NewFlags(fs, tc.standalone)
+
+		err := fs.Parse(tc.args)
+		if tc.expectErr {
+			if err == nil {
+				t.Fatalf("expected an error parsing %v", tc.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, closeFn, err := f.Config(context.Background(), "test", log.NewNopLogger())
+		if err != nil {
+			t.Fatal(err)
+		}
+		closeFn()
+
+		if !reflect.DeepEqual(cfg.RulePolicy.RequiredLabels, []string{"severity", "team"}) {
+			t.Fatalf("unexpected required labels %v", cfg.RulePolicy.RequiredLabels)
+		}
+
+		if !reflect.DeepEqual(cfg.PromQLCompatibility.EnabledFeatures, []string{"promql-at-modifier"}) {
+			t.Fatalf("unexpected PromQL features %v", cfg.PromQLCompatibility.EnabledFeatures)
+		}
+
+		if cfg.TracerProvider != nil {
+			t.Fatal("expected tracing to be disabled without endpoint")
+		}
+	}
+}
+
+func TestFlagsInvalidPromQLFeature(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	f := NewFlags(fs, false)
+
+	if err := fs.Parse([]string{"--admission.promql-version=2.25.0", "--admission.promql-enable-features=unknown"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := f.Config(context.Background(), "test", log.NewNopLogger()); err == nil {
+		t.Fatal("expected an error, got none")
+	}
+}
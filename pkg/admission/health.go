@@ -0,0 +1,97 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+)
+
+const (
+	healthzPath = "/healthz"
+	readyzPath  = "/readyz"
+)
+
+// readiness reports whether the webhook accepts new requests. The zero value
+// is ready.
+type readiness struct {
+	mtx      sync.Mutex
+	notReady bool
+}
+
+func (r *readiness) setNotReady() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.notReady = true
+}
+
+func (r *readiness) isReady() bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return !r.notReady
+}
+
+// RegisterHealth registers the liveness (/healthz) and readiness (/readyz)
+// endpoints of the webhook server. It is only needed when the webhook has a
+// dedicated server: the operator already serves /healthz.
+func (a *Admission) RegisterHealth(mux *http.ServeMux) {
+	mux.HandleFunc(healthzPath, a.serveHealthz)
+	mux.HandleFunc(readyzPath, a.serveReadyz)
+}
+
+// serveHealthz reports that the webhook server is alive.
+func (a *Admission) serveHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "OK")
+}
+
+// serveReadyz reports whether the webhook accepts new requests. It fails once
+// the shutdown has started so that the endpoint is removed from the Service
+// before the server stops.
+func (a *Admission) serveReadyz(w http.ResponseWriter, r *http.Request) {
+	if !a.readiness.isReady() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "OK")
+}
+
+// Shutdown marks the webhook as not ready and waits for the grace period so
+// that the endpoint is removed from the Service before the caller stops the
+// server. The admission requests received in the meantime are still
+// processed. It returns early if the context is done.
+func (a *Admission) Shutdown(ctx context.Context, gracePeriod time.Duration) error {
+	a.readiness.setNotReady()
+	level.Info(a.logger).Log("msg", "Admission webhook marked as not ready, waiting before stopping the server", "grace_period", gracePeriod)
+
+	t := time.NewTimer(gracePeriod)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRegisterDoesNotServeHealth(t *testing.T) {
+	a := api()
+	mux := http.NewServeMux()
+
+	// The operator registers its own /healthz handler on the same mux.
+	mux.HandleFunc(healthzPath, func(w http.ResponseWriter, r *http.Request) {})
+	a.Register(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, readyzPath, nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d for %s, got %d", http.StatusNotFound, readyzPath, w.Code)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	a := api()
+	mux := http.NewServeMux()
+	a.Register(mux)
+	a.RegisterHealth(mux)
+
+	status := func(path string) int {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	for _, path := range []string{healthzPath, readyzPath} {
+		if code := status(path); code != http.StatusOK {
+			t.Fatalf("expected status %d for %s, got %d", http.StatusOK, path, code)
+		}
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- a.Shutdown(context.Background(), 50*time.Millisecond)
+	}()
+
+	// Wait for the shutdown to start.
+	for a.readiness.isReady() {
+		time.Sleep(time.Millisecond)
+	}
+
+	if code := status(readyzPath); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d for %s, got %d", http.StatusServiceUnavailable, readyzPath, code)
+	}
+
+	if code := status(healthzPath); code != http.StatusOK {
+		t.Fatalf("expected status %d for %s, got %d", http.StatusOK, healthzPath, code)
+	}
+
+	// Requests received during the grace period are still processed.
+	ts := server(a.servePrometheusRulesValidate)
+	t.Cleanup(ts.Close)
+	if resp := send(t, ts, goodRulesWithAnnotations); !resp.Response.Allowed {
+		t.Fatal("expected the request to be allowed")
+	}
+
+	select {
+	case err := <-errc:
+		t.Fatalf("expected shutdown to wait for the grace period, got %v", err)
+	default:
+	}
+
+	if err := <-errc; err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestShutdownCanceled(t *testing.T) {
+	a := api()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	t.Cleanup(cancel)
+
+	if err := a.Shutdown(ctx, time.Hour); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	if a.readiness.isReady() {
+		t.Fatal("expected the webhook to be marked as not ready")
+	}
+}
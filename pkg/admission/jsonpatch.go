@@ -17,16 +17,87 @@ package admission
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/pkg/errors"
 )
 
-func generatePatchesForNonStringLabelsAnnotations(content []byte) ([]string, error) {
-	groups := &RuleGroups{}
-	if err := json.Unmarshal(content, groups); err != nil {
-		return nil, errors.Wrap(err, "cannot unmarshal RuleGroups")
+// jsonPointerEscaper escapes the reference tokens of JSON pointers (RFC 6901).
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// patchOperation is a JSON patch operation (RFC 6902).
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+func (p patchOperation) String() string {
+	// The values are strings, string maps or raw JSON messages which can
+	// always be serialized.
+	b, _ := json.Marshal(p)
+	return string(b)
+}
+
+// JSONPatches is a list of JSON patch operations (RFC 6902).
+type JSONPatches []string
+
+// String implements the flag.Value interface.
+func (p *JSONPatches) String() string {
+	if p == nil || len(*p) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(*p, ","))
+}
+
+// Set implements the flag.Value interface. The value is a JSON array of patch
+// operations which are appended to the existing list.
+func (p *JSONPatches) Set(value string) error {
+	if _, err := jsonpatch.DecodePatch([]byte(value)); err != nil {
+		return errors.Wrap(err, "invalid JSON patch")
+	}
+
+	var ops []json.RawMessage
+	if err := json.Unmarshal([]byte(value), &ops); err != nil {
+		return errors.Wrap(err, "invalid JSON patch")
 	}
 
+	for _, op := range ops {
+		*p = append(*p, string(op))
+	}
+
+	return nil
+}
+
+// generateAnnotationPatch returns the JSON patch operation adding the
+// annotation to an object with the given annotations.
+func generateAnnotationPatch(annotations map[string]string, key, value string) (string, error) {
+	patch := struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}{
+		Op:    "add",
+		Path:  "/metadata/annotations/" + jsonPointerEscaper.Replace(key),
+		Value: value,
+	}
+
+	if len(annotations) == 0 {
+		patch.Path = "/metadata/annotations"
+		patch.Value = map[string]string{key: value}
+	}
+
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func generatePatchesForNonStringLabelsAnnotations(groups *RuleGroups) []string {
 	patches := new([]string)
 	for gi := range groups.Groups {
 		for ri, rule := range groups.Groups[gi].Rules {
@@ -39,7 +110,7 @@ func generatePatchesForNonStringLabelsAnnotations(content []byte) ([]string, err
 		}
 	}
 
-	return *patches, nil
+	return *patches
 }
 
 func patchIfNotString(patches *[]string, gi, ri int, typ, key string, val interface{}) {
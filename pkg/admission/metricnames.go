@@ -0,0 +1,155 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+// Regexps is a list of fully anchored regular expressions.
+type Regexps []*regexp.Regexp
+
+// String implements the flag.Value interface.
+func (r *Regexps) String() string {
+	if r == nil {
+		return ""
+	}
+
+	s := make([]string, 0, len(*r))
+	for _, re := range *r {
+		s = append(s, strings.TrimSuffix(strings.TrimPrefix(re.String(), "^(?:"), ")$"))
+	}
+
+	return strings.Join(s, ",")
+}
+
+// Set implements the flag.Value interface. The regular expression is appended
+// to the existing list.
+func (r *Regexps) Set(value string) error {
+	re, err := regexp.Compile("^(?:" + value + ")$")
+	if err != nil {
+		return errors.Wrapf(err, "invalid regular expression %q", value)
+	}
+
+	*r = append(*r, re)
+	return nil
+}
+
+func (r Regexps) matches(s string) bool {
+	for _, re := range r {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MetricNamePolicy defines the metric names which can be queried by the
+// PrometheusRules.
+type MetricNamePolicy struct {
+	// Allowed lists the metric names which can be queried. If empty, all the
+	// metric names are allowed. Otherwise the selectors without a metric name
+	// (e.g. {job="foo"}) are rejected since they may select any metric.
+	Allowed Regexps
+	// Denied lists the metric names which can't be queried. It takes
+	// precedence over Allowed.
+	Denied Regexps
+}
+
+// validate returns one error per rule querying metrics which aren't allowed
+// by the policy. The expressions which can't be parsed are reported by the
+// rule validation.
+func (p MetricNamePolicy) validate(spec monitoringv1.PrometheusRuleSpec) []error {
+	if len(p.Allowed) == 0 && len(p.Denied) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, g := range spec.Groups {
+		for i, r := range g.Rules {
+			expr, err := parser.ParseExpr(r.Expr.String())
+			if err != nil {
+				continue
+			}
+
+			violations := p.violations(expr)
+			if len(violations) == 0 {
+				continue
+			}
+
+			errs = append(errs, errors.Errorf("group %q, rule %d, %q: %s", g.Name, i+1, ruleName(r), strings.Join(violations, "; ")))
+		}
+	}
+
+	return errs
+}
+
+func (p MetricNamePolicy) violations(expr parser.Expr) []string {
+	found := map[string]struct{}{}
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		vs, ok := node.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+
+		name := metricName(vs)
+		switch {
+		case name == "":
+			if len(p.Allowed) > 0 {
+				found[fmt.Sprintf("selector %s has no metric name", vs)] = struct{}{}
+			}
+		case p.Denied.matches(name):
+			found[fmt.Sprintf("metric %q is denied", name)] = struct{}{}
+		case len(p.Allowed) > 0 && !p.Allowed.matches(name):
+			found[fmt.Sprintf("metric %q isn't allowed", name)] = struct{}{}
+		}
+
+		return nil
+	})
+
+	violations := make([]string, 0, len(found))
+	for v := range found {
+		violations = append(violations, v)
+	}
+	sort.Strings(violations)
+
+	return violations
+}
+
+// metricName returns the metric name selected by the vector selector or an
+// empty string if the name isn't matched exactly.
+func metricName(vs *parser.VectorSelector) string {
+	if vs.Name != "" {
+		return vs.Name
+	}
+
+	for _, m := range vs.LabelMatchers {
+		if m.Name == labels.MetricName && m.Type == labels.MatchEqual {
+			return m.Value
+		}
+	}
+
+	return ""
+}
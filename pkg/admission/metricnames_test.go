@@ -0,0 +1,133 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"reflect"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestMetricNamePolicy(t *testing.T) {
+	regexps := func(values ...string) Regexps {
+		var r Regexps
+		for _, v := range values {
+			if err := r.Set(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return r
+	}
+
+	for _, tc := range []struct {
+		name     string
+		allowed  []string
+		denied   []string
+		expr     string
+		expected []string
+	}{
+		{
+			name: "no policy",
+			expr: `sum(apiserver_request_duration_seconds_bucket)`,
+		},
+		{
+			name:   "denied metric",
+			denied: []string{"apiserver_request_duration_seconds_bucket", "etcd_.*"},
+			expr:   `sum(rate(apiserver_request_duration_seconds_bucket[5m])) / sum(rate(apiserver_request_total[5m]))`,
+			expected: []string{
+				`group "test", rule 1, "Test": metric "apiserver_request_duration_seconds_bucket" is denied`,
+			},
+		},
+		{
+			name:   "denied metric in name matcher",
+			denied: []string{"etcd_.*"},
+			expr:   `{__name__="etcd_request_duration_seconds_bucket"}`,
+			expected: []string{
+				`group "test", rule 1, "Test": metric "etcd_request_duration_seconds_bucket" is denied`,
+			},
+		},
+		{
+			name:    "allowed metrics",
+			allowed: []string{"up", "node_.*"},
+			expr:    `up == 0 and on(instance) node_load1 > 1`,
+		},
+		{
+			name:    "metric not allowed",
+			allowed: []string{"up"},
+			denied:  []string{"node_load1"},
+			expr:    `up == 0 and on(instance) (node_load1 > 1 or node_load5 > 1)`,
+			expected: []string{
+				`group "test", rule 1, "Test": metric "node_load1" is denied; metric "node_load5" isn't allowed`,
+			},
+		},
+		{
+			name:    "selector without metric name",
+			allowed: []string{"up"},
+			expr:    `count({job="node"})`,
+			expected: []string{
+				`group "test", rule 1, "Test": selector {job="node"} has no metric name`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := MetricNamePolicy{
+				Allowed: regexps(tc.allowed...),
+				Denied:  regexps(tc.denied...),
+			}
+
+			spec := monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{
+					{
+						Name:  "test",
+						Rules: []monitoringv1.Rule{{Alert: "Test", Expr: intstr.FromString(tc.expr)}},
+					},
+				},
+			}
+
+			var msgs []string
+			for _, err := range p.validate(spec) {
+				msgs = append(msgs, err.Error())
+			}
+
+			if !reflect.DeepEqual(tc.expected, msgs) {
+				t.Fatalf("expected %v, got %v", tc.expected, msgs)
+			}
+		})
+	}
+}
+
+func TestRegexpsFlag(t *testing.T) {
+	var r Regexps
+	if err := r.Set("go_.*"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Set("process_cpu_seconds_total"); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := r.String(); s != "go_.*,process_cpu_seconds_total" {
+		t.Fatalf("unexpected string %q", s)
+	}
+
+	if r.matches("foo_go_info") {
+		t.Fatal("expected the regular expressions to be anchored")
+	}
+
+	if err := r.Set("(foo"); err == nil {
+		t.Fatal("expected an error for an invalid regular expression")
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// validationLabels are the labels of the validation metrics.
+var validationLabels = []string{"namespace", "resource"}
+
+func (a *Admission) initMetrics() {
+	a.validationTriggeredCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_rule_validation_triggered_total",
+		Help: "Number of times a prometheusRule object triggered validation",
+	}, validationLabels)
+
+	a.validationErrorsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_rule_validation_errors_total",
+		Help: "Number of errors that occurred while validating a prometheusRules object",
+	}, validationLabels)
+
+	a.amConfValidationTriggeredCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_alertmanager_config_validation_triggered_total",
+		Help: "Number of times an alertmanagerconfig object triggered validation",
+	}, validationLabels)
+
+	a.amConfValidationErrorsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_alertmanager_config_validation_errors_total",
+		Help: "Number of errors that occurred while validating a alertmanagerconfig object",
+	}, validationLabels)
+
+	a.probeValidationTriggeredCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_probe_validation_triggered_total",
+		Help: "Number of times a probe object triggered validation",
+	}, validationLabels)
+
+	a.probeValidationErrorsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_probe_validation_errors_total",
+		Help: "Number of errors that occurred while validating a probe object",
+	}, validationLabels)
+
+	a.monitorValidationTriggeredCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_monitor_validation_triggered_total",
+		Help: "Number of times a servicemonitor or podmonitor object triggered validation",
+	}, validationLabels)
+
+	a.monitorValidationErrorsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_monitor_validation_errors_total",
+		Help: "Number of errors that occurred while validating a servicemonitor or podmonitor object",
+	}, validationLabels)
+
+	a.promValidationTriggeredCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_prometheus_validation_triggered_total",
+		Help: "Number of times a prometheus object triggered validation",
+	}, validationLabels)
+
+	a.promValidationErrorsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_prometheus_validation_errors_total",
+		Help: "Number of errors that occurred while validating a prometheus object",
+	}, validationLabels)
+
+	a.scValidationTriggeredCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_scrapeconfig_validation_triggered_total",
+		Help: "Number of times a scrapeconfig object triggered validation",
+	}, validationLabels)
+
+	a.scValidationErrorsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_scrapeconfig_validation_errors_total",
+		Help: "Number of errors that occurred while validating a scrapeconfig object",
+	}, validationLabels)
+
+	a.inFlightRequestsGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "prometheus_operator_admission_in_flight_requests",
+		Help: "Number of requests currently processed by the admission webhook",
+	})
+
+	a.throttledRequestsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prometheus_operator_admission_throttled_requests_total",
+		Help: "Number of requests rejected by the admission webhook because of throttling",
+	}, []string{"reason"})
+
+	a.oversizedRequestsCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prometheus_operator_admission_oversized_requests_total",
+		Help: "Number of requests rejected by the admission webhook because the body exceeded the size limit",
+	})
+}
+
+// RegisterMetrics registers the metrics of the admission webhook. The
+// validation metrics are labelled by the namespace and the resource of the
+// validated objects.
+func (a *Admission) RegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(
+		a.validationTriggeredCounter,
+		a.validationErrorsCounter,
+		a.amConfValidationTriggeredCounter,
+		a.amConfValidationErrorsCounter,
+		a.probeValidationTriggeredCounter,
+		a.probeValidationErrorsCounter,
+		a.monitorValidationTriggeredCounter,
+		a.monitorValidationErrorsCounter,
+		a.promValidationTriggeredCounter,
+		a.promValidationErrorsCounter,
+		a.scValidationTriggeredCounter,
+		a.scValidationErrorsCounter,
+		a.inFlightRequestsGauge,
+		a.throttledRequestsCounter,
+		a.oversizedRequestsCounter,
+	)
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+const defaultOPATimeout = 5 * time.Second
+
+// PolicyEvaluator evaluates custom policies against PrometheusRules.
+type PolicyEvaluator interface {
+	// Evaluate returns the list of policy violations for the PrometheusRule.
+	Evaluate(context.Context, *monitoringv1.PrometheusRule) ([]string, error)
+}
+
+// OPAEvaluator evaluates the policies loaded into an Open Policy Agent server
+// using its data API.
+type OPAEvaluator struct {
+	url    string
+	client *http.Client
+}
+
+// NewOPAEvaluator returns a PolicyEvaluator querying the document at the given
+// URL of the OPA data API (e.g. http://opa:8181/v1/data/prometheusrules/deny).
+// The PrometheusRule object is sent as the input document and the result must
+// be the list of violation messages.
+func NewOPAEvaluator(url string) *OPAEvaluator {
+	return &OPAEvaluator{
+		url:    url,
+		client: &http.Client{Timeout: defaultOPATimeout},
+	}
+}
+
+// Evaluate implements the PolicyEvaluator interface.
+func (o *OPAEvaluator) Evaluate(ctx context.Context, promRule *monitoringv1.PrometheusRule) ([]string, error) {
+	body, err := json.Marshal(struct {
+		Input *monitoringv1.PrometheusRule `json:"input"`
+	}{
+		Input: promRule,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal OPA input")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create OPA request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query OPA")
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read OPA response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected OPA response status %d: %s", resp.StatusCode, string(b))
+	}
+
+	// The result is missing when the document isn't defined.
+	var result struct {
+		Result *[]string `json:"result"`
+	}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, errors.Wrap(err, "failed to decode OPA response, expected the result to be a list of strings")
+	}
+
+	if result.Result == nil {
+		return nil, errors.Errorf("OPA document %s is undefined", o.url)
+	}
+
+	return *result.Result, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestOPAEvaluator(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		status     int
+		response   string
+		violations []string
+		ok         bool
+	}{
+		{
+			name:     "no violation",
+			status:   http.StatusOK,
+			response: `{"result": []}`,
+			ok:       true,
+		},
+		{
+			name:       "violations",
+			status:     http.StatusOK,
+			response:   `{"result": ["rule names must be in CamelCase", "team label is missing"]}`,
+			violations: []string{"rule names must be in CamelCase", "team label is missing"},
+			ok:         true,
+		},
+		{
+			name:     "undefined document",
+			status:   http.StatusOK,
+			response: `{}`,
+		},
+		{
+			name:     "invalid result",
+			status:   http.StatusOK,
+			response: `{"result": true}`,
+		},
+		{
+			name:     "server error",
+			status:   http.StatusInternalServerError,
+			response: `{"code": "internal_error"}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var input struct {
+					Input monitoringv1.PrometheusRule `json:"input"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Input.Name != "test" {
+					http.Error(w, "invalid input", http.StatusBadRequest)
+					return
+				}
+
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.response))
+			}))
+			t.Cleanup(ts.Close)
+
+			violations, err := NewOPAEvaluator(ts.URL).Evaluate(
+				context.Background(),
+				&monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+			)
+
+			if !tc.ok {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if len(tc.violations) == 0 && len(violations) == 0 {
+				return
+			}
+
+			if !reflect.DeepEqual(tc.violations, violations) {
+				t.Fatalf("expected %v, got %v", tc.violations, violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+// RulePolicy defines the requirements enforced on the alerting rules of
+// PrometheusRules.
+type RulePolicy struct {
+	// RequiredLabels is the list of labels that every alerting rule must define.
+	RequiredLabels []string
+	// RequiredAnnotations is the list of annotations that every alerting rule
+	// must define.
+	RequiredAnnotations []string
+}
+
+// validate returns one error per rule group with the alerting rules which
+// don't comply with the policy.
+func (p RulePolicy) validate(spec monitoringv1.PrometheusRuleSpec) []error {
+	if len(p.RequiredLabels) == 0 && len(p.RequiredAnnotations) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, g := range spec.Groups {
+		var offending []string
+		for _, r := range g.Rules {
+			if r.Alert == "" {
+				continue
+			}
+
+			var missing []string
+			if l := missingKeys(r.Labels, p.RequiredLabels); len(l) > 0 {
+				missing = append(missing, fmt.Sprintf("missing labels: %s", strings.Join(l, ", ")))
+			}
+			if a := missingKeys(r.Annotations, p.RequiredAnnotations); len(a) > 0 {
+				missing = append(missing, fmt.Sprintf("missing annotations: %s", strings.Join(a, ", ")))
+			}
+
+			if len(missing) > 0 {
+				offending = append(offending, fmt.Sprintf("%s (%s)", r.Alert, strings.Join(missing, "; ")))
+			}
+		}
+
+		if len(offending) > 0 {
+			errs = append(errs, errors.Errorf("group %q: %s", g.Name, strings.Join(offending, ", ")))
+		}
+	}
+
+	return errs
+}
+
+func missingKeys(m map[string]string, keys []string) []string {
+	var missing []string
+	for _, k := range keys {
+		if v, ok := m[k]; !ok || v == "" {
+			missing = append(missing, k)
+		}
+	}
+
+	return missing
+}
+
+// evaluatePolicies returns the violations reported by the custom policy
+// evaluators. The evaluation errors are considered as violations.
+func (a *Admission) evaluatePolicies(ctx context.Context, promRule *monitoringv1.PrometheusRule) []error {
+	var errs []error
+	for _, pe := range a.config.PolicyEvaluators {
+		violations, err := pe.Evaluate(ctx, promRule)
+		if err != nil {
+			errs = append(errs, errors.Wrap(err, "failed to evaluate policy"))
+			continue
+		}
+
+		for _, v := range violations {
+			errs = append(errs, errors.New(v))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestRulePolicyValidate(t *testing.T) {
+	spec := monitoringv1.PrometheusRuleSpec{
+		Groups: []monitoringv1.RuleGroup{
+			{
+				Name: "group1",
+				Rules: []monitoringv1.Rule{
+					{
+						Record: "job:up:sum",
+						Expr:   intstr.FromString("sum by (job) (up)"),
+					},
+					{
+						Alert: "Compliant",
+						Expr:  intstr.FromString("up == 0"),
+						Labels: map[string]string{
+							"severity": "critical",
+							"team":     "infra",
+						},
+						Annotations: map[string]string{
+							"runbook_url": "https://example.com",
+						},
+					},
+					{
+						Alert: "MissingTeam",
+						Expr:  intstr.FromString("up == 0"),
+						Labels: map[string]string{
+							"severity": "critical",
+						},
+						Annotations: map[string]string{
+							"runbook_url": "https://example.com",
+						},
+					},
+				},
+			},
+			{
+				Name: "group2",
+				Rules: []monitoringv1.Rule{
+					{
+						Alert: "MissingAll",
+						Expr:  intstr.FromString("up == 0"),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		policy   RulePolicy
+		expected []string
+	}{
+		{
+			name: "empty policy",
+		},
+		{
+			name: "required labels",
+			policy: RulePolicy{
+				RequiredLabels: []string{"severity", "team"},
+			},
+			expected: []string{
+				`group "group1": MissingTeam (missing labels: team)`,
+				`group "group2": MissingAll (missing labels: severity, team)`,
+			},
+		},
+		{
+			name: "required labels and annotations",
+			policy: RulePolicy{
+				RequiredLabels:      []string{"team"},
+				RequiredAnnotations: []string{"runbook_url"},
+			},
+			expected: []string{
+				`group "group1": MissingTeam (missing labels: team)`,
+				`group "group2": MissingAll (missing labels: team; missing annotations: runbook_url)`,
+			},
+		},
+		{
+			name: "missing annotation on all alerts",
+			policy: RulePolicy{
+				RequiredAnnotations: []string{"foo"},
+			},
+			expected: []string{
+				`group "group1": Compliant (missing annotations: foo), MissingTeam (missing annotations: foo)`,
+				`group "group2": MissingAll (missing annotations: foo)`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.policy.validate(spec)
+
+			if len(errs) != len(tc.expected) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tc.expected), len(errs), errs)
+			}
+
+			for i := range errs {
+				if errs[i].Error() != tc.expected[i] {
+					t.Fatalf("expected error %q, got %q", tc.expected[i], errs[i].Error())
+				}
+			}
+		})
+	}
+}
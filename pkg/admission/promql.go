@@ -0,0 +1,227 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+const (
+	// AtModifierFeature and NegativeOffsetFeature are the Prometheus feature
+	// flags enabling PromQL features before they became stable.
+	AtModifierFeature     = "promql-at-modifier"
+	NegativeOffsetFeature = "promql-negative-offset"
+)
+
+// promqlFeature describes a PromQL feature which is enabled by default from
+// a given Prometheus version and can be enabled by a feature flag in earlier
+// versions.
+type promqlFeature struct {
+	description string
+	flag        string
+	// flagVersion is the first version supporting the feature flag.
+	flagVersion semver.Version
+	// stableVersion is the first version enabling the feature by default.
+	stableVersion semver.Version
+}
+
+var (
+	atModifier = promqlFeature{
+		description:   "the @ modifier",
+		flag:          AtModifierFeature,
+		flagVersion:   semver.MustParse("2.25.0"),
+		stableVersion: semver.MustParse("2.33.0"),
+	}
+	atModifierStartEnd = promqlFeature{
+		description:   "the start() and end() @ modifier preprocessors",
+		flag:          AtModifierFeature,
+		flagVersion:   semver.MustParse("2.26.0"),
+		stableVersion: semver.MustParse("2.33.0"),
+	}
+	negativeOffset = promqlFeature{
+		description:   "negative offsets",
+		flag:          NegativeOffsetFeature,
+		flagVersion:   semver.MustParse("2.26.0"),
+		stableVersion: semver.MustParse("2.33.0"),
+	}
+
+	// functionVersions holds the first Prometheus version supporting the
+	// recently added PromQL functions.
+	functionVersions = map[string]semver.Version{
+		"absent_over_time":  semver.MustParse("2.16.0"),
+		"clamp":             semver.MustParse("2.26.0"),
+		"last_over_time":    semver.MustParse("2.26.0"),
+		"sgn":               semver.MustParse("2.26.0"),
+		"acos":              semver.MustParse("2.26.0"),
+		"acosh":             semver.MustParse("2.26.0"),
+		"asin":              semver.MustParse("2.26.0"),
+		"asinh":             semver.MustParse("2.26.0"),
+		"atan":              semver.MustParse("2.26.0"),
+		"atanh":             semver.MustParse("2.26.0"),
+		"cos":               semver.MustParse("2.26.0"),
+		"cosh":              semver.MustParse("2.26.0"),
+		"sin":               semver.MustParse("2.26.0"),
+		"sinh":              semver.MustParse("2.26.0"),
+		"tan":               semver.MustParse("2.26.0"),
+		"tanh":              semver.MustParse("2.26.0"),
+		"deg":               semver.MustParse("2.26.0"),
+		"rad":               semver.MustParse("2.26.0"),
+		"pi":                semver.MustParse("2.26.0"),
+		"present_over_time": semver.MustParse("2.29.0"),
+	}
+)
+
+// PromQLCompatibility defines the Prometheus version loading the
+// PrometheusRules. The expressions using PromQL features which aren't
+// available in this version are rejected.
+type PromQLCompatibility struct {
+	// Version is the version of the Prometheus servers. If empty, all the
+	// features supported by the webhook are accepted.
+	Version string
+	// EnabledFeatures lists the feature flags enabled on the Prometheus
+	// servers (e.g. promql-at-modifier).
+	EnabledFeatures []string
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c PromQLCompatibility) Validate() error {
+	if c.Version == "" {
+		if len(c.EnabledFeatures) > 0 {
+			return errors.New("enabled features require a Prometheus version")
+		}
+		return nil
+	}
+
+	if _, err := semver.ParseTolerant(c.Version); err != nil {
+		return errors.Wrap(err, "invalid Prometheus version")
+	}
+
+	for _, f := range c.EnabledFeatures {
+		if f != AtModifierFeature && f != NegativeOffsetFeature {
+			return errors.Errorf("unknown feature %q, possible values: %s, %s", f, AtModifierFeature, NegativeOffsetFeature)
+		}
+	}
+
+	return nil
+}
+
+func (c PromQLCompatibility) supports(version semver.Version, f promqlFeature) bool {
+	if version.GTE(f.stableVersion) {
+		return true
+	}
+
+	if version.LT(f.flagVersion) {
+		return false
+	}
+
+	for _, enabled := range c.EnabledFeatures {
+		if enabled == f.flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f promqlFeature) requirement() string {
+	return fmt.Sprintf("%s requires Prometheus >= %s or >= %s with the %s feature flag", f.description, f.stableVersion, f.flagVersion, f.flag)
+}
+
+// validate returns one error per rule using PromQL features unsupported by
+// the target Prometheus version. The expressions which can't be parsed are
+// reported by the rule validation.
+func (c PromQLCompatibility) validate(spec monitoringv1.PrometheusRuleSpec) []error {
+	if c.Version == "" {
+		return nil
+	}
+
+	version, err := semver.ParseTolerant(c.Version)
+	if err != nil {
+		return []error{errors.Wrap(err, "invalid Prometheus version")}
+	}
+
+	var errs []error
+	for _, g := range spec.Groups {
+		for i, r := range g.Rules {
+			expr, err := parser.ParseExpr(r.Expr.String())
+			if err != nil {
+				continue
+			}
+
+			unsupported := c.unsupportedFeatures(version, expr)
+			if len(unsupported) == 0 {
+				continue
+			}
+
+			name := r.Alert
+			if name == "" {
+				name = r.Record
+			}
+			errs = append(errs, errors.Errorf("group %q, rule %d, %q: %s", g.Name, i+1, name, strings.Join(unsupported, "; ")))
+		}
+	}
+
+	return errs
+}
+
+func (c PromQLCompatibility) unsupportedFeatures(version semver.Version, expr parser.Expr) []string {
+	found := map[string]struct{}{}
+	check := func(f promqlFeature) {
+		if !c.supports(version, f) {
+			found[f.requirement()] = struct{}{}
+		}
+	}
+	checkModifiers := func(timestamp *int64, startOrEnd parser.ItemType, offset int64) {
+		if timestamp != nil {
+			check(atModifier)
+		}
+		if startOrEnd != 0 {
+			check(atModifierStartEnd)
+		}
+		if offset < 0 {
+			check(negativeOffset)
+		}
+	}
+
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		switch n := node.(type) {
+		case *parser.VectorSelector:
+			checkModifiers(n.Timestamp, n.StartOrEnd, int64(n.OriginalOffset))
+		case *parser.SubqueryExpr:
+			checkModifiers(n.Timestamp, n.StartOrEnd, int64(n.OriginalOffset))
+		case *parser.Call:
+			if v, ok := functionVersions[n.Func.Name]; ok && version.LT(v) {
+				found[fmt.Sprintf("the %s() function requires Prometheus >= %s", n.Func.Name, v)] = struct{}{}
+			}
+		}
+		return nil
+	})
+
+	unsupported := make([]string, 0, len(found))
+	for f := range found {
+		unsupported = append(unsupported, f)
+	}
+	sort.Strings(unsupported)
+
+	return unsupported
+}
@@ -0,0 +1,125 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestPromQLCompatibility(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		compat   PromQLCompatibility
+		expr     string
+		expected int
+	}{
+		{
+			name: "no version",
+			expr: `present_over_time(up[5m] @ 1609746000)`,
+		},
+		{
+			name:   "supported function",
+			compat: PromQLCompatibility{Version: "2.26.0"},
+			expr:   `last_over_time(up[5m])`,
+		},
+		{
+			name:     "unsupported function",
+			compat:   PromQLCompatibility{Version: "v2.28.1"},
+			expr:     `present_over_time(up[5m])`,
+			expected: 1,
+		},
+		{
+			name:     "@ modifier without feature flag",
+			compat:   PromQLCompatibility{Version: "2.30.0"},
+			expr:     `up @ 1609746000`,
+			expected: 1,
+		},
+		{
+			name:   "@ modifier with feature flag",
+			compat: PromQLCompatibility{Version: "2.30.0", EnabledFeatures: []string{AtModifierFeature}},
+			expr:   `rate(up[5m] @ end())`,
+		},
+		{
+			name:     "@ modifier with feature flag in unsupported version",
+			compat:   PromQLCompatibility{Version: "2.24.0", EnabledFeatures: []string{AtModifierFeature}},
+			expr:     `up @ 1609746000`,
+			expected: 1,
+		},
+		{
+			name:   "@ modifier in stable version",
+			compat: PromQLCompatibility{Version: "2.33.0"},
+			expr:   `max_over_time(rate(up[5m])[1h:] @ start())`,
+		},
+		{
+			name:     "negative offset",
+			compat:   PromQLCompatibility{Version: "2.30.0", EnabledFeatures: []string{AtModifierFeature}},
+			expr:     `up offset -5m`,
+			expected: 1,
+		},
+		{
+			name:   "positive offset",
+			compat: PromQLCompatibility{Version: "2.0.0"},
+			expr:   `up offset 5m`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.compat.Validate(); err != nil {
+				t.Fatalf("unexpected configuration error: %v", err)
+			}
+
+			errs := tc.compat.validate(monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{
+					Name: "test",
+					Rules: []monitoringv1.Rule{
+						{Alert: "Test", Expr: intstr.FromString(tc.expr)},
+					},
+				}},
+			})
+			if len(errs) != tc.expected {
+				t.Fatalf("expected %d errors, got %v", tc.expected, errs)
+			}
+		})
+	}
+}
+
+func TestPromQLCompatibilityValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		compat PromQLCompatibility
+	}{
+		{
+			name:   "invalid version",
+			compat: PromQLCompatibility{Version: "latest"},
+		},
+		{
+			name:   "unknown feature",
+			compat: PromQLCompatibility{Version: "2.30.0", EnabledFeatures: []string{"exemplar-storage"}},
+		},
+		{
+			name:   "features without version",
+			compat: PromQLCompatibility{EnabledFeatures: []string{AtModifierFeature}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.compat.Validate(); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"github.com/pkg/errors"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+// NamespaceRuleLister lists the PrometheusRules of a namespace.
+type NamespaceRuleLister interface {
+	ListNamespaceRules(namespace string) ([]*monitoringv1.PrometheusRule, error)
+}
+
+// RuleQuota defines the limits enforced on the size of the PrometheusRules.
+// Zero means no limit.
+type RuleQuota struct {
+	// MaxGroups is the maximum number of groups per PrometheusRule.
+	MaxGroups int
+	// MaxRulesPerGroup is the maximum number of rules per group.
+	MaxRulesPerGroup int
+	// MaxRulesPerNamespace is the maximum number of rules defined by all the
+	// PrometheusRules of a namespace. It requires a NamespaceRuleLister and
+	// isn't enforced otherwise.
+	MaxRulesPerNamespace int
+}
+
+// validate returns the errors for the limits exceeded by the PrometheusRule.
+func (q RuleQuota) validate(promRule *monitoringv1.PrometheusRule, lister NamespaceRuleLister) []error {
+	var errs []error
+
+	if q.MaxGroups > 0 && len(promRule.Spec.Groups) > q.MaxGroups {
+		errs = append(errs, errors.Errorf("%d groups defined, the limit is %d groups per PrometheusRule", len(promRule.Spec.Groups), q.MaxGroups))
+	}
+
+	var total int
+	for _, g := range promRule.Spec.Groups {
+		total += len(g.Rules)
+		if q.MaxRulesPerGroup > 0 && len(g.Rules) > q.MaxRulesPerGroup {
+			errs = append(errs, errors.Errorf("group %q: %d rules defined, the limit is %d rules per group", g.Name, len(g.Rules), q.MaxRulesPerGroup))
+		}
+	}
+
+	if q.MaxRulesPerNamespace <= 0 || lister == nil {
+		return errs
+	}
+
+	rules, err := lister.ListNamespaceRules(promRule.Namespace)
+	if err != nil {
+		return append(errs, errors.Wrap(err, "failed to count the rules of the namespace"))
+	}
+
+	for _, r := range rules {
+		// The rules of the object being updated are replaced.
+		if r.Name == promRule.Name {
+			continue
+		}
+
+		for _, g := range r.Spec.Groups {
+			total += len(g.Rules)
+		}
+	}
+
+	if total > q.MaxRulesPerNamespace {
+		errs = append(errs, errors.Errorf("%d rules defined in namespace %q, the limit is %d rules per namespace", total, promRule.Namespace, q.MaxRulesPerNamespace))
+	}
+
+	return errs
+}
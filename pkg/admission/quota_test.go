@@ -0,0 +1,140 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"reflect"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+type fakeNamespaceRuleLister []*monitoringv1.PrometheusRule
+
+func (f fakeNamespaceRuleLister) ListNamespaceRules(string) ([]*monitoringv1.PrometheusRule, error) {
+	return f, nil
+}
+
+func TestRuleQuota(t *testing.T) {
+	newRule := func(name string, groups ...int) *monitoringv1.PrometheusRule {
+		promRule := &monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+		for i, n := range groups {
+			g := monitoringv1.RuleGroup{Name: string(rune('a' + i))}
+			for j := 0; j < n; j++ {
+				g.Rules = append(g.Rules, monitoringv1.Rule{Record: "foo", Expr: intstr.FromString("vector(1)")})
+			}
+			promRule.Spec.Groups = append(promRule.Spec.Groups, g)
+		}
+
+		return promRule
+	}
+
+	for _, tc := range []struct {
+		name     string
+		quota    RuleQuota
+		lister   NamespaceRuleLister
+		rule     *monitoringv1.PrometheusRule
+		expected []string
+	}{
+		{
+			name: "no quota",
+			rule: newRule("test", 10, 10, 10),
+		},
+		{
+			name:  "within quota",
+			quota: RuleQuota{MaxGroups: 2, MaxRulesPerGroup: 2, MaxRulesPerNamespace: 5},
+			lister: fakeNamespaceRuleLister{
+				newRule("other", 2),
+			},
+			rule: newRule("test", 2, 1),
+		},
+		{
+			name:  "too many groups",
+			quota: RuleQuota{MaxGroups: 2},
+			rule:  newRule("test", 1, 1, 1),
+			expected: []string{
+				"3 groups defined, the limit is 2 groups per PrometheusRule",
+			},
+		},
+		{
+			name:  "too many rules per group",
+			quota: RuleQuota{MaxRulesPerGroup: 2},
+			rule:  newRule("test", 1, 3),
+			expected: []string{
+				`group "b": 3 rules defined, the limit is 2 rules per group`,
+			},
+		},
+		{
+			name:  "too many rules per namespace",
+			quota: RuleQuota{MaxRulesPerNamespace: 5},
+			lister: fakeNamespaceRuleLister{
+				newRule("other", 2, 2),
+			},
+			rule: newRule("test", 2),
+			expected: []string{
+				`6 rules defined in namespace "default", the limit is 5 rules per namespace`,
+			},
+		},
+		{
+			name:  "updated object isn't counted twice",
+			quota: RuleQuota{MaxRulesPerNamespace: 5},
+			lister: fakeNamespaceRuleLister{
+				newRule("other", 2),
+				newRule("test", 3),
+			},
+			rule: newRule("test", 3),
+		},
+		{
+			name:  "no namespace lister",
+			quota: RuleQuota{MaxRulesPerNamespace: 1},
+			rule:  newRule("test", 2),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var msgs []string
+			for _, err := range tc.quota.validate(tc.rule, tc.lister) {
+				msgs = append(msgs, err.Error())
+			}
+
+			if !reflect.DeepEqual(tc.expected, msgs) {
+				t.Fatalf("expected %v, got %v", tc.expected, msgs)
+			}
+		})
+	}
+}
+
+func TestAdmitRuleQuota(t *testing.T) {
+	a := api()
+	a.config.RuleQuota = RuleQuota{MaxRulesPerGroup: 1}
+
+	ts := server(a.servePrometheusRulesValidate)
+	t.Cleanup(ts.Close)
+
+	resp := send(t, ts, buildAdmissionReview(t, ruleResource, "PrometheusRule", v1.Create, reviewObject{
+		spec: `{"groups":[{"name":"test.rules","rules":[{"record":"foo","expr":"vector(1)"},{"record":"bar","expr":"vector(2)"}]}]}`,
+	}))
+	if resp.Response.Allowed {
+		t.Fatal("Expected admission to not be allowed but it was")
+	}
+
+	if resp.Response.Result.Message != "Rules exceed the quota" {
+		t.Fatalf("Unexpected message %q", resp.Response.Result.Message)
+	}
+}
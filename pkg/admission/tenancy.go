@@ -0,0 +1,81 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus-community/prom-label-proxy/injectproxy"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// generateNamespaceLabelPatches returns the JSON patch operations enforcing
+// the namespace label on the rules, with the same semantics as the
+// enforcedNamespaceLabel field of the Prometheus resource: the label is
+// added to the rule labels and a matcher on the label is injected into every
+// selector of the expression.
+func generateNamespaceLabelPatches(groups *RuleGroups, label, namespace string) ([]string, error) {
+	var patches []string
+	for gi, g := range groups.Groups {
+		for ri, r := range g.Rules {
+			path := fmt.Sprintf("/spec/groups/%d/rules/%d", gi, ri)
+
+			if len(r.Labels) == 0 {
+				patches = append(patches, patchOperation{
+					Op:    "add",
+					Path:  path + "/labels",
+					Value: map[string]string{label: namespace},
+				}.String())
+			} else if v, ok := r.Labels[label]; !ok || v != namespace {
+				patches = append(patches, patchOperation{
+					Op:    "add",
+					Path:  path + "/labels/" + jsonPointerEscaper.Replace(label),
+					Value: namespace,
+				}.String())
+			}
+
+			expr, ok := r.Expr.(string)
+			if !ok {
+				expr = fmt.Sprint(r.Expr)
+			}
+
+			parsedExpr, err := parser.ParseExpr(expr)
+			if err != nil {
+				return nil, errors.Wrapf(err, "group %q, rule %d: failed to parse promql expression", g.Name, ri+1)
+			}
+
+			enforcer := injectproxy.NewEnforcer(&labels.Matcher{
+				Name:  label,
+				Type:  labels.MatchEqual,
+				Value: namespace,
+			})
+			if err := enforcer.EnforceNode(parsedExpr); err != nil {
+				return nil, errors.Wrapf(err, "group %q, rule %d: failed to inject labels to expression", g.Name, ri+1)
+			}
+
+			if enforced := parsedExpr.String(); enforced != expr {
+				patches = append(patches, patchOperation{
+					Op:    "replace",
+					Path:  path + "/expr",
+					Value: enforced,
+				}.String())
+			}
+		}
+	}
+
+	return patches, nil
+}
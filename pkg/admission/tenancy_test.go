@@ -0,0 +1,88 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestEnforceNamespaceLabel(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		label    string
+		spec     string
+		expected []monitoringv1.Rule
+	}{
+		{
+			name: "disabled",
+			spec: `{"groups":[{"name":"test","rules":[{"alert":"Down","expr":"up == 0"}]}]}`,
+			expected: []monitoringv1.Rule{
+				{Alert: "Down", Expr: intstr.FromString("up == 0")},
+			},
+		},
+		{
+			name:  "enforced",
+			label: "namespace",
+			spec:  `{"groups":[{"name":"test","rules":[{"alert":"Down","expr":"up == 0"},{"record":"job:up:sum","expr":"sum(up)","labels":{"team":"a","namespace":"other"}},{"record":"one","expr":"vector(1)","labels":{"namespace":"monitoring"}}]}]}`,
+			expected: []monitoringv1.Rule{
+				{Alert: "Down", Expr: intstr.FromString(`up{namespace="monitoring"} == 0`), Labels: map[string]string{"namespace": "monitoring"}},
+				{Record: "job:up:sum", Expr: intstr.FromString(`sum(up{namespace="monitoring"})`), Labels: map[string]string{"team": "a", "namespace": "monitoring"}},
+				{Record: "one", Expr: intstr.FromString("vector(1)"), Labels: map[string]string{"namespace": "monitoring"}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := api()
+			a.config.EnforcedNamespaceLabel = tc.label
+
+			ts := server(a.servePrometheusRulesMutate)
+			t.Cleanup(ts.Close)
+
+			request := buildAdmissionReview(t, ruleResource, "PrometheusRule", v1.Create, reviewObject{spec: tc.spec})
+			resp := send(t, ts, request)
+
+			patch, err := jsonpatch.DecodePatch(resp.Response.Patch)
+			if err != nil {
+				t.Fatalf("Expected a valid patch, got %v", err)
+			}
+
+			rev := v1.AdmissionReview{}
+			if err := json.Unmarshal(request, &rev); err != nil {
+				t.Fatal(err)
+			}
+
+			raw, err := patch.Apply(rev.Request.Object.Raw)
+			if err != nil {
+				t.Fatalf("Expected to successfully apply patch, got %v", err)
+			}
+
+			var rule monitoringv1.PrometheusRule
+			if err := json.Unmarshal(raw, &rule); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tc.expected, rule.Spec.Groups[0].Rules) {
+				t.Fatalf("expected rules %+v, got %+v", tc.expected, rule.Spec.Groups[0].Rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"golang.org/x/time/rate"
+)
+
+const (
+	throttleReasonConcurrency = "concurrency"
+	throttleReasonRateLimit   = "rate_limit"
+
+	// clientLimiterTTL is the duration after which the rate limiter of an
+	// inactive client is discarded.
+	clientLimiterTTL = 10 * time.Minute
+)
+
+// throttler bounds the number of requests processed concurrently and the
+// rate of requests accepted from each client.
+type throttler struct {
+	// sem is nil when the number of concurrent requests isn't limited.
+	sem chan struct{}
+
+	limit rate.Limit
+	burst int
+
+	mtx       sync.Mutex
+	clients   map[string]*clientLimiter
+	lastPrune time.Time
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newThrottler(maxConcurrent int, limit float64, burst int) *throttler {
+	t := &throttler{
+		limit:   rate.Inf,
+		clients: map[string]*clientLimiter{},
+	}
+
+	if maxConcurrent > 0 {
+		t.sem = make(chan struct{}, maxConcurrent)
+	}
+
+	if limit > 0 {
+		t.limit = rate.Limit(limit)
+		t.burst = burst
+		if t.burst < 1 {
+			t.burst = 1
+		}
+	}
+
+	return t
+}
+
+// allow reports whether a new request from the client is within the rate
+// limit.
+func (t *throttler) allow(client string, now time.Time) bool {
+	if t.limit == rate.Inf {
+		return true
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if now.Sub(t.lastPrune) > clientLimiterTTL {
+		for k, c := range t.clients {
+			if now.Sub(c.lastSeen) > clientLimiterTTL {
+				delete(t.clients, k)
+			}
+		}
+		t.lastPrune = now
+	}
+
+	c, found := t.clients[client]
+	if !found {
+		c = &clientLimiter{limiter: rate.NewLimiter(t.limit, t.burst)}
+		t.clients[client] = c
+	}
+	c.lastSeen = now
+
+	return c.limiter.AllowN(now, 1)
+}
+
+// acquire reserves a slot for a new request. It returns false when the
+// maximum number of concurrent requests is already reached.
+func (t *throttler) acquire() bool {
+	if t.sem == nil {
+		return true
+	}
+
+	select {
+	case t.sem <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *throttler) release() {
+	if t.sem == nil {
+		return
+	}
+
+	<-t.sem
+}
+
+// clientAddress returns the host of the client which sent the request.
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// clientID identifies the client which sent the request without reading its
+// body: the subject of the TLS client certificate when the API server
+// authenticates to the webhook, the address of the client otherwise.
+func clientID(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return r.TLS.PeerCertificates[0].Subject.String()
+	}
+
+	return clientAddress(r)
+}
+
+// throttle wraps the handler to reject with a 429 status the requests
+// exceeding the rate limit of their client or the maximum number of
+// concurrent requests. The requests are rejected before their body is read.
+func (a *Admission) throttle(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client := clientID(r)
+
+		if !a.throttler.allow(client, time.Now()) {
+			a.throttledRequestsCounter.WithLabelValues(throttleReasonRateLimit).Inc()
+			level.Warn(a.logger).Log("msg", "Request rejected by the rate limiter", "client", client)
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "too many requests from the client", http.StatusTooManyRequests)
+			return
+		}
+
+		if !a.throttler.acquire() {
+			a.throttledRequestsCounter.WithLabelValues(throttleReasonConcurrency).Inc()
+			level.Warn(a.logger).Log("msg", "Request rejected because too many requests are in flight", "client", client)
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "too many concurrent requests", http.StatusTooManyRequests)
+			return
+		}
+		defer a.throttler.release()
+
+		a.inFlightRequestsGauge.Inc()
+		defer a.inFlightRequestsGauge.Dec()
+
+		h(w, r)
+	}
+}
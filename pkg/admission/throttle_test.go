@@ -0,0 +1,169 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestThrottlerAllow(t *testing.T) {
+	now := time.Now()
+
+	for _, tc := range []struct {
+		name     string
+		limit    float64
+		burst    int
+		requests []time.Duration
+		expected []bool
+	}{
+		{
+			name:     "no limit",
+			requests: []time.Duration{0, 0, 0},
+			expected: []bool{true, true, true},
+		},
+		{
+			name:     "burst exceeded",
+			limit:    1,
+			burst:    2,
+			requests: []time.Duration{0, 0, 0},
+			expected: []bool{true, true, false},
+		},
+		{
+			name:     "tokens replenished",
+			limit:    1,
+			burst:    1,
+			requests: []time.Duration{0, 0, time.Second},
+			expected: []bool{true, false, true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			th := newThrottler(0, tc.limit, tc.burst)
+
+			for i, d := range tc.requests {
+				if got := th.allow("10.0.0.1", now.Add(d)); got != tc.expected[i] {
+					t.Fatalf("request %d: expected %t, got %t", i, tc.expected[i], got)
+				}
+			}
+
+			// Other clients have their own budget.
+			if !th.allow("10.0.0.2", now) {
+				t.Fatal("expected request from another client to be allowed")
+			}
+		})
+	}
+}
+
+func TestThrottleConcurrentRequests(t *testing.T) {
+	a := api()
+	a.throttler = newThrottler(1, 0, 0)
+
+	started, done := make(chan struct{}), make(chan struct{})
+	ts := httptest.NewServer(a.throttle(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-done
+	}))
+	t.Cleanup(ts.Close)
+
+	errc := make(chan error, 1)
+	go func() {
+		resp, err := http.Get(ts.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		errc <- err
+	}()
+	<-started
+
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, resp.StatusCode)
+	}
+
+	if v := testutil.ToFloat64(a.throttledRequestsCounter.WithLabelValues(throttleReasonConcurrency)); v != 1 {
+		t.Fatalf("expected 1 throttled request, got %v", v)
+	}
+
+	if v := testutil.ToFloat64(a.inFlightRequestsGauge); v != 1 {
+		t.Fatalf("expected 1 in-flight request, got %v", v)
+	}
+
+	close(done)
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRateLimitByClient(t *testing.T) {
+	a := api()
+	a.throttler = newThrottler(0, 0.001, 1)
+
+	var served int
+	h := a.throttle(func(w http.ResponseWriter, r *http.Request) {
+		served++
+	})
+
+	peer := func(cn string) *tls.ConnectionState {
+		return &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: cn}}},
+		}
+	}
+
+	// All the requests come from the same address like the requests sent by
+	// different API servers behind a load balancer.
+	for i, tc := range []struct {
+		tls      *tls.ConnectionState
+		expected int
+	}{
+		{tls: peer("apiserver-a"), expected: http.StatusOK},
+		{tls: peer("apiserver-a"), expected: http.StatusTooManyRequests},
+		{tls: peer("apiserver-b"), expected: http.StatusOK},
+		{tls: peer("apiserver-b"), expected: http.StatusTooManyRequests},
+		{expected: http.StatusOK},
+		{expected: http.StatusTooManyRequests},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/admission-prometheusrules/validate", nil)
+		req.RemoteAddr = "10.0.0.1:8443"
+		req.TLS = tc.tls
+
+		w := httptest.NewRecorder()
+		h(w, req)
+
+		if w.Code != tc.expected {
+			t.Fatalf("request %d from %q: expected status %d, got %d", i, clientID(req), tc.expected, w.Code)
+		}
+	}
+
+	// The rejected requests never reach the handler.
+	if served != 3 {
+		t.Fatalf("expected 3 served requests, got %d", served)
+	}
+
+	if v := testutil.ToFloat64(a.throttledRequestsCounter.WithLabelValues(throttleReasonRateLimit)); v != 3 {
+		t.Fatalf("expected 3 throttled requests, got %v", v)
+	}
+}
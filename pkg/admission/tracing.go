@@ -0,0 +1,124 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	v1 "k8s.io/api/admission/v1"
+)
+
+const tracerName = "github.com/prometheus-operator/prometheus-operator/pkg/admission"
+
+// NewTracerProvider returns a tracer provider exporting the spans to the
+// OTLP/HTTP endpoint of a collector (e.g.
+// 'http://otel-collector:4318/v1/traces'). The caller must shut the provider
+// down to flush the pending spans.
+func NewTracerProvider(ctx context.Context, serviceName, endpoint string, samplingRatio float64) (*sdktrace.TracerProvider, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracing endpoint: %w", err)
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host)}
+	switch u.Scheme {
+	case "http":
+		opts = append(opts, otlptracehttp.WithInsecure())
+	case "https":
+	default:
+		return nil, fmt.Errorf("invalid tracing endpoint %q: the scheme must be http or https", endpoint)
+	}
+	if u.Path != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(samplingRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	), nil
+}
+
+func (a *Admission) tracer() trace.Tracer {
+	if a.config.TracerProvider == nil {
+		return trace.NewNoopTracerProvider().Tracer(tracerName)
+	}
+
+	return a.config.TracerProvider.Tracer(tracerName)
+}
+
+// startRequestSpan starts the span of an incoming request. The span is a
+// child of the W3C trace context propagated in the traceparent header, if
+// any.
+func (a *Admission) startRequestSpan(r *http.Request) (trace.Span, context.Context) {
+	// A missing or invalid trace context starts a new trace.
+	ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := a.tracer().Start(ctx, r.URL.Path,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(r.Method),
+			semconv.HTTPURLKey.String(r.URL.String()),
+		),
+	)
+
+	return span, ctx
+}
+
+// startSpan starts the span of an admission phase.
+func (a *Admission) startSpan(ctx context.Context, operationName string) (trace.Span, context.Context) {
+	ctx, span := a.tracer().Start(ctx, operationName)
+	return span, ctx
+}
+
+// recordError marks the span as failed.
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// setAdmissionAttributes records the attributes of the admission request and
+// the decision on the span.
+func setAdmissionAttributes(span trace.Span, req *v1.AdmissionRequest, resp *v1.AdmissionResponse) {
+	if req != nil {
+		span.SetAttributes(
+			attribute.String("admission.uid", string(req.UID)),
+			attribute.String("admission.namespace", req.Namespace),
+			attribute.String("admission.name", req.Name),
+			attribute.String("admission.operation", string(req.Operation)),
+			attribute.String("admission.resource", req.Resource.Resource),
+		)
+	}
+
+	span.SetAttributes(attribute.Bool("admission.allowed", resp.Allowed))
+	if resp.Result != nil {
+		span.SetAttributes(attribute.String("admission.reason", string(resp.Result.Reason)))
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	a := api()
+	a.config.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	// Trace context propagated by the API server.
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+	req := httptest.NewRequest(http.MethodPost, prometheusRuleMutatePath, bytes.NewReader(goodRulesWithAnnotations))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentSpanID+"-01")
+
+	w := httptest.NewRecorder()
+	a.servePrometheusRulesMutate(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	var names []string
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+		names = append(names, s.Name())
+	}
+
+	expected := []string{"decode", "generate-patches", "mutate", prometheusRuleMutatePath}
+	if !reflect.DeepEqual(expected, names) {
+		t.Fatalf("expected spans %v, got %v", expected, names)
+	}
+
+	root := spans[prometheusRuleMutatePath]
+	if got := root.SpanContext().TraceID().String(); got != traceID {
+		t.Fatalf("expected the request span to belong to the propagated trace %s, got %s", traceID, got)
+	}
+	if got := root.Parent().SpanID().String(); got != parentSpanID {
+		t.Fatalf("expected the request span to be a child of the propagated span %s, got %s", parentSpanID, got)
+	}
+	if root.SpanKind() != trace.SpanKindServer {
+		t.Fatalf("expected the request span to be a server span, got %v", root.SpanKind())
+	}
+
+	for child, parent := range map[string]string{
+		"decode":           prometheusRuleMutatePath,
+		"mutate":           prometheusRuleMutatePath,
+		"generate-patches": "mutate",
+	} {
+		if spans[child].Parent().SpanID() != spans[parent].SpanContext().SpanID() {
+			t.Fatalf("expected span %q to be a child of %q", child, parent)
+		}
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range root.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+
+	if allowed := attrs["admission.allowed"]; !allowed.AsBool() {
+		t.Fatalf("expected admission.allowed attribute to be true, got %v", allowed.Emit())
+	}
+
+	if uid := attrs["admission.uid"].AsString(); uid != "87c5df7f-5090-11e9-b9b4-02425473f309" {
+		t.Fatalf("unexpected admission.uid attribute %v", uid)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"net"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,7 +32,9 @@ import (
 	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -60,20 +63,29 @@ func (c alertmanagerConfig) String() string {
 }
 
 type configGenerator struct {
-	logger    log.Logger
-	amVersion semver.Version
-	store     *assets.Store
+	logger          log.Logger
+	amVersion       semver.Version
+	store           *assets.Store
+	matcherStrategy monitoringv1.AlertmanagerConfigMatcherStrategy
 }
 
-func newConfigGenerator(logger log.Logger, amVersion semver.Version, store *assets.Store) *configGenerator {
+func newConfigGenerator(logger log.Logger, amVersion semver.Version, store *assets.Store, matcherStrategy monitoringv1.AlertmanagerConfigMatcherStrategy) *configGenerator {
 	cg := &configGenerator{
-		logger:    logger,
-		amVersion: amVersion,
-		store:     store,
+		logger:          logger,
+		amVersion:       amVersion,
+		store:           store,
+		matcherStrategy: matcherStrategy,
 	}
 	return cg
 }
 
+// enforceNamespaceMatcher returns true if the routes and inhibition rules of
+// the AlertmanagerConfig resources should only match alerts from the
+// resource's namespace.
+func (cg *configGenerator) enforceNamespaceMatcher() bool {
+	return cg.matcherStrategy.Type != monitoringv1.NoneConfigMatcherStrategyType
+}
+
 func (cg *configGenerator) generateConfig(
 	ctx context.Context,
 	baseConfig alertmanagerConfig,
@@ -97,9 +109,11 @@ func (cg *configGenerator) generateConfig(
 			Namespace: amConfigs[amConfigIdentifier].Namespace,
 		}
 
-		// Add inhibitRules to baseConfig.InhibitRules.
+		// Add inhibitRules to baseConfig.InhibitRules. The cross-namespace
+		// rules have already been checked when selecting the resources.
 		for _, inhibitRule := range amConfigs[amConfigIdentifier].Spec.InhibitRules {
-			baseConfig.InhibitRules = append(baseConfig.InhibitRules, convertInhibitRule(&inhibitRule, crKey))
+			namespaced := cg.enforceNamespaceMatcher() && !inhibitRule.CrossNamespace
+			baseConfig.InhibitRules = append(baseConfig.InhibitRules, convertInhibitRule(&inhibitRule, crKey, namespaced))
 		}
 
 		// Skip early if there's no route definition.
@@ -107,7 +121,7 @@ func (cg *configGenerator) generateConfig(
 			continue
 		}
 
-		subRoutes = append(subRoutes, convertRoute(amConfigs[amConfigIdentifier].Spec.Route, crKey, true))
+		subRoutes = append(subRoutes, convertRoute(amConfigs[amConfigIdentifier].Spec.Route, crKey, true, cg.enforceNamespaceMatcher()))
 
 		for _, receiver := range amConfigs[amConfigIdentifier].Spec.Receivers {
 			receivers, err := cg.convertReceiver(ctx, &receiver, crKey)
@@ -116,6 +130,10 @@ func (cg *configGenerator) generateConfig(
 			}
 			baseConfig.Receivers = append(baseConfig.Receivers, receivers)
 		}
+
+		for _, mti := range amConfigs[amConfigIdentifier].Spec.MuteTimeIntervals {
+			baseConfig.TimeIntervals = append(baseConfig.TimeIntervals, convertMuteTimeInterval(&mti, crKey))
+		}
 	}
 
 	// For alerts to be processed by the AlertmanagerConfig routes, they need
@@ -131,7 +149,134 @@ func (cg *configGenerator) generateConfig(
 	return yaml.Marshal(generatedConf)
 }
 
-func convertRoute(in *monitoringv1alpha1.Route, crKey types.NamespacedName, firstLevelRoute bool) *route {
+// invalidConfigError is returned when the generated configuration is rejected
+// by the Alertmanager configuration parser.
+type invalidConfigError struct {
+	// Description of the source which makes the configuration invalid (e.g.
+	// "AlertmanagerConfig <namespace>/<name>").
+	source string
+	err    error
+}
+
+func (e *invalidConfigError) Error() string {
+	return fmt.Sprintf("%s: %v", e.source, e.err)
+}
+
+// canValidateConfig returns true when the generated configuration can be
+// checked by the Alertmanager configuration parser vendored by the operator
+// (v0.23). The parser doesn't know about the fields introduced by newer
+// Alertmanager versions.
+func (cg *configGenerator) canValidateConfig() bool {
+	return cg.amVersion.LT(semver.MustParse("0.24.0"))
+}
+
+// checkGeneratedConfig loads the generated configuration with the
+// Alertmanager configuration parser which runs the same checks as `amtool
+// check-config`. When the configuration is rejected, the base configuration
+// and the AlertmanagerConfig resources (in alphabetical order) are merged in
+// isolation to find the source of the error.
+//
+// The configuration isn't checked when canValidateConfig returns false.
+func (cg *configGenerator) checkGeneratedConfig(
+	ctx context.Context,
+	generatedConfig []byte,
+	rawBaseConfig []byte,
+	baseSource string,
+	amConfigs map[string]*monitoringv1alpha1.AlertmanagerConfig,
+) error {
+	if !cg.canValidateConfig() {
+		return nil
+	}
+
+	_, err := config.Load(string(generatedConfig))
+	if err == nil {
+		return nil
+	}
+
+	check := func(amConfigs map[string]*monitoringv1alpha1.AlertmanagerConfig) error {
+		var baseConfig alertmanagerConfig
+		if err := yaml.UnmarshalStrict(rawBaseConfig, &baseConfig); err != nil {
+			return err
+		}
+
+		b, err := cg.generateConfig(ctx, baseConfig, amConfigs)
+		if err != nil {
+			return err
+		}
+
+		_, err = config.Load(string(b))
+		return err
+	}
+
+	if err := check(nil); err != nil {
+		return &invalidConfigError{source: baseSource, err: err}
+	}
+
+	keys := make([]string, 0, len(amConfigs))
+	for k := range amConfigs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := check(map[string]*monitoringv1alpha1.AlertmanagerConfig{k: amConfigs[k]}); err != nil {
+			return &invalidConfigError{source: fmt.Sprintf("%s %s", monitoringv1alpha1.AlertmanagerConfigKind, k), err: err}
+		}
+	}
+
+	// All the sources are valid on their own.
+	return &invalidConfigError{source: "merged configuration", err: err}
+}
+
+// generateBaseConfig returns the Alertmanager configuration whose top-level
+// route, receivers and inhibition rules are defined by the global
+// AlertmanagerConfig resource. Contrary to the other AlertmanagerConfig
+// resources, no namespace matcher is enforced.
+func (cg *configGenerator) generateBaseConfig(
+	ctx context.Context,
+	amConfig *monitoringv1alpha1.AlertmanagerConfig,
+	global *monitoringv1.AlertmanagerGlobalConfig,
+) (*alertmanagerConfig, error) {
+	crKey := types.NamespacedName{
+		Name:      amConfig.Name,
+		Namespace: amConfig.Namespace,
+	}
+
+	baseConfig := &alertmanagerConfig{
+		Route: convertRoute(amConfig.Spec.Route, crKey, false, false),
+	}
+
+	if global != nil && global.ResolveTimeout != "" {
+		resolveTimeout, err := model.ParseDuration(global.ResolveTimeout)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid resolveTimeout")
+		}
+		baseConfig.Global = &globalConfig{ResolveTimeout: &resolveTimeout}
+	}
+
+	for _, inhibitRule := range amConfig.Spec.InhibitRules {
+		baseConfig.InhibitRules = append(baseConfig.InhibitRules, convertInhibitRule(&inhibitRule, crKey, false))
+	}
+
+	for _, receiver := range amConfig.Spec.Receivers {
+		receivers, err := cg.convertReceiver(ctx, &receiver, crKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "AlertmanagerConfig %s", crKey.String())
+		}
+		baseConfig.Receivers = append(baseConfig.Receivers, receivers)
+	}
+
+	for _, mti := range amConfig.Spec.MuteTimeIntervals {
+		baseConfig.TimeIntervals = append(baseConfig.TimeIntervals, convertMuteTimeInterval(&mti, crKey))
+	}
+
+	return baseConfig, nil
+}
+
+// convertRoute converts a monitoringv1alpha1.Route to an alertmanager.route.
+// When namespaced is true, the first-level route is restricted to the
+// namespace of the AlertmanagerConfig resource.
+func convertRoute(in *monitoringv1alpha1.Route, crKey types.NamespacedName, firstLevelRoute bool, namespaced bool) *route {
 	// Enforce "continue" to be true for the top-level route.
 	cont := in.Continue
 	if firstLevelRoute {
@@ -148,7 +293,7 @@ func convertRoute(in *monitoringv1alpha1.Route, crKey types.NamespacedName, firs
 			match[matcher.Name] = matcher.Value
 		}
 	}
-	if firstLevelRoute {
+	if firstLevelRoute && namespaced {
 		match["namespace"] = crKey.Namespace
 		delete(matchRE, "namespace")
 	}
@@ -173,33 +318,41 @@ func convertRoute(in *monitoringv1alpha1.Route, crKey types.NamespacedName, firs
 			panic(err)
 		}
 		for i := range children {
-			routes[i] = convertRoute(&children[i], crKey, false)
+			routes[i] = convertRoute(&children[i], crKey, false, false)
 		}
 	}
 
-	receiver := prefixReceiverName(in.Receiver, crKey)
+	receiver := makeNamespacedString(in.Receiver, crKey)
 
 	return &route{
-		Receiver:       receiver,
-		GroupByStr:     in.GroupBy,
-		GroupWait:      in.GroupWait,
-		GroupInterval:  in.GroupInterval,
-		RepeatInterval: in.RepeatInterval,
-		Continue:       cont,
-		Match:          match,
-		MatchRE:        matchRE,
-		Routes:         routes,
+		Receiver:            receiver,
+		GroupByStr:          in.GroupBy,
+		GroupWait:           in.GroupWait,
+		GroupInterval:       in.GroupInterval,
+		RepeatInterval:      in.RepeatInterval,
+		Continue:            cont,
+		Match:               match,
+		MatchRE:             matchRE,
+		Routes:              routes,
+		MuteTimeIntervals:   makeNamespacedStrings(in.MuteTimeIntervals, crKey),
+		ActiveTimeIntervals: makeNamespacedStrings(in.ActiveTimeIntervals, crKey),
 	}
 }
 
 // convertReceiver converts a monitoringv1alpha1.Receiver to an alertmanager.receiver
 func (cg *configGenerator) convertReceiver(ctx context.Context, in *monitoringv1alpha1.Receiver, crKey types.NamespacedName) (*receiver, error) {
+	// The credentials of the receiver may be stored in another namespace.
+	secretKey := crKey
+	if in.SecretNamespace != "" {
+		secretKey.Namespace = in.SecretNamespace
+	}
+
 	var pagerdutyConfigs []*pagerdutyConfig
 
 	if l := len(in.PagerDutyConfigs); l > 0 {
 		pagerdutyConfigs = make([]*pagerdutyConfig, l)
 		for i := range in.PagerDutyConfigs {
-			receiver, err := cg.convertPagerdutyConfig(ctx, in.PagerDutyConfigs[i], crKey)
+			receiver, err := cg.convertPagerdutyConfig(ctx, in.PagerDutyConfigs[i], secretKey)
 			if err != nil {
 				return nil, errors.Wrapf(err, "PagerDutyConfig[%d]", i)
 			}
@@ -211,7 +364,7 @@ func (cg *configGenerator) convertReceiver(ctx context.Context, in *monitoringv1
 	if l := len(in.SlackConfigs); l > 0 {
 		slackConfigs = make([]*slackConfig, l)
 		for i := range in.SlackConfigs {
-			receiver, err := cg.convertSlackConfig(ctx, in.SlackConfigs[i], crKey)
+			receiver, err := cg.convertSlackConfig(ctx, in.SlackConfigs[i], secretKey)
 			if err != nil {
 				return nil, errors.Wrapf(err, "SlackConfig[%d]", i)
 			}
@@ -223,7 +376,7 @@ func (cg *configGenerator) convertReceiver(ctx context.Context, in *monitoringv1
 	if l := len(in.WebhookConfigs); l > 0 {
 		webhookConfigs = make([]*webhookConfig, l)
 		for i := range in.WebhookConfigs {
-			receiver, err := cg.convertWebhookConfig(ctx, in.WebhookConfigs[i], crKey)
+			receiver, err := cg.convertWebhookConfig(ctx, in.WebhookConfigs[i], secretKey)
 			if err != nil {
 				return nil, errors.Wrapf(err, "WebhookConfig[%d]", i)
 			}
@@ -235,7 +388,7 @@ func (cg *configGenerator) convertReceiver(ctx context.Context, in *monitoringv1
 	if l := len(in.OpsGenieConfigs); l > 0 {
 		opsgenieConfigs = make([]*opsgenieConfig, l)
 		for i := range in.OpsGenieConfigs {
-			receiver, err := cg.convertOpsgenieConfig(ctx, in.OpsGenieConfigs[i], crKey)
+			receiver, err := cg.convertOpsgenieConfig(ctx, in.OpsGenieConfigs[i], secretKey)
 			if err != nil {
 				return nil, errors.Wrapf(err, "OpsGenieConfigs[%d]", i)
 			}
@@ -247,7 +400,7 @@ func (cg *configGenerator) convertReceiver(ctx context.Context, in *monitoringv1
 	if l := len(in.WeChatConfigs); l > 0 {
 		weChatConfigs = make([]*weChatConfig, l)
 		for i := range in.WeChatConfigs {
-			receiver, err := cg.convertWeChatConfig(ctx, in.WeChatConfigs[i], crKey)
+			receiver, err := cg.convertWeChatConfig(ctx, in.WeChatConfigs[i], secretKey)
 			if err != nil {
 				return nil, errors.Wrapf(err, "WeChatConfig[%d]", i)
 			}
@@ -259,7 +412,7 @@ func (cg *configGenerator) convertReceiver(ctx context.Context, in *monitoringv1
 	if l := len(in.EmailConfigs); l > 0 {
 		emailConfigs = make([]*emailConfig, l)
 		for i := range in.EmailConfigs {
-			receiver, err := cg.convertEmailConfig(ctx, in.EmailConfigs[i], crKey)
+			receiver, err := cg.convertEmailConfig(ctx, in.EmailConfigs[i], secretKey)
 			if err != nil {
 				return nil, errors.Wrapf(err, "EmailConfig[%d]", i)
 			}
@@ -271,7 +424,7 @@ func (cg *configGenerator) convertReceiver(ctx context.Context, in *monitoringv1
 	if l := len(in.VictorOpsConfigs); l > 0 {
 		victorOpsConfigs = make([]*victorOpsConfig, l)
 		for i := range in.VictorOpsConfigs {
-			receiver, err := cg.convertVictorOpsConfig(ctx, in.VictorOpsConfigs[i], crKey)
+			receiver, err := cg.convertVictorOpsConfig(ctx, in.VictorOpsConfigs[i], secretKey)
 			if err != nil {
 				return nil, errors.Wrapf(err, "VictorOpsConfig[%d]", i)
 			}
@@ -283,7 +436,7 @@ func (cg *configGenerator) convertReceiver(ctx context.Context, in *monitoringv1
 	if l := len(in.PushoverConfigs); l > 0 {
 		pushoverConfigs = make([]*pushoverConfig, l)
 		for i := range in.PushoverConfigs {
-			receiver, err := cg.convertPushoverConfig(ctx, in.PushoverConfigs[i], crKey)
+			receiver, err := cg.convertPushoverConfig(ctx, in.PushoverConfigs[i], secretKey)
 			if err != nil {
 				return nil, errors.Wrapf(err, "PushoverConfig[%d]", i)
 			}
@@ -291,8 +444,80 @@ func (cg *configGenerator) convertReceiver(ctx context.Context, in *monitoringv1
 		}
 	}
 
+	var telegramConfigs []*telegramConfig
+	if l := len(in.TelegramConfigs); l > 0 {
+		telegramConfigs = make([]*telegramConfig, l)
+		for i := range in.TelegramConfigs {
+			receiver, err := cg.convertTelegramConfig(ctx, in.TelegramConfigs[i], secretKey)
+			if err != nil {
+				return nil, errors.Wrapf(err, "TelegramConfig[%d]", i)
+			}
+			telegramConfigs[i] = receiver
+		}
+	}
+
+	var discordConfigs []*discordConfig
+	if l := len(in.DiscordConfigs); l > 0 {
+		discordConfigs = make([]*discordConfig, l)
+		for i := range in.DiscordConfigs {
+			receiver, err := cg.convertDiscordConfig(ctx, in.DiscordConfigs[i], secretKey)
+			if err != nil {
+				return nil, errors.Wrapf(err, "DiscordConfig[%d]", i)
+			}
+			discordConfigs[i] = receiver
+		}
+	}
+
+	var msTeamsConfigs []*msTeamsConfig
+	if l := len(in.MSTeamsConfigs); l > 0 {
+		msTeamsConfigs = make([]*msTeamsConfig, l)
+		for i := range in.MSTeamsConfigs {
+			receiver, err := cg.convertMSTeamsConfig(ctx, in.MSTeamsConfigs[i], secretKey)
+			if err != nil {
+				return nil, errors.Wrapf(err, "MSTeamsConfig[%d]", i)
+			}
+			msTeamsConfigs[i] = receiver
+		}
+	}
+
+	var msTeamsV2Configs []*msTeamsV2Config
+	if l := len(in.MSTeamsV2Configs); l > 0 {
+		msTeamsV2Configs = make([]*msTeamsV2Config, l)
+		for i := range in.MSTeamsV2Configs {
+			receiver, err := cg.convertMSTeamsV2Config(ctx, in.MSTeamsV2Configs[i], secretKey)
+			if err != nil {
+				return nil, errors.Wrapf(err, "MSTeamsV2Config[%d]", i)
+			}
+			msTeamsV2Configs[i] = receiver
+		}
+	}
+
+	var snsConfigs []*snsConfig
+	if l := len(in.SNSConfigs); l > 0 {
+		snsConfigs = make([]*snsConfig, l)
+		for i := range in.SNSConfigs {
+			receiver, err := cg.convertSNSConfig(ctx, in.SNSConfigs[i], secretKey)
+			if err != nil {
+				return nil, errors.Wrapf(err, "SNSConfig[%d]", i)
+			}
+			snsConfigs[i] = receiver
+		}
+	}
+
+	var webexConfigs []*webexConfig
+	if l := len(in.WebexConfigs); l > 0 {
+		webexConfigs = make([]*webexConfig, l)
+		for i := range in.WebexConfigs {
+			receiver, err := cg.convertWebexConfig(ctx, in.WebexConfigs[i], secretKey)
+			if err != nil {
+				return nil, errors.Wrapf(err, "WebexConfig[%d]", i)
+			}
+			webexConfigs[i] = receiver
+		}
+	}
+
 	return &receiver{
-		Name:             prefixReceiverName(in.Name, crKey),
+		Name:             makeNamespacedString(in.Name, crKey),
 		OpsgenieConfigs:  opsgenieConfigs,
 		PagerdutyConfigs: pagerdutyConfigs,
 		SlackConfigs:     slackConfigs,
@@ -301,6 +526,12 @@ func (cg *configGenerator) convertReceiver(ctx context.Context, in *monitoringv1
 		EmailConfigs:     emailConfigs,
 		VictorOpsConfigs: victorOpsConfigs,
 		PushoverConfigs:  pushoverConfigs,
+		TelegramConfigs:  telegramConfigs,
+		DiscordConfigs:   discordConfigs,
+		MSTeamsConfigs:   msTeamsConfigs,
+		MSTeamsV2Configs: msTeamsV2Configs,
+		SNSConfigs:       snsConfigs,
+		WebexConfigs:     webexConfigs,
 	}, nil
 }
 
@@ -711,7 +942,217 @@ func (cg *configGenerator) convertPushoverConfig(ctx context.Context, in monitor
 	return out, nil
 }
 
-func convertInhibitRule(in *monitoringv1alpha1.InhibitRule, crKey types.NamespacedName) *inhibitRule {
+func (cg *configGenerator) convertTelegramConfig(ctx context.Context, in monitoringv1alpha1.TelegramConfig, crKey types.NamespacedName) (*telegramConfig, error) {
+	out := &telegramConfig{
+		VSendResolved: in.SendResolved,
+		APIUrl:        in.APIURL,
+		ChatID:        in.ChatID,
+		Message:       in.Message,
+		ParseMode:     in.ParseMode,
+	}
+
+	if in.DisableNotifications != nil {
+		out.DisableNotifications = *in.DisableNotifications
+	}
+
+	{
+		botToken, err := cg.store.GetSecretKey(ctx, crKey.Namespace, *in.BotToken)
+		if err != nil {
+			return nil, errors.Errorf("failed to get secret %q", in.BotToken)
+		}
+		if botToken == "" {
+			return nil, errors.Errorf("mandatory field %q is empty", "botToken")
+		}
+		out.BotToken = botToken
+	}
+
+	if in.HTTPConfig != nil {
+		httpConfig, err := cg.convertHTTPConfig(ctx, *in.HTTPConfig, crKey)
+		if err != nil {
+			return nil, err
+		}
+		out.HTTPConfig = httpConfig
+	}
+
+	return out, nil
+}
+
+func (cg *configGenerator) convertDiscordConfig(ctx context.Context, in monitoringv1alpha1.DiscordConfig, crKey types.NamespacedName) (*discordConfig, error) {
+	out := &discordConfig{
+		VSendResolved: in.SendResolved,
+		Title:         in.Title,
+		Message:       in.Message,
+	}
+
+	{
+		webhookURL, err := cg.store.GetSecretKey(ctx, crKey.Namespace, *in.APIURL)
+		if err != nil {
+			return nil, errors.Errorf("failed to get secret %q", in.APIURL)
+		}
+		if webhookURL == "" {
+			return nil, errors.Errorf("mandatory field %q is empty", "apiURL")
+		}
+		out.WebhookURL = webhookURL
+	}
+
+	if in.HTTPConfig != nil {
+		httpConfig, err := cg.convertHTTPConfig(ctx, *in.HTTPConfig, crKey)
+		if err != nil {
+			return nil, err
+		}
+		out.HTTPConfig = httpConfig
+	}
+
+	return out, nil
+}
+
+func (cg *configGenerator) convertMSTeamsConfig(ctx context.Context, in monitoringv1alpha1.MSTeamsConfig, crKey types.NamespacedName) (*msTeamsConfig, error) {
+	out := &msTeamsConfig{
+		VSendResolved: in.SendResolved,
+		Title:         in.Title,
+		Summary:       in.Summary,
+		Text:          in.Text,
+	}
+
+	webhookURL, err := cg.getWebhookURL(ctx, in.WebhookURL, crKey)
+	if err != nil {
+		return nil, err
+	}
+	out.WebhookURL = webhookURL
+
+	if in.HTTPConfig != nil {
+		httpConfig, err := cg.convertHTTPConfig(ctx, *in.HTTPConfig, crKey)
+		if err != nil {
+			return nil, err
+		}
+		out.HTTPConfig = httpConfig
+	}
+
+	return out, nil
+}
+
+func (cg *configGenerator) convertMSTeamsV2Config(ctx context.Context, in monitoringv1alpha1.MSTeamsV2Config, crKey types.NamespacedName) (*msTeamsV2Config, error) {
+	out := &msTeamsV2Config{
+		VSendResolved: in.SendResolved,
+		Title:         in.Title,
+		Text:          in.Text,
+	}
+
+	webhookURL, err := cg.getWebhookURL(ctx, in.WebhookURL, crKey)
+	if err != nil {
+		return nil, err
+	}
+	out.WebhookURL = webhookURL
+
+	if in.HTTPConfig != nil {
+		httpConfig, err := cg.convertHTTPConfig(ctx, *in.HTTPConfig, crKey)
+		if err != nil {
+			return nil, err
+		}
+		out.HTTPConfig = httpConfig
+	}
+
+	return out, nil
+}
+
+func (cg *configGenerator) convertSNSConfig(ctx context.Context, in monitoringv1alpha1.SNSConfig, crKey types.NamespacedName) (*snsConfig, error) {
+	out := &snsConfig{
+		VSendResolved: in.SendResolved,
+		APIUrl:        in.APIURL,
+		TopicARN:      in.TopicARN,
+		PhoneNumber:   in.PhoneNumber,
+		TargetARN:     in.TargetARN,
+		Subject:       in.Subject,
+		Message:       in.Message,
+		Attributes:    in.Attributes,
+	}
+
+	if in.Sigv4 != nil {
+		out.Sigv4 = &sigV4Config{
+			Region:  in.Sigv4.Region,
+			Profile: in.Sigv4.Profile,
+			RoleARN: in.Sigv4.RoleArn,
+		}
+
+		if in.Sigv4.AccessKey != nil && in.Sigv4.SecretKey != nil {
+			accessKey, err := cg.store.GetSecretKey(ctx, crKey.Namespace, *in.Sigv4.AccessKey)
+			if err != nil {
+				return nil, errors.Errorf("failed to get access key %q", in.Sigv4.AccessKey)
+			}
+
+			secretKey, err := cg.store.GetSecretKey(ctx, crKey.Namespace, *in.Sigv4.SecretKey)
+			if err != nil {
+				return nil, errors.Errorf("failed to get secret key %q", in.Sigv4.SecretKey)
+			}
+
+			out.Sigv4.AccessKey = accessKey
+			out.Sigv4.SecretKey = secretKey
+		}
+	}
+
+	if in.HTTPConfig != nil {
+		httpConfig, err := cg.convertHTTPConfig(ctx, *in.HTTPConfig, crKey)
+		if err != nil {
+			return nil, err
+		}
+		out.HTTPConfig = httpConfig
+	}
+
+	return out, nil
+}
+
+func (cg *configGenerator) convertWebexConfig(ctx context.Context, in monitoringv1alpha1.WebexConfig, crKey types.NamespacedName) (*webexConfig, error) {
+	out := &webexConfig{
+		VSendResolved: in.SendResolved,
+		APIURL:        in.APIURL,
+		RoomID:        in.RoomID,
+		Message:       in.Message,
+	}
+
+	out.HTTPConfig = &httpClientConfig{}
+	if in.HTTPConfig != nil {
+		httpConfig, err := cg.convertHTTPConfig(ctx, *in.HTTPConfig, crKey)
+		if err != nil {
+			return nil, err
+		}
+		out.HTTPConfig = httpConfig
+	}
+
+	// Webex authenticates the bot with a bearer token in the Authorization
+	// header.
+	botToken, err := cg.store.GetSecretKey(ctx, crKey.Namespace, *in.BotToken)
+	if err != nil {
+		return nil, errors.Errorf("failed to get secret %q", in.BotToken)
+	}
+	if botToken == "" {
+		return nil, errors.Errorf("mandatory field %q is empty", "botToken")
+	}
+	out.HTTPConfig.Authorization = &authorization{
+		Type:        "Bearer",
+		Credentials: botToken,
+	}
+
+	return out, nil
+}
+
+// getWebhookURL returns the (mandatory) webhook URL referenced by the secret
+// key selector.
+func (cg *configGenerator) getWebhookURL(ctx context.Context, sel *corev1.SecretKeySelector, crKey types.NamespacedName) (string, error) {
+	webhookURL, err := cg.store.GetSecretKey(ctx, crKey.Namespace, *sel)
+	if err != nil {
+		return "", errors.Errorf("failed to get secret %q", sel)
+	}
+	if webhookURL == "" {
+		return "", errors.Errorf("mandatory field %q is empty", "webhookUrl")
+	}
+
+	return webhookURL, nil
+}
+
+// convertInhibitRule converts a monitoringv1alpha1.InhibitRule to an
+// alertmanager.inhibitRule. When namespaced is true, the source and target
+// matchers are restricted to the namespace of the AlertmanagerConfig resource.
+func convertInhibitRule(in *monitoringv1alpha1.InhibitRule, crKey types.NamespacedName, namespaced bool) *inhibitRule {
 	sourceMatch := map[string]string{}
 	sourceMatchRE := map[string]string{}
 	for _, sm := range in.SourceMatch {
@@ -722,10 +1163,15 @@ func convertInhibitRule(in *monitoringv1alpha1.InhibitRule, crKey types.Namespac
 		}
 	}
 
-	sourceMatch["namespace"] = crKey.Namespace
-	delete(sourceMatchRE, "namespace")
+	if namespaced {
+		sourceMatch["namespace"] = crKey.Namespace
+		delete(sourceMatchRE, "namespace")
+	}
 
 	// Set to nil if empty so that it doesn't show up in resulting yaml
+	if len(sourceMatch) == 0 {
+		sourceMatch = nil
+	}
 	if len(sourceMatchRE) == 0 {
 		sourceMatchRE = nil
 	}
@@ -740,10 +1186,15 @@ func convertInhibitRule(in *monitoringv1alpha1.InhibitRule, crKey types.Namespac
 		}
 	}
 
-	targetMatch["namespace"] = crKey.Namespace
-	delete(targetMatchRE, "namespace")
+	if namespaced {
+		targetMatch["namespace"] = crKey.Namespace
+		delete(targetMatchRE, "namespace")
+	}
 
 	// Set to nil if empty so that it doesn't show up in resulting yaml
+	if len(targetMatch) == 0 {
+		targetMatch = nil
+	}
 	if len(targetMatchRE) == 0 {
 		targetMatchRE = nil
 	}
@@ -762,11 +1213,74 @@ func convertInhibitRule(in *monitoringv1alpha1.InhibitRule, crKey types.Namespac
 	}
 }
 
-func prefixReceiverName(receiverName string, crKey types.NamespacedName) string {
-	if receiverName == "" {
+// makeNamespacedString prefixes the name of a receiver or a time interval
+// with the namespace and name of the AlertmanagerConfig resource to avoid
+// collisions between resources.
+func makeNamespacedString(in string, crKey types.NamespacedName) string {
+	if in == "" {
 		return ""
 	}
-	return crKey.Namespace + "-" + crKey.Name + "-" + receiverName
+	return crKey.Namespace + "-" + crKey.Name + "-" + in
+}
+
+func makeNamespacedStrings(in []string, crKey types.NamespacedName) []string {
+	if len(in) == 0 {
+		return nil
+	}
+
+	out := make([]string, len(in))
+	for i := range in {
+		out[i] = makeNamespacedString(in[i], crKey)
+	}
+	return out
+}
+
+func convertMuteTimeInterval(in *monitoringv1alpha1.MuteTimeInterval, crKey types.NamespacedName) *timeInterval {
+	out := &timeInterval{
+		Name:          makeNamespacedString(in.Name, crKey),
+		TimeIntervals: make([]timePeriod, 0, len(in.TimeIntervals)),
+	}
+
+	for _, ti := range in.TimeIntervals {
+		out.TimeIntervals = append(out.TimeIntervals, convertTimeInterval(ti))
+	}
+
+	return out
+}
+
+func convertTimeInterval(in monitoringv1alpha1.TimeInterval) timePeriod {
+	out := timePeriod{
+		Location: in.Location,
+	}
+
+	for _, tr := range in.Times {
+		out.Times = append(out.Times, timeRange{
+			StartTime: string(tr.StartTime),
+			EndTime:   string(tr.EndTime),
+		})
+	}
+
+	for _, wd := range in.Weekdays {
+		out.Weekdays = append(out.Weekdays, strings.ToLower(string(wd)))
+	}
+
+	for _, dm := range in.DaysOfMonth {
+		r := strconv.Itoa(dm.Start)
+		if dm.End != dm.Start {
+			r += ":" + strconv.Itoa(dm.End)
+		}
+		out.DaysOfMonth = append(out.DaysOfMonth, r)
+	}
+
+	for _, m := range in.Months {
+		out.Months = append(out.Months, strings.ToLower(string(m)))
+	}
+
+	for _, y := range in.Years {
+		out.Years = append(out.Years, string(y))
+	}
+
+	return out
 }
 
 func (cg *configGenerator) convertHTTPConfig(ctx context.Context, in monitoringv1alpha1.HTTPConfig, crKey types.NamespacedName) (*httpClientConfig, error) {
@@ -851,7 +1365,9 @@ func (c *alertmanagerConfig) sanitize(amVersion semver.Version, logger log.Logge
 	c.Global.sanitize(amVersion, logger)
 
 	for _, receiver := range c.Receivers {
-		receiver.sanitize(amVersion, logger)
+		if err := receiver.sanitize(amVersion, logger); err != nil {
+			return errors.Wrapf(err, "receiver %q", receiver.Name)
+		}
 	}
 
 	for i, rule := range c.InhibitRules {
@@ -860,10 +1376,39 @@ func (c *alertmanagerConfig) sanitize(amVersion semver.Version, logger log.Logge
 		}
 	}
 
+	// Alertmanager < 0.24.0 only supports the time intervals defined by
+	// mute_time_intervals.
+	if len(c.TimeIntervals) > 0 && amVersion.LT(semver.MustParse("0.24.0")) {
+		c.MuteTimeIntervals = append(c.MuteTimeIntervals, c.TimeIntervals...)
+		c.TimeIntervals = nil
+	}
+
+	if len(c.MuteTimeIntervals) > 0 && amVersion.LT(semver.MustParse("0.22.0")) {
+		return errors.New("mute time intervals are supported in Alertmanager >= 0.22.0 only")
+	}
+
+	for _, ti := range append(c.MuteTimeIntervals, c.TimeIntervals...) {
+		if err := ti.sanitize(amVersion, logger); err != nil {
+			return errors.Wrapf(err, "time interval %q", ti.Name)
+		}
+	}
+
 	err := c.Route.sanitize(amVersion, logger)
 	return err
 }
 
+// sanitize a time interval.
+// Returns an error if the time zone isn't supported by the Alertmanager version.
+func (ti *timeInterval) sanitize(amVersion semver.Version, logger log.Logger) error {
+	for _, tp := range ti.TimeIntervals {
+		if tp.Location != "" && amVersion.LT(semver.MustParse("0.25.0")) {
+			return errors.Errorf("location is supported in Alertmanager >= 0.25.0 only (location=%s)", tp.Location)
+		}
+	}
+
+	return nil
+}
+
 // sanitize globalConfig
 func (gc *globalConfig) sanitize(amVersion semver.Version, logger log.Logger) {
 	if gc == nil {
@@ -916,10 +1461,12 @@ func (hc *httpClientConfig) sanitize(amVersion semver.Version, logger log.Logger
 	}
 }
 
-// sanitize the receiver
-func (r *receiver) sanitize(amVersion semver.Version, logger log.Logger) {
+// sanitize the receiver.
+// Returns an error if the receiver uses an integration which isn't supported
+// by the Alertmanager version.
+func (r *receiver) sanitize(amVersion semver.Version, logger log.Logger) error {
 	if r == nil {
-		return
+		return nil
 	}
 	withLogger := log.With(logger, "receiver", r.Name)
 
@@ -954,6 +1501,44 @@ func (r *receiver) sanitize(amVersion semver.Version, logger log.Logger) {
 	for _, conf := range r.WeChatConfigs {
 		conf.sanitize(amVersion, withLogger)
 	}
+
+	for _, conf := range r.TelegramConfigs {
+		if err := conf.sanitize(amVersion, withLogger); err != nil {
+			return err
+		}
+	}
+
+	for _, conf := range r.DiscordConfigs {
+		if err := conf.sanitize(amVersion, withLogger); err != nil {
+			return err
+		}
+	}
+
+	for _, conf := range r.MSTeamsConfigs {
+		if err := conf.sanitize(amVersion, withLogger); err != nil {
+			return err
+		}
+	}
+
+	for _, conf := range r.MSTeamsV2Configs {
+		if err := conf.sanitize(amVersion, withLogger); err != nil {
+			return err
+		}
+	}
+
+	for _, conf := range r.SNSConfigs {
+		if err := conf.sanitize(amVersion, withLogger); err != nil {
+			return err
+		}
+	}
+
+	for _, conf := range r.WebexConfigs {
+		if err := conf.sanitize(amVersion, withLogger); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (ogc *opsgenieConfig) sanitize(amVersion semver.Version, logger log.Logger) {
@@ -1002,6 +1587,66 @@ func (wcc *weChatConfig) sanitize(amVersion semver.Version, logger log.Logger) {
 	wcc.HTTPConfig.sanitize(amVersion, logger)
 }
 
+func (tc *telegramConfig) sanitize(amVersion semver.Version, logger log.Logger) error {
+	if amVersion.LT(semver.MustParse("0.24.0")) {
+		return errors.New("telegram integration is supported in Alertmanager >= 0.24.0 only")
+	}
+
+	tc.HTTPConfig.sanitize(amVersion, logger)
+	return nil
+}
+
+func (dc *discordConfig) sanitize(amVersion semver.Version, logger log.Logger) error {
+	if amVersion.LT(semver.MustParse("0.25.0")) {
+		return errors.New("discord integration is supported in Alertmanager >= 0.25.0 only")
+	}
+
+	dc.HTTPConfig.sanitize(amVersion, logger)
+	return nil
+}
+
+func (mtc *msTeamsConfig) sanitize(amVersion semver.Version, logger log.Logger) error {
+	if amVersion.LT(semver.MustParse("0.26.0")) {
+		return errors.New("msteams integration is supported in Alertmanager >= 0.26.0 only")
+	}
+
+	if mtc.Summary != "" && amVersion.LT(semver.MustParse("0.27.0")) {
+		msg := "'summary' supported in Alertmanager >= 0.27.0 only - dropping field from provided config"
+		level.Warn(logger).Log("msg", msg, "current_version", amVersion.String())
+		mtc.Summary = ""
+	}
+
+	mtc.HTTPConfig.sanitize(amVersion, logger)
+	return nil
+}
+
+func (mtc *msTeamsV2Config) sanitize(amVersion semver.Version, logger log.Logger) error {
+	if amVersion.LT(semver.MustParse("0.28.0")) {
+		return errors.New("msteamsv2 integration is supported in Alertmanager >= 0.28.0 only")
+	}
+
+	mtc.HTTPConfig.sanitize(amVersion, logger)
+	return nil
+}
+
+func (sc *snsConfig) sanitize(amVersion semver.Version, logger log.Logger) error {
+	if amVersion.LT(semver.MustParse("0.23.0")) {
+		return errors.New("sns integration is supported in Alertmanager >= 0.23.0 only")
+	}
+
+	sc.HTTPConfig.sanitize(amVersion, logger)
+	return nil
+}
+
+func (wc *webexConfig) sanitize(amVersion semver.Version, logger log.Logger) error {
+	if amVersion.LT(semver.MustParse("0.25.0")) {
+		return errors.New("webex integration is supported in Alertmanager >= 0.25.0 only")
+	}
+
+	wc.HTTPConfig.sanitize(amVersion, logger)
+	return nil
+}
+
 func (ir *inhibitRule) sanitize(amVersion semver.Version, logger log.Logger) error {
 	matchersV2Allowed := amVersion.GTE(semver.MustParse("0.22.0"))
 
@@ -1014,6 +1659,15 @@ func (ir *inhibitRule) sanitize(amVersion semver.Version, logger log.Logger) err
 		msg := fmt.Sprintf(`target_matchers and source_matchers matching is supported in Alertmanager >= 0.22.0 only (target_matchers=%v, source_matchers=%v)`, ir.TargetMatchers, ir.SourceMatchers)
 		return errors.New(msg)
 	}
+
+	if matchersV2Allowed {
+		if err := checkMatchers("source_matchers", ir.SourceMatchers); err != nil {
+			return err
+		}
+		if err := checkMatchers("target_matchers", ir.TargetMatchers); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1037,6 +1691,20 @@ func (r *route) sanitize(amVersion semver.Version, logger log.Logger) error {
 		return fmt.Errorf(`invalid syntax in route config for 'matchers' comparison based matching is supported in Alertmanager >= 0.22.0 only (matchers=%v)`, r.Matchers)
 	}
 
+	if matchersV2Allowed {
+		if err := checkMatchers("matchers", r.Matchers); err != nil {
+			return err
+		}
+	}
+
+	if len(r.MuteTimeIntervals) > 0 && amVersion.LT(semver.MustParse("0.22.0")) {
+		return fmt.Errorf(`'mute_time_intervals' is supported in Alertmanager >= 0.22.0 only (mute_time_intervals=%v)`, r.MuteTimeIntervals)
+	}
+
+	if len(r.ActiveTimeIntervals) > 0 && amVersion.LT(semver.MustParse("0.24.0")) {
+		return fmt.Errorf(`'active_time_intervals' is supported in Alertmanager >= 0.24.0 only (active_time_intervals=%v)`, r.ActiveTimeIntervals)
+	}
+
 	for i, child := range r.Routes {
 		if err := child.sanitize(amVersion, logger); err != nil {
 			return errors.Wrapf(err, "route[%d]", i)
@@ -28,21 +28,26 @@ import (
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 )
 
 func TestGenerateConfig(t *testing.T) {
 	type testCase struct {
-		name       string
-		kclient    kubernetes.Interface
-		baseConfig alertmanagerConfig
-		amConfigs  map[string]*monitoringv1alpha1.AlertmanagerConfig
-		expected   string
+		name            string
+		kclient         kubernetes.Interface
+		baseConfig      alertmanagerConfig
+		amConfigs       map[string]*monitoringv1alpha1.AlertmanagerConfig
+		matcherStrategy monitoringv1.AlertmanagerConfigMatcherStrategy
+		expected        string
 	}
 
 	globalSlackAPIURL, err := url.Parse("http://slack.example.com")
@@ -268,6 +273,110 @@ inhibit_rules:
 receivers:
 - name: "null"
 templates: []
+`,
+		},
+		{
+			name:    "skeleton base, CR with route and inhibition rules, no namespace matcher",
+			kclient: fake.NewSimpleClientset(),
+			baseConfig: alertmanagerConfig{
+				Route:     &route{Receiver: "null"},
+				Receivers: []*receiver{{Name: "null"}},
+			},
+			amConfigs: map[string]*monitoringv1alpha1.AlertmanagerConfig{
+				"mynamespace": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myamc",
+						Namespace: "mynamespace",
+					},
+					Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+						Route: &monitoringv1alpha1.Route{
+							Receiver: "test",
+							Matchers: []monitoringv1alpha1.Matcher{{Name: "severity", Value: "critical"}},
+						},
+						Receivers: []monitoringv1alpha1.Receiver{{Name: "test"}},
+						InhibitRules: []monitoringv1alpha1.InhibitRule{
+							{
+								SourceMatch: []monitoringv1alpha1.Matcher{{Name: "alertname", Value: "NodeNotReady"}},
+								TargetMatch: []monitoringv1alpha1.Matcher{{Name: "alertname", Value: "TargetDown"}},
+								Equal:       []string{"node"},
+							},
+						},
+					},
+				},
+			},
+			matcherStrategy: monitoringv1.AlertmanagerConfigMatcherStrategy{
+				Type: monitoringv1.NoneConfigMatcherStrategyType,
+			},
+			expected: `route:
+  receiver: "null"
+  routes:
+  - receiver: mynamespace-myamc-test
+    match:
+      severity: critical
+    continue: true
+inhibit_rules:
+- target_match:
+    alertname: TargetDown
+  source_match:
+    alertname: NodeNotReady
+  equal:
+  - node
+receivers:
+- name: "null"
+- name: mynamespace-myamc-test
+templates: []
+`,
+		},
+		{
+			name:    "skeleton base, CR with cross-namespace inhibition rule",
+			kclient: fake.NewSimpleClientset(),
+			baseConfig: alertmanagerConfig{
+				Route:     &route{Receiver: "null"},
+				Receivers: []*receiver{{Name: "null"}},
+			},
+			amConfigs: map[string]*monitoringv1alpha1.AlertmanagerConfig{
+				"monitoring": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myamc",
+						Namespace: "monitoring",
+					},
+					Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+						InhibitRules: []monitoringv1alpha1.InhibitRule{
+							{
+								SourceMatch: []monitoringv1alpha1.Matcher{{Name: "alertname", Value: "NodeDown"}},
+								TargetMatch: []monitoringv1alpha1.Matcher{{Name: "severity", Value: "warning"}},
+								Equal:       []string{"node"},
+							},
+							{
+								SourceMatch:    []monitoringv1alpha1.Matcher{{Name: "alertname", Value: "NodeDown"}},
+								TargetMatch:    []monitoringv1alpha1.Matcher{{Name: "severity", Value: "warning"}},
+								Equal:          []string{"node"},
+								CrossNamespace: true,
+							},
+						},
+					},
+				},
+			},
+			expected: `route:
+  receiver: "null"
+inhibit_rules:
+- target_match:
+    namespace: monitoring
+    severity: warning
+  source_match:
+    alertname: NodeDown
+    namespace: monitoring
+  equal:
+  - node
+- target_match:
+    severity: warning
+  source_match:
+    alertname: NodeDown
+  equal:
+  - node
+receivers:
+- name: "null"
+templates: []
 `,
 		},
 		{
@@ -364,6 +473,65 @@ receivers:
   pagerduty_configs:
   - routing_key: 1234abc
 templates: []
+`,
+		},
+		{
+			name: "CR with Pagerduty Receiver and secret namespace",
+			kclient: fake.NewSimpleClientset(
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "am-pd-test-receiver",
+						Namespace: "shared-credentials",
+					},
+					Data: map[string][]byte{
+						"routingKey": []byte("1234abc"),
+					},
+				},
+			),
+			baseConfig: alertmanagerConfig{
+				Route: &route{
+					Receiver: "null",
+				},
+				Receivers: []*receiver{{Name: "null"}},
+			},
+			amConfigs: map[string]*monitoringv1alpha1.AlertmanagerConfig{
+				"mynamespace": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myamc",
+						Namespace: "mynamespace",
+					},
+					Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+						Route: &monitoringv1alpha1.Route{
+							Receiver: "test-pd",
+						},
+						Receivers: []monitoringv1alpha1.Receiver{{
+							Name:            "test-pd",
+							SecretNamespace: "shared-credentials",
+							PagerDutyConfigs: []monitoringv1alpha1.PagerDutyConfig{{
+								RoutingKey: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: "am-pd-test-receiver",
+									},
+									Key: "routingKey",
+								},
+							}},
+						}},
+					},
+				},
+			},
+			expected: `route:
+  receiver: "null"
+  routes:
+  - receiver: mynamespace-myamc-test-pd
+    match:
+      namespace: mynamespace
+    continue: true
+receivers:
+- name: "null"
+- name: mynamespace-myamc-test-pd
+  pagerduty_configs:
+  - routing_key: 1234abc
+templates: []
 `,
 		},
 		{
@@ -736,6 +904,73 @@ receivers:
       confirm:
         text: text
 templates: []
+`,
+		},
+		{
+			name:    "CR with mute time intervals",
+			kclient: fake.NewSimpleClientset(),
+			baseConfig: alertmanagerConfig{
+				Route:     &route{Receiver: "null"},
+				Receivers: []*receiver{{Name: "null"}},
+			},
+			amConfigs: map[string]*monitoringv1alpha1.AlertmanagerConfig{
+				"mynamespace": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myamc",
+						Namespace: "mynamespace",
+					},
+					Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+						Route: &monitoringv1alpha1.Route{
+							Receiver:          "test",
+							MuteTimeIntervals: []string{"weekends"},
+						},
+						Receivers: []monitoringv1alpha1.Receiver{{Name: "test"}},
+						MuteTimeIntervals: []monitoringv1alpha1.MuteTimeInterval{
+							{
+								Name: "weekends",
+								TimeIntervals: []monitoringv1alpha1.TimeInterval{
+									{
+										Times:       []monitoringv1alpha1.TimeRange{{StartTime: "00:00", EndTime: "24:00"}},
+										Weekdays:    []monitoringv1alpha1.WeekdayRange{"Saturday", "Sunday"},
+										DaysOfMonth: []monitoringv1alpha1.DayOfMonthRange{{Start: 1, End: 1}, {Start: -7, End: -1}},
+										Months:      []monitoringv1alpha1.MonthRange{"january:march"},
+										Years:       []monitoringv1alpha1.YearRange{"2023:2024"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: `route:
+  receiver: "null"
+  routes:
+  - receiver: mynamespace-myamc-test
+    match:
+      namespace: mynamespace
+    continue: true
+    mute_time_intervals:
+    - mynamespace-myamc-weekends
+receivers:
+- name: "null"
+- name: mynamespace-myamc-test
+mute_time_intervals:
+- name: mynamespace-myamc-weekends
+  time_intervals:
+  - times:
+    - start_time: "00:00"
+      end_time: "24:00"
+    weekdays:
+    - saturday
+    - sunday
+    days_of_month:
+    - "1"
+    - -7:-1
+    months:
+    - january:march
+    years:
+    - 2023:2024
+templates: []
 `,
 		},
 	}
@@ -749,7 +984,7 @@ templates: []
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			store := assets.NewStore(tc.kclient.CoreV1(), tc.kclient.CoreV1())
-			cg := newConfigGenerator(logger, version, store)
+			cg := newConfigGenerator(logger, version, store, tc.matcherStrategy)
 			cfgBytes, err := cg.generateConfig(context.Background(), tc.baseConfig, tc.amConfigs)
 			if err != nil {
 				t.Fatal(err)
@@ -771,48 +1006,493 @@ templates: []
 	}
 }
 
-func TestSanitizeConfig(t *testing.T) {
-	logger := log.NewNopLogger()
-	versionFileURLAllowed := semver.Version{Major: 0, Minor: 22}
-	versionFileURLNotAllowed := semver.Version{Major: 0, Minor: 21}
+func TestGenerateConfigWithGlobalAlertmanagerConfig(t *testing.T) {
+	globalAMC := &monitoringv1alpha1.AlertmanagerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "global",
+			Namespace: "monitoring",
+		},
+		Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+			Route: &monitoringv1alpha1.Route{
+				Receiver: "default",
+				GroupBy:  []string{"job"},
+				Routes: []apiextensionsv1.JSON{
+					{Raw: []byte(`{"receiver": "pager", "matchers": [{"name": "severity", "value": "critical"}]}`)},
+				},
+			},
+			Receivers: []monitoringv1alpha1.Receiver{
+				{Name: "default"},
+				{Name: "pager"},
+			},
+			InhibitRules: []monitoringv1alpha1.InhibitRule{{
+				SourceMatch: []monitoringv1alpha1.Matcher{{Name: "severity", Value: "critical"}},
+				TargetMatch: []monitoringv1alpha1.Matcher{{Name: "severity", Value: "warning"}},
+				Equal:       []string{"alertname"},
+			}},
+		},
+	}
 
-	versionAuthzAllowed := semver.Version{Major: 0, Minor: 22}
-	versionAuthzNotAllowed := semver.Version{Major: 0, Minor: 21}
+	amConfigs := map[string]*monitoringv1alpha1.AlertmanagerConfig{
+		"ns1/team": {
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "team",
+				Namespace: "ns1",
+			},
+			Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+				Route: &monitoringv1alpha1.Route{
+					Receiver: "team",
+				},
+				Receivers: []monitoringv1alpha1.Receiver{{Name: "team"}},
+			},
+		},
+	}
 
-	matcherV2SyntaxAllowed := semver.Version{Major: 0, Minor: 22}
-	matcherV2SyntaxNotAllowed := semver.Version{Major: 0, Minor: 21}
+	version, err := semver.ParseTolerant("v0.22.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kclient := fake.NewSimpleClientset()
+	cg := newConfigGenerator(log.NewNopLogger(), version, assets.NewStore(kclient.CoreV1(), kclient.CoreV1()), monitoringv1.AlertmanagerConfigMatcherStrategy{})
+
+	baseConfig, err := cg.generateBaseConfig(context.Background(), globalAMC, &monitoringv1.AlertmanagerGlobalConfig{ResolveTimeout: "5m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfgBytes, err := cg.generateConfig(context.Background(), *baseConfig, amConfigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The routes of the namespaced AlertmanagerConfigs come before the child
+	// routes of the global AlertmanagerConfig and no namespace matcher is
+	// enforced for the global AlertmanagerConfig.
+	expected := `global:
+  resolve_timeout: 5m
+route:
+  receiver: monitoring-global-default
+  group_by:
+  - job
+  routes:
+  - receiver: ns1-team-team
+    match:
+      namespace: ns1
+    continue: true
+  - receiver: monitoring-global-pager
+    match:
+      severity: critical
+inhibit_rules:
+- target_match:
+    severity: warning
+  source_match:
+    severity: critical
+  equal:
+  - alertname
+receivers:
+- name: monitoring-global-default
+- name: monitoring-global-pager
+- name: ns1-team-team
+templates: []
+`
+	result := string(cfgBytes)
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("Unexpected result (-want +got):\n%s", diff)
+	}
+
+	if _, err := config.Load(result); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckGeneratedConfig(t *testing.T) {
+	amConfig := func(namespace, name, receiver string, receivers ...string) *monitoringv1alpha1.AlertmanagerConfig {
+		amc := &monitoringv1alpha1.AlertmanagerConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+				Route: &monitoringv1alpha1.Route{
+					Receiver: receiver,
+				},
+			},
+		}
+		for _, r := range receivers {
+			amc.Spec.Receivers = append(amc.Spec.Receivers, monitoringv1alpha1.Receiver{Name: r})
+		}
+		return amc
+	}
+
+	validBaseConfig := `route:
+  receiver: "null"
+receivers:
+- name: "null"
+`
 
 	for _, tc := range []struct {
 		name           string
-		againstVersion semver.Version
-		in             *alertmanagerConfig
-		expect         alertmanagerConfig
-		expectErr      bool
+		version        string
+		baseConfig     string
+		amConfigs      map[string]*monitoringv1alpha1.AlertmanagerConfig
+		validated      bool
+		expectedSource string
 	}{
 		{
-			name:           "Test slack_api_url takes precedence in global config",
-			againstVersion: versionFileURLAllowed,
-			in: &alertmanagerConfig{
-				Global: &globalConfig{
-					SlackAPIURL: &config.URL{
-						URL: &url.URL{
-							Host: "www.test.com",
-						}},
-					SlackAPIURLFile: "/test",
-				},
-			},
-			expect: alertmanagerConfig{
-				Global: &globalConfig{
-					SlackAPIURL: &config.URL{
-						URL: &url.URL{
-							Host: "www.test.com",
-						}},
-					SlackAPIURLFile: "",
-				},
+			name:       "valid config",
+			version:    "v0.23.0",
+			validated:  true,
+			baseConfig: validBaseConfig,
+			amConfigs: map[string]*monitoringv1alpha1.AlertmanagerConfig{
+				"ns1/foo": amConfig("ns1", "foo", "test", "test"),
 			},
 		},
 		{
-			name:           "Test slack_api_url_file is dropped for unsupported versions",
+			name:       "AlertmanagerConfig with undefined receiver",
+			version:    "v0.23.0",
+			validated:  true,
+			baseConfig: validBaseConfig,
+			amConfigs: map[string]*monitoringv1alpha1.AlertmanagerConfig{
+				"ns1/foo": amConfig("ns1", "foo", "test", "test"),
+				"ns2/bar": amConfig("ns2", "bar", "missing"),
+				"ns3/baz": amConfig("ns3", "baz", "missing"),
+			},
+			expectedSource: "AlertmanagerConfig ns2/bar",
+		},
+		{
+			name:      "base config with undefined receiver",
+			version:   "v0.23.0",
+			validated: true,
+			baseConfig: `route:
+  receiver: "missing"
+`,
+			amConfigs: map[string]*monitoringv1alpha1.AlertmanagerConfig{
+				"ns1/foo": amConfig("ns1", "foo", "test", "test"),
+			},
+			expectedSource: "Secret ns/base",
+		},
+		{
+			name:       "unsupported version",
+			version:    "v0.24.0",
+			baseConfig: validBaseConfig,
+			amConfigs: map[string]*monitoringv1alpha1.AlertmanagerConfig{
+				"ns2/bar": amConfig("ns2", "bar", "missing"),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			version, err := semver.ParseTolerant(tc.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			kclient := fake.NewSimpleClientset()
+			store := assets.NewStore(kclient.CoreV1(), kclient.CoreV1())
+			cg := newConfigGenerator(log.NewNopLogger(), version, store, monitoringv1.AlertmanagerConfigMatcherStrategy{})
+
+			var baseConfig alertmanagerConfig
+			if err := yaml.UnmarshalStrict([]byte(tc.baseConfig), &baseConfig); err != nil {
+				t.Fatal(err)
+			}
+
+			generatedConfig, err := cg.generateConfig(context.Background(), baseConfig, tc.amConfigs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cg.canValidateConfig() != tc.validated {
+				t.Fatalf("expected canValidateConfig() to return %v", tc.validated)
+			}
+
+			err = cg.checkGeneratedConfig(context.Background(), generatedConfig, []byte(tc.baseConfig), "Secret ns/base", tc.amConfigs)
+			if tc.expectedSource == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			invalidErr, ok := err.(*invalidConfigError)
+			if !ok {
+				t.Fatalf("expected invalidConfigError, got %v", err)
+			}
+
+			if invalidErr.source != tc.expectedSource {
+				t.Fatalf("expected source %q, got %q", tc.expectedSource, invalidErr.source)
+			}
+		})
+	}
+}
+
+func TestConvertReceiver(t *testing.T) {
+	kclient := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "secret",
+				Namespace: "ns1",
+			},
+			Data: map[string][]byte{
+				"token": []byte("secret-token"),
+				"url":   []byte("https://webhook.example.com/123"),
+				"empty": []byte(""),
+			},
+		},
+	)
+	crKey := types.NamespacedName{Namespace: "ns1", Name: "amc"}
+	secretKey := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "secret"},
+			Key:                  key,
+		}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		in        monitoringv1alpha1.Receiver
+		expected  *receiver
+		expectErr bool
+	}{
+		{
+			name: "telegram config",
+			in: monitoringv1alpha1.Receiver{
+				Name: "telegram",
+				TelegramConfigs: []monitoringv1alpha1.TelegramConfig{
+					{
+						APIURL:               "https://telegram.example.com",
+						BotToken:             secretKey("token"),
+						ChatID:               12345,
+						Message:              "{{ .CommonAnnotations.summary }}",
+						DisableNotifications: func(b bool) *bool { return &b }(true),
+						ParseMode:            "MarkdownV2",
+					},
+				},
+			},
+			expected: &receiver{
+				Name: "ns1-amc-telegram",
+				TelegramConfigs: []*telegramConfig{
+					{
+						APIUrl:               "https://telegram.example.com",
+						BotToken:             "secret-token",
+						ChatID:               12345,
+						Message:              "{{ .CommonAnnotations.summary }}",
+						DisableNotifications: true,
+						ParseMode:            "MarkdownV2",
+					},
+				},
+			},
+		},
+		{
+			name: "discord config",
+			in: monitoringv1alpha1.Receiver{
+				Name: "discord",
+				DiscordConfigs: []monitoringv1alpha1.DiscordConfig{
+					{
+						APIURL:  secretKey("url"),
+						Title:   "{{ .CommonLabels.alertname }}",
+						Message: "{{ .CommonAnnotations.summary }}",
+					},
+				},
+			},
+			expected: &receiver{
+				Name: "ns1-amc-discord",
+				DiscordConfigs: []*discordConfig{
+					{
+						WebhookURL: "https://webhook.example.com/123",
+						Title:      "{{ .CommonLabels.alertname }}",
+						Message:    "{{ .CommonAnnotations.summary }}",
+					},
+				},
+			},
+		},
+		{
+			name: "msteams configs",
+			in: monitoringv1alpha1.Receiver{
+				Name: "teams",
+				MSTeamsConfigs: []monitoringv1alpha1.MSTeamsConfig{
+					{
+						WebhookURL: secretKey("url"),
+						Title:      "{{ .CommonLabels.alertname }}",
+						Summary:    "{{ .CommonAnnotations.summary }}",
+						Text:       "{{ .CommonAnnotations.description }}",
+					},
+				},
+				MSTeamsV2Configs: []monitoringv1alpha1.MSTeamsV2Config{
+					{
+						WebhookURL: secretKey("url"),
+						Text:       "{{ .CommonAnnotations.description }}",
+					},
+				},
+			},
+			expected: &receiver{
+				Name: "ns1-amc-teams",
+				MSTeamsConfigs: []*msTeamsConfig{
+					{
+						WebhookURL: "https://webhook.example.com/123",
+						Title:      "{{ .CommonLabels.alertname }}",
+						Summary:    "{{ .CommonAnnotations.summary }}",
+						Text:       "{{ .CommonAnnotations.description }}",
+					},
+				},
+				MSTeamsV2Configs: []*msTeamsV2Config{
+					{
+						WebhookURL: "https://webhook.example.com/123",
+						Text:       "{{ .CommonAnnotations.description }}",
+					},
+				},
+			},
+		},
+		{
+			name: "msteams config with empty webhook URL",
+			in: monitoringv1alpha1.Receiver{
+				Name: "teams",
+				MSTeamsConfigs: []monitoringv1alpha1.MSTeamsConfig{
+					{
+						WebhookURL: secretKey("empty"),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "sns config",
+			in: monitoringv1alpha1.Receiver{
+				Name: "sns",
+				SNSConfigs: []monitoringv1alpha1.SNSConfig{
+					{
+						APIURL:   "https://sns.us-east-2.amazonaws.com",
+						TopicARN: "arn:aws:sns:us-east-2:123456789012:My-Topic",
+						Sigv4: &monitoringv1.Sigv4{
+							Region:    "us-east-2",
+							AccessKey: secretKey("token"),
+							SecretKey: secretKey("url"),
+						},
+						Subject:    "{{ .CommonLabels.alertname }}",
+						Attributes: map[string]string{"key": "value"},
+					},
+				},
+			},
+			expected: &receiver{
+				Name: "ns1-amc-sns",
+				SNSConfigs: []*snsConfig{
+					{
+						APIUrl:   "https://sns.us-east-2.amazonaws.com",
+						TopicARN: "arn:aws:sns:us-east-2:123456789012:My-Topic",
+						Sigv4: &sigV4Config{
+							Region:    "us-east-2",
+							AccessKey: "secret-token",
+							SecretKey: "https://webhook.example.com/123",
+						},
+						Subject:    "{{ .CommonLabels.alertname }}",
+						Attributes: map[string]string{"key": "value"},
+					},
+				},
+			},
+		},
+		{
+			name: "webex config",
+			in: monitoringv1alpha1.Receiver{
+				Name: "webex",
+				WebexConfigs: []monitoringv1alpha1.WebexConfig{
+					{
+						BotToken: secretKey("token"),
+						RoomID:   "room",
+						Message:  "{{ .CommonAnnotations.summary }}",
+					},
+				},
+			},
+			expected: &receiver{
+				Name: "ns1-amc-webex",
+				WebexConfigs: []*webexConfig{
+					{
+						HTTPConfig: &httpClientConfig{
+							Authorization: &authorization{
+								Type:        "Bearer",
+								Credentials: "secret-token",
+							},
+						},
+						RoomID:  "room",
+						Message: "{{ .CommonAnnotations.summary }}",
+					},
+				},
+			},
+		},
+		{
+			name: "telegram config with missing bot token",
+			in: monitoringv1alpha1.Receiver{
+				Name: "telegram",
+				TelegramConfigs: []monitoringv1alpha1.TelegramConfig{
+					{
+						BotToken: secretKey("not-existing"),
+						ChatID:   12345,
+					},
+				},
+			},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := assets.NewStore(kclient.CoreV1(), kclient.CoreV1())
+			cg := newConfigGenerator(log.NewNopLogger(), semver.MustParse("0.24.0"), store, monitoringv1.AlertmanagerConfigMatcherStrategy{})
+			out, err := cg.convertReceiver(context.Background(), &tc.in, crKey)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, out); diff != "" {
+				t.Errorf("Unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSanitizeConfig(t *testing.T) {
+	logger := log.NewNopLogger()
+	versionFileURLAllowed := semver.Version{Major: 0, Minor: 22}
+	versionFileURLNotAllowed := semver.Version{Major: 0, Minor: 21}
+
+	versionAuthzAllowed := semver.Version{Major: 0, Minor: 22}
+	versionAuthzNotAllowed := semver.Version{Major: 0, Minor: 21}
+
+	matcherV2SyntaxAllowed := semver.Version{Major: 0, Minor: 22}
+	matcherV2SyntaxNotAllowed := semver.Version{Major: 0, Minor: 21}
+
+	for _, tc := range []struct {
+		name           string
+		againstVersion semver.Version
+		in             *alertmanagerConfig
+		expect         alertmanagerConfig
+		expectErr      bool
+	}{
+		{
+			name:           "Test slack_api_url takes precedence in global config",
+			againstVersion: versionFileURLAllowed,
+			in: &alertmanagerConfig{
+				Global: &globalConfig{
+					SlackAPIURL: &config.URL{
+						URL: &url.URL{
+							Host: "www.test.com",
+						}},
+					SlackAPIURLFile: "/test",
+				},
+			},
+			expect: alertmanagerConfig{
+				Global: &globalConfig{
+					SlackAPIURL: &config.URL{
+						URL: &url.URL{
+							Host: "www.test.com",
+						}},
+					SlackAPIURLFile: "",
+				},
+			},
+		},
+		{
+			name:           "Test slack_api_url_file is dropped for unsupported versions",
 			againstVersion: versionFileURLNotAllowed,
 			in: &alertmanagerConfig{
 				Global: &globalConfig{
@@ -1020,6 +1700,22 @@ func TestSanitizeConfig(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name:           "Test inhibit rules error with invalid matchers",
+			againstVersion: matcherV2SyntaxAllowed,
+			in: &alertmanagerConfig{
+				InhibitRules: []*inhibitRule{
+					{
+						SourceMatchers: []string{"severity=critical"},
+						TargetMatchers: []string{"severity=warning,"},
+					},
+					{
+						SourceMatchers: []string{`severity="critical`},
+					},
+				},
+			},
+			expectErr: true,
+		},
 		{
 			name:           "Test inhibit rules happy path",
 			againstVersion: matcherV2SyntaxAllowed,
@@ -1053,6 +1749,206 @@ func TestSanitizeConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:           "Test time_intervals are converted to mute_time_intervals for unsupported versions",
+			againstVersion: semver.Version{Major: 0, Minor: 23},
+			in: &alertmanagerConfig{
+				TimeIntervals: []*timeInterval{
+					{Name: "weekends", TimeIntervals: []timePeriod{{Weekdays: []string{"saturday:sunday"}}}},
+				},
+			},
+			expect: alertmanagerConfig{
+				MuteTimeIntervals: []*timeInterval{
+					{Name: "weekends", TimeIntervals: []timePeriod{{Weekdays: []string{"saturday:sunday"}}}},
+				},
+			},
+		},
+		{
+			name:           "Test time_intervals are kept for supported versions",
+			againstVersion: semver.Version{Major: 0, Minor: 24},
+			in: &alertmanagerConfig{
+				TimeIntervals: []*timeInterval{
+					{Name: "weekends", TimeIntervals: []timePeriod{{Weekdays: []string{"saturday:sunday"}}}},
+				},
+			},
+			expect: alertmanagerConfig{
+				TimeIntervals: []*timeInterval{
+					{Name: "weekends", TimeIntervals: []timePeriod{{Weekdays: []string{"saturday:sunday"}}}},
+				},
+			},
+		},
+		{
+			name:           "Test mute_time_intervals returns error for unsupported versions",
+			againstVersion: semver.Version{Major: 0, Minor: 21},
+			in: &alertmanagerConfig{
+				TimeIntervals: []*timeInterval{
+					{Name: "weekends", TimeIntervals: []timePeriod{{Weekdays: []string{"saturday:sunday"}}}},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Test telegram config returns error for unsupported versions",
+			againstVersion: semver.Version{Major: 0, Minor: 23},
+			in: &alertmanagerConfig{
+				Receivers: []*receiver{
+					{
+						TelegramConfigs: []*telegramConfig{
+							{
+								BotToken: "secret",
+								ChatID:   12345,
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Test telegram config is kept for supported versions",
+			againstVersion: semver.Version{Major: 0, Minor: 24},
+			in: &alertmanagerConfig{
+				Receivers: []*receiver{
+					{
+						TelegramConfigs: []*telegramConfig{
+							{
+								BotToken: "secret",
+								ChatID:   12345,
+							},
+						},
+					},
+				},
+			},
+			expect: alertmanagerConfig{
+				Receivers: []*receiver{
+					{
+						TelegramConfigs: []*telegramConfig{
+							{
+								BotToken: "secret",
+								ChatID:   12345,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:           "Test discord config returns error for unsupported versions",
+			againstVersion: semver.Version{Major: 0, Minor: 24},
+			in: &alertmanagerConfig{
+				Receivers: []*receiver{
+					{
+						DiscordConfigs: []*discordConfig{
+							{
+								WebhookURL: "https://discord.example.com",
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Test msteams summary is dropped for unsupported versions",
+			againstVersion: semver.Version{Major: 0, Minor: 26},
+			in: &alertmanagerConfig{
+				Receivers: []*receiver{
+					{
+						MSTeamsConfigs: []*msTeamsConfig{
+							{
+								WebhookURL: "https://webhook.example.com",
+								Summary:    "summary",
+							},
+						},
+					},
+				},
+			},
+			expect: alertmanagerConfig{
+				Receivers: []*receiver{
+					{
+						MSTeamsConfigs: []*msTeamsConfig{
+							{
+								WebhookURL: "https://webhook.example.com",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:           "Test msteams config returns error for unsupported versions",
+			againstVersion: semver.Version{Major: 0, Minor: 25},
+			in: &alertmanagerConfig{
+				Receivers: []*receiver{
+					{
+						MSTeamsConfigs: []*msTeamsConfig{
+							{
+								WebhookURL: "https://webhook.example.com",
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Test msteamsv2 config returns error for unsupported versions",
+			againstVersion: semver.Version{Major: 0, Minor: 27},
+			in: &alertmanagerConfig{
+				Receivers: []*receiver{
+					{
+						MSTeamsV2Configs: []*msTeamsV2Config{
+							{
+								WebhookURL: "https://webhook.example.com",
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Test sns config returns error for unsupported versions",
+			againstVersion: semver.Version{Major: 0, Minor: 22},
+			in: &alertmanagerConfig{
+				Receivers: []*receiver{
+					{
+						SNSConfigs: []*snsConfig{
+							{
+								TopicARN: "arn:aws:sns:us-east-2:123456789012:My-Topic",
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Test webex config returns error for unsupported versions",
+			againstVersion: semver.Version{Major: 0, Minor: 24},
+			in: &alertmanagerConfig{
+				Receivers: []*receiver{
+					{
+						WebexConfigs: []*webexConfig{
+							{
+								RoomID: "room",
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Test location returns error for unsupported versions",
+			againstVersion: semver.Version{Major: 0, Minor: 24},
+			in: &alertmanagerConfig{
+				TimeIntervals: []*timeInterval{
+					{Name: "weekends", TimeIntervals: []timePeriod{{Location: "Europe/Paris"}}},
+				},
+			},
+			expectErr: true,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			err := tc.in.sanitize(tc.againstVersion, logger)
@@ -1233,6 +2129,65 @@ func TestSanitizeRoute(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:           "Test route with invalid matchers in child route",
+			againstVersion: matcherV2SyntaxAllowed,
+			in: &route{
+				Receiver: "test",
+				Matchers: []string{`severity="critical"`},
+				Routes: []*route{
+					{
+						Matchers: []string{`team=~"infra|db", job!~(`},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Test route with quoted and negative matchers",
+			againstVersion: matcherV2SyntaxAllowed,
+			in: &route{
+				Receiver: "test",
+				Matchers: []string{`{severity!="info", summary="a \"quoted\", value"}`},
+			},
+			expect: route{
+				Receiver: "test",
+				Matchers: []string{`{severity!="info", summary="a \"quoted\", value"}`},
+			},
+		},
+		{
+			name:           "Test route with mute_time_intervals not supported",
+			againstVersion: semver.Version{Major: 0, Minor: 21},
+			in: &route{
+				Receiver:          "test",
+				MuteTimeIntervals: []string{"weekends"},
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Test route with active_time_intervals not supported in child route",
+			againstVersion: semver.Version{Major: 0, Minor: 23},
+			in: &route{
+				Receiver:          "test",
+				MuteTimeIntervals: []string{"weekends"},
+				Routes: []*route{
+					{ActiveTimeIntervals: []string{"business-hours"}},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Test route with active_time_intervals supported",
+			againstVersion: semver.Version{Major: 0, Minor: 24},
+			in: &route{
+				Receiver:            "test",
+				ActiveTimeIntervals: []string{"business-hours"},
+			},
+			expect: route{
+				Receiver:            "test",
+				ActiveTimeIntervals: []string{"business-hours"},
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			err := tc.in.sanitize(tc.againstVersion, logger)
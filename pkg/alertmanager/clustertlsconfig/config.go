@@ -0,0 +1,265 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertlsconfig
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+const (
+	volumeName = "cluster-tls-config"
+	configFile = "cluster-tls-config.yaml"
+	serverDir  = "server"
+	clientDir  = "client"
+)
+
+// Config is the TLS configuration of the Alertmanager cluster (gossip)
+// protocol.
+//
+// Config can make a secret which holds the cluster TLS config contents, as
+// well as the volume and volume mount for referencing the secret and the
+// necessary TLS credentials.
+type Config struct {
+	tlsConfig   *monitoringv1.ClusterTLSConfig
+	mountingDir string
+	secretName  string
+}
+
+// New creates a new Config.
+func New(mountingDir string, secretName string, tlsConfig *monitoringv1.ClusterTLSConfig) (*Config, error) {
+	if tlsConfig == nil {
+		return nil, errors.New("cluster TLS config must be defined")
+	}
+
+	if err := tlsConfig.ServerTLS.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid server TLS config")
+	}
+
+	if err := tlsConfig.ClientTLS.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid client TLS config")
+	}
+
+	return &Config{
+		tlsConfig:   tlsConfig,
+		mountingDir: mountingDir,
+		secretName:  secretName,
+	}, nil
+}
+
+// GetMountParameters returns a volume and a volume mount which contain the
+// config file and the associated TLS credentials.
+// In addition, GetMountParameters returns a cluster.tls-config command line
+// option pointing to the file in the volume mount.
+func (c *Config) GetMountParameters() (string, v1.Volume, v1.VolumeMount) {
+	sources := []v1.VolumeProjection{
+		{
+			Secret: &v1.SecretProjection{
+				LocalObjectReference: v1.LocalObjectReference{Name: c.secretName},
+				Items:                []v1.KeyToPath{{Key: configFile, Path: configFile}},
+			},
+		},
+	}
+
+	// The same credential may be referenced more than once (e.g. a
+	// self-signed certificate used as the client CA) and projected volumes
+	// reject duplicate paths.
+	seen := map[string]struct{}{configFile: {}}
+	project := func(sel monitoringv1.SecretOrConfigMap, dir string) {
+		p := relativePath(sel, dir)
+		if _, found := seen[p]; found || p == "" {
+			return
+		}
+		seen[p] = struct{}{}
+		sources = append(sources, projection(sel, p))
+	}
+
+	server := c.tlsConfig.ServerTLS
+	project(monitoringv1.SecretOrConfigMap{Secret: &server.KeySecret}, serverDir)
+	project(server.Cert, serverDir)
+	project(server.ClientCA, serverDir)
+
+	client := c.tlsConfig.ClientTLS
+	if client.KeySecret != nil {
+		project(monitoringv1.SecretOrConfigMap{Secret: client.KeySecret}, clientDir)
+	}
+	project(client.Cert, clientDir)
+	project(client.CA, clientDir)
+
+	arg := fmt.Sprintf("--cluster.tls-config=%s", path.Join(c.mountingDir, configFile))
+
+	volume := v1.Volume{
+		Name: volumeName,
+		VolumeSource: v1.VolumeSource{
+			Projected: &v1.ProjectedVolumeSource{
+				Sources: sources,
+			},
+		},
+	}
+
+	mount := v1.VolumeMount{
+		Name:      volumeName,
+		ReadOnly:  true,
+		MountPath: c.mountingDir,
+	}
+
+	return arg, volume, mount
+}
+
+// MakeConfigFileSecret returns a Kubernetes secret with the data for the
+// cluster TLS config file.
+// The format of the file is available in the official Alertmanager
+// documentation:
+// https://github.com/prometheus/alertmanager/blob/main/docs/https.md#gossip-traffic
+func (c *Config) MakeConfigFileSecret(labels map[string]string, ownerReference metav1.OwnerReference) (*v1.Secret, error) {
+	data, err := c.generateConfigFileContents()
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            c.secretName,
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{ownerReference},
+		},
+		Data: map[string][]byte{
+			configFile: data,
+		},
+	}, nil
+}
+
+func (c *Config) generateConfigFileContents() ([]byte, error) {
+	cfg := yaml.MapSlice{
+		{Key: "tls_server_config", Value: c.makeTLSServerConfig()},
+		{Key: "tls_client_config", Value: c.makeTLSClientConfig()},
+	}
+
+	return yaml.Marshal(cfg)
+}
+
+func (c *Config) makeTLSServerConfig() yaml.MapSlice {
+	tls := c.tlsConfig.ServerTLS
+	cfg := yaml.MapSlice{
+		{Key: "cert_file", Value: c.filePath(tls.Cert, serverDir)},
+		{Key: "key_file", Value: c.filePath(monitoringv1.SecretOrConfigMap{Secret: &tls.KeySecret}, serverDir)},
+	}
+
+	if tls.ClientAuthType != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "client_auth_type", Value: tls.ClientAuthType})
+	}
+
+	if caPath := c.filePath(tls.ClientCA, serverDir); caPath != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "client_ca_file", Value: caPath})
+	}
+
+	if tls.MinVersion != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "min_version", Value: tls.MinVersion})
+	}
+
+	if tls.MaxVersion != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "max_version", Value: tls.MaxVersion})
+	}
+
+	if len(tls.CipherSuites) != 0 {
+		cfg = append(cfg, yaml.MapItem{Key: "cipher_suites", Value: tls.CipherSuites})
+	}
+
+	if tls.PreferServerCipherSuites != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "prefer_server_cipher_suites", Value: *tls.PreferServerCipherSuites})
+	}
+
+	if len(tls.CurvePreferences) != 0 {
+		cfg = append(cfg, yaml.MapItem{Key: "curve_preferences", Value: tls.CurvePreferences})
+	}
+
+	return cfg
+}
+
+func (c *Config) makeTLSClientConfig() yaml.MapSlice {
+	tls := c.tlsConfig.ClientTLS
+	cfg := yaml.MapSlice{}
+
+	if caPath := c.filePath(tls.CA, clientDir); caPath != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "ca_file", Value: caPath})
+	}
+
+	if certPath := c.filePath(tls.Cert, clientDir); certPath != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "cert_file", Value: certPath})
+	}
+
+	if tls.KeySecret != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "key_file", Value: c.filePath(monitoringv1.SecretOrConfigMap{Secret: tls.KeySecret}, clientDir)})
+	}
+
+	if tls.ServerName != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "server_name", Value: tls.ServerName})
+	}
+
+	cfg = append(cfg, yaml.MapItem{Key: "insecure_skip_verify", Value: tls.InsecureSkipVerify})
+
+	return cfg
+}
+
+// filePath returns the path of the file holding the TLS credential inside
+// the container or an empty string if the selector isn't defined.
+func (c *Config) filePath(sel monitoringv1.SecretOrConfigMap, dir string) string {
+	p := relativePath(sel, dir)
+	if p == "" {
+		return ""
+	}
+
+	return path.Join(c.mountingDir, p)
+}
+
+// relativePath returns the path of the file holding the TLS credential
+// relative to the mounting directory.
+func relativePath(sel monitoringv1.SecretOrConfigMap, dir string) string {
+	switch {
+	case sel.Secret != nil:
+		return path.Join(dir, fmt.Sprintf("secret_%s_%s", sel.Secret.Name, sel.Secret.Key))
+	case sel.ConfigMap != nil:
+		return path.Join(dir, fmt.Sprintf("configmap_%s_%s", sel.ConfigMap.Name, sel.ConfigMap.Key))
+	}
+
+	return ""
+}
+
+// projection returns the volume projection of the TLS credential at the given
+// path. The selector must reference either a Secret or a ConfigMap.
+func projection(sel monitoringv1.SecretOrConfigMap, p string) v1.VolumeProjection {
+	if sel.Secret != nil {
+		return v1.VolumeProjection{
+			Secret: &v1.SecretProjection{
+				LocalObjectReference: sel.Secret.LocalObjectReference,
+				Items:                []v1.KeyToPath{{Key: sel.Secret.Key, Path: p}},
+			},
+		}
+	}
+
+	return v1.VolumeProjection{
+		ConfigMap: &v1.ConfigMapProjection{
+			LocalObjectReference: sel.ConfigMap.LocalObjectReference,
+			Items:                []v1.KeyToPath{{Key: sel.ConfigMap.Key, Path: p}},
+		},
+	}
+}
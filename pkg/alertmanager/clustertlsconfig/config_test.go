@@ -0,0 +1,199 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertlsconfig_test
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/prometheus-operator/prometheus-operator/pkg/alertmanager/clustertlsconfig"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func secretKeySelector(name, key string) *v1.SecretKeySelector {
+	return &v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: name},
+		Key:                  key,
+	}
+}
+
+func mutualTLSConfig() *monitoringv1.ClusterTLSConfig {
+	return &monitoringv1.ClusterTLSConfig{
+		ServerTLS: monitoringv1.WebTLSConfig{
+			KeySecret:      *secretKeySelector("cluster-tls", "tls.key"),
+			Cert:           monitoringv1.SecretOrConfigMap{Secret: secretKeySelector("cluster-tls", "tls.crt")},
+			ClientCA:       monitoringv1.SecretOrConfigMap{Secret: secretKeySelector("cluster-tls", "ca.crt")},
+			ClientAuthType: "RequireAndVerifyClientCert",
+		},
+		ClientTLS: monitoringv1.SafeTLSConfig{
+			KeySecret:  secretKeySelector("cluster-tls", "tls.key"),
+			Cert:       monitoringv1.SecretOrConfigMap{Secret: secretKeySelector("cluster-tls", "tls.crt")},
+			CA:         monitoringv1.SecretOrConfigMap{Secret: secretKeySelector("cluster-tls", "ca.crt")},
+			ServerName: "alertmanager",
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		tlsConfig *monitoringv1.ClusterTLSConfig
+	}{
+		{
+			name: "nil config",
+		},
+		{
+			name: "server certificate without key",
+			tlsConfig: &monitoringv1.ClusterTLSConfig{
+				ServerTLS: monitoringv1.WebTLSConfig{
+					Cert: monitoringv1.SecretOrConfigMap{Secret: secretKeySelector("cluster-tls", "tls.crt")},
+				},
+			},
+		},
+		{
+			name: "client certificate without key",
+			tlsConfig: &monitoringv1.ClusterTLSConfig{
+				ServerTLS: mutualTLSConfig().ServerTLS,
+				ClientTLS: monitoringv1.SafeTLSConfig{
+					Cert: monitoringv1.SecretOrConfigMap{Secret: secretKeySelector("cluster-tls", "tls.crt")},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := clustertlsconfig.New("/etc/alertmanager/cluster_tls_config", "test-secret", tc.tlsConfig); err == nil {
+				t.Fatal("expected error, got none")
+			}
+		})
+	}
+}
+
+func TestMakeSecret(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		tlsConfig    *monitoringv1.ClusterTLSConfig
+		expectedData string
+	}{
+		{
+			name: "server TLS only",
+			tlsConfig: &monitoringv1.ClusterTLSConfig{
+				ServerTLS: monitoringv1.WebTLSConfig{
+					KeySecret: *secretKeySelector("cluster-tls", "tls.key"),
+					Cert: monitoringv1.SecretOrConfigMap{
+						ConfigMap: &v1.ConfigMapKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "cluster-tls"},
+							Key:                  "tls.crt",
+						},
+					},
+				},
+				ClientTLS: monitoringv1.SafeTLSConfig{
+					InsecureSkipVerify: true,
+				},
+			},
+			expectedData: `tls_server_config:
+  cert_file: /etc/alertmanager/cluster_tls_config/server/configmap_cluster-tls_tls.crt
+  key_file: /etc/alertmanager/cluster_tls_config/server/secret_cluster-tls_tls.key
+tls_client_config:
+  insecure_skip_verify: true
+`,
+		},
+		{
+			name:      "mutual TLS",
+			tlsConfig: mutualTLSConfig(),
+			expectedData: `tls_server_config:
+  cert_file: /etc/alertmanager/cluster_tls_config/server/secret_cluster-tls_tls.crt
+  key_file: /etc/alertmanager/cluster_tls_config/server/secret_cluster-tls_tls.key
+  client_auth_type: RequireAndVerifyClientCert
+  client_ca_file: /etc/alertmanager/cluster_tls_config/server/secret_cluster-tls_ca.crt
+tls_client_config:
+  ca_file: /etc/alertmanager/cluster_tls_config/client/secret_cluster-tls_ca.crt
+  cert_file: /etc/alertmanager/cluster_tls_config/client/secret_cluster-tls_tls.crt
+  key_file: /etc/alertmanager/cluster_tls_config/client/secret_cluster-tls_tls.key
+  server_name: alertmanager
+  insecure_skip_verify: false
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := clustertlsconfig.New("/etc/alertmanager/cluster_tls_config", "test-secret", tc.tlsConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			secret, err := config.MakeConfigFileSecret(nil, metav1.OwnerReference{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.expectedData != string(secret.Data["cluster-tls-config.yaml"]) {
+				t.Fatalf("got\n%s\nwant\n%s", secret.Data["cluster-tls-config.yaml"], tc.expectedData)
+			}
+		})
+	}
+}
+
+func TestGetMountParameters(t *testing.T) {
+	config, err := clustertlsconfig.New("/etc/alertmanager/cluster_tls_config", "test-secret", mutualTLSConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	arg, volume, mount := config.GetMountParameters()
+
+	if arg != "--cluster.tls-config=/etc/alertmanager/cluster_tls_config/cluster-tls-config.yaml" {
+		t.Fatalf("unexpected argument: %s", arg)
+	}
+
+	expectedMount := v1.VolumeMount{
+		Name:      "cluster-tls-config",
+		ReadOnly:  true,
+		MountPath: "/etc/alertmanager/cluster_tls_config",
+	}
+	if !reflect.DeepEqual(mount, expectedMount) {
+		t.Fatalf("expected mount %v, got %v", expectedMount, mount)
+	}
+
+	if volume.Name != "cluster-tls-config" || volume.Projected == nil {
+		t.Fatalf("expected projected volume %q, got %v", "cluster-tls-config", volume)
+	}
+
+	// The server and client credentials are projected into distinct
+	// directories.
+	var paths []string
+	for _, src := range volume.Projected.Sources {
+		if src.Secret == nil {
+			t.Fatalf("expected secret projection, got %v", src)
+		}
+		for _, item := range src.Secret.Items {
+			paths = append(paths, item.Path)
+		}
+	}
+
+	expectedPaths := []string{
+		"cluster-tls-config.yaml",
+		"server/secret_cluster-tls_tls.key",
+		"server/secret_cluster-tls_tls.crt",
+		"server/secret_cluster-tls_ca.crt",
+		"client/secret_cluster-tls_tls.key",
+		"client/secret_cluster-tls_tls.crt",
+		"client/secret_cluster-tls_ca.crt",
+	}
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Fatalf("expected paths %v, got %v", expectedPaths, paths)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"context"
+
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+// newGarbageCollector returns the garbage collector of the objects generated
+// for the Alertmanager resources.
+func (c *Operator) newGarbageCollector() *operator.GarbageCollector {
+	return operator.NewGarbageCollector(
+		log.With(c.logger, "component", "garbage-collector"),
+		c.kclient,
+		c.metrics,
+		operator.GarbageCollectorOptions{
+			AllowList: c.config.Namespaces.AlertmanagerAllowList,
+			DenyList:  c.config.Namespaces.DenyList,
+			OwnsNamespace: func(ns string) bool {
+				return c.config.Namespaces.Sharding.OwnsNamespace(c.nsAlrtInf.GetStore(), ns)
+			},
+			OwnerKinds: []string{monitoringv1.AlertmanagersKind},
+			GetOwner:   c.getOwner,
+			Needed:     neededByAlertmanager,
+			DryRun:     c.config.GarbageCollectionDryRun,
+		},
+	)
+}
+
+// getOwner returns the Alertmanager object from the API server. The
+// informers' caches can't be used because they only contain the objects
+// matching the instance selector.
+func (c *Operator) getOwner(ctx context.Context, kind, namespace, name string) (metav1.Object, error) {
+	if kind != monitoringv1.AlertmanagersKind {
+		return nil, errors.Errorf("unsupported kind %q", kind)
+	}
+
+	am, err := c.mclient.MonitoringV1().Alertmanagers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return am, nil
+}
+
+// neededByAlertmanager returns false for the StatefulSets which don't match
+// the name of the owner and for the cluster TLS and web config Secrets when
+// the owner doesn't define the corresponding settings anymore.
+func neededByAlertmanager(_ string, owner metav1.Object, obj operator.GeneratedObject) bool {
+	switch obj.Kind {
+	case operator.StatefulSetKind:
+		return obj.GetName() == prefixedName(owner.GetName())
+
+	case operator.SecretKind:
+		am, ok := owner.(*monitoringv1.Alertmanager)
+		if !ok {
+			return true
+		}
+
+		switch obj.GetName() {
+		case clusterTLSConfigSecretName(am.Name):
+			return am.Spec.ClusterTLSConfig != nil
+		case webConfigSecretName(am.Name):
+			return am.Spec.Web != nil
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,257 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+)
+
+// matcherParseError is returned when a matcher expression doesn't follow the
+// Alertmanager matcher grammar. The position is the 1-based column (in
+// characters) where the error was detected.
+type matcherParseError struct {
+	pos int
+	msg string
+}
+
+func (e *matcherParseError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.pos, e.msg)
+}
+
+// matcherParser parses a comma-separated list of matchers optionally
+// enclosed in curly braces such as `{severity="critical", team!~"infra|db"}`.
+//
+// The parser is stricter than the one used by Alertmanager < v0.27 so that
+// the configuration generated by the operator is accepted by all the
+// Alertmanager versions:
+// * label names must be valid Prometheus label names,
+// * label values containing reserved characters ('{', '}', ',', '=', '!',
+// '~', quotes or whitespaces) must be double-quoted,
+// * quoted values use the Go escaping rules.
+type matcherParser struct {
+	input []rune
+	pos   int
+}
+
+// parseMatchers parses the matchers of a route or inhibition rule written in
+// the Alertmanager v2 syntax.
+func parseMatchers(s string) ([]*labels.Matcher, error) {
+	if !utf8.ValidString(s) {
+		return nil, &matcherParseError{pos: 1, msg: "invalid UTF-8 string"}
+	}
+
+	p := &matcherParser{input: []rune(s)}
+	return p.parse()
+}
+
+// checkMatchers verifies that all the matchers of a route or inhibition rule
+// field can be parsed.
+func checkMatchers(field string, matchers []string) error {
+	for i, m := range matchers {
+		if _, err := parseMatchers(m); err != nil {
+			return errors.Wrapf(err, "invalid %s[%d] %q", field, i, m)
+		}
+	}
+
+	return nil
+}
+
+func (p *matcherParser) parse() ([]*labels.Matcher, error) {
+	var matchers []*labels.Matcher
+
+	p.skipSpaces()
+	braces := p.consume('{')
+
+	for {
+		p.skipSpaces()
+
+		if p.eof() {
+			if braces {
+				return nil, p.errorf("missing closing '}'")
+			}
+			return matchers, nil
+		}
+
+		if braces && p.consume('}') {
+			p.skipSpaces()
+			if !p.eof() {
+				return nil, p.errorf("unexpected %q after closing '}'", p.peek())
+			}
+			return matchers, nil
+		}
+
+		m, err := p.parseMatcher()
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+
+		p.skipSpaces()
+		if p.eof() || (braces && p.peek() == '}') {
+			continue
+		}
+
+		if !p.consume(',') {
+			return nil, p.errorf("unexpected %q: expected ',' or end of input", p.peek())
+		}
+	}
+}
+
+func (p *matcherParser) parseMatcher() (*labels.Matcher, error) {
+	namePos := p.pos
+	name, err := p.parseString("label name")
+	if err != nil {
+		return nil, err
+	}
+
+	if !model.LabelName(name).IsValid() {
+		return nil, &matcherParseError{pos: namePos + 1, msg: fmt.Sprintf("invalid label name %q", name)}
+	}
+
+	p.skipSpaces()
+	t, err := p.parseOperator()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	valuePos := p.pos
+	value, err := p.parseString("label value")
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := labels.NewMatcher(t, name, value)
+	if err != nil {
+		return nil, &matcherParseError{pos: valuePos + 1, msg: fmt.Sprintf("invalid regular expression %q: %v", value, err)}
+	}
+
+	return m, nil
+}
+
+func (p *matcherParser) parseOperator() (labels.MatchType, error) {
+	switch {
+	case p.consumeString("=~"):
+		return labels.MatchRegexp, nil
+	case p.consumeString("!~"):
+		return labels.MatchNotRegexp, nil
+	case p.consumeString("!="):
+		return labels.MatchNotEqual, nil
+	case p.consumeString("="):
+		return labels.MatchEqual, nil
+	}
+
+	if p.eof() {
+		return 0, p.errorf("unexpected end of input: expected one of '=', '!=', '=~' or '!~'")
+	}
+
+	return 0, p.errorf("unexpected %q: expected one of '=', '!=', '=~' or '!~'", p.peek())
+}
+
+// parseString parses either a double-quoted or an unquoted string.
+func (p *matcherParser) parseString(what string) (string, error) {
+	if p.eof() {
+		return "", p.errorf("unexpected end of input: expected %s", what)
+	}
+
+	if p.peek() == '"' {
+		return p.parseQuotedString()
+	}
+
+	start := p.pos
+	for !p.eof() && !isReservedMatcherRune(p.peek()) {
+		p.pos++
+	}
+
+	if p.pos == start {
+		return "", p.errorf("unexpected %q: expected %s", p.peek(), what)
+	}
+
+	return string(p.input[start:p.pos]), nil
+}
+
+func (p *matcherParser) parseQuotedString() (string, error) {
+	start := p.pos
+	p.pos++
+
+	escaped := false
+	for ; !p.eof(); p.pos++ {
+		r := p.peek()
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			p.pos++
+			s, err := strconv.Unquote(string(p.input[start:p.pos]))
+			if err != nil {
+				return "", &matcherParseError{pos: start + 1, msg: fmt.Sprintf("invalid quoted string %s", string(p.input[start:p.pos]))}
+			}
+			return s, nil
+		}
+	}
+
+	return "", &matcherParseError{pos: start + 1, msg: "missing closing '\"'"}
+}
+
+func (p *matcherParser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *matcherParser) consume(r rune) bool {
+	if p.eof() || p.peek() != r {
+		return false
+	}
+
+	p.pos++
+	return true
+}
+
+func (p *matcherParser) consumeString(s string) bool {
+	r := []rune(s)
+	if len(p.input)-p.pos < len(r) || string(p.input[p.pos:p.pos+len(r)]) != s {
+		return false
+	}
+
+	p.pos += len(r)
+	return true
+}
+
+func (p *matcherParser) peek() rune {
+	return p.input[p.pos]
+}
+
+func (p *matcherParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *matcherParser) errorf(format string, args ...interface{}) error {
+	return &matcherParseError{pos: p.pos + 1, msg: fmt.Sprintf(format, args...)}
+}
+
+func isReservedMatcherRune(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`{}!=~,"'`, r)
+}
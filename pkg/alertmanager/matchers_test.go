@@ -0,0 +1,136 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"testing"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+)
+
+func TestParseMatchers(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		expected []string
+		err      string
+	}{
+		{
+			in: "",
+		},
+		{
+			in: "{}",
+		},
+		{
+			in:       "severity=critical",
+			expected: []string{`severity="critical"`},
+		},
+		{
+			in:       `{severity!="info", team=~"infra|db",job!~node.*,}`,
+			expected: []string{`severity!="info"`, `team=~"infra|db"`, `job!~"node.*"`},
+		},
+		{
+			in:       `summary="a \"quoted\", {value}"`,
+			expected: []string{`summary="a \"quoted\", {value}"`},
+		},
+		{
+			in:       `  instance = "localhost:9090"  `,
+			expected: []string{`instance="localhost:9090"`},
+		},
+		{
+			in:  `{severity="critical"`,
+			err: "column 21: missing closing '}'",
+		},
+		{
+			in:  `{severity="critical"} foo`,
+			err: `column 23: unexpected 'f' after closing '}'`,
+		},
+		{
+			in:  `severity="critical`,
+			err: `column 10: missing closing '"'`,
+		},
+		{
+			in:  `severity=~"critical("`,
+			err: "column 11: invalid regular expression \"critical(\": error parsing regexp: missing closing ): `^(?:critical()$`",
+		},
+		{
+			in:  `1severity=critical`,
+			err: `column 1: invalid label name "1severity"`,
+		},
+		{
+			in:  `severity critical`,
+			err: `column 10: unexpected 'c': expected one of '=', '!=', '=~' or '!~'`,
+		},
+		{
+			in:  `severity=`,
+			err: `column 10: unexpected end of input: expected label value`,
+		},
+		{
+			in:  `severity=critical team=infra`,
+			err: `column 19: unexpected 't': expected ',' or end of input`,
+		},
+		{
+			in:  `severity=crit"ical"`,
+			err: `column 14: unexpected '"': expected ',' or end of input`,
+		},
+		{
+			in:  `summary="\q"`,
+			err: `column 9: invalid quoted string "\q"`,
+		},
+		{
+			in:  "severity=\xff",
+			err: "column 1: invalid UTF-8 string",
+		},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			matchers, err := parseMatchers(tc.in)
+			if tc.err != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got none", tc.err)
+				}
+				if err.Error() != tc.err {
+					t.Fatalf("expected error %q, got %q", tc.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if len(matchers) != len(tc.expected) {
+				t.Fatalf("expected %d matchers, got %d", len(tc.expected), len(matchers))
+			}
+
+			for i, m := range matchers {
+				if m.String() != tc.expected[i] {
+					t.Fatalf("expected matcher %q, got %q", tc.expected[i], m.String())
+				}
+			}
+		})
+	}
+}
+
+func TestParseMatchersType(t *testing.T) {
+	matchers, err := parseMatchers(`a=1,b!=2,c=~3,d!~4`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, expected := range []labels.MatchType{labels.MatchEqual, labels.MatchNotEqual, labels.MatchRegexp, labels.MatchNotRegexp} {
+		if matchers[i].Type != expected {
+			t.Fatalf("expected matcher %d to be of type %v, got %v", i, expected, matchers[i].Type)
+		}
+	}
+}
@@ -15,6 +15,8 @@
 package alertmanager
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"reflect"
@@ -23,6 +25,7 @@ import (
 	"time"
 
 	"github.com/blang/semver/v4"
+	"github.com/prometheus-operator/prometheus-operator/pkg/alertmanager/clustertlsconfig"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
@@ -31,12 +34,14 @@ import (
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
 	"github.com/prometheus-operator/prometheus-operator/pkg/listwatch"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+	"github.com/prometheus-operator/prometheus-operator/pkg/webconfig"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/mitchellh/hashstructure"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -46,7 +51,9 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 )
 
@@ -65,9 +72,11 @@ var (
 // Operator manages life cycle of Alertmanager deployments and
 // monitoring configurations.
 type Operator struct {
-	kclient kubernetes.Interface
-	mclient monitoringclient.Interface
-	logger  log.Logger
+	kclient       kubernetes.Interface
+	mclient       monitoringclient.Interface
+	mdClient      metadata.Interface
+	logger        log.Logger
+	eventRecorder record.EventRecorder
 
 	nsAlrtInf    cache.SharedIndexInformer
 	nsAlrtCfgInf cache.SharedIndexInformer
@@ -94,6 +103,19 @@ type Config struct {
 	Labels                       operator.Labels
 	AlertManagerSelector         string
 	SecretListWatchSelector      string
+	SecretLabelSelector          string
+	Workers                      int
+	GarbageCollection            bool
+	GarbageCollectionDryRun      bool
+	// Namespaces whose AlertmanagerConfig resources may define
+	// cross-namespace inhibition rules.
+	CrossNamespaceInhibitionNamespaces map[string]struct{}
+	// Namespaces from which the receivers of the AlertmanagerConfig
+	// resources may reference Secrets and ConfigMaps, in addition to their
+	// own namespace.
+	AlertmanagerConfigSecretNamespaces map[string]struct{}
+	// Strategy used to reload the configuration of the pods.
+	ReloadStrategy operator.ReloadStrategy
 }
 
 // New creates a new controller.
@@ -114,24 +136,41 @@ func New(ctx context.Context, c operator.Config, logger log.Logger, r prometheus
 	}
 
 	o := &Operator{
-		kclient: client,
-		mclient: mclient,
-		logger:  logger,
-		queue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "alertmanager"),
-		metrics: operator.NewMetrics("alertmanager", r),
+		kclient:       client,
+		mclient:       mclient,
+		logger:        logger,
+		queue:         workqueue.NewNamedRateLimitingQueue(c.WorkqueueRateLimiter.NewRateLimiter(), "alertmanager"),
+		metrics:       operator.NewMetrics("alertmanager", r),
+		eventRecorder: operator.NewEventRecorder(client, "alertmanager-controller"),
 		config: Config{
-			Host:                         c.Host,
-			LocalHost:                    c.LocalHost,
-			ClusterDomain:                c.ClusterDomain,
-			ReloaderConfig:               c.ReloaderConfig,
-			AlertmanagerDefaultBaseImage: c.AlertmanagerDefaultBaseImage,
-			Namespaces:                   c.Namespaces,
-			Labels:                       c.Labels,
-			AlertManagerSelector:         c.AlertManagerSelector,
-			SecretListWatchSelector:      c.SecretListWatchSelector,
+			Host:                               c.Host,
+			LocalHost:                          c.LocalHost,
+			ClusterDomain:                      c.ClusterDomain,
+			ReloaderConfig:                     c.ReloaderConfig,
+			AlertmanagerDefaultBaseImage:       c.AlertmanagerDefaultBaseImage,
+			Namespaces:                         c.Namespaces,
+			Labels:                             c.Labels,
+			AlertManagerSelector:               c.AlertManagerSelector,
+			SecretListWatchSelector:            c.SecretListWatchSelector,
+			SecretLabelSelector:                c.SecretLabelSelector,
+			Workers:                            c.AlertmanagerWorkers,
+			GarbageCollection:                  c.GarbageCollection,
+			GarbageCollectionDryRun:            c.GarbageCollectionDryRun,
+			CrossNamespaceInhibitionNamespaces: c.CrossNamespaceInhibitionNamespaces,
+			AlertmanagerConfigSecretNamespaces: c.AlertmanagerConfigSecretNamespaces,
+			ReloadStrategy:                     c.AlertmanagerReloadStrategy,
 		},
 	}
 
+	// The controller only needs the metadata of the Secrets to trigger the
+	// reconciliations.
+	if c.MetadataOnlyInformers {
+		o.mdClient, err = metadata.NewForConfig(cfg)
+		if err != nil {
+			return nil, errors.Wrap(err, "instantiating metadata client failed")
+		}
+	}
+
 	if err := o.bootstrap(ctx); err != nil {
 		return nil, err
 	}
@@ -186,16 +225,34 @@ func (c *Operator) bootstrap(ctx context.Context) error {
 	if err != nil {
 		return errors.Wrap(err, "can not parse secrets selector value")
 	}
-	c.secrInfs, err = informers.NewInformersForResource(
-		informers.NewKubeInformerFactories(
+	secretLabelSelector, err := labels.Parse(c.config.SecretLabelSelector)
+	if err != nil {
+		return errors.Wrap(err, "can not parse secrets label selector value")
+	}
+	secretListOptions := func(options *metav1.ListOptions) {
+		options.FieldSelector = secretListWatchSelector.String()
+		options.LabelSelector = secretLabelSelector.String()
+	}
+
+	secretFactories := informers.NewKubeInformerFactories(
+		c.config.Namespaces.AllowList,
+		c.config.Namespaces.DenyList,
+		c.kclient,
+		resyncPeriod,
+		secretListOptions,
+	)
+	if c.mdClient != nil {
+		secretFactories = informers.NewMetadataInformerFactories(
 			c.config.Namespaces.AllowList,
 			c.config.Namespaces.DenyList,
-			c.kclient,
+			c.mdClient,
 			resyncPeriod,
-			func(options *metav1.ListOptions) {
-				options.FieldSelector = secretListWatchSelector.String()
-			},
-		),
+			secretListOptions,
+		)
+	}
+
+	c.secrInfs, err = informers.NewInformersForResource(
+		secretFactories,
 		v1.SchemeGroupVersion.WithResource("secrets"),
 	)
 	if err != nil {
@@ -360,12 +417,13 @@ func (c *Operator) handleSecretDelete(obj interface{}) {
 }
 
 func (c *Operator) handleSecretUpdate(old, cur interface{}) {
-	if old.(*v1.Secret).ResourceVersion == cur.(*v1.Secret).ResourceVersion {
+	oldObj, ok := c.getObject(old)
+	if !ok {
 		return
 	}
 
 	o, ok := c.getObject(cur)
-	if ok {
+	if ok && oldObj.GetResourceVersion() != o.GetResourceVersion() {
 		level.Debug(c.logger).Log("msg", "Secret updated")
 		c.metrics.TriggerByCounter("Secret", "update").Inc()
 
@@ -434,10 +492,11 @@ func (c *Operator) enqueueForNamespace(nsName string) {
 	}
 }
 
-// Run the controller.
-func (c *Operator) Run(ctx context.Context) error {
-	defer c.queue.ShutDown()
-
+// StartInformers checks the connection to the API server, starts the
+// informers and waits for their caches to be synced. The informers run until
+// the context is canceled. It is called by all the replicas of the operator,
+// including the ones which don't hold the leadership.
+func (c *Operator) StartInformers(ctx context.Context) error {
 	errChan := make(chan error)
 	go func() {
 		v, err := c.kclient.Discovery().ServerVersion()
@@ -459,8 +518,6 @@ func (c *Operator) Run(ctx context.Context) error {
 		return nil
 	}
 
-	go c.worker(ctx)
-
 	go c.alrtInfs.Start(ctx.Done())
 	go c.alrtCfgInfs.Start(ctx.Done())
 	go c.secrInfs.Start(ctx.Done())
@@ -474,6 +531,23 @@ func (c *Operator) Run(ctx context.Context) error {
 	}
 	c.addHandlers()
 
+	return nil
+}
+
+// Run starts the reconciliation workers and the garbage collector and blocks
+// until the context is canceled. StartInformers must have returned before Run
+// is called. With leader election, only the leader calls Run.
+func (c *Operator) Run(ctx context.Context) error {
+	defer c.queue.ShutDown()
+
+	for i := 0; i < c.config.Workers; i++ {
+		go c.worker(ctx)
+	}
+
+	if c.config.GarbageCollection {
+		go c.newGarbageCollector().Run(ctx, operator.DefaultGarbageCollectionInterval)
+	}
+
 	c.metrics.Ready().Set(1)
 	<-ctx.Done()
 	return nil
@@ -733,33 +807,69 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 	am.APIVersion = monitoringv1.SchemeGroupVersion.String()
 	am.Kind = monitoringv1.AlertmanagersKind
 
-	if am.Spec.Paused {
+	if !c.config.Namespaces.Sharding.OwnsNamespace(c.nsAlrtInf.GetStore(), am.Namespace) {
+		level.Debug(c.logger).Log("msg", "skipping alertmanager assigned to another shard", "key", key)
 		return nil
 	}
 
 	logger := log.With(c.logger, "key", key)
+
+	if am.Spec.Paused {
+		if uErr := c.updateStatus(ctx, logger, key, am, nil, nil); uErr != nil {
+			level.Warn(logger).Log("msg", "failed to update the status", "err", uErr)
+		}
+		return nil
+	}
+
 	level.Info(logger).Log("msg", "sync alertmanager")
 
+	selection, err := c.syncAlertmanager(ctx, logger, key, am)
+	if uErr := c.updateStatus(ctx, logger, key, am, selection, err); uErr != nil {
+		level.Warn(logger).Log("msg", "failed to update the status", "err", uErr)
+	}
+
+	return err
+}
+
+// syncAlertmanager reconciles the configuration, the secrets, the governing
+// service and the StatefulSet of an Alertmanager object. It returns the
+// AlertmanagerConfig resources selected for the configuration (nil when the
+// selection didn't happen).
+func (c *Operator) syncAlertmanager(ctx context.Context, logger log.Logger, key string, am *monitoringv1.Alertmanager) (*configSelection, error) {
 	assetStore := assets.NewStore(c.kclient.CoreV1(), c.kclient.CoreV1())
 
-	if err := c.provisionAlertmanagerConfiguration(ctx, am, assetStore); err != nil {
-		return errors.Wrap(err, "provision alertmanager configuration")
+	selection, err := c.provisionAlertmanagerConfiguration(ctx, am, assetStore)
+	if err != nil {
+		operator.RecordWarningEvent(c.eventRecorder, am, operator.ConfigGenerationFailedReason, "Failed to generate the configuration: %v", err)
+		return selection, errors.Wrap(err, "provision alertmanager configuration")
 	}
 
 	if err := c.createOrUpdateTLSAssetSecret(ctx, am, assetStore); err != nil {
-		return errors.Wrap(err, "creating tls asset secret failed")
+		return selection, errors.Wrap(err, "creating tls asset secret failed")
+	}
+
+	if err := c.createOrUpdateClusterTLSConfigSecret(ctx, am); err != nil {
+		return selection, errors.Wrap(err, "creating cluster TLS config secret failed")
+	}
+
+	if err := c.createOrUpdateWebConfigSecret(ctx, am, assetStore); err != nil {
+		return selection, errors.Wrap(err, "creating web config secret failed")
+	}
+
+	if c.config.ReloadStrategy == operator.OperatorReloadStrategy {
+		c.reloadPods(ctx, logger, key, am, assetStore)
 	}
 
 	// Create governing service if it doesn't exist.
 	svcClient := c.kclient.CoreV1().Services(am.Namespace)
-	if err = k8sutil.CreateOrUpdateService(ctx, svcClient, makeStatefulSetService(am, c.config)); err != nil {
-		return errors.Wrap(err, "synchronizing governing service failed")
+	if err = k8sutil.ApplyService(ctx, svcClient, makeStatefulSetService(am, c.config), operator.ApplyConflictEventHandler(c.eventRecorder, am)); err != nil {
+		return selection, errors.Wrap(err, "synchronizing governing service failed")
 	}
 
 	obj, err := c.ssetInfs.Get(alertmanagerKeyToStatefulSetKey(key))
 	exists := !apierrors.IsNotFound(err)
 	if err != nil && exists {
-		return errors.Wrap(err, "failed to retrieve statefulset")
+		return selection, errors.Wrap(err, "failed to retrieve statefulset")
 	}
 
 	oldSpec := appsv1.StatefulSetSpec{}
@@ -770,12 +880,12 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 
 	newSSetInputHash, err := createSSetInputHash(*am, c.config, oldSpec)
 	if err != nil {
-		return err
+		return selection, err
 	}
 
 	sset, err := makeStatefulSet(am, c.config, newSSetInputHash)
 	if err != nil {
-		return errors.Wrap(err, "failed to make statefulset")
+		return selection, errors.Wrap(err, "failed to make statefulset")
 	}
 	operator.SanitizeSTS(sset)
 
@@ -787,19 +897,20 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 	}
 	if newSSetInputHash == oldSSetInputHash {
 		level.Debug(logger).Log("msg", "new statefulset generation inputs match current, skipping any actions")
-		return nil
+		return selection, nil
 	}
 
 	if !exists {
 		level.Debug(logger).Log("msg", "no current statefulset found")
 		level.Debug(logger).Log("msg", "creating statefulset")
-		if _, err := ssetClient.Create(ctx, sset, metav1.CreateOptions{}); err != nil {
-			return errors.Wrap(err, "creating statefulset failed")
+		if err := k8sutil.ApplyStatefulSet(ctx, ssetClient, sset, operator.ApplyConflictEventHandler(c.eventRecorder, am)); err != nil {
+			operator.RecordWarningEvent(c.eventRecorder, am, operator.StatefulSetUpdateFailedReason, "Failed to create the StatefulSet %s: %v", sset.Name, err)
+			return selection, errors.Wrap(err, "creating statefulset failed")
 		}
-		return nil
+		return selection, nil
 	}
 
-	err = k8sutil.UpdateStatefulSet(ctx, ssetClient, sset)
+	err = k8sutil.ApplyStatefulSet(ctx, ssetClient, sset, operator.ApplyConflictEventHandler(c.eventRecorder, am))
 	sErr, ok := err.(*apierrors.StatusError)
 
 	if ok && sErr.ErrStatus.Code == 422 && sErr.ErrStatus.Reason == metav1.StatusReasonInvalid {
@@ -814,16 +925,18 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 		level.Info(logger).Log("msg", "recreating AlertManager StatefulSet because the update operation wasn't possible", "reason", strings.Join(failMsg, ", "))
 		propagationPolicy := metav1.DeletePropagationForeground
 		if err := ssetClient.Delete(ctx, sset.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil {
-			return errors.Wrap(err, "failed to delete StatefulSet to avoid forbidden action")
+			operator.RecordWarningEvent(c.eventRecorder, am, operator.StatefulSetUpdateFailedReason, "Failed to delete the StatefulSet %s to recreate it: %v", sset.Name, err)
+			return selection, errors.Wrap(err, "failed to delete StatefulSet to avoid forbidden action")
 		}
-		return nil
+		return selection, nil
 	}
 
 	if err != nil {
-		return errors.Wrap(err, "updating StatefulSet failed")
+		operator.RecordWarningEvent(c.eventRecorder, am, operator.StatefulSetUpdateFailedReason, "Failed to update the StatefulSet %s: %v", sset.Name, err)
+		return selection, errors.Wrap(err, "updating StatefulSet failed")
 	}
 
-	return nil
+	return selection, nil
 }
 
 func createSSetInputHash(a monitoringv1.Alertmanager, c Config, s appsv1.StatefulSetSpec) (string, error) {
@@ -844,83 +957,196 @@ func createSSetInputHash(a monitoringv1.Alertmanager, c Config, s appsv1.Statefu
 	return fmt.Sprintf("%d", hash), nil
 }
 
-func (c *Operator) provisionAlertmanagerConfiguration(ctx context.Context, am *monitoringv1.Alertmanager, store *assets.Store) error {
+// renderedConfig is the Alertmanager configuration generated for an
+// Alertmanager object.
+type renderedConfig struct {
+	// Content of the configuration file.
+	data []byte
+	// Keys of the user-provided configuration secret which are copied to
+	// the generated secret (e.g. templates).
+	additionalData map[string][]byte
+}
+
+// provisionAlertmanagerConfiguration generates the Alertmanager configuration
+// secret and returns the outcome of the AlertmanagerConfig selection.
+func (c *Operator) provisionAlertmanagerConfiguration(ctx context.Context, am *monitoringv1.Alertmanager, store *assets.Store) (*configSelection, error) {
 	namespacedLogger := log.With(c.logger, "alertmanager", am.Name, "namespace", am.Namespace)
 
-	secretName := defaultConfigSecretName(am.Name)
-	if am.Spec.ConfigSecret != "" {
-		secretName = am.Spec.ConfigSecret
+	rendered, selection, err := c.renderAlertmanagerConfiguration(ctx, am, store)
+	if err != nil {
+		return selection, err
 	}
 
-	// Tentatively retrieve the secret containing the user-provided Alertmanager
-	// configuration.
-	secret, err := c.kclient.CoreV1().Secrets(am.Namespace).Get(ctx, secretName, metav1.GetOptions{})
-	if err != nil && !apierrors.IsNotFound(err) {
-		return errors.Wrap(err, "get base configuration secret")
+	if selection.invalidConfig != nil {
+		// Keep the last valid configuration rather than breaking the running
+		// Alertmanager pods.
+		_, err := c.kclient.CoreV1().Secrets(am.Namespace).Get(ctx, generatedConfigSecretName(am.Name), metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return selection, errors.Wrap(selection.invalidConfig, "generated Alertmanager config is invalid")
+			}
+			return selection, errors.Wrap(err, "get generated config secret")
+		}
+
+		level.Warn(namespacedLogger).Log("msg", "generated config is invalid, keeping the previous config", "err", selection.invalidConfig)
+		operator.RecordWarningEvent(c.eventRecorder, am, operator.InvalidConfigurationReason, "The generated configuration is invalid, keeping the previous configuration: %v", selection.invalidConfig)
+		return selection, nil
 	}
 
-	var secretData map[string][]byte
-	if secret != nil {
-		secretData = secret.Data
+	if selection.validationSkipped {
+		operator.RecordWarningEvent(c.eventRecorder, am, operator.ConfigValidationSkippedReason, "The generated configuration wasn't validated: Alertmanager %s is newer than the configuration parser of the operator", operator.StringValOrDefault(am.Spec.Version, operator.DefaultAlertmanagerVersion))
 	}
 
-	rawBaseConfig := []byte(`route:
+	err = c.createOrUpdateGeneratedConfigSecret(ctx, am, rendered.data, rendered.additionalData)
+	if err != nil {
+		return selection, errors.Wrap(err, "create or update generated config secret failed")
+	}
+
+	return selection, nil
+}
+
+// renderAlertmanagerConfiguration generates the Alertmanager configuration
+// without modifying the generated secret and returns the outcome of the
+// AlertmanagerConfig selection. The generated configuration is invalid when
+// the invalidConfig field of the selection is set.
+func (c *Operator) renderAlertmanagerConfiguration(ctx context.Context, am *monitoringv1.Alertmanager, store *assets.Store) (*renderedConfig, *configSelection, error) {
+	namespacedLogger := log.With(c.logger, "alertmanager", am.Name, "namespace", am.Namespace)
+
+	amVersion := operator.StringValOrDefault(am.Spec.Version, operator.DefaultAlertmanagerVersion)
+	version, err := semver.ParseTolerant(amVersion)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse alertmanager version")
+	}
+
+	if err := validateTemplates(am.Spec.Templates); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid templates")
+	}
+
+	generator := newConfigGenerator(namespacedLogger, version, store, am.Spec.AlertmanagerConfigMatcherStrategy)
+
+	var (
+		baseConfig *alertmanagerConfig
+		baseSource string
+		secretData map[string][]byte
+	)
+
+	if am.Spec.AlertmanagerConfiguration != nil {
+		// The global AlertmanagerConfig takes precedence over the
+		// user-provided configuration secret.
+		baseConfig, err = c.loadGlobalAlertmanagerConfig(ctx, am, generator, store)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "base config from AlertmanagerConfig could not be generated")
+		}
+		baseSource = fmt.Sprintf("%s %s/%s", monitoringv1alpha1.AlertmanagerConfigKind, am.Namespace, am.Spec.AlertmanagerConfiguration.Name)
+		baseConfig.Templates = append(baseConfig.Templates, templatesPaths(am.Spec.Templates)...)
+	} else {
+		secretName := defaultConfigSecretName(am.Name)
+		if am.Spec.ConfigSecret != "" {
+			secretName = am.Spec.ConfigSecret
+		}
+		baseSource = fmt.Sprintf("Secret %s/%s", am.Namespace, secretName)
+
+		// Tentatively retrieve the secret containing the user-provided Alertmanager
+		// configuration.
+		secret, err := c.kclient.CoreV1().Secrets(am.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, nil, errors.Wrap(err, "get base configuration secret")
+		}
+
+		if secret != nil {
+			secretData = secret.Data
+		}
+
+		rawBaseConfig := []byte(`route:
   receiver: 'null'
 receivers:
 - name: 'null'`)
-	if len(secretData[alertmanagerConfigFile]) > 0 {
-		rawBaseConfig = secretData[alertmanagerConfigFile]
-	} else {
-		if secret == nil {
-			level.Info(namespacedLogger).Log("msg", "base config secret not found", "secret", secretName)
+		if len(secretData[alertmanagerConfigFile]) > 0 {
+			rawBaseConfig = secretData[alertmanagerConfigFile]
 		} else {
-			level.Info(namespacedLogger).
-				Log("msg", "key not found in base config secret", "secret", secretName, "key", alertmanagerConfigFile)
+			if secret == nil {
+				level.Info(namespacedLogger).Log("msg", "base config secret not found", "secret", secretName)
+			} else {
+				level.Info(namespacedLogger).
+					Log("msg", "key not found in base config secret", "secret", secretName, "key", alertmanagerConfigFile)
+			}
 		}
-	}
 
-	baseConfig, err := loadCfg(string(rawBaseConfig))
-	if err != nil {
-		return errors.Wrap(err, "base config from Secret could not be parsed")
-	}
+		baseConfig, err = loadCfg(string(rawBaseConfig))
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "base config from Secret could not be parsed")
+		}
+
+		if resolveTemplatePaths(baseConfig) || len(am.Spec.Templates) > 0 {
+			baseConfig.Templates = append(baseConfig.Templates, templatesPaths(am.Spec.Templates)...)
+			rawBaseConfig, err = yaml.Marshal(baseConfig)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "failed to marshal base config with templates")
+			}
+		}
 
-	// If no AlertmanagerConfig selectors are configured, the user wants to
-	// manage configuration themselves.
-	if am.Spec.AlertmanagerConfigSelector == nil {
-		level.Debug(namespacedLogger).
-			Log("msg", "no AlertmanagerConfig selector specified, copying base config as-is",
-				"base config secret", secretName, "mounted config secret", generatedConfigSecretName(am.Name))
+		// If no AlertmanagerConfig selectors are configured, the user wants to
+		// manage configuration themselves.
+		if am.Spec.AlertmanagerConfigSelector == nil {
+			level.Debug(namespacedLogger).
+				Log("msg", "no AlertmanagerConfig selector specified, copying base config as-is",
+					"base config secret", secretName, "mounted config secret", generatedConfigSecretName(am.Name))
 
-		err = c.createOrUpdateGeneratedConfigSecret(ctx, am, rawBaseConfig, secretData)
-		if err != nil {
-			return errors.Wrap(err, "create or update generated config secret failed")
+			return &renderedConfig{data: rawBaseConfig, additionalData: secretData}, &configSelection{}, nil
 		}
-		return nil
 	}
 
-	amVersion := operator.StringValOrDefault(am.Spec.Version, operator.DefaultAlertmanagerVersion)
-	version, err := semver.ParseTolerant(amVersion)
+	amConfigs, rejected, err := c.selectAlertmanagerConfigs(ctx, am, store)
 	if err != nil {
-		return errors.Wrap(err, "failed to parse alertmanager version")
+		return nil, nil, errors.Wrap(err, "selecting AlertmanagerConfigs failed")
+	}
+
+	// The global AlertmanagerConfig only defines the top-level route even
+	// when it is also matched by the selectors.
+	if am.Spec.AlertmanagerConfiguration != nil {
+		delete(amConfigs, am.Namespace+"/"+am.Spec.AlertmanagerConfiguration.Name)
 	}
 
-	amConfigs, err := c.selectAlertmanagerConfigs(ctx, am, store)
+	selection := &configSelection{selected: len(amConfigs), rejected: rejected}
+
+	// The base configuration is modified by the generator, a copy is kept to
+	// troubleshoot invalid configurations.
+	rawBaseConfig, err := yaml.Marshal(baseConfig)
 	if err != nil {
-		return errors.Wrap(err, "selecting AlertmanagerConfigs failed")
+		return nil, selection, errors.Wrap(err, "failed to marshal base config")
 	}
 
-	generator := newConfigGenerator(namespacedLogger, version, store)
 	generatedConfig, err := generator.generateConfig(ctx, *baseConfig, amConfigs)
 	if err != nil {
-		return errors.Wrap(err, "generating Alertmanager config yaml failed")
+		return nil, selection, errors.Wrap(err, "generating Alertmanager config yaml failed")
 	}
 
-	err = c.createOrUpdateGeneratedConfigSecret(ctx, am, generatedConfig, secretData)
+	selection.invalidConfig = generator.checkGeneratedConfig(ctx, generatedConfig, rawBaseConfig, baseSource, amConfigs)
+	selection.validationSkipped = !generator.canValidateConfig()
+
+	return &renderedConfig{data: generatedConfig, additionalData: secretData}, selection, nil
+}
+
+// loadGlobalAlertmanagerConfig returns the base configuration generated from
+// the AlertmanagerConfig object referenced by the Alertmanager object.
+func (c *Operator) loadGlobalAlertmanagerConfig(ctx context.Context, am *monitoringv1.Alertmanager, generator *configGenerator, store *assets.Store) (*alertmanagerConfig, error) {
+	name := am.Spec.AlertmanagerConfiguration.Name
+
+	amc, err := c.mclient.MonitoringV1alpha1().AlertmanagerConfigs(am.Namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		return errors.Wrap(err, "create or update generated config secret failed")
+		return nil, errors.Wrapf(err, "failed to get AlertmanagerConfig %s/%s", am.Namespace, name)
 	}
 
-	return nil
+	err = checkReceiverSecretNamespaces(amc, c.config.AlertmanagerConfigSecretNamespaces)
+	if err == nil {
+		err = checkGlobalAlertmanagerConfig(ctx, amc, store)
+	}
+	if err != nil {
+		operator.RecordWarningEvent(c.eventRecorder, am, operator.ResourceRejectedReason, "%s %s/%s was rejected: %v", monitoringv1alpha1.AlertmanagerConfigKind, am.Namespace, name, err)
+		return nil, errors.Wrapf(err, "invalid AlertmanagerConfig %s/%s", am.Namespace, name)
+	}
+
+	return generator.generateBaseConfig(ctx, amc, am.Spec.AlertmanagerConfiguration.Global)
 }
 
 func (c *Operator) createOrUpdateGeneratedConfigSecret(ctx context.Context, am *monitoringv1.Alertmanager, conf []byte, additionalData map[string][]byte) error {
@@ -942,15 +1168,15 @@ func (c *Operator) createOrUpdateGeneratedConfigSecret(ctx context.Context, am *
 				},
 			},
 		},
-		Data: map[string][]byte{},
 	}
 
-	for k, v := range additionalData {
-		generatedConfigSecret.Data[k] = v
+	data, err := makeGeneratedConfigData(conf, additionalData, c.config.ReloadStrategy != operator.OperatorReloadStrategy)
+	if err != nil {
+		return err
 	}
-	generatedConfigSecret.Data[alertmanagerConfigFile] = conf
+	generatedConfigSecret.Data = data
 
-	err := k8sutil.CreateOrUpdateSecret(ctx, sClient, generatedConfigSecret)
+	err = k8sutil.ApplySecret(ctx, sClient, generatedConfigSecret, operator.ApplyConflictEventHandler(c.eventRecorder, am))
 	if err != nil {
 		return errors.Wrap(err, "failed to update generated config secret")
 	}
@@ -958,7 +1184,48 @@ func (c *Operator) createOrUpdateGeneratedConfigSecret(ctx context.Context, am *
 	return nil
 }
 
-func (c *Operator) selectAlertmanagerConfigs(ctx context.Context, am *monitoringv1.Alertmanager, store *assets.Store) (map[string]*monitoringv1alpha1.AlertmanagerConfig, error) {
+// makeGeneratedConfigData returns the content of the generated configuration
+// secret. The configuration is compressed like the Prometheus configuration
+// unless the pods don't run the config reloader which decompresses it. The
+// additional data (e.g. templates) is copied as-is.
+func makeGeneratedConfigData(conf []byte, additionalData map[string][]byte, compress bool) (map[string][]byte, error) {
+	key, value := alertmanagerConfigFile, conf
+	if compress {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(conf); err != nil {
+			return nil, errors.Wrap(err, "failed to compress the configuration")
+		}
+		if err := w.Close(); err != nil {
+			return nil, errors.Wrap(err, "failed to compress the configuration")
+		}
+		key, value = alertmanagerConfigFileCompressed, buf.Bytes()
+	}
+
+	data := map[string][]byte{key: value}
+	size := len(value)
+
+	for k, v := range additionalData {
+		// The configuration of the user-provided secret is superseded by
+		// the generated one.
+		if k == alertmanagerConfigFile || k == alertmanagerConfigFileCompressed {
+			continue
+		}
+		data[k] = v
+		size += len(v)
+	}
+
+	if size > v1.MaxSecretSize {
+		return nil, errors.Errorf("the generated configuration and the additional data of the secret are %d bytes which exceeds the limit of %d bytes", size, v1.MaxSecretSize)
+	}
+
+	return data, nil
+}
+
+// selectAlertmanagerConfigs returns the valid AlertmanagerConfig resources
+// selected by the Alertmanager object, indexed by <namespace>/<name>, and the
+// reasons why the other selected resources were rejected.
+func (c *Operator) selectAlertmanagerConfigs(ctx context.Context, am *monitoringv1.Alertmanager, store *assets.Store) (map[string]*monitoringv1alpha1.AlertmanagerConfig, map[string]string, error) {
 	namespaces := []string{}
 
 	// If 'AlertmanagerConfigNamespaceSelector' is nil, only check own namespace.
@@ -969,14 +1236,14 @@ func (c *Operator) selectAlertmanagerConfigs(ctx context.Context, am *monitoring
 	} else {
 		amConfigNSSelector, err := metav1.LabelSelectorAsSelector(am.Spec.AlertmanagerConfigNamespaceSelector)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		err = cache.ListAll(c.nsAlrtCfgInf.GetStore(), amConfigNSSelector, func(obj interface{}) {
 			namespaces = append(namespaces, obj.(*v1.Namespace).Name)
 		})
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to list namespaces")
+			return nil, nil, errors.Wrap(err, "failed to list namespaces")
 		}
 
 		level.Debug(c.logger).Log("msg", "filtering namespaces to select AlertmanagerConfigs from", "namespaces", strings.Join(namespaces, ","), "namespace", am.Namespace, "alertmanager", am.Name)
@@ -987,7 +1254,7 @@ func (c *Operator) selectAlertmanagerConfigs(ctx context.Context, am *monitoring
 
 	amConfigSelector, err := metav1.LabelSelectorAsSelector(am.Spec.AlertmanagerConfigSelector)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, ns := range namespaces {
@@ -998,16 +1265,25 @@ func (c *Operator) selectAlertmanagerConfigs(ctx context.Context, am *monitoring
 			}
 		})
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to list alertmanager configs in namespace %s", ns)
+			return nil, nil, errors.Wrapf(err, "failed to list alertmanager configs in namespace %s", ns)
 		}
 	}
 
-	var rejected int
+	rejected := map[string]string{}
 	res := make(map[string]*monitoringv1alpha1.AlertmanagerConfig, len(amConfigs))
 
 	for namespaceAndName, amc := range amConfigs {
-		if err := checkAlertmanagerConfig(ctx, amc, store); err != nil {
-			rejected++
+		// The secret namespaces are verified first to avoid reading
+		// Secrets from namespaces which aren't allowed.
+		err := checkReceiverSecretNamespaces(amc, c.config.AlertmanagerConfigSecretNamespaces)
+		if err == nil {
+			err = checkAlertmanagerConfig(ctx, amc, store)
+		}
+		if err == nil {
+			err = checkCrossNamespaceInhibitRules(amc, c.config.CrossNamespaceInhibitionNamespaces)
+		}
+		if err != nil {
+			rejected[namespaceAndName] = err.Error()
 			level.Warn(c.logger).Log(
 				"msg", "skipping alertmanagerconfig",
 				"error", err.Error(),
@@ -1015,6 +1291,7 @@ func (c *Operator) selectAlertmanagerConfigs(ctx context.Context, am *monitoring
 				"namespace", am.Namespace,
 				"alertmanager", am.Name,
 			)
+			operator.RecordWarningEvent(c.eventRecorder, am, operator.ResourceRejectedReason, "%s %s was rejected: %v", monitoringv1alpha1.AlertmanagerConfigKind, namespaceAndName, err)
 			continue
 		}
 
@@ -1029,73 +1306,148 @@ func (c *Operator) selectAlertmanagerConfigs(ctx context.Context, am *monitoring
 
 	if amKey, ok := c.keyFunc(am); ok {
 		c.metrics.SetSelectedResources(amKey, monitoringv1alpha1.AlertmanagerConfigKind, len(res))
-		c.metrics.SetRejectedResources(amKey, monitoringv1alpha1.AlertmanagerConfigKind, rejected)
+		c.metrics.SetRejectedResources(amKey, monitoringv1alpha1.AlertmanagerConfigKind, len(rejected))
 	}
 
-	return res, nil
+	return res, rejected, nil
 }
 
 // checkAlertmanagerConfig verifies that an AlertmanagerConfig object is valid
 // and has no missing references to other objects.
 func checkAlertmanagerConfig(ctx context.Context, amc *monitoringv1alpha1.AlertmanagerConfig, store *assets.Store) error {
-	receiverNames, err := checkReceivers(ctx, amc, store)
-	if err != nil {
+	if err := ValidateAlertmanagerConfig(amc); err != nil {
 		return err
 	}
 
-	return validateAlertManagerRoutes(amc.Spec.Route, receiverNames, true)
+	return checkReceivers(ctx, amc, store)
 }
 
-func checkReceivers(ctx context.Context, amc *monitoringv1alpha1.AlertmanagerConfig, store *assets.Store) (map[string]struct{}, error) {
-	receiverNames, err := validateReceivers(amc.Spec.Receivers)
-	if err != nil {
-		return nil, errors.Wrap(err, "checkReceivers: failed to validateReceivers")
+// checkCrossNamespaceInhibitRules verifies that the AlertmanagerConfig object
+// defines cross-namespace inhibition rules only if its namespace is allowed to.
+func checkCrossNamespaceInhibitRules(amc *monitoringv1alpha1.AlertmanagerConfig, allowedNamespaces map[string]struct{}) error {
+	if _, found := allowedNamespaces[amc.Namespace]; found {
+		return nil
+	}
+
+	for i, rule := range amc.Spec.InhibitRules {
+		if rule.CrossNamespace {
+			return errors.Errorf("inhibitRules[%d]: cross-namespace inhibition rules aren't allowed in namespace %q", i, amc.Namespace)
+		}
 	}
 
+	return nil
+}
+
+// checkReceiverSecretNamespaces verifies that the receivers of the
+// AlertmanagerConfig object reference Secrets and ConfigMaps either from the
+// object's namespace or from an allowed namespace.
+func checkReceiverSecretNamespaces(amc *monitoringv1alpha1.AlertmanagerConfig, allowedNamespaces map[string]struct{}) error {
+	for i, receiver := range amc.Spec.Receivers {
+		if receiver.SecretNamespace == "" || receiver.SecretNamespace == amc.Namespace {
+			continue
+		}
+
+		if _, found := allowedNamespaces[receiver.SecretNamespace]; !found {
+			return errors.Errorf("receivers[%d]: referencing secrets from namespace %q isn't allowed", i, receiver.SecretNamespace)
+		}
+	}
+
+	return nil
+}
+
+// checkGlobalAlertmanagerConfig verifies that an AlertmanagerConfig object
+// can define the top-level route of the Alertmanager configuration and has no
+// missing references to other objects.
+func checkGlobalAlertmanagerConfig(ctx context.Context, amc *monitoringv1alpha1.AlertmanagerConfig, store *assets.Store) error {
+	if err := validateGlobalAlertmanagerConfig(amc); err != nil {
+		return err
+	}
+
+	return checkReceivers(ctx, amc, store)
+}
+
+func checkReceivers(ctx context.Context, amc *monitoringv1alpha1.AlertmanagerConfig, store *assets.Store) error {
+	var err error
+
 	for i, receiver := range amc.Spec.Receivers {
 		amcKey := fmt.Sprintf("alertmanagerConfig/%s/%s/%d", amc.GetNamespace(), amc.GetName(), i)
 
-		err = checkPagerDutyConfigs(ctx, receiver.PagerDutyConfigs, amc.GetNamespace(), amcKey, store)
+		namespace := amc.GetNamespace()
+		if receiver.SecretNamespace != "" {
+			namespace = receiver.SecretNamespace
+		}
+
+		err = checkPagerDutyConfigs(ctx, receiver.PagerDutyConfigs, namespace, amcKey, store)
+		if err != nil {
+			return err
+		}
+
+		err = checkOpsGenieConfigs(ctx, receiver.OpsGenieConfigs, namespace, amcKey, store)
+		if err != nil {
+			return err
+		}
+		err = checkSlackConfigs(ctx, receiver.SlackConfigs, namespace, amcKey, store)
+		if err != nil {
+			return err
+		}
+
+		err = checkWebhookConfigs(ctx, receiver.WebhookConfigs, namespace, amcKey, store)
+		if err != nil {
+			return err
+		}
+
+		err = checkWechatConfigs(ctx, receiver.WeChatConfigs, namespace, amcKey, store)
+		if err != nil {
+			return err
+		}
+
+		err = checkEmailConfigs(ctx, receiver.EmailConfigs, namespace, amcKey, store)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		err = checkOpsGenieConfigs(ctx, receiver.OpsGenieConfigs, amc.GetNamespace(), amcKey, store)
+		err = checkVictorOpsConfigs(ctx, receiver.VictorOpsConfigs, namespace, amcKey, store)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		err = checkSlackConfigs(ctx, receiver.SlackConfigs, amc.GetNamespace(), amcKey, store)
+
+		err = checkPushoverConfigs(ctx, receiver.PushoverConfigs, namespace, amcKey, store)
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		err = checkTelegramConfigs(ctx, receiver.TelegramConfigs, namespace, amcKey, store)
+		if err != nil {
+			return err
 		}
 
-		err = checkWebhookConfigs(ctx, receiver.WebhookConfigs, amc.GetNamespace(), amcKey, store)
+		err = checkDiscordConfigs(ctx, receiver.DiscordConfigs, namespace, amcKey, store)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		err = checkWechatConfigs(ctx, receiver.WeChatConfigs, amc.GetNamespace(), amcKey, store)
+		err = checkMSTeamsConfigs(ctx, receiver.MSTeamsConfigs, namespace, amcKey, store)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		err = checkEmailConfigs(ctx, receiver.EmailConfigs, amc.GetNamespace(), amcKey, store)
+		err = checkMSTeamsV2Configs(ctx, receiver.MSTeamsV2Configs, namespace, amcKey, store)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		err = checkVictorOpsConfigs(ctx, receiver.VictorOpsConfigs, amc.GetNamespace(), amcKey, store)
+		err = checkSNSConfigs(ctx, receiver.SNSConfigs, namespace, amcKey, store)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		err = checkPushoverConfigs(ctx, receiver.PushoverConfigs, amc.GetNamespace(), amcKey, store)
+		err = checkWebexConfigs(ctx, receiver.WebexConfigs, namespace, amcKey, store)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return receiverNames, nil
+	return nil
 }
 
 func checkPagerDutyConfigs(ctx context.Context, configs []monitoringv1alpha1.PagerDutyConfig, namespace string, key string, store *assets.Store) error {
@@ -1266,6 +1618,129 @@ func checkPushoverConfigs(ctx context.Context, configs []monitoringv1alpha1.Push
 	return nil
 }
 
+func checkTelegramConfigs(ctx context.Context, configs []monitoringv1alpha1.TelegramConfig, namespace string, key string, store *assets.Store) error {
+	for i, config := range configs {
+		botToken, err := store.GetSecretKey(ctx, namespace, *config.BotToken)
+		if err != nil {
+			return err
+		}
+		if botToken == "" {
+			return errors.New("mandatory field botToken is empty")
+		}
+
+		telegramConfigKey := fmt.Sprintf("%s/telegram/%d", key, i)
+		if err := configureHTTPConfigInStore(ctx, config.HTTPConfig, namespace, telegramConfigKey, store); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkDiscordConfigs(ctx context.Context, configs []monitoringv1alpha1.DiscordConfig, namespace string, key string, store *assets.Store) error {
+	for i, config := range configs {
+		webhookURL, err := store.GetSecretKey(ctx, namespace, *config.APIURL)
+		if err != nil {
+			return err
+		}
+		if webhookURL == "" {
+			return errors.New("mandatory field apiURL is empty")
+		}
+
+		discordConfigKey := fmt.Sprintf("%s/discord/%d", key, i)
+		if err := configureHTTPConfigInStore(ctx, config.HTTPConfig, namespace, discordConfigKey, store); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkMSTeamsConfigs(ctx context.Context, configs []monitoringv1alpha1.MSTeamsConfig, namespace string, key string, store *assets.Store) error {
+	for i, config := range configs {
+		if err := checkWebhookURLSecret(ctx, config.WebhookURL, namespace, store); err != nil {
+			return err
+		}
+
+		msTeamsConfigKey := fmt.Sprintf("%s/msteams/%d", key, i)
+		if err := configureHTTPConfigInStore(ctx, config.HTTPConfig, namespace, msTeamsConfigKey, store); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkMSTeamsV2Configs(ctx context.Context, configs []monitoringv1alpha1.MSTeamsV2Config, namespace string, key string, store *assets.Store) error {
+	for i, config := range configs {
+		if err := checkWebhookURLSecret(ctx, config.WebhookURL, namespace, store); err != nil {
+			return err
+		}
+
+		msTeamsV2ConfigKey := fmt.Sprintf("%s/msteamsv2/%d", key, i)
+		if err := configureHTTPConfigInStore(ctx, config.HTTPConfig, namespace, msTeamsV2ConfigKey, store); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkSNSConfigs(ctx context.Context, configs []monitoringv1alpha1.SNSConfig, namespace string, key string, store *assets.Store) error {
+	for i, config := range configs {
+		if config.Sigv4 != nil {
+			for _, secret := range []*v1.SecretKeySelector{config.Sigv4.AccessKey, config.Sigv4.SecretKey} {
+				if secret == nil {
+					continue
+				}
+				if _, err := store.GetSecretKey(ctx, namespace, *secret); err != nil {
+					return err
+				}
+			}
+		}
+
+		snsConfigKey := fmt.Sprintf("%s/sns/%d", key, i)
+		if err := configureHTTPConfigInStore(ctx, config.HTTPConfig, namespace, snsConfigKey, store); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkWebexConfigs(ctx context.Context, configs []monitoringv1alpha1.WebexConfig, namespace string, key string, store *assets.Store) error {
+	for i, config := range configs {
+		botToken, err := store.GetSecretKey(ctx, namespace, *config.BotToken)
+		if err != nil {
+			return err
+		}
+		if botToken == "" {
+			return errors.New("mandatory field botToken is empty")
+		}
+
+		webexConfigKey := fmt.Sprintf("%s/webex/%d", key, i)
+		if err := configureHTTPConfigInStore(ctx, config.HTTPConfig, namespace, webexConfigKey, store); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkWebhookURLSecret verifies that the secret holding a mandatory webhook
+// URL exists and isn't empty.
+func checkWebhookURLSecret(ctx context.Context, secret *v1.SecretKeySelector, namespace string, store *assets.Store) error {
+	webhookURL, err := store.GetSecretKey(ctx, namespace, *secret)
+	if err != nil {
+		return err
+	}
+	if webhookURL == "" {
+		return errors.New("mandatory field webhookUrl is empty")
+	}
+
+	return nil
+}
+
 // configureHTTPConfigInStore configure the asset store for HTTPConfigs.
 func configureHTTPConfigInStore(ctx context.Context, httpConfig *monitoringv1alpha1.HTTPConfig, namespace string, key string, store *assets.Store) error {
 	if httpConfig == nil {
@@ -1316,7 +1791,7 @@ func (c *Operator) createOrUpdateTLSAssetSecret(ctx context.Context, am *monitor
 		tlsAssetsSecret.Data[key.String()] = []byte(asset)
 	}
 
-	err := k8sutil.CreateOrUpdateSecret(ctx, sClient, tlsAssetsSecret)
+	err := k8sutil.ApplySecret(ctx, sClient, tlsAssetsSecret, operator.ApplyConflictEventHandler(c.eventRecorder, am))
 	if err != nil {
 		return errors.Wrap(err, "failed to create TLS assets secret for Alertmanager")
 	}
@@ -1324,6 +1799,139 @@ func (c *Operator) createOrUpdateTLSAssetSecret(ctx context.Context, am *monitor
 	return nil
 }
 
+// createOrUpdateClusterTLSConfigSecret creates the secret holding the TLS
+// configuration of the cluster protocol. The secret is garbage-collected when
+// the TLS configuration is removed from the spec.
+func (c *Operator) createOrUpdateClusterTLSConfigSecret(ctx context.Context, am *monitoringv1.Alertmanager) error {
+	if am.Spec.ClusterTLSConfig == nil {
+		return nil
+	}
+
+	boolTrue := true
+	sClient := c.kclient.CoreV1().Secrets(am.Namespace)
+
+	clusterTLSConfig, err := clustertlsconfig.New(clusterTLSConfigDir, clusterTLSConfigSecretName(am.Name), am.Spec.ClusterTLSConfig)
+	if err != nil {
+		return errors.Wrap(err, "invalid cluster TLS config")
+	}
+
+	ownerReference := metav1.OwnerReference{
+		APIVersion:         am.APIVersion,
+		BlockOwnerDeletion: &boolTrue,
+		Controller:         &boolTrue,
+		Kind:               am.Kind,
+		Name:               am.Name,
+		UID:                am.UID,
+	}
+
+	secret, err := clusterTLSConfig.MakeConfigFileSecret(c.config.Labels.Merge(managedByOperatorLabels), ownerReference)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate the cluster TLS config")
+	}
+
+	if err := k8sutil.ApplySecret(ctx, sClient, secret, operator.ApplyConflictEventHandler(c.eventRecorder, am)); err != nil {
+		return errors.Wrap(err, "failed to create the cluster TLS config secret for Alertmanager")
+	}
+
+	return nil
+}
+
+// createOrUpdateWebConfigSecret creates the secret holding the web
+// configuration file. The secret is garbage-collected when the web
+// configuration is removed from the spec.
+func (c *Operator) createOrUpdateWebConfigSecret(ctx context.Context, am *monitoringv1.Alertmanager, store *assets.Store) error {
+	if am.Spec.Web == nil {
+		return nil
+	}
+
+	boolTrue := true
+	sClient := c.kclient.CoreV1().Secrets(am.Namespace)
+
+	httpConfig := am.Spec.Web.HTTPConfig
+	if httpConfig != nil {
+		amVersion := operator.StringValOrDefault(am.Spec.Version, operator.DefaultAlertmanagerVersion)
+		version, err := semver.ParseTolerant(amVersion)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse alertmanager version")
+		}
+
+		if version.LT(semver.MustParse("0.24.0")) {
+			level.Warn(c.logger).Log("msg", "found web httpConfig setting but alertmanager version is < 0.24.0, ignoring", "version", version, "minimum_version", "0.24.0")
+			httpConfig = nil
+		}
+	}
+
+	webConfig, err := webconfig.New(webConfigDir, webConfigSecretName(am.Name), am.Spec.Web.TLSConfig, httpConfig)
+	if err != nil {
+		return errors.Wrap(err, "invalid web config")
+	}
+
+	if len(am.Spec.Web.BasicAuthUsers) > 0 {
+		users, err := basicAuthUsers(ctx, am, store)
+		if err != nil {
+			return err
+		}
+		webConfig.SetBasicAuthUsers(users)
+	}
+
+	ownerReference := metav1.OwnerReference{
+		APIVersion:         am.APIVersion,
+		BlockOwnerDeletion: &boolTrue,
+		Controller:         &boolTrue,
+		Kind:               am.Kind,
+		Name:               am.Name,
+		UID:                am.UID,
+	}
+
+	secret, err := webConfig.MakeConfigFileSecret(c.config.Labels.Merge(managedByOperatorLabels), ownerReference)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate the web config")
+	}
+
+	if err := k8sutil.ApplySecret(ctx, sClient, secret, operator.ApplyConflictEventHandler(c.eventRecorder, am)); err != nil {
+		return errors.Wrap(err, "failed to create the web config secret for Alertmanager")
+	}
+
+	return nil
+}
+
+// basicAuthUsers returns the password hashes of the web basic
+// authentication users indexed by user name. It verifies that the credentials
+// of the config-reloader match one of the users.
+func basicAuthUsers(ctx context.Context, am *monitoringv1.Alertmanager, store *assets.Store) (map[string]string, error) {
+	users := make(map[string]string, len(am.Spec.Web.BasicAuthUsers))
+	for i, u := range am.Spec.Web.BasicAuthUsers {
+		if _, found := users[u.Username]; found {
+			return nil, errors.Errorf("basicAuthUsers[%d]: duplicate user %q", i, u.Username)
+		}
+
+		hash, err := store.GetSecretKey(ctx, am.Namespace, u.PasswordHash)
+		if err != nil {
+			return nil, errors.Wrapf(err, "basicAuthUsers[%d]: failed to get the password hash", i)
+		}
+		users[u.Username] = hash
+	}
+
+	if am.Spec.Web.ReloaderBasicAuth == nil {
+		return nil, errors.New("reloaderBasicAuth must be defined when basicAuthUsers is defined")
+	}
+
+	username, err := store.GetSecretKey(ctx, am.Namespace, am.Spec.Web.ReloaderBasicAuth.Username)
+	if err != nil {
+		return nil, errors.Wrap(err, "reloaderBasicAuth: failed to get the username")
+	}
+
+	if _, found := users[username]; !found {
+		return nil, errors.Errorf("reloaderBasicAuth: user %q isn't defined in basicAuthUsers", username)
+	}
+
+	if _, err := store.GetSecretKey(ctx, am.Namespace, am.Spec.Web.ReloaderBasicAuth.Password); err != nil {
+		return nil, errors.Wrap(err, "reloaderBasicAuth: failed to get the password")
+	}
+
+	return users, nil
+}
+
 //checkAlertmanagerSpecDeprecation checks for deprecated fields in the prometheus spec and logs a warning if applicable
 func checkAlertmanagerSpecDeprecation(key string, a *monitoringv1.Alertmanager, logger log.Logger) {
 	deprecationWarningf := "alertmanager key=%v, field %v is deprecated, '%v' field should be used instead"
@@ -15,16 +15,24 @@
 package alertmanager
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
 	"testing"
 
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
 	v1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
@@ -798,10 +806,733 @@ func TestCheckAlertmanagerConfig(t *testing.T) {
 			},
 			ok: true,
 		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "valid-mute-time-intervals",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver:            "recv1",
+						MuteTimeIntervals:   []string{"business-hours"},
+						ActiveTimeIntervals: []string{"business-hours"},
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+					}},
+					MuteTimeIntervals: []monitoringv1alpha1.MuteTimeInterval{
+						{
+							Name: "business-hours",
+							TimeIntervals: []monitoringv1alpha1.TimeInterval{
+								{
+									Times:       []monitoringv1alpha1.TimeRange{{StartTime: "09:00", EndTime: "17:00"}},
+									Weekdays:    []monitoringv1alpha1.WeekdayRange{"monday:friday"},
+									DaysOfMonth: []monitoringv1alpha1.DayOfMonthRange{{Start: 1, End: -1}},
+									Months:      []monitoringv1alpha1.MonthRange{"january:march"},
+									Years:       []monitoringv1alpha1.YearRange{"2023:2024"},
+									Location:    "UTC",
+								},
+							},
+						},
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "missing-mute-time-interval",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver:          "recv1",
+						MuteTimeIntervals: []string{"weekends"},
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+					}},
+					MuteTimeIntervals: []monitoringv1alpha1.MuteTimeInterval{
+						{
+							Name: "business-hours",
+							TimeIntervals: []monitoringv1alpha1.TimeInterval{
+								{
+									Times:       []monitoringv1alpha1.TimeRange{{StartTime: "09:00", EndTime: "17:00"}},
+									Weekdays:    []monitoringv1alpha1.WeekdayRange{"monday:friday"},
+									DaysOfMonth: []monitoringv1alpha1.DayOfMonthRange{{Start: 1, End: -1}},
+									Months:      []monitoringv1alpha1.MonthRange{"january:march"},
+									Years:       []monitoringv1alpha1.YearRange{"2023:2024"},
+									Location:    "UTC",
+								},
+							},
+						},
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "invalid-time-range",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver:          "recv1",
+						MuteTimeIntervals: []string{"business-hours"},
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+					}},
+					MuteTimeIntervals: []monitoringv1alpha1.MuteTimeInterval{
+						{
+							Name: "business-hours",
+							TimeIntervals: []monitoringv1alpha1.TimeInterval{
+								{
+									Times: []monitoringv1alpha1.TimeRange{{StartTime: "17:00", EndTime: "09:00"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "invalid-weekday-range",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver:          "recv1",
+						MuteTimeIntervals: []string{"business-hours"},
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+					}},
+					MuteTimeIntervals: []monitoringv1alpha1.MuteTimeInterval{
+						{
+							Name: "business-hours",
+							TimeIntervals: []monitoringv1alpha1.TimeInterval{
+								{
+									Weekdays: []monitoringv1alpha1.WeekdayRange{"friday:monday"},
+								},
+							},
+						},
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "invalid-location",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver:          "recv1",
+						MuteTimeIntervals: []string{"business-hours"},
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+					}},
+					MuteTimeIntervals: []monitoringv1alpha1.MuteTimeInterval{
+						{
+							Name: "business-hours",
+							TimeIntervals: []monitoringv1alpha1.TimeInterval{
+								{
+									Location: "Mars/Olympus_Mons",
+								},
+							},
+						},
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "valid-telegram",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						TelegramConfigs: []monitoringv1alpha1.TelegramConfig{{
+							BotToken: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+								Key:                  "key1",
+							},
+							ChatID: 12345,
+						}},
+					}},
+				},
+			},
+			ok: true,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "telegram-missing-bot-token-key",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						TelegramConfigs: []monitoringv1alpha1.TelegramConfig{{
+							BotToken: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+								Key:                  "not-existing",
+							},
+							ChatID: 12345,
+						}},
+					}},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "telegram-missing-chat-id",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						TelegramConfigs: []monitoringv1alpha1.TelegramConfig{{
+							BotToken: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+								Key:                  "key1",
+							},
+						}},
+					}},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "valid-discord",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						DiscordConfigs: []monitoringv1alpha1.DiscordConfig{{
+							APIURL: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+								Key:                  "key1",
+							},
+						}},
+					}},
+				},
+			},
+			ok: true,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "discord-missing-api-url-key",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						DiscordConfigs: []monitoringv1alpha1.DiscordConfig{{
+							APIURL: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+								Key:                  "not-existing",
+							},
+						}},
+					}},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "discord-without-api-url",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name:           "recv1",
+						DiscordConfigs: []monitoringv1alpha1.DiscordConfig{{}},
+					}},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "valid-msteams",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						MSTeamsConfigs: []monitoringv1alpha1.MSTeamsConfig{{
+							WebhookURL: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+								Key:                  "key1",
+							},
+						}},
+					}},
+				},
+			},
+			ok: true,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "msteams-missing-webhook-url-key",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						MSTeamsConfigs: []monitoringv1alpha1.MSTeamsConfig{{
+							WebhookURL: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+								Key:                  "not-existing",
+							},
+						}},
+					}},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "valid-msteamsv2",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						MSTeamsV2Configs: []monitoringv1alpha1.MSTeamsV2Config{{
+							WebhookURL: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+								Key:                  "key1",
+							},
+						}},
+					}},
+				},
+			},
+			ok: true,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "msteamsv2-without-webhook-url",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name:             "recv1",
+						MSTeamsV2Configs: []monitoringv1alpha1.MSTeamsV2Config{{}},
+					}},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "valid-sns-topic",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						SNSConfigs: []monitoringv1alpha1.SNSConfig{{
+							TopicARN: "arn:aws:sns:us-east-2:123456789012:My-Topic",
+							Sigv4: &monitoringv1.Sigv4{
+								AccessKey: &v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+									Key:                  "key1",
+								},
+								SecretKey: &v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+									Key:                  "key1",
+								},
+							},
+						}},
+					}},
+				},
+			},
+			ok: true,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sns-with-topic-and-phone-number",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						SNSConfigs: []monitoringv1alpha1.SNSConfig{{
+							TopicARN:    "arn:aws:sns:us-east-2:123456789012:My-Topic",
+							PhoneNumber: "+12345678901",
+						}},
+					}},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sns-without-target",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						SNSConfigs: []monitoringv1alpha1.SNSConfig{{
+							Subject: "subject",
+						}},
+					}},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sns-sigv4-without-secret-key",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						SNSConfigs: []monitoringv1alpha1.SNSConfig{{
+							TopicARN: "arn:aws:sns:us-east-2:123456789012:My-Topic",
+							Sigv4: &monitoringv1.Sigv4{
+								AccessKey: &v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+									Key:                  "key1",
+								},
+							},
+						}},
+					}},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sns-sigv4-with-missing-secret-key",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						SNSConfigs: []monitoringv1alpha1.SNSConfig{{
+							TopicARN: "arn:aws:sns:us-east-2:123456789012:My-Topic",
+							Sigv4: &monitoringv1.Sigv4{
+								AccessKey: &v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+									Key:                  "key1",
+								},
+								SecretKey: &v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+									Key:                  "not-existing",
+								},
+							},
+						}},
+					}},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "valid-webex",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						WebexConfigs: []monitoringv1alpha1.WebexConfig{{
+							BotToken: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+								Key:                  "key1",
+							},
+							RoomID: "room",
+						}},
+					}},
+				},
+			},
+			ok: true,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "webex-missing-bot-token-key",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						WebexConfigs: []monitoringv1alpha1.WebexConfig{{
+							BotToken: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+								Key:                  "not-existing",
+							},
+							RoomID: "room",
+						}},
+					}},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "webex-without-room-id",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						WebexConfigs: []monitoringv1alpha1.WebexConfig{{
+							BotToken: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+								Key:                  "key1",
+							},
+						}},
+					}},
+				},
+			},
+			ok: false,
+		},
+		{
+			amConfig: &monitoringv1alpha1.AlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "webex-with-http-authorization",
+					Namespace: "ns1",
+				},
+				Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+					Route: &monitoringv1alpha1.Route{
+						Receiver: "recv1",
+					},
+					Receivers: []monitoringv1alpha1.Receiver{{
+						Name: "recv1",
+						WebexConfigs: []monitoringv1alpha1.WebexConfig{{
+							BotToken: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+								Key:                  "key1",
+							},
+							RoomID: "room",
+							HTTPConfig: &monitoringv1alpha1.HTTPConfig{
+								BearerTokenSecret: &v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+									Key:                  "key1",
+								},
+							},
+						}},
+					}},
+				},
+			},
+			ok: false,
+		},
+	} {
+		t.Run(tc.amConfig.Name, func(t *testing.T) {
+			store := assets.NewStore(c.CoreV1(), c.CoreV1())
+			err := checkAlertmanagerConfig(context.Background(), tc.amConfig, store)
+			if tc.ok {
+				if err != nil {
+					t.Fatalf("expecting no error but got %q", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expecting error but got none")
+			}
+		})
+	}
+}
+
+func TestCheckCrossNamespaceInhibitRules(t *testing.T) {
+	amConfig := func(ns string, crossNamespace bool) *monitoringv1alpha1.AlertmanagerConfig {
+		return &monitoringv1alpha1.AlertmanagerConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: ns,
+			},
+			Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+				InhibitRules: []monitoringv1alpha1.InhibitRule{
+					{
+						SourceMatch: []monitoringv1alpha1.Matcher{{Name: "alertname", Value: "NodeDown"}},
+						Equal:       []string{"node"},
+					},
+					{
+						SourceMatch:    []monitoringv1alpha1.Matcher{{Name: "alertname", Value: "NodeDown"}},
+						Equal:          []string{"node"},
+						CrossNamespace: crossNamespace,
+					},
+				},
+			},
+		}
+	}
+
+	allowed := map[string]struct{}{"monitoring": {}}
+
+	for _, tc := range []struct {
+		name     string
+		amConfig *monitoringv1alpha1.AlertmanagerConfig
+		ok       bool
+	}{
+		{
+			name:     "namespaced rules",
+			amConfig: amConfig("team", false),
+			ok:       true,
+		},
+		{
+			name:     "cross-namespace rule in allowed namespace",
+			amConfig: amConfig("monitoring", true),
+			ok:       true,
+		},
+		{
+			name:     "cross-namespace rule in other namespace",
+			amConfig: amConfig("team", true),
+			ok:       false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkCrossNamespaceInhibitRules(tc.amConfig, allowed)
+			if tc.ok {
+				if err != nil {
+					t.Fatalf("expecting no error but got %q", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expecting error but got none")
+			}
+		})
+	}
+}
+
+func TestCheckReceiverSecretNamespaces(t *testing.T) {
+	amConfig := func(secretNamespace string) *monitoringv1alpha1.AlertmanagerConfig {
+		return &monitoringv1alpha1.AlertmanagerConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "team",
+			},
+			Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+				Receivers: []monitoringv1alpha1.Receiver{
+					{Name: "local"},
+					{Name: "shared", SecretNamespace: secretNamespace},
+				},
+			},
+		}
+	}
+
+	allowed := map[string]struct{}{"shared-credentials": {}}
+
+	for _, tc := range []struct {
+		name     string
+		amConfig *monitoringv1alpha1.AlertmanagerConfig
+		ok       bool
+	}{
+		{
+			name:     "default namespace",
+			amConfig: amConfig(""),
+			ok:       true,
+		},
+		{
+			name:     "own namespace",
+			amConfig: amConfig("team"),
+			ok:       true,
+		},
+		{
+			name:     "allowed namespace",
+			amConfig: amConfig("shared-credentials"),
+			ok:       true,
+		},
+		{
+			name:     "other namespace",
+			amConfig: amConfig("kube-system"),
+			ok:       false,
+		},
 	} {
-		t.Run(tc.amConfig.Name, func(t *testing.T) {
-			store := assets.NewStore(c.CoreV1(), c.CoreV1())
-			err := checkAlertmanagerConfig(context.Background(), tc.amConfig, store)
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkReceiverSecretNamespaces(tc.amConfig, allowed)
 			if tc.ok {
 				if err != nil {
 					t.Fatalf("expecting no error but got %q", err)
@@ -827,11 +1558,13 @@ func TestListOptions(t *testing.T) {
 
 func TestProvisionAlertmanagerConfiguration(t *testing.T) {
 	for _, tc := range []struct {
-		am      *monitoringv1.Alertmanager
-		objects []runtime.Object
+		am        *monitoringv1.Alertmanager
+		objects   []runtime.Object
+		amConfigs []runtime.Object
 
-		ok           bool
-		expectedKeys []string
+		ok                bool
+		expectedKeys      []string
+		expectedTemplates []string
 	}{
 		{
 			am: &monitoringv1.Alertmanager{
@@ -1024,13 +1757,240 @@ func TestProvisionAlertmanagerConfiguration(t *testing.T) {
 			ok:           true,
 			expectedKeys: []string{"key1"},
 		},
+		{
+			am: &monitoringv1.Alertmanager{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "global-alertmanagerconfig",
+					Namespace: "test",
+				},
+				Spec: monitoringv1.AlertmanagerSpec{
+					AlertmanagerConfiguration: &monitoringv1.AlertmanagerConfiguration{
+						Name: "global",
+						Global: &monitoringv1.AlertmanagerGlobalConfig{
+							ResolveTimeout: "5m",
+						},
+					},
+					AlertmanagerConfigSelector: &metav1.LabelSelector{},
+				},
+			},
+			amConfigs: []runtime.Object{
+				&monitoringv1alpha1.AlertmanagerConfig{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "global",
+						Namespace: "test",
+					},
+					Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+						Route: &monitoringv1alpha1.Route{
+							Receiver: "default",
+						},
+						Receivers: []monitoringv1alpha1.Receiver{{Name: "default"}},
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			am: &monitoringv1.Alertmanager{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "missing-global-alertmanagerconfig",
+					Namespace: "test",
+				},
+				Spec: monitoringv1.AlertmanagerSpec{
+					AlertmanagerConfiguration: &monitoringv1.AlertmanagerConfiguration{
+						Name: "global",
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			am: &monitoringv1.Alertmanager{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "global-alertmanagerconfig-with-matchers",
+					Namespace: "test",
+				},
+				Spec: monitoringv1.AlertmanagerSpec{
+					AlertmanagerConfiguration: &monitoringv1.AlertmanagerConfiguration{
+						Name: "global",
+					},
+				},
+			},
+			amConfigs: []runtime.Object{
+				&monitoringv1alpha1.AlertmanagerConfig{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "global",
+						Namespace: "test",
+					},
+					Spec: monitoringv1alpha1.AlertmanagerConfigSpec{
+						Route: &monitoringv1alpha1.Route{
+							Receiver: "default",
+							Matchers: []monitoringv1alpha1.Matcher{{Name: "severity", Value: "critical"}},
+						},
+						Receivers: []monitoringv1alpha1.Receiver{{Name: "default"}},
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			am: &monitoringv1.Alertmanager{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "templates",
+					Namespace: "test",
+				},
+				Spec: monitoringv1.AlertmanagerSpec{
+					ConfigSecret: "amconfig",
+					Templates: []monitoringv1.SecretOrConfigMap{
+						{
+							ConfigMap: &v1.ConfigMapKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "templates"},
+								Key:                  "slack.tmpl",
+							},
+						},
+						{
+							Secret: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "templates"},
+								Key:                  "email.tmpl",
+							},
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "amconfig",
+						Namespace: "test",
+					},
+					Data: map[string][]byte{
+						"alertmanager.yaml": []byte(`{route: {receiver: empty}, receivers: [{name: empty}], templates: [/etc/alertmanager/config/*.tmpl]}`),
+					},
+				},
+			},
+			ok: true,
+			expectedTemplates: []string{
+				"/etc/alertmanager/config/*.tmpl",
+				"/etc/alertmanager/templates/slack.tmpl",
+				"/etc/alertmanager/templates/email.tmpl",
+			},
+		},
+		{
+			am: &monitoringv1.Alertmanager{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "relative-templates",
+					Namespace: "test",
+				},
+				Spec: monitoringv1.AlertmanagerSpec{
+					ConfigSecret: "amconfig",
+				},
+			},
+			objects: []runtime.Object{
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "amconfig",
+						Namespace: "test",
+					},
+					Data: map[string][]byte{
+						"alertmanager.yaml": []byte(`{route: {receiver: empty}, receivers: [{name: empty}], templates: ['*.tmpl', /etc/alertmanager/extra/*.tmpl]}`),
+						"slack.tmpl":        []byte(`{{ define "slack" }}{{ end }}`),
+					},
+				},
+			},
+			ok:           true,
+			expectedKeys: []string{"slack.tmpl"},
+			expectedTemplates: []string{
+				"/etc/alertmanager/config/*.tmpl",
+				"/etc/alertmanager/extra/*.tmpl",
+			},
+		},
+		{
+			am: &monitoringv1.Alertmanager{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "templates-with-selector",
+					Namespace: "test",
+				},
+				Spec: monitoringv1.AlertmanagerSpec{
+					ConfigSecret:               "amconfig",
+					AlertmanagerConfigSelector: &metav1.LabelSelector{},
+					Templates: []monitoringv1.SecretOrConfigMap{
+						{
+							ConfigMap: &v1.ConfigMapKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "templates"},
+								Key:                  "slack.tmpl",
+							},
+						},
+						{
+							Secret: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "templates"},
+								Key:                  "email.tmpl",
+							},
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "amconfig",
+						Namespace: "test",
+					},
+					Data: map[string][]byte{
+						"alertmanager.yaml": []byte(`{route: {receiver: empty}, receivers: [{name: empty}], templates: [/etc/alertmanager/config/*.tmpl]}`),
+					},
+				},
+			},
+			ok: true,
+			expectedTemplates: []string{
+				"/etc/alertmanager/config/*.tmpl",
+				"/etc/alertmanager/templates/slack.tmpl",
+				"/etc/alertmanager/templates/email.tmpl",
+			},
+		},
+		{
+			am: &monitoringv1.Alertmanager{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "templates-with-duplicate-keys",
+					Namespace: "test",
+				},
+				Spec: monitoringv1.AlertmanagerSpec{
+					ConfigSecret: "amconfig",
+					Templates: []monitoringv1.SecretOrConfigMap{
+						{
+							ConfigMap: &v1.ConfigMapKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "templates"},
+								Key:                  "slack.tmpl",
+							},
+						},
+						{
+							Secret: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "templates"},
+								Key:                  "slack.tmpl",
+							},
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "amconfig",
+						Namespace: "test",
+					},
+					Data: map[string][]byte{
+						"alertmanager.yaml": []byte(`{route: {receiver: empty}, receivers: [{name: empty}], templates: [/etc/alertmanager/config/*.tmpl]}`),
+					},
+				},
+			},
+			ok: false,
+		},
 	} {
 		t.Run(tc.am.Name, func(t *testing.T) {
 			c := fake.NewSimpleClientset(tc.objects...)
+			c.PrependReactor("patch", "secrets", applySecretReactor(c))
 
 			o := &Operator{
 				kclient: c,
-				mclient: monitoringfake.NewSimpleClientset(),
+				mclient: monitoringfake.NewSimpleClientset(tc.amConfigs...),
 				logger:  log.NewNopLogger(),
 				metrics: operator.NewMetrics("alertmanager", prometheus.NewRegistry()),
 			}
@@ -1041,7 +2001,7 @@ func TestProvisionAlertmanagerConfiguration(t *testing.T) {
 			}
 
 			store := assets.NewStore(c.CoreV1(), c.CoreV1())
-			err = o.provisionAlertmanagerConfiguration(context.Background(), tc.am, store)
+			_, err = o.provisionAlertmanagerConfiguration(context.Background(), tc.am, store)
 
 			if !tc.ok {
 				if err == nil {
@@ -1059,7 +2019,7 @@ func TestProvisionAlertmanagerConfiguration(t *testing.T) {
 				t.Fatalf("unexpected error: %v", err)
 			}
 
-			expected := append(tc.expectedKeys, alertmanagerConfigFile)
+			expected := append(tc.expectedKeys, alertmanagerConfigFileCompressed)
 			if len(secret.Data) != len(expected) {
 				t.Fatalf("expecting %d items to be present in the generated secret but got %d", len(expected), len(secret.Data))
 			}
@@ -1068,6 +2028,219 @@ func TestProvisionAlertmanagerConfiguration(t *testing.T) {
 					t.Fatalf("expecting key %q to be present in the generated secret but got nothing", k)
 				}
 			}
+
+			if tc.expectedTemplates != nil {
+				cfg, err := loadCfg(string(gunzip(t, secret.Data[alertmanagerConfigFileCompressed])))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(cfg.Templates, tc.expectedTemplates) {
+					t.Fatalf("expecting templates %v but got %v", tc.expectedTemplates, cfg.Templates)
+				}
+			}
+		})
+	}
+}
+
+func TestMakeGeneratedConfigData(t *testing.T) {
+	conf := []byte(`{route: {receiver: empty}, receivers: [{name: empty}]}`)
+
+	data, err := makeGeneratedConfigData(conf, map[string][]byte{
+		alertmanagerConfigFile: []byte("user-provided"),
+		"template.tmpl":        []byte("template"),
+	}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(data) != 2 {
+		t.Fatalf("expecting 2 keys but got %d", len(data))
+	}
+	if string(data["template.tmpl"]) != "template" {
+		t.Fatalf("expecting the template to be copied but got %q", data["template.tmpl"])
+	}
+	if got := gunzip(t, data[alertmanagerConfigFileCompressed]); string(got) != string(conf) {
+		t.Fatalf("expecting configuration %q but got %q", conf, got)
+	}
+
+	// The configuration is compressed but the additional data isn't.
+	_, err = makeGeneratedConfigData(bytes.Repeat(conf, 100000), nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = makeGeneratedConfigData(conf, map[string][]byte{
+		"template.tmpl": bytes.Repeat([]byte("a"), v1.MaxSecretSize),
+	}, true)
+	if err == nil {
+		t.Fatal("expecting error but got none")
+	}
+
+	// The configuration isn't compressed when the pods don't run the config
+	// reloader.
+	data, err = makeGeneratedConfigData(conf, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data) != 1 || string(data[alertmanagerConfigFile]) != string(conf) {
+		t.Fatalf("expecting the uncompressed configuration but got %v", data)
+	}
+}
+
+func gunzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return b
+}
+
+// applySecretReactor emulates the server-side apply of Secrets which isn't
+// supported by the fake clientset.
+func applySecretReactor(c *fake.Clientset) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+
+		secret := &v1.Secret{}
+		if err := json.Unmarshal(patch.GetPatch(), secret); err != nil {
+			return true, nil, err
+		}
+		secret.Namespace = patch.GetNamespace()
+
+		_, err := c.Tracker().Get(patch.GetResource(), secret.Namespace, secret.Name)
+		if apierrors.IsNotFound(err) {
+			return true, secret, c.Tracker().Create(patch.GetResource(), secret, secret.Namespace)
+		}
+
+		return true, secret, c.Tracker().Update(patch.GetResource(), secret, secret.Namespace)
+	}
+}
+
+func TestBasicAuthUsers(t *testing.T) {
+	c := fake.NewSimpleClientset(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web-auth",
+				Namespace: "ns1",
+			},
+			Data: map[string][]byte{
+				"alice-hash": []byte("$2y$10$alice"),
+				"bob-hash":   []byte("$2y$10$bob"),
+				"username":   []byte("alice"),
+				"unknown":    []byte("carol"),
+				"password":   []byte("secret"),
+			},
+		},
+	)
+
+	selector := func(key string) v1.SecretKeySelector {
+		return v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "web-auth"},
+			Key:                  key,
+		}
+	}
+
+	users := []monitoringv1.WebBasicAuthUser{
+		{Username: "alice", PasswordHash: selector("alice-hash")},
+		{Username: "bob", PasswordHash: selector("bob-hash")},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		web       *monitoringv1.AlertmanagerWebSpec
+		expected  map[string]string
+		expectErr bool
+	}{
+		{
+			name: "valid users",
+			web: &monitoringv1.AlertmanagerWebSpec{
+				BasicAuthUsers: users,
+				ReloaderBasicAuth: &monitoringv1.BasicAuth{
+					Username: selector("username"),
+					Password: selector("password"),
+				},
+			},
+			expected: map[string]string{
+				"alice": "$2y$10$alice",
+				"bob":   "$2y$10$bob",
+			},
+		},
+		{
+			name: "missing reloader credentials",
+			web: &monitoringv1.AlertmanagerWebSpec{
+				BasicAuthUsers: users,
+			},
+			expectErr: true,
+		},
+		{
+			name: "unknown reloader user",
+			web: &monitoringv1.AlertmanagerWebSpec{
+				BasicAuthUsers: users,
+				ReloaderBasicAuth: &monitoringv1.BasicAuth{
+					Username: selector("unknown"),
+					Password: selector("password"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "missing password hash",
+			web: &monitoringv1.AlertmanagerWebSpec{
+				BasicAuthUsers: []monitoringv1.WebBasicAuthUser{
+					{Username: "alice", PasswordHash: selector("not-found")},
+				},
+				ReloaderBasicAuth: &monitoringv1.BasicAuth{
+					Username: selector("username"),
+					Password: selector("password"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "duplicate users",
+			web: &monitoringv1.AlertmanagerWebSpec{
+				BasicAuthUsers: append(users, monitoringv1.WebBasicAuthUser{Username: "alice", PasswordHash: selector("bob-hash")}),
+				ReloaderBasicAuth: &monitoringv1.BasicAuth{
+					Username: selector("username"),
+					Password: selector("password"),
+				},
+			},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			am := &monitoringv1.Alertmanager{
+				ObjectMeta: metav1.ObjectMeta{Name: "am", Namespace: "ns1"},
+				Spec:       monitoringv1.AlertmanagerSpec{Web: tc.web},
+			}
+
+			store := assets.NewStore(c.CoreV1(), c.CoreV1())
+			got, err := basicAuthUsers(context.Background(), am, store)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
 		})
 	}
 }
@@ -0,0 +1,152 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+)
+
+const redactedSecret = "<secret>"
+
+// secretFields are the fields of the Alertmanager configuration holding
+// credentials.
+var secretFields = map[string]struct{}{
+	"api_key":            {},
+	"api_secret":         {},
+	"auth_password":      {},
+	"auth_secret":        {},
+	"bearer_token":       {},
+	"bot_token":          {},
+	"client_secret":      {},
+	"credentials":        {},
+	"hipchat_auth_token": {},
+	"opsgenie_api_key":   {},
+	"password":           {},
+	"routing_key":        {},
+	"service_key":        {},
+	"slack_api_url":      {},
+	"smtp_auth_password": {},
+	"smtp_auth_secret":   {},
+	"token":              {},
+	"user_key":           {},
+	"victorops_api_key":  {},
+	"webhook_url":        {},
+	"wechat_api_secret":  {},
+}
+
+// secretURLFields are the URL fields embedding credentials, indexed by the
+// receiver field containing them.
+var secretURLFields = map[string]string{
+	"slack_configs":   "api_url",
+	"webhook_configs": "url",
+}
+
+// RenderConfig returns the Alertmanager configuration generated for the
+// Alertmanager object with the credentials redacted. The AlertmanagerConfig
+// resources which have been rejected and the validation errors are reported
+// as comments at the top of the configuration.
+//
+// Contrary to the reconciliation, the generated secret isn't modified.
+func (c *Operator) RenderConfig(ctx context.Context, namespace, name string) ([]byte, error) {
+	obj, err := c.alrtInfs.Get(namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+
+	am := obj.(*monitoringv1.Alertmanager)
+	store := assets.NewStore(c.kclient.CoreV1(), c.kclient.CoreV1())
+
+	rendered, selection, err := c.renderAlertmanagerConfiguration(ctx, am, store)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := redactSecrets(rendered.data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to redact the configuration")
+	}
+
+	var buf bytes.Buffer
+	writeSelectionComments(&buf, selection)
+	buf.Write(data)
+
+	return buf.Bytes(), nil
+}
+
+func writeSelectionComments(buf *bytes.Buffer, selection *configSelection) {
+	if selection.invalidConfig != nil {
+		fmt.Fprintf(buf, "# The configuration is invalid: %v\n", selection.invalidConfig)
+	}
+	if selection.validationSkipped {
+		fmt.Fprintf(buf, "# %s\n", validationSkippedMessage)
+	}
+
+	keys := make([]string, 0, len(selection.rejected))
+	for k := range selection.rejected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(buf, "# %s %s was rejected: %s\n", monitoringv1alpha1.AlertmanagerConfigKind, k, selection.rejected[k])
+	}
+}
+
+// redactSecrets replaces the credentials of the Alertmanager configuration by
+// a placeholder. The order of the fields is preserved.
+func redactSecrets(data []byte) ([]byte, error) {
+	var cfg yaml.MapSlice
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(redact(cfg, ""))
+}
+
+// redact walks the configuration recursively. The parent is the name of the
+// field holding v.
+func redact(v interface{}, parent string) interface{} {
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		for i := range v {
+			k, _ := v[i].Key.(string)
+
+			if s, ok := v[i].Value.(string); ok && s != "" {
+				if _, found := secretFields[k]; found || secretURLFields[parent] == k {
+					v[i].Value = redactedSecret
+				}
+				continue
+			}
+
+			v[i].Value = redact(v[i].Value, k)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i], parent)
+		}
+	}
+
+	return v
+}
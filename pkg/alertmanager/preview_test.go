@@ -0,0 +1,114 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	in := `global:
+  slack_api_url: https://hooks.slack.com/services/xxx
+  smtp_auth_password: pass
+route:
+  receiver: ns/foo/team
+receivers:
+- name: ns/foo/team
+  slack_configs:
+  - api_url: https://hooks.slack.com/services/yyy
+    channel: alerts
+  webhook_configs:
+  - url: http://webhook.example.com/token
+    http_config:
+      basic_auth:
+        username: user
+        password: pass
+  opsgenie_configs:
+  - api_url: https://api.opsgenie.com/
+    api_key: key
+templates: []
+`
+
+	expected := `global:
+  slack_api_url: <secret>
+  smtp_auth_password: <secret>
+route:
+  receiver: ns/foo/team
+receivers:
+- name: ns/foo/team
+  slack_configs:
+  - api_url: <secret>
+    channel: alerts
+  webhook_configs:
+  - url: <secret>
+    http_config:
+      basic_auth:
+        username: user
+        password: <secret>
+  opsgenie_configs:
+  - api_url: https://api.opsgenie.com/
+    api_key: <secret>
+templates: []
+`
+
+	out, err := redactSecrets([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(expected, string(out)); diff != "" {
+		t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteSelectionComments(t *testing.T) {
+	var buf bytes.Buffer
+	writeSelectionComments(&buf, &configSelection{
+		rejected: map[string]string{
+			"ns2/bar": "missing secret",
+			"ns1/foo": "invalid matcher",
+		},
+		invalidConfig: &invalidConfigError{source: "AlertmanagerConfig ns3/baz", err: errors.New("undefined receiver")},
+	})
+
+	expected := `# The configuration is invalid: AlertmanagerConfig ns3/baz: undefined receiver
+# AlertmanagerConfig ns1/foo was rejected: invalid matcher
+# AlertmanagerConfig ns2/bar was rejected: missing secret
+`
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+	}
+
+	buf.Reset()
+	writeSelectionComments(&buf, &configSelection{
+		rejected: map[string]string{
+			"ns1/foo": "invalid matcher",
+			"ns2/bar": "missing secret",
+		},
+		validationSkipped: true,
+	})
+
+	expected = `# ` + validationSkippedMessage + `
+# AlertmanagerConfig ns1/foo was rejected: invalid matcher
+# AlertmanagerConfig ns2/bar was rejected: missing secret
+`
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/expfmt"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+)
+
+const (
+	// configHashMetric is the metric exposed by Alertmanager with the hash of
+	// the loaded configuration.
+	configHashMetric = "alertmanager_config_hash"
+
+	// reloadCheckInterval is the delay before checking again the pods which
+	// haven't loaded the latest configuration. The kubelet updates the
+	// mounted secrets asynchronously so it can take up to a minute before
+	// the pods see the new configuration.
+	reloadCheckInterval = 15 * time.Second
+)
+
+// reloadClient is the HTTP client used to reload the Alertmanager pods.
+var reloadClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		// The pods are reached by IP address which isn't part of the
+		// certificate. Like the kubelet probes, the certificate isn't verified.
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	},
+}
+
+// reloadPods triggers the reload of the Alertmanager pods which haven't
+// loaded the generated configuration yet. It is used instead of the
+// config-reloader sidecar when the operator reload strategy is enabled.
+//
+// The pods which are behind are checked again after reloadCheckInterval
+// because the kubelet doesn't propagate the secret updates immediately.
+// Errors are only logged since they don't prevent the reconciliation.
+func (c *Operator) reloadPods(ctx context.Context, logger log.Logger, key string, am *monitoringv1.Alertmanager, store *assets.Store) {
+	secret, err := c.kclient.CoreV1().Secrets(am.Namespace).Get(ctx, generatedConfigSecretName(am.Name), metav1.GetOptions{})
+	if err != nil {
+		level.Warn(logger).Log("msg", "failed to get the generated configuration", "err", err)
+		return
+	}
+	expected := configHash(secret.Data[alertmanagerConfigFile])
+
+	pods, err := c.kclient.CoreV1().Pods(am.Namespace).List(ctx, ListOptions(am.Name))
+	if err != nil {
+		level.Warn(logger).Log("msg", "failed to list the Alertmanager pods", "err", err)
+		return
+	}
+
+	r := podReloader{
+		scheme:      "http",
+		routePrefix: "/",
+	}
+	if am.Spec.RoutePrefix != "" {
+		r.routePrefix = am.Spec.RoutePrefix
+	}
+	if am.Spec.Web != nil {
+		if am.Spec.Web.TLSConfig != nil {
+			r.scheme = "https"
+		}
+
+		if ba := am.Spec.Web.ReloaderBasicAuth; ba != nil {
+			if r.username, err = store.GetSecretKey(ctx, am.Namespace, ba.Username); err != nil {
+				level.Warn(logger).Log("msg", "failed to get the reloader username", "err", err)
+				return
+			}
+			if r.password, err = store.GetSecretKey(ctx, am.Namespace, ba.Password); err != nil {
+				level.Warn(logger).Log("msg", "failed to get the reloader password", "err", err)
+				return
+			}
+		}
+	}
+
+	var pending bool
+	for _, pod := range pods.Items {
+		// The pods which aren't running load the latest configuration when
+		// they start.
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+
+		host := net.JoinHostPort(pod.Status.PodIP, "9093")
+		hash, err := r.configHash(ctx, host)
+		if err != nil {
+			level.Warn(logger).Log("msg", "failed to get the configuration hash", "pod", pod.Name, "err", err)
+			pending = true
+			continue
+		}
+
+		if hash == expected {
+			continue
+		}
+
+		// The configuration is reloaded until the pod reports the expected
+		// hash.
+		pending = true
+		if err := r.reload(ctx, host); err != nil {
+			level.Warn(logger).Log("msg", "failed to reload the configuration", "pod", pod.Name, "err", err)
+			continue
+		}
+		level.Debug(logger).Log("msg", "configuration reloaded", "pod", pod.Name)
+	}
+
+	if pending {
+		c.queue.AddAfter(key, reloadCheckInterval)
+	}
+}
+
+// podReloader sends requests to the web endpoints of the Alertmanager pods.
+type podReloader struct {
+	scheme      string
+	routePrefix string
+	username    string
+	password    string
+}
+
+func (r *podReloader) do(ctx context.Context, method, host, endpoint string) (*http.Response, error) {
+	u := url.URL{
+		Scheme: r.scheme,
+		Host:   host,
+		Path:   path.Clean(r.routePrefix + endpoint),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+
+	resp, err := reloadClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Errorf("%s %s: unexpected status code %d", method, u.Path, resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+// configHash returns the hash of the configuration loaded by the pod.
+func (r *podReloader) configHash(ctx context.Context, host string) (float64, error) {
+	resp, err := r.do(ctx, http.MethodGet, host, "/metrics")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return parseConfigHash(resp.Body)
+}
+
+// reload triggers the reload of the configuration by the pod.
+func (r *podReloader) reload(ctx context.Context, host string) error {
+	resp, err := r.do(ctx, http.MethodPost, host, "/-/reload")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// parseConfigHash returns the value of the configuration hash metric from the
+// Prometheus text exposition format.
+func parseConfigHash(r io.Reader) (float64, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse the metrics")
+	}
+
+	mf, found := families[configHashMetric]
+	if !found || len(mf.GetMetric()) == 0 {
+		return 0, errors.Errorf("metric %s not found", configHashMetric)
+	}
+
+	return mf.GetMetric()[0].GetGauge().GetValue(), nil
+}
+
+// configHash returns the hash of the configuration as computed by
+// Alertmanager for the configHashMetric metric: the first 48 bits of the MD5
+// sum interpreted as a little-endian integer.
+func configHash(conf []byte) float64 {
+	sum := md5.Sum(conf)
+
+	b := make([]byte, 8)
+	copy(b, sum[0:6])
+
+	return float64(binary.LittleEndian.Uint64(b))
+}
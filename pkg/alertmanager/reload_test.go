@@ -0,0 +1,49 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigHash(t *testing.T) {
+	// Value reported by Alertmanager for a configuration file containing "foo".
+	if h := configHash([]byte("foo")); h != 213635349134764 {
+		t.Fatalf("expected hash 213635349134764, got %v", h)
+	}
+}
+
+func TestParseConfigHash(t *testing.T) {
+	metrics := `# HELP alertmanager_config_hash Hash of the currently loaded alertmanager configuration.
+# TYPE alertmanager_config_hash gauge
+alertmanager_config_hash 2.13635349134764e+14
+# HELP alertmanager_config_last_reload_successful Whether the last configuration reload attempt was successful.
+# TYPE alertmanager_config_last_reload_successful gauge
+alertmanager_config_last_reload_successful 1
+`
+
+	h, err := parseConfigHash(strings.NewReader(metrics))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h != configHash([]byte("foo")) {
+		t.Fatalf("expected hash %v, got %v", configHash([]byte("foo")), h)
+	}
+
+	if _, err := parseConfigHash(strings.NewReader("up 1\n")); err == nil {
+		t.Fatal("expecting error but got none")
+	}
+}
@@ -16,6 +16,7 @@ package alertmanager
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"path"
 	"strings"
@@ -28,9 +29,11 @@ import (
 
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
+	"github.com/prometheus-operator/prometheus-operator/pkg/alertmanager/clustertlsconfig"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+	"github.com/prometheus-operator/prometheus-operator/pkg/webconfig"
 )
 
 const (
@@ -39,6 +42,9 @@ const (
 	tlsAssetsDir           = "/etc/alertmanager/certs"
 	secretsDir             = "/etc/alertmanager/secrets/"
 	configmapsDir          = "/etc/alertmanager/configmaps/"
+	templatesDir           = "/etc/alertmanager/templates"
+	clusterTLSConfigDir    = "/etc/alertmanager/cluster_tls_config"
+	webConfigDir           = "/etc/alertmanager/web_config"
 	alertmanagerConfigDir  = "/etc/alertmanager/config"
 	alertmanagerConfigFile = "alertmanager.yaml"
 	alertmanagerStorageDir = "/alertmanager"
@@ -46,6 +52,15 @@ const (
 	defaultPortName        = "web"
 )
 
+const (
+	// The generated configuration is compressed to stay within the size
+	// limit of the secrets. The config reloader decompresses it into the
+	// output directory read by Alertmanager.
+	alertmanagerConfigFileCompressed = "alertmanager.yaml.gz"
+	alertmanagerConfigOutDir         = "/etc/alertmanager/config_out"
+	alertmanagerConfigEnvsubstFile   = "alertmanager.env.yaml"
+)
+
 var (
 	minReplicas         int32 = 1
 	probeTimeoutSeconds int32 = 3
@@ -130,6 +145,14 @@ func makeStatefulSet(am *monitoringv1.Alertmanager, config Config, inputHash str
 				EmptyDir: emptyDir,
 			},
 		})
+	} else if storageSpec.Ephemeral != nil {
+		ephemeral := storageSpec.Ephemeral
+		statefulset.Spec.Template.Spec.Volumes = append(statefulset.Spec.Template.Spec.Volumes, v1.Volume{
+			Name: volumeName(am.Name),
+			VolumeSource: v1.VolumeSource{
+				Ephemeral: ephemeral,
+			},
+		})
 	} else {
 		pvcTemplate := operator.MakeVolumeClaimTemplate(storageSpec.VolumeClaimTemplate)
 		if pvcTemplate.Name == "" {
@@ -223,13 +246,30 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 		return nil, errors.Wrap(err, "failed to parse alertmanager version")
 	}
 
+	// With the operator reload strategy, the pods don't run the
+	// config-reloader containers and Alertmanager reads the generated
+	// configuration directly from the secret volume.
+	operatorReload := config.ReloadStrategy == operator.OperatorReloadStrategy
+	if operatorReload && a.Spec.ListenLocal {
+		return nil, errors.New("listenLocal can't be enabled with the operator reload strategy")
+	}
+
+	configFile := path.Join(alertmanagerConfigOutDir, alertmanagerConfigEnvsubstFile)
+	if operatorReload {
+		configFile = path.Join(alertmanagerConfigDir, alertmanagerConfigFile)
+	}
+
 	amArgs := []string{
-		fmt.Sprintf("--config.file=%s", path.Join(alertmanagerConfigDir, alertmanagerConfigFile)),
+		fmt.Sprintf("--config.file=%s", configFile),
 		fmt.Sprintf("--storage.path=%s", alertmanagerStorageDir),
 		fmt.Sprintf("--data.retention=%s", a.Spec.Retention),
 	}
 
-	if *a.Spec.Replicas == 1 && !a.Spec.ForceEnableClusterMode {
+	if err := validateClusterPeering(a); err != nil {
+		return nil, errors.Wrap(err, "invalid clusterPeering")
+	}
+
+	if *a.Spec.Replicas == 1 && !a.Spec.ForceEnableClusterMode && !hasClusterPeers(a) {
 		amArgs = append(amArgs, "--cluster.listen-address=")
 	} else {
 		amArgs = append(amArgs, "--cluster.listen-address=[$(POD_IP)]:9094")
@@ -261,8 +301,12 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 		}
 	}
 
-	if a.Spec.ClusterAdvertiseAddress != "" {
-		amArgs = append(amArgs, fmt.Sprintf("--cluster.advertise-address=%s", a.Spec.ClusterAdvertiseAddress))
+	advertiseAddress := a.Spec.ClusterAdvertiseAddress
+	if a.Spec.ClusterPeering != nil && a.Spec.ClusterPeering.AdvertiseAddress != "" {
+		advertiseAddress = a.Spec.ClusterPeering.AdvertiseAddress
+	}
+	if advertiseAddress != "" {
+		amArgs = append(amArgs, fmt.Sprintf("--cluster.advertise-address=%s", advertiseAddress))
 	}
 
 	if a.Spec.ClusterGossipInterval != "" {
@@ -277,6 +321,25 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 		amArgs = append(amArgs, fmt.Sprintf("--cluster.peer-timeout=%s", a.Spec.ClusterPeerTimeout))
 	}
 
+	var (
+		webTLS            bool
+		reloaderBasicAuth *monitoringv1.BasicAuth
+	)
+	if a.Spec.Web != nil {
+		if version.LT(semver.MustParse("0.22.0")) {
+			return nil, errors.Errorf("web requires Alertmanager >= 0.22.0, current version is %s", version)
+		}
+
+		webTLS = a.Spec.Web.TLSConfig != nil
+
+		if len(a.Spec.Web.BasicAuthUsers) > 0 {
+			if a.Spec.Web.ReloaderBasicAuth == nil {
+				return nil, errors.New("web: reloaderBasicAuth must be defined when basicAuthUsers is defined")
+			}
+			reloaderBasicAuth = a.Spec.Web.ReloaderBasicAuth
+		}
+	}
+
 	livenessProbeHandler := v1.Handler{
 		HTTPGet: &v1.HTTPGetAction{
 			Path: path.Clean(webRoutePrefix + "/-/healthy"),
@@ -291,6 +354,24 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 		},
 	}
 
+	reloadScheme := "http"
+	if webTLS {
+		livenessProbeHandler.HTTPGet.Scheme = v1.URISchemeHTTPS
+		readinessProbeHandler.HTTPGet.Scheme = v1.URISchemeHTTPS
+		reloadScheme = "https"
+	}
+
+	// The probes can't authenticate against the web server because the
+	// kubelet has no access to the credentials.
+	if reloaderBasicAuth != nil {
+		livenessProbeHandler = v1.Handler{
+			TCPSocket: &v1.TCPSocketAction{Port: intstr.FromString(a.Spec.PortName)},
+		}
+		readinessProbeHandler = v1.Handler{
+			TCPSocket: &v1.TCPSocketAction{Port: intstr.FromString(a.Spec.PortName)},
+		}
+	}
+
 	var livenessProbe *v1.Probe
 	var readinessProbe *v1.Probe
 	if !a.Spec.ListenLocal {
@@ -354,6 +435,12 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 		amArgs = append(amArgs, fmt.Sprintf("--cluster.peer=%s", peer))
 	}
 
+	if a.Spec.ClusterPeering != nil {
+		for _, peer := range a.Spec.ClusterPeering.Peers {
+			amArgs = append(amArgs, fmt.Sprintf("--cluster.peer=%s", peerAddress(peer.Address)))
+		}
+	}
+
 	ports := []v1.ContainerPort{
 		{
 			Name:          "mesh-tcp",
@@ -424,6 +511,16 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 				},
 			},
 		},
+	}
+	if !operatorReload {
+		volumes = append(volumes, v1.Volume{
+			Name: "config-out",
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+	volumes = append(volumes, []v1.Volume{
 		{
 			Name: "tls-assets",
 			VolumeSource: v1.VolumeSource{
@@ -432,7 +529,7 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 				},
 			},
 		},
-	}
+	}...)
 
 	volName := volumeName(a.Name)
 	if a.Spec.Storage != nil {
@@ -446,6 +543,15 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 			Name:      "config-volume",
 			MountPath: alertmanagerConfigDir,
 		},
+	}
+	if !operatorReload {
+		amVolumeMounts = append(amVolumeMounts, v1.VolumeMount{
+			Name:      "config-out",
+			ReadOnly:  true,
+			MountPath: alertmanagerConfigOutDir,
+		})
+	}
+	amVolumeMounts = append(amVolumeMounts, []v1.VolumeMount{
 		{
 			Name:      "tls-assets",
 			ReadOnly:  true,
@@ -456,7 +562,7 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 			MountPath: alertmanagerStorageDir,
 			SubPath:   subPathForStorage(a.Spec.Storage),
 		},
-	}
+	}...)
 
 	reloadWatchDirs := []string{alertmanagerConfigDir}
 	configReloaderVolumeMounts := []v1.VolumeMount{
@@ -465,6 +571,10 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 			MountPath: alertmanagerConfigDir,
 			ReadOnly:  true,
 		},
+		{
+			Name:      "config-out",
+			MountPath: alertmanagerConfigOutDir,
+		},
 	}
 
 	for _, s := range a.Spec.Secrets {
@@ -509,6 +619,53 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 		reloadWatchDirs = append(reloadWatchDirs, mountPath)
 	}
 
+	if len(a.Spec.Templates) > 0 {
+		volumes = append(volumes, v1.Volume{
+			Name: "templates",
+			VolumeSource: v1.VolumeSource{
+				Projected: &v1.ProjectedVolumeSource{
+					Sources: templatesVolumeProjections(a.Spec.Templates),
+				},
+			},
+		})
+		mount := v1.VolumeMount{
+			Name:      "templates",
+			ReadOnly:  true,
+			MountPath: templatesDir,
+		}
+		amVolumeMounts = append(amVolumeMounts, mount)
+		configReloaderVolumeMounts = append(configReloaderVolumeMounts, mount)
+		reloadWatchDirs = append(reloadWatchDirs, templatesDir)
+	}
+
+	if a.Spec.ClusterTLSConfig != nil {
+		if version.LT(semver.MustParse("0.24.0")) {
+			return nil, errors.Errorf("clusterTLSConfig requires Alertmanager >= 0.24.0, current version is %s", version)
+		}
+
+		clusterTLSConfig, err := clustertlsconfig.New(clusterTLSConfigDir, clusterTLSConfigSecretName(a.Name), a.Spec.ClusterTLSConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid cluster TLS config")
+		}
+
+		arg, volume, mount := clusterTLSConfig.GetMountParameters()
+		amArgs = append(amArgs, arg)
+		volumes = append(volumes, volume)
+		amVolumeMounts = append(amVolumeMounts, mount)
+	}
+
+	if a.Spec.Web != nil {
+		webConfig, err := webconfig.New(webConfigDir, webConfigSecretName(a.Name), a.Spec.Web.TLSConfig, a.Spec.Web.HTTPConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid web config")
+		}
+
+		arg, webVolumes, webMounts := webConfig.GetMountParameters()
+		amArgs = append(amArgs, arg)
+		volumes = append(volumes, webVolumes...)
+		amVolumeMounts = append(amVolumeMounts, webMounts...)
+	}
+
 	amVolumeMounts = append(amVolumeMounts, a.Spec.VolumeMounts...)
 
 	terminationGracePeriod := int64(120)
@@ -518,45 +675,63 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 	var watchedDirectories []string
 	watchedDirectories = append(watchedDirectories, reloadWatchDirs...)
 
-	defaultContainers := []v1.Container{
+	amEnv := []v1.EnvVar{
 		{
-			Args:           amArgs,
-			Name:           "alertmanager",
-			Image:          amImagePath,
-			Ports:          ports,
-			VolumeMounts:   amVolumeMounts,
-			LivenessProbe:  livenessProbe,
-			ReadinessProbe: readinessProbe,
-			Resources:      a.Spec.Resources,
-			Env: []v1.EnvVar{
-				{
-					// Necessary for '--cluster.listen-address' flag
-					Name: "POD_IP",
-					ValueFrom: &v1.EnvVarSource{
-						FieldRef: &v1.ObjectFieldSelector{
-							FieldPath: "status.podIP",
-						},
-					},
+			// Necessary for '--cluster.listen-address' flag
+			Name: "POD_IP",
+			ValueFrom: &v1.EnvVarSource{
+				FieldRef: &v1.ObjectFieldSelector{
+					FieldPath: "status.podIP",
 				},
 			},
+		},
+	}
+	if a.Spec.ClusterPeering != nil {
+		// Necessary for the advertise address of the cluster peering.
+		amEnv = append(amEnv, v1.EnvVar{
+			Name: "POD_NAME",
+			ValueFrom: &v1.EnvVarSource{
+				FieldRef: &v1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		})
+	}
+
+	defaultContainers := []v1.Container{
+		{
+			Args:                     amArgs,
+			Name:                     "alertmanager",
+			Image:                    amImagePath,
+			Ports:                    ports,
+			VolumeMounts:             amVolumeMounts,
+			LivenessProbe:            livenessProbe,
+			ReadinessProbe:           readinessProbe,
+			Resources:                a.Spec.Resources,
+			Env:                      amEnv,
 			TerminationMessagePolicy: v1.TerminationMessageFallbackToLogsOnError,
 		},
-		operator.CreateConfigReloader(
+	}
+	if !operatorReload {
+		defaultContainers = append(defaultContainers, operator.CreateConfigReloader(
 			"config-reloader",
 			operator.ReloaderResources(config.ReloaderConfig),
 			operator.ReloaderURL(url.URL{
-				Scheme: "http",
+				Scheme: reloadScheme,
 				Host:   config.LocalHost + ":9093",
 				Path:   path.Clean(webRoutePrefix + "/-/reload"),
 			}),
+			operator.ReloaderBasicAuth(reloaderBasicAuth),
 			operator.ListenLocal(a.Spec.ListenLocal),
 			operator.LocalHost(config.LocalHost),
 			operator.LogFormat(a.Spec.LogFormat),
 			operator.LogLevel(a.Spec.LogLevel),
+			operator.ConfigFile(path.Join(alertmanagerConfigDir, alertmanagerConfigFileCompressed)),
+			operator.ConfigEnvsubstFile(path.Join(alertmanagerConfigOutDir, alertmanagerConfigEnvsubstFile)),
 			operator.WatchedDirectories(watchedDirectories),
 			operator.VolumeMounts(configReloaderVolumeMounts),
 			operator.Shard(-1),
-		),
+		))
 	}
 
 	containers, err := k8sutil.MergePatchContainers(defaultContainers, a.Spec.Containers)
@@ -564,6 +739,29 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 		return nil, errors.Wrap(err, "failed to merge containers spec")
 	}
 
+	// The init container decompresses the configuration before Alertmanager
+	// starts.
+	var operatorInitContainers []v1.Container
+	if !operatorReload {
+		operatorInitContainers = append(operatorInitContainers, operator.CreateConfigReloader(
+			"init-config-reloader",
+			operator.ReloaderResources(config.ReloaderConfig),
+			operator.ReloaderRunOnce(),
+			operator.LogFormat(a.Spec.LogFormat),
+			operator.LogLevel(a.Spec.LogLevel),
+			operator.ConfigFile(path.Join(alertmanagerConfigDir, alertmanagerConfigFileCompressed)),
+			operator.ConfigEnvsubstFile(path.Join(alertmanagerConfigOutDir, alertmanagerConfigEnvsubstFile)),
+			operator.WatchedDirectories(watchedDirectories),
+			operator.VolumeMounts(configReloaderVolumeMounts),
+			operator.Shard(-1),
+		))
+	}
+
+	initContainers, err := k8sutil.MergePatchContainers(operatorInitContainers, a.Spec.InitContainers)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to merge init containers spec")
+	}
+
 	var minReadySeconds int32
 	if a.Spec.MinReadySeconds != nil {
 		minReadySeconds = int32(*a.Spec.MinReadySeconds)
@@ -591,7 +789,7 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 				NodeSelector:                  a.Spec.NodeSelector,
 				PriorityClassName:             a.Spec.PriorityClassName,
 				TerminationGracePeriodSeconds: &terminationGracePeriod,
-				InitContainers:                a.Spec.InitContainers,
+				InitContainers:                initContainers,
 				Containers:                    containers,
 				Volumes:                       volumes,
 				ServiceAccountName:            a.Spec.ServiceAccountName,
@@ -599,6 +797,9 @@ func makeStatefulSetSpec(a *monitoringv1.Alertmanager, config Config) (*appsv1.S
 				Tolerations:                   a.Spec.Tolerations,
 				Affinity:                      a.Spec.Affinity,
 				TopologySpreadConstraints:     a.Spec.TopologySpreadConstraints,
+				HostAliases:                   operator.MakeHostAliases(a.Spec.HostAliases),
+				DNSPolicy:                     a.Spec.DNSPolicy,
+				DNSConfig:                     a.Spec.DNSConfig,
 			},
 		},
 	}, nil
@@ -612,6 +813,14 @@ func generatedConfigSecretName(name string) string {
 	return prefixedName(name) + "-generated"
 }
 
+func clusterTLSConfigSecretName(name string) string {
+	return prefixedName(name) + "-cluster-tls-config"
+}
+
+func webConfigSecretName(name string) string {
+	return prefixedName(name) + "-web-config"
+}
+
 func volumeName(name string) string {
 	return fmt.Sprintf("%s-db", prefixedName(name))
 }
@@ -642,3 +851,150 @@ func filter(strings []string, f func(string) bool) []string {
 	}
 	return filteredStrings
 }
+
+// validateTemplates verifies that the notification templates reference either
+// a ConfigMap or a Secret and that their keys are unique.
+// hasClusterPeers returns true if the Alertmanager pods peer with instances
+// running outside of the Kubernetes cluster.
+func hasClusterPeers(a *monitoringv1.Alertmanager) bool {
+	return a.Spec.ClusterPeering != nil && len(a.Spec.ClusterPeering.Peers) > 0
+}
+
+// peerAddress returns the address of the peer with the default cluster port
+// if none is specified.
+func peerAddress(address string) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+
+	return net.JoinHostPort(strings.Trim(address, "[]"), "9094")
+}
+
+// validateClusterPeering verifies that the peering configuration is valid and
+// that the replicas and the peers form the expected cluster.
+func validateClusterPeering(a *monitoringv1.Alertmanager) error {
+	cp := a.Spec.ClusterPeering
+	if cp == nil {
+		return nil
+	}
+
+	if cp.AdvertiseAddress != "" {
+		if _, _, err := net.SplitHostPort(cp.AdvertiseAddress); err != nil {
+			return errors.Wrapf(err, "invalid advertiseAddress %q", cp.AdvertiseAddress)
+		}
+	}
+
+	size := *a.Spec.Replicas
+	addresses := make(map[string]struct{}, len(cp.Peers))
+	for i, peer := range cp.Peers {
+		address := peerAddress(peer.Address)
+		host, _, err := net.SplitHostPort(address)
+		if err != nil || host == "" {
+			return errors.Errorf("peers[%d]: invalid address %q", i, peer.Address)
+		}
+
+		if _, found := addresses[address]; found {
+			return errors.Errorf("peers[%d]: duplicate address %q", i, peer.Address)
+		}
+		addresses[address] = struct{}{}
+
+		if peer.Replicas != nil {
+			size += *peer.Replicas
+		} else {
+			size++
+		}
+	}
+
+	if cp.ExpectedClusterSize != nil && size != *cp.ExpectedClusterSize {
+		return errors.Errorf("the replicas and the peers add up to %d Alertmanager instances instead of the expected cluster size of %d", size, *cp.ExpectedClusterSize)
+	}
+
+	return nil
+}
+
+func validateTemplates(templates []monitoringv1.SecretOrConfigMap) error {
+	keys := map[string]struct{}{}
+	for i, t := range templates {
+		if err := t.Validate(); err != nil {
+			return errors.Wrapf(err, "templates[%d]", i)
+		}
+
+		var key string
+		switch {
+		case t.ConfigMap != nil:
+			key = t.ConfigMap.Key
+		case t.Secret != nil:
+			key = t.Secret.Key
+		default:
+			return errors.Errorf("templates[%d]: either configMap or secret must be defined", i)
+		}
+
+		if _, found := keys[key]; found {
+			return errors.Errorf("templates[%d]: duplicate key %q", i, key)
+		}
+		keys[key] = struct{}{}
+	}
+
+	return nil
+}
+
+// templatesVolumeProjections returns the projections of the ConfigMap and
+// Secret keys containing the notification templates. Each key is projected
+// into a file with the same name.
+func templatesVolumeProjections(templates []monitoringv1.SecretOrConfigMap) []v1.VolumeProjection {
+	var sources []v1.VolumeProjection
+	for _, t := range templates {
+		switch {
+		case t.ConfigMap != nil:
+			sources = append(sources, v1.VolumeProjection{
+				ConfigMap: &v1.ConfigMapProjection{
+					LocalObjectReference: t.ConfigMap.LocalObjectReference,
+					Items:                []v1.KeyToPath{{Key: t.ConfigMap.Key, Path: t.ConfigMap.Key}},
+					Optional:             t.ConfigMap.Optional,
+				},
+			})
+		case t.Secret != nil:
+			sources = append(sources, v1.VolumeProjection{
+				Secret: &v1.SecretProjection{
+					LocalObjectReference: t.Secret.LocalObjectReference,
+					Items:                []v1.KeyToPath{{Key: t.Secret.Key, Path: t.Secret.Key}},
+					Optional:             t.Secret.Optional,
+				},
+			})
+		}
+	}
+
+	return sources
+}
+
+// templatesPaths returns the paths of the notification templates in the
+// Alertmanager container.
+func templatesPaths(templates []monitoringv1.SecretOrConfigMap) []string {
+	var paths []string
+	for _, t := range templates {
+		switch {
+		case t.ConfigMap != nil:
+			paths = append(paths, path.Join(templatesDir, t.ConfigMap.Key))
+		case t.Secret != nil:
+			paths = append(paths, path.Join(templatesDir, t.Secret.Key))
+		}
+	}
+
+	return paths
+}
+
+// resolveTemplatePaths makes the relative template paths of the configuration
+// absolute. Alertmanager resolves them against the directory of the
+// configuration file which is written by the config reloader outside of the
+// configuration secret's directory. It returns true if a path was modified.
+func resolveTemplatePaths(cfg *alertmanagerConfig) bool {
+	var resolved bool
+	for i, t := range cfg.Templates {
+		if !path.IsAbs(t) {
+			cfg.Templates[i] = path.Join(alertmanagerConfigDir, t)
+			resolved = true
+		}
+	}
+
+	return resolved
+}
@@ -241,6 +241,33 @@ func TestStatefulEmptyDir(t *testing.T) {
 		t.Fatal("Error adding EmptyDir Spec to StatefulSetSpec")
 	}
 }
+
+func TestStatefulSetEphemeral(t *testing.T) {
+	storageClass := "storageclass"
+
+	ephemeral := v1.EphemeralVolumeSource{
+		VolumeClaimTemplate: &v1.PersistentVolumeClaimTemplate{
+			Spec: v1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClass,
+			},
+		},
+	}
+
+	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+		Spec: monitoringv1.AlertmanagerSpec{
+			Storage: &monitoringv1.StorageSpec{
+				Ephemeral: &ephemeral,
+			},
+		},
+	}, defaultTestConfig, "")
+
+	require.NoError(t, err)
+	require.Empty(t, sset.Spec.VolumeClaimTemplates)
+
+	ssetVolumes := sset.Spec.Template.Spec.Volumes
+	require.NotNil(t, ssetVolumes[len(ssetVolumes)-1].VolumeSource.Ephemeral)
+	require.Equal(t, ephemeral, *ssetVolumes[len(ssetVolumes)-1].VolumeSource.Ephemeral)
+}
 func TestListenLocal(t *testing.T) {
 	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
 		Spec: monitoringv1.AlertmanagerSpec{
@@ -425,6 +452,153 @@ func TestMakeStatefulSetSpecAdditionalPeers(t *testing.T) {
 	}
 }
 
+func TestMakeStatefulSetSpecClusterPeering(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	for _, tc := range []struct {
+		name         string
+		replicas     int32
+		peering      *monitoringv1.AlertmanagerClusterPeering
+		expectedArgs []string
+		err          bool
+	}{
+		{
+			name:     "single replica with peers",
+			replicas: 1,
+			peering: &monitoringv1.AlertmanagerClusterPeering{
+				Peers: []monitoringv1.AlertmanagerPeer{
+					{Address: "alertmanager.eu.example.com", Replicas: int32Ptr(2)},
+					{Address: "10.1.2.3:19094"},
+					{Address: "fd00::1"},
+				},
+				AdvertiseAddress:    "$(POD_NAME).us.example.com:9094",
+				ExpectedClusterSize: int32Ptr(5),
+			},
+			expectedArgs: []string{
+				"--cluster.listen-address=[$(POD_IP)]:9094",
+				"--cluster.advertise-address=$(POD_NAME).us.example.com:9094",
+				"--cluster.peer=alertmanager.eu.example.com:9094",
+				"--cluster.peer=10.1.2.3:19094",
+				"--cluster.peer=[fd00::1]:9094",
+			},
+		},
+		{
+			name:     "unexpected cluster size",
+			replicas: 3,
+			peering: &monitoringv1.AlertmanagerClusterPeering{
+				Peers: []monitoringv1.AlertmanagerPeer{
+					{Address: "alertmanager.eu.example.com", Replicas: int32Ptr(3)},
+				},
+				ExpectedClusterSize: int32Ptr(3),
+			},
+			err: true,
+		},
+		{
+			name:     "duplicate peers",
+			replicas: 1,
+			peering: &monitoringv1.AlertmanagerClusterPeering{
+				Peers: []monitoringv1.AlertmanagerPeer{
+					{Address: "alertmanager.eu.example.com"},
+					{Address: "alertmanager.eu.example.com:9094"},
+				},
+			},
+			err: true,
+		},
+		{
+			name:     "advertise address without port",
+			replicas: 1,
+			peering: &monitoringv1.AlertmanagerClusterPeering{
+				AdvertiseAddress: "$(POD_NAME).us.example.com",
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := monitoringv1.Alertmanager{}
+			a.Spec.Version = operator.DefaultAlertmanagerVersion
+			a.Spec.Replicas = &tc.replicas
+			a.Spec.ClusterPeering = tc.peering
+
+			statefulSet, err := makeStatefulSetSpec(&a, defaultTestConfig)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			amArgs := statefulSet.Template.Spec.Containers[0].Args
+			for _, expected := range tc.expectedArgs {
+				require.Contains(t, amArgs, expected)
+			}
+
+			var podNameFound bool
+			for _, env := range statefulSet.Template.Spec.Containers[0].Env {
+				if env.Name == "POD_NAME" {
+					podNameFound = true
+				}
+			}
+			require.True(t, podNameFound, "expected POD_NAME environment variable")
+		})
+	}
+}
+
+func TestMakeStatefulSetSpecCompressedConfig(t *testing.T) {
+	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+		Spec: monitoringv1.AlertmanagerSpec{
+			InitContainers: []v1.Container{{Name: "init-custom"}},
+		},
+	}, defaultTestConfig, "")
+	require.NoError(t, err)
+
+	spec := sset.Spec.Template.Spec
+	require.Contains(t, spec.Containers[0].Args, "--config.file=/etc/alertmanager/config_out/alertmanager.env.yaml")
+
+	require.Len(t, spec.InitContainers, 2)
+	require.Equal(t, "init-config-reloader", spec.InitContainers[0].Name)
+	require.Equal(t, "init-custom", spec.InitContainers[1].Name)
+
+	for _, c := range []v1.Container{spec.InitContainers[0], spec.Containers[1]} {
+		require.Contains(t, c.Args, "--config-file=/etc/alertmanager/config/alertmanager.yaml.gz")
+		require.Contains(t, c.Args, "--config-envsubst-file=/etc/alertmanager/config_out/alertmanager.env.yaml")
+		require.Contains(t, c.VolumeMounts, v1.VolumeMount{Name: "config-out", MountPath: "/etc/alertmanager/config_out"})
+	}
+	require.Contains(t, spec.InitContainers[0].Args, "--watch-interval=0")
+	require.Contains(t, spec.Containers[0].VolumeMounts, v1.VolumeMount{Name: "config-out", ReadOnly: true, MountPath: "/etc/alertmanager/config_out"})
+}
+
+func TestMakeStatefulSetSpecOperatorReloadStrategy(t *testing.T) {
+	config := defaultTestConfig
+	config.ReloadStrategy = operator.OperatorReloadStrategy
+
+	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{}, config, "")
+	require.NoError(t, err)
+
+	spec := sset.Spec.Template.Spec
+	require.Empty(t, spec.InitContainers)
+	require.Len(t, spec.Containers, 1)
+	require.Equal(t, "alertmanager", spec.Containers[0].Name)
+	require.Contains(t, spec.Containers[0].Args, "--config.file=/etc/alertmanager/config/alertmanager.yaml")
+
+	for _, v := range spec.Volumes {
+		require.NotEqual(t, "config-out", v.Name)
+	}
+	for _, m := range spec.Containers[0].VolumeMounts {
+		require.NotEqual(t, "config-out", m.Name)
+	}
+
+	_, err = makeStatefulSet(&monitoringv1.Alertmanager{
+		Spec: monitoringv1.AlertmanagerSpec{
+			ListenLocal: true,
+		},
+	}, config, "")
+	require.Error(t, err)
+}
+
 func TestAdditionalSecretsMounted(t *testing.T) {
 	secrets := []string{"secret1", "secret2"}
 	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
@@ -627,6 +801,81 @@ func TestAdditionalConfigMap(t *testing.T) {
 	}
 }
 
+func TestTemplatesMounted(t *testing.T) {
+	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+		Spec: monitoringv1.AlertmanagerSpec{
+			Templates: []monitoringv1.SecretOrConfigMap{
+				{
+					ConfigMap: &v1.ConfigMapKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "test-cm"},
+						Key:                  "slack.tmpl",
+					},
+				},
+				{
+					Secret: &v1.SecretKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "test-secret"},
+						Key:                  "email.tmpl",
+					},
+				},
+			},
+		},
+	}, defaultTestConfig, "")
+	if err != nil {
+		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
+	}
+
+	var volume *v1.Volume
+	for i, v := range sset.Spec.Template.Spec.Volumes {
+		if v.Name == "templates" {
+			volume = &sset.Spec.Template.Spec.Volumes[i]
+		}
+	}
+	if volume == nil || volume.Projected == nil {
+		t.Fatal("Templates projected volume not found")
+	}
+
+	expectedSources := []v1.VolumeProjection{
+		{
+			ConfigMap: &v1.ConfigMapProjection{
+				LocalObjectReference: v1.LocalObjectReference{Name: "test-cm"},
+				Items:                []v1.KeyToPath{{Key: "slack.tmpl", Path: "slack.tmpl"}},
+			},
+		},
+		{
+			Secret: &v1.SecretProjection{
+				LocalObjectReference: v1.LocalObjectReference{Name: "test-secret"},
+				Items:                []v1.KeyToPath{{Key: "email.tmpl", Path: "email.tmpl"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(volume.Projected.Sources, expectedSources) {
+		t.Fatalf("Unexpected projected sources, want %v, got %v", expectedSources, volume.Projected.Sources)
+	}
+
+	for _, c := range sset.Spec.Template.Spec.Containers {
+		mounted := false
+		for _, v := range c.VolumeMounts {
+			if v.Name == "templates" && v.MountPath == "/etc/alertmanager/templates" {
+				mounted = true
+			}
+		}
+		if !mounted {
+			t.Fatalf("Templates volume not mounted in container %s", c.Name)
+		}
+	}
+
+	reloader := sset.Spec.Template.Spec.Containers[1]
+	watched := false
+	for _, arg := range reloader.Args {
+		if arg == "--watched-dir=/etc/alertmanager/templates" {
+			watched = true
+		}
+	}
+	if !watched {
+		t.Fatalf("Templates directory not watched by the config reloader: %v", reloader.Args)
+	}
+}
+
 func TestSidecarsNoResources(t *testing.T) {
 	testConfig := Config{
 		ReloaderConfig: operator.ReloaderConfig{
@@ -1042,3 +1291,258 @@ func TestExpectStatefulSetMinReadySeconds(t *testing.T) {
 		t.Fatalf("expected MinReadySeconds to be %d but got %d", expect, statefulSet.MinReadySeconds)
 	}
 }
+
+func TestStatefulSetHostAliases(t *testing.T) {
+	a := monitoringv1.Alertmanager{}
+	replicas := int32(1)
+	a.Spec.Version = operator.DefaultAlertmanagerVersion
+	a.Spec.Replicas = &replicas
+	a.Spec.HostAliases = []monitoringv1.HostAlias{
+		{
+			IP:        "10.0.0.1",
+			Hostnames: []string{"smtp.example.com"},
+		},
+	}
+
+	statefulSet, err := makeStatefulSetSpec(&a, defaultTestConfig)
+	require.NoError(t, err)
+
+	expected := []v1.HostAlias{
+		{
+			IP:        "10.0.0.1",
+			Hostnames: []string{"smtp.example.com"},
+		},
+	}
+	require.Equal(t, expected, statefulSet.Template.Spec.HostAliases)
+}
+
+func TestClusterTLSConfig(t *testing.T) {
+	clusterTLSConfig := &monitoringv1.ClusterTLSConfig{
+		ServerTLS: monitoringv1.WebTLSConfig{
+			KeySecret: v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "cluster-tls"},
+				Key:                  "tls.key",
+			},
+			Cert: monitoringv1.SecretOrConfigMap{
+				Secret: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "cluster-tls"},
+					Key:                  "tls.crt",
+				},
+			},
+		},
+	}
+
+	_, err := makeStatefulSet(&monitoringv1.Alertmanager{
+		Spec: monitoringv1.AlertmanagerSpec{
+			Version:          "v0.23.0",
+			ClusterTLSConfig: clusterTLSConfig,
+		},
+	}, defaultTestConfig, "")
+	if err == nil {
+		t.Fatal("Expected error for Alertmanager < v0.24.0, got none")
+	}
+
+	sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: monitoringv1.AlertmanagerSpec{
+			Version:          "v0.24.0",
+			ClusterTLSConfig: clusterTLSConfig,
+		},
+	}, defaultTestConfig, "")
+	if err != nil {
+		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
+	}
+
+	var volume *v1.Volume
+	for i, v := range sset.Spec.Template.Spec.Volumes {
+		if v.Name == "cluster-tls-config" {
+			volume = &sset.Spec.Template.Spec.Volumes[i]
+		}
+	}
+	if volume == nil || volume.Projected == nil {
+		t.Fatal("Cluster TLS config projected volume not found")
+	}
+	if secret := volume.Projected.Sources[0].Secret; secret == nil || secret.Name != "alertmanager-test-cluster-tls-config" {
+		t.Fatalf("Expected the cluster TLS config secret to be projected first, got %v", volume.Projected.Sources[0])
+	}
+
+	amArgs := sset.Spec.Template.Spec.Containers[0].Args
+	found := false
+	for _, arg := range amArgs {
+		if arg == "--cluster.tls-config=/etc/alertmanager/cluster_tls_config/cluster-tls-config.yaml" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Cluster TLS config flag not found in %v", amArgs)
+	}
+
+	mounted := false
+	for _, v := range sset.Spec.Template.Spec.Containers[0].VolumeMounts {
+		if v.Name == "cluster-tls-config" && v.MountPath == "/etc/alertmanager/cluster_tls_config" {
+			mounted = true
+		}
+	}
+	if !mounted {
+		t.Fatal("Cluster TLS config volume not mounted in the alertmanager container")
+	}
+}
+
+func TestWebConfig(t *testing.T) {
+	tlsConfig := &monitoringv1.WebTLSConfig{
+		KeySecret: v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "web-tls"},
+			Key:                  "tls.key",
+		},
+		Cert: monitoringv1.SecretOrConfigMap{
+			Secret: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "web-tls"},
+				Key:                  "tls.crt",
+			},
+		},
+	}
+	basicAuthUsers := []monitoringv1.WebBasicAuthUser{
+		{
+			Username: "alice",
+			PasswordHash: v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "web-auth"},
+				Key:                  "alice",
+			},
+		},
+	}
+	reloaderBasicAuth := &monitoringv1.BasicAuth{
+		Username: v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "web-auth"},
+			Key:                  "username",
+		},
+		Password: v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "web-auth"},
+			Key:                  "password",
+		},
+	}
+
+	for _, tc := range []struct {
+		name             string
+		version          string
+		web              *monitoringv1.AlertmanagerWebSpec
+		expectErr        bool
+		expectedReload   string
+		expectedHTTPS    bool
+		expectedTCPProbe bool
+	}{
+		{
+			name:    "alertmanager too old",
+			version: "v0.21.0",
+			web: &monitoringv1.AlertmanagerWebSpec{
+				TLSConfig: tlsConfig,
+			},
+			expectErr: true,
+		},
+		{
+			name:    "basic auth without reloader credentials",
+			version: "v0.24.0",
+			web: &monitoringv1.AlertmanagerWebSpec{
+				BasicAuthUsers: basicAuthUsers,
+			},
+			expectErr: true,
+		},
+		{
+			name:    "TLS",
+			version: "v0.24.0",
+			web: &monitoringv1.AlertmanagerWebSpec{
+				TLSConfig: tlsConfig,
+			},
+			expectedReload: "--reload-url=https://",
+			expectedHTTPS:  true,
+		},
+		{
+			name:    "basic auth",
+			version: "v0.24.0",
+			web: &monitoringv1.AlertmanagerWebSpec{
+				BasicAuthUsers:    basicAuthUsers,
+				ReloaderBasicAuth: reloaderBasicAuth,
+			},
+			expectedReload:   "--reload-url=http://",
+			expectedTCPProbe: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sset, err := makeStatefulSet(&monitoringv1.Alertmanager{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: monitoringv1.AlertmanagerSpec{
+					Version: tc.version,
+					Web:     tc.web,
+				},
+			}, defaultTestConfig, "")
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("Expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error while making StatefulSet: %v", err)
+			}
+
+			am := sset.Spec.Template.Spec.Containers[0]
+			found := false
+			for _, arg := range am.Args {
+				if arg == "--web.config.file=/etc/alertmanager/web_config/web-config.yaml" {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("Web config flag not found in %v", am.Args)
+			}
+
+			found = false
+			for _, v := range sset.Spec.Template.Spec.Volumes {
+				if v.Name == "web-config" && v.Secret != nil && v.Secret.SecretName == "alertmanager-test-web-config" {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatal("Web config volume not found")
+			}
+
+			for _, probe := range []*v1.Probe{am.LivenessProbe, am.ReadinessProbe} {
+				if tc.expectedTCPProbe {
+					if probe.TCPSocket == nil {
+						t.Fatalf("Expected TCP probe, got %v", probe.Handler)
+					}
+					continue
+				}
+
+				// The scheme defaults to HTTP when empty.
+				var expectedScheme v1.URIScheme
+				if tc.expectedHTTPS {
+					expectedScheme = v1.URISchemeHTTPS
+				}
+				if probe.HTTPGet == nil || probe.HTTPGet.Scheme != expectedScheme {
+					t.Fatalf("Expected HTTP probe with scheme %s, got %v", expectedScheme, probe.Handler)
+				}
+			}
+
+			reloader := sset.Spec.Template.Spec.Containers[1]
+			found = false
+			for _, arg := range reloader.Args {
+				if strings.HasPrefix(arg, tc.expectedReload) {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("Expected reload URL starting with %q in %v", tc.expectedReload, reloader.Args)
+			}
+
+			authEnv := 0
+			for _, env := range reloader.Env {
+				if env.Name == operator.ReloaderBasicAuthUsernameEnvVar || env.Name == operator.ReloaderBasicAuthPasswordEnvVar {
+					authEnv++
+				}
+			}
+			if tc.expectedTCPProbe && authEnv != 2 || !tc.expectedTCPProbe && authEnv != 0 {
+				t.Fatalf("Unexpected basic auth environment variables in %v", reloader.Env)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+)
+
+// configSelection is the outcome of the selection of the AlertmanagerConfig
+// resources during the reconciliation of an Alertmanager object.
+type configSelection struct {
+	// Number of AlertmanagerConfig resources merged into the configuration.
+	selected int
+	// Reasons why the AlertmanagerConfig resources were rejected, indexed
+	// by <namespace>/<name>.
+	rejected map[string]string
+	// Error returned when the generated configuration was rejected by the
+	// Alertmanager configuration parser. The previous configuration is kept
+	// in this case.
+	invalidConfig error
+	// True when the generated configuration couldn't be checked because the
+	// Alertmanager version is newer than the vendored configuration parser.
+	validationSkipped bool
+}
+
+// updateStatus updates the status subresource of the Alertmanager object with
+// the state of its StatefulSet and the outcome of the last reconciliation.
+// The selection is nil when the AlertmanagerConfig resources haven't been
+// selected (e.g. the object is paused) in which case the previous values are
+// kept.
+func (c *Operator) updateStatus(ctx context.Context, logger log.Logger, key string, am *monitoringv1.Alertmanager, selection *configSelection, reconcileErr error) error {
+	var sset *appsv1.StatefulSet
+	obj, err := c.ssetInfs.Get(alertmanagerKeyToStatefulSetKey(key))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "retrieving statefulset failed")
+	}
+	if err == nil {
+		sset = obj.(*appsv1.StatefulSet)
+	}
+
+	status := newAlertmanagerStatus(am, sset, selection, reconcileErr, metav1.Now())
+	if am.Status != nil && equality.Semantic.DeepEqual(*am.Status, status) {
+		return nil
+	}
+
+	level.Debug(logger).Log("msg", "updating status")
+
+	amCopy := am.DeepCopy()
+	amCopy.Status = &status
+	if _, err := c.mclient.MonitoringV1().Alertmanagers(am.Namespace).UpdateStatus(ctx, amCopy, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update the status")
+	}
+
+	return nil
+}
+
+// newAlertmanagerStatus returns the status of the Alertmanager object given
+// its StatefulSet (nil when missing), the selected AlertmanagerConfig
+// resources and the outcome of the last reconciliation. The transition times
+// of the conditions whose status didn't change are preserved.
+func newAlertmanagerStatus(am *monitoringv1.Alertmanager, sset *appsv1.StatefulSet, selection *configSelection, reconcileErr error, now metav1.Time) monitoringv1.AlertmanagerStatus {
+	var previous monitoringv1.AlertmanagerStatus
+	if am.Status != nil {
+		previous = *am.Status
+	}
+
+	status := monitoringv1.AlertmanagerStatus{
+		Paused:                      am.Spec.Paused,
+		SelectedAlertmanagerConfigs: previous.SelectedAlertmanagerConfigs,
+	}
+
+	desired := minReplicas
+	if am.Spec.Replicas != nil {
+		desired = *am.Spec.Replicas
+	}
+
+	if sset != nil {
+		status.Replicas = sset.Status.Replicas
+		status.UpdatedReplicas = sset.Status.UpdatedReplicas
+		status.AvailableReplicas = sset.Status.ReadyReplicas
+	}
+
+	expected := desired
+	if status.Replicas > expected {
+		expected = status.Replicas
+	}
+	if status.AvailableReplicas < expected {
+		status.UnavailableReplicas = expected - status.AvailableReplicas
+	}
+
+	newCondition := func(t monitoringv1.ConditionType, s monitoringv1.ConditionStatus, reason, message string) monitoringv1.Condition {
+		return monitoringv1.Condition{
+			Type:               t,
+			Status:             s,
+			LastTransitionTime: now,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: am.Generation,
+		}
+	}
+
+	available := newCondition(monitoringv1.Available, monitoringv1.ConditionTrue, "AllPodsAvailable", "")
+	if status.AvailableReplicas == 0 {
+		available = newCondition(monitoringv1.Available, monitoringv1.ConditionFalse, "NoPodReady", "No pod is ready")
+	}
+
+	degraded := newCondition(monitoringv1.Degraded, monitoringv1.ConditionFalse, "AllPodsReady", "")
+	switch {
+	case selection != nil && selection.invalidConfig != nil:
+		degraded = newCondition(monitoringv1.Degraded, monitoringv1.ConditionTrue, "InvalidConfiguration", fmt.Sprintf("The generated configuration is invalid, the previous configuration is kept: %v", selection.invalidConfig))
+	case status.UnavailableReplicas > 0 || status.UpdatedReplicas < status.Replicas:
+		degraded = newCondition(monitoringv1.Degraded, monitoringv1.ConditionTrue, "SomePodsNotReady", "Some pods aren't ready or up-to-date")
+	}
+
+	reconciled := newCondition(monitoringv1.Reconciled, monitoringv1.ConditionTrue, "ReconciliationSucceeded", "")
+	if selection != nil && selection.validationSkipped {
+		reconciled.Message = validationSkippedMessage
+	}
+	switch {
+	case am.Spec.Paused:
+		reconciled = newCondition(monitoringv1.Reconciled, monitoringv1.ConditionUnknown, "Paused", "The reconciliation is paused")
+	case reconcileErr != nil:
+		reconciled = newCondition(monitoringv1.Reconciled, monitoringv1.ConditionFalse, "ReconciliationFailed", reconcileErr.Error())
+	}
+
+	conditions := []monitoringv1.Condition{available, reconciled, degraded}
+
+	// The RejectedResources condition is only reported once a resource has
+	// been rejected. It is left unchanged when the selection didn't happen.
+	prevRejected := findCondition(previous.Conditions, monitoringv1.RejectedResources)
+	switch {
+	case selection != nil:
+		status.SelectedAlertmanagerConfigs = int32(selection.selected)
+
+		rejected := newCondition(monitoringv1.RejectedResources, monitoringv1.ConditionFalse, "NoResourceRejected", "")
+		if len(selection.rejected) > 0 {
+			rejected = newCondition(monitoringv1.RejectedResources, monitoringv1.ConditionTrue, "AlertmanagerConfigRejected", rejectedMessage(selection.rejected))
+		}
+		if rejected.Status == monitoringv1.ConditionTrue || prevRejected != nil {
+			conditions = append(conditions, rejected)
+		}
+	case prevRejected != nil:
+		conditions = append(conditions, *prevRejected)
+	}
+
+	for i := range conditions {
+		if prev := findCondition(previous.Conditions, conditions[i].Type); prev != nil && prev.Status == conditions[i].Status {
+			conditions[i].LastTransitionTime = prev.LastTransitionTime
+		}
+	}
+	status.Conditions = conditions
+
+	return status
+}
+
+// validationSkippedMessage is reported when the generated configuration
+// couldn't be checked by the vendored Alertmanager configuration parser.
+const validationSkippedMessage = "The generated configuration wasn't validated: the Alertmanager version is newer than the configuration parser of the operator"
+
+// rejectedMessage returns a human-readable description of the rejected
+// AlertmanagerConfig resources, sorted by namespace and name.
+func rejectedMessage(rejected map[string]string) string {
+	keys := make([]string, 0, len(rejected))
+	for k := range rejected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	reasons := make([]string, len(keys))
+	for i, k := range keys {
+		reasons[i] = fmt.Sprintf("%s %s: %s", monitoringv1alpha1.AlertmanagerConfigKind, k, rejected[k])
+	}
+
+	return fmt.Sprintf("%d resource(s) rejected: %s", len(keys), strings.Join(reasons, "; "))
+}
+
+func findCondition(conditions []monitoringv1.Condition, t monitoringv1.ConditionType) *monitoringv1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == t {
+			return &conditions[i]
+		}
+	}
+
+	return nil
+}
@@ -28,11 +28,13 @@ import (
 // marshalling. See the following issue for details:
 // https://github.com/prometheus/alertmanager/issues/1985
 type alertmanagerConfig struct {
-	Global       *globalConfig  `yaml:"global,omitempty" json:"global,omitempty"`
-	Route        *route         `yaml:"route,omitempty" json:"route,omitempty"`
-	InhibitRules []*inhibitRule `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
-	Receivers    []*receiver    `yaml:"receivers,omitempty" json:"receivers,omitempty"`
-	Templates    []string       `yaml:"templates" json:"templates"`
+	Global            *globalConfig   `yaml:"global,omitempty" json:"global,omitempty"`
+	Route             *route          `yaml:"route,omitempty" json:"route,omitempty"`
+	InhibitRules      []*inhibitRule  `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
+	Receivers         []*receiver     `yaml:"receivers,omitempty" json:"receivers,omitempty"`
+	MuteTimeIntervals []*timeInterval `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
+	TimeIntervals     []*timeInterval `yaml:"time_intervals,omitempty" json:"time_intervals,omitempty"`
+	Templates         []string        `yaml:"templates" json:"templates"`
 }
 
 type globalConfig struct {
@@ -65,16 +67,37 @@ type globalConfig struct {
 }
 
 type route struct {
-	Receiver       string            `yaml:"receiver,omitempty" json:"receiver,omitempty"`
-	GroupByStr     []string          `yaml:"group_by,omitempty" json:"group_by,omitempty"`
-	Match          map[string]string `yaml:"match,omitempty" json:"match,omitempty"`
-	MatchRE        map[string]string `yaml:"match_re,omitempty" json:"match_re,omitempty"`
-	Matchers       []string          `yaml:"matchers,omitempty" json:"matchers,omitempty"`
-	Continue       bool              `yaml:"continue,omitempty" json:"continue,omitempty"`
-	Routes         []*route          `yaml:"routes,omitempty" json:"routes,omitempty"`
-	GroupWait      string            `yaml:"group_wait,omitempty" json:"group_wait,omitempty"`
-	GroupInterval  string            `yaml:"group_interval,omitempty" json:"group_interval,omitempty"`
-	RepeatInterval string            `yaml:"repeat_interval,omitempty" json:"repeat_interval,omitempty"`
+	Receiver            string            `yaml:"receiver,omitempty" json:"receiver,omitempty"`
+	GroupByStr          []string          `yaml:"group_by,omitempty" json:"group_by,omitempty"`
+	Match               map[string]string `yaml:"match,omitempty" json:"match,omitempty"`
+	MatchRE             map[string]string `yaml:"match_re,omitempty" json:"match_re,omitempty"`
+	Matchers            []string          `yaml:"matchers,omitempty" json:"matchers,omitempty"`
+	Continue            bool              `yaml:"continue,omitempty" json:"continue,omitempty"`
+	Routes              []*route          `yaml:"routes,omitempty" json:"routes,omitempty"`
+	GroupWait           string            `yaml:"group_wait,omitempty" json:"group_wait,omitempty"`
+	GroupInterval       string            `yaml:"group_interval,omitempty" json:"group_interval,omitempty"`
+	RepeatInterval      string            `yaml:"repeat_interval,omitempty" json:"repeat_interval,omitempty"`
+	MuteTimeIntervals   []string          `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
+	ActiveTimeIntervals []string          `yaml:"active_time_intervals,omitempty" json:"active_time_intervals,omitempty"`
+}
+
+type timeInterval struct {
+	Name          string       `yaml:"name" json:"name"`
+	TimeIntervals []timePeriod `yaml:"time_intervals" json:"time_intervals"`
+}
+
+type timePeriod struct {
+	Times       []timeRange `yaml:"times,omitempty" json:"times,omitempty"`
+	Weekdays    []string    `yaml:"weekdays,omitempty" json:"weekdays,omitempty"`
+	DaysOfMonth []string    `yaml:"days_of_month,omitempty" json:"days_of_month,omitempty"`
+	Months      []string    `yaml:"months,omitempty" json:"months,omitempty"`
+	Years       []string    `yaml:"years,omitempty" json:"years,omitempty"`
+	Location    string      `yaml:"location,omitempty" json:"location,omitempty"`
+}
+
+type timeRange struct {
+	StartTime string `yaml:"start_time" json:"start_time"`
+	EndTime   string `yaml:"end_time" json:"end_time"`
 }
 
 type inhibitRule struct {
@@ -94,6 +117,12 @@ type receiver struct {
 	SlackConfigs     []*slackConfig     `yaml:"slack_configs,omitempty" json:"slack_configs,omitempty"`
 	WebhookConfigs   []*webhookConfig   `yaml:"webhook_configs,omitempty" json:"webhook_configs,omitempty"`
 	WeChatConfigs    []*weChatConfig    `yaml:"wechat_configs,omitempty" json:"wechat_config,omitempty"`
+	TelegramConfigs  []*telegramConfig  `yaml:"telegram_configs,omitempty" json:"telegram_configs,omitempty"`
+	DiscordConfigs   []*discordConfig   `yaml:"discord_configs,omitempty" json:"discord_configs,omitempty"`
+	MSTeamsConfigs   []*msTeamsConfig   `yaml:"msteams_configs,omitempty" json:"msteams_configs,omitempty"`
+	MSTeamsV2Configs []*msTeamsV2Config `yaml:"msteamsv2_configs,omitempty" json:"msteamsv2_configs,omitempty"`
+	SNSConfigs       []*snsConfig       `yaml:"sns_configs,omitempty" json:"sns_configs,omitempty"`
+	WebexConfigs     []*webexConfig     `yaml:"webex_configs,omitempty" json:"webex_configs,omitempty"`
 	// TODO(simonpasquier): support the following receivers with AlertmanagerConfig.
 	EmailConfigs     []*emailConfig     `yaml:"email_configs,omitempty" json:"email_configs,omitempty"`
 	PushoverConfigs  []*pushoverConfig  `yaml:"pushover_configs,omitempty" json:"pushover_configs,omitempty"`
@@ -283,6 +312,71 @@ type pushoverConfig struct {
 	HTML          bool              `yaml:"html,omitempty" json:"html,omitempty"`
 }
 
+type telegramConfig struct {
+	VSendResolved        *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
+	HTTPConfig           *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+	APIUrl               string            `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	BotToken             string            `yaml:"bot_token,omitempty" json:"bot_token,omitempty"`
+	ChatID               int64             `yaml:"chat_id,omitempty" json:"chat_id,omitempty"`
+	Message              string            `yaml:"message,omitempty" json:"message,omitempty"`
+	DisableNotifications bool              `yaml:"disable_notifications,omitempty" json:"disable_notifications,omitempty"`
+	ParseMode            string            `yaml:"parse_mode,omitempty" json:"parse_mode,omitempty"`
+}
+
+type discordConfig struct {
+	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
+	HTTPConfig    *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+	WebhookURL    string            `yaml:"webhook_url,omitempty" json:"webhook_url,omitempty"`
+	Title         string            `yaml:"title,omitempty" json:"title,omitempty"`
+	Message       string            `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+type msTeamsConfig struct {
+	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
+	HTTPConfig    *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+	WebhookURL    string            `yaml:"webhook_url,omitempty" json:"webhook_url,omitempty"`
+	Title         string            `yaml:"title,omitempty" json:"title,omitempty"`
+	Summary       string            `yaml:"summary,omitempty" json:"summary,omitempty"`
+	Text          string            `yaml:"text,omitempty" json:"text,omitempty"`
+}
+
+type msTeamsV2Config struct {
+	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
+	HTTPConfig    *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+	WebhookURL    string            `yaml:"webhook_url,omitempty" json:"webhook_url,omitempty"`
+	Title         string            `yaml:"title,omitempty" json:"title,omitempty"`
+	Text          string            `yaml:"text,omitempty" json:"text,omitempty"`
+}
+
+type snsConfig struct {
+	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
+	HTTPConfig    *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+	APIUrl        string            `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	Sigv4         *sigV4Config      `yaml:"sigv4,omitempty" json:"sigv4,omitempty"`
+	TopicARN      string            `yaml:"topic_arn,omitempty" json:"topic_arn,omitempty"`
+	PhoneNumber   string            `yaml:"phone_number,omitempty" json:"phone_number,omitempty"`
+	TargetARN     string            `yaml:"target_arn,omitempty" json:"target_arn,omitempty"`
+	Subject       string            `yaml:"subject,omitempty" json:"subject,omitempty"`
+	Message       string            `yaml:"message,omitempty" json:"message,omitempty"`
+	Attributes    map[string]string `yaml:"attributes,omitempty" json:"attributes,omitempty"`
+}
+
+type sigV4Config struct {
+	Region    string `yaml:"region,omitempty" json:"region,omitempty"`
+	AccessKey string `yaml:"access_key,omitempty" json:"access_key,omitempty"`
+	SecretKey string `yaml:"secret_key,omitempty" json:"secret_key,omitempty"`
+	Profile   string `yaml:"profile,omitempty" json:"profile,omitempty"`
+	RoleARN   string `yaml:"role_arn,omitempty" json:"role_arn,omitempty"`
+}
+
+type webexConfig struct {
+	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
+	HTTPConfig    *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+	APIURL        string            `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	RoomID        string            `yaml:"room_id" json:"room_id"`
+	Message       string            `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
 type duration time.Duration
 
 func (d *duration) UnmarshalText(text []byte) error {
@@ -18,13 +18,61 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v2"
 )
 
+// ValidateAlertmanagerConfig checks that the given AlertmanagerConfig object
+// is semantically valid. It doesn't check the references to other objects
+// (e.g. secrets) which requires access to the Kubernetes API.
+func ValidateAlertmanagerConfig(amc *monitoringv1alpha1.AlertmanagerConfig) error {
+	receivers, err := validateReceivers(amc.Spec.Receivers)
+	if err != nil {
+		return err
+	}
+
+	muteTimeIntervals, err := validateMuteTimeIntervals(amc.Spec.MuteTimeIntervals)
+	if err != nil {
+		return err
+	}
+
+	if err := validateAlertManagerRoutes(amc.Spec.Route, receivers, muteTimeIntervals, true); err != nil {
+		return err
+	}
+
+	return validateInhibitRules(amc.Spec.InhibitRules)
+}
+
+// validateGlobalAlertmanagerConfig verifies that the AlertmanagerConfig
+// object can define the top-level route of the Alertmanager configuration.
+func validateGlobalAlertmanagerConfig(amc *monitoringv1alpha1.AlertmanagerConfig) error {
+	if err := ValidateAlertmanagerConfig(amc); err != nil {
+		return err
+	}
+
+	if amc.Spec.Route == nil {
+		return errors.New("the top-level route must be defined")
+	}
+
+	if len(amc.Spec.Route.Matchers) > 0 {
+		return errors.New("the top-level route can't have matchers")
+	}
+
+	if amc.Spec.Route.Continue {
+		return errors.New("the top-level route can't have 'continue' set to true")
+	}
+
+	return nil
+}
+
 func validateReceivers(receivers []monitoringv1alpha1.Receiver) (map[string]struct{}, error) {
 	var err error
 	receiverNames := make(map[string]struct{})
@@ -67,6 +115,30 @@ func validateReceivers(receivers []monitoringv1alpha1.Receiver) (map[string]stru
 		if err := validatePushoverConfigs(receiver.PushoverConfigs); err != nil {
 			return nil, errors.Wrapf(err, "failed to validate 'pushOverConfig' - receiver %s", receiver.Name)
 		}
+
+		if err := validateTelegramConfigs(receiver.TelegramConfigs); err != nil {
+			return nil, errors.Wrapf(err, "failed to validate 'telegramConfig' - receiver %s", receiver.Name)
+		}
+
+		if err := validateDiscordConfigs(receiver.DiscordConfigs); err != nil {
+			return nil, errors.Wrapf(err, "failed to validate 'discordConfig' - receiver %s", receiver.Name)
+		}
+
+		if err := validateMSTeamsConfigs(receiver.MSTeamsConfigs); err != nil {
+			return nil, errors.Wrapf(err, "failed to validate 'msteamsConfig' - receiver %s", receiver.Name)
+		}
+
+		if err := validateMSTeamsV2Configs(receiver.MSTeamsV2Configs); err != nil {
+			return nil, errors.Wrapf(err, "failed to validate 'msteamsv2Config' - receiver %s", receiver.Name)
+		}
+
+		if err := validateSNSConfigs(receiver.SNSConfigs); err != nil {
+			return nil, errors.Wrapf(err, "failed to validate 'snsConfig' - receiver %s", receiver.Name)
+		}
+
+		if err := validateWebexConfigs(receiver.WebexConfigs); err != nil {
+			return nil, errors.Wrapf(err, "failed to validate 'webexConfig' - receiver %s", receiver.Name)
+		}
 	}
 
 	return receiverNames, nil
@@ -199,8 +271,108 @@ func validatePushoverConfigs(configs []monitoringv1alpha1.PushoverConfig) error
 	return nil
 }
 
+func validateTelegramConfigs(configs []monitoringv1alpha1.TelegramConfig) error {
+	for _, config := range configs {
+		if config.BotToken == nil {
+			return errors.Errorf("mandatory field %q is empty", "botToken")
+		}
+
+		if config.ChatID == 0 {
+			return errors.Errorf("mandatory field %q is empty", "chatID")
+		}
+
+		if config.APIURL != "" {
+			if _, err := url.Parse(config.APIURL); err != nil {
+				return errors.Wrap(err, "telegram 'apiURL' not valid")
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateDiscordConfigs(configs []monitoringv1alpha1.DiscordConfig) error {
+	for _, config := range configs {
+		if config.APIURL == nil {
+			return errors.Errorf("mandatory field %q is empty", "apiURL")
+		}
+	}
+
+	return nil
+}
+
+func validateMSTeamsConfigs(configs []monitoringv1alpha1.MSTeamsConfig) error {
+	for _, config := range configs {
+		if config.WebhookURL == nil {
+			return errors.Errorf("mandatory field %q is empty", "webhookUrl")
+		}
+	}
+
+	return nil
+}
+
+func validateMSTeamsV2Configs(configs []monitoringv1alpha1.MSTeamsV2Config) error {
+	for _, config := range configs {
+		if config.WebhookURL == nil {
+			return errors.Errorf("mandatory field %q is empty", "webhookUrl")
+		}
+	}
+
+	return nil
+}
+
+func validateSNSConfigs(configs []monitoringv1alpha1.SNSConfig) error {
+	for _, config := range configs {
+		var targets int
+		for _, t := range []string{config.TopicARN, config.PhoneNumber, config.TargetARN} {
+			if t != "" {
+				targets++
+			}
+		}
+		if targets != 1 {
+			return errors.New("exactly one of 'topicARN', 'phoneNumber' and 'targetARN' must be specified")
+		}
+
+		if config.APIURL != "" {
+			if _, err := url.Parse(config.APIURL); err != nil {
+				return errors.Wrap(err, "sns 'apiURL' not valid")
+			}
+		}
+
+		if config.Sigv4 != nil && (config.Sigv4.AccessKey == nil) != (config.Sigv4.SecretKey == nil) {
+			return errors.New("'sigv4.accessKey' and 'sigv4.secretKey' must be specified together")
+		}
+	}
+
+	return nil
+}
+
+func validateWebexConfigs(configs []monitoringv1alpha1.WebexConfig) error {
+	for _, config := range configs {
+		if config.BotToken == nil {
+			return errors.Errorf("mandatory field %q is empty", "botToken")
+		}
+
+		if config.RoomID == "" {
+			return errors.Errorf("mandatory field %q is empty", "roomID")
+		}
+
+		if config.APIURL != "" {
+			if _, err := url.Parse(config.APIURL); err != nil {
+				return errors.Wrap(err, "webex 'apiURL' not valid")
+			}
+		}
+
+		if hc := config.HTTPConfig; hc != nil && (hc.Authorization != nil || hc.BasicAuth != nil || hc.BearerTokenSecret != nil) {
+			return errors.New("'httpConfig' can't define the authorization which is configured from 'botToken'")
+		}
+	}
+
+	return nil
+}
+
 // validateAlertManagerRoutes verifies that the given route and all its children are semantically valid.
-func validateAlertManagerRoutes(r *monitoringv1alpha1.Route, receivers map[string]struct{}, topLevelRoute bool) error {
+func validateAlertManagerRoutes(r *monitoringv1alpha1.Route, receivers, muteTimeIntervals map[string]struct{}, topLevelRoute bool) error {
 	if r == nil {
 		return nil
 	}
@@ -209,16 +381,134 @@ func validateAlertManagerRoutes(r *monitoringv1alpha1.Route, receivers map[strin
 		return errors.Errorf("receiver %q not found", r.Receiver)
 	}
 
+	for _, d := range []struct {
+		name  string
+		value string
+	}{
+		{name: "groupWait", value: r.GroupWait},
+		{name: "groupInterval", value: r.GroupInterval},
+		{name: "repeatInterval", value: r.RepeatInterval},
+	} {
+		if d.value == "" {
+			continue
+		}
+		if _, err := model.ParseDuration(d.value); err != nil {
+			return errors.Wrapf(err, "invalid %s", d.name)
+		}
+	}
+
+	if err := validateMatchers(r.Matchers); err != nil {
+		return err
+	}
+
+	for _, ti := range r.MuteTimeIntervals {
+		if _, found := muteTimeIntervals[ti]; !found {
+			return errors.Errorf("mute time interval %q not found", ti)
+		}
+	}
+
+	for _, ti := range r.ActiveTimeIntervals {
+		if _, found := muteTimeIntervals[ti]; !found {
+			return errors.Errorf("active time interval %q not found", ti)
+		}
+	}
+
 	children, err := r.ChildRoutes()
 	if err != nil {
 		return err
 	}
 
 	for i := range children {
-		if err := validateAlertManagerRoutes(&children[i], receivers, false); err != nil {
+		if err := validateAlertManagerRoutes(&children[i], receivers, muteTimeIntervals, false); err != nil {
 			return errors.Wrapf(err, "route[%d]", i)
 		}
 	}
 
 	return nil
 }
+
+func validateInhibitRules(rules []monitoringv1alpha1.InhibitRule) error {
+	for i, rule := range rules {
+		if err := validateMatchers(rule.SourceMatch); err != nil {
+			return errors.Wrapf(err, "inhibitRules[%d]: invalid sourceMatch", i)
+		}
+
+		if err := validateMatchers(rule.TargetMatch); err != nil {
+			return errors.Wrapf(err, "inhibitRules[%d]: invalid targetMatch", i)
+		}
+
+		for _, l := range rule.Equal {
+			if !model.LabelName(l).IsValid() {
+				return errors.Errorf("inhibitRules[%d]: invalid label name %q in equal", i, l)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateMatchers(matchers []monitoringv1alpha1.Matcher) error {
+	for _, m := range matchers {
+		if !model.LabelName(m.Name).IsValid() {
+			return errors.Errorf("invalid label name %q in matcher", m.Name)
+		}
+
+		if !utf8.ValidString(m.Value) {
+			return errors.Errorf("invalid UTF-8 value in matcher %q", m.Name)
+		}
+
+		if m.Regex {
+			if _, err := regexp.Compile("^(?:" + m.Value + ")$"); err != nil {
+				return errors.Wrapf(err, "invalid regular expression %q in matcher %q", m.Value, m.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateMuteTimeIntervals verifies that the time intervals are unique and
+// that their time ranges and time zones are valid. It returns the names of
+// the time intervals.
+func validateMuteTimeIntervals(muteTimeIntervals []monitoringv1alpha1.MuteTimeInterval) (map[string]struct{}, error) {
+	names := make(map[string]struct{}, len(muteTimeIntervals))
+
+	for _, mti := range muteTimeIntervals {
+		if mti.Name == "" {
+			return nil, errors.New("mute time interval name can't be empty")
+		}
+
+		if _, found := names[mti.Name]; found {
+			return nil, errors.Errorf("%q mute time interval is not unique", mti.Name)
+		}
+		names[mti.Name] = struct{}{}
+
+		for i, ti := range mti.TimeIntervals {
+			if err := validateTimeInterval(ti); err != nil {
+				return nil, errors.Wrapf(err, "mute time interval %q: time interval[%d]", mti.Name, i)
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// validateTimeInterval relies on the Alertmanager parser to validate the time
+// ranges since it already checks their boundaries and ordering.
+func validateTimeInterval(ti monitoringv1alpha1.TimeInterval) error {
+	tp := convertTimeInterval(ti)
+
+	if tp.Location != "" {
+		if _, err := time.LoadLocation(tp.Location); err != nil {
+			return errors.Wrap(err, "invalid location")
+		}
+		tp.Location = ""
+	}
+
+	b, err := yaml.Marshal(tp)
+	if err != nil {
+		return err
+	}
+
+	return yaml.UnmarshalStrict(b, &timeinterval.TimeInterval{})
+}
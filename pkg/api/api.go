@@ -15,6 +15,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"regexp"
@@ -32,9 +33,16 @@ import (
 )
 
 type API struct {
-	kclient *kubernetes.Clientset
-	mclient monitoringclient.Interface
-	logger  log.Logger
+	kclient          *kubernetes.Clientset
+	mclient          monitoringclient.Interface
+	logger           log.Logger
+	amConfigRenderer AlertmanagerConfigRenderer
+}
+
+// AlertmanagerConfigRenderer renders the configuration generated for an
+// Alertmanager object with the credentials redacted.
+type AlertmanagerConfigRenderer interface {
+	RenderConfig(ctx context.Context, namespace, name string) ([]byte, error)
 }
 
 func New(conf operator.Config, l log.Logger) (*API, error) {
@@ -61,15 +69,26 @@ func New(conf operator.Config, l log.Logger) (*API, error) {
 }
 
 var (
-	prometheusRoute = regexp.MustCompile("/apis/monitoring.coreos.com/" + v1.Version + "/namespaces/(.*)/prometheuses/(.*)/status")
+	prometheusRoute         = regexp.MustCompile("/apis/monitoring.coreos.com/" + v1.Version + "/namespaces/(.*)/prometheuses/(.*)/status")
+	alertmanagerConfigRoute = regexp.MustCompile("/apis/monitoring.coreos.com/" + v1.Version + "/namespaces/(.*)/alertmanagers/(.*)/config")
 )
 
+// EnableAlertmanagerConfigPreview serves the configuration generated for the
+// Alertmanager objects under
+// /apis/monitoring.coreos.com/v1/namespaces/<namespace>/alertmanagers/<name>/config.
+func (api *API) EnableAlertmanagerConfigPreview(r AlertmanagerConfigRenderer) {
+	api.amConfigRenderer = r
+}
+
 func (api *API) Register(mux *http.ServeMux) {
 	mux.HandleFunc("/healthz", ok)
 	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
-		if prometheusRoute.MatchString(req.URL.Path) {
+		switch {
+		case prometheusRoute.MatchString(req.URL.Path):
 			api.prometheusStatus(w, req)
-		} else {
+		case alertmanagerConfigRoute.MatchString(req.URL.Path) && api.amConfigRenderer != nil:
+			api.alertmanagerConfig(w, req)
+		default:
 			w.WriteHeader(404)
 		}
 	})
@@ -81,7 +100,11 @@ type objectReference struct {
 }
 
 func parsePrometheusStatusURL(path string) objectReference {
-	matches := prometheusRoute.FindAllStringSubmatch(path, -1)
+	return parseObjectURL(prometheusRoute, path)
+}
+
+func parseObjectURL(route *regexp.Regexp, path string) objectReference {
+	matches := route.FindAllStringSubmatch(path, -1)
 	ns := ""
 	name := ""
 	if len(matches) == 1 {
@@ -124,6 +147,25 @@ func (api *API) prometheusStatus(w http.ResponseWriter, req *http.Request) {
 	w.Write(b)
 }
 
+func (api *API) alertmanagerConfig(w http.ResponseWriter, req *http.Request) {
+	or := parseObjectURL(alertmanagerConfigRoute, req.URL.Path)
+
+	b, err := api.amConfigRenderer.RenderConfig(req.Context(), or.namespace, or.name)
+	if err != nil {
+		if k8sutil.IsResourceNotFoundError(err) {
+			w.WriteHeader(404)
+			return
+		}
+		api.logger.Log("error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(200)
+	w.Write(b)
+}
+
 func ok(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
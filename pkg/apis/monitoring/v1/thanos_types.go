@@ -30,6 +30,11 @@ const (
 // +genclient
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:categories="prometheus-operator"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas",description="The desired replicas number of Thanos Rulers"
+// +kubebuilder:printcolumn:name="Reconciled",type="string",JSONPath=".status.conditions[?(@.type == 'Reconciled')].status",description="Whether the resource was reconciled successfully"
+// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type == 'Available')].status",description="Whether the resource is available"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
 type ThanosRuler struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -82,6 +87,15 @@ type ThanosRulerSpec struct {
 	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
 	// If specified, the pod's topology spread constraints.
 	TopologySpreadConstraints []v1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// Pods' hostAliases configuration
+	// +listType=map
+	// +listMapKey=ip
+	HostAliases []HostAlias `json:"hostAliases,omitempty"`
+	// Defines the DNS policy for the pods.
+	// +kubebuilder:validation:Enum=ClusterFirstWithHostNet;ClusterFirst;Default;None
+	DNSPolicy v1.DNSPolicy `json:"dnsPolicy,omitempty"`
+	// Defines the DNS configuration for the pods.
+	DNSConfig *v1.PodDNSConfig `json:"dnsConfig,omitempty"`
 	// SecurityContext holds pod-level security attributes and common container settings.
 	// This defaults to the default PodSecurityContext.
 	SecurityContext *v1.PodSecurityContext `json:"securityContext,omitempty"`
@@ -112,6 +126,14 @@ type ThanosRulerSpec struct {
 	// Maps to the `query.config` CLI argument.
 	// Only available with thanos v0.11.0 and higher.
 	QueryConfig *v1.SecretKeySelector `json:"queryConfig,omitempty"`
+	// Discover the Thanos Querier endpoints from the Services matching the
+	// selector in the ThanosRuler namespace. The operator maintains a file
+	// service discovery configuration with the ready endpoints of the
+	// Services which is reloaded by the Thanos ruler without restart when
+	// the Thanos Queriers scale.
+	// It can't be set at the same time as QueryEndpoints or QueryConfig.
+	// +optional
+	QueryServiceSelector *QueryServiceSelector `json:"queryServiceSelector,omitempty"`
 	// Define URLs to send alerts to Alertmanager.  For Thanos v0.10.0 and higher,
 	// AlertManagersConfig should be used instead.  Note: this field will be ignored
 	// if AlertManagersConfig is specified.
@@ -134,6 +156,12 @@ type ThanosRulerSpec struct {
 	// of adding namespace labels. Works only if enforcedNamespaceLabel set to true.
 	// Make sure both ruleNamespace and ruleName are set for each pair
 	PrometheusRulesExcludedFromEnforce []PrometheusRuleExcludeConfig `json:"prometheusRulesExcludedFromEnforce,omitempty"`
+	// Settings of the Thanos ruler for the rule groups of the selected
+	// PrometheusRule objects. For each rule group, the first matching item
+	// applies and its settings take precedence over the values defined in
+	// the rule group.
+	// +optional
+	RuleGroupOverrides []ThanosRuleGroupOverride `json:"ruleGroupOverrides,omitempty"`
 	// Log level for ThanosRuler to be configured with.
 	LogLevel string `json:"logLevel,omitempty"`
 	// Log format for ThanosRuler to be configured with.
@@ -182,13 +210,14 @@ type ThanosRulerSpec struct {
 	// Maps to the '--grpc-server-tls-*' CLI args.
 	GRPCServerTLSConfig *TLSConfig `json:"grpcServerTlsConfig,omitempty"`
 	// The external Query URL the Thanos Ruler will set in the 'Source' field
-	// of all alerts.
+	// of all alerts. It must be an absolute HTTP or HTTPS URL.
 	// Maps to the '--alert.query-url' CLI arg.
 	AlertQueryURL string `json:"alertQueryUrl,omitempty"`
 	// Minimum number of seconds for which a newly created pod should be ready
 	// without any of its container crashing for it to be considered available.
 	// Defaults to 0 (pod will be considered available as soon as it is ready)
-	// This is an alpha field and requires enabling StatefulSetMinReadySeconds feature gate.
+	// This is an alpha field from kubernetes 1.22 until 1.24 which requires
+	// enabling the StatefulSetMinReadySeconds feature gate.
 	// +optional
 	MinReadySeconds *uint32 `json:"minReadySeconds,omitempty"`
 	// AlertRelabelConfigs configures alert relabeling in ThanosRuler.
@@ -199,6 +228,61 @@ type ThanosRulerSpec struct {
 	// AlertRelabelConfigFile specifies the path of the alert relabeling configuration file.
 	// When used alongside with AlertRelabelConfigs, alertRelabelConfigFile takes precedence.
 	AlertRelabelConfigFile *string `json:"alertRelabelConfigFile,omitempty"`
+	// AlertRelabelings configures the relabeling of the alerts before they
+	// are sent to Alertmanager, for instance to rewrite the external labels.
+	// Unlike AlertRelabelConfigs, the relabel configurations are validated
+	// and the file is generated by the operator.
+	// Cannot be set at the same time as AlertRelabelConfigs or
+	// AlertRelabelConfigFile.
+	AlertRelabelings []*RelabelConfig `json:"alertRelabelings,omitempty"`
+	// Defines the list of remote write configurations. When defined, the
+	// Thanos ruler runs in stateless mode: it ships the evaluated series to
+	// the remote endpoints instead of storing them in local blocks and the
+	// `retention` and `objectStorageConfig` fields are ignored.
+	// The fields have the same semantics as for Prometheus but they must be
+	// supported by the Prometheus version embedded in Thanos.
+	// Maps to the `remote-write.config` arg.
+	// Only available with Thanos v0.24.0 and higher.
+	RemoteWrite []RemoteWriteSpec `json:"remoteWrite,omitempty"`
+}
+
+// ThanosRuleGroupOverride defines the settings of the Thanos ruler for the
+// rule groups of the PrometheusRule objects matching the selector.
+// +k8s:openapi-gen=true
+type ThanosRuleGroupOverride struct {
+	// Label selector for the PrometheusRule objects. An empty selector
+	// matches all the objects.
+	// +optional
+	RuleSelector *metav1.LabelSelector `json:"ruleSelector,omitempty"`
+	// Names of the rule groups to which the settings apply. When empty, the
+	// settings apply to all the groups of the matching objects.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+	// Strategy of the rule group evaluation when some of the queried
+	// StoreAPIs are unavailable.
+	// +kubebuilder:validation:Enum=abort;warn
+	// +optional
+	PartialResponseStrategy string `json:"partialResponseStrategy,omitempty"`
+	// Maximum number of alerts or series produced by the rules of the group.
+	// 0 means no limit.
+	// Only available with Thanos v0.24.0 and higher.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Limit *int32 `json:"limit,omitempty"`
+}
+
+// QueryServiceSelector selects the Services of the Thanos Queriers used by
+// ThanosRuler to evaluate the rules.
+// +k8s:openapi-gen=true
+type QueryServiceSelector struct {
+	// Label selector for the Services of the Thanos Queriers. The endpoints
+	// are read from the EndpointSlices which carry the labels of their
+	// Service.
+	Selector metav1.LabelSelector `json:"selector"`
+	// Name of the Service port exposing the HTTP API of the Thanos Queriers.
+	// Defaults to `http`.
+	// +optional
+	Port string `json:"port,omitempty"`
 }
 
 // ThanosRulerStatus is the most recent observed status of the ThanosRuler. Read-only. Not
@@ -221,6 +305,16 @@ type ThanosRulerStatus struct {
 	AvailableReplicas int32 `json:"availableReplicas"`
 	// Total number of unavailable pods targeted by this ThanosRuler deployment.
 	UnavailableReplicas int32 `json:"unavailableReplicas"`
+	// The current state of the ThanosRuler object.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Number of PrometheusRule objects selected by the ThanosRuler object
+	// and loaded by the Thanos ruler. The rejected objects are reported by
+	// the `RejectedResources` condition.
+	// +optional
+	SelectedPrometheusRules int32 `json:"selectedPrometheusRules,omitempty"`
 }
 
 // DeepCopyObject implements the runtime.Object interface.
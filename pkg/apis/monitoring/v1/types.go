@@ -58,7 +58,11 @@ const (
 // +kubebuilder:resource:categories="prometheus-operator"
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.version",description="The version of Prometheus"
 // +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas",description="The desired replicas number of Prometheuses"
+// +kubebuilder:printcolumn:name="Reconciled",type="string",JSONPath=".status.conditions[?(@.type == 'Reconciled')].status",description="Whether the resource was reconciled successfully"
+// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type == 'Available')].status",description="Whether the resource is available"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.shards,statuspath=.status.shards,selectorpath=.status.selector
 type Prometheus struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -107,6 +111,25 @@ type PrometheusSpec struct {
 	ProbeSelector *metav1.LabelSelector `json:"probeSelector,omitempty"`
 	// *Experimental* Namespaces to be selected for Probe discovery. If nil, only check own namespace.
 	ProbeNamespaceSelector *metav1.LabelSelector `json:"probeNamespaceSelector,omitempty"`
+	// *Experimental* ScrapeConfigs to be selected for target discovery.
+	ScrapeConfigSelector *metav1.LabelSelector `json:"scrapeConfigSelector,omitempty"`
+	// *Experimental* Namespaces to be selected for ScrapeConfig discovery. If nil, only check own namespace.
+	ScrapeConfigNamespaceSelector *metav1.LabelSelector `json:"scrapeConfigNamespaceSelector,omitempty"`
+	// List of scrape classes which ServiceMonitors, PodMonitors and Probes
+	// can reference with the `scrapeClass` field to share common scrape
+	// settings. At most one scrape class can be marked as default.
+	// +listType=map
+	// +listMapKey=name
+	ScrapeClasses []ScrapeClass `json:"scrapeClasses,omitempty"`
+	// The Kubernetes service discovery role used to discover the targets of
+	// the ServiceMonitors and the Alertmanager endpoints. The `EndpointSlice`
+	// role scales better for Services with many endpoints but requires
+	// Prometheus to have the permissions to list and watch the EndpointSlice
+	// objects.
+	// If unset, the operator uses the `Endpoints` role.
+	// The `EndpointSlice` role is only valid in Prometheus versions 2.21.0
+	// and newer.
+	ServiceDiscoveryRole *ServiceDiscoveryRole `json:"serviceDiscoveryRole,omitempty"`
 	// Version of Prometheus to be deployed.
 	Version string `json:"version,omitempty"`
 	// Tag of Prometheus container image to be deployed. Defaults to the value of `version`.
@@ -146,7 +169,8 @@ type PrometheusSpec struct {
 	// either but it will continue to be available from the same instances. To
 	// query globally use Thanos sidecar and Thanos querier or remote write
 	// data to a central location. Sharding is done on the content of the
-	// `__address__` target meta-label.
+	// `__address__` target meta-label. The field is exposed through the scale
+	// subresource so that autoscalers can manage the number of shards.
 	Shards *int32 `json:"shards,omitempty"`
 	// Name of Prometheus external label used to denote replica name.
 	// Defaults to the value of `prometheus_replica`. External label will
@@ -174,6 +198,12 @@ type PrometheusSpec struct {
 	ScrapeInterval string `json:"scrapeInterval,omitempty"`
 	// Number of seconds to wait for target to respond before erroring.
 	ScrapeTimeout string `json:"scrapeTimeout,omitempty"`
+	// The protocols to negotiate during a scrape, in order of preference. It
+	// applies to all the scrape jobs which don't define their own
+	// scrapeProtocols value. If unset, Prometheus uses its default value.
+	// Only valid in Prometheus versions 2.49.0 and newer.
+	// +listType=set
+	ScrapeProtocols []ScrapeProtocol `json:"scrapeProtocols,omitempty"`
 	// Interval between consecutive evaluations. Default: `1m`
 	EvaluationInterval string `json:"evaluationInterval,omitempty"`
 	// /--rules.*/ command-line arguments.
@@ -188,12 +218,30 @@ type PrometheusSpec struct {
 	// ensure only clients authorized to perform these actions can do so.
 	// For more information see https://prometheus.io/docs/prometheus/latest/querying/api/#tsdb-admin-apis
 	EnableAdminAPI bool `json:"enableAdminAPI,omitempty"`
+	// Enable Prometheus to be used as a receiver for the Prometheus remote
+	// write protocol. Defaults to the value of `false`.
+	// WARNING: This is not considered an efficient way of ingesting samples.
+	// Use it with caution for specific low-volume use cases.
+	// It is not suitable for replacing the ingestion via scraping and turning
+	// Prometheus into a push-based metrics collection system.
+	// It requires Prometheus >= v2.33.0.
+	EnableRemoteWriteReceiver bool `json:"enableRemoteWriteReceiver,omitempty"`
 	// Enable access to Prometheus disabled features. By default, no features are enabled.
 	// Enabling disabled features is entirely outside the scope of what the maintainers will
 	// support and by doing so, you accept that this behaviour may break at any
 	// time without notice.
 	// For more information see https://prometheus.io/docs/prometheus/latest/disabled_features/
 	EnableFeatures []string `json:"enableFeatures,omitempty"`
+	// EnableNativeHistograms enables the ingestion of native histograms. The
+	// operator adds the `native-histograms` feature flag so that it doesn't
+	// need to be listed in `enableFeatures`.
+	// Only valid in Prometheus versions 2.40.0 and newer.
+	EnableNativeHistograms bool `json:"enableNativeHistograms,omitempty"`
+	// Exemplars related settings. Setting this field enables the exemplar
+	// storage: the operator adds the `exemplar-storage` feature flag so that
+	// it doesn't need to be listed in `enableFeatures`.
+	// Only valid in Prometheus versions 2.25.0 and newer.
+	Exemplars *Exemplars `json:"exemplars,omitempty"`
 	// The external URL the Prometheus instances will be available under. This is
 	// necessary to generate correct URLs. This is necessary if Prometheus is not
 	// served from root of a DNS name.
@@ -247,9 +295,23 @@ type PrometheusSpec struct {
 	// If specified, the pod's tolerations.
 	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
 	// If specified, the pod's topology spread constraints.
-	TopologySpreadConstraints []v1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// Constraints defining `additionalLabelSelectors` get the labels of the
+	// Prometheus pods added to their label selector by the operator.
+	TopologySpreadConstraints []TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// Pods' hostAliases configuration
+	// +listType=map
+	// +listMapKey=ip
+	HostAliases []HostAlias `json:"hostAliases,omitempty"`
+	// Defines the DNS policy for the pods.
+	// +kubebuilder:validation:Enum=ClusterFirstWithHostNet;ClusterFirst;Default;None
+	DNSPolicy v1.DNSPolicy `json:"dnsPolicy,omitempty"`
+	// Defines the DNS configuration for the pods.
+	DNSConfig *v1.PodDNSConfig `json:"dnsConfig,omitempty"`
 	// If specified, the remote_write spec. This is an experimental feature, it may change in any upcoming release in a breaking way.
 	RemoteWrite []RemoteWriteSpec `json:"remoteWrite,omitempty"`
+	// Configures the remote write to Thanos Receive. The endpoint is added to
+	// the ones defined in `remoteWrite`.
+	RemoteWriteToThanosReceive *RemoteWriteToThanosReceiveSpec `json:"remoteWriteToThanosReceive,omitempty"`
 	// If specified, the remote_read spec. This is an experimental feature, it may change in any upcoming release in a breaking way.
 	RemoteRead []RemoteReadSpec `json:"remoteRead,omitempty"`
 	// SecurityContext holds pod-level security attributes and common container settings.
@@ -275,10 +337,20 @@ type PrometheusSpec struct {
 	// InitContainers described here modify an operator
 	// generated init containers if they share the same name and modifications are
 	// done via a strategic merge patch. The current init container name is:
-	// `init-config-reloader`. Overriding init containers is entirely outside the
+	// `init-config-reloader`. The values set in InitContainers take precedence
+	// over the operator generated values and the additional init containers run
+	// after the operator generated ones. Init container names must be unique.
+	// Overriding init containers is entirely outside the
 	// scope of what the maintainers will support and by doing so, you accept that
 	// this behaviour may break at any time without notice.
 	InitContainers []v1.Container `json:"initContainers,omitempty"`
+	// Sidecars defines containers running alongside the Prometheus
+	// container. Contrary to `containers`, the operator adds them after its
+	// own containers, mounts the shared volumes on demand and restarts the
+	// pods when the Secrets referenced by the sidecars change.
+	// +listType=map
+	// +listMapKey=name
+	Sidecars []Sidecar `json:"sidecars,omitempty"`
 	// AdditionalScrapeConfigs allows specifying a key of a Secret containing
 	// additional Prometheus scrape configurations. Scrape configurations
 	// specified are appended to the configurations generated by the Prometheus
@@ -389,30 +461,47 @@ type PrometheusSpec struct {
 	// Per-scrape limit on number of labels that will be accepted for a sample. If
 	// more than this number of labels are present post metric-relabeling, the
 	// entire scrape will be treated as failed. 0 means no limit.
+	// This overrides any LabelLimit set per ServiceMonitor, PodMonitor, Probe
+	// or ScrapeConfig. If LabelLimit is lower, that value will be taken
+	// instead, except if it is zero.
 	// Only valid in Prometheus versions 2.27.0 and newer.
 	EnforcedLabelLimit *uint64 `json:"enforcedLabelLimit,omitempty"`
 	// Per-scrape limit on length of labels name that will be accepted for a sample.
 	// If a label name is longer than this number post metric-relabeling, the entire
 	// scrape will be treated as failed. 0 means no limit.
+	// This overrides any LabelNameLengthLimit set per ServiceMonitor,
+	// PodMonitor, Probe or ScrapeConfig. If LabelNameLengthLimit is lower, that
+	// value will be taken instead, except if it is zero.
 	// Only valid in Prometheus versions 2.27.0 and newer.
 	EnforcedLabelNameLengthLimit *uint64 `json:"enforcedLabelNameLengthLimit,omitempty"`
 	// Per-scrape limit on length of labels value that will be accepted for a sample.
 	// If a label value is longer than this number post metric-relabeling, the
 	// entire scrape will be treated as failed. 0 means no limit.
+	// This overrides any LabelValueLengthLimit set per ServiceMonitor,
+	// PodMonitor, Probe or ScrapeConfig. If LabelValueLengthLimit is lower,
+	// that value will be taken instead, except if it is zero.
 	// Only valid in Prometheus versions 2.27.0 and newer.
 	EnforcedLabelValueLengthLimit *uint64 `json:"enforcedLabelValueLengthLimit,omitempty"`
 	// EnforcedBodySizeLimit defines the maximum size of uncompressed response body
 	// that will be accepted by Prometheus. Targets responding with a body larger than this many bytes
 	// will cause the scrape to fail. Example: 100MB.
-	// If defined, the limit will apply to all service/pod monitors and probes.
+	// If defined, the limit will apply to all service/pod monitors, probes and
+	// scrape configs.
 	// This is an experimental feature, this behaviour could
 	// change or be removed in the future.
 	// Only valid in Prometheus versions 2.28.0 and newer.
 	EnforcedBodySizeLimit string `json:"enforcedBodySizeLimit,omitempty"`
+	// Per-scrape limit on the number of targets dropped by relabeling that
+	// will be kept in memory and shown in the Prometheus UI. 0 means no limit.
+	// It applies to all the scrape jobs which don't define their own
+	// keepDroppedTargets value.
+	// Only valid in Prometheus versions 2.47.0 and newer.
+	KeepDroppedTargets *uint64 `json:"keepDroppedTargets,omitempty"`
 	// Minimum number of seconds for which a newly created pod should be ready
 	// without any of its container crashing for it to be considered available.
 	// Defaults to 0 (pod will be considered available as soon as it is ready)
-	// This is an alpha field and requires enabling StatefulSetMinReadySeconds feature gate.
+	// This is an alpha field from kubernetes 1.22 until 1.24 which requires
+	// enabling the StatefulSetMinReadySeconds feature gate.
 	// +optional
 	MinReadySeconds *uint32 `json:"minReadySeconds,omitempty"`
 }
@@ -458,6 +547,106 @@ type PrometheusStatus struct {
 	AvailableReplicas int32 `json:"availableReplicas"`
 	// Total number of unavailable pods targeted by this Prometheus deployment.
 	UnavailableReplicas int32 `json:"unavailableReplicas"`
+	// The current state of the Prometheus deployment.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+	// The list has one entry per shard. Each shard is managed by one
+	// StatefulSet.
+	// +listType=map
+	// +listMapKey=shardID
+	// +optional
+	ShardStatuses []ShardStatus `json:"shardStatuses,omitempty"`
+	// Number of shards currently deployed. It is exposed with `spec.shards`
+	// through the scale subresource.
+	// +optional
+	Shards int32 `json:"shards,omitempty"`
+	// Label selector of the pods of all the shards, in the string format
+	// expected by the scale subresource.
+	// +optional
+	Selector string `json:"selector,omitempty"`
+}
+
+// ShardStatus is the status of a shard of a Prometheus deployment.
+// +k8s:openapi-gen=true
+type ShardStatus struct {
+	// Identifier of the shard.
+	// +required
+	ShardID string `json:"shardID"`
+	// Total number of pods targeted by this shard.
+	Replicas int32 `json:"replicas"`
+	// Total number of non-terminated pods targeted by this shard
+	// that have the desired spec.
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+	// Total number of available pods (ready for at least minReadySeconds)
+	// targeted by this shard.
+	AvailableReplicas int32 `json:"availableReplicas"`
+	// Total number of unavailable pods targeted by this shard.
+	UnavailableReplicas int32 `json:"unavailableReplicas"`
+}
+
+// ConditionType is the type of a condition of a custom resource status.
+type ConditionType string
+
+const (
+	// Available reports whether enough pods are ready to provide the
+	// service. It is False when at least one shard has no ready pod.
+	Available ConditionType = "Available"
+	// Reconciled reports whether the operator successfully reconciled the
+	// resource at its last attempt. When False, the reason and the message
+	// explain the last failure.
+	Reconciled ConditionType = "Reconciled"
+	// Degraded reports whether some pods of the resource aren't ready or
+	// don't run the desired spec.
+	Degraded ConditionType = "Degraded"
+	// DisallowedCapabilities reports whether the resource requested
+	// capabilities which are disabled by the operator's configuration.
+	DisallowedCapabilities ConditionType = "DisallowedCapabilities"
+	// RejectedResources reports whether some resources selected by the
+	// object were rejected because they are invalid. The message lists the
+	// rejected resources and the reasons.
+	RejectedResources ConditionType = "RejectedResources"
+	// StaleCredentials reports whether some pods still run with a previous
+	// version of the object storage configuration of the Thanos sidecar. It
+	// is True from the moment the Secret changes until all the pods are
+	// restarted.
+	StaleCredentials ConditionType = "StaleCredentials"
+)
+
+// ConditionStatus is the status of a condition.
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// Condition represents the state of the resources associated with the
+// custom resource.
+// +k8s:deepcopy-gen=true
+type Condition struct {
+	// Type of the condition being reported.
+	// +required
+	Type ConditionType `json:"type"`
+	// Status of the condition.
+	// +required
+	Status ConditionStatus `json:"status"`
+	// The last time the condition transitioned from one status to another.
+	// +required
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=date-time
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// The reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human-readable message indicating details for the condition's last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// The generation of the object observed when the condition was last updated.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // AlertingSpec defines parameters for alerting configuration of Prometheus servers.
@@ -468,7 +657,7 @@ type AlertingSpec struct {
 }
 
 // StorageSpec defines the configured storage for a group Prometheus servers.
-// If neither `emptyDir` nor `volumeClaimTemplate` is specified, then by default an [EmptyDir](https://kubernetes.io/docs/concepts/storage/volumes/#emptydir) will be used.
+// If neither `emptyDir`, `ephemeral` nor `volumeClaimTemplate` is specified, then by default an [EmptyDir](https://kubernetes.io/docs/concepts/storage/volumes/#emptydir) will be used.
 // +k8s:openapi-gen=true
 type StorageSpec struct {
 	// Deprecated: subPath usage will be disabled by default in a future release, this option will become unnecessary.
@@ -477,10 +666,82 @@ type StorageSpec struct {
 	// EmptyDirVolumeSource to be used by the Prometheus StatefulSets. If specified, used in place of any volumeClaimTemplate. More
 	// info: https://kubernetes.io/docs/concepts/storage/volumes/#emptydir
 	EmptyDir *v1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+	// EphemeralVolumeSource to be used by the Prometheus StatefulSets. If specified, used in place of any volumeClaimTemplate.
+	// The volume claim is created with each pod and deleted with it.
+	// This is a beta field in k8s 1.21, for lower versions, starting with k8s 1.19, it requires enabling the GenericEphemeralVolume feature gate.
+	// More info: https://kubernetes.io/docs/concepts/storage/ephemeral-volumes/#generic-ephemeral-volumes
+	Ephemeral *v1.EphemeralVolumeSource `json:"ephemeral,omitempty"`
 	// A PVC spec to be used by the Prometheus StatefulSets.
 	VolumeClaimTemplate EmbeddedPersistentVolumeClaim `json:"volumeClaimTemplate,omitempty"`
 }
 
+// AdditionalLabelSelectors defines which operator-managed labels are added to
+// the label selector of a topology spread constraint.
+// +kubebuilder:validation:Enum=OnResource;OnShard
+type AdditionalLabelSelectors string
+
+const (
+	// ResourceNameLabelSelector selects all the pods of the Prometheus
+	// resource.
+	ResourceNameLabelSelector AdditionalLabelSelectors = "OnResource"
+
+	// ShardAndResourceNameLabelSelector selects the pods of the same shard of
+	// the Prometheus resource.
+	ShardAndResourceNameLabelSelector AdditionalLabelSelectors = "OnShard"
+)
+
+// CoreV1TopologySpreadConstraint is the Kubernetes TopologySpreadConstraint
+// type, aliased to be inlined in TopologySpreadConstraint.
+type CoreV1TopologySpreadConstraint v1.TopologySpreadConstraint
+
+// TopologySpreadConstraint specifies how to spread matching pods among the
+// given topology.
+type TopologySpreadConstraint struct {
+	// The Kubernetes topology spread constraint.
+	CoreV1TopologySpreadConstraint `json:",inline"`
+
+	// Defines which operator-managed labels should be added to the
+	// labelSelector of the constraint. This avoids hard-coding the pod labels
+	// in the custom resource.
+	//
+	// * `OnResource`: the selector matches all the pods of the Prometheus resource.
+	// * `OnShard`: the selector matches the pods of the same shard.
+	//
+	// +optional
+	AdditionalLabelSelectors *AdditionalLabelSelectors `json:"additionalLabelSelectors,omitempty"`
+}
+
+// Sidecar defines a container running alongside the Prometheus container.
+type Sidecar struct {
+	// The sidecar container. Its name must not conflict with the names of
+	// the other containers of the pod.
+	v1.Container `json:",inline"`
+	// When true, the generated Prometheus configuration is mounted read-only
+	// into the sidecar at `/etc/prometheus/config_out`.
+	// +optional
+	MountConfig bool `json:"mountConfig,omitempty"`
+	// When true, the TLS assets used by the Prometheus configuration are
+	// mounted read-only into the sidecar at `/etc/prometheus/certs`.
+	// +optional
+	MountTLSAssets bool `json:"mountTLSAssets,omitempty"`
+	// Names of Secrets in the same namespace as the Prometheus object which
+	// are used by the sidecar. The pods are restarted when the content of
+	// any of these Secrets changes.
+	// +optional
+	RestartOnSecretChange []string `json:"restartOnSecretChange,omitempty"`
+}
+
+// HostAlias holds the mapping between IP and hostnames that will be injected as
+// an entry in the pod's hosts file.
+type HostAlias struct {
+	// IP address of the host file entry.
+	// +kubebuilder:validation:Required
+	IP string `json:"ip"`
+	// Hostnames for the above IP address.
+	// +kubebuilder:validation:Required
+	Hostnames []string `json:"hostnames"`
+}
+
 // EmbeddedPersistentVolumeClaim is an embedded version of k8s.io/api/core/v1.PersistentVolumeClaim.
 // It contains TypeMeta and a reduced ObjectMeta.
 type EmbeddedPersistentVolumeClaim struct {
@@ -528,6 +789,70 @@ type EmbeddedObjectMetadata struct {
 	Annotations map[string]string `json:"annotations,omitempty" protobuf:"bytes,12,rep,name=annotations"`
 }
 
+// Exemplars defines the exemplar storage settings.
+// +k8s:openapi-gen=true
+type Exemplars struct {
+	// Maximum number of exemplars stored in memory for all series.
+	// If not set, Prometheus uses its default value.
+	// A value of zero or less than zero disables the storage.
+	// Only valid in Prometheus versions 2.29.0 and newer.
+	MaxSize *int64 `json:"maxSize,omitempty"`
+}
+
+// ScrapeClass defines a set of scrape settings which are applied to the
+// ServiceMonitors, PodMonitors and Probes referencing it.
+// +k8s:openapi-gen=true
+type ScrapeClass struct {
+	// Name of the scrape class.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Default indicates that the scrape class applies to all the scrape
+	// objects which don't reference a scrape class explicitly.
+	Default *bool `json:"default,omitempty"`
+	// TLS configuration used when the scrape object doesn't define its own.
+	// Only the `caFile`, `certFile`, `keyFile`, `serverName` and
+	// `insecureSkipVerify` fields are supported.
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+	// Authorization section used when the scrape object doesn't define any
+	// authentication (bearer token, basic auth, OAuth2 or authorization).
+	// The secret holding the credentials needs to be in the same namespace
+	// as the Prometheus object.
+	Authorization *Authorization `json:"authorization,omitempty"`
+	// Relabelings applied to all the targets. They are added after the
+	// relabelings generated by the operator and before the relabelings of
+	// the scrape object.
+	Relabelings []*RelabelConfig `json:"relabelings,omitempty"`
+	// MetricRelabelings applied to all the samples before ingestion. They are
+	// added before the metric relabelings of the scrape object.
+	MetricRelabelings []*RelabelConfig `json:"metricRelabelings,omitempty"`
+}
+
+// ScrapeProtocol represents a protocol used by Prometheus for scraping
+// metrics. Supported values are:
+// * `OpenMetricsText0.0.1`
+// * `OpenMetricsText1.0.0`
+// * `PrometheusProto`
+// * `PrometheusText0.0.4`
+// +kubebuilder:validation:Enum=PrometheusProto;OpenMetricsText0.0.1;OpenMetricsText1.0.0;PrometheusText0.0.4
+type ScrapeProtocol string
+
+const (
+	PrometheusProto      ScrapeProtocol = "PrometheusProto"
+	PrometheusText0_0_4  ScrapeProtocol = "PrometheusText0.0.4"
+	OpenMetricsText0_0_1 ScrapeProtocol = "OpenMetricsText0.0.1"
+	OpenMetricsText1_0_0 ScrapeProtocol = "OpenMetricsText1.0.0"
+)
+
+// ServiceDiscoveryRole is the Kubernetes service discovery role used to
+// discover the endpoints of Services.
+// +kubebuilder:validation:Enum=Endpoints;EndpointSlice
+type ServiceDiscoveryRole string
+
+const (
+	EndpointsRole     ServiceDiscoveryRole = "Endpoints"
+	EndpointSliceRole ServiceDiscoveryRole = "EndpointSlice"
+)
+
 // QuerySpec defines the query command line flags when starting Prometheus.
 // +k8s:openapi-gen=true
 type QuerySpec struct {
@@ -547,6 +872,48 @@ type WebSpec struct {
 	// The prometheus web page title
 	PageTitle *string       `json:"pageTitle,omitempty"`
 	TLSConfig *WebTLSConfig `json:"tlsConfig,omitempty"`
+	// Defines HTTP parameters for the web server.
+	// It requires Prometheus >= v2.36.0.
+	HTTPConfig *WebHTTPConfig `json:"httpConfig,omitempty"`
+}
+
+// WebHTTPConfig defines HTTP parameters for the web server.
+// +k8s:openapi-gen=true
+type WebHTTPConfig struct {
+	// Enable HTTP/2 support. Note that HTTP/2 is only supported with TLS.
+	// When TLSConfig is not configured, HTTP/2 will be disabled.
+	HTTP2 *bool `json:"http2,omitempty"`
+	// List of headers that can be added to HTTP responses.
+	Headers *WebHTTPHeaders `json:"headers,omitempty"`
+}
+
+// WebHTTPHeaders defines the list of headers that can be added to HTTP responses.
+// +k8s:openapi-gen=true
+type WebHTTPHeaders struct {
+	// Set the Content-Security-Policy header to HTTP responses.
+	// Unset if blank.
+	ContentSecurityPolicy string `json:"contentSecurityPolicy,omitempty"`
+	// Set the X-Frame-Options header to HTTP responses.
+	// Unset if blank. Accepted values are deny and sameorigin.
+	// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/X-Frame-Options
+	// +kubebuilder:validation:Enum="";Deny;SameOrigin
+	XFrameOptions string `json:"xFrameOptions,omitempty"`
+	// Set the X-Content-Type-Options header to HTTP responses.
+	// Unset if blank. Accepted value is nosniff.
+	// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/X-Content-Type-Options
+	// +kubebuilder:validation:Enum="";NoSniff
+	XContentTypeOptions string `json:"xContentTypeOptions,omitempty"`
+	// Set the X-XSS-Protection header to all responses.
+	// Unset if blank.
+	// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/X-XSS-Protection
+	XXSSProtection string `json:"xXSSProtection,omitempty"`
+	// Set the Strict-Transport-Security header to HTTP responses.
+	// Unset if blank.
+	// Please make sure that you use this with care as this header might force
+	// browsers to load Prometheus and the other applications hosted on the same
+	// domain and subdomains over HTTPS.
+	// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Strict-Transport-Security
+	StrictTransportSecurity string `json:"strictTransportSecurity,omitempty"`
 }
 
 // WebTLSConfig defines the TLS parameters for HTTPS.
@@ -643,7 +1010,8 @@ type ThanosSpec struct {
 	// Deprecated: use 'image' instead
 	BaseImage *string `json:"baseImage,omitempty"`
 	// Resources defines the resource requirements for the Thanos sidecar.
-	// If not provided, no requests/limits will be set
+	// The requests and limits which aren't provided default to the values
+	// of the operator's `--thanos-sidecar-*` flags, if any.
 	Resources v1.ResourceRequirements `json:"resources,omitempty"`
 	// ObjectStorageConfig configures object storage in Thanos.
 	// Alternative to ObjectStorageConfigFile, and lower order priority.
@@ -661,7 +1029,10 @@ type ThanosSpec struct {
 	TracingConfigFile string `json:"tracingConfigFile,omitempty"`
 	// GRPCServerTLSConfig configures the gRPC server from which Thanos Querier reads
 	// recorded rule data.
-	// Note: Currently only the CAFile, CertFile, and KeyFile fields are supported.
+	// The certificate and the key can be read from files or from Secrets
+	// and ConfigMaps. When the CA is defined, the clients must present a
+	// certificate signed by it.
+	// The `insecureSkipVerify` and `serverName` fields are ignored.
 	// Maps to the '--grpc-server-tls-*' CLI args.
 	GRPCServerTLSConfig *TLSConfig `json:"grpcServerTlsConfig,omitempty"`
 	// LogLevel for Thanos sidecar to be configured with.
@@ -675,6 +1046,32 @@ type ThanosSpec struct {
 	// VolumeMounts allows configuration of additional VolumeMounts on the output StatefulSet definition.
 	// VolumeMounts specified will be appended to other VolumeMounts in the thanos-sidecar container.
 	VolumeMounts []v1.VolumeMount `json:"volumeMounts,omitempty"`
+	// Shipper configures the upload of the TSDB blocks to the object storage.
+	// +optional
+	Shipper *ThanosShipperSpec `json:"shipper,omitempty"`
+}
+
+// ThanosShipperSpec configures the upload of the TSDB blocks by the Thanos
+// sidecar.
+// +k8s:openapi-gen=true
+type ThanosShipperSpec struct {
+	// When true, the Thanos sidecar doesn't upload the blocks to the object
+	// storage even if the object storage configuration is defined. The
+	// sidecar still serves the queries and the local compaction of
+	// Prometheus remains enabled.
+	// +optional
+	DisableUpload bool `json:"disableUpload,omitempty"`
+	// When true, the Thanos sidecar also uploads the compacted blocks. It is
+	// useful to migrate the existing blocks to the object storage and should
+	// be disabled once done.
+	// Maps to the '--shipper.upload-compacted' CLI arg.
+	// +optional
+	UploadCompacted bool `json:"uploadCompacted,omitempty"`
+	// Number of goroutines used to upload the block files.
+	// Maps to the '--shipper.upload-concurrency' CLI arg.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	UploadConcurrency *int32 `json:"uploadConcurrency,omitempty"`
 }
 
 // RemoteWriteSpec defines the remote_write configuration for prometheus.
@@ -708,16 +1105,78 @@ type RemoteWriteSpec struct {
 	Authorization *Authorization `json:"authorization,omitempty"`
 	// Sigv4 allows to configures AWS's Signature Verification 4
 	Sigv4 *Sigv4 `json:"sigv4,omitempty"`
+	// AzureAD for the URL.
+	// Cannot be set at the same time as `basicAuth`, `oauth2`, `authorization`
+	// or `sigv4`.
+	// Only valid in Prometheus versions 2.45.0 and newer.
+	AzureAD *AzureAD `json:"azureAd,omitempty"`
 	// TLS Config to use for remote write.
 	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
 	// Optional ProxyURL
 	ProxyURL string `json:"proxyUrl,omitempty"`
+	// Proxy settings complementing the proxy URL.
+	ProxyConfig `json:",inline"`
+	// FollowRedirects defines whether the remote write requests follow HTTP 3xx redirects.
+	// Only valid in Prometheus versions 2.26.0 and newer.
+	FollowRedirects *bool `json:"followRedirects,omitempty"`
+	// EnableHttp2 defines whether to use HTTP2 for the remote write requests.
+	// Only valid in Prometheus versions 2.35.0 and newer.
+	EnableHttp2 *bool `json:"enableHttp2,omitempty"`
 	// QueueConfig allows tuning of the remote write queue parameters.
 	QueueConfig *QueueConfig `json:"queueConfig,omitempty"`
 	// MetadataConfig configures the sending of series metadata to remote storage.
 	MetadataConfig *MetadataConfig `json:"metadataConfig,omitempty"`
 }
 
+// RemoteWriteToThanosReceiveSpec configures the remote write to Thanos
+// Receive. The operator generates the remote write endpoint from the Receive
+// service.
+// +k8s:openapi-gen=true
+type RemoteWriteToThanosReceiveSpec struct {
+	// Reference to the Kubernetes service of Thanos Receive.
+	Service ThanosReceiveServiceReference `json:"service"`
+	// The tenant of the samples. When empty, Thanos Receive uses its default
+	// tenant.
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
+	// The HTTP header used to send the tenant. It should match the
+	// `--receive.tenant-header` flag of Thanos Receive. Defaults to
+	// `THANOS-TENANT`.
+	// +optional
+	TenantHeader string `json:"tenantHeader,omitempty"`
+	// TLS configuration to connect to Thanos Receive. The `https` scheme is
+	// used when defined.
+	// +optional
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+	// Labels added to the samples sent to Thanos Receive (for instance to
+	// identify the cluster). They take precedence over the external labels.
+	// +optional
+	ExternalLabels map[string]string `json:"externalLabels,omitempty"`
+	// When true, the retention of the local TSDB is reduced to 2 hours which
+	// is the minimum block duration. It can't be set together with
+	// `retention` and `retentionSize`. Ignored by PrometheusAgent which
+	// doesn't retain data locally.
+	// +optional
+	DisableLocalRetention bool `json:"disableLocalRetention,omitempty"`
+}
+
+// ThanosReceiveServiceReference references the Kubernetes service of Thanos
+// Receive.
+// +k8s:openapi-gen=true
+type ThanosReceiveServiceReference struct {
+	// Name of the service.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Namespace of the service. Defaults to the namespace of the object.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Port of the remote write endpoint. Defaults to 19291.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+}
+
 // QueueConfig allows the tuning of remote_write queue_config parameters. This object
 // is referenced in the RemoteWriteSpec object.
 // +k8s:openapi-gen=true
@@ -752,10 +1211,47 @@ type Sigv4 struct {
 	SecretKey *v1.SecretKeySelector `json:"secretKey,omitempty"`
 	// Profile is the named AWS profile used to authenticate.
 	Profile string `json:"profile,omitempty"`
-	// RoleArn is the named AWS profile used to authenticate.
+	// RoleArn is the ARN of the AWS role assumed to sign the requests.
 	RoleArn string `json:"roleArn,omitempty"`
 }
 
+// AzureAD defines the configuration for remote write's azuread parameters.
+// +k8s:openapi-gen=true
+type AzureAD struct {
+	// The Azure Cloud. Options are 'AzurePublic', 'AzureChina', or 'AzureGovernment'.
+	// +kubebuilder:validation:Enum=AzureChina;AzureGovernment;AzurePublic
+	Cloud *string `json:"cloud,omitempty"`
+	// ManagedIdentity defines the Azure User-assigned Managed identity.
+	// Cannot be set at the same time as `oauth`.
+	ManagedIdentity *ManagedIdentity `json:"managedIdentity,omitempty"`
+	// OAuth defines the OAuth client credentials used to authenticate.
+	// Cannot be set at the same time as `managedIdentity`.
+	// Only valid in Prometheus versions 2.48.0 and newer.
+	OAuth *AzureOAuth `json:"oauth,omitempty"`
+}
+
+// ManagedIdentity defines the Azure User-assigned Managed identity.
+// +k8s:openapi-gen=true
+type ManagedIdentity struct {
+	// The client id
+	// +kubebuilder:validation:MinLength=1
+	ClientID string `json:"clientId"`
+}
+
+// AzureOAuth defines the Azure OAuth client credentials.
+// +k8s:openapi-gen=true
+type AzureOAuth struct {
+	// The client id of the Azure Active Directory application.
+	// +kubebuilder:validation:MinLength=1
+	ClientID string `json:"clientId"`
+	// The secret containing the client secret of the Azure Active Directory
+	// application.
+	ClientSecret v1.SecretKeySelector `json:"clientSecret"`
+	// The tenant id of the Azure Active Directory application.
+	// +kubebuilder:validation:MinLength=1
+	TenantID string `json:"tenantId"`
+}
+
 // RemoteReadSpec defines the remote_read configuration for prometheus.
 // +k8s:openapi-gen=true
 type RemoteReadSpec struct {
@@ -773,6 +1269,10 @@ type RemoteReadSpec struct {
 	// Whether reads should be made for queries for time ranges that
 	// the local storage should have complete data for.
 	ReadRecent bool `json:"readRecent,omitempty"`
+	// Whether to use the external labels as selectors for the remote read
+	// endpoint. If not set, Prometheus uses its default value (true).
+	// Only valid in Prometheus versions 2.34.0 and newer.
+	FilterExternalLabels *bool `json:"filterExternalLabels,omitempty"`
 	// BasicAuth for the URL.
 	BasicAuth *BasicAuth `json:"basicAuth,omitempty"`
 	// OAuth2 for the URL. Only valid in Prometheus versions 2.27.0 and newer.
@@ -892,6 +1392,20 @@ type ServiceMonitorSpec struct {
 	Selector metav1.LabelSelector `json:"selector"`
 	// Selector to select which namespaces the Kubernetes Endpoints objects are discovered from.
 	NamespaceSelector NamespaceSelector `json:"namespaceSelector,omitempty"`
+	// Additional metadata attached to the discovered targets.
+	// Only valid in Prometheus versions 2.37.0 and newer.
+	AttachMetadata *AttachMetadata `json:"attachMetadata,omitempty"`
+	// The scrape class to apply. If not set, the default scrape class of the
+	// Prometheus object is applied if any.
+	// +kubebuilder:validation:MinLength=1
+	ScrapeClassName *string `json:"scrapeClass,omitempty"`
+	// The protocols to negotiate during a scrape, in order of preference.
+	// If unset, the value of the Prometheus object is used.
+	// The `scrapeNativeHistograms` field of an endpoint takes precedence
+	// over this field.
+	// Only valid in Prometheus versions 2.49.0 and newer.
+	// +listType=set
+	ScrapeProtocols []ScrapeProtocol `json:"scrapeProtocols,omitempty"`
 	// SampleLimit defines per-scrape limit on number of scraped samples that will be accepted.
 	SampleLimit uint64 `json:"sampleLimit,omitempty"`
 	// TargetLimit defines a limit on the number of scraped targets that will be accepted.
@@ -905,6 +1419,10 @@ type ServiceMonitorSpec struct {
 	// Per-scrape limit on length of labels value that will be accepted for a sample.
 	// Only valid in Prometheus versions 2.27.0 and newer.
 	LabelValueLengthLimit uint64 `json:"labelValueLengthLimit,omitempty"`
+	// Per-scrape limit on the number of targets dropped by relabeling that
+	// will be kept in memory. 0 means no limit.
+	// Only valid in Prometheus versions 2.47.0 and newer.
+	KeepDroppedTargets uint64 `json:"keepDroppedTargets,omitempty"`
 }
 
 // Endpoint defines a scrapeable endpoint serving Prometheus metrics.
@@ -952,6 +1470,25 @@ type Endpoint struct {
 	RelabelConfigs []*RelabelConfig `json:"relabelings,omitempty"`
 	// ProxyURL eg http://proxyserver:2195 Directs scrapes to proxy through this endpoint.
 	ProxyURL *string `json:"proxyUrl,omitempty"`
+	// Proxy settings complementing the proxy URL.
+	ProxyConfig `json:",inline"`
+	// FollowRedirects defines whether the scrape requests follow HTTP 3xx redirects.
+	// Only valid in Prometheus versions 2.26.0 and newer.
+	FollowRedirects *bool `json:"followRedirects,omitempty"`
+	// EnableHttp2 defines whether to use HTTP2 for the scrape requests.
+	// Only valid in Prometheus versions 2.35.0 and newer.
+	EnableHttp2 *bool `json:"enableHttp2,omitempty"`
+	// ScrapeNativeHistograms defines whether Prometheus negotiates the protobuf
+	// exposition format with the endpoint, which is required to scrape native
+	// histograms. If unset, Prometheus uses its default.
+	// Native histograms are only ingested when the `enableNativeHistograms`
+	// field of the Prometheus resource is true.
+	// Only valid in Prometheus versions 2.49.0 and newer.
+	ScrapeNativeHistograms *bool `json:"scrapeNativeHistograms,omitempty"`
+	// NativeHistogramBucketLimit defines a per-scrape limit on the number of
+	// buckets of a single native histogram.
+	// Only valid in Prometheus versions 2.45.0 and newer.
+	NativeHistogramBucketLimit uint64 `json:"nativeHistogramBucketLimit,omitempty"`
 }
 
 // PodMonitor defines monitoring for a set of pods.
@@ -978,6 +1515,20 @@ type PodMonitorSpec struct {
 	Selector metav1.LabelSelector `json:"selector"`
 	// Selector to select which namespaces the Endpoints objects are discovered from.
 	NamespaceSelector NamespaceSelector `json:"namespaceSelector,omitempty"`
+	// Additional metadata attached to the discovered targets.
+	// Only valid in Prometheus versions 2.35.0 and newer.
+	AttachMetadata *AttachMetadata `json:"attachMetadata,omitempty"`
+	// The scrape class to apply. If not set, the default scrape class of the
+	// Prometheus object is applied if any.
+	// +kubebuilder:validation:MinLength=1
+	ScrapeClassName *string `json:"scrapeClass,omitempty"`
+	// The protocols to negotiate during a scrape, in order of preference.
+	// If unset, the value of the Prometheus object is used.
+	// The `scrapeNativeHistograms` field of an endpoint takes precedence
+	// over this field.
+	// Only valid in Prometheus versions 2.49.0 and newer.
+	// +listType=set
+	ScrapeProtocols []ScrapeProtocol `json:"scrapeProtocols,omitempty"`
 	// SampleLimit defines per-scrape limit on number of scraped samples that will be accepted.
 	SampleLimit uint64 `json:"sampleLimit,omitempty"`
 	// TargetLimit defines a limit on the number of scraped targets that will be accepted.
@@ -991,6 +1542,10 @@ type PodMonitorSpec struct {
 	// Per-scrape limit on length of labels value that will be accepted for a sample.
 	// Only valid in Prometheus versions 2.27.0 and newer.
 	LabelValueLengthLimit uint64 `json:"labelValueLengthLimit,omitempty"`
+	// Per-scrape limit on the number of targets dropped by relabeling that
+	// will be kept in memory. 0 means no limit.
+	// Only valid in Prometheus versions 2.47.0 and newer.
+	KeepDroppedTargets uint64 `json:"keepDroppedTargets,omitempty"`
 }
 
 // PodMetricsEndpoint defines a scrapeable endpoint of a Kubernetes Pod serving Prometheus metrics.
@@ -1036,6 +1591,31 @@ type PodMetricsEndpoint struct {
 	RelabelConfigs []*RelabelConfig `json:"relabelings,omitempty"`
 	// ProxyURL eg http://proxyserver:2195 Directs scrapes to proxy through this endpoint.
 	ProxyURL *string `json:"proxyUrl,omitempty"`
+	// Proxy settings complementing the proxy URL.
+	ProxyConfig `json:",inline"`
+	// FollowRedirects defines whether the scrape requests follow HTTP 3xx redirects.
+	// Only valid in Prometheus versions 2.26.0 and newer.
+	FollowRedirects *bool `json:"followRedirects,omitempty"`
+	// EnableHttp2 defines whether to use HTTP2 for the scrape requests.
+	// Only valid in Prometheus versions 2.35.0 and newer.
+	EnableHttp2 *bool `json:"enableHttp2,omitempty"`
+	// FilterRunning defines whether the pods which aren't running (in the
+	// `Failed` or `Succeeded` phase) are dropped from the discovered
+	// targets. Disable it to scrape the metrics of completed pods such as
+	// the ones of finished Jobs.
+	// Defaults to true.
+	FilterRunning *bool `json:"filterRunning,omitempty"`
+	// ScrapeNativeHistograms defines whether Prometheus negotiates the protobuf
+	// exposition format with the endpoint, which is required to scrape native
+	// histograms. If unset, Prometheus uses its default.
+	// Native histograms are only ingested when the `enableNativeHistograms`
+	// field of the Prometheus resource is true.
+	// Only valid in Prometheus versions 2.49.0 and newer.
+	ScrapeNativeHistograms *bool `json:"scrapeNativeHistograms,omitempty"`
+	// NativeHistogramBucketLimit defines a per-scrape limit on the number of
+	// buckets of a single native histogram.
+	// Only valid in Prometheus versions 2.45.0 and newer.
+	NativeHistogramBucketLimit uint64 `json:"nativeHistogramBucketLimit,omitempty"`
 }
 
 // PodMetricsEndpointTLSConfig specifies TLS configuration parameters.
@@ -1089,6 +1669,15 @@ type ProbeSpec struct {
 	MetricRelabelConfigs []*RelabelConfig `json:"metricRelabelings,omitempty"`
 	// Authorization section for this endpoint
 	Authorization *SafeAuthorization `json:"authorization,omitempty"`
+	// The scrape class to apply. If not set, the default scrape class of the
+	// Prometheus object is applied if any.
+	// +kubebuilder:validation:MinLength=1
+	ScrapeClassName *string `json:"scrapeClass,omitempty"`
+	// The protocols to negotiate during a scrape, in order of preference.
+	// If unset, the value of the Prometheus object is used.
+	// Only valid in Prometheus versions 2.49.0 and newer.
+	// +listType=set
+	ScrapeProtocols []ScrapeProtocol `json:"scrapeProtocols,omitempty"`
 	// SampleLimit defines per-scrape limit on number of scraped samples that will be accepted.
 	SampleLimit uint64 `json:"sampleLimit,omitempty"`
 	// TargetLimit defines a limit on the number of scraped targets that will be accepted.
@@ -1102,6 +1691,10 @@ type ProbeSpec struct {
 	// Per-scrape limit on length of labels value that will be accepted for a sample.
 	// Only valid in Prometheus versions 2.27.0 and newer.
 	LabelValueLengthLimit uint64 `json:"labelValueLengthLimit,omitempty"`
+	// Per-scrape limit on the number of targets dropped by relabeling that
+	// will be kept in memory. 0 means no limit.
+	// Only valid in Prometheus versions 2.47.0 and newer.
+	KeepDroppedTargets uint64 `json:"keepDroppedTargets,omitempty"`
 }
 
 // ProbeTargets defines a set of static and dynamically discovered targets for the prober.
@@ -1119,6 +1712,13 @@ type ProbeTargets struct {
 type ProbeTargetStaticConfig struct {
 	// Targets is a list of URLs to probe using the configured prober.
 	Targets []string `json:"static,omitempty"`
+	// Additional targets read from a key of a ConfigMap or a Secret in the
+	// namespace of the Probe. The value contains one target per line, empty
+	// lines and lines starting with `#` are ignored.
+	// The configuration is regenerated as soon as a ConfigMap or Secret
+	// labeled with `monitoring.coreos.com/probe-targets: "true"` changes,
+	// other changes are picked up at the next reconciliation.
+	StaticFrom *SecretOrConfigMap `json:"staticFrom,omitempty"`
 	// Labels assigned to all metrics scraped from the targets.
 	Labels map[string]string `json:"labels,omitempty"`
 	// RelabelConfigs to apply to samples before ingestion.
@@ -1151,6 +1751,38 @@ type ProberSpec struct {
 	Path string `json:"path,omitempty"`
 	// Optional ProxyURL.
 	ProxyURL string `json:"proxyUrl,omitempty"`
+	// Proxy settings complementing the proxy URL.
+	ProxyConfig `json:",inline"`
+}
+
+// AttachMetadata defines the additional metadata attached to the targets
+// discovered by ServiceMonitors and PodMonitors.
+// +k8s:openapi-gen=true
+type AttachMetadata struct {
+	// When true, the labels and annotations of the node running the target
+	// are attached to the target (`__meta_kubernetes_node_*` labels).
+	// Prometheus needs the `get` permission on the Node objects.
+	Node bool `json:"node,omitempty"`
+}
+
+// ProxyConfig defines the HTTP proxy settings which complement the proxy URL.
+// +k8s:openapi-gen=true
+type ProxyConfig struct {
+	// Comma-separated list of IP addresses, CIDR notations and domain names
+	// which are excluded from proxying. IP addresses and domain names can
+	// contain port numbers.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	NoProxy *string `json:"noProxy,omitempty"`
+	// Whether to use the proxy configuration defined by the HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables. It can't be used
+	// together with the proxy URL.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	ProxyFromEnvironment *bool `json:"proxyFromEnvironment,omitempty"`
+	// Headers sent to the proxy during CONNECT requests. The values are read
+	// from secrets in the namespace of the object.
+	// Only valid in Prometheus versions 2.43.0 and newer.
+	// +mapType=atomic
+	ProxyConnectHeader map[string][]v1.SecretKeySelector `json:"proxyConnectHeader,omitempty"`
 }
 
 // OAuth2 allows an endpoint to authenticate with OAuth2.
@@ -1432,7 +2064,10 @@ type Rule struct {
 // +kubebuilder:resource:categories="prometheus-operator"
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.version",description="The version of Alertmanager"
 // +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas",description="The desired replicas number of Alertmanagers"
+// +kubebuilder:printcolumn:name="Reconciled",type="string",JSONPath=".status.conditions[?(@.type == 'Reconciled')].status",description="Whether the resource was reconciled successfully"
+// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type == 'Available')].status",description="Whether the resource is available"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
 type Alertmanager struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -1485,6 +2120,13 @@ type AlertmanagerSpec struct {
 	// object, which shall be mounted into the Alertmanager Pods.
 	// The ConfigMaps are mounted into /etc/alertmanager/configmaps/<configmap-name>.
 	ConfigMaps []string `json:"configMaps,omitempty"`
+	// Templates is a list of ConfigMap or Secret keys in the same namespace as
+	// the Alertmanager object, which contain notification templates.
+	// The templates are mounted into /etc/alertmanager/templates/<key> and
+	// added to the templates of the generated Alertmanager configuration.
+	// Alertmanager is reloaded when the templates change.
+	// The keys must be unique.
+	Templates []SecretOrConfigMap `json:"templates,omitempty"`
 	// ConfigSecret is the name of a Kubernetes Secret in the same namespace as the
 	// Alertmanager object, which contains configuration for this Alertmanager
 	// instance. Defaults to 'alertmanager-<alertmanager-name>'
@@ -1534,6 +2176,15 @@ type AlertmanagerSpec struct {
 	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
 	// If specified, the pod's topology spread constraints.
 	TopologySpreadConstraints []v1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// Pods' hostAliases configuration
+	// +listType=map
+	// +listMapKey=ip
+	HostAliases []HostAlias `json:"hostAliases,omitempty"`
+	// Defines the DNS policy for the pods.
+	// +kubebuilder:validation:Enum=ClusterFirstWithHostNet;ClusterFirst;Default;None
+	DNSPolicy v1.DNSPolicy `json:"dnsPolicy,omitempty"`
+	// Defines the DNS configuration for the pods.
+	DNSConfig *v1.PodDNSConfig `json:"dnsConfig,omitempty"`
 	// SecurityContext holds pod-level security attributes and common container settings.
 	// This defaults to the default PodSecurityContext.
 	SecurityContext *v1.PodSecurityContext `json:"securityContext,omitempty"`
@@ -1544,6 +2195,10 @@ type AlertmanagerSpec struct {
 	// does not bind against the Pod IP. Note this is only for the Alertmanager
 	// UI, not the gossip communication.
 	ListenLocal bool `json:"listenLocal,omitempty"`
+	// Defines the web command line flags when starting Alertmanager.
+	// It requires Alertmanager >= 0.22.0.
+	// +optional
+	Web *AlertmanagerWebSpec `json:"web,omitempty"`
 	// Containers allows injecting additional containers. This is meant to
 	// allow adding an authentication proxy to an Alertmanager pod.
 	// Containers described here modify an operator generated container if they
@@ -1574,25 +2229,196 @@ type AlertmanagerSpec struct {
 	ClusterPushpullInterval string `json:"clusterPushpullInterval,omitempty"`
 	// Timeout for cluster peering.
 	ClusterPeerTimeout string `json:"clusterPeerTimeout,omitempty"`
+	// Configures mutual TLS for the gossip traffic between the Alertmanager
+	// peers of the cluster.
+	// It requires Alertmanager >= 0.24.0.
+	// +optional
+	ClusterTLSConfig *ClusterTLSConfig `json:"clusterTLSConfig,omitempty"`
 	// Port name used for the pods and governing service.
 	// This defaults to web
 	PortName string `json:"portName,omitempty"`
 	// ForceEnableClusterMode ensures Alertmanager does not deactivate the cluster mode when running with a single replica.
 	// Use case is e.g. spanning an Alertmanager cluster across Kubernetes clusters with a single replica in each.
 	ForceEnableClusterMode bool `json:"forceEnableClusterMode,omitempty"`
+	// ClusterPeering configures the peering of the Alertmanager pods with
+	// Alertmanager instances running outside of the Kubernetes cluster (e.g.
+	// in other Kubernetes clusters) to form a single Alertmanager cluster.
+	// When peers are defined, the cluster mode is enabled even with a single
+	// replica.
+	// +optional
+	ClusterPeering *AlertmanagerClusterPeering `json:"clusterPeering,omitempty"`
 	// AlertmanagerConfigs to be selected for to merge and configure Alertmanager with.
 	AlertmanagerConfigSelector *metav1.LabelSelector `json:"alertmanagerConfigSelector,omitempty"`
 	// Namespaces to be selected for AlertmanagerConfig discovery. If nil, only
 	// check own namespace.
 	AlertmanagerConfigNamespaceSelector *metav1.LabelSelector `json:"alertmanagerConfigNamespaceSelector,omitempty"`
+	// AlertmanagerConfigMatcherStrategy defines how the AlertmanagerConfig
+	// resources selected by AlertmanagerConfigSelector match the alerts.
+	// +optional
+	AlertmanagerConfigMatcherStrategy AlertmanagerConfigMatcherStrategy `json:"alertmanagerConfigMatcherStrategy,omitempty"`
+	// AlertmanagerConfiguration specifies the AlertmanagerConfig resource
+	// which defines the top-level route, receivers and inhibition rules of
+	// the Alertmanager configuration. The routes of the AlertmanagerConfig
+	// resources selected by AlertmanagerConfigSelector are merged underneath
+	// the top-level route.
+	// If defined, it takes precedence over the ConfigSecret field.
+	// +optional
+	AlertmanagerConfiguration *AlertmanagerConfiguration `json:"alertmanagerConfiguration,omitempty"`
 	// Minimum number of seconds for which a newly created pod should be ready
 	// without any of its container crashing for it to be considered available.
 	// Defaults to 0 (pod will be considered available as soon as it is ready)
-	// This is an alpha field and requires enabling StatefulSetMinReadySeconds feature gate.
+	// This is an alpha field from kubernetes 1.22 until 1.24 which requires
+	// enabling the StatefulSetMinReadySeconds feature gate.
 	// +optional
 	MinReadySeconds *uint32 `json:"minReadySeconds,omitempty"`
 }
 
+// AlertmanagerConfigMatcherStrategyType is the strategy applied by the
+// operator to restrict the alerts matched by the AlertmanagerConfig resources.
+type AlertmanagerConfigMatcherStrategyType string
+
+const (
+	// OnNamespaceConfigMatcherStrategyType injects a `namespace` matcher
+	// equal to the namespace of the AlertmanagerConfig resource into its
+	// top-level route and inhibition rules.
+	OnNamespaceConfigMatcherStrategyType AlertmanagerConfigMatcherStrategyType = "OnNamespace"
+	// NoneConfigMatcherStrategyType doesn't inject any matcher besides the
+	// ones defined in the AlertmanagerConfig resource.
+	NoneConfigMatcherStrategyType AlertmanagerConfigMatcherStrategyType = "None"
+)
+
+// AlertmanagerConfigMatcherStrategy defines the strategy used by
+// AlertmanagerConfig objects to match alerts.
+// +k8s:openapi-gen=true
+type AlertmanagerConfigMatcherStrategy struct {
+	// If set to `OnNamespace`, the operator injects a label matcher matching
+	// the namespace of the AlertmanagerConfig object for all its routes and
+	// inhibition rules.
+	// `None` will not add any additional matchers other than the ones
+	// specified in the AlertmanagerConfig. It allows the AlertmanagerConfig
+	// resources to handle alerts from all namespaces, hence it should only
+	// be used when AlertmanagerConfigNamespaceSelector selects trusted
+	// namespaces.
+	// Default is `OnNamespace`.
+	// +kubebuilder:validation:Enum="OnNamespace";"None"
+	// +kubebuilder:default:="OnNamespace"
+	Type AlertmanagerConfigMatcherStrategyType `json:"type,omitempty"`
+}
+
+// ClusterTLSConfig defines the mutual TLS configuration of the Alertmanager
+// cluster (gossip) protocol.
+// +k8s:openapi-gen=true
+type ClusterTLSConfig struct {
+	// Server-side configuration for mutual TLS.
+	ServerTLS WebTLSConfig `json:"server"`
+	// Client-side configuration for mutual TLS.
+	ClientTLS SafeTLSConfig `json:"client"`
+}
+
+// AlertmanagerClusterPeering defines how the Alertmanager pods mesh with the
+// Alertmanager instances running in other clusters.
+// +k8s:openapi-gen=true
+type AlertmanagerClusterPeering struct {
+	// Alertmanager instances running in other clusters.
+	// +optional
+	Peers []AlertmanagerPeer `json:"peers,omitempty"`
+	// Address advertised by the Alertmanager pods to the other peers, as
+	// `<host>:<port>`. It must be reachable from the other clusters.
+	// The `$(POD_NAME)` and `$(POD_IP)` variables are replaced by the name
+	// and the IP address of the pod, e.g.
+	// `$(POD_NAME).alertmanager.example.com:9094` when every pod is exposed
+	// under its own DNS name.
+	// If defined, it takes precedence over the ClusterAdvertiseAddress field.
+	// +optional
+	AdvertiseAddress string `json:"advertiseAddress,omitempty"`
+	// Total number of Alertmanager instances in the cluster, including the
+	// replicas of this object and the peers from the other clusters. If
+	// defined, the operator rejects the configuration when the replicas and
+	// the peers don't add up to this number, which would result in
+	// partitioned Alertmanager clusters sending duplicate notifications.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ExpectedClusterSize *int32 `json:"expectedClusterSize,omitempty"`
+}
+
+// AlertmanagerPeer is an Alertmanager instance (or a group of instances)
+// running outside of the Kubernetes cluster.
+// +k8s:openapi-gen=true
+type AlertmanagerPeer struct {
+	// Address of the peer as `<host>[:<port>]`. The port defaults to 9094.
+	// When the host is a DNS name, Alertmanager resolves it to all its
+	// addresses and refreshes the resolution periodically, which allows the
+	// discovery of the instances behind a headless service exported from
+	// another cluster.
+	// +kubebuilder:validation:MinLength=1
+	Address string `json:"address"`
+	// Number of Alertmanager instances reachable at the address, e.g. the
+	// replicas behind a DNS name. It is only used to verify the expected
+	// cluster size. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// AlertmanagerConfiguration defines the global Alertmanager configuration
+// which is generated from an AlertmanagerConfig resource.
+// +k8s:openapi-gen=true
+type AlertmanagerConfiguration struct {
+	// Name of the AlertmanagerConfig resource in the same namespace as the
+	// Alertmanager object. Unlike for the AlertmanagerConfig resources
+	// selected by AlertmanagerConfigSelector, the operator doesn't enforce a
+	// `namespace` matcher on its route and inhibition rules.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Global parameters of the Alertmanager configuration.
+	// +optional
+	Global *AlertmanagerGlobalConfig `json:"global,omitempty"`
+}
+
+// AlertmanagerGlobalConfig configures the global parameters of the
+// Alertmanager configuration.
+// +k8s:openapi-gen=true
+type AlertmanagerGlobalConfig struct {
+	// Time after which an alert is declared resolved if it hasn't been
+	// updated.
+	// +optional
+	ResolveTimeout string `json:"resolveTimeout,omitempty"`
+}
+
+// AlertmanagerWebSpec defines the web command line flags when starting
+// Alertmanager.
+// +k8s:openapi-gen=true
+type AlertmanagerWebSpec struct {
+	// Defines the TLS parameters for HTTPS.
+	TLSConfig *WebTLSConfig `json:"tlsConfig,omitempty"`
+	// Defines HTTP parameters for the web server.
+	// It requires Alertmanager >= 0.24.0.
+	HTTPConfig *WebHTTPConfig `json:"httpConfig,omitempty"`
+	// Users allowed to access the Alertmanager API and UI with basic
+	// authentication. When defined, the liveness and readiness probes of the
+	// Alertmanager container check the TCP port instead of the HTTP endpoints.
+	// +listType=map
+	// +listMapKey=username
+	BasicAuthUsers []WebBasicAuthUser `json:"basicAuthUsers,omitempty"`
+	// Credentials used by the config-reloader sidecar to trigger the reload of
+	// the configuration. It is required when basicAuthUsers is defined and
+	// the username must match one of the users.
+	ReloaderBasicAuth *BasicAuth `json:"reloaderBasicAuth,omitempty"`
+}
+
+// WebBasicAuthUser defines a user allowed to access the web server with
+// basic authentication.
+// +k8s:openapi-gen=true
+type WebBasicAuthUser struct {
+	// Name of the user.
+	// +kubebuilder:validation:MinLength=1
+	Username string `json:"username"`
+	// The secret's key that contains the bcrypt hash of the user's password.
+	// The secret needs to be in the same namespace as the Alertmanager
+	// object.
+	PasswordHash v1.SecretKeySelector `json:"passwordHash"`
+}
+
 // AlertmanagerList is a list of Alertmanagers.
 // +k8s:openapi-gen=true
 type AlertmanagerList struct {
@@ -1633,6 +2459,16 @@ type AlertmanagerStatus struct {
 	AvailableReplicas int32 `json:"availableReplicas"`
 	// Total number of unavailable pods targeted by this Alertmanager cluster.
 	UnavailableReplicas int32 `json:"unavailableReplicas"`
+	// The current state of the Alertmanager object.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Number of AlertmanagerConfig resources selected by the Alertmanager
+	// object and merged into the generated configuration. The rejected
+	// resources are reported by the `RejectedResources` condition.
+	// +optional
+	SelectedAlertmanagerConfigs int32 `json:"selectedAlertmanagerConfigs,omitempty"`
 }
 
 // NamespaceSelector is a selector for selecting either all namespaces or a
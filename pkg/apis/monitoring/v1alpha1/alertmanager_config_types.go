@@ -45,6 +45,7 @@ var (
 // +genclient
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:categories="prometheus-operator"
+// +kubebuilder:storageversion
 type AlertmanagerConfig struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -79,6 +80,10 @@ type AlertmanagerConfigSpec struct {
 	// the resource’s namespace.
 	// +optional
 	InhibitRules []InhibitRule `json:"inhibitRules,omitempty"`
+	// List of time intervals that can be referenced by the routes to mute or
+	// activate notifications.
+	// +optional
+	MuteTimeIntervals []MuteTimeInterval `json:"muteTimeIntervals,omitempty"`
 }
 
 // Route defines a node in the routing tree.
@@ -125,6 +130,15 @@ type Route struct {
 	// an alternative type to circumvent the limitation. The downside is that
 	// the Kube API can't validate the data beyond the fact that it is a valid
 	// JSON representation.
+
+	// Names of the time intervals during which the route should be muted. They
+	// must be defined in the `muteTimeIntervals` field.
+	// +optional
+	MuteTimeIntervals []string `json:"muteTimeIntervals,omitempty"`
+	// Names of the time intervals during which the route should be active.
+	// They must be defined in the `muteTimeIntervals` field.
+	// +optional
+	ActiveTimeIntervals []string `json:"activeTimeIntervals,omitempty"`
 }
 
 // ChildRoutes extracts the child routes.
@@ -145,6 +159,15 @@ type Receiver struct {
 	// Name of the receiver. Must be unique across all items from the list.
 	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name"`
+	// Namespace of the Secrets and ConfigMaps referenced by the receiver's
+	// configurations. Defaults to the namespace of the AlertmanagerConfig
+	// resource.
+	// Referencing another namespace requires the namespace to be allowed by
+	// the --alertmanager-config-secret-namespaces flag of the operator. The
+	// admission webhook verifies that the user creating or updating the
+	// resource can read the Secrets and ConfigMaps of the namespace.
+	// +optional
+	SecretNamespace string `json:"secretNamespace,omitempty"`
 	// List of OpsGenie configurations.
 	OpsGenieConfigs []OpsGenieConfig `json:"opsgenieConfigs,omitempty"`
 	// List of PagerDuty configurations.
@@ -161,6 +184,25 @@ type Receiver struct {
 	VictorOpsConfigs []VictorOpsConfig `json:"victoropsConfigs,omitempty"`
 	// List of Pushover configurations.
 	PushoverConfigs []PushoverConfig `json:"pushoverConfigs,omitempty"`
+	// List of Telegram configurations.
+	// It requires Alertmanager >= 0.24.0.
+	TelegramConfigs []TelegramConfig `json:"telegramConfigs,omitempty"`
+	// List of Discord configurations.
+	// It requires Alertmanager >= 0.25.0.
+	DiscordConfigs []DiscordConfig `json:"discordConfigs,omitempty"`
+	// List of Microsoft Teams configurations.
+	// It requires Alertmanager >= 0.26.0.
+	MSTeamsConfigs []MSTeamsConfig `json:"msteamsConfigs,omitempty"`
+	// List of Microsoft Teams configurations using the Workflows (Power
+	// Automate) webhooks.
+	// It requires Alertmanager >= 0.28.0.
+	MSTeamsV2Configs []MSTeamsV2Config `json:"msteamsv2Configs,omitempty"`
+	// List of SNS configurations.
+	// It requires Alertmanager >= 0.23.0.
+	SNSConfigs []SNSConfig `json:"snsConfigs,omitempty"`
+	// List of Webex configurations.
+	// It requires Alertmanager >= 0.25.0.
+	WebexConfigs []WebexConfig `json:"webexConfigs,omitempty"`
 }
 
 // PagerDutyConfig configures notifications via PagerDuty.
@@ -680,6 +722,171 @@ type PushoverConfig struct {
 	HTTPConfig *HTTPConfig `json:"httpConfig,omitempty"`
 }
 
+// TelegramConfig configures notifications via Telegram.
+// See https://prometheus.io/docs/alerting/latest/configuration/#telegram_config
+type TelegramConfig struct {
+	// Whether or not to notify about resolved alerts.
+	// +optional
+	SendResolved *bool `json:"sendResolved,omitempty"`
+	// The Telegram API URL (e.g. `https://api.telegram.org`).
+	// If not specified, the default API URL will be used.
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+	// The secret's key that contains the Telegram bot token.
+	// The secret needs to be in the same namespace as the AlertmanagerConfig
+	// object and accessible by the Prometheus Operator.
+	BotToken *v1.SecretKeySelector `json:"botToken"`
+	// The Telegram chat ID.
+	ChatID int64 `json:"chatID"`
+	// Message template.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Disable the Telegram notifications.
+	// +optional
+	DisableNotifications *bool `json:"disableNotifications,omitempty"`
+	// Parse mode for the Telegram message.
+	// +kubebuilder:validation:Enum=MarkdownV2;Markdown;HTML
+	// +optional
+	ParseMode string `json:"parseMode,omitempty"`
+	// HTTP client configuration.
+	// +optional
+	HTTPConfig *HTTPConfig `json:"httpConfig,omitempty"`
+}
+
+// DiscordConfig configures notifications via Discord.
+// See https://prometheus.io/docs/alerting/latest/configuration/#discord_config
+type DiscordConfig struct {
+	// Whether or not to notify about resolved alerts.
+	// +optional
+	SendResolved *bool `json:"sendResolved,omitempty"`
+	// The secret's key that contains the Discord webhook URL.
+	// The secret needs to be in the same namespace as the AlertmanagerConfig
+	// object and accessible by the Prometheus Operator.
+	APIURL *v1.SecretKeySelector `json:"apiURL"`
+	// The template of the message's title.
+	// +optional
+	Title string `json:"title,omitempty"`
+	// The template of the message's body.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// HTTP client configuration.
+	// +optional
+	HTTPConfig *HTTPConfig `json:"httpConfig,omitempty"`
+}
+
+// MSTeamsConfig configures notifications via Microsoft Teams.
+// See https://prometheus.io/docs/alerting/latest/configuration/#msteams_config
+type MSTeamsConfig struct {
+	// Whether or not to notify about resolved alerts.
+	// +optional
+	SendResolved *bool `json:"sendResolved,omitempty"`
+	// The secret's key that contains the incoming webhook URL.
+	// The secret needs to be in the same namespace as the AlertmanagerConfig
+	// object and accessible by the Prometheus Operator.
+	WebhookURL *v1.SecretKeySelector `json:"webhookUrl"`
+	// The template of the message's title.
+	// +optional
+	Title string `json:"title,omitempty"`
+	// The template of the message's summary.
+	// It requires Alertmanager >= 0.27.0.
+	// +optional
+	Summary string `json:"summary,omitempty"`
+	// The template of the message's body.
+	// +optional
+	Text string `json:"text,omitempty"`
+	// HTTP client configuration.
+	// +optional
+	HTTPConfig *HTTPConfig `json:"httpConfig,omitempty"`
+}
+
+// MSTeamsV2Config configures notifications via Microsoft Teams using the
+// Workflows (Power Automate) webhooks.
+// See https://prometheus.io/docs/alerting/latest/configuration/#msteamsv2_config
+type MSTeamsV2Config struct {
+	// Whether or not to notify about resolved alerts.
+	// +optional
+	SendResolved *bool `json:"sendResolved,omitempty"`
+	// The secret's key that contains the Workflows webhook URL.
+	// The secret needs to be in the same namespace as the AlertmanagerConfig
+	// object and accessible by the Prometheus Operator.
+	WebhookURL *v1.SecretKeySelector `json:"webhookUrl"`
+	// The template of the message's title.
+	// +optional
+	Title string `json:"title,omitempty"`
+	// The template of the message's body.
+	// +optional
+	Text string `json:"text,omitempty"`
+	// HTTP client configuration.
+	// +optional
+	HTTPConfig *HTTPConfig `json:"httpConfig,omitempty"`
+}
+
+// SNSConfig configures notifications via AWS SNS.
+// See https://prometheus.io/docs/alerting/latest/configuration/#sns_config
+// Exactly one of `topicARN`, `phoneNumber` and `targetARN` must be defined.
+type SNSConfig struct {
+	// Whether or not to notify about resolved alerts.
+	// +optional
+	SendResolved *bool `json:"sendResolved,omitempty"`
+	// The SNS API URL i.e. https://sns.us-east-2.amazonaws.com.
+	// If not specified, the SNS API URL from the SNS SDK will be used.
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+	// Configures AWS's Signature Verification 4 signing process to sign requests.
+	// +optional
+	Sigv4 *monitoringv1.Sigv4 `json:"sigv4,omitempty"`
+	// SNS topic ARN, i.e. arn:aws:sns:us-east-2:698519295917:My-Topic.
+	// +optional
+	TopicARN string `json:"topicARN,omitempty"`
+	// Phone number if message is delivered via SMS in E.164 format.
+	// +optional
+	PhoneNumber string `json:"phoneNumber,omitempty"`
+	// The mobile platform endpoint ARN if message is delivered via mobile
+	// notifications.
+	// +optional
+	TargetARN string `json:"targetARN,omitempty"`
+	// Subject line when the message is delivered to email endpoints.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+	// The message content of the SNS notification.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// SNS message attributes.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// HTTP client configuration.
+	// +optional
+	HTTPConfig *HTTPConfig `json:"httpConfig,omitempty"`
+}
+
+// WebexConfig configures notifications via Webex.
+// See https://prometheus.io/docs/alerting/latest/configuration/#webex_config
+type WebexConfig struct {
+	// Whether or not to notify about resolved alerts.
+	// +optional
+	SendResolved *bool `json:"sendResolved,omitempty"`
+	// The Webex Teams API URL (e.g. `https://webexapis.com/v1/messages`).
+	// If not specified, the default API URL will be used.
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+	// The secret's key that contains the Webex bot token used to authenticate
+	// the requests.
+	// The secret needs to be in the same namespace as the AlertmanagerConfig
+	// object and accessible by the Prometheus Operator.
+	BotToken *v1.SecretKeySelector `json:"botToken"`
+	// ID of the Webex Teams room where to send the messages.
+	// +kubebuilder:validation:MinLength=1
+	RoomID string `json:"roomID"`
+	// Message template.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// HTTP client configuration.
+	// The authorization is configured from the bot token and can't be
+	// defined here.
+	// +optional
+	HTTPConfig *HTTPConfig `json:"httpConfig,omitempty"`
+}
+
 // InhibitRule defines an inhibition rule that allows to mute alerts when other
 // alerts are already firing.
 // See https://prometheus.io/docs/alerting/latest/configuration/#inhibit_rule
@@ -694,6 +901,15 @@ type InhibitRule struct {
 	// Labels that must have an equal value in the source and target alert for
 	// the inhibition to take effect.
 	Equal []string `json:"equal,omitempty"`
+	// If true, the operator doesn't enforce that the source and target
+	// alerts match the resource’s namespace, allowing the rule to inhibit
+	// alerts from all namespaces (e.g. a node being down inhibits all the
+	// alerts of the node).
+	// The namespace of the resource must be allowed by the
+	// `--alertmanager-config-cross-namespace-inhibition-namespaces` operator
+	// flag, otherwise the resource is rejected.
+	// +optional
+	CrossNamespace bool `json:"crossNamespace,omitempty"`
 }
 
 // KeyValue defines a (key, value) tuple.
@@ -718,6 +934,85 @@ type Matcher struct {
 	Regex bool `json:"regex,omitempty"`
 }
 
+// MuteTimeInterval defines a named list of time intervals which can be
+// referenced by routes to mute or activate notifications.
+// See https://prometheus.io/docs/alerting/latest/configuration/#time_interval
+type MuteTimeInterval struct {
+	// Name of the time interval. Must be unique across all items from the list.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// List of time intervals. The mute time interval matches if any of the
+	// time intervals matches.
+	// +optional
+	TimeIntervals []TimeInterval `json:"timeIntervals,omitempty"`
+}
+
+// TimeInterval describes a period of time. All the non-empty fields must
+// match for the time interval to match.
+type TimeInterval struct {
+	// List of time ranges within a day.
+	// +optional
+	Times []TimeRange `json:"times,omitempty"`
+	// List of days of the week.
+	// +optional
+	Weekdays []WeekdayRange `json:"weekdays,omitempty"`
+	// List of days of the month.
+	// +optional
+	DaysOfMonth []DayOfMonthRange `json:"daysOfMonth,omitempty"`
+	// List of months of the year.
+	// +optional
+	Months []MonthRange `json:"months,omitempty"`
+	// List of years.
+	// +optional
+	Years []YearRange `json:"years,omitempty"`
+	// Time zone in which the time interval is evaluated given as an IANA
+	// location name (e.g. `Europe/Paris`). Defaults to UTC.
+	// It requires Alertmanager >= 0.25.0.
+	// +optional
+	Location string `json:"location,omitempty"`
+}
+
+// Time defines a time of the day in the 24-hour format (e.g. `17:30`).
+// +kubebuilder:validation:Pattern=`^(([01][0-9]|2[0-3]):[0-5][0-9]|24:00)$`
+type Time string
+
+// TimeRange defines a range of time within a day. The start time is inclusive
+// and the end time is exclusive.
+type TimeRange struct {
+	// Start of the time range.
+	StartTime Time `json:"startTime"`
+	// End of the time range.
+	EndTime Time `json:"endTime"`
+}
+
+// WeekdayRange is a day of the week (e.g. `monday`) or an inclusive range of
+// days (e.g. `monday:friday`).
+// +kubebuilder:validation:Pattern=`^(?i)(sun|mon|tues|wednes|thurs|fri|satur)day(:(sun|mon|tues|wednes|thurs|fri|satur)day)?$`
+type WeekdayRange string
+
+// DayOfMonthRange is an inclusive range of days of the month. Negative values
+// count from the end of the month (e.g. -1 is the last day of the month).
+type DayOfMonthRange struct {
+	// Start of the range.
+	// +kubebuilder:validation:Minimum=-31
+	// +kubebuilder:validation:Maximum=31
+	Start int `json:"start"`
+	// End of the range.
+	// +kubebuilder:validation:Minimum=-31
+	// +kubebuilder:validation:Maximum=31
+	End int `json:"end"`
+}
+
+// MonthRange is a month of the year given by its name (e.g. `january`) or its
+// number (e.g. `1`), or an inclusive range of months (e.g. `january:march`).
+// +kubebuilder:validation:Pattern=`^(?i)([a-z]+|[1-9]|1[0-2])(:([a-z]+|[1-9]|1[0-2]))?$`
+type MonthRange string
+
+// YearRange is a year (e.g. `2021`) or an inclusive range of years (e.g.
+// `2021:2022`).
+// +kubebuilder:validation:Pattern=`^[0-9]{4}(:[0-9]{4})?$`
+type YearRange string
+
 // DeepCopyObject implements the runtime.Object interface.
 func (l *AlertmanagerConfig) DeepCopyObject() runtime.Object {
 	return l.DeepCopy()
@@ -0,0 +1,298 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	PrometheusAgentsKind   = "PrometheusAgent"
+	PrometheusAgentName    = "prometheusagents"
+	PrometheusAgentKindKey = "prometheusagent"
+)
+
+// PrometheusAgent defines a Prometheus deployment running in agent mode: the
+// scraped samples are only forwarded to remote-write endpoints, no rule is
+// evaluated and the data can't be queried locally.
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:categories="prometheus-operator"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.version",description="The version of Prometheus agent"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas",description="The desired replicas number of Prometheus agents"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type PrometheusAgent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the desired behavior of the Prometheus agent. More info:
+	// https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec PrometheusAgentSpec `json:"spec"`
+	// Most recent observed status of the Prometheus agent. Read-only. Not
+	// included when requesting from the apiserver, only from the Prometheus
+	// Operator API itself. More info:
+	// https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Status *monitoringv1.PrometheusStatus `json:"status,omitempty"`
+}
+
+// PrometheusAgentList is a list of PrometheusAgents.
+// +k8s:openapi-gen=true
+type PrometheusAgentList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// More info: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#metadata
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of PrometheusAgents
+	Items []*PrometheusAgent `json:"items"`
+}
+
+// PrometheusAgentSpec is a specification of the desired behavior of the
+// Prometheus agent. The fields have the same semantics as the fields of the
+// Prometheus resource.
+// +k8s:openapi-gen=true
+type PrometheusAgentSpec struct {
+	// PodMetadata configures Labels and Annotations which are propagated to the Prometheus agent pods.
+	PodMetadata *monitoringv1.EmbeddedObjectMetadata `json:"podMetadata,omitempty"`
+	// ServiceMonitors to be selected for target discovery.
+	ServiceMonitorSelector *metav1.LabelSelector `json:"serviceMonitorSelector,omitempty"`
+	// Namespace's labels to match for ServiceMonitor discovery. If nil, only
+	// check own namespace.
+	ServiceMonitorNamespaceSelector *metav1.LabelSelector `json:"serviceMonitorNamespaceSelector,omitempty"`
+	// PodMonitors to be selected for target discovery.
+	PodMonitorSelector *metav1.LabelSelector `json:"podMonitorSelector,omitempty"`
+	// Namespace's labels to match for PodMonitor discovery. If nil, only
+	// check own namespace.
+	PodMonitorNamespaceSelector *metav1.LabelSelector `json:"podMonitorNamespaceSelector,omitempty"`
+	// Probes to be selected for target discovery.
+	ProbeSelector *metav1.LabelSelector `json:"probeSelector,omitempty"`
+	// Namespaces to be selected for Probe discovery. If nil, only check own namespace.
+	ProbeNamespaceSelector *metav1.LabelSelector `json:"probeNamespaceSelector,omitempty"`
+	// ScrapeConfigs to be selected for target discovery.
+	ScrapeConfigSelector *metav1.LabelSelector `json:"scrapeConfigSelector,omitempty"`
+	// Namespaces to be selected for ScrapeConfig discovery. If nil, only check own namespace.
+	ScrapeConfigNamespaceSelector *metav1.LabelSelector `json:"scrapeConfigNamespaceSelector,omitempty"`
+	// The Kubernetes service discovery role used to discover the targets of
+	// the ServiceMonitors. The `EndpointSlice` role scales better for
+	// Services with many endpoints but requires Prometheus to have the
+	// permissions to list and watch the EndpointSlice objects.
+	// If unset, the operator uses the `Endpoints` role.
+	// The `EndpointSlice` role is only valid in Prometheus versions 2.21.0
+	// and newer.
+	ServiceDiscoveryRole *monitoringv1.ServiceDiscoveryRole `json:"serviceDiscoveryRole,omitempty"`
+	// Version of Prometheus to be deployed. The agent mode requires
+	// Prometheus >= 2.32.0.
+	Version string `json:"version,omitempty"`
+	// When a Prometheus agent deployment is paused, no actions except for
+	// deletion will be performed on the underlying objects.
+	Paused bool `json:"paused,omitempty"`
+	// Image if specified has precedence over the default base image.
+	// Specifying the version is still necessary to ensure the Prometheus
+	// Operator knows what version of Prometheus is being configured.
+	Image *string `json:"image,omitempty"`
+	// An optional list of references to secrets in the same namespace
+	// to use for pulling images from registries
+	// see http://kubernetes.io/docs/user-guide/images#specifying-imagepullsecrets-on-a-pod
+	ImagePullSecrets []v1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// Number of replicas of each shard to deploy for a Prometheus agent
+	// deployment. Number of replicas multiplied by shards is the total number
+	// of Pods created.
+	Replicas *int32 `json:"replicas,omitempty"`
+	// EXPERIMENTAL: Number of shards to distribute targets onto. Number of
+	// replicas multiplied by shards is the total number of Pods created.
+	// Sharding is done on the content of the `__address__` target meta-label.
+	Shards *int32 `json:"shards,omitempty"`
+	// Name of Prometheus external label used to denote replica name.
+	// Defaults to the value of `prometheus_replica`. External label will
+	// _not_ be added when value is set to empty string (`""`).
+	ReplicaExternalLabelName *string `json:"replicaExternalLabelName,omitempty"`
+	// Name of Prometheus external label used to denote Prometheus agent
+	// name. Defaults to the value of `prometheus`. External label will
+	// _not_ be added when value is set to empty string (`""`).
+	PrometheusExternalLabelName *string `json:"prometheusExternalLabelName,omitempty"`
+	// Log level for Prometheus to be configured with.
+	LogLevel string `json:"logLevel,omitempty"`
+	// Log format for Prometheus to be configured with.
+	LogFormat string `json:"logFormat,omitempty"`
+	// Interval between consecutive scrapes. Default: `30s`
+	ScrapeInterval string `json:"scrapeInterval,omitempty"`
+	// Number of seconds to wait for target to respond before erroring.
+	ScrapeTimeout string `json:"scrapeTimeout,omitempty"`
+	// The labels to add to any time series when communicating with
+	// external systems (remote storage).
+	ExternalLabels map[string]string `json:"externalLabels,omitempty"`
+	// Enable access to Prometheus disabled features. By default, only the
+	// agent feature is enabled.
+	// For more information see https://prometheus.io/docs/prometheus/latest/disabled_features/
+	EnableFeatures []string `json:"enableFeatures,omitempty"`
+	// EnableNativeHistograms enables the ingestion of native histograms. The
+	// operator adds the `native-histograms` feature flag so that it doesn't
+	// need to be listed in `enableFeatures`.
+	// Only valid in Prometheus versions 2.40.0 and newer.
+	EnableNativeHistograms bool `json:"enableNativeHistograms,omitempty"`
+	// The external URL the Prometheus agent instances will be available
+	// under.
+	ExternalURL string `json:"externalUrl,omitempty"`
+	// The route prefix Prometheus registers HTTP handlers for.
+	RoutePrefix string `json:"routePrefix,omitempty"`
+	// Storage spec to specify how storage of the write-ahead log shall be used.
+	Storage *monitoringv1.StorageSpec `json:"storage,omitempty"`
+	// Volumes allows configuration of additional volumes on the output StatefulSet definition. Volumes specified will
+	// be appended to other volumes that are generated as a result of StorageSpec objects.
+	Volumes []v1.Volume `json:"volumes,omitempty"`
+	// VolumeMounts allows configuration of additional VolumeMounts on the output StatefulSet definition.
+	// VolumeMounts specified will be appended to other VolumeMounts in the prometheus container,
+	// that are generated as a result of StorageSpec objects.
+	VolumeMounts []v1.VolumeMount `json:"volumeMounts,omitempty"`
+	// WebSpec defines the web command line flags when starting Prometheus.
+	Web *monitoringv1.WebSpec `json:"web,omitempty"`
+	// Define resources requests and limits for single Pods.
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+	// Define which Nodes the Pods are scheduled on.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// ServiceAccountName is the name of the ServiceAccount to use to run the
+	// Prometheus agent Pods.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// Secrets is a list of Secrets in the same namespace as the PrometheusAgent
+	// object, which shall be mounted into the Prometheus agent Pods.
+	// The Secrets are mounted into /etc/prometheus/secrets/<secret-name>.
+	Secrets []string `json:"secrets,omitempty"`
+	// ConfigMaps is a list of ConfigMaps in the same namespace as the
+	// PrometheusAgent object, which shall be mounted into the Prometheus agent
+	// Pods. The ConfigMaps are mounted into /etc/prometheus/configmaps/<configmap-name>.
+	ConfigMaps []string `json:"configMaps,omitempty"`
+	// If specified, the pod's scheduling constraints.
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+	// If specified, the pod's tolerations.
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+	// If specified, the pod's topology spread constraints.
+	// Constraints defining `additionalLabelSelectors` get the labels of the
+	// Prometheus agent pods added to their label selector by the operator.
+	TopologySpreadConstraints []monitoringv1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// Pods' hostAliases configuration
+	// +listType=map
+	// +listMapKey=ip
+	HostAliases []monitoringv1.HostAlias `json:"hostAliases,omitempty"`
+	// Defines the DNS policy for the pods.
+	// +kubebuilder:validation:Enum=ClusterFirstWithHostNet;ClusterFirst;Default;None
+	DNSPolicy v1.DNSPolicy `json:"dnsPolicy,omitempty"`
+	// Defines the DNS configuration for the pods.
+	DNSConfig *v1.PodDNSConfig `json:"dnsConfig,omitempty"`
+	// The remote_write spec. At least one endpoint is required (here or with
+	// `remoteWriteToThanosReceive`) since the agent doesn't store the samples
+	// locally.
+	RemoteWrite []monitoringv1.RemoteWriteSpec `json:"remoteWrite,omitempty"`
+	// Configures the remote write to Thanos Receive. The endpoint is added to
+	// the ones defined in `remoteWrite`.
+	RemoteWriteToThanosReceive *monitoringv1.RemoteWriteToThanosReceiveSpec `json:"remoteWriteToThanosReceive,omitempty"`
+	// SecurityContext holds pod-level security attributes and common container settings.
+	// This defaults to the default PodSecurityContext.
+	SecurityContext *v1.PodSecurityContext `json:"securityContext,omitempty"`
+	// ListenLocal makes the Prometheus agent listen on loopback, so that it
+	// does not bind against the Pod IP.
+	ListenLocal bool `json:"listenLocal,omitempty"`
+	// Containers allows injecting additional containers or modifying operator
+	// generated containers. Containers described here modify an operator
+	// generated container if they share the same name and modifications are
+	// done via a strategic merge patch. The current container names are:
+	// `prometheus` and `config-reloader`. Overriding containers is entirely
+	// outside the scope of what the maintainers will support and by doing so,
+	// you accept that this behaviour may break at any time without notice.
+	Containers []v1.Container `json:"containers,omitempty"`
+	// InitContainers allows adding initContainers to the pod definition.
+	// InitContainers described here modify an operator generated init
+	// containers if they share the same name and modifications are done via a
+	// strategic merge patch. The current init container name is:
+	// `init-config-reloader`. The values set in InitContainers take precedence
+	// over the operator generated values and the additional init containers run
+	// after the operator generated ones. Init container names must be unique.
+	InitContainers []v1.Container `json:"initContainers,omitempty"`
+	// Sidecars defines containers running alongside the Prometheus agent
+	// container. Contrary to `containers`, the operator adds them after its
+	// own containers, mounts the shared volumes on demand and restarts the
+	// pods when the Secrets referenced by the sidecars change.
+	// +listType=map
+	// +listMapKey=name
+	Sidecars []monitoringv1.Sidecar `json:"sidecars,omitempty"`
+	// AdditionalScrapeConfigs allows specifying a key of a Secret containing
+	// additional Prometheus scrape configurations. Scrape configurations
+	// specified are appended to the configurations generated by the Prometheus
+	// Operator.
+	AdditionalScrapeConfigs *v1.SecretKeySelector `json:"additionalScrapeConfigs,omitempty"`
+	// APIServerConfig allows specifying a host and auth methods to access apiserver.
+	// If left empty, Prometheus is assumed to run inside of the cluster
+	// and will discover API servers automatically and use the pod's CA certificate
+	// and bearer token file at /var/run/secrets/kubernetes.io/serviceaccount/.
+	APIServerConfig *monitoringv1.APIServerConfig `json:"apiserverConfig,omitempty"`
+	// Priority class assigned to the Pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Port name used for the pods and governing service.
+	// This defaults to web
+	PortName string `json:"portName,omitempty"`
+	// ArbitraryFSAccessThroughSMs configures whether configuration
+	// based on a service monitor can access arbitrary files on the file system
+	// of the Prometheus container e.g. bearer token files.
+	ArbitraryFSAccessThroughSMs monitoringv1.ArbitraryFSAccessThroughSMsConfig `json:"arbitraryFSAccessThroughSMs,omitempty"`
+	// OverrideHonorLabels if set to true overrides all user configured honor_labels.
+	// If HonorLabels is set in ServiceMonitor or PodMonitor to true, this overrides honor_labels to false.
+	OverrideHonorLabels bool `json:"overrideHonorLabels,omitempty"`
+	// OverrideHonorTimestamps allows to globally enforce honoring timestamps in all scrape configs.
+	OverrideHonorTimestamps bool `json:"overrideHonorTimestamps,omitempty"`
+	// IgnoreNamespaceSelectors if set to true will ignore NamespaceSelector settings from
+	// the podmonitor and servicemonitor configs, and they will only discover endpoints
+	// within their current namespace.  Defaults to false.
+	IgnoreNamespaceSelectors bool `json:"ignoreNamespaceSelectors,omitempty"`
+	// EnforcedNamespaceLabel If set, a label will be added to all
+	// user-metrics (created by `ServiceMonitor`, `PodMonitor` and `Probe`
+	// objects). Label name is this field's value. Label value is the namespace
+	// of the created object.
+	EnforcedNamespaceLabel string `json:"enforcedNamespaceLabel,omitempty"`
+	// EnforcedSampleLimit defines global limit on number of scraped samples
+	// that will be accepted. This overrides any SampleLimit set per
+	// ServiceMonitor or/and PodMonitor.
+	EnforcedSampleLimit *uint64 `json:"enforcedSampleLimit,omitempty"`
+	// EnforcedTargetLimit defines a global limit on the number of scraped
+	// targets. This overrides any TargetLimit set per ServiceMonitor or/and
+	// PodMonitor.
+	EnforcedTargetLimit *uint64 `json:"enforcedTargetLimit,omitempty"`
+	// Per-scrape limit on number of labels that will be accepted for a sample.
+	EnforcedLabelLimit *uint64 `json:"enforcedLabelLimit,omitempty"`
+	// Per-scrape limit on length of labels name that will be accepted for a sample.
+	EnforcedLabelNameLengthLimit *uint64 `json:"enforcedLabelNameLengthLimit,omitempty"`
+	// Per-scrape limit on length of labels value that will be accepted for a sample.
+	EnforcedLabelValueLengthLimit *uint64 `json:"enforcedLabelValueLengthLimit,omitempty"`
+	// EnforcedBodySizeLimit defines the maximum size of uncompressed response body
+	// that will be accepted by Prometheus. Example: 100MB.
+	EnforcedBodySizeLimit string `json:"enforcedBodySizeLimit,omitempty"`
+	// Minimum number of seconds for which a newly created pod should be ready
+	// without any of its container crashing for it to be considered available.
+	// Defaults to 0 (pod will be considered available as soon as it is ready)
+	// This is an alpha field from kubernetes 1.22 until 1.24 which requires
+	// enabling the StatefulSetMinReadySeconds feature gate.
+	// +optional
+	MinReadySeconds *uint32 `json:"minReadySeconds,omitempty"`
+}
+
+// DeepCopyObject implements the runtime.Object interface.
+func (l *PrometheusAgent) DeepCopyObject() runtime.Object {
+	return l.DeepCopy()
+}
+
+// DeepCopyObject implements the runtime.Object interface.
+func (l *PrometheusAgentList) DeepCopyObject() runtime.Object {
+	return l.DeepCopy()
+}
@@ -0,0 +1,199 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	ScrapeConfigsKind   = "ScrapeConfig"
+	ScrapeConfigName    = "scrapeconfigs"
+	ScrapeConfigKindKey = "scrapeconfig"
+)
+
+// ScrapeConfig defines a namespaced Prometheus scrape job. The targets are
+// either static or discovered with one of the supported service discovery
+// mechanisms.
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:categories="prometheus-operator"
+type ScrapeConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the scrape job.
+	Spec ScrapeConfigSpec `json:"spec"`
+}
+
+// ScrapeConfigList is a list of ScrapeConfigs.
+// +k8s:openapi-gen=true
+type ScrapeConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// More info: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#metadata
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of ScrapeConfigs
+	Items []*ScrapeConfig `json:"items"`
+}
+
+// ScrapeConfigSpec contains specification parameters for a ScrapeConfig.
+// +k8s:openapi-gen=true
+type ScrapeConfigSpec struct {
+	// The job name assigned to scraped metrics by default.
+	JobName string `json:"jobName,omitempty"`
+	// StaticConfigs defines a list of static targets with a common label set.
+	// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#static_config
+	StaticConfigs []StaticConfig `json:"staticConfigs,omitempty"`
+	// FileSDConfigs defines a list of file service discovery configurations.
+	// The files have to be mounted into the Prometheus pods, for instance
+	// with the `configMaps` field of the Prometheus resource.
+	// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#file_sd_config
+	FileSDConfigs []FileSDConfig `json:"fileSDConfigs,omitempty"`
+	// HTTPSDConfigs defines a list of HTTP service discovery configurations.
+	// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#http_sd_config
+	HTTPSDConfigs []HTTPSDConfig `json:"httpSDConfigs,omitempty"`
+	// DNSSDConfigs defines a list of DNS service discovery configurations.
+	// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#dns_sd_config
+	DNSSDConfigs []DNSSDConfig `json:"dnsSDConfigs,omitempty"`
+	// KubernetesSDConfigs defines a list of Kubernetes service discovery
+	// configurations. The discovery is restricted to the namespace of the
+	// ScrapeConfig object.
+	// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#kubernetes_sd_config
+	KubernetesSDConfigs []KubernetesSDConfig `json:"kubernetesSDConfigs,omitempty"`
+	// RelabelConfigs to apply to the targets before scraping.
+	// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config
+	RelabelConfigs []*monitoringv1.RelabelConfig `json:"relabelings,omitempty"`
+	// HTTP path to scrape for metrics. If empty, Prometheus uses the default value (e.g. `/metrics`).
+	MetricsPath string `json:"metricsPath,omitempty"`
+	// HTTP scheme to use for scraping.
+	Scheme string `json:"scheme,omitempty"`
+	// Optional HTTP URL parameters.
+	Params map[string][]string `json:"params,omitempty"`
+	// Interval at which metrics should be scraped.
+	// If not specified Prometheus' global scrape interval is used.
+	ScrapeInterval string `json:"scrapeInterval,omitempty"`
+	// Timeout after which the scrape is ended.
+	// If not specified, the Prometheus global scrape timeout is used.
+	ScrapeTimeout string `json:"scrapeTimeout,omitempty"`
+	// HonorLabels chooses the metric's labels on collisions with target labels.
+	HonorLabels bool `json:"honorLabels,omitempty"`
+	// HonorTimestamps controls whether Prometheus respects the timestamps present in scraped data.
+	HonorTimestamps *bool `json:"honorTimestamps,omitempty"`
+	// BasicAuth information to use on every scrape request.
+	BasicAuth *monitoringv1.BasicAuth `json:"basicAuth,omitempty"`
+	// Authorization header to use on every scrape request.
+	Authorization *monitoringv1.SafeAuthorization `json:"authorization,omitempty"`
+	// TLS configuration to use on every scrape request.
+	TLSConfig *monitoringv1.SafeTLSConfig `json:"tlsConfig,omitempty"`
+	// MetricRelabelConfigs to apply to samples before ingestion.
+	MetricRelabelConfigs []*monitoringv1.RelabelConfig `json:"metricRelabelings,omitempty"`
+	// SampleLimit defines per-scrape limit on number of scraped samples that will be accepted.
+	SampleLimit uint64 `json:"sampleLimit,omitempty"`
+	// TargetLimit defines a limit on the number of scraped targets that will be accepted.
+	TargetLimit uint64 `json:"targetLimit,omitempty"`
+	// Per-scrape limit on number of labels that will be accepted for a sample.
+	// Only valid in Prometheus versions 2.27.0 and newer.
+	LabelLimit uint64 `json:"labelLimit,omitempty"`
+	// Per-scrape limit on length of labels name that will be accepted for a sample.
+	// Only valid in Prometheus versions 2.27.0 and newer.
+	LabelNameLengthLimit uint64 `json:"labelNameLengthLimit,omitempty"`
+	// Per-scrape limit on length of labels value that will be accepted for a sample.
+	// Only valid in Prometheus versions 2.27.0 and newer.
+	LabelValueLengthLimit uint64 `json:"labelValueLengthLimit,omitempty"`
+	// Per-scrape limit on the number of targets dropped by relabeling that
+	// will be kept in memory. 0 means no limit.
+	// Only valid in Prometheus versions 2.47.0 and newer.
+	KeepDroppedTargets uint64 `json:"keepDroppedTargets,omitempty"`
+}
+
+// StaticConfig defines a list of static targets with a common label set.
+// +k8s:openapi-gen=true
+type StaticConfig struct {
+	// List of targets for this static configuration.
+	Targets []string `json:"targets,omitempty"`
+	// Labels assigned to all metrics scraped from the targets.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// FileSDConfig defines a Prometheus file service discovery configuration.
+// +k8s:openapi-gen=true
+type FileSDConfig struct {
+	// List of files to be used for file discovery. The last path segment may
+	// contain a single `*` that matches any character sequence.
+	// +kubebuilder:validation:MinItems=1
+	Files []string `json:"files"`
+	// RefreshInterval configures the refresh interval at which Prometheus
+	// will re-read the files.
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+}
+
+// HTTPSDConfig defines a Prometheus HTTP service discovery configuration.
+// +k8s:openapi-gen=true
+type HTTPSDConfig struct {
+	// URL from which the targets are fetched.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:Pattern:="^http(s)?://.+$"
+	URL string `json:"url"`
+	// RefreshInterval configures the refresh interval at which Prometheus
+	// will re-query the endpoint to update the target list.
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+	// BasicAuth information to authenticate against the target HTTP endpoint.
+	BasicAuth *monitoringv1.BasicAuth `json:"basicAuth,omitempty"`
+	// Authorization header configuration to authenticate against the target
+	// HTTP endpoint.
+	Authorization *monitoringv1.SafeAuthorization `json:"authorization,omitempty"`
+	// TLS configuration to use when connecting to the target HTTP endpoint.
+	TLSConfig *monitoringv1.SafeTLSConfig `json:"tlsConfig,omitempty"`
+}
+
+// DNSSDConfig defines a Prometheus DNS service discovery configuration.
+// +k8s:openapi-gen=true
+type DNSSDConfig struct {
+	// A list of DNS domain names to be queried.
+	// +kubebuilder:validation:MinItems=1
+	Names []string `json:"names"`
+	// RefreshInterval configures the time after which the provided names are
+	// refreshed.
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+	// The type of DNS query to perform. If not set, Prometheus uses its
+	// default value (`SRV`).
+	// +kubebuilder:validation:Enum=SRV;A;AAAA;MX
+	Type string `json:"type,omitempty"`
+	// The port number used if the query type is not SRV. Ignored for SRV
+	// records.
+	Port *int32 `json:"port,omitempty"`
+}
+
+// KubernetesSDConfig defines a Prometheus Kubernetes service discovery
+// configuration.
+// +k8s:openapi-gen=true
+type KubernetesSDConfig struct {
+	// Role of the Kubernetes entities that should be discovered.
+	// +kubebuilder:validation:Enum=pod;service;endpoints;endpointslice;node;ingress
+	Role string `json:"role"`
+}
+
+// DeepCopyObject implements the runtime.Object interface.
+func (l *ScrapeConfig) DeepCopyObject() runtime.Object {
+	return l.DeepCopy()
+}
+
+// DeepCopyObject implements the runtime.Object interface.
+func (l *ScrapeConfigList) DeepCopyObject() runtime.Object {
+	return l.DeepCopy()
+}
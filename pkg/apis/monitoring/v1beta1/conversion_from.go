@@ -0,0 +1,498 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+
+	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// ConvertFrom converts from the v1alpha1 version to this version.
+func (dst *AlertmanagerConfig) ConvertFrom(src *v1alpha1.AlertmanagerConfig) error {
+	dst.ObjectMeta = src.ObjectMeta
+	dst.APIVersion = SchemeGroupVersion.String()
+	dst.Kind = AlertmanagerConfigKind
+
+	for _, in := range src.Spec.Receivers {
+		dst.Spec.Receivers = append(dst.Spec.Receivers, convertReceiverFrom(in))
+	}
+
+	if src.Spec.Route != nil {
+		r, err := convertRouteFrom(src.Spec.Route)
+		if err != nil {
+			return fmt.Errorf("route: %w", err)
+		}
+		dst.Spec.Route = r
+	}
+
+	for _, in := range src.Spec.InhibitRules {
+		dst.Spec.InhibitRules = append(dst.Spec.InhibitRules, InhibitRule{
+			TargetMatch:    convertMatchersFrom(in.TargetMatch),
+			SourceMatch:    convertMatchersFrom(in.SourceMatch),
+			Equal:          in.Equal,
+			CrossNamespace: in.CrossNamespace,
+		})
+	}
+
+	for _, in := range src.Spec.MuteTimeIntervals {
+		dst.Spec.TimeIntervals = append(dst.Spec.TimeIntervals, convertMuteTimeIntervalFrom(in))
+	}
+
+	return nil
+}
+
+func convertMuteTimeIntervalFrom(in v1alpha1.MuteTimeInterval) TimeInterval {
+	out := TimeInterval{
+		Name: in.Name,
+	}
+
+	for _, ti := range in.TimeIntervals {
+		tp := TimePeriod{
+			Location: ti.Location,
+		}
+
+		for _, tr := range ti.Times {
+			tp.Times = append(tp.Times, TimeRange{
+				StartTime: Time(tr.StartTime),
+				EndTime:   Time(tr.EndTime),
+			})
+		}
+
+		for _, wd := range ti.Weekdays {
+			tp.Weekdays = append(tp.Weekdays, WeekdayRange(wd))
+		}
+
+		for _, dm := range ti.DaysOfMonth {
+			tp.DaysOfMonth = append(tp.DaysOfMonth, DayOfMonthRange{
+				Start: dm.Start,
+				End:   dm.End,
+			})
+		}
+
+		for _, m := range ti.Months {
+			tp.Months = append(tp.Months, MonthRange(m))
+		}
+
+		for _, y := range ti.Years {
+			tp.Years = append(tp.Years, YearRange(y))
+		}
+
+		out.TimeIntervals = append(out.TimeIntervals, tp)
+	}
+
+	return out
+}
+
+func convertRouteFrom(in *v1alpha1.Route) (*Route, error) {
+	out := &Route{
+		Receiver:            in.Receiver,
+		GroupBy:             in.GroupBy,
+		GroupWait:           in.GroupWait,
+		GroupInterval:       in.GroupInterval,
+		RepeatInterval:      in.RepeatInterval,
+		Matchers:            convertMatchersFrom(in.Matchers),
+		Continue:            in.Continue,
+		MuteTimeIntervals:   in.MuteTimeIntervals,
+		ActiveTimeIntervals: in.ActiveTimeIntervals,
+	}
+
+	children, err := in.ChildRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range children {
+		child, err := convertRouteFrom(&children[i])
+		if err != nil {
+			return nil, fmt.Errorf("route[%d]: %w", i, err)
+		}
+
+		b, err := json.Marshal(child)
+		if err != nil {
+			return nil, fmt.Errorf("route[%d]: %w", i, err)
+		}
+
+		out.Routes = append(out.Routes, apiextensionsv1.JSON{Raw: b})
+	}
+
+	return out, nil
+}
+
+func convertMatchersFrom(in []v1alpha1.Matcher) []Matcher {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]Matcher, 0, len(in))
+	for _, m := range in {
+		mt := MatchEqual
+		if m.Regex {
+			mt = MatchRegexp
+		}
+
+		out = append(out, Matcher{
+			Name:      m.Name,
+			Value:     m.Value,
+			MatchType: mt,
+		})
+	}
+
+	return out
+}
+
+func convertSecretKeySelectorFrom(in *v1.SecretKeySelector) *SecretKeySelector {
+	if in == nil {
+		return nil
+	}
+
+	return &SecretKeySelector{
+		Name: in.Name,
+		Key:  in.Key,
+	}
+}
+
+func convertKeyValuesFrom(in []v1alpha1.KeyValue) []KeyValue {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]KeyValue, 0, len(in))
+	for _, kv := range in {
+		out = append(out, KeyValue(kv))
+	}
+
+	return out
+}
+
+func convertHTTPConfigFrom(in *v1alpha1.HTTPConfig) *HTTPConfig {
+	if in == nil {
+		return nil
+	}
+
+	return &HTTPConfig{
+		Authorization:     in.Authorization,
+		BasicAuth:         in.BasicAuth,
+		BearerTokenSecret: convertSecretKeySelectorFrom(in.BearerTokenSecret),
+		TLSConfig:         in.TLSConfig,
+		ProxyURL:          in.ProxyURL,
+	}
+}
+
+func convertReceiverFrom(in v1alpha1.Receiver) Receiver {
+	out := Receiver{
+		Name:            in.Name,
+		SecretNamespace: in.SecretNamespace,
+	}
+
+	for _, c := range in.OpsGenieConfigs {
+		out.OpsGenieConfigs = append(out.OpsGenieConfigs, convertOpsGenieConfigFrom(c))
+	}
+
+	for _, c := range in.PagerDutyConfigs {
+		out.PagerDutyConfigs = append(out.PagerDutyConfigs, convertPagerDutyConfigFrom(c))
+	}
+
+	for _, c := range in.SlackConfigs {
+		out.SlackConfigs = append(out.SlackConfigs, convertSlackConfigFrom(c))
+	}
+
+	for _, c := range in.WebhookConfigs {
+		out.WebhookConfigs = append(out.WebhookConfigs, convertWebhookConfigFrom(c))
+	}
+
+	for _, c := range in.WeChatConfigs {
+		out.WeChatConfigs = append(out.WeChatConfigs, convertWeChatConfigFrom(c))
+	}
+
+	for _, c := range in.EmailConfigs {
+		out.EmailConfigs = append(out.EmailConfigs, convertEmailConfigFrom(c))
+	}
+
+	for _, c := range in.VictorOpsConfigs {
+		out.VictorOpsConfigs = append(out.VictorOpsConfigs, convertVictorOpsConfigFrom(c))
+	}
+
+	for _, c := range in.PushoverConfigs {
+		out.PushoverConfigs = append(out.PushoverConfigs, convertPushoverConfigFrom(c))
+	}
+
+	for _, c := range in.TelegramConfigs {
+		out.TelegramConfigs = append(out.TelegramConfigs, convertTelegramConfigFrom(c))
+	}
+
+	for _, c := range in.DiscordConfigs {
+		out.DiscordConfigs = append(out.DiscordConfigs, convertDiscordConfigFrom(c))
+	}
+
+	for _, c := range in.MSTeamsConfigs {
+		out.MSTeamsConfigs = append(out.MSTeamsConfigs, convertMSTeamsConfigFrom(c))
+	}
+
+	for _, c := range in.MSTeamsV2Configs {
+		out.MSTeamsV2Configs = append(out.MSTeamsV2Configs, convertMSTeamsV2ConfigFrom(c))
+	}
+
+	for _, c := range in.SNSConfigs {
+		out.SNSConfigs = append(out.SNSConfigs, convertSNSConfigFrom(c))
+	}
+
+	for _, c := range in.WebexConfigs {
+		out.WebexConfigs = append(out.WebexConfigs, convertWebexConfigFrom(c))
+	}
+
+	return out
+}
+
+func convertOpsGenieConfigFrom(in v1alpha1.OpsGenieConfig) OpsGenieConfig {
+	out := OpsGenieConfig{
+		SendResolved: in.SendResolved,
+		APIKey:       convertSecretKeySelectorFrom(in.APIKey),
+		APIURL:       in.APIURL,
+		Message:      in.Message,
+		Description:  in.Description,
+		Source:       in.Source,
+		Tags:         in.Tags,
+		Note:         in.Note,
+		Priority:     in.Priority,
+		Details:      convertKeyValuesFrom(in.Details),
+		HTTPConfig:   convertHTTPConfigFrom(in.HTTPConfig),
+	}
+
+	for _, r := range in.Responders {
+		out.Responders = append(out.Responders, OpsGenieConfigResponder(r))
+	}
+
+	return out
+}
+
+func convertPagerDutyConfigFrom(in v1alpha1.PagerDutyConfig) PagerDutyConfig {
+	return PagerDutyConfig{
+		SendResolved: in.SendResolved,
+		RoutingKey:   convertSecretKeySelectorFrom(in.RoutingKey),
+		ServiceKey:   convertSecretKeySelectorFrom(in.ServiceKey),
+		URL:          in.URL,
+		Client:       in.Client,
+		ClientURL:    in.ClientURL,
+		Description:  in.Description,
+		Severity:     in.Severity,
+		Class:        in.Class,
+		Group:        in.Group,
+		Component:    in.Component,
+		Details:      convertKeyValuesFrom(in.Details),
+		HTTPConfig:   convertHTTPConfigFrom(in.HTTPConfig),
+	}
+}
+
+func convertSlackConfigFrom(in v1alpha1.SlackConfig) SlackConfig {
+	out := SlackConfig{
+		SendResolved: in.SendResolved,
+		APIURL:       convertSecretKeySelectorFrom(in.APIURL),
+		Channel:      in.Channel,
+		Username:     in.Username,
+		Color:        in.Color,
+		Title:        in.Title,
+		TitleLink:    in.TitleLink,
+		Pretext:      in.Pretext,
+		Text:         in.Text,
+		ShortFields:  in.ShortFields,
+		Footer:       in.Footer,
+		Fallback:     in.Fallback,
+		CallbackID:   in.CallbackID,
+		IconEmoji:    in.IconEmoji,
+		IconURL:      in.IconURL,
+		ImageURL:     in.ImageURL,
+		ThumbURL:     in.ThumbURL,
+		LinkNames:    in.LinkNames,
+		MrkdwnIn:     in.MrkdwnIn,
+		HTTPConfig:   convertHTTPConfigFrom(in.HTTPConfig),
+	}
+
+	for _, f := range in.Fields {
+		out.Fields = append(out.Fields, SlackField(f))
+	}
+
+	for _, a := range in.Actions {
+		action := SlackAction{
+			Type:  a.Type,
+			Text:  a.Text,
+			URL:   a.URL,
+			Style: a.Style,
+			Name:  a.Name,
+			Value: a.Value,
+		}
+
+		if a.ConfirmField != nil {
+			cf := SlackConfirmationField(*a.ConfirmField)
+			action.ConfirmField = &cf
+		}
+
+		out.Actions = append(out.Actions, action)
+	}
+
+	return out
+}
+
+func convertWebhookConfigFrom(in v1alpha1.WebhookConfig) WebhookConfig {
+	return WebhookConfig{
+		SendResolved: in.SendResolved,
+		URL:          in.URL,
+		URLSecret:    convertSecretKeySelectorFrom(in.URLSecret),
+		HTTPConfig:   convertHTTPConfigFrom(in.HTTPConfig),
+		MaxAlerts:    in.MaxAlerts,
+	}
+}
+
+func convertWeChatConfigFrom(in v1alpha1.WeChatConfig) WeChatConfig {
+	return WeChatConfig{
+		SendResolved: in.SendResolved,
+		APISecret:    convertSecretKeySelectorFrom(in.APISecret),
+		APIURL:       in.APIURL,
+		CorpID:       in.CorpID,
+		AgentID:      in.AgentID,
+		ToUser:       in.ToUser,
+		ToParty:      in.ToParty,
+		ToTag:        in.ToTag,
+		Message:      in.Message,
+		MessageType:  in.MessageType,
+		HTTPConfig:   convertHTTPConfigFrom(in.HTTPConfig),
+	}
+}
+
+func convertEmailConfigFrom(in v1alpha1.EmailConfig) EmailConfig {
+	return EmailConfig{
+		SendResolved: in.SendResolved,
+		To:           in.To,
+		From:         in.From,
+		Hello:        in.Hello,
+		Smarthost:    in.Smarthost,
+		AuthUsername: in.AuthUsername,
+		AuthPassword: convertSecretKeySelectorFrom(in.AuthPassword),
+		AuthSecret:   convertSecretKeySelectorFrom(in.AuthSecret),
+		AuthIdentity: in.AuthIdentity,
+		Headers:      convertKeyValuesFrom(in.Headers),
+		HTML:         in.HTML,
+		Text:         in.Text,
+		RequireTLS:   in.RequireTLS,
+		TLSConfig:    in.TLSConfig,
+	}
+}
+
+func convertVictorOpsConfigFrom(in v1alpha1.VictorOpsConfig) VictorOpsConfig {
+	return VictorOpsConfig{
+		SendResolved:      in.SendResolved,
+		APIKey:            convertSecretKeySelectorFrom(in.APIKey),
+		APIURL:            in.APIURL,
+		RoutingKey:        in.RoutingKey,
+		MessageType:       in.MessageType,
+		EntityDisplayName: in.EntityDisplayName,
+		StateMessage:      in.StateMessage,
+		MonitoringTool:    in.MonitoringTool,
+		CustomFields:      convertKeyValuesFrom(in.CustomFields),
+		HTTPConfig:        convertHTTPConfigFrom(in.HTTPConfig),
+	}
+}
+
+func convertPushoverConfigFrom(in v1alpha1.PushoverConfig) PushoverConfig {
+	return PushoverConfig{
+		SendResolved: in.SendResolved,
+		UserKey:      convertSecretKeySelectorFrom(in.UserKey),
+		Token:        convertSecretKeySelectorFrom(in.Token),
+		Title:        in.Title,
+		Message:      in.Message,
+		URL:          in.URL,
+		URLTitle:     in.URLTitle,
+		Sound:        in.Sound,
+		Priority:     in.Priority,
+		Retry:        in.Retry,
+		Expire:       in.Expire,
+		HTML:         in.HTML,
+		HTTPConfig:   convertHTTPConfigFrom(in.HTTPConfig),
+	}
+}
+
+func convertTelegramConfigFrom(in v1alpha1.TelegramConfig) TelegramConfig {
+	return TelegramConfig{
+		SendResolved:         in.SendResolved,
+		APIURL:               in.APIURL,
+		BotToken:             convertSecretKeySelectorFrom(in.BotToken),
+		ChatID:               in.ChatID,
+		Message:              in.Message,
+		DisableNotifications: in.DisableNotifications,
+		ParseMode:            in.ParseMode,
+		HTTPConfig:           convertHTTPConfigFrom(in.HTTPConfig),
+	}
+}
+
+func convertDiscordConfigFrom(in v1alpha1.DiscordConfig) DiscordConfig {
+	return DiscordConfig{
+		SendResolved: in.SendResolved,
+		APIURL:       convertSecretKeySelectorFrom(in.APIURL),
+		Title:        in.Title,
+		Message:      in.Message,
+		HTTPConfig:   convertHTTPConfigFrom(in.HTTPConfig),
+	}
+}
+
+func convertMSTeamsConfigFrom(in v1alpha1.MSTeamsConfig) MSTeamsConfig {
+	return MSTeamsConfig{
+		SendResolved: in.SendResolved,
+		WebhookURL:   convertSecretKeySelectorFrom(in.WebhookURL),
+		Title:        in.Title,
+		Summary:      in.Summary,
+		Text:         in.Text,
+		HTTPConfig:   convertHTTPConfigFrom(in.HTTPConfig),
+	}
+}
+
+func convertMSTeamsV2ConfigFrom(in v1alpha1.MSTeamsV2Config) MSTeamsV2Config {
+	return MSTeamsV2Config{
+		SendResolved: in.SendResolved,
+		WebhookURL:   convertSecretKeySelectorFrom(in.WebhookURL),
+		Title:        in.Title,
+		Text:         in.Text,
+		HTTPConfig:   convertHTTPConfigFrom(in.HTTPConfig),
+	}
+}
+
+func convertSNSConfigFrom(in v1alpha1.SNSConfig) SNSConfig {
+	return SNSConfig{
+		SendResolved: in.SendResolved,
+		APIURL:       in.APIURL,
+		Sigv4:        in.Sigv4,
+		TopicARN:     in.TopicARN,
+		PhoneNumber:  in.PhoneNumber,
+		TargetARN:    in.TargetARN,
+		Subject:      in.Subject,
+		Message:      in.Message,
+		Attributes:   in.Attributes,
+		HTTPConfig:   convertHTTPConfigFrom(in.HTTPConfig),
+	}
+}
+
+func convertWebexConfigFrom(in v1alpha1.WebexConfig) WebexConfig {
+	return WebexConfig{
+		SendResolved: in.SendResolved,
+		APIURL:       in.APIURL,
+		BotToken:     convertSecretKeySelectorFrom(in.BotToken),
+		RoomID:       in.RoomID,
+		Message:      in.Message,
+		HTTPConfig:   convertHTTPConfigFrom(in.HTTPConfig),
+	}
+}
@@ -0,0 +1,347 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+
+	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func secretKeySelector(name, key string) *v1.SecretKeySelector {
+	return &v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: name},
+		Key:                  key,
+	}
+}
+
+func mustMarshalRoute(t *testing.T, r interface{}) apiextensionsv1.JSON {
+	t.Helper()
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return apiextensionsv1.JSON{Raw: b}
+}
+
+func TestConvertFrom(t *testing.T) {
+	src := &v1alpha1.AlertmanagerConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       v1alpha1.AlertmanagerConfigKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "ns",
+		},
+		Spec: v1alpha1.AlertmanagerConfigSpec{
+			Route: &v1alpha1.Route{
+				Receiver:          "pagerduty",
+				MuteTimeIntervals: []string{"business-hours"},
+				Matchers: []v1alpha1.Matcher{
+					{Name: "severity", Value: "critical"},
+					{Name: "job", Value: "node|kubelet", Regex: true},
+				},
+				Routes: []apiextensionsv1.JSON{
+					mustMarshalRoute(t, v1alpha1.Route{
+						Receiver: "slack",
+						Matchers: []v1alpha1.Matcher{{Name: "team", Value: "infra.*", Regex: true}},
+					}),
+				},
+			},
+			Receivers: []v1alpha1.Receiver{
+				{
+					Name:            "pagerduty",
+					SecretNamespace: "shared-credentials",
+					PagerDutyConfigs: []v1alpha1.PagerDutyConfig{
+						{
+							RoutingKey: secretKeySelector("pd", "routing-key"),
+							HTTPConfig: &v1alpha1.HTTPConfig{
+								BearerTokenSecret: secretKeySelector("pd", "token"),
+							},
+						},
+					},
+				},
+				{
+					Name: "slack",
+					SlackConfigs: []v1alpha1.SlackConfig{
+						{
+							APIURL: secretKeySelector("slack", "url"),
+							Actions: []v1alpha1.SlackAction{
+								{
+									Type:         "button",
+									Text:         "Silence",
+									Name:         "silence",
+									ConfirmField: &v1alpha1.SlackConfirmationField{Text: "Are you sure?"},
+								},
+							},
+						},
+					},
+				},
+			},
+			InhibitRules: []v1alpha1.InhibitRule{
+				{
+					SourceMatch: []v1alpha1.Matcher{{Name: "severity", Value: "critical"}},
+					TargetMatch: []v1alpha1.Matcher{{Name: "severity", Value: "warning|info", Regex: true}},
+					Equal:       []string{"job"},
+				},
+				{
+					SourceMatch:    []v1alpha1.Matcher{{Name: "alertname", Value: "NodeDown"}},
+					Equal:          []string{"node"},
+					CrossNamespace: true,
+				},
+			},
+			MuteTimeIntervals: []v1alpha1.MuteTimeInterval{
+				{
+					Name: "business-hours",
+					TimeIntervals: []v1alpha1.TimeInterval{
+						{
+							Times:       []v1alpha1.TimeRange{{StartTime: "09:00", EndTime: "17:00"}},
+							Weekdays:    []v1alpha1.WeekdayRange{"monday:friday"},
+							DaysOfMonth: []v1alpha1.DayOfMonthRange{{Start: 1, End: -1}},
+							Months:      []v1alpha1.MonthRange{"january:march"},
+							Years:       []v1alpha1.YearRange{"2023"},
+							Location:    "Europe/Paris",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expected := &AlertmanagerConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       AlertmanagerConfigKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "ns",
+		},
+		Spec: AlertmanagerConfigSpec{
+			Route: &Route{
+				Receiver:          "pagerduty",
+				MuteTimeIntervals: []string{"business-hours"},
+				Matchers: []Matcher{
+					{Name: "severity", Value: "critical", MatchType: MatchEqual},
+					{Name: "job", Value: "node|kubelet", MatchType: MatchRegexp},
+				},
+				Routes: []apiextensionsv1.JSON{
+					mustMarshalRoute(t, Route{
+						Receiver: "slack",
+						Matchers: []Matcher{{Name: "team", Value: "infra.*", MatchType: MatchRegexp}},
+					}),
+				},
+			},
+			Receivers: []Receiver{
+				{
+					Name:            "pagerduty",
+					SecretNamespace: "shared-credentials",
+					PagerDutyConfigs: []PagerDutyConfig{
+						{
+							RoutingKey: &SecretKeySelector{Name: "pd", Key: "routing-key"},
+							HTTPConfig: &HTTPConfig{
+								BearerTokenSecret: &SecretKeySelector{Name: "pd", Key: "token"},
+							},
+						},
+					},
+				},
+				{
+					Name: "slack",
+					SlackConfigs: []SlackConfig{
+						{
+							APIURL: &SecretKeySelector{Name: "slack", Key: "url"},
+							Actions: []SlackAction{
+								{
+									Type:         "button",
+									Text:         "Silence",
+									Name:         "silence",
+									ConfirmField: &SlackConfirmationField{Text: "Are you sure?"},
+								},
+							},
+						},
+					},
+				},
+			},
+			InhibitRules: []InhibitRule{
+				{
+					SourceMatch: []Matcher{{Name: "severity", Value: "critical", MatchType: MatchEqual}},
+					TargetMatch: []Matcher{{Name: "severity", Value: "warning|info", MatchType: MatchRegexp}},
+					Equal:       []string{"job"},
+				},
+				{
+					SourceMatch:    []Matcher{{Name: "alertname", Value: "NodeDown", MatchType: MatchEqual}},
+					Equal:          []string{"node"},
+					CrossNamespace: true,
+				},
+			},
+			TimeIntervals: []TimeInterval{
+				{
+					Name: "business-hours",
+					TimeIntervals: []TimePeriod{
+						{
+							Times:       []TimeRange{{StartTime: "09:00", EndTime: "17:00"}},
+							Weekdays:    []WeekdayRange{"monday:friday"},
+							DaysOfMonth: []DayOfMonthRange{{Start: 1, End: -1}},
+							Months:      []MonthRange{"january:march"},
+							Years:       []YearRange{"2023"},
+							Location:    "Europe/Paris",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dst := &AlertmanagerConfig{}
+	if err := dst.ConvertFrom(src); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(expected, dst) {
+		exp, _ := json.Marshal(expected)
+		got, _ := json.Marshal(dst)
+		t.Fatalf("expected %s\ngot %s", exp, got)
+	}
+
+	// Converting back should return the original object.
+	back := &v1alpha1.AlertmanagerConfig{}
+	if err := dst.ConvertTo(back); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(src, back) {
+		exp, _ := json.Marshal(src)
+		got, _ := json.Marshal(back)
+		t.Fatalf("expected %s\ngot %s", exp, got)
+	}
+}
+
+func TestConvertTo(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec AlertmanagerConfigSpec
+		ok   bool
+	}{
+		{
+			name: "empty",
+			ok:   true,
+		},
+		{
+			name: "default match type",
+			spec: AlertmanagerConfigSpec{
+				Route: &Route{
+					Receiver: "webhook",
+					Matchers: []Matcher{{Name: "severity", Value: "critical"}},
+				},
+				Receivers: []Receiver{
+					{
+						Name: "webhook",
+						WebhookConfigs: []WebhookConfig{
+							{
+								URLSecret: &SecretKeySelector{Name: "webhook", Key: "url"},
+								HTTPConfig: &HTTPConfig{
+									BasicAuth: &monitoringv1.BasicAuth{
+										Username: *secretKeySelector("webhook", "username"),
+										Password: *secretKeySelector("webhook", "password"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "negative matcher",
+			spec: AlertmanagerConfigSpec{
+				Route: &Route{
+					Matchers: []Matcher{{Name: "severity", Value: "info", MatchType: MatchNotEqual}},
+				},
+			},
+		},
+		{
+			name: "negative matcher in child route",
+			spec: AlertmanagerConfigSpec{
+				Route: &Route{
+					Routes: []apiextensionsv1.JSON{
+						mustMarshalRoute(t, Route{
+							Matchers: []Matcher{{Name: "severity", Value: "info", MatchType: MatchNotRegexp}},
+						}),
+					},
+				},
+			},
+		},
+		{
+			name: "negative matcher in inhibition rule",
+			spec: AlertmanagerConfigSpec{
+				InhibitRules: []InhibitRule{
+					{
+						SourceMatch: []Matcher{{Name: "severity", Value: "info", MatchType: MatchNotEqual}},
+					},
+				},
+			},
+		},
+		{
+			name: "time intervals",
+			spec: AlertmanagerConfigSpec{
+				TimeIntervals: []TimeInterval{
+					{
+						Name: "weekends",
+						TimeIntervals: []TimePeriod{
+							{Weekdays: []WeekdayRange{"saturday:sunday"}},
+						},
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "mute time intervals",
+			spec: AlertmanagerConfigSpec{
+				Route: &Route{
+					MuteTimeIntervals: []string{"weekends"},
+				},
+			},
+			ok: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			src := &AlertmanagerConfig{Spec: tc.spec}
+			err := src.ConvertTo(&v1alpha1.AlertmanagerConfig{})
+
+			if tc.ok {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected error, got none")
+			}
+		})
+	}
+}
@@ -0,0 +1,521 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+
+	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// ConvertTo converts from this version to the v1alpha1 version.
+// It fails if the object uses features which can't be represented with
+// v1alpha1 (negative matchers).
+func (src *AlertmanagerConfig) ConvertTo(dst *v1alpha1.AlertmanagerConfig) error {
+	dst.ObjectMeta = src.ObjectMeta
+	dst.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	dst.Kind = v1alpha1.AlertmanagerConfigKind
+
+	for _, in := range src.Spec.Receivers {
+		dst.Spec.Receivers = append(dst.Spec.Receivers, convertReceiverTo(in))
+	}
+
+	if src.Spec.Route != nil {
+		r, err := convertRouteTo(src.Spec.Route)
+		if err != nil {
+			return fmt.Errorf("route: %w", err)
+		}
+		dst.Spec.Route = r
+	}
+
+	for i, in := range src.Spec.InhibitRules {
+		targetMatch, err := convertMatchersTo(in.TargetMatch)
+		if err != nil {
+			return fmt.Errorf("inhibitRules[%d]: targetMatch: %w", i, err)
+		}
+
+		sourceMatch, err := convertMatchersTo(in.SourceMatch)
+		if err != nil {
+			return fmt.Errorf("inhibitRules[%d]: sourceMatch: %w", i, err)
+		}
+
+		dst.Spec.InhibitRules = append(dst.Spec.InhibitRules, v1alpha1.InhibitRule{
+			TargetMatch:    targetMatch,
+			SourceMatch:    sourceMatch,
+			Equal:          in.Equal,
+			CrossNamespace: in.CrossNamespace,
+		})
+	}
+
+	for _, in := range src.Spec.TimeIntervals {
+		dst.Spec.MuteTimeIntervals = append(dst.Spec.MuteTimeIntervals, convertTimeIntervalTo(in))
+	}
+
+	return nil
+}
+
+func convertTimeIntervalTo(in TimeInterval) v1alpha1.MuteTimeInterval {
+	out := v1alpha1.MuteTimeInterval{
+		Name: in.Name,
+	}
+
+	for _, tp := range in.TimeIntervals {
+		ti := v1alpha1.TimeInterval{
+			Location: tp.Location,
+		}
+
+		for _, tr := range tp.Times {
+			ti.Times = append(ti.Times, v1alpha1.TimeRange{
+				StartTime: v1alpha1.Time(tr.StartTime),
+				EndTime:   v1alpha1.Time(tr.EndTime),
+			})
+		}
+
+		for _, wd := range tp.Weekdays {
+			ti.Weekdays = append(ti.Weekdays, v1alpha1.WeekdayRange(wd))
+		}
+
+		for _, dm := range tp.DaysOfMonth {
+			ti.DaysOfMonth = append(ti.DaysOfMonth, v1alpha1.DayOfMonthRange{
+				Start: dm.Start,
+				End:   dm.End,
+			})
+		}
+
+		for _, m := range tp.Months {
+			ti.Months = append(ti.Months, v1alpha1.MonthRange(m))
+		}
+
+		for _, y := range tp.Years {
+			ti.Years = append(ti.Years, v1alpha1.YearRange(y))
+		}
+
+		out.TimeIntervals = append(out.TimeIntervals, ti)
+	}
+
+	return out
+}
+
+func convertRouteTo(in *Route) (*v1alpha1.Route, error) {
+	matchers, err := convertMatchersTo(in.Matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &v1alpha1.Route{
+		Receiver:            in.Receiver,
+		GroupBy:             in.GroupBy,
+		GroupWait:           in.GroupWait,
+		GroupInterval:       in.GroupInterval,
+		RepeatInterval:      in.RepeatInterval,
+		Matchers:            matchers,
+		Continue:            in.Continue,
+		MuteTimeIntervals:   in.MuteTimeIntervals,
+		ActiveTimeIntervals: in.ActiveTimeIntervals,
+	}
+
+	children, err := in.ChildRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range children {
+		child, err := convertRouteTo(&children[i])
+		if err != nil {
+			return nil, fmt.Errorf("route[%d]: %w", i, err)
+		}
+
+		b, err := json.Marshal(child)
+		if err != nil {
+			return nil, fmt.Errorf("route[%d]: %w", i, err)
+		}
+
+		out.Routes = append(out.Routes, apiextensionsv1.JSON{Raw: b})
+	}
+
+	return out, nil
+}
+
+func convertMatchersTo(in []Matcher) ([]v1alpha1.Matcher, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := make([]v1alpha1.Matcher, 0, len(in))
+	for _, m := range in {
+		var regex bool
+		switch m.MatchType {
+		case "", MatchEqual:
+		case MatchRegexp:
+			regex = true
+		default:
+			return nil, fmt.Errorf("matcher %q: match type %q isn't supported by the v1alpha1 version", m.Name, m.MatchType)
+		}
+
+		out = append(out, v1alpha1.Matcher{
+			Name:  m.Name,
+			Value: m.Value,
+			Regex: regex,
+		})
+	}
+
+	return out, nil
+}
+
+func convertSecretKeySelectorTo(in *SecretKeySelector) *v1.SecretKeySelector {
+	if in == nil {
+		return nil
+	}
+
+	return &v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{
+			Name: in.Name,
+		},
+		Key: in.Key,
+	}
+}
+
+func convertKeyValuesTo(in []KeyValue) []v1alpha1.KeyValue {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]v1alpha1.KeyValue, 0, len(in))
+	for _, kv := range in {
+		out = append(out, v1alpha1.KeyValue(kv))
+	}
+
+	return out
+}
+
+func convertHTTPConfigTo(in *HTTPConfig) *v1alpha1.HTTPConfig {
+	if in == nil {
+		return nil
+	}
+
+	return &v1alpha1.HTTPConfig{
+		Authorization:     in.Authorization,
+		BasicAuth:         in.BasicAuth,
+		BearerTokenSecret: convertSecretKeySelectorTo(in.BearerTokenSecret),
+		TLSConfig:         in.TLSConfig,
+		ProxyURL:          in.ProxyURL,
+	}
+}
+
+func convertReceiverTo(in Receiver) v1alpha1.Receiver {
+	out := v1alpha1.Receiver{
+		Name:            in.Name,
+		SecretNamespace: in.SecretNamespace,
+	}
+
+	for _, c := range in.OpsGenieConfigs {
+		out.OpsGenieConfigs = append(out.OpsGenieConfigs, convertOpsGenieConfigTo(c))
+	}
+
+	for _, c := range in.PagerDutyConfigs {
+		out.PagerDutyConfigs = append(out.PagerDutyConfigs, convertPagerDutyConfigTo(c))
+	}
+
+	for _, c := range in.SlackConfigs {
+		out.SlackConfigs = append(out.SlackConfigs, convertSlackConfigTo(c))
+	}
+
+	for _, c := range in.WebhookConfigs {
+		out.WebhookConfigs = append(out.WebhookConfigs, convertWebhookConfigTo(c))
+	}
+
+	for _, c := range in.WeChatConfigs {
+		out.WeChatConfigs = append(out.WeChatConfigs, convertWeChatConfigTo(c))
+	}
+
+	for _, c := range in.EmailConfigs {
+		out.EmailConfigs = append(out.EmailConfigs, convertEmailConfigTo(c))
+	}
+
+	for _, c := range in.VictorOpsConfigs {
+		out.VictorOpsConfigs = append(out.VictorOpsConfigs, convertVictorOpsConfigTo(c))
+	}
+
+	for _, c := range in.PushoverConfigs {
+		out.PushoverConfigs = append(out.PushoverConfigs, convertPushoverConfigTo(c))
+	}
+
+	for _, c := range in.TelegramConfigs {
+		out.TelegramConfigs = append(out.TelegramConfigs, convertTelegramConfigTo(c))
+	}
+
+	for _, c := range in.DiscordConfigs {
+		out.DiscordConfigs = append(out.DiscordConfigs, convertDiscordConfigTo(c))
+	}
+
+	for _, c := range in.MSTeamsConfigs {
+		out.MSTeamsConfigs = append(out.MSTeamsConfigs, convertMSTeamsConfigTo(c))
+	}
+
+	for _, c := range in.MSTeamsV2Configs {
+		out.MSTeamsV2Configs = append(out.MSTeamsV2Configs, convertMSTeamsV2ConfigTo(c))
+	}
+
+	for _, c := range in.SNSConfigs {
+		out.SNSConfigs = append(out.SNSConfigs, convertSNSConfigTo(c))
+	}
+
+	for _, c := range in.WebexConfigs {
+		out.WebexConfigs = append(out.WebexConfigs, convertWebexConfigTo(c))
+	}
+
+	return out
+}
+
+func convertOpsGenieConfigTo(in OpsGenieConfig) v1alpha1.OpsGenieConfig {
+	out := v1alpha1.OpsGenieConfig{
+		SendResolved: in.SendResolved,
+		APIKey:       convertSecretKeySelectorTo(in.APIKey),
+		APIURL:       in.APIURL,
+		Message:      in.Message,
+		Description:  in.Description,
+		Source:       in.Source,
+		Tags:         in.Tags,
+		Note:         in.Note,
+		Priority:     in.Priority,
+		Details:      convertKeyValuesTo(in.Details),
+		HTTPConfig:   convertHTTPConfigTo(in.HTTPConfig),
+	}
+
+	for _, r := range in.Responders {
+		out.Responders = append(out.Responders, v1alpha1.OpsGenieConfigResponder(r))
+	}
+
+	return out
+}
+
+func convertPagerDutyConfigTo(in PagerDutyConfig) v1alpha1.PagerDutyConfig {
+	return v1alpha1.PagerDutyConfig{
+		SendResolved: in.SendResolved,
+		RoutingKey:   convertSecretKeySelectorTo(in.RoutingKey),
+		ServiceKey:   convertSecretKeySelectorTo(in.ServiceKey),
+		URL:          in.URL,
+		Client:       in.Client,
+		ClientURL:    in.ClientURL,
+		Description:  in.Description,
+		Severity:     in.Severity,
+		Class:        in.Class,
+		Group:        in.Group,
+		Component:    in.Component,
+		Details:      convertKeyValuesTo(in.Details),
+		HTTPConfig:   convertHTTPConfigTo(in.HTTPConfig),
+	}
+}
+
+func convertSlackConfigTo(in SlackConfig) v1alpha1.SlackConfig {
+	out := v1alpha1.SlackConfig{
+		SendResolved: in.SendResolved,
+		APIURL:       convertSecretKeySelectorTo(in.APIURL),
+		Channel:      in.Channel,
+		Username:     in.Username,
+		Color:        in.Color,
+		Title:        in.Title,
+		TitleLink:    in.TitleLink,
+		Pretext:      in.Pretext,
+		Text:         in.Text,
+		ShortFields:  in.ShortFields,
+		Footer:       in.Footer,
+		Fallback:     in.Fallback,
+		CallbackID:   in.CallbackID,
+		IconEmoji:    in.IconEmoji,
+		IconURL:      in.IconURL,
+		ImageURL:     in.ImageURL,
+		ThumbURL:     in.ThumbURL,
+		LinkNames:    in.LinkNames,
+		MrkdwnIn:     in.MrkdwnIn,
+		HTTPConfig:   convertHTTPConfigTo(in.HTTPConfig),
+	}
+
+	for _, f := range in.Fields {
+		out.Fields = append(out.Fields, v1alpha1.SlackField(f))
+	}
+
+	for _, a := range in.Actions {
+		action := v1alpha1.SlackAction{
+			Type:  a.Type,
+			Text:  a.Text,
+			URL:   a.URL,
+			Style: a.Style,
+			Name:  a.Name,
+			Value: a.Value,
+		}
+
+		if a.ConfirmField != nil {
+			cf := v1alpha1.SlackConfirmationField(*a.ConfirmField)
+			action.ConfirmField = &cf
+		}
+
+		out.Actions = append(out.Actions, action)
+	}
+
+	return out
+}
+
+func convertWebhookConfigTo(in WebhookConfig) v1alpha1.WebhookConfig {
+	return v1alpha1.WebhookConfig{
+		SendResolved: in.SendResolved,
+		URL:          in.URL,
+		URLSecret:    convertSecretKeySelectorTo(in.URLSecret),
+		HTTPConfig:   convertHTTPConfigTo(in.HTTPConfig),
+		MaxAlerts:    in.MaxAlerts,
+	}
+}
+
+func convertWeChatConfigTo(in WeChatConfig) v1alpha1.WeChatConfig {
+	return v1alpha1.WeChatConfig{
+		SendResolved: in.SendResolved,
+		APISecret:    convertSecretKeySelectorTo(in.APISecret),
+		APIURL:       in.APIURL,
+		CorpID:       in.CorpID,
+		AgentID:      in.AgentID,
+		ToUser:       in.ToUser,
+		ToParty:      in.ToParty,
+		ToTag:        in.ToTag,
+		Message:      in.Message,
+		MessageType:  in.MessageType,
+		HTTPConfig:   convertHTTPConfigTo(in.HTTPConfig),
+	}
+}
+
+func convertEmailConfigTo(in EmailConfig) v1alpha1.EmailConfig {
+	return v1alpha1.EmailConfig{
+		SendResolved: in.SendResolved,
+		To:           in.To,
+		From:         in.From,
+		Hello:        in.Hello,
+		Smarthost:    in.Smarthost,
+		AuthUsername: in.AuthUsername,
+		AuthPassword: convertSecretKeySelectorTo(in.AuthPassword),
+		AuthSecret:   convertSecretKeySelectorTo(in.AuthSecret),
+		AuthIdentity: in.AuthIdentity,
+		Headers:      convertKeyValuesTo(in.Headers),
+		HTML:         in.HTML,
+		Text:         in.Text,
+		RequireTLS:   in.RequireTLS,
+		TLSConfig:    in.TLSConfig,
+	}
+}
+
+func convertVictorOpsConfigTo(in VictorOpsConfig) v1alpha1.VictorOpsConfig {
+	return v1alpha1.VictorOpsConfig{
+		SendResolved:      in.SendResolved,
+		APIKey:            convertSecretKeySelectorTo(in.APIKey),
+		APIURL:            in.APIURL,
+		RoutingKey:        in.RoutingKey,
+		MessageType:       in.MessageType,
+		EntityDisplayName: in.EntityDisplayName,
+		StateMessage:      in.StateMessage,
+		MonitoringTool:    in.MonitoringTool,
+		CustomFields:      convertKeyValuesTo(in.CustomFields),
+		HTTPConfig:        convertHTTPConfigTo(in.HTTPConfig),
+	}
+}
+
+func convertPushoverConfigTo(in PushoverConfig) v1alpha1.PushoverConfig {
+	return v1alpha1.PushoverConfig{
+		SendResolved: in.SendResolved,
+		UserKey:      convertSecretKeySelectorTo(in.UserKey),
+		Token:        convertSecretKeySelectorTo(in.Token),
+		Title:        in.Title,
+		Message:      in.Message,
+		URL:          in.URL,
+		URLTitle:     in.URLTitle,
+		Sound:        in.Sound,
+		Priority:     in.Priority,
+		Retry:        in.Retry,
+		Expire:       in.Expire,
+		HTML:         in.HTML,
+		HTTPConfig:   convertHTTPConfigTo(in.HTTPConfig),
+	}
+}
+
+func convertTelegramConfigTo(in TelegramConfig) v1alpha1.TelegramConfig {
+	return v1alpha1.TelegramConfig{
+		SendResolved:         in.SendResolved,
+		APIURL:               in.APIURL,
+		BotToken:             convertSecretKeySelectorTo(in.BotToken),
+		ChatID:               in.ChatID,
+		Message:              in.Message,
+		DisableNotifications: in.DisableNotifications,
+		ParseMode:            in.ParseMode,
+		HTTPConfig:           convertHTTPConfigTo(in.HTTPConfig),
+	}
+}
+
+func convertDiscordConfigTo(in DiscordConfig) v1alpha1.DiscordConfig {
+	return v1alpha1.DiscordConfig{
+		SendResolved: in.SendResolved,
+		APIURL:       convertSecretKeySelectorTo(in.APIURL),
+		Title:        in.Title,
+		Message:      in.Message,
+		HTTPConfig:   convertHTTPConfigTo(in.HTTPConfig),
+	}
+}
+
+func convertMSTeamsConfigTo(in MSTeamsConfig) v1alpha1.MSTeamsConfig {
+	return v1alpha1.MSTeamsConfig{
+		SendResolved: in.SendResolved,
+		WebhookURL:   convertSecretKeySelectorTo(in.WebhookURL),
+		Title:        in.Title,
+		Summary:      in.Summary,
+		Text:         in.Text,
+		HTTPConfig:   convertHTTPConfigTo(in.HTTPConfig),
+	}
+}
+
+func convertMSTeamsV2ConfigTo(in MSTeamsV2Config) v1alpha1.MSTeamsV2Config {
+	return v1alpha1.MSTeamsV2Config{
+		SendResolved: in.SendResolved,
+		WebhookURL:   convertSecretKeySelectorTo(in.WebhookURL),
+		Title:        in.Title,
+		Text:         in.Text,
+		HTTPConfig:   convertHTTPConfigTo(in.HTTPConfig),
+	}
+}
+
+func convertSNSConfigTo(in SNSConfig) v1alpha1.SNSConfig {
+	return v1alpha1.SNSConfig{
+		SendResolved: in.SendResolved,
+		APIURL:       in.APIURL,
+		Sigv4:        in.Sigv4,
+		TopicARN:     in.TopicARN,
+		PhoneNumber:  in.PhoneNumber,
+		TargetARN:    in.TargetARN,
+		Subject:      in.Subject,
+		Message:      in.Message,
+		Attributes:   in.Attributes,
+		HTTPConfig:   convertHTTPConfigTo(in.HTTPConfig),
+	}
+}
+
+func convertWebexConfigTo(in WebexConfig) v1alpha1.WebexConfig {
+	return v1alpha1.WebexConfig{
+		SendResolved: in.SendResolved,
+		APIURL:       in.APIURL,
+		BotToken:     convertSecretKeySelectorTo(in.BotToken),
+		RoomID:       in.RoomID,
+		Message:      in.Message,
+		HTTPConfig:   convertHTTPConfigTo(in.HTTPConfig),
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"strings"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
@@ -47,6 +48,9 @@ type Store struct {
 	BasicAuthAssets map[string]BasicAuthCredentials
 	OAuth2Assets    map[string]OAuth2Credentials
 	SigV4Assets     map[string]SigV4Credentials
+
+	ProxyConnectHeaderAssets map[string]ProxyConnectHeader
+	ProbeTargetsAssets       map[string][]string
 }
 
 // NewStore returns an empty assetStore.
@@ -60,6 +64,9 @@ func NewStore(cmClient corev1client.ConfigMapsGetter, sClient corev1client.Secre
 		OAuth2Assets:    make(map[string]OAuth2Credentials),
 		SigV4Assets:     make(map[string]SigV4Credentials),
 		objStore:        cache.NewStore(assetKeyFunc),
+
+		ProxyConnectHeaderAssets: make(map[string]ProxyConnectHeader),
+		ProbeTargetsAssets:       make(map[string][]string),
 	}
 }
 
@@ -288,6 +295,72 @@ func (s *Store) AddSigV4(ctx context.Context, ns string, sigv4 *monitoringv1.Sig
 	return nil
 }
 
+// AddAzureOAuth processes the Azure OAuth client secret and adds it to the
+// store.
+func (s *Store) AddAzureOAuth(ctx context.Context, ns string, azureAD *monitoringv1.AzureAD, key string) error {
+	if azureAD == nil || azureAD.OAuth == nil {
+		return nil
+	}
+
+	if err := s.addToken(ctx, ns, azureAD.OAuth.ClientSecret, key); err != nil {
+		return errors.Wrap(err, "failed to read Azure OAuth client secret")
+	}
+
+	return nil
+}
+
+// AddProxyConfig processes the secrets referenced by the proxy connect headers
+// and adds their values to the store.
+func (s *Store) AddProxyConfig(ctx context.Context, ns string, pc monitoringv1.ProxyConfig, key string) error {
+	if len(pc.ProxyConnectHeader) == 0 {
+		return nil
+	}
+
+	header := make(ProxyConnectHeader, len(pc.ProxyConnectHeader))
+	for name, sels := range pc.ProxyConnectHeader {
+		values := make([]string, 0, len(sels))
+		for _, sel := range sels {
+			v, err := s.GetSecretKey(ctx, ns, sel)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read proxy connect header %q", name)
+			}
+			values = append(values, v)
+		}
+		header[name] = values
+	}
+
+	s.ProxyConnectHeaderAssets[key] = header
+
+	return nil
+}
+
+// AddProbeTargets reads the targets referenced by the given selector and adds
+// them to the store. The value holds one target per line, empty lines and
+// lines starting with '#' are ignored.
+func (s *Store) AddProbeTargets(ctx context.Context, ns string, sel monitoringv1.SecretOrConfigMap, key string) error {
+	if err := sel.Validate(); err != nil {
+		return errors.Wrap(err, "invalid static targets source")
+	}
+
+	data, err := s.GetKey(ctx, ns, sel)
+	if err != nil {
+		return errors.Wrap(err, "failed to read static targets")
+	}
+
+	var targets []string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		targets = append(targets, line)
+	}
+
+	s.ProbeTargetsAssets[key] = targets
+
+	return nil
+}
+
 // GetKey processes the given SecretOrConfigMap selector and returns the referenced data.
 func (s *Store) GetKey(ctx context.Context, namespace string, sel monitoringv1.SecretOrConfigMap) (string, error) {
 	switch {
@@ -1039,3 +1039,300 @@ func TestAddSigV4(t *testing.T) {
 		})
 	}
 }
+
+func TestAddAzureOAuth(t *testing.T) {
+	c := fake.NewSimpleClientset(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "secret",
+				Namespace: "ns1",
+			},
+			Data: map[string][]byte{
+				"clientSecret": []byte("val1"),
+			},
+		},
+	)
+
+	for i, tc := range []struct {
+		title   string
+		ns      string
+		azureAD *monitoringv1.AzureAD
+
+		err      bool
+		expected string
+	}{
+		{
+			title: "nil Azure AD",
+			ns:    "ns1",
+		},
+		{
+			title: "managed identity",
+			ns:    "ns1",
+			azureAD: &monitoringv1.AzureAD{
+				ManagedIdentity: &monitoringv1.ManagedIdentity{ClientID: "client-id"},
+			},
+		},
+		{
+			title: "valid client secret",
+			ns:    "ns1",
+			azureAD: &monitoringv1.AzureAD{
+				OAuth: &monitoringv1.AzureOAuth{
+					ClientID: "client-id",
+					ClientSecret: v1.SecretKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+						Key:                  "clientSecret",
+					},
+					TenantID: "tenant-id",
+				},
+			},
+
+			expected: "val1",
+		},
+		{
+			title: "wrong namespace",
+			ns:    "ns2",
+			azureAD: &monitoringv1.AzureAD{
+				OAuth: &monitoringv1.AzureOAuth{
+					ClientSecret: v1.SecretKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+						Key:                  "clientSecret",
+					},
+				},
+			},
+
+			err: true,
+		},
+		{
+			title: "wrong key selector",
+			ns:    "ns1",
+			azureAD: &monitoringv1.AzureAD{
+				OAuth: &monitoringv1.AzureOAuth{
+					ClientSecret: v1.SecretKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+						Key:                  "wrong",
+					},
+				},
+			},
+
+			err: true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			store := NewStore(c.CoreV1(), c.CoreV1())
+
+			key := fmt.Sprintf("remoteWrite/azuread/%d", i)
+			err := store.AddAzureOAuth(context.Background(), tc.ns, tc.azureAD, key)
+
+			if tc.err {
+				if err == nil {
+					t.Fatal("expecting error, got no error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expecting no error, got %q", err)
+			}
+
+			if got := string(store.TokenAssets[key]); got != tc.expected {
+				t.Fatalf("expecting %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAddProxyConfig(t *testing.T) {
+	c := fake.NewSimpleClientset(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "secret",
+				Namespace: "ns1",
+			},
+			Data: map[string][]byte{
+				"user":  []byte("val1"),
+				"token": []byte("val2"),
+			},
+		},
+	)
+
+	selector := func(key string) v1.SecretKeySelector {
+		return v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+			Key:                  key,
+		}
+	}
+
+	for _, tc := range []struct {
+		title       string
+		ns          string
+		proxyConfig monitoringv1.ProxyConfig
+
+		err      bool
+		expected ProxyConnectHeader
+	}{
+		{
+			title: "no proxy connect header",
+			ns:    "ns1",
+		},
+		{
+			title: "valid proxy connect header",
+			ns:    "ns1",
+			proxyConfig: monitoringv1.ProxyConfig{
+				ProxyConnectHeader: map[string][]v1.SecretKeySelector{
+					"X-User":  {selector("user")},
+					"X-Token": {selector("token"), selector("user")},
+				},
+			},
+
+			expected: ProxyConnectHeader{
+				"X-User":  {"val1"},
+				"X-Token": {"val2", "val1"},
+			},
+		},
+		{
+			title: "wrong namespace",
+			ns:    "ns2",
+			proxyConfig: monitoringv1.ProxyConfig{
+				ProxyConnectHeader: map[string][]v1.SecretKeySelector{
+					"X-User": {selector("user")},
+				},
+			},
+
+			err: true,
+		},
+		{
+			title: "wrong key selector",
+			ns:    "ns1",
+			proxyConfig: monitoringv1.ProxyConfig{
+				ProxyConnectHeader: map[string][]v1.SecretKeySelector{
+					"X-User": {selector("wrong")},
+				},
+			},
+
+			err: true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			store := NewStore(c.CoreV1(), c.CoreV1())
+
+			err := store.AddProxyConfig(context.Background(), tc.ns, tc.proxyConfig, "remoteWrite/0")
+
+			if tc.err {
+				if err == nil {
+					t.Fatal("expecting error, got no error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expecting no error, got %q", err)
+			}
+
+			if got := store.ProxyConnectHeaderAssets["remoteWrite/0"]; !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expecting %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAddProbeTargets(t *testing.T) {
+	c := fake.NewSimpleClientset(
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "targets",
+				Namespace: "ns1",
+			},
+			Data: map[string]string{
+				"targets.txt": "# Public endpoints\nhttps://example.com\n\n  https://example.org  \n",
+			},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "targets",
+				Namespace: "ns1",
+			},
+			Data: map[string][]byte{
+				"targets.txt": []byte("https://internal.example.com"),
+			},
+		},
+	)
+
+	for _, tc := range []struct {
+		title    string
+		ns       string
+		selector monitoringv1.SecretOrConfigMap
+
+		err      bool
+		expected []string
+	}{
+		{
+			title: "targets from configmap",
+			ns:    "ns1",
+			selector: monitoringv1.SecretOrConfigMap{
+				ConfigMap: &v1.ConfigMapKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "targets"},
+					Key:                  "targets.txt",
+				},
+			},
+			expected: []string{"https://example.com", "https://example.org"},
+		},
+		{
+			title: "targets from secret",
+			ns:    "ns1",
+			selector: monitoringv1.SecretOrConfigMap{
+				Secret: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "targets"},
+					Key:                  "targets.txt",
+				},
+			},
+			expected: []string{"https://internal.example.com"},
+		},
+		{
+			title: "wrong namespace",
+			ns:    "ns2",
+			selector: monitoringv1.SecretOrConfigMap{
+				ConfigMap: &v1.ConfigMapKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "targets"},
+					Key:                  "targets.txt",
+				},
+			},
+			err: true,
+		},
+		{
+			title: "both secret and configmap",
+			ns:    "ns1",
+			selector: monitoringv1.SecretOrConfigMap{
+				Secret: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "targets"},
+					Key:                  "targets.txt",
+				},
+				ConfigMap: &v1.ConfigMapKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "targets"},
+					Key:                  "targets.txt",
+				},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			store := NewStore(c.CoreV1(), c.CoreV1())
+
+			err := store.AddProbeTargets(context.Background(), tc.ns, tc.selector, "probe/ns1/probe")
+
+			if tc.err {
+				if err == nil {
+					t.Fatal("expecting error, got no error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expecting no error, got %q", err)
+			}
+
+			if got := store.ProbeTargetsAssets["probe/ns1/probe"]; !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expecting %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
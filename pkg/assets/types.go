@@ -42,3 +42,7 @@ type SigV4Credentials struct {
 	AccessKeyID string
 	SecretKeyID string
 }
+
+// ProxyConnectHeader represents the headers sent to a proxy during CONNECT
+// requests.
+type ProxyConnectHeader map[string][]string
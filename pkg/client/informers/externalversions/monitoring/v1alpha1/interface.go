@@ -24,6 +24,10 @@ import (
 type Interface interface {
 	// AlertmanagerConfigs returns a AlertmanagerConfigInformer.
 	AlertmanagerConfigs() AlertmanagerConfigInformer
+	// PrometheusAgents returns a PrometheusAgentInformer.
+	PrometheusAgents() PrometheusAgentInformer
+	// ScrapeConfigs returns a ScrapeConfigInformer.
+	ScrapeConfigs() ScrapeConfigInformer
 }
 
 type version struct {
@@ -41,3 +45,13 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) AlertmanagerConfigs() AlertmanagerConfigInformer {
 	return &alertmanagerConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// PrometheusAgents returns a PrometheusAgentInformer.
+func (v *version) PrometheusAgents() PrometheusAgentInformer {
+	return &prometheusAgentInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ScrapeConfigs returns a ScrapeConfigInformer.
+func (v *version) ScrapeConfigs() ScrapeConfigInformer {
+	return &scrapeConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
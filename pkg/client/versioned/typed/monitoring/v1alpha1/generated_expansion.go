@@ -17,3 +17,7 @@
 package v1alpha1
 
 type AlertmanagerConfigExpansion interface{}
+
+type PrometheusAgentExpansion interface{}
+
+type ScrapeConfigExpansion interface{}
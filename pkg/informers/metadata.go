@@ -0,0 +1,59 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+)
+
+// NewMetadataInformerFactories creates factories for metadata-only informers
+// for the given allowed, and denied namespaces these parameters being mutually exclusive.
+// The informers only cache the object metadata (as
+// *metav1.PartialObjectMetadata objects) which reduces the memory usage for
+// resources with large payloads such as Secrets and ConfigMaps.
+// metadataClient, defaultResync, and tweakListOptions are being passed to the underlying informer factory.
+func NewMetadataInformerFactories(
+	allowNamespaces, denyNamespaces map[string]struct{},
+	metadataClient metadata.Interface,
+	defaultResync time.Duration,
+	tweakListOptions func(*metav1.ListOptions),
+) FactoriesForNamespaces {
+	tweaks, namespaces := newInformerOptions(
+		allowNamespaces, denyNamespaces, tweakListOptions,
+	)
+
+	ret := metadataInformersForNamespaces{}
+	for _, namespace := range namespaces {
+		ret[namespace] = metadatainformer.NewFilteredSharedInformerFactory(metadataClient, defaultResync, namespace, tweaks)
+	}
+
+	return ret
+}
+
+type metadataInformersForNamespaces map[string]metadatainformer.SharedInformerFactory
+
+func (i metadataInformersForNamespaces) Namespaces() sets.String {
+	return sets.StringKeySet(i)
+}
+
+func (i metadataInformersForNamespaces) ForResource(namespace string, resource schema.GroupVersionResource) (InformLister, error) {
+	return i[namespace].ForResource(resource), nil
+}
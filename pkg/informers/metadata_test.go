@@ -0,0 +1,94 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informers
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/metadata/fake"
+)
+
+func TestMetadataInformers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := metav1.AddMetaToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	newSecret := func(ns, name string, labels map[string]string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ns,
+				Name:      name,
+				Labels:    labels,
+			},
+		}
+	}
+
+	client := fake.NewSimpleMetadataClient(
+		scheme,
+		newSecret("default", "foo", map[string]string{"app": "foo"}),
+		newSecret("other", "bar", nil),
+	)
+
+	ifs, err := NewInformersForResource(
+		NewMetadataInformerFactories(
+			map[string]struct{}{"default": {}},
+			nil,
+			client,
+			time.Minute,
+			nil,
+		),
+		v1.SchemeGroupVersion.WithResource(string(v1.ResourceSecrets)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	ifs.Start(stopCh)
+
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return ifs.HasSynced(), nil
+	}); err != nil {
+		t.Fatalf("informers didn't sync: %v", err)
+	}
+
+	obj, err := ifs.Get("default/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o, ok := obj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		t.Fatalf("expected *metav1.PartialObjectMetadata, got %T", obj)
+	}
+	if o.Labels["app"] != "foo" {
+		t.Fatalf("expected label app=foo, got %v", o.Labels)
+	}
+
+	if _, err := ifs.Get("other/bar"); err == nil {
+		t.Fatal("expected the Secret of the unwatched namespace to be ignored")
+	}
+}
@@ -16,6 +16,7 @@ package k8sutil
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -27,21 +28,26 @@ import (
 
 	"github.com/hashicorp/go-version"
 	v1 "k8s.io/api/core/v1"
-	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/discovery"
 	clientappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
 	clientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/retry"
 )
 
 // KubeConfigEnv (optionally) specify the location of kubeconfig file
 const KubeConfigEnv = "KUBECONFIG"
 
+// FieldManager is the name of the field manager used by the operator to apply
+// the resources it manages.
+const FieldManager = "prometheus-operator"
+
 var invalidDNS1123Characters = regexp.MustCompile("[^-a-z0-9]+")
 
 // PodRunningAndReady returns whether a pod is running and each container has
@@ -110,92 +116,168 @@ func IsResourceNotFoundError(err error) bool {
 	return false
 }
 
-func CreateOrUpdateService(ctx context.Context, sclient clientv1.ServiceInterface, svc *v1.Service) error {
-	// As stated in the RetryOnConflict's documentation, the returned error shouldn't be wrapped.
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		service, err := sclient.Get(ctx, svc.Name, metav1.GetOptions{})
-		if err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
+// ConflictHandler is called when applying a resource conflicts with fields
+// managed by another field manager. The operator doesn't force the ownership
+// of the conflicting fields hence the resource isn't applied.
+type ConflictHandler func(err error)
 
-			_, err = sclient.Create(ctx, svc, metav1.CreateOptions{})
-			return err
-		}
+// applyClient gets and patches a given object.
+type applyClient struct {
+	get   func(context.Context) (metav1.Object, error)
+	patch func(context.Context, types.PatchType, []byte, metav1.PatchOptions) (metav1.Object, error)
+}
 
-		// Apply immutable fields from the existing service.
-		svc.Spec.IPFamilies = service.Spec.IPFamilies
-		svc.Spec.IPFamilyPolicy = service.Spec.IPFamilyPolicy
-		svc.Spec.ClusterIP = service.Spec.ClusterIP
-		svc.Spec.ClusterIPs = service.Spec.ClusterIPs
+// apply sends obj to the API server with server-side apply.
+//
+// The fields set by the client-side updates of previous versions of the
+// operator are first transferred to the operator's apply field manager (see
+// upgradeManagedFields). Conflicts with other field managers aren't forced:
+// they are passed to onConflict (if not nil) and returned.
+func apply(ctx context.Context, obj runtime.Object, gvk schema.GroupVersionKind, onConflict ConflictHandler, c applyClient) error {
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", gvk.Kind, err)
+	}
 
-		svc.SetOwnerReferences(mergeOwnerReferences(service.GetOwnerReferences(), svc.GetOwnerReferences()))
-		mergeMetadata(&svc.ObjectMeta, service.ObjectMeta)
+	opts := metav1.PatchOptions{FieldManager: FieldManager}
 
-		_, err = sclient.Update(ctx, svc, metav1.UpdateOptions{})
+	var conflictErr error
+	current, err := c.patch(ctx, types.ApplyPatchType, data, opts)
+	if apierrors.IsConflict(err) {
+		// The conflicting fields may have been set by a previous version of
+		// the operator.
+		conflictErr = err
+		current, err = c.get(ctx)
+	}
+	if err != nil {
 		return err
-	})
-}
+	}
 
-func CreateOrUpdateEndpoints(ctx context.Context, eclient clientv1.EndpointsInterface, eps *v1.Endpoints) error {
-	// As stated in the RetryOnConflict's documentation, the returned error shouldn't be wrapped.
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		endpoints, err := eclient.Get(ctx, eps.Name, metav1.GetOptions{})
-		if err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
+	entries, err := upgradeManagedFields(current.GetManagedFields())
+	if err != nil {
+		return err
+	}
+	if entries == nil {
+		return reportConflict(conflictErr, onConflict)
+	}
 
-			_, err = eclient.Create(ctx, eps, metav1.CreateOptions{})
-			return err
-		}
+	patch, err := managedFieldsPatch(current, entries)
+	if err != nil {
+		return err
+	}
+	if _, err := c.patch(ctx, types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to upgrade the managed fields of %s: %w", gvk.Kind, err)
+	}
 
-		mergeMetadata(&eps.ObjectMeta, endpoints.ObjectMeta)
+	// Apply again now that the operator owns the fields of its previous
+	// updates: the fields which aren't part of the configuration anymore are
+	// removed.
+	_, err = c.patch(ctx, types.ApplyPatchType, data, opts)
+	if apierrors.IsConflict(err) {
+		return reportConflict(err, onConflict)
+	}
 
-		_, err = eclient.Update(ctx, eps, metav1.UpdateOptions{})
-		return err
-	})
+	return err
 }
 
-// UpdateStatefulSet merges metadata of existing StatefulSet with new one and updates it.
-func UpdateStatefulSet(ctx context.Context, sstClient clientappsv1.StatefulSetInterface, sset *appsv1.StatefulSet) error {
-	// As stated in the RetryOnConflict's documentation, the returned error shouldn't be wrapped.
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		existingSset, err := sstClient.Get(ctx, sset.Name, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
+func reportConflict(err error, onConflict ConflictHandler) error {
+	if err != nil && onConflict != nil {
+		onConflict(err)
+	}
 
-		mergeMetadata(&sset.ObjectMeta, existingSset.ObjectMeta)
-		// Propagate annotations set by kubectl on spec.template.annotations. e.g performing a rolling restart.
-		mergeKubectlAnnotations(&sset.Spec.Template.ObjectMeta, existingSset.Spec.Template.ObjectMeta)
+	return err
+}
 
-		_, err = sstClient.Update(ctx, sset, metav1.UpdateOptions{})
+// ApplyService applies the Service with server-side apply. The fields which
+// aren't set (e.g. the cluster IPs) keep the values allocated by the API
+// server. The owner references of the existing Service are preserved since
+// the governing Services are shared by all the resources of a namespace.
+func ApplyService(ctx context.Context, sclient clientv1.ServiceInterface, svc *v1.Service, onConflict ConflictHandler) error {
+	service, err := sclient.Get(ctx, svc.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
 		return err
+	}
+	if err == nil {
+		svc.SetOwnerReferences(mergeOwnerReferences(service.GetOwnerReferences(), svc.GetOwnerReferences()))
+	}
+
+	// The protocol is part of the key identifying the ports.
+	for i := range svc.Spec.Ports {
+		if svc.Spec.Ports[i].Protocol == "" {
+			svc.Spec.Ports[i].Protocol = v1.ProtocolTCP
+		}
+	}
+
+	return apply(ctx, svc, v1.SchemeGroupVersion.WithKind("Service"), onConflict, applyClient{
+		get: func(ctx context.Context) (metav1.Object, error) {
+			return sclient.Get(ctx, svc.Name, metav1.GetOptions{})
+		},
+		patch: func(ctx context.Context, pt types.PatchType, data []byte, opts metav1.PatchOptions) (metav1.Object, error) {
+			return sclient.Patch(ctx, svc.Name, pt, data, opts)
+		},
 	})
 }
 
-// CreateOrUpdateSecret merges metadata of existing Secret with new one and updates it.
-func CreateOrUpdateSecret(ctx context.Context, secretClient clientv1.SecretInterface, desired *v1.Secret) error {
-	// As stated in the RetryOnConflict's documentation, the returned error shouldn't be wrapped.
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		existingSecret, err := secretClient.Get(ctx, desired.Name, metav1.GetOptions{})
-		if err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
+// ApplyEndpoints applies the Endpoints with server-side apply.
+func ApplyEndpoints(ctx context.Context, eclient clientv1.EndpointsInterface, eps *v1.Endpoints, onConflict ConflictHandler) error {
+	return apply(ctx, eps, v1.SchemeGroupVersion.WithKind("Endpoints"), onConflict, applyClient{
+		get: func(ctx context.Context) (metav1.Object, error) {
+			return eclient.Get(ctx, eps.Name, metav1.GetOptions{})
+		},
+		patch: func(ctx context.Context, pt types.PatchType, data []byte, opts metav1.PatchOptions) (metav1.Object, error) {
+			return eclient.Patch(ctx, eps.Name, pt, data, opts)
+		},
+	})
+}
 
-			_, err = secretClient.Create(ctx, desired, metav1.CreateOptions{})
-			return err
+// ApplyStatefulSet applies the StatefulSet with server-side apply. The labels
+// and annotations set by other field managers (e.g. the
+// kubectl.kubernetes.io/restartedAt annotation of the pod template) are
+// preserved.
+func ApplyStatefulSet(ctx context.Context, sstClient clientappsv1.StatefulSetInterface, sset *appsv1.StatefulSet, onConflict ConflictHandler) error {
+	// The protocol is part of the key identifying the container ports.
+	for _, containers := range [][]v1.Container{sset.Spec.Template.Spec.InitContainers, sset.Spec.Template.Spec.Containers} {
+		for i := range containers {
+			for j := range containers[i].Ports {
+				if containers[i].Ports[j].Protocol == "" {
+					containers[i].Ports[j].Protocol = v1.ProtocolTCP
+				}
+			}
 		}
+	}
 
-		mutated := existingSecret.DeepCopyObject().(*v1.Secret)
-		mergeMetadata(&desired.ObjectMeta, mutated.ObjectMeta)
-		if apiequality.Semantic.DeepEqual(existingSecret, desired) {
-			return nil
-		}
-		_, err = secretClient.Update(ctx, desired, metav1.UpdateOptions{})
-		return err
+	return apply(ctx, sset, appsv1.SchemeGroupVersion.WithKind("StatefulSet"), onConflict, applyClient{
+		get: func(ctx context.Context) (metav1.Object, error) {
+			return sstClient.Get(ctx, sset.Name, metav1.GetOptions{})
+		},
+		patch: func(ctx context.Context, pt types.PatchType, data []byte, opts metav1.PatchOptions) (metav1.Object, error) {
+			return sstClient.Patch(ctx, sset.Name, pt, data, opts)
+		},
+	})
+}
+
+// ApplySecret applies the Secret with server-side apply.
+func ApplySecret(ctx context.Context, secretClient clientv1.SecretInterface, secret *v1.Secret, onConflict ConflictHandler) error {
+	return apply(ctx, secret, v1.SchemeGroupVersion.WithKind("Secret"), onConflict, applyClient{
+		get: func(ctx context.Context) (metav1.Object, error) {
+			return secretClient.Get(ctx, secret.Name, metav1.GetOptions{})
+		},
+		patch: func(ctx context.Context, pt types.PatchType, data []byte, opts metav1.PatchOptions) (metav1.Object, error) {
+			return secretClient.Patch(ctx, secret.Name, pt, data, opts)
+		},
+	})
+}
+
+// ApplyConfigMap applies the ConfigMap with server-side apply.
+func ApplyConfigMap(ctx context.Context, cmClient clientv1.ConfigMapInterface, cm *v1.ConfigMap, onConflict ConflictHandler) error {
+	return apply(ctx, cm, v1.SchemeGroupVersion.WithKind("ConfigMap"), onConflict, applyClient{
+		get: func(ctx context.Context) (metav1.Object, error) {
+			return cmClient.Get(ctx, cm.Name, metav1.GetOptions{})
+		},
+		patch: func(ctx context.Context, pt types.PatchType, data []byte, opts metav1.PatchOptions) (metav1.Object, error) {
+			return cmClient.Patch(ctx, cm.Name, pt, data, opts)
+		},
 	})
 }
 
@@ -214,6 +296,27 @@ func GetMinorVersion(dclient discovery.DiscoveryInterface) (int, error) {
 	return ver.Segments()[1], nil
 }
 
+// IsAPIGroupVersionResourceSupported returns whether the given resource is
+// served by the API server for the given group version (e.g.
+// "monitoring.coreos.com/v1alpha1").
+func IsAPIGroupVersionResourceSupported(dclient discovery.DiscoveryInterface, groupVersion string, resource string) (bool, error) {
+	apiResourceList, err := dclient.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, apiResource := range apiResourceList.APIResources {
+		if apiResource.Name == resource {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // SanitizeVolumeName ensures that the given volume name is a valid DNS-1123 label
 // accepted by Kubernetes.
 func SanitizeVolumeName(name string) string {
@@ -237,36 +340,3 @@ func mergeOwnerReferences(old []metav1.OwnerReference, new []metav1.OwnerReferen
 	}
 	return old
 }
-
-// mergeMetadata takes labels and annotations from the old resource and merges
-// them into the new resource. If a key is present in both resources, the new
-// resource wins. It also copies the ResourceVersion from the old resource to
-// the new resource to prevent update conflicts.
-func mergeMetadata(new *metav1.ObjectMeta, old metav1.ObjectMeta) {
-	new.ResourceVersion = old.ResourceVersion
-
-	new.SetLabels(mergeMaps(new.Labels, old.Labels))
-	new.SetAnnotations(mergeMaps(new.Annotations, old.Annotations))
-}
-
-func mergeMaps(new map[string]string, old map[string]string) map[string]string {
-	return mergeMapsByPrefix(new, old, "")
-}
-
-func mergeKubectlAnnotations(new *metav1.ObjectMeta, old metav1.ObjectMeta) {
-	new.SetAnnotations(mergeMapsByPrefix(new.Annotations, old.Annotations, "kubectl.kubernetes.io/"))
-}
-
-func mergeMapsByPrefix(new map[string]string, old map[string]string, prefix string) map[string]string {
-	if old == nil {
-		old = make(map[string]string, len(new))
-	}
-
-	for k, v := range new {
-		if strings.HasPrefix(k, prefix) {
-			old[k] = v
-		}
-	}
-
-	return old
-}
@@ -16,15 +16,22 @@ package k8sutil
 
 import (
 	"context"
+	"encoding/json"
 	"reflect"
 	"strings"
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
-
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"k8s.io/apimachinery/pkg/util/validation"
 )
@@ -68,75 +75,263 @@ func Test_SanitizeVolumeName(t *testing.T) {
 	}
 }
 
+func TestApplyService(t *testing.T) {
+	namespace := "ns-1"
+	existing := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus-operated",
+			Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Prometheus", Name: "other", UID: "1"},
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "None",
+		},
+	}
+
+	client := fake.NewSimpleClientset(existing)
+
+	var patches []k8stesting.PatchAction
+	client.PrependReactor("patch", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patches = append(patches, action.(k8stesting.PatchAction))
+		return true, existing, nil
+	})
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus-operated",
+			Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Prometheus", Name: "test", UID: "2"},
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name: "web",
+					Port: 9090,
+				},
+			},
+		},
+	}
+
+	if err := ApplyService(context.Background(), client.CoreV1().Services(namespace), svc, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(patches) != 1 {
+		t.Fatalf("expected 1 patch, got %d", len(patches))
+	}
+
+	if patches[0].GetPatchType() != types.ApplyPatchType {
+		t.Fatalf("expected patch type %q, got %q", types.ApplyPatchType, patches[0].GetPatchType())
+	}
+
+	var applied corev1.Service
+	if err := json.Unmarshal(patches[0].GetPatch(), &applied); err != nil {
+		t.Fatal(err)
+	}
+
+	if applied.APIVersion != "v1" || applied.Kind != "Service" {
+		t.Fatalf("expected type meta v1/Service, got %s/%s", applied.APIVersion, applied.Kind)
+	}
+
+	if len(applied.OwnerReferences) != 2 {
+		t.Fatalf("expected the owner references to be merged, got %v", applied.OwnerReferences)
+	}
+
+	if applied.Spec.Ports[0].Protocol != corev1.ProtocolTCP {
+		t.Fatalf("expected the port protocol to be defaulted, got %q", applied.Spec.Ports[0].Protocol)
+	}
+
+	if applied.Spec.ClusterIP != "" {
+		t.Fatalf("expected the cluster IP not to be applied, got %q", applied.Spec.ClusterIP)
+	}
+}
+
+func TestApplyConflict(t *testing.T) {
+	namespace := "ns-1"
+	srv := newSSAServer()
+	if err := srv.update(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{"key": []byte("value")},
+	}, "kubectl-edit"); err != nil {
+		t.Fatal(err)
+	}
+
+	var conflicts []error
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{"key": []byte("other")},
+	}
+
+	err := ApplySecret(context.Background(), &fakeSecrets{srv: srv}, secret, func(err error) {
+		conflicts = append(conflicts, err)
+	})
+	if !apierrors.IsConflict(err) {
+		t.Fatalf("expected a conflict error, got %v", err)
+	}
+
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict to be reported, got %d", len(conflicts))
+	}
+
+	var current corev1.Secret
+	if err := srv.get(&current); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(current.Data["key"]) != "value" {
+		t.Fatalf("expected the conflicting field not to be forced, got %q", current.Data["key"])
+	}
+}
+
+func TestApplyUpgradesManagedFields(t *testing.T) {
+	namespace := "ns-1"
+	srv := newSSAServer()
+
+	// The Secret has been created by a previous version of the operator.
+	if err := srv.update(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: namespace,
+			Labels:    map[string]string{"stale": "value"},
+		},
+		Data: map[string][]byte{"key": []byte("value")},
+	}, "operator"); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{"key": []byte("other")},
+	}
+
+	if err := ApplySecret(context.Background(), &fakeSecrets{srv: srv}, secret, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var current corev1.Secret
+	if err := srv.get(&current); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(current.Data["key"]) != "other" {
+		t.Fatalf("expected the field to be applied, got %q", current.Data["key"])
+	}
+
+	if len(current.Labels) != 0 {
+		t.Fatalf("expected the stale label to be removed, got %v", current.Labels)
+	}
+
+	if len(current.ManagedFields) != 1 {
+		t.Fatalf("expected 1 managed fields entry, got %d", len(current.ManagedFields))
+	}
+
+	if current.ManagedFields[0].Manager != FieldManager || current.ManagedFields[0].Operation != metav1.ManagedFieldsOperationApply {
+		t.Fatalf("expected the fields to be applied by %q, got %q (%s)", FieldManager, current.ManagedFields[0].Manager, current.ManagedFields[0].Operation)
+	}
+}
+
 func TestPropagateKubectlTemplateAnnotations(t *testing.T) {
 	ctx := context.Background()
 
 	testCases := []struct {
 		name                string
-		expectedAnnotations map[string]string
+		appliedAnnotations  map[string]string
 		modifiedAnnotations map[string]string
+		expectedAnnotations map[string]string
 	}{
 		{
-			name:                "no change",
-			expectedAnnotations: map[string]string{},
+			name: "no change",
+			expectedAnnotations: map[string]string{
+				"operator": "value",
+			},
 		},
 		{
 			name: "added kubectl annotation",
-			expectedAnnotations: map[string]string{
+			modifiedAnnotations: map[string]string{
 				"kubectl.kubernetes.io/restartedAt": "now",
 			},
-			modifiedAnnotations: map[string]string{
+			expectedAnnotations: map[string]string{
+				"operator":                          "value",
 				"kubectl.kubernetes.io/restartedAt": "now",
-				"annotation":                        "value",
 			},
 		},
 		{
-			name:                "added another annotation",
-			expectedAnnotations: map[string]string{},
-			modifiedAnnotations: map[string]string{
-				"app.kubernetes.io/name": "overridden-value",
+			name: "removed operator annotation",
+			appliedAnnotations: map[string]string{
+				"removed": "value",
+			},
+			expectedAnnotations: map[string]string{
+				"operator": "value",
 			},
 		},
 	}
 
 	namespace := "ns-1"
 
-	t.Run("UpdateStatefulSet", func(t *testing.T) {
-		for _, tc := range testCases {
-			t.Run(tc.name, func(t *testing.T) {
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newSSAServer()
+			ssetClient := &fakeStatefulSets{srv: srv}
+
+			newStatefulSet := func(annotations map[string]string) *appsv1.StatefulSet {
 				sset := &appsv1.StatefulSet{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "prometheus",
 						Namespace: namespace,
 					},
 				}
+				sset.Spec.Template.Annotations = map[string]string{"operator": "value"}
+				for k, v := range annotations {
+					sset.Spec.Template.Annotations[k] = v
+				}
 
-				ssetClient := fake.NewSimpleClientset(sset).AppsV1().StatefulSets(namespace)
+				return sset
+			}
 
-				modifiedSset := sset.DeepCopy()
-				if modifiedSset.Spec.Template.Annotations == nil {
-					modifiedSset.Spec.Template.Annotations = make(map[string]string, len(tc.modifiedAnnotations))
+			if err := ApplyStatefulSet(ctx, ssetClient, newStatefulSet(tc.appliedAnnotations), nil); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(tc.modifiedAnnotations) > 0 {
+				var modified appsv1.StatefulSet
+				if err := srv.get(&modified); err != nil {
+					t.Fatal(err)
 				}
 				for k, v := range tc.modifiedAnnotations {
-					modifiedSset.Spec.Template.Annotations[k] = v
+					modified.Spec.Template.Annotations[k] = v
 				}
-
-				err := UpdateStatefulSet(ctx, ssetClient, modifiedSset)
-				if err != nil {
+				if err := srv.update(&modified, "kubectl-rollout"); err != nil {
 					t.Fatal(err)
 				}
+			}
 
-				updatedSset, err := ssetClient.Get(ctx, "prometheus", metav1.GetOptions{})
-				if err != nil {
-					t.Fatal(err)
-				}
+			if err := ApplyStatefulSet(ctx, ssetClient, newStatefulSet(nil), nil); err != nil {
+				t.Fatal(err)
+			}
 
-				if !reflect.DeepEqual(tc.expectedAnnotations, updatedSset.Spec.Template.Annotations) {
-					t.Errorf("expected annotations %q, got %q", tc.expectedAnnotations, updatedSset.Spec.Template.Annotations)
-				}
-			})
-		}
-	})
+			updatedSset, err := ssetClient.Get(ctx, "prometheus", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedAnnotations, updatedSset.Spec.Template.Annotations) {
+				t.Errorf("expected annotations %q, got %q", tc.expectedAnnotations, updatedSset.Spec.Template.Annotations)
+			}
+		})
+	}
 }
 
 func TestMergeMetadata(t *testing.T) {
@@ -146,6 +341,7 @@ func TestMergeMetadata(t *testing.T) {
 		expectedAnnotations map[string]string
 		modifiedLabels      map[string]string
 		modifiedAnnotations map[string]string
+		expectedConflict    bool
 	}{
 		{
 			name: "no change",
@@ -174,290 +370,200 @@ func TestMergeMetadata(t *testing.T) {
 			},
 		},
 		{
-			name: "overridden label amd annotation",
+			name: "overridden label and annotation",
 			expectedLabels: map[string]string{
-				"app.kubernetes.io/name": "kube-state-metrics",
+				"app.kubernetes.io/name": "overridden-value",
 			},
 			modifiedLabels: map[string]string{
 				"app.kubernetes.io/name": "overridden-value",
 			},
 			expectedAnnotations: map[string]string{
-				"app.kubernetes.io/name": "kube-state-metrics",
+				"app.kubernetes.io/name": "overridden-value",
 			},
 			modifiedAnnotations: map[string]string{
 				"app.kubernetes.io/name": "overridden-value",
 			},
+			expectedConflict: true,
 		},
 	}
 
 	namespace := "ns-1"
-
-	t.Run("CreateOrUpdateService", func(t *testing.T) {
-		for _, tc := range testCases {
-			t.Run(tc.name, func(t *testing.T) {
-				service := &corev1.Service{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:        "prometheus-operated",
-						Namespace:   namespace,
-						Labels:      map[string]string{"app.kubernetes.io/name": "kube-state-metrics"},
-						Annotations: map[string]string{"app.kubernetes.io/name": "kube-state-metrics"},
-					},
-					Spec:   corev1.ServiceSpec{},
-					Status: corev1.ServiceStatus{},
-				}
-
-				svcClient := fake.NewSimpleClientset(service).CoreV1().Services(namespace)
-
-				modifiedSvc := service.DeepCopy()
-				for l, v := range tc.modifiedLabels {
-					modifiedSvc.Labels[l] = v
-				}
-				for a, v := range tc.modifiedAnnotations {
-					modifiedSvc.Annotations[a] = v
-				}
-				_, err := svcClient.Update(context.Background(), modifiedSvc, metav1.UpdateOptions{})
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				err = CreateOrUpdateService(context.Background(), svcClient, service)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				updatedSvc, err := svcClient.Get(context.Background(), "prometheus-operated", metav1.GetOptions{})
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				if !reflect.DeepEqual(tc.expectedAnnotations, updatedSvc.Annotations) {
-					t.Errorf("expected annotations %q, got %q", tc.expectedAnnotations, updatedSvc.Annotations)
-				}
-				if !reflect.DeepEqual(tc.expectedLabels, updatedSvc.Labels) {
-					t.Errorf("expected labels %q, got %q", tc.expectedLabels, updatedSvc.Labels)
-				}
-			})
-		}
-	})
-
-	t.Run("CreateOrUpdateEndpoints", func(t *testing.T) {
-		for _, tc := range testCases {
-			t.Run(tc.name, func(t *testing.T) {
-				endpoints := &corev1.Endpoints{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:        "prometheus-operated",
-						Namespace:   namespace,
-						Labels:      map[string]string{"app.kubernetes.io/name": "kube-state-metrics"},
-						Annotations: map[string]string{"app.kubernetes.io/name": "kube-state-metrics"},
-					},
-				}
-
-				endpointsClient := fake.NewSimpleClientset(endpoints).CoreV1().Endpoints(namespace)
-
-				modifiedEndpoints := endpoints.DeepCopy()
-				for l, v := range tc.modifiedLabels {
-					modifiedEndpoints.Labels[l] = v
-				}
-				for a, v := range tc.modifiedAnnotations {
-					modifiedEndpoints.Annotations[a] = v
-				}
-				_, err := endpointsClient.Update(context.Background(), modifiedEndpoints, metav1.UpdateOptions{})
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				err = CreateOrUpdateEndpoints(context.Background(), endpointsClient, endpoints)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				updatedEndpoints, err := endpointsClient.Get(context.Background(), "prometheus-operated", metav1.GetOptions{})
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				if !reflect.DeepEqual(tc.expectedAnnotations, updatedEndpoints.Annotations) {
-					t.Errorf("expected annotations %q, got %q", tc.expectedAnnotations, updatedEndpoints.Annotations)
-				}
-				if !reflect.DeepEqual(tc.expectedLabels, updatedEndpoints.Labels) {
-					t.Errorf("expected labels %q, got %q", tc.expectedLabels, updatedEndpoints.Labels)
-				}
-			})
+	objectMeta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      map[string]string{"app.kubernetes.io/name": "kube-state-metrics"},
+			Annotations: map[string]string{"app.kubernetes.io/name": "kube-state-metrics"},
 		}
-	})
-
-	t.Run("UpdateStatefulSet", func(t *testing.T) {
-		for _, tc := range testCases {
-			t.Run(tc.name, func(t *testing.T) {
-				sset := &appsv1.StatefulSet{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:        "prometheus",
-						Namespace:   namespace,
-						Labels:      map[string]string{"app.kubernetes.io/name": "kube-state-metrics"},
-						Annotations: map[string]string{"app.kubernetes.io/name": "kube-state-metrics"},
-					},
-				}
-
-				ssetClient := fake.NewSimpleClientset(sset).AppsV1().StatefulSets(namespace)
-
-				modifiedSset := sset.DeepCopy()
-				for l, v := range tc.modifiedLabels {
-					modifiedSset.Labels[l] = v
-				}
-				for a, v := range tc.modifiedAnnotations {
-					modifiedSset.Annotations[a] = v
-				}
-				_, err := ssetClient.Update(context.Background(), modifiedSset, metav1.UpdateOptions{})
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				err = UpdateStatefulSet(context.Background(), ssetClient, sset)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				updatedSset, err := ssetClient.Get(context.Background(), "prometheus", metav1.GetOptions{})
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				if !reflect.DeepEqual(tc.expectedAnnotations, updatedSset.Annotations) {
-					t.Errorf("expected annotations %q, got %q", tc.expectedAnnotations, updatedSset.Annotations)
-				}
-				if !reflect.DeepEqual(tc.expectedLabels, updatedSset.Labels) {
-					t.Errorf("expected labels %q, got %q", tc.expectedLabels, updatedSset.Labels)
-				}
-			})
-		}
-	})
-
-	t.Run("CreateOrUpdateSecret", func(t *testing.T) {
-		for _, tc := range testCases {
-			t.Run(tc.name, func(t *testing.T) {
-				secret := &corev1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:        "prometheus-tls-assets",
-						Namespace:   namespace,
-						Labels:      map[string]string{"app.kubernetes.io/name": "kube-state-metrics"},
-						Annotations: map[string]string{"app.kubernetes.io/name": "kube-state-metrics"},
-					},
-				}
-
-				sClient := fake.NewSimpleClientset(secret).CoreV1().Secrets(namespace)
-
-				modifiedSecret := secret.DeepCopy()
-				for l, v := range tc.modifiedLabels {
-					modifiedSecret.Labels[l] = v
-				}
-				for a, v := range tc.modifiedAnnotations {
-					modifiedSecret.Annotations[a] = v
-				}
-				_, err := sClient.Update(context.Background(), modifiedSecret, metav1.UpdateOptions{})
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				err = CreateOrUpdateSecret(context.Background(), sClient, secret)
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				updatedSecret, err := sClient.Get(context.Background(), "prometheus-tls-assets", metav1.GetOptions{})
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				if !reflect.DeepEqual(tc.expectedAnnotations, updatedSecret.Annotations) {
-					t.Errorf("expected annotations %q, got %q", tc.expectedAnnotations, updatedSecret.Annotations)
-				}
-				if !reflect.DeepEqual(tc.expectedLabels, updatedSecret.Labels) {
-					t.Errorf("expected labels %q, got %q", tc.expectedLabels, updatedSecret.Labels)
-				}
-			})
-		}
-	})
-}
-
-func TestCreateOrUpdateImmutableFields(t *testing.T) {
-	namespace := "default"
-	policy := corev1.IPFamilyPolicyRequireDualStack
+	}
 
-	t.Run("CreateOrUpdateService with immutable fields", func(t *testing.T) {
-		service := &corev1.Service{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "prometheus-operated-test",
-				Namespace: namespace,
+	for _, resource := range []struct {
+		name string
+		// newObject returns the object generated by the operator.
+		newObject func() runtime.Object
+		apply     func(*ssaServer, runtime.Object, ConflictHandler) error
+	}{
+		{
+			name: "ApplyService",
+			newObject: func() runtime.Object {
+				return &corev1.Service{ObjectMeta: objectMeta("prometheus-operated")}
 			},
-			Spec: corev1.ServiceSpec{
-				ClusterIP: "127.0.0.1",
-				ClusterIPs: []string{
-					"127.0.0.1",
-					"192.168.0.159",
-				},
-				IPFamilyPolicy: &policy,
-				IPFamilies: []corev1.IPFamily{
-					corev1.IPv6Protocol,
-				},
-				Ports: []corev1.ServicePort{
-					{
-						Name: "https-metrics",
-						Port: 10250,
-					},
-					{
-						Name: "http-metrics",
-						Port: 10255,
-					},
-				},
+			apply: func(srv *ssaServer, obj runtime.Object, onConflict ConflictHandler) error {
+				return ApplyService(context.Background(), &fakeServices{srv: srv}, obj.(*corev1.Service), onConflict)
 			},
-			Status: corev1.ServiceStatus{},
-		}
-
-		svcClient := fake.NewSimpleClientset(service).CoreV1().Services(namespace)
-
-		modifiedSvc := &corev1.Service{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "prometheus-operated-test",
-				Namespace: namespace,
+		},
+		{
+			name: "ApplyEndpoints",
+			newObject: func() runtime.Object {
+				return &corev1.Endpoints{ObjectMeta: objectMeta("prometheus-operated")}
 			},
-			Spec: corev1.ServiceSpec{
-				Ports: []corev1.ServicePort{
-					{
-						Name: "https-metrics",
-						Port: 10250,
-					},
-				},
+			apply: func(srv *ssaServer, obj runtime.Object, onConflict ConflictHandler) error {
+				return ApplyEndpoints(context.Background(), &fakeEndpoints{srv: srv}, obj.(*corev1.Endpoints), onConflict)
 			},
-			Status: corev1.ServiceStatus{},
-		}
-
-		if err := CreateOrUpdateService(context.TODO(), svcClient, modifiedSvc); err != nil {
-			t.Fatal(err)
-		}
+		},
+		{
+			name: "ApplyStatefulSet",
+			newObject: func() runtime.Object {
+				return &appsv1.StatefulSet{ObjectMeta: objectMeta("prometheus")}
+			},
+			apply: func(srv *ssaServer, obj runtime.Object, onConflict ConflictHandler) error {
+				return ApplyStatefulSet(context.Background(), &fakeStatefulSets{srv: srv}, obj.(*appsv1.StatefulSet), onConflict)
+			},
+		},
+		{
+			name: "ApplySecret",
+			newObject: func() runtime.Object {
+				return &corev1.Secret{ObjectMeta: objectMeta("prometheus-tls-assets")}
+			},
+			apply: func(srv *ssaServer, obj runtime.Object, onConflict ConflictHandler) error {
+				return ApplySecret(context.Background(), &fakeSecrets{srv: srv}, obj.(*corev1.Secret), onConflict)
+			},
+		},
+	} {
+		t.Run(resource.name, func(t *testing.T) {
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					srv := newSSAServer()
+
+					// The object has been created by a previous version
+					// of the operator.
+					if err := srv.update(resource.newObject(), "operator"); err != nil {
+						t.Fatal(err)
+					}
+
+					modified := resource.newObject()
+					if err := srv.get(modified); err != nil {
+						t.Fatal(err)
+					}
+					modifiedMeta, err := meta.Accessor(modified)
+					if err != nil {
+						t.Fatal(err)
+					}
+					for l, v := range tc.modifiedLabels {
+						modifiedMeta.GetLabels()[l] = v
+					}
+					for a, v := range tc.modifiedAnnotations {
+						modifiedMeta.GetAnnotations()[a] = v
+					}
+					if err := srv.update(modified, "kubectl-edit"); err != nil {
+						t.Fatal(err)
+					}
+
+					var conflicts []error
+					err = resource.apply(srv, resource.newObject(), func(err error) {
+						conflicts = append(conflicts, err)
+					})
+					if tc.expectedConflict {
+						if !apierrors.IsConflict(err) {
+							t.Fatalf("expected a conflict error, got %v", err)
+						}
+						if len(conflicts) != 1 {
+							t.Fatalf("expected 1 conflict to be reported, got %d", len(conflicts))
+						}
+					} else if err != nil {
+						t.Fatal(err)
+					}
+
+					updated := resource.newObject()
+					if err := srv.get(updated); err != nil {
+						t.Fatal(err)
+					}
+					updatedMeta, err := meta.Accessor(updated)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if !reflect.DeepEqual(tc.expectedAnnotations, updatedMeta.GetAnnotations()) {
+						t.Errorf("expected annotations %q, got %q", tc.expectedAnnotations, updatedMeta.GetAnnotations())
+					}
+					if !reflect.DeepEqual(tc.expectedLabels, updatedMeta.GetLabels()) {
+						t.Errorf("expected labels %q, got %q", tc.expectedLabels, updatedMeta.GetLabels())
+					}
+				})
+			}
+		})
+	}
+}
 
-		if !reflect.DeepEqual(service.Spec.IPFamilies, modifiedSvc.Spec.IPFamilies) {
-			t.Fatalf("services Spec.IPFamilies are not equal, expected %q, got %q",
-				service.Spec.IPFamilies, modifiedSvc.Spec.IPFamilies)
-		}
+// notFoundDiscovery returns a NotFound error for the unknown group versions
+// like the API server does (the fake discovery client returns a generic
+// error).
+type notFoundDiscovery struct {
+	*fakediscovery.FakeDiscovery
+}
 
-		if !reflect.DeepEqual(service.Spec.ClusterIP, modifiedSvc.Spec.ClusterIP) {
-			t.Fatalf("services Spec.ClusterIP are not equal, expected %q, got %q",
-				service.Spec.ClusterIP, modifiedSvc.Spec.ClusterIP)
+func (d *notFoundDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	for _, l := range d.Resources {
+		if l.GroupVersion == groupVersion {
+			return l, nil
 		}
+	}
 
-		if !reflect.DeepEqual(service.Spec.ClusterIPs, modifiedSvc.Spec.ClusterIPs) {
-			t.Fatalf("services Spec.ClusterIPs are not equal, expected %q, got %q",
-				service.Spec.ClusterIPs, modifiedSvc.Spec.ClusterIPs)
-		}
+	return nil, apierrors.NewNotFound(schema.GroupResource{}, groupVersion)
+}
 
-		if !reflect.DeepEqual(service.Spec.IPFamilyPolicy, modifiedSvc.Spec.IPFamilyPolicy) {
-			t.Fatalf("services Spec.IPFamilyPolicy are not equal, expected %v, got %v",
-				service.Spec.IPFamilyPolicy, modifiedSvc.Spec.IPFamilyPolicy)
-		}
+func TestIsAPIGroupVersionResourceSupported(t *testing.T) {
+	dclient := &notFoundDiscovery{fake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)}
+	dclient.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "monitoring.coreos.com/v1alpha1",
+			APIResources: []metav1.APIResource{
+				{Name: "alertmanagerconfigs"},
+				{Name: "prometheusagents"},
+			},
+		},
+	}
 
-		if !reflect.DeepEqual(service.Spec.IPFamilyPolicy, modifiedSvc.Spec.IPFamilyPolicy) {
-			t.Fatalf("services Spec.IPFamilyPolicy are not equal, expected %v, got %v",
-				service.Spec.IPFamilyPolicy, modifiedSvc.Spec.IPFamilyPolicy)
-		}
-	})
+	for _, tc := range []struct {
+		groupVersion string
+		resource     string
+		expected     bool
+	}{
+		{
+			groupVersion: "monitoring.coreos.com/v1alpha1",
+			resource:     "prometheusagents",
+			expected:     true,
+		},
+		{
+			groupVersion: "monitoring.coreos.com/v1alpha1",
+			resource:     "scrapeconfigs",
+			expected:     false,
+		},
+		{
+			groupVersion: "monitoring.coreos.com/v1beta1",
+			resource:     "prometheusagents",
+			expected:     false,
+		},
+	} {
+		t.Run(tc.groupVersion+"/"+tc.resource, func(t *testing.T) {
+			supported, err := IsAPIGroupVersionResourceSupported(dclient, tc.groupVersion, tc.resource)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if supported != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, supported)
+			}
+		})
+	}
 }
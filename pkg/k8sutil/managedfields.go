@@ -0,0 +1,114 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// legacyFieldManagers are the field managers of the client-side updates done
+// by the versions of the operator which didn't use server-side apply. Without
+// an explicit field manager, the API server uses the name of the binary
+// found in the user agent.
+var legacyFieldManagers = map[string]struct{}{
+	"operator":   {},
+	FieldManager: {},
+}
+
+// upgradeManagedFields merges the managed fields of the client-side updates
+// done by the operator into the entry of its server-side apply, like
+// kubectl does when upgrading from client-side apply. It returns nil when
+// there is nothing to upgrade.
+//
+// Once upgraded, the fields previously set by the operator are owned by the
+// apply entry: they don't conflict with the applied configuration anymore
+// and they are removed when they aren't part of it.
+func upgradeManagedFields(entries []metav1.ManagedFieldsEntry) ([]metav1.ManagedFieldsEntry, error) {
+	var (
+		upgraded   []metav1.ManagedFieldsEntry
+		applied    *metav1.ManagedFieldsEntry
+		apiVersion string
+		found      bool
+		fields     = &fieldpath.Set{}
+	)
+
+	for i, e := range entries {
+		_, legacy := legacyFieldManagers[e.Manager]
+
+		switch {
+		case e.Subresource != "":
+			// The updates of the status subresource aren't applied.
+			upgraded = append(upgraded, e)
+			continue
+		case legacy && e.Operation == metav1.ManagedFieldsOperationUpdate:
+			found = true
+			if apiVersion == "" {
+				apiVersion = e.APIVersion
+			}
+		case e.Manager == FieldManager && e.Operation == metav1.ManagedFieldsOperationApply:
+			applied = &entries[i]
+		default:
+			upgraded = append(upgraded, e)
+			continue
+		}
+
+		if e.FieldsV1 == nil {
+			continue
+		}
+
+		s := &fieldpath.Set{}
+		if err := s.FromJSON(bytes.NewReader(e.FieldsV1.Raw)); err != nil {
+			return nil, fmt.Errorf("failed to decode the managed fields of %q: %w", e.Manager, err)
+		}
+		fields = fields.Union(s)
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	raw, err := fields.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the managed fields: %w", err)
+	}
+
+	entry := metav1.ManagedFieldsEntry{
+		Manager:    FieldManager,
+		Operation:  metav1.ManagedFieldsOperationApply,
+		APIVersion: apiVersion,
+		FieldsType: "FieldsV1",
+	}
+	if applied != nil {
+		entry = *applied.DeepCopy()
+	}
+	entry.FieldsV1 = &metav1.FieldsV1{Raw: raw}
+
+	return append(upgraded, entry), nil
+}
+
+// managedFieldsPatch returns the JSON patch replacing the managed fields of
+// the object. Setting the resource version makes the patch fail if the object
+// has been modified in the meantime.
+func managedFieldsPatch(obj metav1.Object, entries []metav1.ManagedFieldsEntry) ([]byte, error) {
+	return json.Marshal([]map[string]interface{}{
+		{"op": "replace", "path": "/metadata/managedFields", "value": entries},
+		{"op": "replace", "path": "/metadata/resourceVersion", "value": obj.GetResourceVersion()},
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sutil
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func fieldsEntry(manager string, op metav1.ManagedFieldsOperationType, subresource, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:     manager,
+		Operation:   op,
+		APIVersion:  "v1",
+		FieldsType:  "FieldsV1",
+		FieldsV1:    &metav1.FieldsV1{Raw: []byte(fields)},
+		Subresource: subresource,
+	}
+}
+
+func TestUpgradeManagedFields(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		entries  []metav1.ManagedFieldsEntry
+		expected []metav1.ManagedFieldsEntry
+	}{
+		{
+			name: "no legacy entry",
+			entries: []metav1.ManagedFieldsEntry{
+				fieldsEntry(FieldManager, metav1.ManagedFieldsOperationApply, "", `{"f:data":{"f:a":{}}}`),
+				fieldsEntry("kubectl-edit", metav1.ManagedFieldsOperationUpdate, "", `{"f:data":{"f:b":{}}}`),
+			},
+		},
+		{
+			name: "legacy update",
+			entries: []metav1.ManagedFieldsEntry{
+				fieldsEntry("operator", metav1.ManagedFieldsOperationUpdate, "", `{"f:data":{"f:a":{}}}`),
+				fieldsEntry("kubectl-edit", metav1.ManagedFieldsOperationUpdate, "", `{"f:data":{"f:b":{}}}`),
+			},
+			expected: []metav1.ManagedFieldsEntry{
+				fieldsEntry("kubectl-edit", metav1.ManagedFieldsOperationUpdate, "", `{"f:data":{"f:b":{}}}`),
+				fieldsEntry(FieldManager, metav1.ManagedFieldsOperationApply, "", `{"f:data":{"f:a":{}}}`),
+			},
+		},
+		{
+			name: "legacy updates merged with the applied fields",
+			entries: []metav1.ManagedFieldsEntry{
+				fieldsEntry(FieldManager, metav1.ManagedFieldsOperationApply, "", `{"f:data":{"f:a":{}}}`),
+				fieldsEntry("operator", metav1.ManagedFieldsOperationUpdate, "", `{"f:data":{"f:b":{}}}`),
+				fieldsEntry(FieldManager, metav1.ManagedFieldsOperationUpdate, "", `{"f:data":{"f:c":{}}}`),
+			},
+			expected: []metav1.ManagedFieldsEntry{
+				fieldsEntry(FieldManager, metav1.ManagedFieldsOperationApply, "", `{"f:data":{"f:a":{},"f:b":{},"f:c":{}}}`),
+			},
+		},
+		{
+			name: "status updates left untouched",
+			entries: []metav1.ManagedFieldsEntry{
+				fieldsEntry("operator", metav1.ManagedFieldsOperationUpdate, "status", `{"f:status":{"f:replicas":{}}}`),
+				fieldsEntry("operator", metav1.ManagedFieldsOperationUpdate, "", `{"f:spec":{"f:replicas":{}}}`),
+			},
+			expected: []metav1.ManagedFieldsEntry{
+				fieldsEntry("operator", metav1.ManagedFieldsOperationUpdate, "status", `{"f:status":{"f:replicas":{}}}`),
+				fieldsEntry(FieldManager, metav1.ManagedFieldsOperationApply, "", `{"f:spec":{"f:replicas":{}}}`),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			upgraded, err := upgradeManagedFields(tc.entries)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, upgraded); diff != "" {
+				t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -23,13 +23,22 @@ import (
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 )
 
-// MergePatchContainers adds patches to base using a strategic merge patch and iterating by container name, failing on the first error
+// MergePatchContainers adds patches to base using a strategic merge patch and iterating by container name, failing on the first error.
+// The fields of the patches take precedence over the fields of the base
+// containers. Base containers keep their position and the patches which don't
+// match any base container are appended in order.
 func MergePatchContainers(base, patches []v1.Container) ([]v1.Container, error) {
 	var out []v1.Container
 
 	// map of containers that still need to be patched by name
 	containersToPatch := make(map[string]v1.Container)
 	for _, c := range patches {
+		if c.Name == "" {
+			return nil, errors.New("container name is required")
+		}
+		if _, found := containersToPatch[c.Name]; found {
+			return nil, errors.Errorf("duplicate container name %q", c.Name)
+		}
 		containersToPatch[c.Name] = c
 	}
 
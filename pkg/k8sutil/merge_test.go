@@ -117,3 +117,30 @@ func TestPodLabelsAnnotations(t *testing.T) {
 		}
 	}
 }
+
+func TestMergePatchContainersInvalidPatches(t *testing.T) {
+	base := []v1.Container{{Name: "c1", Image: "image:A"}}
+
+	for _, tc := range []struct {
+		name    string
+		patches []v1.Container
+	}{
+		{
+			name:    "empty name",
+			patches: []v1.Container{{Image: "image:B"}},
+		},
+		{
+			name:    "duplicate names",
+			patches: []v1.Container{{Name: "c2", Image: "image:B"}, {Name: "c2", Image: "image:C"}},
+		},
+		{
+			name:    "duplicate names matching a base container",
+			patches: []v1.Container{{Name: "c1", Image: "image:B"}, {Name: "c1", Image: "image:C"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := MergePatchContainers(base, tc.patches)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,326 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	clientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/merge"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// ssaServer emulates the field management of the API server for a single
+// object (the fake clientset doesn't support server-side apply). The schema is
+// deduced from the object: all the maps are granular and all the lists are
+// atomic.
+type ssaServer struct {
+	obj             *typed.TypedValue
+	managers        fieldpath.ManagedFields
+	resourceVersion int
+	updater         *merge.Updater
+}
+
+func newSSAServer() *ssaServer {
+	return &ssaServer{
+		managers: fieldpath.ManagedFields{},
+		updater:  &merge.Updater{Converter: noopConverter{}},
+	}
+}
+
+type noopConverter struct{}
+
+func (noopConverter) Convert(v *typed.TypedValue, _ fieldpath.APIVersion) (*typed.TypedValue, error) {
+	return v, nil
+}
+
+func (noopConverter) IsMissingVersionError(error) bool { return false }
+
+func managerKey(manager string, op metav1.ManagedFieldsOperationType) string {
+	return manager + "/" + string(op)
+}
+
+// toTypedValue decodes a JSON object, ignoring the fields set by the API
+// server.
+func toTypedValue(data []byte) (*typed.TypedValue, error) {
+	var u map[string]interface{}
+	if err := json.Unmarshal(data, &u); err != nil {
+		return nil, err
+	}
+
+	delete(u, "status")
+	if m, ok := u["metadata"].(map[string]interface{}); ok {
+		delete(m, "managedFields")
+		delete(m, "resourceVersion")
+		delete(m, "creationTimestamp")
+	}
+
+	return typed.DeducedParseableType.FromUnstructured(u)
+}
+
+func (s *ssaServer) live() (*typed.TypedValue, error) {
+	if s.obj != nil {
+		return s.obj, nil
+	}
+
+	return typed.DeducedParseableType.FromUnstructured(map[string]interface{}{})
+}
+
+// get decodes the current object with its managed fields into obj.
+func (s *ssaServer) get(obj runtime.Object) error {
+	if s.obj == nil {
+		return apierrors.NewNotFound(schema.GroupResource{}, "")
+	}
+
+	data, err := json.Marshal(s.obj.AsValue().Unstructured())
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, obj); err != nil {
+		return err
+	}
+
+	var entries []metav1.ManagedFieldsEntry
+	for k, vs := range s.managers {
+		raw, err := vs.Set().ToJSON()
+		if err != nil {
+			return err
+		}
+
+		i := strings.LastIndex(k, "/")
+		entries = append(entries, metav1.ManagedFieldsEntry{
+			Manager:    k[:i],
+			Operation:  metav1.ManagedFieldsOperationType(k[i+1:]),
+			APIVersion: string(vs.APIVersion()),
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: raw},
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return managerKey(entries[i].Manager, entries[i].Operation) < managerKey(entries[j].Manager, entries[j].Operation)
+	})
+
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	m.SetManagedFields(entries)
+	m.SetResourceVersion(strconv.Itoa(s.resourceVersion))
+
+	return nil
+}
+
+// update emulates a client-side update of obj by the given manager.
+func (s *ssaServer) update(obj runtime.Object, manager string) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	newObj, err := toTypedValue(data)
+	if err != nil {
+		return err
+	}
+
+	live, err := s.live()
+	if err != nil {
+		return err
+	}
+
+	newObj, managers, err := s.updater.Update(live, newObj, "v1", s.managers, managerKey(manager, metav1.ManagedFieldsOperationUpdate))
+	if err != nil {
+		return err
+	}
+
+	s.obj, s.managers = newObj, managers
+	s.resourceVersion++
+
+	return nil
+}
+
+func (s *ssaServer) apply(data []byte, opts metav1.PatchOptions) error {
+	config, err := toTypedValue(data)
+	if err != nil {
+		return err
+	}
+
+	live, err := s.live()
+	if err != nil {
+		return err
+	}
+
+	force := opts.Force != nil && *opts.Force
+	newObj, managers, err := s.updater.Apply(live, config, "v1", s.managers, managerKey(opts.FieldManager, metav1.ManagedFieldsOperationApply), force)
+	if err != nil {
+		var conflicts merge.Conflicts
+		if errors.As(err, &conflicts) {
+			return apierrors.NewApplyConflict(nil, conflicts.Error())
+		}
+		return err
+	}
+
+	if newObj != nil {
+		s.obj = newObj
+	}
+	s.managers = managers
+	s.resourceVersion++
+
+	return nil
+}
+
+// jsonPatch supports only the patches replacing the managed fields.
+func (s *ssaServer) jsonPatch(data []byte) error {
+	var ops []struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return err
+	}
+
+	managers := s.managers
+	for _, op := range ops {
+		if op.Op != "replace" {
+			return fmt.Errorf("unsupported operation %q", op.Op)
+		}
+
+		switch op.Path {
+		case "/metadata/resourceVersion":
+			var rv string
+			if err := json.Unmarshal(op.Value, &rv); err != nil {
+				return err
+			}
+			if rv != strconv.Itoa(s.resourceVersion) {
+				return apierrors.NewConflict(schema.GroupResource{}, "", errors.New("the object has been modified"))
+			}
+		case "/metadata/managedFields":
+			var entries []metav1.ManagedFieldsEntry
+			if err := json.Unmarshal(op.Value, &entries); err != nil {
+				return err
+			}
+
+			managers = fieldpath.ManagedFields{}
+			for _, e := range entries {
+				set := &fieldpath.Set{}
+				if err := set.FromJSON(bytes.NewReader(e.FieldsV1.Raw)); err != nil {
+					return err
+				}
+				managers[managerKey(e.Manager, e.Operation)] = fieldpath.NewVersionedSet(set, fieldpath.APIVersion(e.APIVersion), e.Operation == metav1.ManagedFieldsOperationApply)
+			}
+		default:
+			return fmt.Errorf("unsupported path %q", op.Path)
+		}
+	}
+
+	s.managers = managers
+	s.resourceVersion++
+
+	return nil
+}
+
+func (s *ssaServer) patch(pt types.PatchType, data []byte, opts metav1.PatchOptions, obj runtime.Object) error {
+	var err error
+	switch pt {
+	case types.ApplyPatchType:
+		err = s.apply(data, opts)
+	case types.JSONPatchType:
+		err = s.jsonPatch(data)
+	default:
+		err = fmt.Errorf("unsupported patch type %q", pt)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.get(obj)
+}
+
+type fakeServices struct {
+	clientv1.ServiceInterface
+	srv *ssaServer
+}
+
+func (c *fakeServices) Get(_ context.Context, _ string, _ metav1.GetOptions) (*corev1.Service, error) {
+	svc := &corev1.Service{}
+	return svc, c.srv.get(svc)
+}
+
+func (c *fakeServices) Patch(_ context.Context, _ string, pt types.PatchType, data []byte, opts metav1.PatchOptions, _ ...string) (*corev1.Service, error) {
+	svc := &corev1.Service{}
+	return svc, c.srv.patch(pt, data, opts, svc)
+}
+
+type fakeEndpoints struct {
+	clientv1.EndpointsInterface
+	srv *ssaServer
+}
+
+func (c *fakeEndpoints) Get(_ context.Context, _ string, _ metav1.GetOptions) (*corev1.Endpoints, error) {
+	eps := &corev1.Endpoints{}
+	return eps, c.srv.get(eps)
+}
+
+func (c *fakeEndpoints) Patch(_ context.Context, _ string, pt types.PatchType, data []byte, opts metav1.PatchOptions, _ ...string) (*corev1.Endpoints, error) {
+	eps := &corev1.Endpoints{}
+	return eps, c.srv.patch(pt, data, opts, eps)
+}
+
+type fakeStatefulSets struct {
+	clientappsv1.StatefulSetInterface
+	srv *ssaServer
+}
+
+func (c *fakeStatefulSets) Get(_ context.Context, _ string, _ metav1.GetOptions) (*appsv1.StatefulSet, error) {
+	sset := &appsv1.StatefulSet{}
+	return sset, c.srv.get(sset)
+}
+
+func (c *fakeStatefulSets) Patch(_ context.Context, _ string, pt types.PatchType, data []byte, opts metav1.PatchOptions, _ ...string) (*appsv1.StatefulSet, error) {
+	sset := &appsv1.StatefulSet{}
+	return sset, c.srv.patch(pt, data, opts, sset)
+}
+
+type fakeSecrets struct {
+	clientv1.SecretInterface
+	srv *ssaServer
+}
+
+func (c *fakeSecrets) Get(_ context.Context, _ string, _ metav1.GetOptions) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	return secret, c.srv.get(secret)
+}
+
+func (c *fakeSecrets) Patch(_ context.Context, _ string, pt types.PatchType, data []byte, opts metav1.PatchOptions, _ ...string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	return secret, c.srv.patch(pt, data, opts, secret)
+}
@@ -21,9 +21,18 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 )
 
-const configReloaderPort = 8080
+const (
+	configReloaderPort = 8080
+
+	// Environment variables holding the basic authentication credentials
+	// used by the config-reloader to call the reload URL.
+	ReloaderBasicAuthUsernameEnvVar = "RELOAD_BASIC_AUTH_USERNAME"
+	ReloaderBasicAuthPasswordEnvVar = "RELOAD_BASIC_AUTH_PASSWORD"
+)
 
 // ConfigReloader contains the options to configure
 // a config-reloader container
@@ -37,6 +46,7 @@ type ConfigReloader struct {
 	logFormat          string
 	logLevel           string
 	reloadURL          url.URL
+	basicAuth          *monitoringv1.BasicAuth
 	runOnce            bool
 	shard              *int32
 	volumeMounts       []v1.VolumeMount
@@ -87,6 +97,14 @@ func ReloaderURL(reloadURL url.URL) ReloaderOption {
 	}
 }
 
+// ReloaderBasicAuth sets the basic authentication credentials used by the
+// config-reloader container to call the reload URL
+func ReloaderBasicAuth(basicAuth *monitoringv1.BasicAuth) ReloaderOption {
+	return func(c *ConfigReloader) {
+		c.basicAuth = basicAuth
+	}
+}
+
 // ListenLocal sets the listenLocal option for the config-reloader container
 func ListenLocal(listenLocal bool) ReloaderOption {
 	return func(c *ConfigReloader) {
@@ -220,6 +238,21 @@ func CreateConfigReloader(name string, options ...ReloaderOption) v1.Container {
 		})
 	}
 
+	if configReloader.basicAuth != nil {
+		username := configReloader.basicAuth.Username
+		password := configReloader.basicAuth.Password
+		envVars = append(envVars,
+			v1.EnvVar{
+				Name:      ReloaderBasicAuthUsernameEnvVar,
+				ValueFrom: &v1.EnvVarSource{SecretKeyRef: &username},
+			},
+			v1.EnvVar{
+				Name:      ReloaderBasicAuthPasswordEnvVar,
+				ValueFrom: &v1.EnvVarSource{SecretKeyRef: &password},
+			},
+		)
+	}
+
 	return v1.Container{
 		Name:                     name,
 		Image:                    configReloader.config.Image,
@@ -19,6 +19,10 @@ import (
 	"net/url"
 	"strconv"
 	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 )
 
 var reloaderConfig = ReloaderConfig{
@@ -106,6 +110,43 @@ func TestCreateConfigReloader(t *testing.T) {
 	}
 }
 
+func TestCreateConfigReloaderWithBasicAuth(t *testing.T) {
+	container := CreateConfigReloader(
+		"config-reloader",
+		ReloaderResources(reloaderConfig),
+		ReloaderBasicAuth(&monitoringv1.BasicAuth{
+			Username: v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "reloader-auth"},
+				Key:                  "username",
+			},
+			Password: v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "reloader-auth"},
+				Key:                  "password",
+			},
+		}),
+	)
+
+	expected := map[string]string{
+		ReloaderBasicAuthUsernameEnvVar: "username",
+		ReloaderBasicAuthPasswordEnvVar: "password",
+	}
+	for _, env := range container.Env {
+		key, found := expected[env.Name]
+		if !found {
+			continue
+		}
+
+		if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil || env.ValueFrom.SecretKeyRef.Name != "reloader-auth" || env.ValueFrom.SecretKeyRef.Key != key {
+			t.Errorf("Expected %s to reference the key %q of the reloader-auth secret, got %v", env.Name, key, env.ValueFrom)
+		}
+		delete(expected, env.Name)
+	}
+
+	if len(expected) > 0 {
+		t.Errorf("Expected environment variables %v not found in %v", expected, container.Env)
+	}
+}
+
 func contains(s []string, str string) bool {
 	for _, v := range s {
 		if v == str {
@@ -17,6 +17,8 @@ package operator
 import (
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/client-go/rest"
 )
 
@@ -30,6 +32,7 @@ type Config struct {
 	TLSConfig                    rest.TLSClientConfig
 	ServerTLSConfig              TLSServerConfig
 	ReloaderConfig               ReloaderConfig
+	ThanosSidecarConfig          ThanosSidecarConfig
 	AlertmanagerDefaultBaseImage string
 	PrometheusDefaultBaseImage   string
 	ThanosDefaultBaseImage       string
@@ -42,8 +45,51 @@ type Config struct {
 	AlertManagerSelector         string
 	ThanosRulerSelector          string
 	SecretListWatchSelector      string
+	// Label selector of the Secrets watched by the controllers. The
+	// Secrets which don't match don't trigger reconciliations.
+	SecretLabelSelector string
+	// Whether the Secret and ConfigMap informers only cache the object
+	// metadata.
+	MetadataOnlyInformers bool
+	// Capabilities of the Prometheus resources which are forcibly disabled
+	// by the operator.
+	DisallowAdminAPI            bool
+	DisallowRemoteWriteReceiver bool
+	// Namespaces whose AlertmanagerConfig resources may define inhibition
+	// rules matching the alerts from all namespaces.
+	CrossNamespaceInhibitionNamespaces map[string]struct{}
+	// Namespaces from which the AlertmanagerConfig receivers may reference
+	// Secrets and ConfigMaps.
+	AlertmanagerConfigSecretNamespaces map[string]struct{}
+	// Strategy used to reload the configuration of the Alertmanager pods.
+	AlertmanagerReloadStrategy ReloadStrategy
+	// Number of workers reconciling the resources concurrently, per
+	// controller.
+	PrometheusWorkers   int
+	AlertmanagerWorkers int
+	ThanosRulerWorkers  int
+	// Rate limiting of the work queues.
+	WorkqueueRateLimiter RateLimiterConfig
+	// Whether the controllers periodically delete the generated objects
+	// which aren't needed anymore. In dry-run mode, the orphaned objects are
+	// only logged and counted.
+	GarbageCollection       bool
+	GarbageCollectionDryRun bool
 }
 
+// ReloadStrategy defines how the configuration changes are applied to the
+// running pods.
+type ReloadStrategy string
+
+const (
+	// SidecarReloadStrategy relies on the config-reloader sidecar of the
+	// pods.
+	SidecarReloadStrategy ReloadStrategy = "sidecar"
+	// OperatorReloadStrategy makes the operator call the reload endpoint of
+	// the pods which don't run the config-reloader sidecar.
+	OperatorReloadStrategy ReloadStrategy = "operator"
+)
+
 type ReloaderConfig struct {
 	CPURequest    string
 	CPULimit      string
@@ -52,6 +98,54 @@ type ReloaderConfig struct {
 	Image         string
 }
 
+// ThanosSidecarConfig defines the default resources of the Thanos sidecar
+// containers. Empty and "0" values mean no default.
+type ThanosSidecarConfig struct {
+	CPURequest    string
+	CPULimit      string
+	MemoryRequest string
+	MemoryLimit   string
+}
+
+// Resources returns the resource requirements of the Thanos sidecar. The
+// requests and limits defined by res take precedence over the defaults. A
+// default is ignored when it conflicts with the value defined by res (e.g. a
+// default limit lower than the request).
+func (c ThanosSidecarConfig) Resources(res v1.ResourceRequirements) v1.ResourceRequirements {
+	ret := *res.DeepCopy()
+
+	for _, d := range []struct {
+		name    v1.ResourceName
+		request string
+		limit   string
+	}{
+		{name: v1.ResourceCPU, request: c.CPURequest, limit: c.CPULimit},
+		{name: v1.ResourceMemory, request: c.MemoryRequest, limit: c.MemoryLimit},
+	} {
+		if _, found := res.Requests[d.name]; !found && d.request != "" && d.request != "0" {
+			q := resource.MustParse(d.request)
+			if limit, found := res.Limits[d.name]; !found || q.Cmp(limit) <= 0 {
+				if ret.Requests == nil {
+					ret.Requests = v1.ResourceList{}
+				}
+				ret.Requests[d.name] = q
+			}
+		}
+
+		if _, found := res.Limits[d.name]; !found && d.limit != "" && d.limit != "0" {
+			q := resource.MustParse(d.limit)
+			if request, found := res.Requests[d.name]; !found || q.Cmp(request) >= 0 {
+				if ret.Limits == nil {
+					ret.Limits = v1.ResourceList{}
+				}
+				ret.Limits[d.name] = q
+			}
+		}
+	}
+
+	return ret
+}
+
 type Labels struct {
 	LabelsString string
 	LabelsMap    map[string]string
@@ -96,4 +190,6 @@ type Namespaces struct {
 	AllowList, DenyList map[string]struct{}
 	// Allow list for prometheus/alertmanager custom resources.
 	PrometheusAllowList, AlertmanagerAllowList, ThanosRulerAllowList map[string]struct{}
+	// Distribution of the namespaces between several operator instances.
+	Sharding NamespaceSharding
 }
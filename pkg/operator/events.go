@@ -0,0 +1,64 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons of the warning events emitted by the controllers on the
+// Prometheus, PrometheusAgent, Alertmanager and ThanosRuler objects.
+const (
+	ConfigGenerationFailedReason  = "ConfigGenerationFailed"
+	ConfigValidationSkippedReason = "ConfigValidationSkipped"
+	InvalidConfigurationReason    = "InvalidConfiguration"
+	ResourceRejectedReason        = "ResourceRejected"
+	StatefulSetUpdateFailedReason = "StatefulSetUpdateFailed"
+)
+
+// NewEventRecorder returns a recorder emitting Kubernetes events on behalf of
+// the given controller.
+func NewEventRecorder(client kubernetes.Interface, component string) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+
+	return broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: component})
+}
+
+// ApplyConflictEventHandler returns a k8sutil.ConflictHandler emitting a
+// warning event for obj when the operator can't apply fields managed by
+// another field manager.
+func ApplyConflictEventHandler(recorder record.EventRecorder, obj runtime.Object) k8sutil.ConflictHandler {
+	return func(err error) {
+		RecordWarningEvent(recorder, obj, "FieldManagerConflict", "Failed to apply fields managed by another field manager: %v", err)
+	}
+}
+
+// RecordWarningEvent emits a warning event for obj. It does nothing when the
+// recorder is nil.
+func RecordWarningEvent(recorder record.EventRecorder, obj runtime.Object, reason, messageFmt string, args ...interface{}) {
+	if recorder == nil {
+		return
+	}
+
+	recorder.Eventf(obj, v1.EventTypeWarning, reason, messageFmt, args...)
+}
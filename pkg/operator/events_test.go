@@ -0,0 +1,44 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"errors"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRecordWarningEvent(t *testing.T) {
+	obj := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+
+	// A nil recorder is a no-op.
+	RecordWarningEvent(nil, obj, ConfigGenerationFailedReason, "Failed to generate the configuration: %v", errors.New("boom"))
+
+	recorder := record.NewFakeRecorder(1)
+	RecordWarningEvent(recorder, obj, ResourceRejectedReason, "%s %s was rejected: %v", "ServiceMonitor", "default/foo", errors.New("invalid"))
+
+	select {
+	case e := <-recorder.Events:
+		expected := "Warning ResourceRejected ServiceMonitor default/foo was rejected: invalid"
+		if e != expected {
+			t.Fatalf("expected event %q, got %q", expected, e)
+		}
+	default:
+		t.Fatal("expected an event")
+	}
+}
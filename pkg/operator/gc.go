@@ -0,0 +1,401 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring"
+)
+
+// DefaultGarbageCollectionInterval is the interval between two searches of
+// orphaned objects.
+const DefaultGarbageCollectionInterval = 10 * time.Minute
+
+// Kinds of the objects generated by the controllers which are garbage
+// collected.
+const (
+	SecretKind      = "Secret"
+	ConfigMapKind   = "ConfigMap"
+	ServiceKind     = "Service"
+	StatefulSetKind = "StatefulSet"
+)
+
+// managedByOperatorSelector selects the Secrets and ConfigMaps generated by
+// the operator.
+const managedByOperatorSelector = "managed-by=prometheus-operator"
+
+// GeneratedObject is an object generated by a controller for one or more
+// owner resources.
+type GeneratedObject struct {
+	Kind string
+	metav1.Object
+}
+
+// Orphan is a generated object which isn't needed anymore.
+type Orphan struct {
+	GeneratedObject
+	Reason string
+}
+
+// OwnerGetter returns the owner resource with the given kind, namespace and
+// name. It returns nil if the resource doesn't exist.
+type OwnerGetter func(ctx context.Context, kind, namespace, name string) (metav1.Object, error)
+
+// NeededFunc returns whether the existing owner resource still needs the
+// generated object, e.g. a StatefulSet of a shard which has been scaled in
+// isn't needed anymore.
+type NeededFunc func(kind string, owner metav1.Object, obj GeneratedObject) bool
+
+// GarbageCollector detects and deletes the Secrets, ConfigMaps, Services and
+// StatefulSets generated by a controller whose owners don't exist or don't
+// need them anymore.
+//
+// The Kubernetes garbage collector already deletes the objects whose owner is
+// gone but it won't remove objects which are still referenced by an existing
+// owner (e.g. rule ConfigMaps left behind after a rename) nor objects whose
+// owner reference has been lost.
+type GarbageCollector struct {
+	logger  log.Logger
+	kclient kubernetes.Interface
+	dryRun  bool
+
+	allowList map[string]struct{}
+	denyList  map[string]struct{}
+	// ownsNamespace returns false for the namespaces managed by other
+	// operator instances.
+	ownsNamespace func(string) bool
+
+	ownerKinds map[string]struct{}
+	getOwner   OwnerGetter
+	needed     NeededFunc
+
+	orphaned *prometheus.GaugeVec
+	deleted  *prometheus.CounterVec
+}
+
+// GarbageCollectorOptions defines the objects that a garbage collector
+// considers.
+type GarbageCollectorOptions struct {
+	// Namespaces of the owner resources.
+	AllowList map[string]struct{}
+	DenyList  map[string]struct{}
+	// OwnsNamespace returns false for the namespaces which aren't managed by
+	// the operator instance. All namespaces are managed when nil.
+	OwnsNamespace func(string) bool
+	// Kinds of the owner resources (e.g. Prometheus).
+	OwnerKinds []string
+	GetOwner   OwnerGetter
+	// Needed is optional. When nil, the objects are needed as long as one
+	// owner exists.
+	Needed NeededFunc
+	// When true, the orphaned objects are only reported.
+	DryRun bool
+}
+
+// NewGarbageCollector returns a garbage collector registering its metrics
+// with the controller's metrics.
+func NewGarbageCollector(logger log.Logger, kclient kubernetes.Interface, m *Metrics, opts GarbageCollectorOptions) *GarbageCollector {
+	gc := &GarbageCollector{
+		logger:        logger,
+		kclient:       kclient,
+		dryRun:        opts.DryRun,
+		allowList:     opts.AllowList,
+		denyList:      opts.DenyList,
+		ownsNamespace: opts.OwnsNamespace,
+		ownerKinds:    make(map[string]struct{}, len(opts.OwnerKinds)),
+		getOwner:      opts.GetOwner,
+		needed:        opts.Needed,
+		orphaned: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "prometheus_operator_orphaned_objects",
+				Help: "Number of generated objects found orphaned by the last garbage collection",
+			},
+			[]string{"kind"},
+		),
+		deleted: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "prometheus_operator_orphaned_objects_deleted_total",
+				Help: "Total number of orphaned objects deleted by the garbage collection",
+			},
+			[]string{"kind"},
+		),
+	}
+
+	for _, k := range opts.OwnerKinds {
+		gc.ownerKinds[k] = struct{}{}
+	}
+
+	for _, k := range []string{SecretKind, ConfigMapKind, ServiceKind, StatefulSetKind} {
+		gc.orphaned.WithLabelValues(k)
+		gc.deleted.WithLabelValues(k)
+	}
+
+	if m != nil {
+		m.MustRegister(gc.orphaned, gc.deleted)
+	}
+
+	return gc
+}
+
+// Run searches and deletes the orphaned objects at the given interval until
+// the context is canceled.
+func (gc *GarbageCollector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := gc.Collect(ctx); err != nil {
+			level.Warn(gc.logger).Log("msg", "garbage collection failed", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect searches and deletes the orphaned objects once.
+func (gc *GarbageCollector) Collect(ctx context.Context) error {
+	orphans, err := gc.FindOrphans(ctx)
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{SecretKind: 0, ConfigMapKind: 0, ServiceKind: 0, StatefulSetKind: 0}
+	for _, o := range orphans {
+		counts[o.Kind]++
+	}
+	for k, v := range counts {
+		gc.orphaned.WithLabelValues(k).Set(float64(v))
+	}
+
+	for _, o := range orphans {
+		logger := log.With(gc.logger, "kind", o.Kind, "namespace", o.GetNamespace(), "name", o.GetName(), "reason", o.Reason)
+
+		if gc.dryRun {
+			level.Info(logger).Log("msg", "found orphaned object (dry run)")
+			continue
+		}
+
+		if err := gc.delete(ctx, o); err != nil && !apierrors.IsNotFound(err) {
+			level.Warn(logger).Log("msg", "failed to delete orphaned object", "err", err)
+			continue
+		}
+
+		gc.deleted.WithLabelValues(o.Kind).Inc()
+		level.Info(logger).Log("msg", "deleted orphaned object")
+	}
+
+	return nil
+}
+
+// FindOrphans returns the generated objects which aren't needed anymore,
+// sorted by kind, namespace and name.
+func (gc *GarbageCollector) FindOrphans(ctx context.Context) ([]Orphan, error) {
+	objs, err := gc.listGeneratedObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := map[string]metav1.Object{}
+	getOwner := func(kind, namespace, name string) (metav1.Object, error) {
+		key := kind + "/" + namespace + "/" + name
+		if o, found := owners[key]; found {
+			return o, nil
+		}
+
+		o, err := gc.getOwner(ctx, kind, namespace, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get %s %s/%s", kind, namespace, name)
+		}
+		owners[key] = o
+
+		return o, nil
+	}
+
+	var orphans []Orphan
+	for _, obj := range objs {
+		reason, err := gc.orphanReason(obj, getOwner)
+		if err != nil {
+			return nil, err
+		}
+
+		if reason != "" {
+			orphans = append(orphans, Orphan{GeneratedObject: obj, Reason: reason})
+		}
+	}
+
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].Kind != orphans[j].Kind {
+			return orphans[i].Kind < orphans[j].Kind
+		}
+		if orphans[i].GetNamespace() != orphans[j].GetNamespace() {
+			return orphans[i].GetNamespace() < orphans[j].GetNamespace()
+		}
+		return orphans[i].GetName() < orphans[j].GetName()
+	})
+
+	return orphans, nil
+}
+
+// orphanReason returns why the object is orphaned or an empty string if it
+// is still needed. Objects without owner references to the controller's
+// resources are never orphaned.
+func (gc *GarbageCollector) orphanReason(obj GeneratedObject, getOwner func(kind, namespace, name string) (metav1.Object, error)) (string, error) {
+	var (
+		managed   bool
+		notNeeded []string
+	)
+
+	for _, ref := range obj.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil || gv.Group != monitoring.GroupName {
+			continue
+		}
+
+		if _, found := gc.ownerKinds[ref.Kind]; !found {
+			continue
+		}
+		managed = true
+
+		owner, err := getOwner(ref.Kind, obj.GetNamespace(), ref.Name)
+		if err != nil {
+			return "", err
+		}
+
+		if owner == nil || owner.GetUID() != ref.UID {
+			continue
+		}
+
+		if gc.needed == nil || gc.needed(ref.Kind, owner, obj) {
+			return "", nil
+		}
+
+		notNeeded = append(notNeeded, fmt.Sprintf("%s %s", ref.Kind, ref.Name))
+	}
+
+	if !managed {
+		return "", nil
+	}
+
+	if len(notNeeded) > 0 {
+		return fmt.Sprintf("not needed by %v", notNeeded), nil
+	}
+
+	return "owner not found", nil
+}
+
+// listGeneratedObjects returns the objects which may have been generated by
+// the controller in the managed namespaces.
+func (gc *GarbageCollector) listGeneratedObjects(ctx context.Context) ([]GeneratedObject, error) {
+	var namespaces []string
+	if _, found := gc.allowList[metav1.NamespaceAll]; found {
+		namespaces = []string{metav1.NamespaceAll}
+	} else {
+		for ns := range gc.allowList {
+			namespaces = append(namespaces, ns)
+		}
+		sort.Strings(namespaces)
+	}
+
+	var objs []GeneratedObject
+	for _, ns := range namespaces {
+		secrets, err := gc.kclient.CoreV1().Secrets(ns).List(ctx, metav1.ListOptions{LabelSelector: managedByOperatorSelector})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list secrets")
+		}
+		for i := range secrets.Items {
+			objs = append(objs, GeneratedObject{Kind: SecretKind, Object: &secrets.Items[i]})
+		}
+
+		cmaps, err := gc.kclient.CoreV1().ConfigMaps(ns).List(ctx, metav1.ListOptions{LabelSelector: managedByOperatorSelector})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list configmaps")
+		}
+		for i := range cmaps.Items {
+			objs = append(objs, GeneratedObject{Kind: ConfigMapKind, Object: &cmaps.Items[i]})
+		}
+
+		svcs, err := gc.kclient.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list services")
+		}
+		for i := range svcs.Items {
+			objs = append(objs, GeneratedObject{Kind: ServiceKind, Object: &svcs.Items[i]})
+		}
+
+		ssets, err := gc.kclient.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list statefulsets")
+		}
+		for i := range ssets.Items {
+			objs = append(objs, GeneratedObject{Kind: StatefulSetKind, Object: &ssets.Items[i]})
+		}
+	}
+
+	filtered := objs[:0]
+	for _, o := range objs {
+		if _, denied := gc.denyList[o.GetNamespace()]; denied {
+			continue
+		}
+
+		if gc.ownsNamespace != nil && !gc.ownsNamespace(o.GetNamespace()) {
+			continue
+		}
+
+		filtered = append(filtered, o)
+	}
+
+	return filtered, nil
+}
+
+func (gc *GarbageCollector) delete(ctx context.Context, o Orphan) error {
+	// The precondition prevents the deletion of an object recreated since
+	// it was listed.
+	uid := o.GetUID()
+	opts := metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	}
+
+	switch o.Kind {
+	case SecretKind:
+		return gc.kclient.CoreV1().Secrets(o.GetNamespace()).Delete(ctx, o.GetName(), opts)
+	case ConfigMapKind:
+		return gc.kclient.CoreV1().ConfigMaps(o.GetNamespace()).Delete(ctx, o.GetName(), opts)
+	case ServiceKind:
+		return gc.kclient.CoreV1().Services(o.GetNamespace()).Delete(ctx, o.GetName(), opts)
+	case StatefulSetKind:
+		propagationPolicy := metav1.DeletePropagationForeground
+		opts.PropagationPolicy = &propagationPolicy
+		return gc.kclient.AppsV1().StatefulSets(o.GetNamespace()).Delete(ctx, o.GetName(), opts)
+	}
+
+	return errors.Errorf("unsupported kind %q", o.Kind)
+}
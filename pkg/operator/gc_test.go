@@ -0,0 +1,153 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testOwnerRef(kind, name string, uid types.UID) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{
+		APIVersion: "monitoring.coreos.com/v1",
+		Kind:       kind,
+		Name:       name,
+		UID:        uid,
+	}}
+}
+
+func testObjectMeta(ns, name string, managed bool, refs []metav1.OwnerReference) metav1.ObjectMeta {
+	om := metav1.ObjectMeta{
+		Namespace:       ns,
+		Name:            name,
+		UID:             types.UID(ns + "/" + name),
+		OwnerReferences: refs,
+	}
+
+	if managed {
+		om.Labels = map[string]string{"managed-by": "prometheus-operator"}
+	}
+
+	return om
+}
+
+func TestGarbageCollector(t *testing.T) {
+	objs := []runtime.Object{
+		// Owner exists.
+		&v1.Secret{ObjectMeta: testObjectMeta("default", "live", true, testOwnerRef("Prometheus", "live", "1"))},
+		// Owner doesn't exist.
+		&v1.Secret{ObjectMeta: testObjectMeta("default", "gone", true, testOwnerRef("Prometheus", "gone", "2"))},
+		// Owner has been recreated.
+		&v1.Secret{ObjectMeta: testObjectMeta("default", "recreated", true, testOwnerRef("Prometheus", "live", "3"))},
+		// Not generated by the operator.
+		&v1.Secret{ObjectMeta: testObjectMeta("default", "unmanaged", false, testOwnerRef("Prometheus", "gone", "2"))},
+		// Owned by another kind.
+		&v1.ConfigMap{ObjectMeta: testObjectMeta("default", "other-kind", true, testOwnerRef("Alertmanager", "gone", "4"))},
+		// Not needed by its owner anymore.
+		&v1.ConfigMap{ObjectMeta: testObjectMeta("default", "stale", true, testOwnerRef("Prometheus", "live", "1"))},
+		// One of the owners still exists.
+		&v1.Service{ObjectMeta: testObjectMeta("default", "shared", false, append(testOwnerRef("Prometheus", "gone", "2"), testOwnerRef("Prometheus", "live", "1")...))},
+		// No owner reference.
+		&v1.Service{ObjectMeta: testObjectMeta("default", "orphan-without-owner", false, nil)},
+		// Denied namespace.
+		&appsv1.StatefulSet{ObjectMeta: testObjectMeta("denied", "gone", false, testOwnerRef("Prometheus", "gone", "2"))},
+		&appsv1.StatefulSet{ObjectMeta: testObjectMeta("default", "gone", false, testOwnerRef("Prometheus", "gone", "2"))},
+	}
+
+	newGC := func(dryRun bool) (*GarbageCollector, *fake.Clientset) {
+		kclient := fake.NewSimpleClientset(objs...)
+		gc := NewGarbageCollector(
+			log.NewNopLogger(),
+			kclient,
+			nil,
+			GarbageCollectorOptions{
+				AllowList:  map[string]struct{}{v1.NamespaceAll: {}},
+				DenyList:   map[string]struct{}{"denied": {}},
+				OwnerKinds: []string{"Prometheus"},
+				GetOwner: func(_ context.Context, kind, namespace, name string) (metav1.Object, error) {
+					if name != "live" {
+						return nil, nil
+					}
+					return &metav1.ObjectMeta{Namespace: namespace, Name: name, UID: "1"}, nil
+				},
+				Needed: func(_ string, _ metav1.Object, obj GeneratedObject) bool {
+					return obj.GetName() != "stale"
+				},
+				DryRun: dryRun,
+			},
+		)
+
+		return gc, kclient
+	}
+
+	gc, _ := newGC(false)
+	orphans, err := gc.FindOrphans(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		kind, name, reason string
+	}{
+		{kind: ConfigMapKind, name: "stale", reason: "not needed by [Prometheus live]"},
+		{kind: SecretKind, name: "gone", reason: "owner not found"},
+		{kind: SecretKind, name: "recreated", reason: "owner not found"},
+		{kind: StatefulSetKind, name: "gone", reason: "owner not found"},
+	}
+	if len(orphans) != len(expected) {
+		t.Fatalf("expected %d orphans, got %d: %v", len(expected), len(orphans), orphans)
+	}
+	for i, o := range orphans {
+		if o.Kind != expected[i].kind || o.GetName() != expected[i].name || o.Reason != expected[i].reason {
+			t.Fatalf("orphan %d: expected %v, got %s %s (%s)", i, expected[i], o.Kind, o.GetName(), o.Reason)
+		}
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		gc, kclient := newGC(dryRun)
+		if err := gc.Collect(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if v := testutil.ToFloat64(gc.orphaned.WithLabelValues(SecretKind)); v != 2 {
+			t.Fatalf("dryRun=%v: expected 2 orphaned secrets, got %v", dryRun, v)
+		}
+
+		_, err := kclient.CoreV1().Secrets("default").Get(context.Background(), "gone", metav1.GetOptions{})
+		if dryRun != !apierrors.IsNotFound(err) {
+			t.Fatalf("dryRun=%v: unexpected error getting the orphaned secret: %v", dryRun, err)
+		}
+
+		_, err = kclient.CoreV1().Secrets("default").Get(context.Background(), "live", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("dryRun=%v: expected the secret to exist, got %v", dryRun, err)
+		}
+
+		deleted := testutil.ToFloat64(gc.deleted.WithLabelValues(SecretKind))
+		if dryRun && deleted != 0 || !dryRun && deleted != 2 {
+			t.Fatalf("dryRun=%v: unexpected number of deleted secrets: %v", dryRun, deleted)
+		}
+	}
+}
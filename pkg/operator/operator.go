@@ -43,6 +43,12 @@ var (
 		[]string{"resource", "state"},
 		nil,
 	)
+	clampedResourcesDesc = prometheus.NewDesc(
+		"prometheus_operator_clamped_resources",
+		"Number of selected resources with scrape limits lowered to the enforced limits",
+		[]string{"resource"},
+		nil,
+	)
 )
 
 // Metrics represents metrics associated to an operator.
@@ -67,6 +73,7 @@ type Metrics struct {
 	mtx       sync.RWMutex
 	syncs     map[string]bool
 	resources map[resourceKey]map[string]int
+	clamped   map[string]map[string]int
 }
 
 type resourceKey struct {
@@ -120,6 +127,7 @@ func NewMetrics(name string, r prometheus.Registerer) *Metrics {
 
 		syncs:     make(map[string]bool),
 		resources: make(map[resourceKey]map[string]int),
+		clamped:   make(map[string]map[string]int),
 	}
 
 	m.reg.MustRegister(
@@ -197,6 +205,19 @@ func (m *Metrics) setResources(objKey string, resKey resourceKey, v int) {
 	m.resources[resKey][objKey] = v
 }
 
+// SetClampedResources sets the number of selected resources for the given
+// object's key whose scrape limits are lowered to the enforced limits.
+func (m *Metrics) SetClampedResources(objKey, resource string, v int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if _, found := m.clamped[resource]; !found {
+		m.clamped[resource] = make(map[string]int)
+	}
+
+	m.clamped[resource][objKey] = v
+}
+
 // SetSyncStatus tracks the status of the last sync operation for the given object.
 func (m *Metrics) SetSyncStatus(objKey string, success bool) {
 	m.mtx.Lock()
@@ -216,6 +237,10 @@ func (m *Metrics) ForgetObject(objKey string) {
 	for k := range m.resources {
 		delete(m.resources[k], objKey)
 	}
+
+	for k := range m.clamped {
+		delete(m.clamped[k], objKey)
+	}
 }
 
 // Ready returns a gauge to track whether the controller is ready or not.
@@ -231,6 +256,7 @@ func (m *Metrics) MustRegister(metrics ...prometheus.Collector) {
 // Describe implements the prometheus.Collector interface.
 func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
 	ch <- resourcesDesc
+	ch <- clampedResourcesDesc
 	ch <- syncsDesc
 }
 
@@ -274,6 +300,19 @@ func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
 			rKey.state.String(),
 		)
 	}
+
+	for resource := range m.clamped {
+		var total int
+		for _, v := range m.clamped[resource] {
+			total += v
+		}
+		ch <- prometheus.MustNewConstMetric(
+			clampedResourcesDesc,
+			prometheus.GaugeValue,
+			float64(total),
+			resource,
+		)
+	}
 }
 
 type instrumentedListerWatcher struct {
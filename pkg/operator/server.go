@@ -25,6 +25,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/component-base/cli/flag"
 )
 
@@ -93,3 +94,65 @@ func NewTLSConfig(logger log.Logger, certFile, keyFile, clientCAFile, minVersion
 
 	return tlsCfg, nil
 }
+
+// certificateExpiryCollector exposes the expiry time of the certificate
+// currently served. The certificate is fetched at every scrape so that
+// reloaded certificates are reflected.
+type certificateExpiryCollector struct {
+	logger         log.Logger
+	getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	desc           *prometheus.Desc
+}
+
+// NewCertificateExpiryCollector returns a collector exposing the expiry
+// timestamp of the certificate returned by getCertificate.
+func NewCertificateExpiryCollector(logger log.Logger, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) prometheus.Collector {
+	return &certificateExpiryCollector{
+		logger:         logger,
+		getCertificate: getCertificate,
+		desc: prometheus.NewDesc(
+			"prometheus_operator_tls_certificate_expiry_timestamp_seconds",
+			"Expiry time of the TLS certificate served by the operator in seconds since the Unix epoch.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *certificateExpiryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *certificateExpiryCollector) Collect(ch chan<- prometheus.Metric) {
+	cert, err := c.getCertificate(nil)
+	if err != nil {
+		level.Warn(c.logger).Log("msg", "failed to get the serving certificate", "err", err)
+		return
+	}
+
+	notAfter, err := certificateNotAfter(cert)
+	if err != nil {
+		level.Warn(c.logger).Log("msg", "failed to parse the serving certificate", "err", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(notAfter.Unix()))
+}
+
+func certificateNotAfter(cert *tls.Certificate) (time.Time, error) {
+	if cert == nil || len(cert.Certificate) == 0 {
+		return time.Time{}, errors.New("no certificate")
+	}
+
+	leaf := cert.Leaf
+	if leaf == nil {
+		var err error
+		leaf, err = x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return leaf.NotAfter, nil
+}
@@ -15,7 +15,19 @@
 package operator
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
 	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestNewTLSConfig(t *testing.T) {
@@ -24,3 +36,41 @@ func TestNewTLSConfig(t *testing.T) {
 		t.Errorf("expected tls err when client CA set without key and cert files")
 	}
 }
+
+func TestCertificateExpiryCollector(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notAfter := time.Date(2031, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "prometheus-operator"},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		cert    *tls.Certificate
+		certErr error
+	)
+	c := NewCertificateExpiryCollector(log.NewNopLogger(), func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return cert, certErr
+	})
+
+	// The certificate is read at every scrape.
+	cert = &tls.Certificate{Certificate: [][]byte{der}}
+	if v := testutil.ToFloat64(c); v != float64(notAfter.Unix()) {
+		t.Fatalf("expected %v, got %v", float64(notAfter.Unix()), v)
+	}
+
+	cert, certErr = nil, errors.New("no certificate")
+	if n := testutil.CollectAndCount(c); n != 0 {
+		t.Fatalf("expected no metric, got %d", n)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NamespaceSharding distributes the namespaces between several operator
+// instances. Each instance only reconciles the Prometheus, Alertmanager and
+// ThanosRuler resources of the namespaces assigned to its shard.
+type NamespaceSharding struct {
+	// Number of shards. Sharding is disabled when lower than 2.
+	Shards int
+	// Index of the shard managed by the operator instance, from 0 to Shards-1.
+	Index int
+	// Label of the namespaces whose value is the index of the shard managing
+	// them. Namespaces without the label are assigned by hashing their name.
+	Label string
+}
+
+// Enabled returns true when the namespaces are sharded.
+func (s NamespaceSharding) Enabled() bool {
+	return s.Shards > 1
+}
+
+// Validate checks that the shard index is consistent with the number of
+// shards.
+func (s NamespaceSharding) Validate() error {
+	if s.Shards < 0 {
+		return fmt.Errorf("number of shards must be positive, got %d", s.Shards)
+	}
+
+	if s.Index < 0 || (s.Enabled() && s.Index >= s.Shards) {
+		return fmt.Errorf("shard index must be between 0 and %d, got %d", s.Shards-1, s.Index)
+	}
+
+	if !s.Enabled() && (s.Index != 0 || s.Label != "") {
+		return fmt.Errorf("shard index and label require more than 1 shard")
+	}
+
+	return nil
+}
+
+// Owns returns true if the namespace is assigned to the shard of the operator
+// instance.
+func (s NamespaceSharding) Owns(ns *v1.Namespace) bool {
+	if !s.Enabled() {
+		return true
+	}
+
+	if s.Label != "" {
+		if v, ok := ns.Labels[s.Label]; ok {
+			i, err := strconv.Atoi(v)
+			return err == nil && i == s.Index
+		}
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(ns.Name))
+
+	return int(h.Sum32()%uint32(s.Shards)) == s.Index
+}
+
+// OwnsNamespace is like Owns but it looks up the namespace by name in the
+// given store. When the namespace isn't found, the assignment only depends on
+// the hash of its name.
+func (s NamespaceSharding) OwnsNamespace(store cache.Store, name string) bool {
+	if !s.Enabled() {
+		return true
+	}
+
+	obj, exists, err := store.GetByKey(name)
+	if err != nil || !exists {
+		return s.Owns(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+
+	return s.Owns(obj.(*v1.Namespace))
+}
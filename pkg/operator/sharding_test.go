@@ -0,0 +1,112 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestNamespaceShardingValidate(t *testing.T) {
+	for _, tc := range []struct {
+		sharding NamespaceSharding
+		valid    bool
+	}{
+		{sharding: NamespaceSharding{}, valid: true},
+		{sharding: NamespaceSharding{Shards: 1}, valid: true},
+		{sharding: NamespaceSharding{Shards: 3, Index: 2, Label: "shard"}, valid: true},
+		{sharding: NamespaceSharding{Shards: -1}, valid: false},
+		{sharding: NamespaceSharding{Shards: 3, Index: 3}, valid: false},
+		{sharding: NamespaceSharding{Shards: 3, Index: -1}, valid: false},
+		{sharding: NamespaceSharding{Shards: 1, Index: 1}, valid: false},
+		{sharding: NamespaceSharding{Shards: 1, Label: "shard"}, valid: false},
+	} {
+		t.Run(fmt.Sprintf("%+v", tc.sharding), func(t *testing.T) {
+			err := tc.sharding.Validate()
+			if tc.valid && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestNamespaceShardingOwns(t *testing.T) {
+	// Every namespace is owned by exactly one shard.
+	for i := 0; i < 100; i++ {
+		ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("ns-%d", i)}}
+
+		owners := 0
+		for shard := 0; shard < 3; shard++ {
+			if (NamespaceSharding{Shards: 3, Index: shard}).Owns(ns) {
+				owners++
+			}
+		}
+
+		if owners != 1 {
+			t.Fatalf("expected namespace %q to be owned by 1 shard, got %d", ns.Name, owners)
+		}
+	}
+
+	labeled := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "labeled",
+		Labels: map[string]string{"shard": "1"},
+	}}
+	for shard, expected := range []bool{false, true, false} {
+		if got := (NamespaceSharding{Shards: 3, Index: shard, Label: "shard"}).Owns(labeled); got != expected {
+			t.Fatalf("shard %d: expected %v, got %v", shard, expected, got)
+		}
+	}
+
+	invalid := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "invalid",
+		Labels: map[string]string{"shard": "foo"},
+	}}
+	for shard := 0; shard < 3; shard++ {
+		if (NamespaceSharding{Shards: 3, Index: shard, Label: "shard"}).Owns(invalid) {
+			t.Fatalf("expected namespace with invalid shard label to be ignored by shard %d", shard)
+		}
+	}
+
+	if !(NamespaceSharding{}).Owns(labeled) {
+		t.Fatal("expected all namespaces to be owned when sharding is disabled")
+	}
+}
+
+func TestNamespaceShardingOwnsNamespace(t *testing.T) {
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	if err := store.Add(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "labeled",
+		Labels: map[string]string{"shard": "1"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NamespaceSharding{Shards: 2, Index: 1, Label: "shard"}
+	if !s.OwnsNamespace(store, "labeled") {
+		t.Fatal("expected the labeled namespace to be owned")
+	}
+
+	unknown := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unknown"}}
+	if s.OwnsNamespace(store, "unknown") != s.Owns(unknown) {
+		t.Fatal("expected unknown namespaces to be assigned by hash")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// The default delays match workqueue.DefaultControllerRateLimiter().
+const (
+	DefaultWorkqueueBaseDelay = 5 * time.Millisecond
+	DefaultWorkqueueMaxDelay  = 1000 * time.Second
+)
+
+// RateLimiterConfig defines the rate limiting of the work queues of the
+// controllers.
+type RateLimiterConfig struct {
+	// BaseDelay and MaxDelay bound the exponential backoff applied to the
+	// items failing to reconcile.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// QPS and Burst limit the overall rate at which the items are requeued.
+	QPS   float64
+	Burst int
+}
+
+// Validate returns an error if the rate limiting parameters are invalid.
+func (c RateLimiterConfig) Validate() error {
+	if c.BaseDelay <= 0 {
+		return fmt.Errorf("the base delay must be positive, got %s", c.BaseDelay)
+	}
+
+	if c.MaxDelay < c.BaseDelay {
+		return fmt.Errorf("the max delay (%s) must be greater than or equal to the base delay (%s)", c.MaxDelay, c.BaseDelay)
+	}
+
+	if c.QPS <= 0 {
+		return fmt.Errorf("the QPS must be positive, got %v", c.QPS)
+	}
+
+	if c.Burst <= 0 {
+		return fmt.Errorf("the burst must be positive, got %d", c.Burst)
+	}
+
+	return nil
+}
+
+// NewRateLimiter returns a rate limiter combining a per-item exponential
+// backoff with an overall token bucket, like
+// workqueue.DefaultControllerRateLimiter(). The zero value of c returns the
+// default rate limiter.
+func (c RateLimiterConfig) NewRateLimiter() workqueue.RateLimiter {
+	if c == (RateLimiterConfig{}) {
+		return workqueue.DefaultControllerRateLimiter()
+	}
+
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(c.BaseDelay, c.MaxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(c.QPS), c.Burst)},
+	)
+}
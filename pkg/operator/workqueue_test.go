@@ -0,0 +1,92 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config RateLimiterConfig
+		err    bool
+	}{
+		{
+			name:   "valid",
+			config: RateLimiterConfig{BaseDelay: time.Millisecond, MaxDelay: time.Second, QPS: 10, Burst: 100},
+		},
+		{
+			name:   "zero base delay",
+			config: RateLimiterConfig{MaxDelay: time.Second, QPS: 10, Burst: 100},
+			err:    true,
+		},
+		{
+			name:   "max delay lower than base delay",
+			config: RateLimiterConfig{BaseDelay: time.Second, MaxDelay: time.Millisecond, QPS: 10, Burst: 100},
+			err:    true,
+		},
+		{
+			name:   "zero QPS",
+			config: RateLimiterConfig{BaseDelay: time.Millisecond, MaxDelay: time.Second, Burst: 100},
+			err:    true,
+		},
+		{
+			name:   "zero burst",
+			config: RateLimiterConfig{BaseDelay: time.Millisecond, MaxDelay: time.Second, QPS: 10},
+			err:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.err && err == nil {
+				t.Fatal("expected an error, got none")
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRateLimiterConfigNewRateLimiter(t *testing.T) {
+	rl := RateLimiterConfig{
+		BaseDelay: 10 * time.Millisecond,
+		MaxDelay:  40 * time.Millisecond,
+		QPS:       1000,
+		Burst:     1000,
+	}.NewRateLimiter()
+
+	for i, exp := range []time.Duration{
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		40 * time.Millisecond,
+		40 * time.Millisecond,
+	} {
+		if got := rl.When("key"); got != exp {
+			t.Fatalf("attempt %d: expected a delay of %s, got %s", i, exp, got)
+		}
+	}
+
+	if n := rl.NumRequeues("key"); n != 4 {
+		t.Fatalf("expected 4 requeues, got %d", n)
+	}
+
+	rl.Forget("key")
+	if got := rl.When("key"); got != 10*time.Millisecond {
+		t.Fatalf("expected the backoff to be reset, got %s", got)
+	}
+}
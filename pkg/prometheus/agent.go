@@ -0,0 +1,292 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultAgentVersion is the first Prometheus version supporting the
+	// agent mode.
+	defaultAgentVersion             = "v2.32.0"
+	agentFeature                    = "agent"
+	agentAppName                    = "prometheus-agent"
+	agentGoverningServiceName       = "prometheus-agent-operated"
+	agentStatefulSetNamePrefix      = "prom-agent"
+	prometheusStatefulSetNamePrefix = "prometheus"
+)
+
+var minAgentVersion = semver.MustParse("2.32.0")
+
+// isAgent returns true if the Prometheus object has been converted from a
+// PrometheusAgent object.
+func isAgent(p *monitoringv1.Prometheus) bool {
+	return p.Kind == monitoringv1alpha1.PrometheusAgentsKind
+}
+
+// prometheusFromAgent converts a PrometheusAgent object into the equivalent
+// Prometheus object so that the agents can be reconciled with the same code
+// as the Prometheus servers. The type metadata of the agent is preserved to
+// generate the right owner references.
+func prometheusFromAgent(a *monitoringv1alpha1.PrometheusAgent) *monitoringv1.Prometheus {
+	a = a.DeepCopy()
+
+	features := []string{agentFeature}
+	for _, f := range a.Spec.EnableFeatures {
+		if f != agentFeature {
+			features = append(features, f)
+		}
+	}
+
+	return &monitoringv1.Prometheus{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: monitoringv1alpha1.SchemeGroupVersion.String(),
+			Kind:       monitoringv1alpha1.PrometheusAgentsKind,
+		},
+		ObjectMeta: a.ObjectMeta,
+		Spec: monitoringv1.PrometheusSpec{
+			PodMetadata:                     a.Spec.PodMetadata,
+			ServiceMonitorSelector:          a.Spec.ServiceMonitorSelector,
+			ServiceMonitorNamespaceSelector: a.Spec.ServiceMonitorNamespaceSelector,
+			PodMonitorSelector:              a.Spec.PodMonitorSelector,
+			PodMonitorNamespaceSelector:     a.Spec.PodMonitorNamespaceSelector,
+			ProbeSelector:                   a.Spec.ProbeSelector,
+			ProbeNamespaceSelector:          a.Spec.ProbeNamespaceSelector,
+			ScrapeConfigSelector:            a.Spec.ScrapeConfigSelector,
+			ScrapeConfigNamespaceSelector:   a.Spec.ScrapeConfigNamespaceSelector,
+			ServiceDiscoveryRole:            a.Spec.ServiceDiscoveryRole,
+			Version:                         operator.StringValOrDefault(a.Spec.Version, defaultAgentVersion),
+			Paused:                          a.Spec.Paused,
+			Image:                           a.Spec.Image,
+			ImagePullSecrets:                a.Spec.ImagePullSecrets,
+			Replicas:                        a.Spec.Replicas,
+			Shards:                          a.Spec.Shards,
+			ReplicaExternalLabelName:        a.Spec.ReplicaExternalLabelName,
+			PrometheusExternalLabelName:     a.Spec.PrometheusExternalLabelName,
+			LogLevel:                        a.Spec.LogLevel,
+			LogFormat:                       a.Spec.LogFormat,
+			ScrapeInterval:                  a.Spec.ScrapeInterval,
+			ScrapeTimeout:                   a.Spec.ScrapeTimeout,
+			ExternalLabels:                  a.Spec.ExternalLabels,
+			EnableFeatures:                  features,
+			EnableNativeHistograms:          a.Spec.EnableNativeHistograms,
+			ExternalURL:                     a.Spec.ExternalURL,
+			RoutePrefix:                     a.Spec.RoutePrefix,
+			Storage:                         a.Spec.Storage,
+			Volumes:                         a.Spec.Volumes,
+			VolumeMounts:                    a.Spec.VolumeMounts,
+			Web:                             a.Spec.Web,
+			Resources:                       a.Spec.Resources,
+			NodeSelector:                    a.Spec.NodeSelector,
+			ServiceAccountName:              a.Spec.ServiceAccountName,
+			Secrets:                         a.Spec.Secrets,
+			ConfigMaps:                      a.Spec.ConfigMaps,
+			Affinity:                        a.Spec.Affinity,
+			Tolerations:                     a.Spec.Tolerations,
+			TopologySpreadConstraints:       a.Spec.TopologySpreadConstraints,
+			HostAliases:                     a.Spec.HostAliases,
+			DNSPolicy:                       a.Spec.DNSPolicy,
+			DNSConfig:                       a.Spec.DNSConfig,
+			RemoteWrite:                     a.Spec.RemoteWrite,
+			RemoteWriteToThanosReceive:      a.Spec.RemoteWriteToThanosReceive,
+			SecurityContext:                 a.Spec.SecurityContext,
+			ListenLocal:                     a.Spec.ListenLocal,
+			Containers:                      a.Spec.Containers,
+			InitContainers:                  a.Spec.InitContainers,
+			Sidecars:                        a.Spec.Sidecars,
+			AdditionalScrapeConfigs:         a.Spec.AdditionalScrapeConfigs,
+			APIServerConfig:                 a.Spec.APIServerConfig,
+			PriorityClassName:               a.Spec.PriorityClassName,
+			PortName:                        a.Spec.PortName,
+			ArbitraryFSAccessThroughSMs:     a.Spec.ArbitraryFSAccessThroughSMs,
+			OverrideHonorLabels:             a.Spec.OverrideHonorLabels,
+			OverrideHonorTimestamps:         a.Spec.OverrideHonorTimestamps,
+			IgnoreNamespaceSelectors:        a.Spec.IgnoreNamespaceSelectors,
+			EnforcedNamespaceLabel:          a.Spec.EnforcedNamespaceLabel,
+			EnforcedSampleLimit:             a.Spec.EnforcedSampleLimit,
+			EnforcedTargetLimit:             a.Spec.EnforcedTargetLimit,
+			EnforcedLabelLimit:              a.Spec.EnforcedLabelLimit,
+			EnforcedLabelNameLengthLimit:    a.Spec.EnforcedLabelNameLengthLimit,
+			EnforcedLabelValueLengthLimit:   a.Spec.EnforcedLabelValueLengthLimit,
+			EnforcedBodySizeLimit:           a.Spec.EnforcedBodySizeLimit,
+			MinReadySeconds:                 a.Spec.MinReadySeconds,
+		},
+	}
+}
+
+// metricsFor returns the operator metrics of the resource kind from which the
+// Prometheus object has been built.
+func (c *Operator) metricsFor(p *monitoringv1.Prometheus) *operator.Metrics {
+	if isAgent(p) {
+		return c.agentMetrics
+	}
+	return c.metrics
+}
+
+func (c *Operator) handleAgentAdd(obj interface{}) {
+	key, ok := c.keyFunc(obj)
+	if !ok {
+		return
+	}
+
+	level.Debug(c.logger).Log("msg", "PrometheusAgent added", "key", key)
+	c.agentMetrics.TriggerByCounter(monitoringv1alpha1.PrometheusAgentsKind, "add").Inc()
+	c.enqueueAgent(key)
+}
+
+func (c *Operator) handleAgentDelete(obj interface{}) {
+	key, ok := c.keyFunc(obj)
+	if !ok {
+		return
+	}
+
+	level.Debug(c.logger).Log("msg", "PrometheusAgent deleted", "key", key)
+	c.agentMetrics.TriggerByCounter(monitoringv1alpha1.PrometheusAgentsKind, "delete").Inc()
+	c.enqueueAgent(key)
+}
+
+func (c *Operator) handleAgentUpdate(old, cur interface{}) {
+	if old.(*monitoringv1alpha1.PrometheusAgent).ResourceVersion == cur.(*monitoringv1alpha1.PrometheusAgent).ResourceVersion {
+		return
+	}
+
+	key, ok := c.keyFunc(cur)
+	if !ok {
+		return
+	}
+
+	level.Debug(c.logger).Log("msg", "PrometheusAgent updated", "key", key)
+	c.agentMetrics.TriggerByCounter(monitoringv1alpha1.PrometheusAgentsKind, "update").Inc()
+	c.enqueueAgent(key)
+}
+
+// enqueueAgent adds a key to the PrometheusAgent queue. If obj is a key
+// already it gets added directly. Otherwise, the key is extracted via keyFunc.
+func (c *Operator) enqueueAgent(obj interface{}) {
+	if obj == nil {
+		return
+	}
+
+	key, ok := obj.(string)
+	if !ok {
+		key, ok = c.keyFunc(obj)
+		if !ok {
+			return
+		}
+	}
+
+	c.agentQueue.Add(key)
+}
+
+func (c *Operator) agentForStatefulSet(sset interface{}) *monitoringv1alpha1.PrometheusAgent {
+	if c.agentInfs == nil {
+		return nil
+	}
+
+	key, ok := c.keyFunc(sset)
+	if !ok {
+		return nil
+	}
+
+	match, agentKey := statefulSetKeyToAgentKey(key)
+	if !match {
+		return nil
+	}
+
+	a, err := c.agentInfs.Get(agentKey)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		level.Error(c.logger).Log("msg", "PrometheusAgent lookup failed", "err", err)
+		return nil
+	}
+
+	return a.(*monitoringv1alpha1.PrometheusAgent)
+}
+
+var agentKeyInShardStatefulSet = regexp.MustCompile("^(.+)/prom-agent-(.+)-shard-[1-9][0-9]*$")
+var agentKeyInStatefulSet = regexp.MustCompile("^(.+)/prom-agent-(.+)$")
+
+func statefulSetKeyToAgentKey(key string) (bool, string) {
+	r := agentKeyInStatefulSet
+	if agentKeyInShardStatefulSet.MatchString(key) {
+		r = agentKeyInShardStatefulSet
+	}
+
+	matches := r.FindAllStringSubmatch(key, 2)
+	if len(matches) != 1 {
+		return false, ""
+	}
+	if len(matches[0]) != 3 {
+		return false, ""
+	}
+	return true, matches[0][1] + "/" + matches[0][2]
+}
+
+func agentKeyToStatefulSetKey(key string, shard int) string {
+	keyParts := strings.Split(key, "/")
+	if shard == 0 {
+		return fmt.Sprintf("%s/%s-%s", keyParts[0], agentStatefulSetNamePrefix, keyParts[1])
+	}
+	return fmt.Sprintf("%s/%s-%s-shard-%d", keyParts[0], agentStatefulSetNamePrefix, keyParts[1], shard)
+}
+
+func (c *Operator) syncAgent(ctx context.Context, key string) error {
+	aobj, err := c.agentInfs.Get(key)
+
+	if apierrors.IsNotFound(err) {
+		c.agentMetrics.ForgetObject(key)
+		c.forgetDependent(dependent{kind: monitoringv1alpha1.PrometheusAgentsKind, key: key})
+		// Dependent resources are cleaned up by K8s via OwnerReferences
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	p := prometheusFromAgent(aobj.(*monitoringv1alpha1.PrometheusAgent))
+	if p.Spec.Paused {
+		return nil
+	}
+
+	if !c.config.Namespaces.Sharding.OwnsNamespace(c.nsPromInf.GetStore(), p.Namespace) {
+		level.Debug(c.logger).Log("msg", "skipping prometheus agent assigned to another shard", "key", key)
+		return nil
+	}
+
+	logger := log.With(c.logger, "key", key)
+	level.Info(logger).Log("msg", "sync prometheus agent")
+
+	// The agent mode doesn't support rules hence no rule ConfigMap is
+	// mounted into the pods.
+	return c.syncResources(ctx, logger, key, p, nil)
+}
@@ -0,0 +1,211 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func makeTestAgent(version string) *monitoringv1alpha1.PrometheusAgent {
+	return &monitoringv1alpha1.PrometheusAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: monitoringv1alpha1.PrometheusAgentSpec{
+			Version:        version,
+			EnableFeatures: []string{"expand-external-labels", "agent"},
+			RemoteWrite: []monitoringv1.RemoteWriteSpec{
+				{URL: "http://example.com/api/v1/write"},
+			},
+		},
+	}
+}
+
+func TestPrometheusFromAgent(t *testing.T) {
+	p := prometheusFromAgent(makeTestAgent(""))
+
+	if !isAgent(p) {
+		t.Fatalf("expected the Prometheus object to be flagged as an agent")
+	}
+
+	if p.APIVersion != monitoringv1alpha1.SchemeGroupVersion.String() {
+		t.Fatalf("expected API version %q, got %q", monitoringv1alpha1.SchemeGroupVersion.String(), p.APIVersion)
+	}
+
+	if p.Spec.Version != defaultAgentVersion {
+		t.Fatalf("expected version %q, got %q", defaultAgentVersion, p.Spec.Version)
+	}
+
+	expectedFeatures := []string{"agent", "expand-external-labels"}
+	if !reflect.DeepEqual(p.Spec.EnableFeatures, expectedFeatures) {
+		t.Fatalf("expected features %v, got %v", expectedFeatures, p.Spec.EnableFeatures)
+	}
+
+	if len(p.Spec.RemoteWrite) != 1 {
+		t.Fatalf("expected 1 remote-write endpoint, got %d", len(p.Spec.RemoteWrite))
+	}
+}
+
+func TestAgentStatefulSet(t *testing.T) {
+	p := prometheusFromAgent(makeTestAgent(""))
+
+	sset, err := makeStatefulSet(prometheusNameByShard(p, 0), *p, defaultTestConfig, nil, "", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
+	}
+
+	if sset.Name != "prom-agent-test" {
+		t.Fatalf("expected StatefulSet name %q, got %q", "prom-agent-test", sset.Name)
+	}
+
+	if sset.Spec.ServiceName != agentGoverningServiceName {
+		t.Fatalf("expected service name %q, got %q", agentGoverningServiceName, sset.Spec.ServiceName)
+	}
+
+	if sset.Spec.Template.Labels["app.kubernetes.io/name"] != agentAppName {
+		t.Fatalf("expected app name %q, got %q", agentAppName, sset.Spec.Template.Labels["app.kubernetes.io/name"])
+	}
+
+	args := sset.Spec.Template.Spec.Containers[0].Args
+	for _, expected := range []string{
+		"--storage.agent.path=/prometheus",
+		"--enable-feature=agent,expand-external-labels",
+	} {
+		found := false
+		for _, arg := range args {
+			if arg == expected {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("expected argument %q, got %v", expected, args)
+		}
+	}
+
+	for _, arg := range args {
+		for _, unexpected := range []string{"--storage.tsdb.", "--web.console."} {
+			if strings.HasPrefix(arg, unexpected) {
+				t.Fatalf("unexpected argument %q", arg)
+			}
+		}
+	}
+}
+
+func TestAgentUnsupportedVersion(t *testing.T) {
+	p := prometheusFromAgent(makeTestAgent("v2.31.1"))
+
+	_, err := makeStatefulSet(prometheusNameByShard(p, 0), *p, defaultTestConfig, nil, "", 0)
+	if err == nil {
+		t.Fatal("expected an error for Prometheus versions without the agent mode")
+	}
+}
+
+func TestAgentGenerateConfig(t *testing.T) {
+	p := prometheusFromAgent(makeTestAgent(""))
+
+	cg := &ConfigGenerator{}
+	cfg, err := cg.GenerateConfig(
+		p,
+		map[string]*monitoringv1.ServiceMonitor{},
+		nil,
+		nil,
+		nil,
+		&assets.Store{},
+		nil,
+		nil,
+		nil,
+		[]string{"rules-configmap"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, section := range []string{"rule_files:", "alerting:"} {
+		if strings.Contains(string(cfg), section) {
+			t.Fatalf("expected no %q section in the agent configuration, got:\n%s", section, cfg)
+		}
+	}
+
+	if !strings.Contains(string(cfg), "remote_write:") {
+		t.Fatalf("expected a remote_write section in the agent configuration, got:\n%s", cfg)
+	}
+}
+
+func TestStatefulSetKeyToAgentKey(t *testing.T) {
+	cases := []struct {
+		input         string
+		expectedKey   string
+		expectedMatch bool
+	}{
+		{
+			input:         "namespace/prom-agent-test",
+			expectedKey:   "namespace/test",
+			expectedMatch: true,
+		},
+		{
+			input:         "namespace/prom-agent-test-shard-1",
+			expectedKey:   "namespace/test",
+			expectedMatch: true,
+		},
+		{
+			input:         "namespace/prometheus-test",
+			expectedKey:   "",
+			expectedMatch: false,
+		},
+	}
+
+	for _, c := range cases {
+		match, key := statefulSetKeyToAgentKey(c.input)
+		if c.expectedKey != key {
+			t.Fatalf("Expected agent key %q got %q", c.expectedKey, key)
+		}
+		if c.expectedMatch != match {
+			t.Fatalf("Expected match %v for input %q, got %v", c.expectedMatch, c.input, match)
+		}
+	}
+}
+
+func TestAgentKeyToStatefulSetKey(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		shard    int
+		expected string
+	}{
+		{
+			name:     "namespace/test",
+			shard:    0,
+			expected: "namespace/prom-agent-test",
+		},
+		{
+			name:     "namespace/test",
+			shard:    1,
+			expected: "namespace/prom-agent-test-shard-1",
+		},
+	} {
+		got := agentKeyToStatefulSetKey(c.name, c.shard)
+		if c.expected != got {
+			t.Fatalf("Expected key %q got %q", c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"sort"
+	"sync"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+// dependent identifies a Prometheus or PrometheusAgent object by its kind and
+// its queue key (<namespace>/<name>).
+type dependent struct {
+	kind string
+	key  string
+}
+
+// dependentFor returns the dependent identifying p. The type metadata of p
+// must be set.
+func dependentFor(p *monitoringv1.Prometheus) dependent {
+	return dependent{kind: p.Kind, key: p.Namespace + "/" + p.Name}
+}
+
+// forgetDependent drops the state kept for a deleted or unmanaged
+// Prometheus or PrometheusAgent object.
+func (c *Operator) forgetDependent(d dependent) {
+	c.monitorDeps.forget(d)
+	c.configGenerator.forget(d)
+}
+
+// dependencyIndex records which Prometheus and PrometheusAgent objects
+// selected a given monitor resource at their last reconciliation. It allows
+// the operator to enqueue only the affected objects when a monitor changes.
+type dependencyIndex struct {
+	mtx sync.RWMutex
+	// monitors maps a monitor key to the objects which selected it.
+	monitors map[string]map[dependent]struct{}
+	// dependents maps an object to the monitor keys that it selected.
+	dependents map[dependent][]string
+}
+
+func newDependencyIndex() *dependencyIndex {
+	return &dependencyIndex{
+		monitors:   make(map[string]map[dependent]struct{}),
+		dependents: make(map[dependent][]string),
+	}
+}
+
+// monitorKey returns the index key of the monitor resource with the given
+// kind and <namespace>/<name> key.
+func monitorKey(kind, key string) string {
+	return kind + "/" + key
+}
+
+// set replaces the monitors selected by d.
+func (idx *dependencyIndex) set(d dependent, monitors []string) {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+
+	idx.remove(d)
+
+	if len(monitors) == 0 {
+		return
+	}
+
+	for _, m := range monitors {
+		if _, found := idx.monitors[m]; !found {
+			idx.monitors[m] = make(map[dependent]struct{})
+		}
+		idx.monitors[m][d] = struct{}{}
+	}
+	idx.dependents[d] = monitors
+}
+
+// forget removes d from the index.
+func (idx *dependencyIndex) forget(d dependent) {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+
+	idx.remove(d)
+}
+
+func (idx *dependencyIndex) remove(d dependent) {
+	for _, m := range idx.dependents[d] {
+		delete(idx.monitors[m], d)
+		if len(idx.monitors[m]) == 0 {
+			delete(idx.monitors, m)
+		}
+	}
+	delete(idx.dependents, d)
+}
+
+// dependentsOf returns the objects which selected the given monitor at their
+// last reconciliation, sorted by kind and key.
+func (idx *dependencyIndex) dependentsOf(monitor string) []dependent {
+	idx.mtx.RLock()
+	defer idx.mtx.RUnlock()
+
+	ret := make([]dependent, 0, len(idx.monitors[monitor]))
+	for d := range idx.monitors[monitor] {
+		ret = append(ret, d)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].kind != ret[j].kind {
+			return ret[i].kind < ret[j].kind
+		}
+		return ret[i].key < ret[j].key
+	})
+
+	return ret
+}
@@ -0,0 +1,153 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-kit/log"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+)
+
+func TestDependencyIndex(t *testing.T) {
+	idx := newDependencyIndex()
+
+	prom := dependent{kind: monitoringv1.PrometheusesKind, key: "default/prom"}
+	agent := dependent{kind: monitoringv1alpha1.PrometheusAgentsKind, key: "default/agent"}
+
+	smon := monitorKey(monitoringv1.ServiceMonitorsKind, "default/smon")
+	pmon := monitorKey(monitoringv1.PodMonitorsKind, "default/pmon")
+
+	idx.set(prom, []string{smon, pmon})
+	idx.set(agent, []string{smon})
+
+	if got, exp := idx.dependentsOf(smon), []dependent{prom, agent}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+
+	// The ServiceMonitor isn't selected anymore by the Prometheus object.
+	idx.set(prom, []string{pmon})
+	if got, exp := idx.dependentsOf(smon), []dependent{agent}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+
+	idx.forget(agent)
+	if got := idx.dependentsOf(smon); len(got) != 0 {
+		t.Fatalf("expected no dependent, got %v", got)
+	}
+	if got, exp := idx.dependentsOf(pmon), []dependent{prom}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+}
+
+func TestSelectsMonitor(t *testing.T) {
+	ns := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "monitoring",
+			Labels: map[string]string{"team": "a"},
+		},
+	}
+
+	smon := &monitoringv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "smon",
+			Namespace: "monitoring",
+			Labels:    map[string]string{"group": "a"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		spec     monitoringv1.PrometheusSpec
+		pNs      string
+		kind     string
+		expected bool
+	}{
+		{
+			name:     "nil selector",
+			pNs:      "monitoring",
+			kind:     monitoringv1.ServiceMonitorsKind,
+			expected: false,
+		},
+		{
+			name: "same namespace",
+			spec: monitoringv1.PrometheusSpec{
+				ServiceMonitorSelector: &metav1.LabelSelector{},
+			},
+			pNs:      "monitoring",
+			kind:     monitoringv1.ServiceMonitorsKind,
+			expected: true,
+		},
+		{
+			name: "other namespace without namespace selector",
+			spec: monitoringv1.PrometheusSpec{
+				ServiceMonitorSelector: &metav1.LabelSelector{},
+			},
+			pNs:      "default",
+			kind:     monitoringv1.ServiceMonitorsKind,
+			expected: false,
+		},
+		{
+			name: "matching namespace selector",
+			spec: monitoringv1.PrometheusSpec{
+				ServiceMonitorSelector:          &metav1.LabelSelector{MatchLabels: map[string]string{"group": "a"}},
+				ServiceMonitorNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			},
+			pNs:      "default",
+			kind:     monitoringv1.ServiceMonitorsKind,
+			expected: true,
+		},
+		{
+			name: "non-matching label selector",
+			spec: monitoringv1.PrometheusSpec{
+				ServiceMonitorSelector:          &metav1.LabelSelector{MatchLabels: map[string]string{"group": "b"}},
+				ServiceMonitorNamespaceSelector: &metav1.LabelSelector{},
+			},
+			pNs:      "default",
+			kind:     monitoringv1.ServiceMonitorsKind,
+			expected: false,
+		},
+		{
+			name: "selector of another kind",
+			spec: monitoringv1.PrometheusSpec{
+				PodMonitorSelector:          &metav1.LabelSelector{},
+				PodMonitorNamespaceSelector: &metav1.LabelSelector{},
+			},
+			pNs:      "default",
+			kind:     monitoringv1.ServiceMonitorsKind,
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Operator{logger: log.NewNopLogger()}
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: tc.pNs,
+				},
+				Spec: tc.spec,
+			}
+
+			if got := c.selectsMonitor(p, tc.kind, smon, ns); got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"github.com/blang/semver/v4"
+	"github.com/go-kit/log/level"
+	"github.com/mitchellh/hashstructure"
+	"gopkg.in/yaml.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+)
+
+// scrapeConfigFragment holds the scrape configurations generated for a
+// monitor resource and the hash of the inputs used to generate them.
+type scrapeConfigFragment struct {
+	hash          uint64
+	scrapeConfigs []yaml.MapSlice
+}
+
+// fragmentCache looks up and records the scrape configurations of the monitor
+// resources during a single GenerateConfig call.
+type fragmentCache struct {
+	// inputsHash is the hash of the inputs shared by all the monitor
+	// resources: the Prometheus spec, its version and the assets.
+	inputsHash uint64
+	previous   map[string]scrapeConfigFragment
+	current    map[string]scrapeConfigFragment
+}
+
+// newFragmentCache returns the fragment cache for the given Prometheus object.
+// It returns nil when the inputs can't be hashed in which case all the
+// fragments are generated.
+func (cg *ConfigGenerator) newFragmentCache(p *v1.Prometheus, version semver.Version, store *assets.Store) *fragmentCache {
+	if store == nil {
+		store = &assets.Store{}
+	}
+
+	inputsHash, err := hashstructure.Hash(struct {
+		Spec                     v1.PrometheusSpec
+		Version                  string
+		TLSAssets                map[assets.TLSAssetKey]assets.TLSAsset
+		TokenAssets              map[string]assets.Token
+		BasicAuthAssets          map[string]assets.BasicAuthCredentials
+		OAuth2Assets             map[string]assets.OAuth2Credentials
+		SigV4Assets              map[string]assets.SigV4Credentials
+		ProxyConnectHeaderAssets map[string]assets.ProxyConnectHeader
+		ProbeTargetsAssets       map[string][]string
+	}{
+		Spec:                     p.Spec,
+		Version:                  version.String(),
+		TLSAssets:                store.TLSAssets,
+		TokenAssets:              store.TokenAssets,
+		BasicAuthAssets:          store.BasicAuthAssets,
+		OAuth2Assets:             store.OAuth2Assets,
+		SigV4Assets:              store.SigV4Assets,
+		ProxyConnectHeaderAssets: store.ProxyConnectHeaderAssets,
+		ProbeTargetsAssets:       store.ProbeTargetsAssets,
+	}, nil)
+	if err != nil {
+		level.Warn(cg.logger).Log("msg", "failed to hash the configuration inputs, regenerating all scrape configurations", "err", err)
+		return nil
+	}
+
+	cg.mtx.Lock()
+	defer cg.mtx.Unlock()
+
+	return &fragmentCache{
+		inputsHash: inputsHash,
+		previous:   cg.fragments[dependentFor(p)],
+		current:    make(map[string]scrapeConfigFragment),
+	}
+}
+
+// get returns the scrape configurations of the monitor resource o. They are
+// reused from the previous generation if neither the monitor nor the shared
+// inputs have changed since then, otherwise generate is called.
+//
+// Objects without resource version (e.g. not retrieved from the API server)
+// are always generated.
+func (fc *fragmentCache) get(kind string, o metav1.Object, generate func() []yaml.MapSlice) []yaml.MapSlice {
+	if fc == nil || o.GetResourceVersion() == "" {
+		return generate()
+	}
+
+	key := monitorKey(kind, o.GetNamespace()+"/"+o.GetName())
+	hash, err := hashstructure.Hash(struct {
+		Inputs          uint64
+		UID             types.UID
+		ResourceVersion string
+	}{
+		Inputs:          fc.inputsHash,
+		UID:             o.GetUID(),
+		ResourceVersion: o.GetResourceVersion(),
+	}, nil)
+	if err != nil {
+		return generate()
+	}
+
+	if f, found := fc.previous[key]; found && f.hash == hash {
+		fc.current[key] = f
+		return f.scrapeConfigs
+	}
+
+	f := scrapeConfigFragment{
+		hash:          hash,
+		scrapeConfigs: generate(),
+	}
+	fc.current[key] = f
+
+	return f.scrapeConfigs
+}
+
+// storeFragments replaces the cached fragments of the Prometheus object with
+// the ones used by the last generation. The fragments of the monitors which
+// aren't selected anymore are dropped.
+func (cg *ConfigGenerator) storeFragments(p *v1.Prometheus, fc *fragmentCache) {
+	if fc == nil {
+		return
+	}
+
+	cg.mtx.Lock()
+	defer cg.mtx.Unlock()
+
+	if cg.fragments == nil {
+		cg.fragments = make(map[dependent]map[string]scrapeConfigFragment)
+	}
+	cg.fragments[dependentFor(p)] = fc.current
+}
+
+// forget drops the cached fragments of the Prometheus object.
+func (cg *ConfigGenerator) forget(d dependent) {
+	cg.mtx.Lock()
+	defer cg.mtx.Unlock()
+
+	delete(cg.fragments, d)
+}
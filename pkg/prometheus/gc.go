@@ -0,0 +1,122 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+// newGarbageCollector returns the garbage collector of the objects generated
+// for the Prometheus and PrometheusAgent resources.
+func (c *Operator) newGarbageCollector() *operator.GarbageCollector {
+	kinds := []string{monitoringv1.PrometheusesKind}
+	if c.agentInfs != nil {
+		kinds = append(kinds, monitoringv1alpha1.PrometheusAgentsKind)
+	}
+
+	return operator.NewGarbageCollector(
+		log.With(c.logger, "component", "garbage-collector"),
+		c.kclient,
+		c.metrics,
+		operator.GarbageCollectorOptions{
+			AllowList: c.config.Namespaces.PrometheusAllowList,
+			DenyList:  c.config.Namespaces.DenyList,
+			OwnsNamespace: func(ns string) bool {
+				return c.config.Namespaces.Sharding.OwnsNamespace(c.nsPromInf.GetStore(), ns)
+			},
+			OwnerKinds: kinds,
+			GetOwner:   c.getOwner,
+			Needed:     neededByPrometheus,
+			DryRun:     c.config.GarbageCollectionDryRun,
+		},
+	)
+}
+
+// getOwner returns the Prometheus or PrometheusAgent object from the API
+// server. The informers' caches can't be used because they only contain the
+// objects matching the instance selector.
+func (c *Operator) getOwner(ctx context.Context, kind, namespace, name string) (metav1.Object, error) {
+	var (
+		obj metav1.Object
+		err error
+	)
+
+	switch kind {
+	case monitoringv1.PrometheusesKind:
+		obj, err = c.mclient.MonitoringV1().Prometheuses(namespace).Get(ctx, name, metav1.GetOptions{})
+	case monitoringv1alpha1.PrometheusAgentsKind:
+		obj, err = c.mclient.MonitoringV1alpha1().PrometheusAgents(namespace).Get(ctx, name, metav1.GetOptions{})
+	default:
+		return nil, errors.Errorf("unsupported kind %q", kind)
+	}
+
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// neededByPrometheus returns false for the StatefulSets of the shards which
+// don't exist anymore and for the rule ConfigMaps which don't belong to the
+// owner.
+func neededByPrometheus(kind string, owner metav1.Object, obj operator.GeneratedObject) bool {
+	var p *monitoringv1.Prometheus
+	switch o := owner.(type) {
+	case *monitoringv1.Prometheus:
+		p = o
+	case *monitoringv1alpha1.PrometheusAgent:
+		p = prometheusFromAgent(o)
+	default:
+		return true
+	}
+
+	switch obj.Kind {
+	case operator.StatefulSetKind:
+		for _, name := range expectedStatefulSetShardNames(p) {
+			if obj.GetName() == name {
+				return true
+			}
+		}
+		return false
+
+	case operator.ConfigMapKind:
+		promName, found := obj.GetLabels()[labelPrometheusName]
+		if !found {
+			return true
+		}
+
+		// PrometheusAgent doesn't evaluate rules.
+		return kind == monitoringv1.PrometheusesKind &&
+			promName == p.Name &&
+			strings.HasPrefix(obj.GetName(), prometheusRuleConfigMapName(p.Name)+"-")
+	}
+
+	return true
+}
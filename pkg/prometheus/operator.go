@@ -19,14 +19,17 @@ import (
 	"compress/gzip"
 	"context"
 	"fmt"
+	"net/url"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/prometheus-operator/prometheus-operator/pkg/webconfig"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	monitoringclient "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
 	"github.com/prometheus-operator/prometheus-operator/pkg/informers"
@@ -34,6 +37,7 @@ import (
 	"github.com/prometheus-operator/prometheus-operator/pkg/listwatch"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 
+	"github.com/blang/semver/v4"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/mitchellh/hashstructure"
@@ -48,36 +52,50 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 )
 
 const (
 	resyncPeriod = 5 * time.Minute
+
+	// probeTargetsLabel is the label of the ConfigMaps and Secrets holding
+	// Probe targets which are watched by the operator.
+	probeTargetsLabel = "monitoring.coreos.com/probe-targets"
 )
 
 // Operator manages life cycle of Prometheus deployments and
 // monitoring configurations.
 type Operator struct {
-	kclient kubernetes.Interface
-	mclient monitoringclient.Interface
-	logger  log.Logger
+	kclient       kubernetes.Interface
+	mclient       monitoringclient.Interface
+	logger        log.Logger
+	eventRecorder record.EventRecorder
 
 	nsPromInf cache.SharedIndexInformer
 	nsMonInf  cache.SharedIndexInformer
 
 	promInfs  *informers.ForResource
+	agentInfs *informers.ForResource
 	smonInfs  *informers.ForResource
 	pmonInfs  *informers.ForResource
 	probeInfs *informers.ForResource
+	sconInfs  *informers.ForResource
 	ruleInfs  *informers.ForResource
 	cmapInfs  *informers.ForResource
 	secrInfs  *informers.ForResource
 	ssetInfs  *informers.ForResource
 
-	queue workqueue.RateLimitingInterface
+	probeTargetCmapInfs *informers.ForResource
+	probeTargetSecrInfs *informers.ForResource
+
+	queue      workqueue.RateLimitingInterface
+	agentQueue workqueue.RateLimitingInterface
 
-	metrics *operator.Metrics
+	metrics      *operator.Metrics
+	agentMetrics *operator.Metrics
 
 	nodeAddressLookupErrors prometheus.Counter
 	nodeEndpointSyncs       prometheus.Counter
@@ -90,6 +108,7 @@ type Operator struct {
 	config                 operator.Config
 
 	configGenerator *ConfigGenerator
+	monitorDeps     *dependencyIndex
 }
 
 // New creates a new controller.
@@ -118,6 +137,19 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 		return nil, errors.Wrap(err, "can not parse secrets selector value")
 	}
 
+	secretLabelSelector, err := labels.Parse(conf.SecretLabelSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "can not parse secrets label selector value")
+	}
+
+	var mdClient metadata.Interface
+	if conf.MetadataOnlyInformers {
+		mdClient, err = metadata.NewForConfig(cfg)
+		if err != nil {
+			return nil, errors.Wrap(err, "instantiating metadata client failed")
+		}
+	}
+
 	kubeletObjectName := ""
 	kubeletObjectNamespace := ""
 	kubeletSyncEnabled := false
@@ -136,14 +168,18 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 		kclient:                client,
 		mclient:                mclient,
 		logger:                 logger,
-		queue:                  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "prometheus"),
+		queue:                  workqueue.NewNamedRateLimitingQueue(conf.WorkqueueRateLimiter.NewRateLimiter(), "prometheus"),
+		agentQueue:             workqueue.NewNamedRateLimitingQueue(conf.WorkqueueRateLimiter.NewRateLimiter(), "prometheusagent"),
 		host:                   cfg.Host,
 		kubeletObjectName:      kubeletObjectName,
 		kubeletObjectNamespace: kubeletObjectNamespace,
 		kubeletSyncEnabled:     kubeletSyncEnabled,
 		config:                 conf,
 		configGenerator:        NewConfigGenerator(logger),
+		monitorDeps:            newDependencyIndex(),
+		eventRecorder:          operator.NewEventRecorder(client, "prometheus-controller"),
 		metrics:                operator.NewMetrics("prometheus", r),
+		agentMetrics:           operator.NewMetrics("prometheusagent", r),
 		nodeAddressLookupErrors: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: "prometheus_operator_node_address_lookup_errors_total",
 			Help: "Number of times a node IP address could not be determined",
@@ -181,6 +217,33 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 	}
 	c.metrics.MustRegister(newPrometheusCollectorForStores(promStores...))
 
+	// The PrometheusAgent CRD may not be installed when upgrading the
+	// operator, in which case the agents aren't reconciled.
+	agentSupported, err := k8sutil.IsAPIGroupVersionResourceSupported(client.Discovery(), monitoringv1alpha1.SchemeGroupVersion.String(), monitoringv1alpha1.PrometheusAgentName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check if the PrometheusAgent resource is supported")
+	}
+
+	if agentSupported {
+		c.agentInfs, err = informers.NewInformersForResource(
+			informers.NewMonitoringInformerFactories(
+				c.config.Namespaces.PrometheusAllowList,
+				c.config.Namespaces.DenyList,
+				mclient,
+				resyncPeriod,
+				func(options *metav1.ListOptions) {
+					options.LabelSelector = c.config.PromSelector
+				},
+			),
+			monitoringv1alpha1.SchemeGroupVersion.WithResource(monitoringv1alpha1.PrometheusAgentName),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating prometheusagent informers")
+		}
+	} else {
+		level.Warn(c.logger).Log("msg", "the PrometheusAgent resource isn't served by the API server, PrometheusAgent objects won't be reconciled")
+	}
+
 	c.smonInfs, err = informers.NewInformersForResource(
 		informers.NewMonitoringInformerFactories(
 			c.config.Namespaces.AllowList,
@@ -223,6 +286,31 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 		return nil, errors.Wrap(err, "error creating probe informers")
 	}
 
+	// The ScrapeConfig CRD may not be installed when upgrading the operator,
+	// in which case the ScrapeConfig objects are ignored.
+	sconSupported, err := k8sutil.IsAPIGroupVersionResourceSupported(client.Discovery(), monitoringv1alpha1.SchemeGroupVersion.String(), monitoringv1alpha1.ScrapeConfigName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check if the ScrapeConfig resource is supported")
+	}
+
+	if sconSupported {
+		c.sconInfs, err = informers.NewInformersForResource(
+			informers.NewMonitoringInformerFactories(
+				c.config.Namespaces.AllowList,
+				c.config.Namespaces.DenyList,
+				mclient,
+				resyncPeriod,
+				nil,
+			),
+			monitoringv1alpha1.SchemeGroupVersion.WithResource(monitoringv1alpha1.ScrapeConfigName),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating scrapeconfig informers")
+		}
+	} else {
+		level.Warn(c.logger).Log("msg", "the ScrapeConfig resource isn't served by the API server, ScrapeConfig objects won't be selected")
+	}
+
 	c.ruleInfs, err = informers.NewInformersForResource(
 		informers.NewMonitoringInformerFactories(
 			c.config.Namespaces.AllowList,
@@ -237,12 +325,32 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 		return nil, errors.Wrap(err, "error creating prometheusrule informers")
 	}
 
-	c.cmapInfs, err = informers.NewInformersForResource(
-		informers.NewKubeInformerFactories(
-			c.config.Namespaces.PrometheusAllowList,
+	// The controller only needs the metadata of the ConfigMaps and Secrets
+	// to trigger the reconciliations, their content is fetched from the API
+	// when generating the assets.
+	newAssetInformerFactories := func(allowNamespaces map[string]struct{}, tweakListOptions func(*metav1.ListOptions)) informers.FactoriesForNamespaces {
+		if mdClient != nil {
+			return informers.NewMetadataInformerFactories(
+				allowNamespaces,
+				c.config.Namespaces.DenyList,
+				mdClient,
+				resyncPeriod,
+				tweakListOptions,
+			)
+		}
+
+		return informers.NewKubeInformerFactories(
+			allowNamespaces,
 			c.config.Namespaces.DenyList,
 			c.kclient,
 			resyncPeriod,
+			tweakListOptions,
+		)
+	}
+
+	c.cmapInfs, err = informers.NewInformersForResource(
+		newAssetInformerFactories(
+			c.config.Namespaces.PrometheusAllowList,
 			func(options *metav1.ListOptions) {
 				options.LabelSelector = labelPrometheusName
 			},
@@ -254,13 +362,11 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 	}
 
 	c.secrInfs, err = informers.NewInformersForResource(
-		informers.NewKubeInformerFactories(
+		newAssetInformerFactories(
 			c.config.Namespaces.PrometheusAllowList,
-			c.config.Namespaces.DenyList,
-			c.kclient,
-			resyncPeriod,
 			func(options *metav1.ListOptions) {
 				options.FieldSelector = secretListWatchSelector.String()
+				options.LabelSelector = secretLabelSelector.String()
 			},
 		),
 		v1.SchemeGroupVersion.WithResource(string(v1.ResourceSecrets)),
@@ -269,6 +375,34 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 		return nil, errors.Wrap(err, "error creating secrets informers")
 	}
 
+	// Only the ConfigMaps and Secrets explicitly labeled as Probe targets are
+	// watched in the monitor namespaces.
+	probeTargetsListOptions := func(options *metav1.ListOptions) {
+		options.LabelSelector = probeTargetsLabel + "=true"
+	}
+
+	c.probeTargetCmapInfs, err = informers.NewInformersForResource(
+		newAssetInformerFactories(
+			c.config.Namespaces.AllowList,
+			probeTargetsListOptions,
+		),
+		v1.SchemeGroupVersion.WithResource(string(v1.ResourceConfigMaps)),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating probe targets configmap informers")
+	}
+
+	c.probeTargetSecrInfs, err = informers.NewInformersForResource(
+		newAssetInformerFactories(
+			c.config.Namespaces.AllowList,
+			probeTargetsListOptions,
+		),
+		v1.SchemeGroupVersion.WithResource(string(v1.ResourceSecrets)),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating probe targets secret informers")
+	}
+
 	c.ssetInfs, err = informers.NewInformersForResource(
 		informers.NewKubeInformerFactories(
 			c.config.Namespaces.PrometheusAllowList,
@@ -316,10 +450,12 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 
 // waitForCacheSync waits for the informers' caches to be synced.
 func (c *Operator) waitForCacheSync(ctx context.Context) error {
-	for _, infs := range []struct {
+	type namedInformers struct {
 		name                 string
 		informersForResource *informers.ForResource
-	}{
+	}
+
+	infs := []namedInformers{
 		{"Prometheus", c.promInfs},
 		{"ServiceMonitor", c.smonInfs},
 		{"PodMonitor", c.pmonInfs},
@@ -328,7 +464,17 @@ func (c *Operator) waitForCacheSync(ctx context.Context) error {
 		{"ConfigMap", c.cmapInfs},
 		{"Secret", c.secrInfs},
 		{"StatefulSet", c.ssetInfs},
-	} {
+		{"ProbeTargetsConfigMap", c.probeTargetCmapInfs},
+		{"ProbeTargetsSecret", c.probeTargetSecrInfs},
+	}
+	if c.agentInfs != nil {
+		infs = append(infs, namedInformers{"PrometheusAgent", c.agentInfs})
+	}
+	if c.sconInfs != nil {
+		infs = append(infs, namedInformers{"ScrapeConfig", c.sconInfs})
+	}
+
+	for _, infs := range infs {
 		for _, inf := range infs.informersForResource.GetInformers() {
 			if !operator.WaitForNamedCacheSync(ctx, "prometheus", log.With(c.logger, "informer", infs.name), inf.Informer()) {
 				return errors.Errorf("failed to sync cache for %s informer", infs.name)
@@ -360,6 +506,14 @@ func (c *Operator) addHandlers() {
 		UpdateFunc: c.handlePrometheusUpdate,
 	})
 
+	if c.agentInfs != nil {
+		c.agentInfs.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleAgentAdd,
+			DeleteFunc: c.handleAgentDelete,
+			UpdateFunc: c.handleAgentUpdate,
+		})
+	}
+
 	c.smonInfs.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.handleSmonAdd,
 		DeleteFunc: c.handleSmonDelete,
@@ -376,6 +530,13 @@ func (c *Operator) addHandlers() {
 		UpdateFunc: c.handleBmonUpdate,
 		DeleteFunc: c.handleBmonDelete,
 	})
+	if c.sconInfs != nil {
+		c.sconInfs.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleScrapeConfigAdd,
+			UpdateFunc: c.handleScrapeConfigUpdate,
+			DeleteFunc: c.handleScrapeConfigDelete,
+		})
+	}
 	c.ruleInfs.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.handleRuleAdd,
 		DeleteFunc: c.handleRuleDelete,
@@ -391,6 +552,8 @@ func (c *Operator) addHandlers() {
 		DeleteFunc: c.handleSecretDelete,
 		UpdateFunc: c.handleSecretUpdate,
 	})
+	c.probeTargetCmapInfs.AddEventHandler(c.probeTargetsEventHandler("ConfigMap"))
+	c.probeTargetSecrInfs.AddEventHandler(c.probeTargetsEventHandler("Secret"))
 	c.ssetInfs.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.handleStatefulSetAdd,
 		DeleteFunc: c.handleStatefulSetDelete,
@@ -407,10 +570,12 @@ func (c *Operator) addHandlers() {
 	})
 }
 
-// Run the controller.
-func (c *Operator) Run(ctx context.Context) error {
-	defer c.queue.ShutDown()
-
+// StartInformers checks the connection to the API server, starts the
+// informers and waits for their caches to be synced. The informers run until
+// the context is canceled. It is called by all the replicas of the operator
+// because the admission webhook reads the caches, even when the replica
+// doesn't hold the leadership.
+func (c *Operator) StartInformers(ctx context.Context) error {
 	errChan := make(chan error)
 	go func() {
 		v, err := c.kclient.Discovery().ServerVersion()
@@ -432,15 +597,21 @@ func (c *Operator) Run(ctx context.Context) error {
 		return nil
 	}
 
-	go c.worker(ctx)
-
 	go c.promInfs.Start(ctx.Done())
+	if c.agentInfs != nil {
+		go c.agentInfs.Start(ctx.Done())
+	}
 	go c.smonInfs.Start(ctx.Done())
 	go c.pmonInfs.Start(ctx.Done())
 	go c.probeInfs.Start(ctx.Done())
+	if c.sconInfs != nil {
+		go c.sconInfs.Start(ctx.Done())
+	}
 	go c.ruleInfs.Start(ctx.Done())
 	go c.cmapInfs.Start(ctx.Done())
 	go c.secrInfs.Start(ctx.Done())
+	go c.probeTargetCmapInfs.Start(ctx.Done())
+	go c.probeTargetSecrInfs.Start(ctx.Done())
 	go c.ssetInfs.Start(ctx.Done())
 	go c.nsMonInf.Run(ctx.Done())
 	if c.nsPromInf != c.nsMonInf {
@@ -451,11 +622,38 @@ func (c *Operator) Run(ctx context.Context) error {
 	}
 	c.addHandlers()
 
+	return nil
+}
+
+// Run starts the reconciliation workers, the synchronization of the kubelet
+// endpoints and the garbage collector and blocks until the context is
+// canceled. StartInformers must have returned before Run is called. With
+// leader election, only the leader calls Run.
+func (c *Operator) Run(ctx context.Context) error {
+	defer c.queue.ShutDown()
+	defer c.agentQueue.ShutDown()
+
+	for i := 0; i < c.config.PrometheusWorkers; i++ {
+		go c.worker(ctx, c.queue, c.metrics, c.sync)
+	}
+	if c.agentInfs != nil {
+		for i := 0; i < c.config.PrometheusWorkers; i++ {
+			go c.worker(ctx, c.agentQueue, c.agentMetrics, c.syncAgent)
+		}
+	}
+
 	if c.kubeletSyncEnabled {
 		go c.reconcileNodeEndpoints(ctx)
 	}
 
+	if c.config.GarbageCollection {
+		go c.newGarbageCollector().Run(ctx, operator.DefaultGarbageCollectionInterval)
+	}
+
 	c.metrics.Ready().Set(1)
+	if c.agentInfs != nil {
+		c.agentMetrics.Ready().Set(1)
+	}
 	<-ctx.Done()
 	return nil
 }
@@ -656,13 +854,13 @@ func (c *Operator) syncNodeEndpoints(ctx context.Context) error {
 	}
 
 	level.Debug(logger).Log("msg", "Updating Kubernetes service", "service", c.kubeletObjectName, "ns", c.kubeletObjectNamespace)
-	err = k8sutil.CreateOrUpdateService(ctx, c.kclient.CoreV1().Services(c.kubeletObjectNamespace), svc)
+	err = k8sutil.ApplyService(ctx, c.kclient.CoreV1().Services(c.kubeletObjectNamespace), svc, nil)
 	if err != nil {
 		return errors.Wrap(err, "synchronizing kubelet service object failed")
 	}
 
 	level.Debug(logger).Log("msg", "Updating Kubernetes endpoint", "endpoint", c.kubeletObjectName, "ns", c.kubeletObjectNamespace)
-	err = k8sutil.CreateOrUpdateEndpoints(ctx, c.kclient.CoreV1().Endpoints(c.kubeletObjectNamespace), eps)
+	err = k8sutil.ApplyEndpoints(ctx, c.kclient.CoreV1().Endpoints(c.kubeletObjectNamespace), eps, nil)
 	if err != nil {
 		return errors.Wrap(err, "synchronizing kubelet endpoints object failed")
 	}
@@ -670,18 +868,16 @@ func (c *Operator) syncNodeEndpoints(ctx context.Context) error {
 	return nil
 }
 
-// TODO: Don't enqueue just for the namespace
 func (c *Operator) handleSmonAdd(obj interface{}) {
 	o, ok := c.getObject(obj)
 	if ok {
 		level.Debug(c.logger).Log("msg", "ServiceMonitor added")
 		c.metrics.TriggerByCounter(monitoringv1.ServiceMonitorsKind, "add").Inc()
 
-		c.enqueueForMonitorNamespace(o.GetNamespace())
+		c.enqueueForMonitor(monitoringv1.ServiceMonitorsKind, o)
 	}
 }
 
-// TODO: Don't enqueue just for the namespace
 func (c *Operator) handleSmonUpdate(old, cur interface{}) {
 	if old.(*monitoringv1.ServiceMonitor).ResourceVersion == cur.(*monitoringv1.ServiceMonitor).ResourceVersion {
 		return
@@ -692,32 +888,29 @@ func (c *Operator) handleSmonUpdate(old, cur interface{}) {
 		level.Debug(c.logger).Log("msg", "ServiceMonitor updated")
 		c.metrics.TriggerByCounter(monitoringv1.ServiceMonitorsKind, "update").Inc()
 
-		c.enqueueForMonitorNamespace(o.GetNamespace())
+		c.enqueueForMonitor(monitoringv1.ServiceMonitorsKind, o)
 	}
 }
 
-// TODO: Don't enqueue just for the namespace
 func (c *Operator) handleSmonDelete(obj interface{}) {
 	o, ok := c.getObject(obj)
 	if ok {
 		level.Debug(c.logger).Log("msg", "ServiceMonitor delete")
 		c.metrics.TriggerByCounter(monitoringv1.ServiceMonitorsKind, "delete").Inc()
 
-		c.enqueueForMonitorNamespace(o.GetNamespace())
+		c.enqueueForMonitor(monitoringv1.ServiceMonitorsKind, o)
 	}
 }
 
-// TODO: Don't enqueue just for the namespace
 func (c *Operator) handlePmonAdd(obj interface{}) {
 	o, ok := c.getObject(obj)
 	if ok {
 		level.Debug(c.logger).Log("msg", "PodMonitor added")
 		c.metrics.TriggerByCounter(monitoringv1.PodMonitorsKind, "add").Inc()
-		c.enqueueForMonitorNamespace(o.GetNamespace())
+		c.enqueueForMonitor(monitoringv1.PodMonitorsKind, o)
 	}
 }
 
-// TODO: Don't enqueue just for the namespace
 func (c *Operator) handlePmonUpdate(old, cur interface{}) {
 	if old.(*monitoringv1.PodMonitor).ResourceVersion == cur.(*monitoringv1.PodMonitor).ResourceVersion {
 		return
@@ -728,31 +921,28 @@ func (c *Operator) handlePmonUpdate(old, cur interface{}) {
 		level.Debug(c.logger).Log("msg", "PodMonitor updated")
 		c.metrics.TriggerByCounter(monitoringv1.PodMonitorsKind, "update").Inc()
 
-		c.enqueueForMonitorNamespace(o.GetNamespace())
+		c.enqueueForMonitor(monitoringv1.PodMonitorsKind, o)
 	}
 }
 
-// TODO: Don't enqueue just for the namespace
 func (c *Operator) handlePmonDelete(obj interface{}) {
 	o, ok := c.getObject(obj)
 	if ok {
 		level.Debug(c.logger).Log("msg", "PodMonitor delete")
 		c.metrics.TriggerByCounter(monitoringv1.PodMonitorsKind, "delete").Inc()
 
-		c.enqueueForMonitorNamespace(o.GetNamespace())
+		c.enqueueForMonitor(monitoringv1.PodMonitorsKind, o)
 	}
 }
 
-// TODO: Don't enqueue just for the namespace
 func (c *Operator) handleBmonAdd(obj interface{}) {
 	if o, ok := c.getObject(obj); ok {
 		level.Debug(c.logger).Log("msg", "Probe added")
 		c.metrics.TriggerByCounter(monitoringv1.ProbesKind, "add").Inc()
-		c.enqueueForMonitorNamespace(o.GetNamespace())
+		c.enqueueForMonitor(monitoringv1.ProbesKind, o)
 	}
 }
 
-// TODO: Don't enqueue just for the namespace
 func (c *Operator) handleBmonUpdate(old, cur interface{}) {
 	if old.(*monitoringv1.Probe).ResourceVersion == cur.(*monitoringv1.Probe).ResourceVersion {
 		return
@@ -761,16 +951,43 @@ func (c *Operator) handleBmonUpdate(old, cur interface{}) {
 	if o, ok := c.getObject(cur); ok {
 		level.Debug(c.logger).Log("msg", "Probe updated")
 		c.metrics.TriggerByCounter(monitoringv1.ProbesKind, "update")
-		c.enqueueForMonitorNamespace(o.GetNamespace())
+		c.enqueueForMonitor(monitoringv1.ProbesKind, o)
 	}
 }
 
-// TODO: Don't enqueue just for the namespace
 func (c *Operator) handleBmonDelete(obj interface{}) {
 	if o, ok := c.getObject(obj); ok {
 		level.Debug(c.logger).Log("msg", "Probe delete")
 		c.metrics.TriggerByCounter(monitoringv1.ProbesKind, "delete").Inc()
-		c.enqueueForMonitorNamespace(o.GetNamespace())
+		c.enqueueForMonitor(monitoringv1.ProbesKind, o)
+	}
+}
+
+func (c *Operator) handleScrapeConfigAdd(obj interface{}) {
+	if o, ok := c.getObject(obj); ok {
+		level.Debug(c.logger).Log("msg", "ScrapeConfig added")
+		c.metrics.TriggerByCounter(monitoringv1alpha1.ScrapeConfigsKind, "add").Inc()
+		c.enqueueForMonitor(monitoringv1alpha1.ScrapeConfigsKind, o)
+	}
+}
+
+func (c *Operator) handleScrapeConfigUpdate(old, cur interface{}) {
+	if old.(*monitoringv1alpha1.ScrapeConfig).ResourceVersion == cur.(*monitoringv1alpha1.ScrapeConfig).ResourceVersion {
+		return
+	}
+
+	if o, ok := c.getObject(cur); ok {
+		level.Debug(c.logger).Log("msg", "ScrapeConfig updated")
+		c.metrics.TriggerByCounter(monitoringv1alpha1.ScrapeConfigsKind, "update").Inc()
+		c.enqueueForMonitor(monitoringv1alpha1.ScrapeConfigsKind, o)
+	}
+}
+
+func (c *Operator) handleScrapeConfigDelete(obj interface{}) {
+	if o, ok := c.getObject(obj); ok {
+		level.Debug(c.logger).Log("msg", "ScrapeConfig deleted")
+		c.metrics.TriggerByCounter(monitoringv1alpha1.ScrapeConfigsKind, "delete").Inc()
+		c.enqueueForMonitor(monitoringv1alpha1.ScrapeConfigsKind, o)
 	}
 }
 
@@ -823,12 +1040,13 @@ func (c *Operator) handleSecretDelete(obj interface{}) {
 }
 
 func (c *Operator) handleSecretUpdate(old, cur interface{}) {
-	if old.(*v1.Secret).ResourceVersion == cur.(*v1.Secret).ResourceVersion {
+	oldObj, ok := c.getObject(old)
+	if !ok {
 		return
 	}
 
 	o, ok := c.getObject(cur)
-	if ok {
+	if ok && oldObj.GetResourceVersion() != o.GetResourceVersion() {
 		level.Debug(c.logger).Log("msg", "Secret updated")
 		c.metrics.TriggerByCounter("Secret", "update").Inc()
 
@@ -868,12 +1086,13 @@ func (c *Operator) handleConfigMapDelete(obj interface{}) {
 }
 
 func (c *Operator) handleConfigMapUpdate(old, cur interface{}) {
-	if old.(*v1.ConfigMap).ResourceVersion == cur.(*v1.ConfigMap).ResourceVersion {
+	oldObj, ok := c.getObject(old)
+	if !ok {
 		return
 	}
 
 	o, ok := c.getObject(cur)
-	if ok {
+	if ok && oldObj.GetResourceVersion() != o.GetResourceVersion() {
 		level.Debug(c.logger).Log("msg", "ConfigMap updated")
 		c.metrics.TriggerByCounter("ConfigMap", "update").Inc()
 
@@ -881,6 +1100,44 @@ func (c *Operator) handleConfigMapUpdate(old, cur interface{}) {
 	}
 }
 
+// probeTargetsEventHandler returns the event handlers for the ConfigMaps and
+// Secrets holding Probe targets. Any change triggers the reconciliation of the
+// Prometheus objects selecting the namespace.
+func (c *Operator) probeTargetsEventHandler(kind string) cache.ResourceEventHandlerFuncs {
+	enqueue := func(obj interface{}, event string) {
+		o, ok := c.getObject(obj)
+		if !ok {
+			return
+		}
+
+		level.Debug(c.logger).Log("msg", "Probe targets changed", "kind", kind, "event", event, "namespace", o.GetNamespace(), "name", o.GetName())
+		c.metrics.TriggerByCounter(kind, event).Inc()
+		c.enqueueForMonitorNamespace(o.GetNamespace())
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			enqueue(obj, "add")
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			oldObj, ok := c.getObject(old)
+			if !ok {
+				return
+			}
+
+			curObj, ok := c.getObject(cur)
+			if !ok || oldObj.GetResourceVersion() == curObj.GetResourceVersion() {
+				return
+			}
+
+			enqueue(cur, "update")
+		},
+		DeleteFunc: func(obj interface{}) {
+			enqueue(obj, "delete")
+		},
+	}
+}
+
 func (c *Operator) getObject(obj interface{}) (metav1.Object, bool) {
 	ts, ok := obj.(cache.DeletedFinalStateUnknown)
 	if ok {
@@ -921,10 +1178,22 @@ func (c *Operator) enqueueForMonitorNamespace(nsName string) {
 	c.enqueueForNamespace(c.nsMonInf.GetStore(), nsName)
 }
 
-// enqueueForNamespace enqueues all Prometheus object keys that belong to the
-// given namespace or select objects in the given namespace.
-func (c *Operator) enqueueForNamespace(store cache.Store, nsName string) {
-	nsObject, exists, err := store.GetByKey(nsName)
+// enqueueForMonitor enqueues the Prometheus and PrometheusAgent objects which
+// selected the monitor resource at their last reconciliation as well as the
+// ones selecting it now. The other objects watching the monitor's namespace
+// aren't affected by the change and don't need to be reconciled.
+func (c *Operator) enqueueForMonitor(kind string, o metav1.Object) {
+	enqueued := map[dependent]struct{}{}
+	for _, d := range c.monitorDeps.dependentsOf(monitorKey(kind, o.GetNamespace()+"/"+o.GetName())) {
+		if d.kind == monitoringv1alpha1.PrometheusAgentsKind {
+			c.enqueueAgent(d.key)
+		} else {
+			c.enqueue(d.key)
+		}
+		enqueued[d] = struct{}{}
+	}
+
+	nsObject, exists, err := c.nsMonInf.GetStore().GetByKey(o.GetNamespace())
 	if err != nil {
 		level.Error(c.logger).Log(
 			"msg", "get namespace to enqueue Prometheus instances failed",
@@ -933,81 +1202,126 @@ func (c *Operator) enqueueForNamespace(store cache.Store, nsName string) {
 		return
 	}
 	if !exists {
-		level.Error(c.logger).Log(
-			"msg", fmt.Sprintf("get namespace to enqueue Prometheus instances failed: namespace %q does not exist", nsName),
-		)
+		// The namespace is being deleted, the previous dependents have
+		// already been enqueued.
 		return
 	}
 	ns := nsObject.(*v1.Namespace)
 
 	err = c.promInfs.ListAll(labels.Everything(), func(obj interface{}) {
-		// Check for Prometheus instances in the namespace.
 		p := obj.(*monitoringv1.Prometheus)
-		if p.Namespace == nsName {
-			c.enqueue(p)
-			return
-		}
-
-		// Check for Prometheus instances selecting ServiceMonitors in
-		// the namespace.
-		smNSSelector, err := metav1.LabelSelectorAsSelector(p.Spec.ServiceMonitorNamespaceSelector)
-		if err != nil {
-			level.Error(c.logger).Log(
-				"msg", fmt.Sprintf("failed to convert ServiceMonitorNamespaceSelector of %q to selector", p.Name),
-				"err", err,
-			)
+		if _, found := enqueued[dependent{kind: monitoringv1.PrometheusesKind, key: p.Namespace + "/" + p.Name}]; found {
 			return
 		}
 
-		if smNSSelector.Matches(labels.Set(ns.Labels)) {
+		if c.selectsMonitor(p, kind, o, ns) {
 			c.enqueue(p)
-			return
 		}
+	})
+	if err != nil {
+		level.Error(c.logger).Log(
+			"msg", "listing all Prometheus instances from cache failed",
+			"err", err,
+		)
+	}
 
-		// Check for Prometheus instances selecting PodMonitors in the NS.
-		pmNSSelector, err := metav1.LabelSelectorAsSelector(p.Spec.PodMonitorNamespaceSelector)
-		if err != nil {
-			level.Error(c.logger).Log(
-				"msg", fmt.Sprintf("failed to convert PodMonitorNamespaceSelector of %q to selector", p.Name),
-				"err", err,
-			)
+	if c.agentInfs == nil {
+		return
+	}
+
+	err = c.agentInfs.ListAll(labels.Everything(), func(obj interface{}) {
+		a := obj.(*monitoringv1alpha1.PrometheusAgent)
+		if _, found := enqueued[dependent{kind: monitoringv1alpha1.PrometheusAgentsKind, key: a.Namespace + "/" + a.Name}]; found {
 			return
 		}
 
-		if pmNSSelector.Matches(labels.Set(ns.Labels)) {
-			c.enqueue(p)
-			return
+		if c.selectsMonitor(prometheusFromAgent(a), kind, o, ns) {
+			c.enqueueAgent(a)
 		}
+	})
+	if err != nil {
+		level.Error(c.logger).Log(
+			"msg", "listing all PrometheusAgent instances from cache failed",
+			"err", err,
+		)
+	}
+}
+
+// selectsMonitor returns true if the Prometheus object selects the monitor
+// resource of the given kind living in the ns namespace.
+func (c *Operator) selectsMonitor(p *monitoringv1.Prometheus, kind string, o metav1.Object, ns *v1.Namespace) bool {
+	var selector, nsSelector *metav1.LabelSelector
+	switch kind {
+	case monitoringv1.ServiceMonitorsKind:
+		selector, nsSelector = p.Spec.ServiceMonitorSelector, p.Spec.ServiceMonitorNamespaceSelector
+	case monitoringv1.PodMonitorsKind:
+		selector, nsSelector = p.Spec.PodMonitorSelector, p.Spec.PodMonitorNamespaceSelector
+	case monitoringv1.ProbesKind:
+		selector, nsSelector = p.Spec.ProbeSelector, p.Spec.ProbeNamespaceSelector
+	case monitoringv1alpha1.ScrapeConfigsKind:
+		selector, nsSelector = p.Spec.ScrapeConfigSelector, p.Spec.ScrapeConfigNamespaceSelector
+	}
+
+	if selector == nil {
+		return false
+	}
 
-		// Check for Prometheus instances selecting Probes in the NS.
-		bmNSSelector, err := metav1.LabelSelectorAsSelector(p.Spec.ProbeNamespaceSelector)
+	// If the namespace selector is nil, only the Prometheus namespace is
+	// considered.
+	if nsSelector == nil {
+		if p.Namespace != ns.Name {
+			return false
+		}
+	} else {
+		s, err := metav1.LabelSelectorAsSelector(nsSelector)
 		if err != nil {
 			level.Error(c.logger).Log(
-				"msg", fmt.Sprintf("failed to convert ProbeNamespaceSelector of %q to selector", p.Name),
+				"msg", fmt.Sprintf("failed to convert the %s namespace selector of %q to selector", kind, p.Name),
 				"err", err,
 			)
-			return
+			return false
 		}
 
-		if bmNSSelector.Matches(labels.Set(ns.Labels)) {
-			c.enqueue(p)
-			return
+		if !s.Matches(labels.Set(ns.Labels)) {
+			return false
 		}
+	}
 
-		// Check for Prometheus instances selecting PrometheusRules in
-		// the NS.
-		ruleNSSelector, err := metav1.LabelSelectorAsSelector(p.Spec.RuleNamespaceSelector)
-		if err != nil {
-			level.Error(c.logger).Log(
-				"msg", fmt.Sprintf("failed to convert RuleNamespaceSelector of %q to selector", p.Name),
-				"err", err,
-			)
-			return
-		}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		level.Error(c.logger).Log(
+			"msg", fmt.Sprintf("failed to convert the %s selector of %q to selector", kind, p.Name),
+			"err", err,
+		)
+		return false
+	}
+
+	return s.Matches(labels.Set(o.GetLabels()))
+}
+
+// enqueueForNamespace enqueues all Prometheus and PrometheusAgent object keys
+// that belong to the given namespace or select objects in the given namespace.
+func (c *Operator) enqueueForNamespace(store cache.Store, nsName string) {
+	nsObject, exists, err := store.GetByKey(nsName)
+	if err != nil {
+		level.Error(c.logger).Log(
+			"msg", "get namespace to enqueue Prometheus instances failed",
+			"err", err,
+		)
+		return
+	}
+	if !exists {
+		level.Error(c.logger).Log(
+			"msg", fmt.Sprintf("get namespace to enqueue Prometheus instances failed: namespace %q does not exist", nsName),
+		)
+		return
+	}
+	ns := nsObject.(*v1.Namespace)
 
-		if ruleNSSelector.Matches(labels.Set(ns.Labels)) {
+	err = c.promInfs.ListAll(labels.Everything(), func(obj interface{}) {
+		p := obj.(*monitoringv1.Prometheus)
+		if c.watchesNamespace(p, ns) {
 			c.enqueue(p)
-			return
 		}
 	})
 	if err != nil {
@@ -1017,36 +1331,135 @@ func (c *Operator) enqueueForNamespace(store cache.Store, nsName string) {
 		)
 	}
 
-}
+	if c.agentInfs == nil {
+		return
+	}
 
-// worker runs a worker thread that just dequeues items, processes them, and
-// marks them done. It enforces that the syncHandler is never invoked
-// concurrently with the same key.
-func (c *Operator) worker(ctx context.Context) {
-	for c.processNextWorkItem(ctx) {
+	err = c.agentInfs.ListAll(labels.Everything(), func(obj interface{}) {
+		a := obj.(*monitoringv1alpha1.PrometheusAgent)
+		if c.watchesNamespace(prometheusFromAgent(a), ns) {
+			c.enqueueAgent(a)
+		}
+	})
+	if err != nil {
+		level.Error(c.logger).Log(
+			"msg", "listing all PrometheusAgent instances from cache failed",
+			"err", err,
+		)
 	}
 }
 
-func (c *Operator) processNextWorkItem(ctx context.Context) bool {
-	key, quit := c.queue.Get()
-	if quit {
+// watchesNamespace returns true if the Prometheus object belongs to the given
+// namespace or selects objects in the given namespace.
+func (c *Operator) watchesNamespace(p *monitoringv1.Prometheus, ns *v1.Namespace) bool {
+	// Check for Prometheus instances in the namespace.
+	if p.Namespace == ns.Name {
+		return true
+	}
+
+	// Check for Prometheus instances selecting ServiceMonitors in
+	// the namespace.
+	smNSSelector, err := metav1.LabelSelectorAsSelector(p.Spec.ServiceMonitorNamespaceSelector)
+	if err != nil {
+		level.Error(c.logger).Log(
+			"msg", fmt.Sprintf("failed to convert ServiceMonitorNamespaceSelector of %q to selector", p.Name),
+			"err", err,
+		)
 		return false
 	}
-	defer c.queue.Done(key)
 
-	c.metrics.ReconcileCounter().Inc()
-	err := c.sync(ctx, key.(string))
-	c.metrics.SetSyncStatus(key.(string), err == nil)
-	if err == nil {
-		c.queue.Forget(key)
+	if smNSSelector.Matches(labels.Set(ns.Labels)) {
 		return true
 	}
 
-	c.metrics.ReconcileErrorsCounter().Inc()
-	utilruntime.HandleError(errors.Wrap(err, fmt.Sprintf("Sync %q failed", key)))
-	c.queue.AddRateLimited(key)
-
-	return true
+	// Check for Prometheus instances selecting PodMonitors in the NS.
+	pmNSSelector, err := metav1.LabelSelectorAsSelector(p.Spec.PodMonitorNamespaceSelector)
+	if err != nil {
+		level.Error(c.logger).Log(
+			"msg", fmt.Sprintf("failed to convert PodMonitorNamespaceSelector of %q to selector", p.Name),
+			"err", err,
+		)
+		return false
+	}
+
+	if pmNSSelector.Matches(labels.Set(ns.Labels)) {
+		return true
+	}
+
+	// Check for Prometheus instances selecting Probes in the NS.
+	bmNSSelector, err := metav1.LabelSelectorAsSelector(p.Spec.ProbeNamespaceSelector)
+	if err != nil {
+		level.Error(c.logger).Log(
+			"msg", fmt.Sprintf("failed to convert ProbeNamespaceSelector of %q to selector", p.Name),
+			"err", err,
+		)
+		return false
+	}
+
+	if bmNSSelector.Matches(labels.Set(ns.Labels)) {
+		return true
+	}
+
+	// Check for Prometheus instances selecting ScrapeConfigs in the NS.
+	scNSSelector, err := metav1.LabelSelectorAsSelector(p.Spec.ScrapeConfigNamespaceSelector)
+	if err != nil {
+		level.Error(c.logger).Log(
+			"msg", fmt.Sprintf("failed to convert ScrapeConfigNamespaceSelector of %q to selector", p.Name),
+			"err", err,
+		)
+		return false
+	}
+
+	if scNSSelector.Matches(labels.Set(ns.Labels)) {
+		return true
+	}
+
+	// Check for Prometheus instances selecting PrometheusRules in
+	// the NS.
+	ruleNSSelector, err := metav1.LabelSelectorAsSelector(p.Spec.RuleNamespaceSelector)
+	if err != nil {
+		level.Error(c.logger).Log(
+			"msg", fmt.Sprintf("failed to convert RuleNamespaceSelector of %q to selector", p.Name),
+			"err", err,
+		)
+		return false
+	}
+
+	if ruleNSSelector.Matches(labels.Set(ns.Labels)) {
+		return true
+	}
+
+	return false
+}
+
+// worker runs a worker thread that just dequeues items, processes them, and
+// marks them done. It enforces that the syncHandler is never invoked
+// concurrently with the same key.
+func (c *Operator) worker(ctx context.Context, queue workqueue.RateLimitingInterface, metrics *operator.Metrics, sync func(context.Context, string) error) {
+	for c.processNextWorkItem(ctx, queue, metrics, sync) {
+	}
+}
+
+func (c *Operator) processNextWorkItem(ctx context.Context, queue workqueue.RateLimitingInterface, metrics *operator.Metrics, sync func(context.Context, string) error) bool {
+	key, quit := queue.Get()
+	if quit {
+		return false
+	}
+	defer queue.Done(key)
+
+	metrics.ReconcileCounter().Inc()
+	err := sync(ctx, key.(string))
+	metrics.SetSyncStatus(key.(string), err == nil)
+	if err == nil {
+		queue.Forget(key)
+		return true
+	}
+
+	metrics.ReconcileErrorsCounter().Inc()
+	utilruntime.HandleError(errors.Wrap(err, fmt.Sprintf("Sync %q failed", key)))
+	queue.AddRateLimited(key)
+
+	return true
 }
 
 func (c *Operator) prometheusForStatefulSet(sset interface{}) *monitoringv1.Prometheus {
@@ -1112,6 +1525,13 @@ func (c *Operator) handleStatefulSetDelete(obj interface{}) {
 
 		c.enqueue(ps)
 	}
+
+	if a := c.agentForStatefulSet(obj); a != nil {
+		level.Debug(c.logger).Log("msg", "StatefulSet delete")
+		c.agentMetrics.TriggerByCounter("StatefulSet", "delete").Inc()
+
+		c.enqueueAgent(a)
+	}
 }
 
 func (c *Operator) handleStatefulSetAdd(obj interface{}) {
@@ -1121,6 +1541,13 @@ func (c *Operator) handleStatefulSetAdd(obj interface{}) {
 
 		c.enqueue(ps)
 	}
+
+	if a := c.agentForStatefulSet(obj); a != nil {
+		level.Debug(c.logger).Log("msg", "StatefulSet added")
+		c.agentMetrics.TriggerByCounter("StatefulSet", "add").Inc()
+
+		c.enqueueAgent(a)
+	}
 }
 
 func (c *Operator) handleStatefulSetUpdate(oldo, curo interface{}) {
@@ -1142,6 +1569,13 @@ func (c *Operator) handleStatefulSetUpdate(oldo, curo interface{}) {
 
 		c.enqueue(ps)
 	}
+
+	if a := c.agentForStatefulSet(cur); a != nil {
+		level.Debug(c.logger).Log("msg", "StatefulSet updated")
+		c.agentMetrics.TriggerByCounter("StatefulSet", "update").Inc()
+
+		c.enqueueAgent(a)
+	}
 }
 
 func (c *Operator) handleMonitorNamespaceUpdate(oldo, curo interface{}) {
@@ -1163,44 +1597,72 @@ func (c *Operator) handleMonitorNamespaceUpdate(oldo, curo interface{}) {
 	// Probes and PrometheusRules in the namespace.
 	err := c.promInfs.ListAll(labels.Everything(), func(obj interface{}) {
 		p := obj.(*monitoringv1.Prometheus)
+		if c.namespaceSelectionChanged(p, old, cur) {
+			c.enqueue(p)
+		}
+	})
+	if err != nil {
+		level.Error(c.logger).Log(
+			"msg", "listing all Prometheus instances from cache failed",
+			"err", err,
+		)
+	}
 
-		for name, selector := range map[string]*metav1.LabelSelector{
-			"PodMonitors":     p.Spec.PodMonitorNamespaceSelector,
-			"Probes":          p.Spec.ProbeNamespaceSelector,
-			"PrometheusRules": p.Spec.RuleNamespaceSelector,
-			"ServiceMonitors": p.Spec.ServiceMonitorNamespaceSelector,
-		} {
-
-			sync, err := k8sutil.LabelSelectionHasChanged(old.Labels, cur.Labels, selector)
-			if err != nil {
-				level.Error(c.logger).Log(
-					"err", err,
-					"name", p.Name,
-					"namespace", p.Namespace,
-					"subresource", name,
-				)
-				return
-			}
+	if c.agentInfs == nil {
+		return
+	}
 
-			if sync {
-				c.enqueue(p)
-				return
-			}
+	err = c.agentInfs.ListAll(labels.Everything(), func(obj interface{}) {
+		a := obj.(*monitoringv1alpha1.PrometheusAgent)
+		if c.namespaceSelectionChanged(prometheusFromAgent(a), old, cur) {
+			c.enqueueAgent(a)
 		}
 	})
 	if err != nil {
 		level.Error(c.logger).Log(
-			"msg", "listing all Prometheus instances from cache failed",
+			"msg", "listing all PrometheusAgent instances from cache failed",
 			"err", err,
 		)
 	}
 }
 
+// namespaceSelectionChanged returns true if the label change of the namespace
+// modifies the selection of ServiceMonitors, PodMonitors, Probes,
+// ScrapeConfigs or PrometheusRules by the Prometheus object.
+func (c *Operator) namespaceSelectionChanged(p *monitoringv1.Prometheus, old, cur *v1.Namespace) bool {
+	for name, selector := range map[string]*metav1.LabelSelector{
+		"PodMonitors":     p.Spec.PodMonitorNamespaceSelector,
+		"Probes":          p.Spec.ProbeNamespaceSelector,
+		"PrometheusRules": p.Spec.RuleNamespaceSelector,
+		"ScrapeConfigs":   p.Spec.ScrapeConfigNamespaceSelector,
+		"ServiceMonitors": p.Spec.ServiceMonitorNamespaceSelector,
+	} {
+
+		sync, err := k8sutil.LabelSelectionHasChanged(old.Labels, cur.Labels, selector)
+		if err != nil {
+			level.Error(c.logger).Log(
+				"err", err,
+				"name", p.Name,
+				"namespace", p.Namespace,
+				"subresource", name,
+			)
+			return false
+		}
+
+		if sync {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (c *Operator) sync(ctx context.Context, key string) error {
 	pobj, err := c.promInfs.Get(key)
 
 	if apierrors.IsNotFound(err) {
 		c.metrics.ForgetObject(key)
+		c.forgetDependent(dependent{kind: monitoringv1.PrometheusesKind, key: key})
 		// Dependent resources are cleaned up by K8s via OwnerReferences
 		return nil
 	}
@@ -1217,16 +1679,70 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 		return nil
 	}
 
+	if !c.config.Namespaces.Sharding.OwnsNamespace(c.nsPromInf.GetStore(), p.Namespace) {
+		level.Debug(c.logger).Log("msg", "skipping prometheus assigned to another shard", "key", key)
+		return nil
+	}
+
 	logger := log.With(c.logger, "key", key)
 	level.Info(logger).Log("msg", "sync prometheus")
+
+	err = c.syncPrometheus(ctx, logger, key, p)
+	if uErr := c.updateStatus(ctx, logger, key, p, err); uErr != nil {
+		level.Warn(logger).Log("msg", "failed to update the status", "err", uErr)
+	}
+
+	return err
+}
+
+// syncPrometheus reconciles the rule ConfigMaps, the secrets, the governing
+// service and the StatefulSets of a Prometheus object.
+func (c *Operator) syncPrometheus(ctx context.Context, logger log.Logger, key string, p *monitoringv1.Prometheus) error {
+	if err := ValidatePrometheus(p); err != nil {
+		return errors.Wrap(err, "invalid Prometheus spec")
+	}
+
+	if disallowed := disallowedCapabilities(p, c.config); len(disallowed) > 0 {
+		level.Warn(logger).Log("msg", "ignoring capabilities disabled by the operator", "capabilities", strings.Join(disallowed, ","))
+	}
+
+	if retentionSizeExceedsStorage(p) {
+		level.Warn(logger).Log("msg", "retentionSize is greater than the size of the storage volume, Prometheus may run out of disk space", "retentionSize", p.Spec.RetentionSize)
+	}
+
 	ruleConfigMapNames, err := c.createOrUpdateRuleConfigMaps(ctx, p)
 	if err != nil {
+		operator.RecordWarningEvent(c.eventRecorder, p, operator.ConfigGenerationFailedReason, "Failed to generate the rule ConfigMaps: %v", err)
 		return err
 	}
 
+	return c.syncResources(ctx, logger, key, p, ruleConfigMapNames)
+}
+
+// disallowedCapabilities returns the capabilities requested by the Prometheus
+// object which are disabled by the operator's configuration.
+func disallowedCapabilities(p *monitoringv1.Prometheus, config operator.Config) []string {
+	var disallowed []string
+
+	if p.Spec.EnableAdminAPI && config.DisallowAdminAPI {
+		disallowed = append(disallowed, "enableAdminAPI")
+	}
+
+	if p.Spec.EnableRemoteWriteReceiver && config.DisallowRemoteWriteReceiver {
+		disallowed = append(disallowed, "enableRemoteWriteReceiver")
+	}
+
+	return disallowed
+}
+
+// syncResources reconciles the secrets, the governing service and the
+// StatefulSets of a Prometheus object. It is shared by the Prometheus and
+// PrometheusAgent resources.
+func (c *Operator) syncResources(ctx context.Context, logger log.Logger, key string, p *monitoringv1.Prometheus, ruleConfigMapNames []string) error {
 	assetStore := assets.NewStore(c.kclient.CoreV1(), c.kclient.CoreV1())
 
 	if err := c.createOrUpdateConfigurationSecret(ctx, p, ruleConfigMapNames, assetStore); err != nil {
+		operator.RecordWarningEvent(c.eventRecorder, p, operator.ConfigGenerationFailedReason, "Failed to generate the configuration: %v", err)
 		return errors.Wrap(err, "creating config failed")
 	}
 
@@ -1240,10 +1756,31 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 
 	// Create governing service if it doesn't exist.
 	svcClient := c.kclient.CoreV1().Services(p.Namespace)
-	if err := k8sutil.CreateOrUpdateService(ctx, svcClient, makeStatefulSetService(p, c.config)); err != nil {
+	if err := k8sutil.ApplyService(ctx, svcClient, makeStatefulSetService(p, c.config), operator.ApplyConflictEventHandler(c.eventRecorder, p)); err != nil {
 		return errors.Wrap(err, "synchronizing governing service failed")
 	}
 
+	// The hashes of the Secrets whose changes require a restart of the pods
+	// are added to the pod template annotations.
+	secretsHashes := map[string]string{}
+
+	sidecarSecretsHash, err := c.sidecarSecretsHash(ctx, p)
+	if err != nil {
+		return errors.Wrap(err, "hashing sidecar secrets failed")
+	}
+	if sidecarSecretsHash != "" {
+		secretsHashes[sidecarSecretsHashAnnotation] = sidecarSecretsHash
+	}
+
+	// The Thanos sidecar can't reload the object storage configuration.
+	objstoreConfigHash, err := c.objectStorageConfigHash(ctx, p)
+	if err != nil {
+		return errors.Wrap(err, "hashing object storage configuration failed")
+	}
+	if objstoreConfigHash != "" {
+		secretsHashes[objstoreConfigHashAnnotation] = objstoreConfigHash
+	}
+
 	ssetClient := c.kclient.AppsV1().StatefulSets(p.Namespace)
 
 	// Ensure we have a StatefulSet running Prometheus deployed and that StatefulSet names are created correctly.
@@ -1252,7 +1789,12 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 		logger := log.With(logger, "statefulset", ssetName, "shard", fmt.Sprintf("%d", shard))
 		level.Debug(logger).Log("msg", "reconciling statefulset")
 
-		obj, err := c.ssetInfs.Get(prometheusKeyToStatefulSetKey(key, shard))
+		ssetKey := prometheusKeyToStatefulSetKey(key, shard)
+		if isAgent(p) {
+			ssetKey = agentKeyToStatefulSetKey(key, shard)
+		}
+
+		obj, err := c.ssetInfs.Get(ssetKey)
 		exists := !apierrors.IsNotFound(err)
 		if err != nil && !apierrors.IsNotFound(err) {
 			return errors.Wrap(err, "retrieving statefulset failed")
@@ -1263,7 +1805,7 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 			ss := obj.(*appsv1.StatefulSet)
 			spec = ss.Spec
 		}
-		newSSetInputHash, err := createSSetInputHash(*p, c.config, ruleConfigMapNames, spec)
+		newSSetInputHash, err := createSSetInputHash(*p, c.config, ruleConfigMapNames, secretsHashes, spec)
 		if err != nil {
 			return err
 		}
@@ -1272,12 +1814,17 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 		if err != nil {
 			return errors.Wrap(err, "making statefulset failed")
 		}
+		for k, v := range secretsHashes {
+			// Changing the pod template triggers a rollout of the pods.
+			sset.Spec.Template.Annotations[k] = v
+		}
 		operator.SanitizeSTS(sset)
 
 		if !exists {
 			level.Debug(logger).Log("msg", "no current statefulset found")
 			level.Debug(logger).Log("msg", "creating statefulset")
-			if _, err := ssetClient.Create(ctx, sset, metav1.CreateOptions{}); err != nil {
+			if err := k8sutil.ApplyStatefulSet(ctx, ssetClient, sset, operator.ApplyConflictEventHandler(c.eventRecorder, p)); err != nil {
+				operator.RecordWarningEvent(c.eventRecorder, p, operator.StatefulSetUpdateFailedReason, "Failed to create the StatefulSet %s: %v", sset.Name, err)
 				return errors.Wrap(err, "creating statefulset failed")
 			}
 			return nil
@@ -1291,11 +1838,11 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 
 		level.Debug(logger).Log("msg", "updating current statefulset")
 
-		err = k8sutil.UpdateStatefulSet(ctx, ssetClient, sset)
+		err = k8sutil.ApplyStatefulSet(ctx, ssetClient, sset, operator.ApplyConflictEventHandler(c.eventRecorder, p))
 		sErr, ok := err.(*apierrors.StatusError)
 
 		if ok && sErr.ErrStatus.Code == 422 && sErr.ErrStatus.Reason == metav1.StatusReasonInvalid {
-			c.metrics.StsDeleteCreateCounter().Inc()
+			c.metricsFor(p).StsDeleteCreateCounter().Inc()
 
 			// Gather only reason for failed update
 			failMsg := make([]string, len(sErr.ErrStatus.Details.Causes))
@@ -1306,12 +1853,14 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 			level.Info(logger).Log("msg", "recreating StatefulSet because the update operation wasn't possible", "reason", strings.Join(failMsg, ", "))
 			propagationPolicy := metav1.DeletePropagationForeground
 			if err := ssetClient.Delete(ctx, sset.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil {
+				operator.RecordWarningEvent(c.eventRecorder, p, operator.StatefulSetUpdateFailedReason, "Failed to delete the StatefulSet %s to recreate it: %v", sset.Name, err)
 				return errors.Wrap(err, "failed to delete StatefulSet to avoid forbidden action")
 			}
 			return nil
 		}
 
 		if err != nil {
+			operator.RecordWarningEvent(c.eventRecorder, p, operator.StatefulSetUpdateFailedReason, "Failed to update the StatefulSet %s: %v", sset.Name, err)
 			return errors.Wrap(err, "updating StatefulSet failed")
 		}
 	}
@@ -1324,6 +1873,12 @@ func (c *Operator) sync(ctx context.Context, key string) error {
 	err = c.ssetInfs.ListAllByNamespace(p.Namespace, labels.SelectorFromSet(labels.Set{prometheusNameLabelName: p.Name}), func(obj interface{}) {
 		s := obj.(*appsv1.StatefulSet)
 
+		// Prometheus and PrometheusAgent objects with the same name share the
+		// same label value so only consider the StatefulSets owned by p.
+		if !metav1.IsControlledBy(s, p) {
+			return
+		}
+
 		if _, ok := ssets[s.Name]; ok {
 			// Do not delete statefulsets that we still expect to exist. This
 			// is to cleanup StatefulSets when shards are reduced.
@@ -1366,18 +1921,19 @@ func checkPrometheusSpecDeprecation(key string, p *monitoringv1.Prometheus, logg
 		}
 	}
 
-	if p.Spec.ServiceMonitorSelector == nil && p.Spec.PodMonitorSelector == nil && p.Spec.ProbeSelector == nil {
-		level.Warn(logger).Log("msg", "neither serviceMonitorSelector nor podMonitorSelector, nor probeSelector specified. Custom configuration is deprecated, use additionalScrapeConfigs instead")
+	if p.Spec.ServiceMonitorSelector == nil && p.Spec.PodMonitorSelector == nil && p.Spec.ProbeSelector == nil && p.Spec.ScrapeConfigSelector == nil {
+		level.Warn(logger).Log("msg", "neither serviceMonitorSelector nor podMonitorSelector, nor probeSelector, nor scrapeConfigSelector specified. Custom configuration is deprecated, use additionalScrapeConfigs instead")
 	}
 }
 
-func createSSetInputHash(p monitoringv1.Prometheus, c operator.Config, ruleConfigMapNames []string, ss interface{}) (string, error) {
+func createSSetInputHash(p monitoringv1.Prometheus, c operator.Config, ruleConfigMapNames []string, secretsHashes map[string]string, ss interface{}) (string, error) {
 	hash, err := hashstructure.Hash(struct {
 		P monitoringv1.Prometheus
 		C operator.Config
 		S interface{}
 		R []string `hash:"set"`
-	}{p, c, ss, ruleConfigMapNames},
+		H map[string]string
+	}{p, c, ss, ruleConfigMapNames, secretsHashes},
 		nil,
 	)
 	if err != nil {
@@ -1391,6 +1947,68 @@ func createSSetInputHash(p monitoringv1.Prometheus, c operator.Config, ruleConfi
 	return fmt.Sprintf("%d", hash), nil
 }
 
+// sidecarSecretsHash returns a hash of the content of the Secrets referenced by
+// the sidecars of the Prometheus object. It returns an empty string when no
+// Secret is referenced.
+func (c *Operator) sidecarSecretsHash(ctx context.Context, p *monitoringv1.Prometheus) (string, error) {
+	secrets := map[string]map[string][]byte{}
+	for _, sidecar := range p.Spec.Sidecars {
+		for _, name := range sidecar.RestartOnSecretChange {
+			if _, found := secrets[name]; found {
+				continue
+			}
+
+			secret, err := c.kclient.CoreV1().Secrets(p.Namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to get secret %q of sidecar %q", name, sidecar.Name)
+			}
+			secrets[name] = secret.Data
+		}
+	}
+
+	if len(secrets) == 0 {
+		return "", nil
+	}
+
+	hash, err := hashstructure.Hash(secrets, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to calculate the hash of the sidecar secrets")
+	}
+
+	return fmt.Sprintf("%d", hash), nil
+}
+
+// objectStorageConfigHash returns a hash of the object storage configuration
+// of the Thanos sidecar when it is read from a Secret. It returns an empty
+// string otherwise or when the Secret key doesn't exist (the pods can't start
+// in this case).
+func (c *Operator) objectStorageConfigHash(ctx context.Context, p *monitoringv1.Prometheus) (string, error) {
+	sel := objectStorageConfigSecret(p)
+	if sel == nil {
+		return "", nil
+	}
+
+	secret, err := c.kclient.CoreV1().Secrets(p.Namespace).Get(ctx, sel.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get secret %q", sel.Name)
+	}
+
+	data, found := secret.Data[sel.Key]
+	if !found {
+		return "", nil
+	}
+
+	hash, err := hashstructure.Hash(data, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to calculate the hash of the object storage configuration")
+	}
+
+	return fmt.Sprintf("%d", hash), nil
+}
+
 func ListOptions(name string) metav1.ListOptions {
 	return metav1.ListOptions{
 		LabelSelector: fields.SelectorFromSet(fields.Set(map[string]string{
@@ -1491,8 +2109,9 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *mon
 	// manage configuration themselves. Do create an empty Secret if it doesn't
 	// exist.
 	if p.Spec.ServiceMonitorSelector == nil && p.Spec.PodMonitorSelector == nil &&
-		p.Spec.ProbeSelector == nil {
-		level.Debug(c.logger).Log("msg", "neither ServiceMonitor nor PodMonitor, nor Probe selector specified, leaving configuration unmanaged", "prometheus", p.Name, "namespace", p.Namespace)
+		p.Spec.ProbeSelector == nil && p.Spec.ScrapeConfigSelector == nil {
+		level.Debug(c.logger).Log("msg", "neither ServiceMonitor nor PodMonitor, nor Probe, nor ScrapeConfig selector specified, leaving configuration unmanaged", "prometheus", p.Name, "namespace", p.Namespace)
+		c.forgetDependent(dependentFor(p))
 
 		s, err := makeEmptyConfigurationSecret(p, c.config)
 		if err != nil {
@@ -1527,13 +2146,42 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *mon
 		return errors.Wrap(err, "selecting Probes failed")
 	}
 
+	sCons, err := c.selectScrapeConfigs(ctx, p, store)
+	if err != nil {
+		return errors.Wrap(err, "selecting ScrapeConfigs failed")
+	}
+
+	monitors := make([]string, 0, len(smons)+len(pmons)+len(bmons)+len(sCons))
+	for k := range smons {
+		monitors = append(monitors, monitorKey(monitoringv1.ServiceMonitorsKind, k))
+	}
+	for k := range pmons {
+		monitors = append(monitors, monitorKey(monitoringv1.PodMonitorsKind, k))
+	}
+	for k := range bmons {
+		monitors = append(monitors, monitorKey(monitoringv1.ProbesKind, k))
+	}
+	for k := range sCons {
+		monitors = append(monitors, monitorKey(monitoringv1alpha1.ScrapeConfigsKind, k))
+	}
+	c.monitorDeps.set(dependentFor(p), monitors)
+
 	sClient := c.kclient.CoreV1().Secrets(p.Namespace)
 	SecretsInPromNS, err := sClient.List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return err
 	}
 
+	for _, sc := range p.Spec.ScrapeClasses {
+		if err := store.AddAuthorizationCredentials(ctx, p.GetNamespace(), sc.Authorization, scrapeClassAuthKey(sc.Name)); err != nil {
+			return errors.Wrapf(err, "scrape class %q", sc.Name)
+		}
+	}
+
 	for i, remote := range p.Spec.RemoteRead {
+		if err := validateRemoteReadSpec(remote); err != nil {
+			return errors.Wrapf(err, "remote read %d", i)
+		}
 		if err := store.AddBasicAuth(ctx, p.GetNamespace(), remote.BasicAuth, fmt.Sprintf("remoteRead/%d", i)); err != nil {
 			return errors.Wrapf(err, "remote read %d", i)
 		}
@@ -1548,26 +2196,12 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *mon
 		}
 	}
 
-	for i, remote := range p.Spec.RemoteWrite {
-		if err := validateRemoteWriteSpec(remote); err != nil {
-			return errors.Wrapf(err, "remote write %d", i)
-		}
-		key := fmt.Sprintf("remoteWrite/%d", i)
-		if err := store.AddBasicAuth(ctx, p.GetNamespace(), remote.BasicAuth, key); err != nil {
-			return errors.Wrapf(err, "remote write %d", i)
-		}
-		if err := store.AddOAuth2(ctx, p.GetNamespace(), remote.OAuth2, key); err != nil {
-			return errors.Wrapf(err, "remote write %d", i)
-		}
-		if err := store.AddTLSConfig(ctx, p.GetNamespace(), remote.TLSConfig); err != nil {
-			return errors.Wrapf(err, "remote write %d", i)
-		}
-		if err := store.AddAuthorizationCredentials(ctx, p.GetNamespace(), remote.Authorization, fmt.Sprintf("remoteWrite/auth/%d", i)); err != nil {
-			return errors.Wrapf(err, "remote write %d", i)
-		}
-		if err := store.AddSigV4(ctx, p.GetNamespace(), remote.Sigv4, key); err != nil {
-			return errors.Wrapf(err, "remote write %d", i)
-		}
+	if err := AddRemoteWriteAssets(ctx, store, p.GetNamespace(), remoteWriteSpecs(p)); err != nil {
+		return err
+	}
+
+	if err := c.addThanosGRPCServerTLSAssets(ctx, p, store); err != nil {
+		return err
 	}
 
 	if p.Spec.APIServerConfig != nil {
@@ -1605,6 +2239,7 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *mon
 		smons,
 		pmons,
 		bmons,
+		sCons,
 		store,
 		additionalScrapeConfigs,
 		additionalAlertRelabelConfigs,
@@ -1628,7 +2263,7 @@ func (c *Operator) createOrUpdateConfigurationSecret(ctx context.Context, p *mon
 	s.Data[configFilename] = buf.Bytes()
 
 	level.Debug(c.logger).Log("msg", "updating Prometheus configuration secret")
-	return k8sutil.CreateOrUpdateSecret(ctx, sClient, s)
+	return k8sutil.ApplySecret(ctx, sClient, s, operator.ApplyConflictEventHandler(c.eventRecorder, p))
 }
 
 func (c *Operator) createOrUpdateTLSAssetSecret(ctx context.Context, p *monitoringv1.Prometheus, store *assets.Store) error {
@@ -1637,7 +2272,7 @@ func (c *Operator) createOrUpdateTLSAssetSecret(ctx context.Context, p *monitori
 
 	tlsAssetsSecret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   tlsAssetsSecretName(p.Name),
+			Name:   tlsAssetsSecretName(p),
 			Labels: c.config.Labels.Merge(managedByOperatorLabels),
 			OwnerReferences: []metav1.OwnerReference{
 				{
@@ -1657,7 +2292,7 @@ func (c *Operator) createOrUpdateTLSAssetSecret(ctx context.Context, p *monitori
 		tlsAssetsSecret.Data[key.String()] = []byte(asset)
 	}
 
-	err := k8sutil.CreateOrUpdateSecret(ctx, sClient, tlsAssetsSecret)
+	err := k8sutil.ApplySecret(ctx, sClient, tlsAssetsSecret, operator.ApplyConflictEventHandler(c.eventRecorder, p))
 	if err != nil {
 		return errors.Wrap(err, "failed to create TLS assets secret for Prometheus")
 	}
@@ -1669,15 +2304,33 @@ func (c *Operator) createOrUpdateWebConfigSecret(ctx context.Context, p *monitor
 	boolTrue := true
 	client := c.kclient.CoreV1().Secrets(p.Namespace)
 
-	var tlsConfig *monitoringv1.WebTLSConfig
+	var (
+		tlsConfig  *monitoringv1.WebTLSConfig
+		httpConfig *monitoringv1.WebHTTPConfig
+	)
 	if p.Spec.Web != nil {
 		tlsConfig = p.Spec.Web.TLSConfig
+		httpConfig = p.Spec.Web.HTTPConfig
+	}
+
+	if httpConfig != nil {
+		promVersion := operator.StringValOrDefault(p.Spec.Version, operator.DefaultPrometheusVersion)
+		version, err := semver.ParseTolerant(promVersion)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse prometheus version")
+		}
+
+		if version.LT(semver.MustParse("2.36.0")) {
+			level.Warn(c.logger).Log("msg", "found web httpConfig setting but prometheus version is < 2.36.0, ignoring", "version", version, "minimum_version", "2.36.0")
+			httpConfig = nil
+		}
 	}
 
 	webConfig, err := webconfig.New(
 		webConfigDir,
-		WebConfigSecretName(p.Name),
+		WebConfigSecretName(p),
 		tlsConfig,
+		httpConfig,
 	)
 	if err != nil {
 		return err
@@ -1698,7 +2351,7 @@ func (c *Operator) createOrUpdateWebConfigSecret(ctx context.Context, p *monitor
 		return err
 	}
 
-	err = k8sutil.CreateOrUpdateSecret(ctx, client, secret)
+	err = k8sutil.ApplySecret(ctx, client, secret, operator.ApplyConflictEventHandler(c.eventRecorder, p))
 	if err != nil {
 		return errors.Wrap(err, "failed to create web config for Prometheus")
 	}
@@ -1779,12 +2432,24 @@ func (c *Operator) selectServiceMonitors(ctx context.Context, p *monitoringv1.Pr
 				break
 			}
 
+			if err = validateProxyConfig(operator.StringPtrValOrDefault(endpoint.ProxyURL, ""), endpoint.ProxyConfig); err != nil {
+				break
+			}
+
+			if err = store.AddProxyConfig(ctx, sm.GetNamespace(), endpoint.ProxyConfig, smKey); err != nil {
+				break
+			}
+
 			smAuthKey := fmt.Sprintf("serviceMonitor/auth/%s/%s/%d", sm.GetNamespace(), sm.GetName(), i)
 			if err = store.AddSafeAuthorizationCredentials(ctx, sm.GetNamespace(), endpoint.Authorization, smAuthKey); err != nil {
 				break
 			}
 		}
 
+		if err == nil {
+			err = validateScrapeClassReference(p, sm.Spec.ScrapeClassName)
+		}
+
 		if err != nil {
 			rejected++
 			level.Warn(c.logger).Log(
@@ -1794,6 +2459,7 @@ func (c *Operator) selectServiceMonitors(ctx context.Context, p *monitoringv1.Pr
 				"namespace", p.Namespace,
 				"prometheus", p.Name,
 			)
+			operator.RecordWarningEvent(c.eventRecorder, p, operator.ResourceRejectedReason, "%s %s was rejected: %v", monitoringv1.ServiceMonitorsKind, namespaceAndName, err)
 			continue
 		}
 
@@ -1807,8 +2473,16 @@ func (c *Operator) selectServiceMonitors(ctx context.Context, p *monitoringv1.Pr
 	level.Debug(c.logger).Log("msg", "selected ServiceMonitors", "servicemonitors", strings.Join(smKeys, ","), "namespace", p.Namespace, "prometheus", p.Name)
 
 	if pKey, ok := c.keyFunc(p); ok {
-		c.metrics.SetSelectedResources(pKey, monitoringv1.ServiceMonitorsKind, len(res))
-		c.metrics.SetRejectedResources(pKey, monitoringv1.ServiceMonitorsKind, rejected)
+		c.metricsFor(p).SetSelectedResources(pKey, monitoringv1.ServiceMonitorsKind, len(res))
+		c.metricsFor(p).SetRejectedResources(pKey, monitoringv1.ServiceMonitorsKind, rejected)
+
+		clamped := 0
+		for _, r := range res {
+			if limitsClamped(p, r.Spec.SampleLimit, r.Spec.TargetLimit, r.Spec.LabelLimit, r.Spec.LabelNameLengthLimit, r.Spec.LabelValueLengthLimit) {
+				clamped++
+			}
+		}
+		c.metricsFor(p).SetClampedResources(pKey, monitoringv1.ServiceMonitorsKind, clamped)
 	}
 
 	return res, nil
@@ -1879,12 +2553,24 @@ func (c *Operator) selectPodMonitors(ctx context.Context, p *monitoringv1.Promet
 				break
 			}
 
+			if err = validateProxyConfig(operator.StringPtrValOrDefault(endpoint.ProxyURL, ""), endpoint.ProxyConfig); err != nil {
+				break
+			}
+
+			if err = store.AddProxyConfig(ctx, pm.GetNamespace(), endpoint.ProxyConfig, pmKey); err != nil {
+				break
+			}
+
 			pmAuthKey := fmt.Sprintf("podMonitor/auth/%s/%s/%d", pm.GetNamespace(), pm.GetName(), i)
 			if err = store.AddSafeAuthorizationCredentials(ctx, pm.GetNamespace(), endpoint.Authorization, pmAuthKey); err != nil {
 				break
 			}
 		}
 
+		if err == nil {
+			err = validateScrapeClassReference(p, pm.Spec.ScrapeClassName)
+		}
+
 		if err != nil {
 			rejected++
 			level.Warn(c.logger).Log(
@@ -1894,6 +2580,7 @@ func (c *Operator) selectPodMonitors(ctx context.Context, p *monitoringv1.Promet
 				"namespace", p.Namespace,
 				"prometheus", p.Name,
 			)
+			operator.RecordWarningEvent(c.eventRecorder, p, operator.ResourceRejectedReason, "%s %s was rejected: %v", monitoringv1.PodMonitorsKind, namespaceAndName, err)
 			continue
 		}
 
@@ -1907,8 +2594,16 @@ func (c *Operator) selectPodMonitors(ctx context.Context, p *monitoringv1.Promet
 	level.Debug(c.logger).Log("msg", "selected PodMonitors", "podmonitors", strings.Join(pmKeys, ","), "namespace", p.Namespace, "prometheus", p.Name)
 
 	if pKey, ok := c.keyFunc(p); ok {
-		c.metrics.SetSelectedResources(pKey, monitoringv1.PodMonitorsKind, len(res))
-		c.metrics.SetRejectedResources(pKey, monitoringv1.PodMonitorsKind, rejected)
+		c.metricsFor(p).SetSelectedResources(pKey, monitoringv1.PodMonitorsKind, len(res))
+		c.metricsFor(p).SetRejectedResources(pKey, monitoringv1.PodMonitorsKind, rejected)
+
+		clamped := 0
+		for _, r := range res {
+			if limitsClamped(p, r.Spec.SampleLimit, r.Spec.TargetLimit, r.Spec.LabelLimit, r.Spec.LabelNameLengthLimit, r.Spec.LabelValueLengthLimit) {
+				clamped++
+			}
+		}
+		c.metricsFor(p).SetClampedResources(pKey, monitoringv1.PodMonitorsKind, clamped)
 	}
 
 	return res, nil
@@ -1965,11 +2660,18 @@ func (c *Operator) selectProbes(ctx context.Context, p *monitoringv1.Prometheus,
 				"namespace", p.Namespace,
 				"prometheus", p.Name,
 			)
+			operator.RecordWarningEvent(c.eventRecorder, p, operator.ResourceRejectedReason, "%s %s/%s was rejected: %v", monitoringv1.ProbesKind, probe.Namespace, probe.Name, err)
 		}
-		if probe.Spec.Targets.StaticConfig == nil && probe.Spec.Targets.Ingress == nil {
+		if err = ValidateProbe(probe); err != nil {
 			rejectFn(probe, err)
 			continue
 		}
+
+		if err = validateScrapeClassReference(p, probe.Spec.ScrapeClassName); err != nil {
+			rejectFn(probe, err)
+			continue
+		}
+
 		pnKey := fmt.Sprintf("probe/%s/%s", probe.GetNamespace(), probe.GetName())
 		if err = store.AddBearerToken(ctx, probe.GetNamespace(), probe.Spec.BearerTokenSecret, pnKey); err != nil {
 			rejectFn(probe, err)
@@ -1998,6 +2700,18 @@ func (c *Operator) selectProbes(ctx context.Context, p *monitoringv1.Prometheus,
 			continue
 		}
 
+		if err = store.AddProxyConfig(ctx, probe.GetNamespace(), probe.Spec.ProberSpec.ProxyConfig, pnKey); err != nil {
+			rejectFn(probe, err)
+			continue
+		}
+
+		if sc := probe.Spec.Targets.StaticConfig; sc != nil && sc.StaticFrom != nil {
+			if err = store.AddProbeTargets(ctx, probe.GetNamespace(), *sc.StaticFrom, pnKey); err != nil {
+				rejectFn(probe, err)
+				continue
+			}
+		}
+
 		res[probeName] = probe
 	}
 
@@ -2008,13 +2722,155 @@ func (c *Operator) selectProbes(ctx context.Context, p *monitoringv1.Prometheus,
 	level.Debug(c.logger).Log("msg", "selected Probes", "probes", strings.Join(probeKeys, ","), "namespace", p.Namespace, "prometheus", p.Name)
 
 	if pKey, ok := c.keyFunc(p); ok {
-		c.metrics.SetSelectedResources(pKey, monitoringv1.ProbesKind, len(res))
-		c.metrics.SetRejectedResources(pKey, monitoringv1.ProbesKind, rejected)
+		c.metricsFor(p).SetSelectedResources(pKey, monitoringv1.ProbesKind, len(res))
+		c.metricsFor(p).SetRejectedResources(pKey, monitoringv1.ProbesKind, rejected)
+
+		clamped := 0
+		for _, r := range res {
+			if limitsClamped(p, r.Spec.SampleLimit, r.Spec.TargetLimit, r.Spec.LabelLimit, r.Spec.LabelNameLengthLimit, r.Spec.LabelValueLengthLimit) {
+				clamped++
+			}
+		}
+		c.metricsFor(p).SetClampedResources(pKey, monitoringv1.ProbesKind, clamped)
+	}
+
+	return res, nil
+}
+
+func (c *Operator) selectScrapeConfigs(ctx context.Context, p *monitoringv1.Prometheus, store *assets.Store) (map[string]*monitoringv1alpha1.ScrapeConfig, error) {
+	namespaces := []string{}
+	// Selectors might overlap. Deduplicate them along the keyFunc.
+	scrapeConfigs := make(map[string]*monitoringv1alpha1.ScrapeConfig)
+
+	// The ScrapeConfig CRD isn't installed or the Prometheus object doesn't
+	// select any ScrapeConfig.
+	if c.sconInfs == nil || p.Spec.ScrapeConfigSelector == nil {
+		return scrapeConfigs, nil
+	}
+
+	sConSelector, err := metav1.LabelSelectorAsSelector(p.Spec.ScrapeConfigSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	// If 'ScrapeConfigNamespaceSelector' is nil only check own namespace.
+	if p.Spec.ScrapeConfigNamespaceSelector == nil {
+		namespaces = append(namespaces, p.Namespace)
+	} else {
+		sConNSSelector, err := metav1.LabelSelectorAsSelector(p.Spec.ScrapeConfigNamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		namespaces, err = c.listMatchingNamespaces(sConNSSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	level.Debug(c.logger).Log("msg", "filtering namespaces to select ScrapeConfigs from", "namespaces", strings.Join(namespaces, ","), "namespace", p.Namespace, "prometheus", p.Name)
+
+	for _, ns := range namespaces {
+		err := c.sconInfs.ListAllByNamespace(ns, sConSelector, func(obj interface{}) {
+			if k, ok := c.keyFunc(obj); ok {
+				scrapeConfigs[k] = obj.(*monitoringv1alpha1.ScrapeConfig)
+			}
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list scrape configs in namespace %s", ns)
+		}
+	}
+
+	var rejected int
+	res := make(map[string]*monitoringv1alpha1.ScrapeConfig, len(scrapeConfigs))
+
+	for scName, sc := range scrapeConfigs {
+		rejectFn := func(sc *monitoringv1alpha1.ScrapeConfig, err error) {
+			rejected++
+			level.Warn(c.logger).Log(
+				"msg", "skipping scrapeconfig",
+				"error", err.Error(),
+				"scrapeconfig", sc.GetNamespace()+"/"+sc.GetName(),
+				"namespace", p.Namespace,
+				"prometheus", p.Name,
+			)
+			operator.RecordWarningEvent(c.eventRecorder, p, operator.ResourceRejectedReason, "%s %s/%s was rejected: %v", monitoringv1alpha1.ScrapeConfigsKind, sc.GetNamespace(), sc.GetName(), err)
+		}
+		if err = ValidateScrapeConfig(sc); err != nil {
+			rejectFn(sc, err)
+			continue
+		}
+
+		if err = c.addScrapeConfigAssets(ctx, sc, store); err != nil {
+			rejectFn(sc, err)
+			continue
+		}
+
+		res[scName] = sc
+	}
+
+	scKeys := make([]string, 0)
+	for k := range res {
+		scKeys = append(scKeys, k)
+	}
+	level.Debug(c.logger).Log("msg", "selected ScrapeConfigs", "scrapeConfigs", strings.Join(scKeys, ","), "namespace", p.Namespace, "prometheus", p.Name)
+
+	if pKey, ok := c.keyFunc(p); ok {
+		c.metricsFor(p).SetSelectedResources(pKey, monitoringv1alpha1.ScrapeConfigsKind, len(res))
+		c.metricsFor(p).SetRejectedResources(pKey, monitoringv1alpha1.ScrapeConfigsKind, rejected)
+
+		clamped := 0
+		for _, r := range res {
+			if limitsClamped(p, r.Spec.SampleLimit, r.Spec.TargetLimit, r.Spec.LabelLimit, r.Spec.LabelNameLengthLimit, r.Spec.LabelValueLengthLimit) {
+				clamped++
+			}
+		}
+		c.metricsFor(p).SetClampedResources(pKey, monitoringv1alpha1.ScrapeConfigsKind, clamped)
 	}
 
 	return res, nil
 }
 
+// addScrapeConfigAssets loads the secrets referenced by the ScrapeConfig
+// object into the store.
+func (c *Operator) addScrapeConfigAssets(ctx context.Context, sc *monitoringv1alpha1.ScrapeConfig, store *assets.Store) error {
+	ns := sc.GetNamespace()
+
+	scKey := fmt.Sprintf("scrapeConfig/%s/%s", ns, sc.GetName())
+	if err := store.AddBasicAuth(ctx, ns, sc.Spec.BasicAuth, scKey); err != nil {
+		return err
+	}
+
+	scAuthKey := fmt.Sprintf("scrapeConfig/auth/%s/%s", ns, sc.GetName())
+	if err := store.AddSafeAuthorizationCredentials(ctx, ns, sc.Spec.Authorization, scAuthKey); err != nil {
+		return err
+	}
+
+	if sc.Spec.TLSConfig != nil {
+		if err := store.AddSafeTLSConfig(ctx, ns, sc.Spec.TLSConfig); err != nil {
+			return err
+		}
+	}
+
+	for i, config := range sc.Spec.HTTPSDConfigs {
+		if err := store.AddBasicAuth(ctx, ns, config.BasicAuth, fmt.Sprintf("%s/httpsdconfig/%d", scKey, i)); err != nil {
+			return errors.Wrapf(err, "httpSDConfigs[%d]", i)
+		}
+
+		if err := store.AddSafeAuthorizationCredentials(ctx, ns, config.Authorization, fmt.Sprintf("%s/httpsdconfig/%d", scAuthKey, i)); err != nil {
+			return errors.Wrapf(err, "httpSDConfigs[%d]", i)
+		}
+
+		if config.TLSConfig != nil {
+			if err := store.AddSafeTLSConfig(ctx, ns, config.TLSConfig); err != nil {
+				return errors.Wrapf(err, "httpSDConfigs[%d]", i)
+			}
+		}
+	}
+
+	return nil
+}
+
 func testForArbitraryFSAccess(e monitoringv1.Endpoint) error {
 	if e.BearerTokenFile != "" {
 		return errors.New("it accesses file system via bearer token file which Prometheus specification prohibits")
@@ -2045,6 +2901,83 @@ func (c *Operator) listMatchingNamespaces(selector labels.Selector) ([]string, e
 	return ns, nil
 }
 
+// validateRemoteReadSpec checks that the URLs are valid and that mutually
+// exclusive configurations are not included in the Prometheus remoteRead
+// configuration section.
+// Reference:
+// https://github.com/prometheus/prometheus/blob/main/docs/configuration/configuration.md#remote_read
+func validateRemoteReadSpec(spec monitoringv1.RemoteReadSpec) error {
+	u, err := url.Parse(spec.URL)
+	if err != nil {
+		return errors.Wrapf(err, "invalid url %q", spec.URL)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("invalid url %q: it should be an absolute HTTP(S) URL", spec.URL)
+	}
+
+	if spec.ProxyURL != "" {
+		if _, err := url.Parse(spec.ProxyURL); err != nil {
+			return errors.Wrapf(err, "invalid proxyUrl %q", spec.ProxyURL)
+		}
+	}
+
+	var nonEmptyFields []string
+	for k, isSet := range map[string]bool{
+		"basicAuth":       spec.BasicAuth != nil,
+		"oauth2":          spec.OAuth2 != nil,
+		"authorization":   spec.Authorization != nil,
+		"bearerToken":     spec.BearerToken != "",
+		"bearerTokenFile": spec.BearerTokenFile != "",
+	} {
+		if !isSet {
+			continue
+		}
+		nonEmptyFields = append(nonEmptyFields, fmt.Sprintf("%q", k))
+	}
+
+	if len(nonEmptyFields) > 1 {
+		sort.Strings(nonEmptyFields)
+		return errors.Errorf("%s can't be set at the same time, at most one of them must be defined", strings.Join(nonEmptyFields, " and "))
+	}
+
+	return nil
+}
+
+// AddRemoteWriteAssets validates the remote write specs and loads their
+// credentials into the store.
+func AddRemoteWriteAssets(ctx context.Context, store *assets.Store, namespace string, specs []monitoringv1.RemoteWriteSpec) error {
+	for i, remote := range specs {
+		if err := validateRemoteWriteSpec(remote); err != nil {
+			return errors.Wrapf(err, "remote write %d", i)
+		}
+		key := fmt.Sprintf("remoteWrite/%d", i)
+		if err := store.AddBasicAuth(ctx, namespace, remote.BasicAuth, key); err != nil {
+			return errors.Wrapf(err, "remote write %d", i)
+		}
+		if err := store.AddOAuth2(ctx, namespace, remote.OAuth2, key); err != nil {
+			return errors.Wrapf(err, "remote write %d", i)
+		}
+		if err := store.AddTLSConfig(ctx, namespace, remote.TLSConfig); err != nil {
+			return errors.Wrapf(err, "remote write %d", i)
+		}
+		if err := store.AddAuthorizationCredentials(ctx, namespace, remote.Authorization, fmt.Sprintf("remoteWrite/auth/%d", i)); err != nil {
+			return errors.Wrapf(err, "remote write %d", i)
+		}
+		if err := store.AddSigV4(ctx, namespace, remote.Sigv4, key); err != nil {
+			return errors.Wrapf(err, "remote write %d", i)
+		}
+		if err := store.AddAzureOAuth(ctx, namespace, remote.AzureAD, fmt.Sprintf("remoteWrite/azuread/%d", i)); err != nil {
+			return errors.Wrapf(err, "remote write %d", i)
+		}
+		if err := store.AddProxyConfig(ctx, namespace, remote.ProxyConfig, key); err != nil {
+			return errors.Wrapf(err, "remote write %d", i)
+		}
+	}
+
+	return nil
+}
+
 // validateRemoteWriteSpec checks that mutually exclusive configurations are not
 // included in the Prometheus remoteWrite configuration section.
 // Reference:
@@ -2056,6 +2989,7 @@ func validateRemoteWriteSpec(spec monitoringv1.RemoteWriteSpec) error {
 		"oauth2":        spec.OAuth2,
 		"authorization": spec.Authorization,
 		"sigv4":         spec.Sigv4,
+		"azureAd":       spec.AzureAD,
 	} {
 		if reflect.ValueOf(v).IsNil() {
 			continue
@@ -2067,5 +3001,15 @@ func validateRemoteWriteSpec(spec monitoringv1.RemoteWriteSpec) error {
 		return errors.Errorf("%s can't be set at the same time, at most one of them must be defined", strings.Join(nonNilFields, " and "))
 	}
 
-	return nil
+	if spec.AzureAD != nil {
+		if spec.AzureAD.ManagedIdentity == nil && spec.AzureAD.OAuth == nil {
+			return errors.New("must provide Azure Managed Identity or Azure OAuth in the Azure AD config")
+		}
+
+		if spec.AzureAD.ManagedIdentity != nil && spec.AzureAD.OAuth != nil {
+			return errors.New("cannot provide both Azure Managed Identity and Azure OAuth in the Azure AD config")
+		}
+	}
+
+	return validateProxyConfig(spec.ProxyURL, spec.ProxyConfig)
 }
@@ -42,11 +42,11 @@ func TestCreateStatefulSetInputHash(t *testing.T) {
 	p2.Spec.Version = "v1.7.2"
 	c := operator.Config{}
 
-	p1Hash, err := createSSetInputHash(p1, c, []string{}, nil)
+	p1Hash, err := createSSetInputHash(p1, c, []string{}, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	p2Hash, err := createSSetInputHash(p2, c, []string{}, nil)
+	p2Hash, err := createSSetInputHash(p2, c, []string{}, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -54,6 +54,42 @@ func TestCreateStatefulSetInputHash(t *testing.T) {
 	if p1Hash == p2Hash {
 		t.Fatal("expected two different Prometheus CRDs to result in two different hash but got equal hash")
 	}
+
+	p3Hash, err := createSSetInputHash(p1, c, []string{}, map[string]string{sidecarSecretsHashAnnotation: "12345"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p1Hash == p3Hash {
+		t.Fatal("expected different sidecar secrets to result in two different hash but got equal hash")
+	}
+
+	p4Hash, err := createSSetInputHash(p1, c, []string{}, map[string]string{sidecarSecretsHashAnnotation: "12345", objstoreConfigHashAnnotation: "67890"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p3Hash == p4Hash {
+		t.Fatal("expected different object storage configurations to result in two different hash but got equal hash")
+	}
+}
+
+func TestDisallowedCapabilitiesList(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			EnableAdminAPI:            true,
+			EnableRemoteWriteReceiver: true,
+		},
+	}
+
+	if got := disallowedCapabilities(p, operator.Config{}); len(got) != 0 {
+		t.Fatalf("expected no disallowed capabilities, got %v", got)
+	}
+
+	got := disallowedCapabilities(p, operator.Config{DisallowAdminAPI: true, DisallowRemoteWriteReceiver: true})
+	if !reflect.DeepEqual(got, []string{"enableAdminAPI", "enableRemoteWriteReceiver"}) {
+		t.Fatalf("unexpected disallowed capabilities %v", got)
+	}
 }
 
 func TestGetNodeAddresses(t *testing.T) {
@@ -203,6 +239,93 @@ func TestPrometheusKeyToStatefulSetKey(t *testing.T) {
 	}
 }
 
+func TestValidateRemoteReadConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		spec      monitoringv1.RemoteReadSpec
+		expectErr bool
+	}{
+		{
+			name: "valid_url",
+			spec: monitoringv1.RemoteReadSpec{
+				URL: "http://example.com/api/v1/read",
+			},
+		},
+		{
+			name:      "empty_url",
+			spec:      monitoringv1.RemoteReadSpec{},
+			expectErr: true,
+		},
+		{
+			name: "relative_url",
+			spec: monitoringv1.RemoteReadSpec{
+				URL: "/api/v1/read",
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid_scheme",
+			spec: monitoringv1.RemoteReadSpec{
+				URL: "ftp://example.com",
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid_proxy_url",
+			spec: monitoringv1.RemoteReadSpec{
+				URL:      "http://example.com",
+				ProxyURL: "http://proxy:port",
+			},
+			expectErr: true,
+		},
+		{
+			name: "with_OAuth2",
+			spec: monitoringv1.RemoteReadSpec{
+				URL:    "http://example.com",
+				OAuth2: &monitoringv1.OAuth2{},
+			},
+		},
+		{
+			name: "with_OAuth2_and_BasicAuth",
+			spec: monitoringv1.RemoteReadSpec{
+				URL:       "http://example.com",
+				OAuth2:    &monitoringv1.OAuth2{},
+				BasicAuth: &monitoringv1.BasicAuth{},
+			},
+			expectErr: true,
+		},
+		{
+			name: "with_Authorization_and_BearerToken",
+			spec: monitoringv1.RemoteReadSpec{
+				URL:           "http://example.com",
+				Authorization: &monitoringv1.Authorization{},
+				BearerToken:   "token",
+			},
+			expectErr: true,
+		},
+		{
+			name: "with_BearerToken_and_BearerTokenFile",
+			spec: monitoringv1.RemoteReadSpec{
+				URL:             "http://example.com",
+				BearerToken:     "token",
+				BearerTokenFile: "/etc/token",
+			},
+			expectErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateRemoteReadSpec(tc.spec)
+			if err != nil && !tc.expectErr {
+				t.Fatalf("unexpected error occurred: %v", err)
+			}
+			if err == nil && tc.expectErr {
+				t.Fatalf("expected an error, got nil")
+			}
+		})
+	}
+}
+
 func TestValidateRemoteWriteConfig(t *testing.T) {
 	cases := []struct {
 		name      string
@@ -249,6 +372,44 @@ func TestValidateRemoteWriteConfig(t *testing.T) {
 				OAuth2:    &monitoringv1.OAuth2{},
 			},
 			expectErr: true,
+		}, {
+			name: "with_AzureAD_ManagedIdentity",
+			spec: monitoringv1.RemoteWriteSpec{
+				AzureAD: &monitoringv1.AzureAD{
+					ManagedIdentity: &monitoringv1.ManagedIdentity{ClientID: "client-id"},
+				},
+			},
+		}, {
+			name: "with_AzureAD_OAuth",
+			spec: monitoringv1.RemoteWriteSpec{
+				AzureAD: &monitoringv1.AzureAD{
+					OAuth: &monitoringv1.AzureOAuth{ClientID: "client-id", TenantID: "tenant-id"},
+				},
+			},
+		}, {
+			name: "with_AzureAD_without_credentials",
+			spec: monitoringv1.RemoteWriteSpec{
+				AzureAD: &monitoringv1.AzureAD{},
+			},
+			expectErr: true,
+		}, {
+			name: "with_AzureAD_ManagedIdentity_and_OAuth",
+			spec: monitoringv1.RemoteWriteSpec{
+				AzureAD: &monitoringv1.AzureAD{
+					ManagedIdentity: &monitoringv1.ManagedIdentity{ClientID: "client-id"},
+					OAuth:           &monitoringv1.AzureOAuth{ClientID: "client-id", TenantID: "tenant-id"},
+				},
+			},
+			expectErr: true,
+		}, {
+			name: "with_AzureAD_and_SigV4",
+			spec: monitoringv1.RemoteWriteSpec{
+				AzureAD: &monitoringv1.AzureAD{
+					ManagedIdentity: &monitoringv1.ManagedIdentity{ClientID: "client-id"},
+				},
+				Sigv4: &monitoringv1.Sigv4{},
+			},
+			expectErr: true,
 		},
 	}
 	for _, c := range cases {
@@ -21,6 +21,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/blang/semver/v4"
 	"github.com/go-kit/log"
@@ -30,14 +31,18 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 )
 
 const (
-	kubernetesSDRoleEndpoint = "endpoints"
-	kubernetesSDRolePod      = "pod"
-	kubernetesSDRoleIngress  = "ingress"
+	kubernetesSDRoleEndpoint      = "endpoints"
+	kubernetesSDRoleEndpointSlice = "endpointslice"
+	kubernetesSDRolePod           = "pod"
+	kubernetesSDRoleIngress       = "ingress"
+	kubernetesSDRoleService       = "service"
+	kubernetesSDRoleNode          = "node"
 )
 
 var (
@@ -47,6 +52,11 @@ var (
 // ConfigGenerator is used to create Prometheus configurations from operator resources.
 type ConfigGenerator struct {
 	logger log.Logger
+
+	mtx sync.Mutex
+	// fragments caches the scrape configurations generated for the monitor
+	// resources, per Prometheus object.
+	fragments map[dependent]map[string]scrapeConfigFragment
 }
 
 // NewConfigGenerator creates a ConfigGenerator instance using the provided Logger.
@@ -79,7 +89,7 @@ func stringMapToMapSlice(m map[string]string) yaml.MapSlice {
 
 func addSafeTLStoYaml(cfg yaml.MapSlice, namespace string, tls v1.SafeTLSConfig) yaml.MapSlice {
 	pathForSelector := func(sel v1.SecretOrConfigMap) string {
-		return path.Join(tlsAssetsDir, assets.TLSAssetKeyFromSelector(namespace, sel).String())
+		return path.Join(TLSAssetsDir, assets.TLSAssetKeyFromSelector(namespace, sel).String())
 	}
 	tlsConfig := yaml.MapSlice{
 		{Key: "insecure_skip_verify", Value: tls.InsecureSkipVerify},
@@ -177,6 +187,80 @@ func addAuthorizationToYaml(
 	return append(cfg, yaml.MapItem{Key: "authorization", Value: authCfg})
 }
 
+func addProxyConfigToYaml(
+	cfg yaml.MapSlice,
+	version semver.Version,
+	assetStoreKey string,
+	store *assets.Store,
+	pc v1.ProxyConfig,
+	logger log.Logger,
+) yaml.MapSlice {
+	if pc.NoProxy == nil && pc.ProxyFromEnvironment == nil && len(pc.ProxyConnectHeader) == 0 {
+		return cfg
+	}
+
+	if version.LT(semver.MustParse("2.43.0")) {
+		level.Warn(logger).Log("msg", "found proxy settings but prometheus version is < 2.43.0, ignoring",
+			"version", version, "minimum_version", "2.43.0")
+		return cfg
+	}
+
+	if pc.NoProxy != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "no_proxy", Value: *pc.NoProxy})
+	}
+
+	if pc.ProxyFromEnvironment != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "proxy_from_environment", Value: *pc.ProxyFromEnvironment})
+	}
+
+	if header, ok := store.ProxyConnectHeaderAssets[assetStoreKey]; ok {
+		names := make([]string, 0, len(header))
+		for name := range header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		proxyConnectHeader := yaml.MapSlice{}
+		for _, name := range names {
+			proxyConnectHeader = append(proxyConnectHeader, yaml.MapItem{Key: name, Value: header[name]})
+		}
+		cfg = append(cfg, yaml.MapItem{Key: "proxy_connect_header", Value: proxyConnectHeader})
+	}
+
+	return cfg
+}
+
+// addHTTPClientOptionsToYaml adds the follow_redirects and enable_http2
+// settings to the HTTP client configuration if they're supported by the
+// Prometheus version.
+func addHTTPClientOptionsToYaml(
+	cfg yaml.MapSlice,
+	version semver.Version,
+	followRedirects *bool,
+	enableHTTP2 *bool,
+	logger log.Logger,
+) yaml.MapSlice {
+	if followRedirects != nil {
+		if version.LT(semver.MustParse("2.26.0")) {
+			level.Warn(logger).Log("msg", "found followRedirects setting but prometheus version is < 2.26.0, ignoring",
+				"version", version, "minimum_version", "2.26.0")
+		} else {
+			cfg = append(cfg, yaml.MapItem{Key: "follow_redirects", Value: *followRedirects})
+		}
+	}
+
+	if enableHTTP2 != nil {
+		if version.LT(semver.MustParse("2.35.0")) {
+			level.Warn(logger).Log("msg", "found enableHttp2 setting but prometheus version is < 2.35.0, ignoring",
+				"version", version, "minimum_version", "2.35.0")
+		} else {
+			cfg = append(cfg, yaml.MapItem{Key: "enable_http2", Value: *enableHTTP2})
+		}
+	}
+
+	return cfg
+}
+
 func buildExternalLabels(p *v1.Prometheus) yaml.MapSlice {
 	m := map[string]string{}
 
@@ -223,6 +307,15 @@ func validateConfigInputs(p *v1.Prometheus) error {
 			return err
 		}
 	}
+
+	if err := validateRemoteWriteToThanosReceive(p); err != nil {
+		return err
+	}
+
+	// The agent doesn't store the samples locally.
+	if isAgent(p) && len(remoteWriteSpecs(p)) == 0 {
+		return errors.New("at least one remote write endpoint is required in agent mode")
+	}
 	return nil
 }
 
@@ -240,6 +333,7 @@ func (cg *ConfigGenerator) GenerateConfig(
 	sMons map[string]*v1.ServiceMonitor,
 	pMons map[string]*v1.PodMonitor,
 	probes map[string]*v1.Probe,
+	sCons map[string]*monitoringv1alpha1.ScrapeConfig,
 	store *assets.Store,
 	additionalScrapeConfigs []byte,
 	additionalAlertRelabelConfigs []byte,
@@ -291,16 +385,30 @@ func (cg *ConfigGenerator) GenerateConfig(
 		})
 	}
 
+	if p.Spec.KeepDroppedTargets != nil {
+		enforcer := limitEnforcer{
+			logger:            cg.logger,
+			prometheusVersion: version,
+		}
+		globalItems = enforcer.addLimitsToYAML(globalItems, keepDroppedTargetsKey, *p.Spec.KeepDroppedTargets, nil)
+	}
+
+	globalItems = addScrapeProtocols(globalItems, version, p.Spec.ScrapeProtocols, cg.logger)
+
 	cfg = append(cfg, yaml.MapItem{Key: "global", Value: globalItems})
 
-	ruleFilePaths := []string{}
-	for _, name := range ruleConfigMapNames {
-		ruleFilePaths = append(ruleFilePaths, rulesDir+"/"+name+"/*.yaml")
+	// Prometheus refuses the rule_files, alerting and remote_read sections
+	// in agent mode.
+	if !isAgent(p) {
+		ruleFilePaths := []string{}
+		for _, name := range ruleConfigMapNames {
+			ruleFilePaths = append(ruleFilePaths, rulesDir+"/"+name+"/*.yaml")
+		}
+		cfg = append(cfg, yaml.MapItem{
+			Key:   "rule_files",
+			Value: ruleFilePaths,
+		})
 	}
-	cfg = append(cfg, yaml.MapItem{
-		Key:   "rule_files",
-		Value: ruleFilePaths,
-	})
 
 	sMonIdentifiers := make([]string, len(sMons))
 	i := 0
@@ -332,22 +440,103 @@ func (cg *ConfigGenerator) GenerateConfig(
 	// Sorting ensures, that we always generate the config in the same order.
 	sort.Strings(probeIdentifiers)
 
+	sConIdentifiers := make([]string, len(sCons))
+	i = 0
+	for k := range sCons {
+		sConIdentifiers[i] = k
+		i++
+	}
+
+	// Sorting ensures, that we always generate the config in the same order.
+	sort.Strings(sConIdentifiers)
+
 	apiserverConfig := p.Spec.APIServerConfig
 	shards := int32(1)
 	if p.Spec.Shards != nil && *p.Spec.Shards > 1 {
 		shards = *p.Spec.Shards
 	}
 
+	endpointsRole := cg.endpointsDiscoveryRole(version, p.Spec.ServiceDiscoveryRole)
+
+	// Only the scrape configurations of the monitors which changed since the
+	// last generation are regenerated.
+	fragments := cg.newFragmentCache(p, version, store)
+
 	var scrapeConfigs []yaml.MapSlice
 	for _, identifier := range sMonIdentifiers {
-		for i, ep := range sMons[identifier].Spec.Endpoints {
-			scrapeConfigs = append(scrapeConfigs,
-				cg.generateServiceMonitorConfig(
+		sMon := sMons[identifier]
+		scrapeConfigs = append(scrapeConfigs, fragments.get(v1.ServiceMonitorsKind, sMon, func() []yaml.MapSlice {
+			var cfgs []yaml.MapSlice
+			scrapeClass, _ := scrapeClassFor(p, sMon.Spec.ScrapeClassName)
+			for i, ep := range sMon.Spec.Endpoints {
+				cfgs = append(cfgs,
+					cg.generateServiceMonitorConfig(
+						version,
+						sMon,
+						ep, i,
+						apiserverConfig,
+						store,
+						scrapeClass,
+						endpointsRole,
+						p.Spec.OverrideHonorLabels,
+						p.Spec.OverrideHonorTimestamps,
+						p.Spec.IgnoreNamespaceSelectors,
+						p.Spec.EnforcedNamespaceLabel,
+						p.Spec.EnforcedSampleLimit,
+						p.Spec.EnforcedTargetLimit,
+						p.Spec.EnforcedLabelLimit,
+						p.Spec.EnforcedLabelNameLengthLimit,
+						p.Spec.EnforcedLabelValueLengthLimit,
+						p.Spec.EnforcedBodySizeLimit,
+						shards,
+					),
+				)
+			}
+			return cfgs
+		})...)
+	}
+	for _, identifier := range pMonIdentifiers {
+		pMon := pMons[identifier]
+		scrapeConfigs = append(scrapeConfigs, fragments.get(v1.PodMonitorsKind, pMon, func() []yaml.MapSlice {
+			var cfgs []yaml.MapSlice
+			scrapeClass, _ := scrapeClassFor(p, pMon.Spec.ScrapeClassName)
+			for i, ep := range pMon.Spec.PodMetricsEndpoints {
+				cfgs = append(cfgs,
+					cg.generatePodMonitorConfig(
+						version,
+						pMon, ep, i,
+						apiserverConfig,
+						store,
+						scrapeClass,
+						p.Spec.OverrideHonorLabels,
+						p.Spec.OverrideHonorTimestamps,
+						p.Spec.IgnoreNamespaceSelectors,
+						p.Spec.EnforcedNamespaceLabel,
+						p.Spec.EnforcedSampleLimit,
+						p.Spec.EnforcedTargetLimit,
+						p.Spec.EnforcedLabelLimit,
+						p.Spec.EnforcedLabelNameLengthLimit,
+						p.Spec.EnforcedLabelValueLengthLimit,
+						p.Spec.EnforcedBodySizeLimit,
+						shards,
+					),
+				)
+			}
+			return cfgs
+		})...)
+	}
+
+	for _, identifier := range probeIdentifiers {
+		probe := probes[identifier]
+		scrapeConfigs = append(scrapeConfigs, fragments.get(v1.ProbesKind, probe, func() []yaml.MapSlice {
+			scrapeClass, _ := scrapeClassFor(p, probe.Spec.ScrapeClassName)
+			return []yaml.MapSlice{
+				cg.generateProbeConfig(
 					version,
-					sMons[identifier],
-					ep, i,
+					probe,
 					apiserverConfig,
 					store,
+					scrapeClass,
 					p.Spec.OverrideHonorLabels,
 					p.Spec.OverrideHonorTimestamps,
 					p.Spec.IgnoreNamespaceSelectors,
@@ -358,22 +547,22 @@ func (cg *ConfigGenerator) GenerateConfig(
 					p.Spec.EnforcedLabelNameLengthLimit,
 					p.Spec.EnforcedLabelValueLengthLimit,
 					p.Spec.EnforcedBodySizeLimit,
-					shards,
 				),
-			)
-		}
+			}
+		})...)
 	}
-	for _, identifier := range pMonIdentifiers {
-		for i, ep := range pMons[identifier].Spec.PodMetricsEndpoints {
-			scrapeConfigs = append(scrapeConfigs,
-				cg.generatePodMonitorConfig(
+
+	for _, identifier := range sConIdentifiers {
+		sCon := sCons[identifier]
+		scrapeConfigs = append(scrapeConfigs, fragments.get(monitoringv1alpha1.ScrapeConfigsKind, sCon, func() []yaml.MapSlice {
+			return []yaml.MapSlice{
+				cg.generateScrapeConfig(
 					version,
-					pMons[identifier], ep, i,
+					sCon,
 					apiserverConfig,
 					store,
 					p.Spec.OverrideHonorLabels,
 					p.Spec.OverrideHonorTimestamps,
-					p.Spec.IgnoreNamespaceSelectors,
 					p.Spec.EnforcedNamespaceLabel,
 					p.Spec.EnforcedSampleLimit,
 					p.Spec.EnforcedTargetLimit,
@@ -383,33 +572,13 @@ func (cg *ConfigGenerator) GenerateConfig(
 					p.Spec.EnforcedBodySizeLimit,
 					shards,
 				),
-			)
-		}
-	}
-
-	for _, identifier := range probeIdentifiers {
-		scrapeConfigs = append(scrapeConfigs,
-			cg.generateProbeConfig(
-				version,
-				probes[identifier],
-				apiserverConfig,
-				store,
-				p.Spec.OverrideHonorLabels,
-				p.Spec.OverrideHonorTimestamps,
-				p.Spec.IgnoreNamespaceSelectors,
-				p.Spec.EnforcedNamespaceLabel,
-				p.Spec.EnforcedSampleLimit,
-				p.Spec.EnforcedTargetLimit,
-				p.Spec.EnforcedLabelLimit,
-				p.Spec.EnforcedLabelNameLengthLimit,
-				p.Spec.EnforcedLabelValueLengthLimit,
-				p.Spec.EnforcedBodySizeLimit,
-			),
-		)
+			}
+		})...)
 	}
+	cg.storeFragments(p, fragments)
 
 	var alertmanagerConfigs []yaml.MapSlice
-	alertmanagerConfigs = cg.generateAlertmanagerConfig(version, p.Spec.Alerting, apiserverConfig, store)
+	alertmanagerConfigs = cg.generateAlertmanagerConfig(version, p.Spec.Alerting, apiserverConfig, store, endpointsRole)
 
 	var addlScrapeConfigs []yaml.MapSlice
 	addlScrapeConfigs, err = cg.generateAdditionalScrapeConfigs(additionalScrapeConfigs, shards)
@@ -457,31 +626,56 @@ func (cg *ConfigGenerator) GenerateConfig(
 		return nil, errors.Wrap(err, "unmarshalling additional alerting relabel configs failed")
 	}
 
-	cfg = append(cfg, yaml.MapItem{
-		Key: "alerting",
-		Value: yaml.MapSlice{
-			{
-				Key:   "alert_relabel_configs",
-				Value: append(alertRelabelConfigs, additionalAlertRelabelConfigsYaml...),
-			},
-			{
-				Key:   "alertmanagers",
-				Value: alertmanagerConfigs,
+	if !isAgent(p) {
+		cfg = append(cfg, yaml.MapItem{
+			Key: "alerting",
+			Value: yaml.MapSlice{
+				{
+					Key:   "alert_relabel_configs",
+					Value: append(alertRelabelConfigs, additionalAlertRelabelConfigsYaml...),
+				},
+				{
+					Key:   "alertmanagers",
+					Value: alertmanagerConfigs,
+				},
 			},
-		},
-	})
+		})
+	}
 
-	if len(p.Spec.RemoteWrite) > 0 {
-		cfg = append(cfg, cg.generateRemoteWriteConfig(version, p, store))
+	if remoteWrite := remoteWriteSpecs(p); len(remoteWrite) > 0 {
+		cfg = append(cfg, cg.generateRemoteWriteConfig(version, p.Namespace, remoteWrite, store))
 	}
 
-	if len(p.Spec.RemoteRead) > 0 {
+	if len(p.Spec.RemoteRead) > 0 && !isAgent(p) {
 		cfg = append(cfg, cg.generateRemoteReadConfig(version, p, store))
 	}
 
+	if p.Spec.Exemplars != nil && p.Spec.Exemplars.MaxSize != nil {
+		cfg = cg.appendExemplarsConfig(cfg, version, p.Spec.Exemplars)
+	}
+
 	return yaml.Marshal(cfg)
 }
 
+func (cg *ConfigGenerator) appendExemplarsConfig(cfg yaml.MapSlice, version semver.Version, exemplars *v1.Exemplars) yaml.MapSlice {
+	if version.LT(semver.MustParse("2.29.0")) {
+		level.Warn(cg.logger).Log("msg", "ignoring exemplars maxSize not supported by Prometheus", "version", version, "minimum_version", "2.29.0")
+		return cfg
+	}
+
+	return append(cfg, yaml.MapItem{
+		Key: "storage",
+		Value: yaml.MapSlice{
+			{
+				Key: "exemplars",
+				Value: yaml.MapSlice{
+					{Key: "max_exemplars", Value: *exemplars.MaxSize},
+				},
+			},
+		},
+	})
+}
+
 // honorLabels determines the value of honor_labels.
 // if overrideHonorLabels is true and user tries to set the
 // value to true, we want to set honor_labels to false.
@@ -510,6 +704,42 @@ func honorTimestamps(cfg yaml.MapSlice, userHonorTimestamps *bool, overrideHonor
 	return append(cfg, yaml.MapItem{Key: "honor_timestamps", Value: honor && !overrideHonorTimestamps})
 }
 
+// scrapeNativeHistograms configures the scrape protocols of the scrape config
+// depending on whether native histograms should be scraped. Native histograms
+// are only exposed in the protobuf format.
+func scrapeNativeHistograms(cfg yaml.MapSlice, version semver.Version, scrape *bool, logger log.Logger) yaml.MapSlice {
+	if scrape == nil {
+		return cfg
+	}
+
+	if version.LT(semver.MustParse("2.49.0")) {
+		level.Warn(logger).Log("msg", "\"scrapeNativeHistograms\" is only available starting from prometheus 2.49.0",
+			"version", version)
+		return cfg
+	}
+
+	protocols := []v1.ScrapeProtocol{v1.OpenMetricsText1_0_0, v1.OpenMetricsText0_0_1, v1.PrometheusText0_0_4}
+	if *scrape {
+		protocols = append([]v1.ScrapeProtocol{v1.PrometheusProto}, protocols...)
+	}
+
+	return append(cfg, yaml.MapItem{Key: "scrape_protocols", Value: protocols})
+}
+
+func addScrapeProtocols(cfg yaml.MapSlice, version semver.Version, protocols []v1.ScrapeProtocol, logger log.Logger) yaml.MapSlice {
+	if len(protocols) == 0 {
+		return cfg
+	}
+
+	if version.LT(semver.MustParse("2.49.0")) {
+		level.Warn(logger).Log("msg", "\"scrapeProtocols\" is only available starting from prometheus 2.49.0",
+			"version", version)
+		return cfg
+	}
+
+	return append(cfg, yaml.MapItem{Key: "scrape_protocols", Value: protocols})
+}
+
 func initRelabelings() []yaml.MapSlice {
 	// Relabel prometheus job name into a meta label
 	return []yaml.MapSlice{
@@ -520,12 +750,72 @@ func initRelabelings() []yaml.MapSlice {
 	}
 }
 
+// scrapeClassFor returns the scrape class referenced by name. When name is
+// nil, it returns the default scrape class of the Prometheus object (if any).
+// The boolean is false if the referenced scrape class doesn't exist.
+func scrapeClassFor(p *v1.Prometheus, name *string) (*v1.ScrapeClass, bool) {
+	for i := range p.Spec.ScrapeClasses {
+		sc := &p.Spec.ScrapeClasses[i]
+
+		if name == nil && sc.Default != nil && *sc.Default {
+			return sc, true
+		}
+
+		if name != nil && sc.Name == *name {
+			return sc, true
+		}
+	}
+
+	return nil, name == nil
+}
+
+func scrapeClassAuthKey(name string) string {
+	return fmt.Sprintf("scrapeClass/auth/%s", name)
+}
+
+// addScrapeClassAuthorizationToYaml adds the authorization section of the
+// scrape class to the scrape configuration.
+func addScrapeClassAuthorizationToYaml(
+	cfg yaml.MapSlice,
+	version semver.Version,
+	store *assets.Store,
+	scrapeClass *v1.ScrapeClass,
+	logger log.Logger,
+) yaml.MapSlice {
+	if scrapeClass == nil {
+		return cfg
+	}
+
+	return addAuthorizationToYaml(cfg, version, scrapeClassAuthKey(scrapeClass.Name), store, scrapeClass.Authorization, logger)
+}
+
+// scrapeClassRelabelings returns the relabelings of the scrape class followed
+// by the relabelings of the scrape object.
+func scrapeClassRelabelings(scrapeClass *v1.ScrapeClass, rcs []*v1.RelabelConfig) []*v1.RelabelConfig {
+	if scrapeClass == nil {
+		return rcs
+	}
+
+	return append(append([]*v1.RelabelConfig{}, scrapeClass.Relabelings...), rcs...)
+}
+
+// scrapeClassMetricRelabelings returns the metric relabelings of the scrape
+// class followed by the metric relabelings of the scrape object.
+func scrapeClassMetricRelabelings(scrapeClass *v1.ScrapeClass, rcs []*v1.RelabelConfig) []*v1.RelabelConfig {
+	if scrapeClass == nil {
+		return rcs
+	}
+
+	return append(append([]*v1.RelabelConfig{}, scrapeClass.MetricRelabelings...), rcs...)
+}
+
 func (cg *ConfigGenerator) generatePodMonitorConfig(
 	version semver.Version,
 	m *v1.PodMonitor,
 	ep v1.PodMetricsEndpoint,
 	i int, apiserverConfig *v1.APIServerConfig,
 	store *assets.Store,
+	scrapeClass *v1.ScrapeClass,
 	ignoreHonorLabels bool,
 	overrideHonorTimestamps bool,
 	ignoreNamespaceSelectors bool,
@@ -556,7 +846,7 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 	}
 
 	selectedNamespaces := getNamespacesFromNamespaceSelector(&m.Spec.NamespaceSelector, m.Namespace, ignoreNamespaceSelectors)
-	cfg = append(cfg, cg.generateK8SSDConfig(version, selectedNamespaces, apiserverConfig, store, kubernetesSDRolePod))
+	cfg = append(cfg, cg.generateK8SSDConfig(version, selectedNamespaces, apiserverConfig, store, kubernetesSDRolePod, m.Spec.AttachMetadata))
 
 	if ep.Interval != "" {
 		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: ep.Interval})
@@ -579,6 +869,8 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 
 	if ep.TLSConfig != nil {
 		cfg = addSafeTLStoYaml(cfg, m.Namespace, ep.TLSConfig.SafeTLSConfig)
+	} else if scrapeClass != nil {
+		cfg = addTLStoYaml(cfg, m.Namespace, scrapeClass.TLSConfig)
 	}
 
 	if ep.BearerTokenSecret.Name != "" {
@@ -600,9 +892,15 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 
 	assetKey := fmt.Sprintf("podMonitor/%s/%s/%d", m.Namespace, m.Name, i)
 	cfg = addOAuth2ToYaml(cfg, version, ep.OAuth2, store.OAuth2Assets, assetKey)
+	cfg = addProxyConfigToYaml(cfg, version, assetKey, store, ep.ProxyConfig, logger)
+	cfg = addHTTPClientOptionsToYaml(cfg, version, ep.FollowRedirects, ep.EnableHttp2, logger)
 
 	cfg = addSafeAuthorizationToYaml(cfg, version, fmt.Sprintf("podMonitor/auth/%s/%s/%d", m.Namespace, m.Name, i), store, ep.Authorization, logger)
 
+	if ep.BearerTokenSecret.Name == "" && ep.BasicAuth == nil && ep.OAuth2 == nil && ep.Authorization == nil {
+		cfg = addScrapeClassAuthorizationToYaml(cfg, version, store, scrapeClass, logger)
+	}
+
 	relabelings := initRelabelings()
 
 	var labelKeys []string
@@ -651,6 +949,15 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 		}
 	}
 
+	// Drop the pods which aren't running anymore unless explicitly disabled.
+	if ep.FilterRunning == nil || *ep.FilterRunning {
+		relabelings = append(relabelings, yaml.MapSlice{
+			{Key: "action", Value: "drop"},
+			{Key: "source_labels", Value: []string{"__meta_kubernetes_pod_phase"}},
+			{Key: "regex", Value: "(Failed|Succeeded)"},
+		})
+	}
+
 	// Filter targets based on correct port for the endpoint.
 	if ep.Port != "" {
 		relabelings = append(relabelings, yaml.MapSlice{
@@ -737,7 +1044,7 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 		obj:                    m,
 		enforcedNamespaceLabel: enforcedNamespaceLabel,
 	}
-	relabelings = append(relabelings, rcg.generate(ep.RelabelConfigs)...)
+	relabelings = append(relabelings, rcg.generate(scrapeClassRelabelings(scrapeClass, ep.RelabelConfigs))...)
 
 	relabelings = generateAddressShardingRelabelingRules(relabelings, shards)
 	cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
@@ -751,11 +1058,19 @@ func (cg *ConfigGenerator) generatePodMonitorConfig(
 	cfg = enforcer.addLimitsToYAML(cfg, labelLimitKey, m.Spec.LabelLimit, enforcedLabelLimit)
 	cfg = enforcer.addLimitsToYAML(cfg, labelNameLengthLimitKey, m.Spec.LabelNameLengthLimit, enforcedLabelNameLengthLimit)
 	cfg = enforcer.addLimitsToYAML(cfg, labelValueLengthLimitKey, m.Spec.LabelValueLengthLimit, enforcedLabelValueLengthLimit)
+	cfg = enforcer.addLimitsToYAML(cfg, keepDroppedTargetsKey, m.Spec.KeepDroppedTargets, nil)
 
 	// Since BodySizeLimit is defined only in PrometheusCRD
 	cfg = enforcer.addBodySizeLimitsToYAML(cfg, enforcedBodySizeLimit)
 
-	cfg = append(cfg, yaml.MapItem{Key: "metric_relabel_configs", Value: rcg.generate(ep.MetricRelabelConfigs)})
+	cfg = enforcer.addLimitsToYAML(cfg, nativeHistogramBucketLimitKey, ep.NativeHistogramBucketLimit, nil)
+	if ep.ScrapeNativeHistograms != nil {
+		cfg = scrapeNativeHistograms(cfg, version, ep.ScrapeNativeHistograms, logger)
+	} else {
+		cfg = addScrapeProtocols(cfg, version, m.Spec.ScrapeProtocols, logger)
+	}
+
+	cfg = append(cfg, yaml.MapItem{Key: "metric_relabel_configs", Value: rcg.generate(scrapeClassMetricRelabelings(scrapeClass, ep.MetricRelabelConfigs))})
 
 	return cfg
 }
@@ -765,6 +1080,7 @@ func (cg *ConfigGenerator) generateProbeConfig(
 	m *v1.Probe,
 	apiserverConfig *v1.APIServerConfig,
 	store *assets.Store,
+	scrapeClass *v1.ScrapeClass,
 	ignoreHonorLabels bool,
 	overrideHonorTimestamps bool,
 	ignoreNamespaceSelectors bool,
@@ -822,10 +1138,13 @@ func (cg *ConfigGenerator) generateProbeConfig(
 	cfg = enforcer.addLimitsToYAML(cfg, labelLimitKey, m.Spec.LabelLimit, enforcedLabelLimit)
 	cfg = enforcer.addLimitsToYAML(cfg, labelNameLengthLimitKey, m.Spec.LabelNameLengthLimit, enforcedLabelNameLengthLimit)
 	cfg = enforcer.addLimitsToYAML(cfg, labelValueLengthLimitKey, m.Spec.LabelValueLengthLimit, enforcedLabelValueLengthLimit)
+	cfg = enforcer.addLimitsToYAML(cfg, keepDroppedTargetsKey, m.Spec.KeepDroppedTargets, nil)
 
 	// Since BodySizeLimit is defined only in PrometheusCRD
 	cfg = enforcer.addBodySizeLimitsToYAML(cfg, enforcedBodySizeLimit)
 
+	cfg = addScrapeProtocols(cfg, version, m.Spec.ScrapeProtocols, logger)
+
 	relabelings := initRelabelings()
 
 	if m.Spec.JobName != "" {
@@ -844,8 +1163,14 @@ func (cg *ConfigGenerator) generateProbeConfig(
 
 	// Generate static_config section.
 	if m.Spec.Targets.StaticConfig != nil {
+		targets := m.Spec.Targets.StaticConfig.Targets
+		if m.Spec.Targets.StaticConfig.StaticFrom != nil {
+			// Don't modify the targets of the Probe object.
+			targets = append(append([]string{}, targets...), store.ProbeTargetsAssets[fmt.Sprintf("probe/%s/%s", m.Namespace, m.Name)]...)
+		}
+
 		staticConfig := yaml.MapSlice{
-			{Key: "targets", Value: m.Spec.Targets.StaticConfig.Targets},
+			{Key: "targets", Value: targets},
 		}
 
 		if m.Spec.Targets.StaticConfig.Labels != nil {
@@ -882,7 +1207,7 @@ func (cg *ConfigGenerator) generateProbeConfig(
 		}...)
 
 		// Add configured relabelings.
-		relabelings = append(relabelings, rcg.generate(m.Spec.Targets.StaticConfig.RelabelConfigs)...)
+		relabelings = append(relabelings, rcg.generate(scrapeClassRelabelings(scrapeClass, m.Spec.Targets.StaticConfig.RelabelConfigs))...)
 
 		cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
 	}
@@ -938,7 +1263,7 @@ func (cg *ConfigGenerator) generateProbeConfig(
 		}
 
 		selectedNamespaces := getNamespacesFromNamespaceSelector(&m.Spec.Targets.Ingress.NamespaceSelector, m.Namespace, ignoreNamespaceSelectors)
-		cfg = append(cfg, cg.generateK8SSDConfig(version, selectedNamespaces, apiserverConfig, store, kubernetesSDRoleIngress))
+		cfg = append(cfg, cg.generateK8SSDConfig(version, selectedNamespaces, apiserverConfig, store, kubernetesSDRoleIngress, nil))
 
 		// Relabelings for ingress SD.
 		relabelings = append(relabelings, []yaml.MapSlice{
@@ -977,7 +1302,7 @@ func (cg *ConfigGenerator) generateProbeConfig(
 			obj:                    m,
 			enforcedNamespaceLabel: enforcedNamespaceLabel,
 		}
-		relabelings = append(relabelings, rcg.generate(m.Spec.Targets.Ingress.RelabelConfigs)...)
+		relabelings = append(relabelings, rcg.generate(scrapeClassRelabelings(scrapeClass, m.Spec.Targets.Ingress.RelabelConfigs))...)
 
 		cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
 
@@ -985,6 +1310,8 @@ func (cg *ConfigGenerator) generateProbeConfig(
 
 	if m.Spec.TLSConfig != nil {
 		cfg = addSafeTLStoYaml(cfg, m.Namespace, m.Spec.TLSConfig.SafeTLSConfig)
+	} else if scrapeClass != nil {
+		cfg = addTLStoYaml(cfg, m.Namespace, scrapeClass.TLSConfig)
 	}
 
 	if m.Spec.BearerTokenSecret.Name != "" {
@@ -1007,10 +1334,221 @@ func (cg *ConfigGenerator) generateProbeConfig(
 
 	assetKey := fmt.Sprintf("probe/%s/%s", m.Namespace, m.Name)
 	cfg = addOAuth2ToYaml(cfg, version, m.Spec.OAuth2, store.OAuth2Assets, assetKey)
+	cfg = addProxyConfigToYaml(cfg, version, assetKey, store, m.Spec.ProberSpec.ProxyConfig, logger)
 
 	cfg = addSafeAuthorizationToYaml(cfg, version, fmt.Sprintf("probe/auth/%s/%s", m.Namespace, m.Name), store, m.Spec.Authorization, logger)
 
-	cfg = append(cfg, yaml.MapItem{Key: "metric_relabel_configs", Value: rcg.generate(m.Spec.MetricRelabelConfigs)})
+	if m.Spec.BearerTokenSecret.Name == "" && m.Spec.BasicAuth == nil && m.Spec.OAuth2 == nil && m.Spec.Authorization == nil {
+		cfg = addScrapeClassAuthorizationToYaml(cfg, version, store, scrapeClass, logger)
+	}
+
+	cfg = append(cfg, yaml.MapItem{Key: "metric_relabel_configs", Value: rcg.generate(scrapeClassMetricRelabelings(scrapeClass, m.Spec.MetricRelabelConfigs))})
+
+	return cfg
+}
+
+func (cg *ConfigGenerator) generateScrapeConfig(
+	version semver.Version,
+	sc *monitoringv1alpha1.ScrapeConfig,
+	apiserverConfig *v1.APIServerConfig,
+	store *assets.Store,
+	overrideHonorLabels bool,
+	overrideHonorTimestamps bool,
+	enforcedNamespaceLabel string,
+	enforcedSampleLimit *uint64,
+	enforcedTargetLimit *uint64,
+	enforcedLabelLimit *uint64,
+	enforcedLabelNameLengthLimit *uint64,
+	enforcedLabelValueLengthLimit *uint64,
+	enforcedBodySizeLimit string,
+	shards int32,
+) yaml.MapSlice {
+	logger := log.With(cg.logger, "scrapeConfig", sc.Name, "namespace", sc.Namespace)
+
+	cfg := yaml.MapSlice{
+		{
+			Key:   "job_name",
+			Value: fmt.Sprintf("scrapeConfig/%s/%s", sc.Namespace, sc.Name),
+		},
+		{
+			Key:   "honor_labels",
+			Value: honorLabels(sc.Spec.HonorLabels, overrideHonorLabels),
+		},
+	}
+	if version.Major == 2 && version.Minor >= 9 {
+		cfg = honorTimestamps(cfg, sc.Spec.HonorTimestamps, overrideHonorTimestamps)
+	}
+
+	if sc.Spec.ScrapeInterval != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: sc.Spec.ScrapeInterval})
+	}
+	if sc.Spec.ScrapeTimeout != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scrape_timeout", Value: sc.Spec.ScrapeTimeout})
+	}
+	if sc.Spec.MetricsPath != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "metrics_path", Value: sc.Spec.MetricsPath})
+	}
+	if sc.Spec.Params != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "params", Value: sc.Spec.Params})
+	}
+	if sc.Spec.Scheme != "" {
+		cfg = append(cfg, yaml.MapItem{Key: "scheme", Value: sc.Spec.Scheme})
+	}
+
+	if sc.Spec.TLSConfig != nil {
+		cfg = addSafeTLStoYaml(cfg, sc.Namespace, *sc.Spec.TLSConfig)
+	}
+
+	assetKey := fmt.Sprintf("scrapeConfig/%s/%s", sc.Namespace, sc.Name)
+	if sc.Spec.BasicAuth != nil {
+		if s, ok := store.BasicAuthAssets[assetKey]; ok {
+			cfg = append(cfg, yaml.MapItem{
+				Key: "basic_auth", Value: yaml.MapSlice{
+					{Key: "username", Value: s.Username},
+					{Key: "password", Value: s.Password},
+				},
+			})
+		}
+	}
+
+	cfg = addSafeAuthorizationToYaml(cfg, version, fmt.Sprintf("scrapeConfig/auth/%s/%s", sc.Namespace, sc.Name), store, sc.Spec.Authorization, logger)
+
+	enforcer := limitEnforcer{
+		logger:            logger,
+		prometheusVersion: version,
+	}
+	cfg = enforcer.addLimitsToYAML(cfg, sampleLimitKey, sc.Spec.SampleLimit, enforcedSampleLimit)
+	cfg = enforcer.addLimitsToYAML(cfg, targetLimitKey, sc.Spec.TargetLimit, enforcedTargetLimit)
+	cfg = enforcer.addLimitsToYAML(cfg, labelLimitKey, sc.Spec.LabelLimit, enforcedLabelLimit)
+	cfg = enforcer.addLimitsToYAML(cfg, labelNameLengthLimitKey, sc.Spec.LabelNameLengthLimit, enforcedLabelNameLengthLimit)
+	cfg = enforcer.addLimitsToYAML(cfg, labelValueLengthLimitKey, sc.Spec.LabelValueLengthLimit, enforcedLabelValueLengthLimit)
+	cfg = enforcer.addLimitsToYAML(cfg, keepDroppedTargetsKey, sc.Spec.KeepDroppedTargets, nil)
+
+	// Since BodySizeLimit is defined only in PrometheusCRD
+	cfg = enforcer.addBodySizeLimitsToYAML(cfg, enforcedBodySizeLimit)
+
+	// Generate static_configs section.
+	if len(sc.Spec.StaticConfigs) > 0 {
+		var staticConfigs []yaml.MapSlice
+		for _, c := range sc.Spec.StaticConfigs {
+			staticConfig := yaml.MapSlice{
+				{Key: "targets", Value: c.Targets},
+			}
+			if len(c.Labels) > 0 {
+				staticConfig = append(staticConfig, yaml.MapItem{Key: "labels", Value: stringMapToMapSlice(c.Labels)})
+			}
+			staticConfigs = append(staticConfigs, staticConfig)
+		}
+		cfg = append(cfg, yaml.MapItem{Key: "static_configs", Value: staticConfigs})
+	}
+
+	// Generate file_sd_configs section.
+	if len(sc.Spec.FileSDConfigs) > 0 {
+		var fileSDConfigs []yaml.MapSlice
+		for _, c := range sc.Spec.FileSDConfigs {
+			fileSDConfig := yaml.MapSlice{
+				{Key: "files", Value: c.Files},
+			}
+			if c.RefreshInterval != "" {
+				fileSDConfig = append(fileSDConfig, yaml.MapItem{Key: "refresh_interval", Value: c.RefreshInterval})
+			}
+			fileSDConfigs = append(fileSDConfigs, fileSDConfig)
+		}
+		cfg = append(cfg, yaml.MapItem{Key: "file_sd_configs", Value: fileSDConfigs})
+	}
+
+	// Generate http_sd_configs section.
+	if len(sc.Spec.HTTPSDConfigs) > 0 {
+		if version.LT(semver.MustParse("2.28.0")) {
+			level.Warn(logger).Log("msg", "ignoring httpSDConfigs not supported by Prometheus", "version", version, "minimum_version", "2.28.0")
+		} else {
+			var httpSDConfigs []yaml.MapSlice
+			for i, c := range sc.Spec.HTTPSDConfigs {
+				httpSDConfig := yaml.MapSlice{
+					{Key: "url", Value: c.URL},
+				}
+				if c.RefreshInterval != "" {
+					httpSDConfig = append(httpSDConfig, yaml.MapItem{Key: "refresh_interval", Value: c.RefreshInterval})
+				}
+
+				if c.BasicAuth != nil {
+					if s, ok := store.BasicAuthAssets[fmt.Sprintf("scrapeConfig/%s/%s/httpsdconfig/%d", sc.Namespace, sc.Name, i)]; ok {
+						httpSDConfig = append(httpSDConfig, yaml.MapItem{
+							Key: "basic_auth", Value: yaml.MapSlice{
+								{Key: "username", Value: s.Username},
+								{Key: "password", Value: s.Password},
+							},
+						})
+					}
+				}
+
+				httpSDConfig = addSafeAuthorizationToYaml(httpSDConfig, version, fmt.Sprintf("scrapeConfig/auth/%s/%s/httpsdconfig/%d", sc.Namespace, sc.Name, i), store, c.Authorization, logger)
+
+				if c.TLSConfig != nil {
+					httpSDConfig = addSafeTLStoYaml(httpSDConfig, sc.Namespace, *c.TLSConfig)
+				}
+
+				httpSDConfigs = append(httpSDConfigs, httpSDConfig)
+			}
+			cfg = append(cfg, yaml.MapItem{Key: "http_sd_configs", Value: httpSDConfigs})
+		}
+	}
+
+	// Generate dns_sd_configs section.
+	if len(sc.Spec.DNSSDConfigs) > 0 {
+		var dnsSDConfigs []yaml.MapSlice
+		for _, c := range sc.Spec.DNSSDConfigs {
+			dnsSDConfig := yaml.MapSlice{
+				{Key: "names", Value: c.Names},
+			}
+			if c.RefreshInterval != "" {
+				dnsSDConfig = append(dnsSDConfig, yaml.MapItem{Key: "refresh_interval", Value: c.RefreshInterval})
+			}
+			if c.Type != "" {
+				dnsSDConfig = append(dnsSDConfig, yaml.MapItem{Key: "type", Value: c.Type})
+			}
+			if c.Port != nil {
+				dnsSDConfig = append(dnsSDConfig, yaml.MapItem{Key: "port", Value: *c.Port})
+			}
+			dnsSDConfigs = append(dnsSDConfigs, dnsSDConfig)
+		}
+		cfg = append(cfg, yaml.MapItem{Key: "dns_sd_configs", Value: dnsSDConfigs})
+	}
+
+	// Generate kubernetes_sd_configs section. The discovery is restricted to
+	// the namespace of the ScrapeConfig object except for the nodes which
+	// aren't namespaced.
+	if len(sc.Spec.KubernetesSDConfigs) > 0 {
+		var k8sSDConfigs []yaml.MapSlice
+		for _, c := range sc.Spec.KubernetesSDConfigs {
+			namespaces := []string{sc.Namespace}
+			if c.Role == kubernetesSDRoleNode {
+				namespaces = nil
+			}
+			k8sSDConfig := cg.generateK8SSDConfig(version, namespaces, apiserverConfig, store, c.Role, nil)
+			k8sSDConfigs = append(k8sSDConfigs, k8sSDConfig.Value.([]yaml.MapSlice)...)
+		}
+		cfg = append(cfg, yaml.MapItem{Key: "kubernetes_sd_configs", Value: k8sSDConfigs})
+	}
+
+	relabelings := initRelabelings()
+
+	if sc.Spec.JobName != "" {
+		relabelings = append(relabelings, yaml.MapSlice{
+			{Key: "target_label", Value: "job"},
+			{Key: "replacement", Value: sc.Spec.JobName},
+		})
+	}
+
+	rcg := &relabelConfigGenerator{
+		obj:                    sc,
+		enforcedNamespaceLabel: enforcedNamespaceLabel,
+	}
+	relabelings = append(relabelings, rcg.generate(sc.Spec.RelabelConfigs)...)
+	relabelings = generateAddressShardingRelabelingRules(relabelings, shards)
+	cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
+
+	cfg = append(cfg, yaml.MapItem{Key: "metric_relabel_configs", Value: rcg.generate(sc.Spec.MetricRelabelConfigs)})
 
 	return cfg
 }
@@ -1022,6 +1560,8 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 	i int,
 	apiserverConfig *v1.APIServerConfig,
 	store *assets.Store,
+	scrapeClass *v1.ScrapeClass,
+	role string,
 	overrideHonorLabels bool,
 	overrideHonorTimestamps bool,
 	ignoreNamespaceSelectors bool,
@@ -1052,7 +1592,7 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 	}
 
 	selectedNamespaces := getNamespacesFromNamespaceSelector(&m.Spec.NamespaceSelector, m.Namespace, ignoreNamespaceSelectors)
-	cfg = append(cfg, cg.generateK8SSDConfig(version, selectedNamespaces, apiserverConfig, store, kubernetesSDRoleEndpoint))
+	cfg = append(cfg, cg.generateK8SSDConfig(version, selectedNamespaces, apiserverConfig, store, role, m.Spec.AttachMetadata))
 
 	if ep.Interval != "" {
 		cfg = append(cfg, yaml.MapItem{Key: "scrape_interval", Value: ep.Interval})
@@ -1075,8 +1615,14 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 
 	assetKey := fmt.Sprintf("serviceMonitor/%s/%s/%d", m.Namespace, m.Name, i)
 	cfg = addOAuth2ToYaml(cfg, version, ep.OAuth2, store.OAuth2Assets, assetKey)
+	cfg = addProxyConfigToYaml(cfg, version, assetKey, store, ep.ProxyConfig, logger)
+	cfg = addHTTPClientOptionsToYaml(cfg, version, ep.FollowRedirects, ep.EnableHttp2, logger)
 
-	cfg = addTLStoYaml(cfg, m.Namespace, ep.TLSConfig)
+	if ep.TLSConfig != nil {
+		cfg = addTLStoYaml(cfg, m.Namespace, ep.TLSConfig)
+	} else if scrapeClass != nil {
+		cfg = addTLStoYaml(cfg, m.Namespace, scrapeClass.TLSConfig)
+	}
 
 	if ep.BearerTokenFile != "" {
 		cfg = append(cfg, yaml.MapItem{Key: "bearer_token_file", Value: ep.BearerTokenFile})
@@ -1101,6 +1647,10 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 
 	cfg = addSafeAuthorizationToYaml(cfg, version, fmt.Sprintf("serviceMonitor/auth/%s/%s/%d", m.Namespace, m.Name, i), store, ep.Authorization, logger)
 
+	if ep.BearerTokenFile == "" && ep.BearerTokenSecret.Name == "" && ep.BasicAuth == nil && ep.OAuth2 == nil && ep.Authorization == nil {
+		cfg = addScrapeClassAuthorizationToYaml(cfg, version, store, scrapeClass, logger)
+	}
+
 	relabelings := initRelabelings()
 
 	// Filter targets by services selected by the monitor.
@@ -1154,7 +1704,7 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 	if ep.Port != "" {
 		relabelings = append(relabelings, yaml.MapSlice{
 			{Key: "action", Value: "keep"},
-			{Key: "source_labels", Value: []string{"__meta_kubernetes_endpoint_port_name"}},
+			{Key: "source_labels", Value: []string{endpointsMetaLabel(role, "port_name")}},
 			{Key: "regex", Value: ep.Port},
 		})
 	} else if ep.TargetPort != nil {
@@ -1176,14 +1726,14 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 	// Relabel namespace and pod and service labels into proper labels.
 	relabelings = append(relabelings, []yaml.MapSlice{
 		{ // Relabel node labels for pre v2.3 meta labels
-			{Key: "source_labels", Value: []string{"__meta_kubernetes_endpoint_address_target_kind", "__meta_kubernetes_endpoint_address_target_name"}},
+			{Key: "source_labels", Value: []string{endpointsMetaLabel(role, "address_target_kind"), endpointsMetaLabel(role, "address_target_name")}},
 			{Key: "separator", Value: ";"},
 			{Key: "regex", Value: "Node;(.*)"},
 			{Key: "replacement", Value: "${1}"},
 			{Key: "target_label", Value: "node"},
 		},
 		{ // Relabel pod labels for >=v2.3 meta labels
-			{Key: "source_labels", Value: []string{"__meta_kubernetes_endpoint_address_target_kind", "__meta_kubernetes_endpoint_address_target_name"}},
+			{Key: "source_labels", Value: []string{endpointsMetaLabel(role, "address_target_kind"), endpointsMetaLabel(role, "address_target_name")}},
 			{Key: "separator", Value: ";"},
 			{Key: "regex", Value: "Pod;(.*)"},
 			{Key: "replacement", Value: "${1}"},
@@ -1262,7 +1812,7 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 		obj:                    m,
 		enforcedNamespaceLabel: enforcedNamespaceLabel,
 	}
-	relabelings = append(relabelings, rcg.generate(ep.RelabelConfigs)...)
+	relabelings = append(relabelings, rcg.generate(scrapeClassRelabelings(scrapeClass, ep.RelabelConfigs))...)
 
 	relabelings = generateAddressShardingRelabelingRules(relabelings, shards)
 	cfg = append(cfg, yaml.MapItem{Key: "relabel_configs", Value: relabelings})
@@ -1276,11 +1826,19 @@ func (cg *ConfigGenerator) generateServiceMonitorConfig(
 	cfg = enforcer.addLimitsToYAML(cfg, labelLimitKey, m.Spec.LabelLimit, enforcedLabelLimit)
 	cfg = enforcer.addLimitsToYAML(cfg, labelNameLengthLimitKey, m.Spec.LabelNameLengthLimit, enforcedLabelNameLengthLimit)
 	cfg = enforcer.addLimitsToYAML(cfg, labelValueLengthLimitKey, m.Spec.LabelValueLengthLimit, enforcedLabelValueLengthLimit)
+	cfg = enforcer.addLimitsToYAML(cfg, keepDroppedTargetsKey, m.Spec.KeepDroppedTargets, nil)
 
 	// Since BodySizeLimit is defined only in PrometheusCRD
 	cfg = enforcer.addBodySizeLimitsToYAML(cfg, enforcedBodySizeLimit)
 
-	cfg = append(cfg, yaml.MapItem{Key: "metric_relabel_configs", Value: rcg.generate(ep.MetricRelabelConfigs)})
+	cfg = enforcer.addLimitsToYAML(cfg, nativeHistogramBucketLimitKey, ep.NativeHistogramBucketLimit, nil)
+	if ep.ScrapeNativeHistograms != nil {
+		cfg = scrapeNativeHistograms(cfg, version, ep.ScrapeNativeHistograms, logger)
+	} else {
+		cfg = addScrapeProtocols(cfg, version, m.Spec.ScrapeProtocols, logger)
+	}
+
+	cfg = append(cfg, yaml.MapItem{Key: "metric_relabel_configs", Value: rcg.generate(scrapeClassMetricRelabelings(scrapeClass, ep.MetricRelabelConfigs))})
 
 	return cfg
 }
@@ -1295,6 +1853,27 @@ func getLimit(user uint64, enforced *uint64) uint64 {
 	return user
 }
 
+// limitsClamped returns true if at least one of the scrape limits set by a
+// monitor is lowered to the limit enforced by the Prometheus object.
+func limitsClamped(p *v1.Prometheus, sampleLimit, targetLimit, labelLimit, labelNameLengthLimit, labelValueLengthLimit uint64) bool {
+	for _, l := range []struct {
+		user     uint64
+		enforced *uint64
+	}{
+		{user: sampleLimit, enforced: p.Spec.EnforcedSampleLimit},
+		{user: targetLimit, enforced: p.Spec.EnforcedTargetLimit},
+		{user: labelLimit, enforced: p.Spec.EnforcedLabelLimit},
+		{user: labelNameLengthLimit, enforced: p.Spec.EnforcedLabelNameLengthLimit},
+		{user: labelValueLengthLimit, enforced: p.Spec.EnforcedLabelValueLengthLimit},
+	} {
+		if l.user != 0 && getLimit(l.user, l.enforced) < l.user {
+			return true
+		}
+	}
+
+	return false
+}
+
 func generateAddressShardingRelabelingRules(relabelings []yaml.MapSlice, shards int32) []yaml.MapSlice {
 	return append(relabelings, yaml.MapSlice{
 		{Key: "source_labels", Value: []string{"__address__"}},
@@ -1308,6 +1887,21 @@ func generateAddressShardingRelabelingRules(relabelings []yaml.MapSlice, shards
 	})
 }
 
+// GenerateRelabelConfigs returns the YAML representation of the relabel
+// configurations. The configurations should have been validated with
+// ValidateRelabelConfigs.
+func GenerateRelabelConfigs(rcs []*v1.RelabelConfig) ([]byte, error) {
+	cfg := make([]yaml.MapSlice, 0, len(rcs))
+	for _, c := range rcs {
+		if c == nil {
+			continue
+		}
+		cfg = append(cfg, generateRelabelConfig(c))
+	}
+
+	return yaml.Marshal(cfg)
+}
+
 func generateRelabelConfig(c *v1.RelabelConfig) yaml.MapSlice {
 	relabeling := yaml.MapSlice{}
 
@@ -1355,7 +1949,33 @@ func getNamespacesFromNamespaceSelector(nsel *v1.NamespaceSelector, namespace st
 	return nsel.MatchNames
 }
 
-func (cg *ConfigGenerator) generateK8SSDConfig(version semver.Version, namespaces []string, apiserverConfig *v1.APIServerConfig, store *assets.Store, role string) yaml.MapItem {
+// endpointsDiscoveryRole returns the Kubernetes service discovery role used
+// for the ServiceMonitors and the Alertmanager endpoints.
+func (cg *ConfigGenerator) endpointsDiscoveryRole(version semver.Version, role *v1.ServiceDiscoveryRole) string {
+	if role == nil || *role != v1.EndpointSliceRole {
+		return kubernetesSDRoleEndpoint
+	}
+
+	if version.LT(semver.MustParse("2.21.0")) {
+		level.Warn(cg.logger).Log("msg", "found EndpointSlice service discovery role but prometheus version is < 2.21.0, using the Endpoints role",
+			"version", version, "minimum_version", "2.21.0")
+		return kubernetesSDRoleEndpoint
+	}
+
+	return kubernetesSDRoleEndpointSlice
+}
+
+// endpointsMetaLabel returns the name of the meta label with the given suffix
+// for the endpoints or endpointslice discovery role.
+func endpointsMetaLabel(role, suffix string) string {
+	if role == kubernetesSDRoleEndpointSlice {
+		return "__meta_kubernetes_endpointslice_" + suffix
+	}
+
+	return "__meta_kubernetes_endpoint_" + suffix
+}
+
+func (cg *ConfigGenerator) generateK8SSDConfig(version semver.Version, namespaces []string, apiserverConfig *v1.APIServerConfig, store *assets.Store, role string, attachMetadata *v1.AttachMetadata) yaml.MapItem {
 	k8sSDConfig := yaml.MapSlice{
 		{
 			Key:   "role",
@@ -1406,6 +2026,27 @@ func (cg *ConfigGenerator) generateK8SSDConfig(version semver.Version, namespace
 		k8sSDConfig = addTLStoYaml(k8sSDConfig, "", apiserverConfig.TLSConfig)
 	}
 
+	if attachMetadata != nil {
+		// The endpoints and endpointslice roles support attaching the node
+		// metadata since v2.37.0, the pod role since v2.35.0.
+		minVersion := "2.35.0"
+		if role == kubernetesSDRoleEndpoint || role == kubernetesSDRoleEndpointSlice {
+			minVersion = "2.37.0"
+		}
+
+		if version.LT(semver.MustParse(minVersion)) {
+			level.Warn(cg.logger).Log("msg", "found attachMetadata setting but prometheus version is too old, ignoring",
+				"version", version, "minimum_version", minVersion, "role", role)
+		} else {
+			k8sSDConfig = append(k8sSDConfig, yaml.MapItem{
+				Key: "attach_metadata",
+				Value: yaml.MapSlice{
+					{Key: "node", Value: attachMetadata.Node},
+				},
+			})
+		}
+	}
+
 	return yaml.MapItem{
 		Key: "kubernetes_sd_configs",
 		Value: []yaml.MapSlice{
@@ -1414,7 +2055,7 @@ func (cg *ConfigGenerator) generateK8SSDConfig(version semver.Version, namespace
 	}
 }
 
-func (cg *ConfigGenerator) generateAlertmanagerConfig(version semver.Version, alerting *v1.AlertingSpec, apiserverConfig *v1.APIServerConfig, store *assets.Store) []yaml.MapSlice {
+func (cg *ConfigGenerator) generateAlertmanagerConfig(version semver.Version, alerting *v1.AlertingSpec, apiserverConfig *v1.APIServerConfig, store *assets.Store, role string) []yaml.MapSlice {
 	var alertmanagerConfigs []yaml.MapSlice
 	if alerting == nil {
 		return alertmanagerConfigs
@@ -1441,7 +2082,7 @@ func (cg *ConfigGenerator) generateAlertmanagerConfig(version semver.Version, al
 		// config as well, make sure to path the right namespace here.
 		cfg = addTLStoYaml(cfg, "", am.TLSConfig)
 
-		cfg = append(cfg, cg.generateK8SSDConfig(version, []string{am.Namespace}, apiserverConfig, store, kubernetesSDRoleEndpoint))
+		cfg = append(cfg, cg.generateK8SSDConfig(version, []string{am.Namespace}, apiserverConfig, store, role, nil))
 
 		if am.BearerTokenFile != "" {
 			cfg = append(cfg, yaml.MapItem{Key: "bearer_token_file", Value: am.BearerTokenFile})
@@ -1466,7 +2107,7 @@ func (cg *ConfigGenerator) generateAlertmanagerConfig(version semver.Version, al
 		if am.Port.StrVal != "" {
 			relabelings = append(relabelings, yaml.MapSlice{
 				{Key: "action", Value: "keep"},
-				{Key: "source_labels", Value: []string{"__meta_kubernetes_endpoint_port_name"}},
+				{Key: "source_labels", Value: []string{endpointsMetaLabel(role, "port_name")}},
 				{Key: "regex", Value: am.Port.String()},
 			})
 		} else if am.Port.IntVal != 0 {
@@ -1557,6 +2198,14 @@ func (cg *ConfigGenerator) generateRemoteReadConfig(
 			cfg = append(cfg, yaml.MapItem{Key: "read_recent", Value: spec.ReadRecent})
 		}
 
+		if spec.FilterExternalLabels != nil {
+			if version.GTE(semver.MustParse("2.34.0")) {
+				cfg = append(cfg, yaml.MapItem{Key: "filter_external_labels", Value: *spec.FilterExternalLabels})
+			} else {
+				level.Warn(cg.logger).Log("msg", "ignoring filterExternalLabels not supported by Prometheus", "version", version, "minimum_version", "2.34.0")
+			}
+		}
+
 		if spec.BasicAuth != nil {
 			if s, ok := store.BasicAuthAssets[fmt.Sprintf("remoteRead/%d", i)]; ok {
 				cfg = append(cfg, yaml.MapItem{
@@ -1629,15 +2278,75 @@ func addOAuth2ToYaml(
 	return append(cfg, yaml.MapItem{Key: "oauth2", Value: oauth2Cfg})
 }
 
+func (cg *ConfigGenerator) addAzureADToYaml(
+	cfg yaml.MapSlice,
+	version semver.Version,
+	azureAD *v1.AzureAD,
+	store *assets.Store,
+	assetKey string,
+) yaml.MapSlice {
+	if version.LT(semver.MustParse("2.45.0")) {
+		level.Warn(cg.logger).Log("msg", "ignoring azureAd not supported by Prometheus", "version", version, "minimum_version", "2.45.0")
+		return cfg
+	}
+
+	azureADCfg := yaml.MapSlice{}
+	if azureAD.Cloud != nil {
+		azureADCfg = append(azureADCfg, yaml.MapItem{Key: "cloud", Value: *azureAD.Cloud})
+	}
+
+	if azureAD.ManagedIdentity != nil {
+		azureADCfg = append(azureADCfg, yaml.MapItem{
+			Key: "managed_identity",
+			Value: yaml.MapSlice{
+				{Key: "client_id", Value: azureAD.ManagedIdentity.ClientID},
+			},
+		})
+	}
+
+	if azureAD.OAuth != nil {
+		if version.LT(semver.MustParse("2.48.0")) {
+			level.Warn(cg.logger).Log("msg", "ignoring azureAd oauth not supported by Prometheus", "version", version, "minimum_version", "2.48.0")
+			return cfg
+		}
+
+		azureADCfg = append(azureADCfg, yaml.MapItem{
+			Key: "oauth",
+			Value: yaml.MapSlice{
+				{Key: "client_id", Value: azureAD.OAuth.ClientID},
+				{Key: "client_secret", Value: store.TokenAssets[assetKey]},
+				{Key: "tenant_id", Value: azureAD.OAuth.TenantID},
+			},
+		})
+	}
+
+	return append(cfg, yaml.MapItem{Key: "azuread", Value: azureADCfg})
+}
+
+// GenerateRemoteWriteConfig returns a configuration file containing only the
+// remote_write section generated from the specs. It is used by the components
+// embedding the Prometheus remote write client such as the Thanos ruler. The
+// credentials must have been loaded with AddRemoteWriteAssets and the TLS
+// assets are expected in TLSAssetsDir.
+func (cg *ConfigGenerator) GenerateRemoteWriteConfig(
+	version semver.Version,
+	namespace string,
+	specs []v1.RemoteWriteSpec,
+	store *assets.Store,
+) ([]byte, error) {
+	return yaml.Marshal(yaml.MapSlice{cg.generateRemoteWriteConfig(version, namespace, specs, store)})
+}
+
 func (cg *ConfigGenerator) generateRemoteWriteConfig(
 	version semver.Version,
-	p *v1.Prometheus,
+	namespace string,
+	specs []v1.RemoteWriteSpec,
 	store *assets.Store,
 ) yaml.MapItem {
 
 	cfgs := []yaml.MapSlice{}
 
-	for i, spec := range p.Spec.RemoteWrite {
+	for i, spec := range specs {
 		//defaults
 		if spec.RemoteTimeout == "" {
 			spec.RemoteTimeout = "30s"
@@ -1719,7 +2428,7 @@ func (cg *ConfigGenerator) generateRemoteWriteConfig(
 
 		cfg = addOAuth2ToYaml(cfg, version, spec.OAuth2, store.OAuth2Assets, fmt.Sprintf("remoteWrite/%d", i))
 
-		cfg = addTLStoYaml(cfg, p.ObjectMeta.Namespace, spec.TLSConfig)
+		cfg = addTLStoYaml(cfg, namespace, spec.TLSConfig)
 
 		cfg = addAuthorizationToYaml(cfg, version, fmt.Sprintf("remoteWrite/auth/%d", i), store, spec.Authorization, cg.logger)
 
@@ -1727,6 +2436,10 @@ func (cg *ConfigGenerator) generateRemoteWriteConfig(
 			cfg = append(cfg, yaml.MapItem{Key: "proxy_url", Value: spec.ProxyURL})
 		}
 
+		cfg = addProxyConfigToYaml(cfg, version, fmt.Sprintf("remoteWrite/%d", i), store, spec.ProxyConfig, cg.logger)
+
+		cfg = addHTTPClientOptionsToYaml(cfg, version, spec.FollowRedirects, spec.EnableHttp2, cg.logger)
+
 		if spec.Sigv4 != nil && version.GTE(semver.MustParse("2.26.0")) {
 			sigV4 := yaml.MapSlice{}
 			if spec.Sigv4.Region != "" {
@@ -1748,6 +2461,10 @@ func (cg *ConfigGenerator) generateRemoteWriteConfig(
 			cfg = append(cfg, yaml.MapItem{Key: "sigv4", Value: sigV4})
 		}
 
+		if spec.AzureAD != nil {
+			cfg = cg.addAzureADToYaml(cfg, version, spec.AzureAD, store, fmt.Sprintf("remoteWrite/azuread/%d", i))
+		}
+
 		if spec.QueueConfig != nil {
 			queueConfig := yaml.MapSlice{}
 
@@ -1839,6 +2556,16 @@ var (
 		prometheusField: "label_value_length_limit",
 		minVersion:      "2.27.0",
 	}
+	keepDroppedTargetsKey = limitKey{
+		specField:       "keepDroppedTargets",
+		prometheusField: "keep_dropped_targets",
+		minVersion:      "2.47.0",
+	}
+	nativeHistogramBucketLimitKey = limitKey{
+		specField:       "nativeHistogramBucketLimit",
+		prometheusField: "native_histogram_bucket_limit",
+		minVersion:      "2.45.0",
+	}
 )
 
 type limitEnforcer struct {
@@ -28,6 +28,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/kylelemons/godebug/pretty"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	"gopkg.in/yaml.v2"
@@ -211,6 +212,7 @@ alerting:
 			map[string]*monitoringv1.ServiceMonitor{},
 			nil,
 			nil,
+			nil,
 			&assets.Store{},
 			nil,
 			nil,
@@ -334,7 +336,7 @@ func TestNamespaceSetCorrectly(t *testing.T) {
 
 	for _, tc := range testcases {
 		selectedNamespaces := getNamespacesFromNamespaceSelector(&tc.ServiceMonitor.Spec.NamespaceSelector, tc.ServiceMonitor.Namespace, tc.IgnoreNamespaceSelectors)
-		c := cg.generateK8SSDConfig(semver.Version{}, selectedNamespaces, nil, nil, kubernetesSDRoleEndpoint)
+		c := cg.generateK8SSDConfig(semver.Version{}, selectedNamespaces, nil, nil, kubernetesSDRoleEndpoint, nil)
 		s, err := yaml.Marshal(yaml.MapSlice{c})
 		if err != nil {
 			t.Fatal(err)
@@ -363,7 +365,7 @@ func TestNamespaceSetCorrectlyForPodMonitor(t *testing.T) {
 
 	cg := &ConfigGenerator{}
 	selectedNamespaces := getNamespacesFromNamespaceSelector(&pm.Spec.NamespaceSelector, pm.Namespace, false)
-	c := cg.generateK8SSDConfig(semver.Version{}, selectedNamespaces, nil, nil, kubernetesSDRolePod)
+	c := cg.generateK8SSDConfig(semver.Version{}, selectedNamespaces, nil, nil, kubernetesSDRolePod, nil)
 	s, err := yaml.Marshal(yaml.MapSlice{c})
 	if err != nil {
 		t.Fatal(err)
@@ -382,6 +384,79 @@ func TestNamespaceSetCorrectlyForPodMonitor(t *testing.T) {
 	}
 }
 
+func TestK8SSDConfigAttachMetadata(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		version        string
+		role           string
+		attachMetadata *monitoringv1.AttachMetadata
+		expected       string
+	}{
+		{
+			name:    "not set",
+			version: "v2.37.0",
+			role:    kubernetesSDRoleEndpoint,
+			expected: `kubernetes_sd_configs:
+- role: endpoints
+`,
+		},
+		{
+			name:           "endpoints role",
+			version:        "v2.37.0",
+			role:           kubernetesSDRoleEndpoint,
+			attachMetadata: &monitoringv1.AttachMetadata{Node: true},
+			expected: `kubernetes_sd_configs:
+- role: endpoints
+  attach_metadata:
+    node: true
+`,
+		},
+		{
+			name:           "endpoints role with unsupported version",
+			version:        "v2.36.0",
+			role:           kubernetesSDRoleEndpoint,
+			attachMetadata: &monitoringv1.AttachMetadata{Node: true},
+			expected: `kubernetes_sd_configs:
+- role: endpoints
+`,
+		},
+		{
+			name:           "pod role",
+			version:        "v2.35.0",
+			role:           kubernetesSDRolePod,
+			attachMetadata: &monitoringv1.AttachMetadata{Node: true},
+			expected: `kubernetes_sd_configs:
+- role: pod
+  attach_metadata:
+    node: true
+`,
+		},
+		{
+			name:           "pod role with unsupported version",
+			version:        "v2.34.0",
+			role:           kubernetesSDRolePod,
+			attachMetadata: &monitoringv1.AttachMetadata{Node: true},
+			expected: `kubernetes_sd_configs:
+- role: pod
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
+
+			c := cg.generateK8SSDConfig(semver.MustParse(strings.TrimPrefix(tc.version, "v")), nil, nil, nil, tc.role, tc.attachMetadata)
+			s, err := yaml.Marshal(yaml.MapSlice{c})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.expected != string(s) {
+				t.Fatalf("Unexpected result.\n\nGot:\n\n%s\n\nExpected:\n\n%s\n\n", string(s), tc.expected)
+			}
+		})
+	}
+}
+
 func TestProbeStaticTargetsConfigGeneration(t *testing.T) {
 	cg := &ConfigGenerator{}
 	cfg, err := cg.GenerateConfig(
@@ -438,6 +513,7 @@ func TestProbeStaticTargetsConfigGeneration(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -557,6 +633,7 @@ func TestProbeStaticTargetsConfigGenerationWithLabelEnforce(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -668,6 +745,7 @@ func TestProbeStaticTargetsConfigGenerationWithJobName(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -728,6 +806,129 @@ alerting:
 	}
 }
 
+func TestProbeStaticTargetsFromConfigGeneration(t *testing.T) {
+	probe := &monitoringv1.Probe{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testprobe1",
+			Namespace: "default",
+			Labels: map[string]string{
+				"group": "group1",
+			},
+		},
+		Spec: monitoringv1.ProbeSpec{
+			JobName: "blackbox",
+			ProberSpec: monitoringv1.ProberSpec{
+				Scheme: "http",
+				URL:    "blackbox.exporter.io",
+				Path:   "/probe",
+			},
+			Module: "http_2xx",
+			Targets: monitoringv1.ProbeTargets{
+				StaticConfig: &monitoringv1.ProbeTargetStaticConfig{
+					Targets: []string{
+						"prometheus.io",
+					},
+					StaticFrom: &monitoringv1.SecretOrConfigMap{
+						ConfigMap: &v1.ConfigMapKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "targets"},
+							Key:                  "targets.txt",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cg := &ConfigGenerator{}
+	cfg, err := cg.GenerateConfig(
+		&monitoringv1.Prometheus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+			Spec: monitoringv1.PrometheusSpec{
+				ProbeSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"group": "group1",
+					},
+				},
+			},
+		},
+		nil,
+		nil,
+		map[string]*monitoringv1.Probe{
+			"probe1": probe,
+		},
+		nil,
+		&assets.Store{
+			ProbeTargetsAssets: map[string][]string{
+				"probe/default/testprobe1": {"promcon.io", "example.com"},
+			},
+		},
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs:
+- job_name: probe/default/testprobe1
+  honor_timestamps: true
+  metrics_path: /probe
+  scheme: http
+  params:
+    module:
+    - http_2xx
+  static_configs:
+  - targets:
+    - prometheus.io
+    - promcon.io
+    - example.com
+    labels:
+      namespace: default
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - target_label: job
+    replacement: blackbox
+  - source_labels:
+    - __address__
+    target_label: __param_target
+  - source_labels:
+    - __param_target
+    target_label: instance
+  - target_label: __address__
+    replacement: blackbox.exporter.io
+  metric_relabel_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+`
+
+	result := string(cfg)
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Fatalf("Unexpected result got(-) want(+)\n%s\n", diff)
+	}
+
+	if len(probe.Spec.Targets.StaticConfig.Targets) != 1 {
+		t.Fatalf("expected the static targets of the Probe object to be unchanged, got %v", probe.Spec.Targets.StaticConfig.Targets)
+	}
+}
+
 func TestProbeStaticTargetsConfigGenerationWithoutModule(t *testing.T) {
 	cg := &ConfigGenerator{}
 	cfg, err := cg.GenerateConfig(
@@ -773,6 +974,7 @@ func TestProbeStaticTargetsConfigGenerationWithoutModule(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -886,6 +1088,7 @@ func TestProbeIngressSDConfigGeneration(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1016,6 +1219,7 @@ func TestProbeIngressSDConfigGenerationWithLabelEnforce(t *testing.T) {
 				},
 			},
 		},
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1166,6 +1370,7 @@ func TestK8SSDConfigGeneration(t *testing.T) {
 			tc.apiserverConfig,
 			tc.store,
 			kubernetesSDRoleEndpoint,
+			nil,
 		)
 		s, err := yaml.Marshal(yaml.MapSlice{c})
 		if err != nil {
@@ -1203,6 +1408,7 @@ func TestAlertmanagerBearerToken(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1281,6 +1487,7 @@ func TestAlertmanagerAPIVersion(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1360,6 +1567,7 @@ func TestAlertmanagerTimeoutConfig(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1433,6 +1641,7 @@ func TestAdditionalScrapeConfigs(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 			&assets.Store{},
 			[]byte(`- job_name: prometheus
   scrape_interval: 15s
@@ -1620,6 +1829,7 @@ func TestAdditionalAlertRelabelConfigs(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		[]byte(`- action: drop
@@ -1732,6 +1942,7 @@ func TestNoEnforcedNamespaceLabelServiceMonitor(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1888,6 +2099,7 @@ func TestEnforcedNamespaceLabelPodMonitor(t *testing.T) {
 			},
 		},
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -1918,6 +2130,10 @@ scrape_configs:
   - source_labels:
     - job
     target_label: __tmp_prometheus_job_name
+  - action: drop
+    source_labels:
+    - __meta_kubernetes_pod_phase
+    regex: (Failed|Succeeded)
   - action: keep
     source_labels:
     - __meta_kubernetes_pod_container_port_name
@@ -2034,6 +2250,7 @@ func TestEnforcedNamespaceLabelServiceMonitor(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2165,6 +2382,7 @@ func TestAdditionalAlertmanagers(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2260,6 +2478,7 @@ func TestSettingHonorTimestampsInServiceMonitor(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2402,6 +2621,7 @@ func TestSettingHonorTimestampsInPodMonitor(t *testing.T) {
 			},
 		},
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2433,6 +2653,10 @@ scrape_configs:
   - source_labels:
     - job
     target_label: __tmp_prometheus_job_name
+  - action: drop
+    source_labels:
+    - __meta_kubernetes_pod_phase
+    regex: (Failed|Succeeded)
   - action: keep
     source_labels:
     - __meta_kubernetes_pod_container_port_name
@@ -2526,6 +2750,7 @@ func TestHonorTimestampsOverriding(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2667,6 +2892,7 @@ func TestSettingHonorLabels(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2808,6 +3034,7 @@ func TestHonorLabelsOverriding(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -2948,6 +3175,7 @@ func TestTargetLabels(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -3234,6 +3462,7 @@ oauth2:
 				tt.sMons,
 				tt.pMons,
 				tt.probes,
+				nil,
 				&assets.Store{
 					BasicAuthAssets: map[string]assets.BasicAuthCredentials{},
 					OAuth2Assets:    tt.oauth2Credentials,
@@ -3295,6 +3524,7 @@ func TestPodTargetLabels(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -3434,6 +3664,7 @@ func TestPodTargetLabelsFromPodMonitor(t *testing.T) {
 			},
 		},
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -3464,6 +3695,10 @@ scrape_configs:
   - source_labels:
     - job
     target_label: __tmp_prometheus_job_name
+  - action: drop
+    source_labels:
+    - __meta_kubernetes_pod_phase
+    regex: (Failed|Succeeded)
   - action: keep
     source_labels:
     - __meta_kubernetes_pod_container_port_name
@@ -3516,49 +3751,7 @@ alerting:
 	}
 }
 
-func TestEmptyEndointPorts(t *testing.T) {
-	cg := &ConfigGenerator{}
-	cfg, err := cg.GenerateConfig(
-		&monitoringv1.Prometheus{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test",
-				Namespace: "default",
-			},
-		},
-		map[string]*monitoringv1.ServiceMonitor{
-			"test": {
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "test",
-					Namespace: "default",
-				},
-				Spec: monitoringv1.ServiceMonitorSpec{
-					Selector: metav1.LabelSelector{
-						MatchLabels: map[string]string{
-							"foo": "bar",
-						},
-					},
-					Endpoints: []monitoringv1.Endpoint{
-						// Add a single endpoint with empty configuration.
-						{},
-					},
-				},
-			},
-		},
-		nil,
-		nil,
-		&assets.Store{},
-		nil,
-		nil,
-		nil,
-		nil,
-	)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	// If this becomes an endless sink of maintenance, then we should just
-	// change this to check that just the `bearer_token_file` is set with
-	// something like json-path.
+func TestPodMonitorFilterRunning(t *testing.T) {
 	expected := `global:
   evaluation_interval: 30s
   scrape_interval: 30s
@@ -3567,51 +3760,35 @@ func TestEmptyEndointPorts(t *testing.T) {
     prometheus_replica: $(POD_NAME)
 rule_files: []
 scrape_configs:
-- job_name: serviceMonitor/default/test/0
+- job_name: podMonitor/default/testpodmonitor1/0
   honor_labels: false
   kubernetes_sd_configs:
-  - role: endpoints
+  - role: pod
     namespaces:
       names:
       - default
+  scrape_interval: 30s
   relabel_configs:
   - source_labels:
     - job
     target_label: __tmp_prometheus_job_name
-  - action: keep
+%s  - action: keep
     source_labels:
-    - __meta_kubernetes_service_label_foo
-    regex: bar
-  - source_labels:
-    - __meta_kubernetes_endpoint_address_target_kind
-    - __meta_kubernetes_endpoint_address_target_name
-    separator: ;
-    regex: Node;(.*)
-    replacement: ${1}
-    target_label: node
-  - source_labels:
-    - __meta_kubernetes_endpoint_address_target_kind
-    - __meta_kubernetes_endpoint_address_target_name
-    separator: ;
-    regex: Pod;(.*)
-    replacement: ${1}
-    target_label: pod
+    - __meta_kubernetes_pod_container_port_name
+    regex: web
   - source_labels:
     - __meta_kubernetes_namespace
     target_label: namespace
-  - source_labels:
-    - __meta_kubernetes_service_name
-    target_label: service
-  - source_labels:
-    - __meta_kubernetes_pod_name
-    target_label: pod
   - source_labels:
     - __meta_kubernetes_pod_container_name
     target_label: container
   - source_labels:
-    - __meta_kubernetes_service_name
-    target_label: job
-    replacement: ${1}
+    - __meta_kubernetes_pod_name
+    target_label: pod
+  - target_label: job
+    replacement: default/testpodmonitor1
+  - target_label: endpoint
+    replacement: web
   - source_labels:
     - __address__
     target_label: __tmp_hash
@@ -3628,15 +3805,212 @@ alerting:
     regex: prometheus_replica
   alertmanagers: []
 `
+	filter := `  - action: drop
+    source_labels:
+    - __meta_kubernetes_pod_phase
+    regex: (Failed|Succeeded)
+`
 
-	result := string(cfg)
-	if expected != result {
-		fmt.Println(pretty.Compare(expected, result))
-		t.Fatal("expected Prometheus configuration and actual configuration do not match")
-	}
-}
-
-func generateTestConfig(version string) ([]byte, error) {
+	for _, tc := range []struct {
+		name          string
+		filterRunning *bool
+		expected      string
+	}{
+		{
+			name:     "default",
+			expected: fmt.Sprintf(expected, filter),
+		},
+		{
+			name:          "enabled",
+			filterRunning: pointer.Bool(true),
+			expected:      fmt.Sprintf(expected, filter),
+		},
+		{
+			name:          "disabled",
+			filterRunning: pointer.Bool(false),
+			expected:      fmt.Sprintf(expected, ""),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cg := &ConfigGenerator{}
+			cfg, err := cg.GenerateConfig(
+				&monitoringv1.Prometheus{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "default",
+					},
+					Spec: monitoringv1.PrometheusSpec{
+						PodMonitorSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"group": "group1",
+							},
+						},
+					},
+				},
+				nil,
+				map[string]*monitoringv1.PodMonitor{
+					"testpodmonitor1": {
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "testpodmonitor1",
+							Namespace: "default",
+							Labels: map[string]string{
+								"group": "group1",
+							},
+						},
+						Spec: monitoringv1.PodMonitorSpec{
+							PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{
+								{
+									Port:          "web",
+									Interval:      "30s",
+									FilterRunning: tc.filterRunning,
+								},
+							},
+						},
+					},
+				},
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := string(cfg)
+			if tc.expected != result {
+				t.Logf("\n%s", pretty.Compare(tc.expected, result))
+				t.Fatal("expected Prometheus configuration and actual configuration do not match")
+			}
+		})
+	}
+}
+
+func TestEmptyEndointPorts(t *testing.T) {
+	cg := &ConfigGenerator{}
+	cfg, err := cg.GenerateConfig(
+		&monitoringv1.Prometheus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+		},
+		map[string]*monitoringv1.ServiceMonitor{
+			"test": {
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.ServiceMonitorSpec{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"foo": "bar",
+						},
+					},
+					Endpoints: []monitoringv1.Endpoint{
+						// Add a single endpoint with empty configuration.
+						{},
+					},
+				},
+			},
+		},
+		nil,
+		nil,
+		nil,
+		&assets.Store{},
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// If this becomes an endless sink of maintenance, then we should just
+	// change this to check that just the `bearer_token_file` is set with
+	// something like json-path.
+	expected := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs:
+- job_name: serviceMonitor/default/test/0
+  honor_labels: false
+  kubernetes_sd_configs:
+  - role: endpoints
+    namespaces:
+      names:
+      - default
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - action: keep
+    source_labels:
+    - __meta_kubernetes_service_label_foo
+    regex: bar
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Node;(.*)
+    replacement: ${1}
+    target_label: node
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Pod;(.*)
+    replacement: ${1}
+    target_label: pod
+  - source_labels:
+    - __meta_kubernetes_namespace
+    target_label: namespace
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: service
+  - source_labels:
+    - __meta_kubernetes_pod_name
+    target_label: pod
+  - source_labels:
+    - __meta_kubernetes_pod_container_name
+    target_label: container
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: job
+    replacement: ${1}
+  - source_labels:
+    - __address__
+    target_label: __tmp_hash
+    modulus: 1
+    action: hashmod
+  - source_labels:
+    - __tmp_hash
+    regex: $(SHARD)
+    action: keep
+  metric_relabel_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+`
+
+	result := string(cfg)
+	if expected != result {
+		fmt.Println(pretty.Compare(expected, result))
+		t.Fatal("expected Prometheus configuration and actual configuration do not match")
+	}
+}
+
+func generateTestConfig(version string) ([]byte, error) {
 	cg := &ConfigGenerator{}
 	replicas := int32(1)
 	return cg.GenerateConfig(
@@ -3692,6 +4066,7 @@ func generateTestConfig(version string) ([]byte, error) {
 		makeServiceMonitors(),
 		makePodMonitors(),
 		nil,
+		nil,
 		&assets.Store{},
 		nil,
 		nil,
@@ -4321,6 +4696,7 @@ alerting:
 				},
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -4593,6 +4969,7 @@ alerting:
 				},
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -4715,11 +5092,64 @@ remote_read:
   authorization:
     type: Bearer
     credentials: secret
+`,
+		},
+		{
+			version: "v2.34.0",
+			remoteRead: monitoringv1.RemoteReadSpec{
+				URL:                  "http://example.com",
+				ReadRecent:           true,
+				FilterExternalLabels: pointer.Bool(false),
+				ProxyURL:             "http://proxy.example.com",
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+remote_read:
+- url: http://example.com
+  remote_timeout: 30s
+  read_recent: true
+  filter_external_labels: false
+  proxy_url: http://proxy.example.com
+`,
+		},
+		{
+			version: "v2.33.0",
+			remoteRead: monitoringv1.RemoteReadSpec{
+				URL:                  "http://example.com",
+				FilterExternalLabels: pointer.Bool(false),
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+remote_read:
+- url: http://example.com
+  remote_timeout: 30s
 `,
 		},
 	} {
 		t.Run(fmt.Sprintf("version=%s", tc.version), func(t *testing.T) {
-			cg := &ConfigGenerator{}
+			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
 
 			prometheus := monitoringv1.Prometheus{
 				ObjectMeta: metav1.ObjectMeta{
@@ -4742,6 +5172,7 @@ remote_read:
 				nil,
 				nil,
 				nil,
+				nil,
 				&assets.Store{
 					BasicAuthAssets: map[string]assets.BasicAuthCredentials{},
 					OAuth2Assets: map[string]assets.OAuth2Credentials{
@@ -5155,41 +5586,285 @@ remote_write:
   sigv4: {}
 `,
 		},
-	} {
-		t.Run(fmt.Sprintf("version=%s", tc.version), func(t *testing.T) {
-			cg := &ConfigGenerator{}
-
-			prometheus := monitoringv1.Prometheus{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "test",
-					Namespace: "default",
-				},
-				Spec: monitoringv1.PrometheusSpec{
-					Version: tc.version,
-					ServiceMonitorSelector: &metav1.LabelSelector{
-						MatchLabels: map[string]string{
-							"group": "group1",
-						},
-					},
-					RemoteWrite: []monitoringv1.RemoteWriteSpec{tc.remoteWrite},
-					Secrets:     []string{"sigv4-secret"},
-				},
-			}
-
-			store := &assets.Store{
-				BasicAuthAssets: map[string]assets.BasicAuthCredentials{},
-				OAuth2Assets: map[string]assets.OAuth2Credentials{
-					"remoteWrite/0": {
-						ClientID:     "client-id",
-						ClientSecret: "client-secret",
+		{
+			version: "v2.45.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL: "http://example.com",
+				AzureAD: &monitoringv1.AzureAD{
+					Cloud: pointer.String("AzurePublic"),
+					ManagedIdentity: &monitoringv1.ManagedIdentity{
+						ClientID: "client-id",
 					},
 				},
-				TokenAssets: map[string]assets.Token{
-					"remoteWrite/auth/0": assets.Token("secret"),
-				}}
-			if tc.remoteWrite.Sigv4 != nil && tc.remoteWrite.Sigv4.AccessKey != nil {
-				store.SigV4Assets = map[string]assets.SigV4Credentials{
-					"remoteWrite/0": {
+				RemoteTimeout: "1s",
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 1s
+  azuread:
+    cloud: AzurePublic
+    managed_identity:
+      client_id: client-id
+`,
+		},
+		{
+			version: "v2.44.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL: "http://example.com",
+				AzureAD: &monitoringv1.AzureAD{
+					ManagedIdentity: &monitoringv1.ManagedIdentity{
+						ClientID: "client-id",
+					},
+				},
+				RemoteTimeout: "1s",
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 1s
+`,
+		},
+		{
+			version: "v2.48.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL: "http://example.com",
+				AzureAD: &monitoringv1.AzureAD{
+					Cloud: pointer.String("AzureGovernment"),
+					OAuth: &monitoringv1.AzureOAuth{
+						ClientID: "client-id",
+						ClientSecret: v1.SecretKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{
+								Name: "azure-oauth-secret",
+							},
+							Key: "secret-key",
+						},
+						TenantID: "00000000-a12b-3cd4-e56f-000000000000",
+					},
+				},
+				RemoteTimeout: "1s",
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 1s
+  azuread:
+    cloud: AzureGovernment
+    oauth:
+      client_id: client-id
+      client_secret: azure-client-secret
+      tenant_id: 00000000-a12b-3cd4-e56f-000000000000
+`,
+		},
+		{
+			version: "v2.47.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL: "http://example.com",
+				AzureAD: &monitoringv1.AzureAD{
+					OAuth: &monitoringv1.AzureOAuth{
+						ClientID: "client-id",
+						ClientSecret: v1.SecretKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{
+								Name: "azure-oauth-secret",
+							},
+							Key: "secret-key",
+						},
+						TenantID: "00000000-a12b-3cd4-e56f-000000000000",
+					},
+				},
+				RemoteTimeout: "1s",
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 1s
+`,
+		},
+		{
+			version: "v2.43.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL:      "http://example.com",
+				ProxyURL: "http://proxy.example.com",
+				ProxyConfig: monitoringv1.ProxyConfig{
+					NoProxy: pointer.String("0.0.0.0"),
+					ProxyConnectHeader: map[string][]v1.SecretKeySelector{
+						"X-Proxy-Auth": {
+							{
+								LocalObjectReference: v1.LocalObjectReference{
+									Name: "proxy-secret",
+								},
+								Key: "token",
+							},
+						},
+					},
+				},
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+  proxy_url: http://proxy.example.com
+  no_proxy: 0.0.0.0
+  proxy_connect_header:
+    X-Proxy-Auth:
+    - proxy-token
+`,
+		},
+		{
+			version: "v2.35.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL:             "http://example.com",
+				FollowRedirects: pointer.Bool(false),
+				EnableHttp2:     pointer.Bool(false),
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+  follow_redirects: false
+  enable_http2: false
+`,
+		},
+		{
+			version: "v2.42.0",
+			remoteWrite: monitoringv1.RemoteWriteSpec{
+				URL: "http://example.com",
+				ProxyConfig: monitoringv1.ProxyConfig{
+					ProxyFromEnvironment: pointer.Bool(true),
+				},
+			},
+			expected: `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+`,
+		},
+	} {
+		t.Run(fmt.Sprintf("version=%s", tc.version), func(t *testing.T) {
+			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
+
+			prometheus := monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					Version: tc.version,
+					ServiceMonitorSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"group": "group1",
+						},
+					},
+					RemoteWrite: []monitoringv1.RemoteWriteSpec{tc.remoteWrite},
+					Secrets:     []string{"sigv4-secret"},
+				},
+			}
+
+			store := &assets.Store{
+				BasicAuthAssets: map[string]assets.BasicAuthCredentials{},
+				OAuth2Assets: map[string]assets.OAuth2Credentials{
+					"remoteWrite/0": {
+						ClientID:     "client-id",
+						ClientSecret: "client-secret",
+					},
+				},
+				TokenAssets: map[string]assets.Token{
+					"remoteWrite/auth/0":    assets.Token("secret"),
+					"remoteWrite/azuread/0": assets.Token("azure-client-secret"),
+				},
+				ProxyConnectHeaderAssets: map[string]assets.ProxyConnectHeader{
+					"remoteWrite/0": {
+						"X-Proxy-Auth": []string{"proxy-token"},
+					},
+				},
+			}
+			if tc.remoteWrite.Sigv4 != nil && tc.remoteWrite.Sigv4.AccessKey != nil {
+				store.SigV4Assets = map[string]assets.SigV4Credentials{
+					"remoteWrite/0": {
 						AccessKeyID: "access-key",
 						SecretKeyID: "secret-key",
 					},
@@ -5201,6 +5876,7 @@ remote_write:
 				nil,
 				nil,
 				nil,
+				nil,
 				store,
 				nil,
 				nil,
@@ -5219,8 +5895,1094 @@ remote_write:
 	}
 }
 
-func TestLabelLimits(t *testing.T) {
-	expectNoLimit := `global:
+func TestGenerateRemoteWriteConfig(t *testing.T) {
+	cg := &ConfigGenerator{}
+	cfg, err := cg.GenerateRemoteWriteConfig(
+		semver.MustParse("2.40.0"),
+		"ns1",
+		[]monitoringv1.RemoteWriteSpec{
+			{
+				URL: "http://example.com",
+				BasicAuth: &monitoringv1.BasicAuth{
+					Username: v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "auth"}, Key: "user"},
+					Password: v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "auth"}, Key: "password"},
+				},
+				TLSConfig: &monitoringv1.TLSConfig{
+					SafeTLSConfig: monitoringv1.SafeTLSConfig{
+						CA: monitoringv1.SecretOrConfigMap{
+							Secret: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "tls"}, Key: "ca.crt"},
+						},
+					},
+				},
+			},
+		},
+		&assets.Store{
+			BasicAuthAssets: map[string]assets.BasicAuthCredentials{
+				"remoteWrite/0": {Username: "user", Password: "pass"},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+  basic_auth:
+    username: user
+    password: pass
+  tls_config:
+    insecure_skip_verify: false
+    ca_file: /etc/prometheus/certs/secret_ns1_tls_ca.crt
+`
+	if diff := cmp.Diff(expected, string(cfg)); diff != "" {
+		t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestLimitsClamped(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		enforced    *uint64
+		sampleLimit uint64
+		labelLimit  uint64
+		expected    bool
+	}{
+		{
+			name:        "no enforced limit",
+			sampleLimit: 1000,
+			labelLimit:  1000,
+		},
+		{
+			name:     "no monitor limit",
+			enforced: uint64Ptr(100),
+		},
+		{
+			name:        "monitor limit lower than the enforced limit",
+			enforced:    uint64Ptr(100),
+			sampleLimit: 50,
+			labelLimit:  100,
+		},
+		{
+			name:        "enforced limit set to zero",
+			enforced:    uint64Ptr(0),
+			sampleLimit: 1000,
+		},
+		{
+			name:        "sample limit greater than the enforced limit",
+			enforced:    uint64Ptr(100),
+			sampleLimit: 1000,
+			expected:    true,
+		},
+		{
+			name:       "label limit greater than the enforced limit",
+			enforced:   uint64Ptr(100),
+			labelLimit: 1000,
+			expected:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					EnforcedSampleLimit: tc.enforced,
+					EnforcedLabelLimit:  tc.enforced,
+				},
+			}
+
+			if got := limitsClamped(p, tc.sampleLimit, 0, tc.labelLimit, 0, 0); got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func uint64Ptr(i uint64) *uint64 {
+	return &i
+}
+
+func TestLabelLimits(t *testing.T) {
+	expectNoLimit := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs:
+- job_name: serviceMonitor/default/testservicemonitor1/0
+  honor_labels: false
+  kubernetes_sd_configs:
+  - role: endpoints
+    namespaces:
+      names:
+      - default
+  scrape_interval: 30s
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - action: keep
+    source_labels:
+    - __meta_kubernetes_endpoint_port_name
+    regex: web
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Node;(.*)
+    replacement: ${1}
+    target_label: node
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Pod;(.*)
+    replacement: ${1}
+    target_label: pod
+  - source_labels:
+    - __meta_kubernetes_namespace
+    target_label: namespace
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: service
+  - source_labels:
+    - __meta_kubernetes_pod_name
+    target_label: pod
+  - source_labels:
+    - __meta_kubernetes_pod_container_name
+    target_label: container
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: job
+    replacement: ${1}
+  - target_label: endpoint
+    replacement: web
+  - source_labels:
+    - __address__
+    target_label: __tmp_hash
+    modulus: 1
+    action: hashmod
+  - source_labels:
+    - __tmp_hash
+    regex: $(SHARD)
+    action: keep
+  metric_relabel_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+`
+
+	expectLimit := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs:
+- job_name: serviceMonitor/default/testservicemonitor1/0
+  honor_labels: false
+  kubernetes_sd_configs:
+  - role: endpoints
+    namespaces:
+      names:
+      - default
+  scrape_interval: 30s
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - action: keep
+    source_labels:
+    - __meta_kubernetes_endpoint_port_name
+    regex: web
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Node;(.*)
+    replacement: ${1}
+    target_label: node
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Pod;(.*)
+    replacement: ${1}
+    target_label: pod
+  - source_labels:
+    - __meta_kubernetes_namespace
+    target_label: namespace
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: service
+  - source_labels:
+    - __meta_kubernetes_pod_name
+    target_label: pod
+  - source_labels:
+    - __meta_kubernetes_pod_container_name
+    target_label: container
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: job
+    replacement: ${1}
+  - target_label: endpoint
+    replacement: web
+  - source_labels:
+    - __address__
+    target_label: __tmp_hash
+    modulus: 1
+    action: hashmod
+  - source_labels:
+    - __tmp_hash
+    regex: $(SHARD)
+    action: keep
+  label_limit: %d
+  metric_relabel_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+`
+
+	for _, tc := range []struct {
+		version            string
+		enforcedLabelLimit int
+		labelLimit         int
+		expected           string
+	}{
+		{
+			version:            "v2.26.0",
+			enforcedLabelLimit: -1,
+			labelLimit:         -1,
+			expected:           expectNoLimit,
+		},
+		{
+			version:            "v2.27.0",
+			enforcedLabelLimit: -1,
+			labelLimit:         -1,
+			expected:           expectNoLimit,
+		},
+		{
+			version:            "v2.26.0",
+			enforcedLabelLimit: 1000,
+			labelLimit:         -1,
+			expected:           expectNoLimit,
+		},
+		{
+			version:            "v2.27.0",
+			enforcedLabelLimit: 1000,
+			labelLimit:         -1,
+			expected:           fmt.Sprintf(expectLimit, 1000),
+		},
+		{
+			version:            "v2.26.0",
+			enforcedLabelLimit: 1000,
+			labelLimit:         2000,
+			expected:           expectNoLimit,
+		},
+		{
+			version:            "v2.27.0",
+			enforcedLabelLimit: 1000,
+			labelLimit:         2000,
+			expected:           fmt.Sprintf(expectLimit, 1000),
+		},
+		{
+			version:            "v2.26.0",
+			enforcedLabelLimit: 1000,
+			labelLimit:         500,
+			expected:           expectNoLimit,
+		},
+		{
+			version:            "v2.27.0",
+			enforcedLabelLimit: 1000,
+			labelLimit:         500,
+			expected:           fmt.Sprintf(expectLimit, 500),
+		},
+	} {
+		t.Run(fmt.Sprintf("%s enforcedLabelLimit(%d) labelLimit(%d)", tc.version, tc.enforcedLabelLimit, tc.labelLimit), func(t *testing.T) {
+			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
+
+			prometheus := monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					Version: tc.version,
+					ServiceMonitorSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"group": "group1",
+						},
+					},
+				},
+			}
+			if tc.enforcedLabelLimit >= 0 {
+				i := uint64(tc.enforcedLabelLimit)
+				prometheus.Spec.EnforcedLabelLimit = &i
+			}
+
+			serviceMonitor := monitoringv1.ServiceMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testservicemonitor1",
+					Namespace: "default",
+					Labels: map[string]string{
+						"group": "group1",
+					},
+				},
+				Spec: monitoringv1.ServiceMonitorSpec{
+					Endpoints: []monitoringv1.Endpoint{
+						{
+							Port:     "web",
+							Interval: "30s",
+						},
+					},
+				},
+			}
+			if tc.labelLimit >= 0 {
+				serviceMonitor.Spec.LabelLimit = uint64(tc.labelLimit)
+			}
+
+			cfg, err := cg.GenerateConfig(
+				&prometheus,
+				map[string]*monitoringv1.ServiceMonitor{
+					"testservicemonitor1": &serviceMonitor,
+				},
+				nil,
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := string(cfg)
+			if tc.expected != result {
+				t.Logf("\n%s", pretty.Compare(tc.expected, result))
+				t.Fatal("expected Prometheus configuration and actual configuration do not match")
+			}
+		})
+	}
+}
+
+func TestLabelNameLengthLimits(t *testing.T) {
+	expectNoLimit := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs:
+- job_name: podMonitor/default/testpodmonitor1/0
+  honor_labels: false
+  kubernetes_sd_configs:
+  - role: pod
+    namespaces:
+      names:
+      - default
+  scrape_interval: 30s
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - action: drop
+    source_labels:
+    - __meta_kubernetes_pod_phase
+    regex: (Failed|Succeeded)
+  - action: keep
+    source_labels:
+    - __meta_kubernetes_pod_container_port_name
+    regex: web
+  - source_labels:
+    - __meta_kubernetes_namespace
+    target_label: namespace
+  - source_labels:
+    - __meta_kubernetes_pod_container_name
+    target_label: container
+  - source_labels:
+    - __meta_kubernetes_pod_name
+    target_label: pod
+  - target_label: job
+    replacement: default/testpodmonitor1
+  - target_label: endpoint
+    replacement: web
+  - source_labels:
+    - __address__
+    target_label: __tmp_hash
+    modulus: 1
+    action: hashmod
+  - source_labels:
+    - __tmp_hash
+    regex: $(SHARD)
+    action: keep
+  metric_relabel_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+`
+
+	expectLimit := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs:
+- job_name: podMonitor/default/testpodmonitor1/0
+  honor_labels: false
+  kubernetes_sd_configs:
+  - role: pod
+    namespaces:
+      names:
+      - default
+  scrape_interval: 30s
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - action: drop
+    source_labels:
+    - __meta_kubernetes_pod_phase
+    regex: (Failed|Succeeded)
+  - action: keep
+    source_labels:
+    - __meta_kubernetes_pod_container_port_name
+    regex: web
+  - source_labels:
+    - __meta_kubernetes_namespace
+    target_label: namespace
+  - source_labels:
+    - __meta_kubernetes_pod_container_name
+    target_label: container
+  - source_labels:
+    - __meta_kubernetes_pod_name
+    target_label: pod
+  - target_label: job
+    replacement: default/testpodmonitor1
+  - target_label: endpoint
+    replacement: web
+  - source_labels:
+    - __address__
+    target_label: __tmp_hash
+    modulus: 1
+    action: hashmod
+  - source_labels:
+    - __tmp_hash
+    regex: $(SHARD)
+    action: keep
+  label_name_length_limit: %d
+  metric_relabel_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+`
+
+	for _, tc := range []struct {
+		version                      string
+		enforcedLabelNameLengthLimit int
+		labelNameLengthLimit         int
+		expected                     string
+	}{
+		{
+			version:                      "v2.26.0",
+			enforcedLabelNameLengthLimit: -1,
+			labelNameLengthLimit:         -1,
+			expected:                     expectNoLimit,
+		},
+		{
+			version:                      "v2.27.0",
+			enforcedLabelNameLengthLimit: -1,
+			labelNameLengthLimit:         -1,
+			expected:                     expectNoLimit,
+		},
+		{
+			version:                      "v2.26.0",
+			enforcedLabelNameLengthLimit: 1000,
+			labelNameLengthLimit:         -1,
+			expected:                     expectNoLimit,
+		},
+		{
+			version:                      "v2.27.0",
+			enforcedLabelNameLengthLimit: 1000,
+			labelNameLengthLimit:         -1,
+			expected:                     fmt.Sprintf(expectLimit, 1000),
+		},
+		{
+			version:                      "v2.26.0",
+			enforcedLabelNameLengthLimit: 1000,
+			labelNameLengthLimit:         2000,
+			expected:                     expectNoLimit,
+		},
+		{
+			version:                      "v2.27.0",
+			enforcedLabelNameLengthLimit: 1000,
+			labelNameLengthLimit:         2000,
+			expected:                     fmt.Sprintf(expectLimit, 1000),
+		},
+		{
+			version:                      "v2.26.0",
+			enforcedLabelNameLengthLimit: 1000,
+			labelNameLengthLimit:         500,
+			expected:                     expectNoLimit,
+		},
+		{
+			version:                      "v2.27.0",
+			enforcedLabelNameLengthLimit: 1000,
+			labelNameLengthLimit:         500,
+			expected:                     fmt.Sprintf(expectLimit, 500),
+		},
+	} {
+		t.Run(fmt.Sprintf("%s enforcedLabelNameLengthLimit(%d) labelNameLengthLimit(%d)", tc.version, tc.enforcedLabelNameLengthLimit, tc.labelNameLengthLimit), func(t *testing.T) {
+			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
+
+			prometheus := monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					Version: tc.version,
+					ServiceMonitorSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"group": "group1",
+						},
+					},
+				},
+			}
+			if tc.enforcedLabelNameLengthLimit >= 0 {
+				i := uint64(tc.enforcedLabelNameLengthLimit)
+				prometheus.Spec.EnforcedLabelNameLengthLimit = &i
+			}
+
+			podMonitor := monitoringv1.PodMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testpodmonitor1",
+					Namespace: "default",
+					Labels: map[string]string{
+						"group": "group1",
+					},
+				},
+				Spec: monitoringv1.PodMonitorSpec{
+					PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{
+						{
+							Port:     "web",
+							Interval: "30s",
+						},
+					},
+				},
+			}
+			if tc.labelNameLengthLimit >= 0 {
+				podMonitor.Spec.LabelNameLengthLimit = uint64(tc.labelNameLengthLimit)
+			}
+
+			cfg, err := cg.GenerateConfig(
+				&prometheus,
+				nil,
+				map[string]*monitoringv1.PodMonitor{
+					"testpodmonitor1": &podMonitor,
+				},
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := string(cfg)
+			if tc.expected != result {
+				t.Logf("\n%s", pretty.Compare(tc.expected, result))
+				t.Fatal("expected Prometheus configuration and actual configuration do not match")
+			}
+		})
+	}
+}
+
+func TestLabelValueLengthLimits(t *testing.T) {
+	expectNoLimit := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs:
+- job_name: probe/default/testprobe1
+  honor_timestamps: true
+  metrics_path: /probe
+  scheme: http
+  proxy_url: socks://myproxy:9095
+  params:
+    module:
+    - http_2xx
+  static_configs:
+  - targets:
+    - prometheus.io
+    - promcon.io
+    labels:
+      namespace: default
+      static: label
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - source_labels:
+    - __address__
+    target_label: __param_target
+  - source_labels:
+    - __param_target
+    target_label: instance
+  - target_label: __address__
+    replacement: blackbox.exporter.io
+  metric_relabel_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+`
+
+	expectLimit := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs:
+- job_name: probe/default/testprobe1
+  honor_timestamps: true
+  metrics_path: /probe
+  scheme: http
+  proxy_url: socks://myproxy:9095
+  params:
+    module:
+    - http_2xx
+  label_value_length_limit: %d
+  static_configs:
+  - targets:
+    - prometheus.io
+    - promcon.io
+    labels:
+      namespace: default
+      static: label
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - source_labels:
+    - __address__
+    target_label: __param_target
+  - source_labels:
+    - __param_target
+    target_label: instance
+  - target_label: __address__
+    replacement: blackbox.exporter.io
+  metric_relabel_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+`
+
+	for _, tc := range []struct {
+		version                       string
+		enforcedLabelValueLengthLimit int
+		labelValueLengthLimit         int
+		expected                      string
+	}{
+		{
+			version:                       "v2.26.0",
+			enforcedLabelValueLengthLimit: -1,
+			labelValueLengthLimit:         -1,
+			expected:                      expectNoLimit,
+		},
+		{
+			version:                       "v2.27.0",
+			enforcedLabelValueLengthLimit: -1,
+			labelValueLengthLimit:         -1,
+			expected:                      expectNoLimit,
+		},
+		{
+			version:                       "v2.26.0",
+			enforcedLabelValueLengthLimit: 1000,
+			labelValueLengthLimit:         -1,
+			expected:                      expectNoLimit,
+		},
+		{
+			version:                       "v2.27.0",
+			enforcedLabelValueLengthLimit: 1000,
+			labelValueLengthLimit:         -1,
+			expected:                      fmt.Sprintf(expectLimit, 1000),
+		},
+		{
+			version:                       "v2.26.0",
+			enforcedLabelValueLengthLimit: 1000,
+			labelValueLengthLimit:         2000,
+			expected:                      expectNoLimit,
+		},
+		{
+			version:                       "v2.27.0",
+			enforcedLabelValueLengthLimit: 1000,
+			labelValueLengthLimit:         2000,
+			expected:                      fmt.Sprintf(expectLimit, 1000),
+		},
+		{
+			version:                       "v2.26.0",
+			enforcedLabelValueLengthLimit: 1000,
+			labelValueLengthLimit:         500,
+			expected:                      expectNoLimit,
+		},
+		{
+			version:                       "v2.27.0",
+			enforcedLabelValueLengthLimit: 1000,
+			labelValueLengthLimit:         500,
+			expected:                      fmt.Sprintf(expectLimit, 500),
+		},
+	} {
+		t.Run(fmt.Sprintf("%s enforcedLabelValueLengthLimit(%d) labelValueLengthLimit(%d)", tc.version, tc.enforcedLabelValueLengthLimit, tc.labelValueLengthLimit), func(t *testing.T) {
+			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
+
+			prometheus := monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					Version: tc.version,
+					ServiceMonitorSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"group": "group1",
+						},
+					},
+				},
+			}
+			if tc.enforcedLabelValueLengthLimit >= 0 {
+				i := uint64(tc.enforcedLabelValueLengthLimit)
+				prometheus.Spec.EnforcedLabelValueLengthLimit = &i
+			}
+
+			probe := monitoringv1.Probe{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testprobe1",
+					Namespace: "default",
+					Labels: map[string]string{
+						"group": "group1",
+					},
+				},
+				Spec: monitoringv1.ProbeSpec{
+					ProberSpec: monitoringv1.ProberSpec{
+						Scheme:   "http",
+						URL:      "blackbox.exporter.io",
+						Path:     "/probe",
+						ProxyURL: "socks://myproxy:9095",
+					},
+					Module: "http_2xx",
+					Targets: monitoringv1.ProbeTargets{
+						StaticConfig: &monitoringv1.ProbeTargetStaticConfig{
+							Targets: []string{
+								"prometheus.io",
+								"promcon.io",
+							},
+							Labels: map[string]string{
+								"static": "label",
+							},
+						},
+					},
+				},
+			}
+			if tc.labelValueLengthLimit >= 0 {
+				probe.Spec.LabelValueLengthLimit = uint64(tc.labelValueLengthLimit)
+			}
+
+			cfg, err := cg.GenerateConfig(
+				&prometheus,
+				nil,
+				nil,
+				map[string]*monitoringv1.Probe{
+					"testprobe1": &probe,
+				},
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := string(cfg)
+			if tc.expected != result {
+				t.Logf("\n%s", pretty.Compare(tc.expected, result))
+				t.Fatal("expected Prometheus configuration and actual configuration do not match")
+			}
+		})
+	}
+}
+
+func TestKeepDroppedTargets(t *testing.T) {
+	expected := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+%srule_files: []
+scrape_configs:
+- job_name: serviceMonitor/default/testservicemonitor1/0
+  honor_labels: false
+  kubernetes_sd_configs:
+  - role: endpoints
+    namespaces:
+      names:
+      - default
+  scrape_interval: 30s
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - action: keep
+    source_labels:
+    - __meta_kubernetes_endpoint_port_name
+    regex: web
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Node;(.*)
+    replacement: ${1}
+    target_label: node
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Pod;(.*)
+    replacement: ${1}
+    target_label: pod
+  - source_labels:
+    - __meta_kubernetes_namespace
+    target_label: namespace
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: service
+  - source_labels:
+    - __meta_kubernetes_pod_name
+    target_label: pod
+  - source_labels:
+    - __meta_kubernetes_pod_container_name
+    target_label: container
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: job
+    replacement: ${1}
+  - target_label: endpoint
+    replacement: web
+  - source_labels:
+    - __address__
+    target_label: __tmp_hash
+    modulus: 1
+    action: hashmod
+  - source_labels:
+    - __tmp_hash
+    regex: $(SHARD)
+    action: keep
+%s  metric_relabel_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+`
+
+	for _, tc := range []struct {
+		name                      string
+		version                   string
+		keepDroppedTargets        *uint64
+		monitorKeepDroppedTargets uint64
+		expected                  string
+	}{
+		{
+			name:     "not set",
+			version:  "v2.47.0",
+			expected: fmt.Sprintf(expected, "", ""),
+		},
+		{
+			name:                      "global and monitor values",
+			version:                   "v2.47.0",
+			keepDroppedTargets:        uint64Ptr(100),
+			monitorKeepDroppedTargets: 50,
+			expected:                  fmt.Sprintf(expected, "  keep_dropped_targets: 100\n", "  keep_dropped_targets: 50\n"),
+		},
+		{
+			name:                      "unsupported version",
+			version:                   "v2.46.0",
+			keepDroppedTargets:        uint64Ptr(100),
+			monitorKeepDroppedTargets: 50,
+			expected:                  fmt.Sprintf(expected, "", ""),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
+
+			prometheus := monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					Version:            tc.version,
+					KeepDroppedTargets: tc.keepDroppedTargets,
+					ServiceMonitorSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"group": "group1",
+						},
+					},
+				},
+			}
+
+			serviceMonitor := monitoringv1.ServiceMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testservicemonitor1",
+					Namespace: "default",
+					Labels: map[string]string{
+						"group": "group1",
+					},
+				},
+				Spec: monitoringv1.ServiceMonitorSpec{
+					KeepDroppedTargets: tc.monitorKeepDroppedTargets,
+					Endpoints: []monitoringv1.Endpoint{
+						{
+							Port:     "web",
+							Interval: "30s",
+						},
+					},
+				},
+			}
+
+			cfg, err := cg.GenerateConfig(
+				&prometheus,
+				map[string]*monitoringv1.ServiceMonitor{
+					"testservicemonitor1": &serviceMonitor,
+				},
+				nil,
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := string(cfg)
+			if tc.expected != result {
+				t.Logf("\n%s", pretty.Compare(tc.expected, result))
+				t.Fatal("expected Prometheus configuration and actual configuration do not match")
+			}
+		})
+	}
+}
+
+func TestBodySizeLimits(t *testing.T) {
+	expectNoLimit := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs:
+- job_name: serviceMonitor/default/testservicemonitor1/0
+  honor_labels: false
+  kubernetes_sd_configs:
+  - role: endpoints
+    namespaces:
+      names:
+      - default
+  scrape_interval: 30s
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - action: keep
+    source_labels:
+    - __meta_kubernetes_endpoint_port_name
+    regex: web
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Node;(.*)
+    replacement: ${1}
+    target_label: node
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Pod;(.*)
+    replacement: ${1}
+    target_label: pod
+  - source_labels:
+    - __meta_kubernetes_namespace
+    target_label: namespace
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: service
+  - source_labels:
+    - __meta_kubernetes_pod_name
+    target_label: pod
+  - source_labels:
+    - __meta_kubernetes_pod_container_name
+    target_label: container
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: job
+    replacement: ${1}
+  - target_label: endpoint
+    replacement: web
+  - source_labels:
+    - __address__
+    target_label: __tmp_hash
+    modulus: 1
+    action: hashmod
+  - source_labels:
+    - __tmp_hash
+    regex: $(SHARD)
+    action: keep
+  metric_relabel_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+`
+
+	expectLimit := `global:
   evaluation_interval: 30s
   scrape_interval: 30s
   external_labels:
@@ -5285,6 +7047,286 @@ scrape_configs:
     - __tmp_hash
     regex: $(SHARD)
     action: keep
+  body_size_limit: %s
+  metric_relabel_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+`
+
+	for _, tc := range []struct {
+		version               string
+		enforcedBodySizeLimit string
+		expected              string
+		expectedErr           error
+	}{
+		{
+			version:               "v2.27.0",
+			enforcedBodySizeLimit: "1000MB",
+			expected:              expectNoLimit,
+		},
+		{
+			version:               "v2.28.0",
+			enforcedBodySizeLimit: "1000MB",
+			expected:              fmt.Sprintf(expectLimit, "1000MB"),
+		},
+		{
+			version:               "v2.28.0",
+			enforcedBodySizeLimit: "",
+			expected:              expectNoLimit,
+		},
+		{
+			version:               "v2.28.0",
+			enforcedBodySizeLimit: "100",
+			expectedErr:           errors.New("invalid enforcedBodySizeLimit value specified: units: unknown unit  in 100"),
+		},
+		{
+			version:               "v2.28.0",
+			enforcedBodySizeLimit: "200kb",
+			expectedErr:           errors.New("invalid enforcedBodySizeLimit value specified: units: unknown unit kb in 200kb"),
+		},
+		{
+			version:               "v2.28.0",
+			enforcedBodySizeLimit: "300 MB",
+			expectedErr:           errors.New("invalid enforcedBodySizeLimit value specified: units: unknown unit  MB in 300 MB"),
+		},
+		{
+			version:               "v2.28.0",
+			enforcedBodySizeLimit: "150M",
+			expectedErr:           errors.New("invalid enforcedBodySizeLimit value specified: units: unknown unit M in 150M"),
+		},
+	} {
+		t.Run(fmt.Sprintf("%s enforcedBodySizeLimit(%s)", tc.version, tc.enforcedBodySizeLimit), func(t *testing.T) {
+			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
+
+			prometheus := monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					Version: tc.version,
+					ServiceMonitorSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"group": "group1",
+						},
+					},
+				},
+			}
+			if tc.enforcedBodySizeLimit != "" {
+				i := tc.enforcedBodySizeLimit
+				prometheus.Spec.EnforcedBodySizeLimit = i
+			}
+
+			serviceMonitor := monitoringv1.ServiceMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testservicemonitor1",
+					Namespace: "default",
+					Labels: map[string]string{
+						"group": "group1",
+					},
+				},
+				Spec: monitoringv1.ServiceMonitorSpec{
+					Endpoints: []monitoringv1.Endpoint{
+						{
+							Port:     "web",
+							Interval: "30s",
+						},
+					},
+				},
+			}
+
+			cfg, err := cg.GenerateConfig(
+				&prometheus,
+				map[string]*monitoringv1.ServiceMonitor{
+					"testservicemonitor1": &serviceMonitor,
+				},
+				nil,
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			if tc.expectedErr != nil {
+				if tc.expectedErr.Error() != err.Error() {
+					t.Logf("\n%s", pretty.Compare(tc.expectedErr.Error(), err.Error()))
+					t.Fatal("expected error and actual error do not match")
+				}
+			} else {
+				result := string(cfg)
+				if tc.expected != result {
+					t.Logf("\n%s", pretty.Compare(tc.expected, result))
+					t.Fatal("expected Prometheus configuration and actual configuration do not match")
+				}
+			}
+		})
+	}
+}
+
+func TestScrapeConfigGeneration(t *testing.T) {
+	cg := &ConfigGenerator{}
+	cfg, err := cg.GenerateConfig(
+		&monitoringv1.Prometheus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+			Spec: monitoringv1.PrometheusSpec{
+				ScrapeConfigSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"group": "group1",
+					},
+				},
+			},
+		},
+		nil,
+		nil,
+		nil,
+		map[string]*monitoringv1alpha1.ScrapeConfig{
+			"scrapeconfig1": {
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testscrapeconfig1",
+					Namespace: "default",
+					Labels: map[string]string{
+						"group": "group1",
+					},
+				},
+				Spec: monitoringv1alpha1.ScrapeConfigSpec{
+					JobName:        "node",
+					MetricsPath:    "/metrics",
+					Scheme:         "http",
+					ScrapeInterval: "30s",
+					StaticConfigs: []monitoringv1alpha1.StaticConfig{
+						{
+							Targets: []string{"node1:9100", "node2:9100"},
+							Labels: map[string]string{
+								"static": "label",
+							},
+						},
+					},
+					FileSDConfigs: []monitoringv1alpha1.FileSDConfig{
+						{
+							Files:           []string{"/etc/prometheus/sd/*.json"},
+							RefreshInterval: "5m",
+						},
+					},
+					HTTPSDConfigs: []monitoringv1alpha1.HTTPSDConfig{
+						{
+							URL: "https://sd.example.com/targets",
+							BasicAuth: &monitoringv1.BasicAuth{
+								Username: v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{Name: "sd-auth"},
+									Key:                  "username",
+								},
+								Password: v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{Name: "sd-auth"},
+									Key:                  "password",
+								},
+							},
+						},
+					},
+					DNSSDConfigs: []monitoringv1alpha1.DNSSDConfig{
+						{
+							Names: []string{"node.example.com"},
+							Type:  "A",
+							Port:  pointer.Int32Ptr(9100),
+						},
+					},
+					KubernetesSDConfigs: []monitoringv1alpha1.KubernetesSDConfig{
+						{Role: "pod"},
+						{Role: "node"},
+					},
+					RelabelConfigs: []*monitoringv1.RelabelConfig{
+						{
+							TargetLabel: "foo",
+							Replacement: "bar",
+							Action:      "replace",
+						},
+					},
+				},
+			},
+		},
+		&assets.Store{
+			BasicAuthAssets: map[string]assets.BasicAuthCredentials{
+				"scrapeConfig/default/testscrapeconfig1/httpsdconfig/0": {
+					Username: "user",
+					Password: "pass",
+				},
+			},
+		},
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs:
+- job_name: scrapeConfig/default/testscrapeconfig1
+  honor_labels: false
+  scrape_interval: 30s
+  metrics_path: /metrics
+  scheme: http
+  static_configs:
+  - targets:
+    - node1:9100
+    - node2:9100
+    labels:
+      static: label
+  file_sd_configs:
+  - files:
+    - /etc/prometheus/sd/*.json
+    refresh_interval: 5m
+  http_sd_configs:
+  - url: https://sd.example.com/targets
+    basic_auth:
+      username: user
+      password: pass
+  dns_sd_configs:
+  - names:
+    - node.example.com
+    type: A
+    port: 9100
+  kubernetes_sd_configs:
+  - role: pod
+    namespaces:
+      names:
+      - default
+  - role: node
+  relabel_configs:
+  - source_labels:
+    - job
+    target_label: __tmp_prometheus_job_name
+  - target_label: job
+    replacement: node
+  - target_label: foo
+    replacement: bar
+    action: replace
+  - source_labels:
+    - __address__
+    target_label: __tmp_hash
+    modulus: 1
+    action: hashmod
+  - source_labels:
+    - __tmp_hash
+    regex: $(SHARD)
+    action: keep
   metric_relabel_configs: []
 alerting:
   alert_relabel_configs:
@@ -5293,7 +7335,14 @@ alerting:
   alertmanagers: []
 `
 
-	expectLimit := `global:
+	result := string(cfg)
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Fatalf("Unexpected result got(-) want(+)\n%s\n", diff)
+	}
+}
+
+func TestNativeHistograms(t *testing.T) {
+	expected := `global:
   evaluation_interval: 30s
   scrape_interval: 30s
   external_labels:
@@ -5358,8 +7407,7 @@ scrape_configs:
     - __tmp_hash
     regex: $(SHARD)
     action: keep
-  label_limit: %d
-  metric_relabel_configs: []
+%s  metric_relabel_configs: []
 alerting:
   alert_relabel_configs:
   - action: labeldrop
@@ -5368,61 +7416,50 @@ alerting:
 `
 
 	for _, tc := range []struct {
-		version            string
-		enforcedLabelLimit int
-		labelLimit         int
-		expected           string
+		version                    string
+		scrapeNativeHistograms     *bool
+		nativeHistogramBucketLimit uint64
+		expected                   string
 	}{
 		{
-			version:            "v2.26.0",
-			enforcedLabelLimit: -1,
-			labelLimit:         -1,
-			expected:           expectNoLimit,
-		},
-		{
-			version:            "v2.27.0",
-			enforcedLabelLimit: -1,
-			labelLimit:         -1,
-			expected:           expectNoLimit,
-		},
-		{
-			version:            "v2.26.0",
-			enforcedLabelLimit: 1000,
-			labelLimit:         -1,
-			expected:           expectNoLimit,
-		},
-		{
-			version:            "v2.27.0",
-			enforcedLabelLimit: 1000,
-			labelLimit:         -1,
-			expected:           fmt.Sprintf(expectLimit, 1000),
+			version:                    "v2.44.0",
+			scrapeNativeHistograms:     pointer.BoolPtr(true),
+			nativeHistogramBucketLimit: 160,
+			expected:                   fmt.Sprintf(expected, ""),
 		},
 		{
-			version:            "v2.26.0",
-			enforcedLabelLimit: 1000,
-			labelLimit:         2000,
-			expected:           expectNoLimit,
+			version:                    "v2.45.0",
+			scrapeNativeHistograms:     pointer.BoolPtr(true),
+			nativeHistogramBucketLimit: 160,
+			expected:                   fmt.Sprintf(expected, "  native_histogram_bucket_limit: 160\n"),
 		},
 		{
-			version:            "v2.27.0",
-			enforcedLabelLimit: 1000,
-			labelLimit:         2000,
-			expected:           fmt.Sprintf(expectLimit, 1000),
+			version:                    "v2.49.0",
+			scrapeNativeHistograms:     pointer.BoolPtr(true),
+			nativeHistogramBucketLimit: 160,
+			expected: fmt.Sprintf(expected, `  native_histogram_bucket_limit: 160
+  scrape_protocols:
+  - PrometheusProto
+  - OpenMetricsText1.0.0
+  - OpenMetricsText0.0.1
+  - PrometheusText0.0.4
+`),
 		},
 		{
-			version:            "v2.26.0",
-			enforcedLabelLimit: 1000,
-			labelLimit:         500,
-			expected:           expectNoLimit,
+			version:                "v2.49.0",
+			scrapeNativeHistograms: pointer.BoolPtr(false),
+			expected: fmt.Sprintf(expected, `  scrape_protocols:
+  - OpenMetricsText1.0.0
+  - OpenMetricsText0.0.1
+  - PrometheusText0.0.4
+`),
 		},
 		{
-			version:            "v2.27.0",
-			enforcedLabelLimit: 1000,
-			labelLimit:         500,
-			expected:           fmt.Sprintf(expectLimit, 500),
+			version:  "v2.49.0",
+			expected: fmt.Sprintf(expected, ""),
 		},
 	} {
-		t.Run(fmt.Sprintf("%s enforcedLabelLimit(%d) labelLimit(%d)", tc.version, tc.enforcedLabelLimit, tc.labelLimit), func(t *testing.T) {
+		t.Run(tc.version, func(t *testing.T) {
 			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
 
 			prometheus := monitoringv1.Prometheus{
@@ -5439,10 +7476,6 @@ alerting:
 					},
 				},
 			}
-			if tc.enforcedLabelLimit >= 0 {
-				i := uint64(tc.enforcedLabelLimit)
-				prometheus.Spec.EnforcedLabelLimit = &i
-			}
 
 			serviceMonitor := monitoringv1.ServiceMonitor{
 				ObjectMeta: metav1.ObjectMeta{
@@ -5455,15 +7488,14 @@ alerting:
 				Spec: monitoringv1.ServiceMonitorSpec{
 					Endpoints: []monitoringv1.Endpoint{
 						{
-							Port:     "web",
-							Interval: "30s",
+							Port:                       "web",
+							Interval:                   "30s",
+							ScrapeNativeHistograms:     tc.scrapeNativeHistograms,
+							NativeHistogramBucketLimit: tc.nativeHistogramBucketLimit,
 						},
 					},
 				},
 			}
-			if tc.labelLimit >= 0 {
-				serviceMonitor.Spec.LabelLimit = uint64(tc.labelLimit)
-			}
 
 			cfg, err := cg.GenerateConfig(
 				&prometheus,
@@ -5472,6 +7504,7 @@ alerting:
 				},
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -5491,8 +7524,8 @@ alerting:
 	}
 }
 
-func TestLabelNameLengthLimits(t *testing.T) {
-	expectNoLimit := `global:
+func TestScrapeClass(t *testing.T) {
+	expected := `global:
   evaluation_interval: 30s
   scrape_interval: 30s
   external_labels:
@@ -5500,36 +7533,55 @@ func TestLabelNameLengthLimits(t *testing.T) {
     prometheus_replica: $(POD_NAME)
 rule_files: []
 scrape_configs:
-- job_name: podMonitor/default/testpodmonitor1/0
+- job_name: serviceMonitor/default/testservicemonitor1/0
   honor_labels: false
   kubernetes_sd_configs:
-  - role: pod
+  - role: endpoints
     namespaces:
       names:
       - default
   scrape_interval: 30s
-  relabel_configs:
+%s  relabel_configs:
   - source_labels:
     - job
     target_label: __tmp_prometheus_job_name
   - action: keep
     source_labels:
-    - __meta_kubernetes_pod_container_port_name
+    - __meta_kubernetes_endpoint_port_name
     regex: web
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Node;(.*)
+    replacement: ${1}
+    target_label: node
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Pod;(.*)
+    replacement: ${1}
+    target_label: pod
   - source_labels:
     - __meta_kubernetes_namespace
     target_label: namespace
   - source_labels:
-    - __meta_kubernetes_pod_container_name
-    target_label: container
+    - __meta_kubernetes_service_name
+    target_label: service
   - source_labels:
     - __meta_kubernetes_pod_name
     target_label: pod
-  - target_label: job
-    replacement: default/testpodmonitor1
+  - source_labels:
+    - __meta_kubernetes_pod_container_name
+    target_label: container
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: job
+    replacement: ${1}
   - target_label: endpoint
     replacement: web
-  - source_labels:
+%s  - source_labels:
     - __address__
     target_label: __tmp_hash
     modulus: 1
@@ -5538,7 +7590,7 @@ scrape_configs:
     - __tmp_hash
     regex: $(SHARD)
     action: keep
-  metric_relabel_configs: []
+  metric_relabel_configs:%s
 alerting:
   alert_relabel_configs:
   - action: labeldrop
@@ -5546,18 +7598,174 @@ alerting:
   alertmanagers: []
 `
 
-	expectLimit := `global:
+	classTLSAndAuth := `  tls_config:
+    insecure_skip_verify: false
+    server_name: example.com
+    ca_file: /etc/prometheus/certs/ca.crt
+  authorization:
+    type: Bearer
+    credentials_file: /etc/prometheus/token
+`
+	classRelabelings := `  - target_label: cluster
+    replacement: prod
+`
+	classMetricRelabelings := `
+  - source_labels:
+    - __name__
+    regex: go_.*
+    action: drop`
+
+	newScrapeClass := func(isDefault bool) monitoringv1.ScrapeClass {
+		return monitoringv1.ScrapeClass{
+			Name:    "default-tls",
+			Default: pointer.Bool(isDefault),
+			TLSConfig: &monitoringv1.TLSConfig{
+				CAFile: "/etc/prometheus/certs/ca.crt",
+				SafeTLSConfig: monitoringv1.SafeTLSConfig{
+					ServerName: "example.com",
+				},
+			},
+			Authorization: &monitoringv1.Authorization{
+				CredentialsFile: "/etc/prometheus/token",
+			},
+			Relabelings: []*monitoringv1.RelabelConfig{
+				{
+					TargetLabel: "cluster",
+					Replacement: "prod",
+				},
+			},
+			MetricRelabelings: []*monitoringv1.RelabelConfig{
+				{
+					Action:       "drop",
+					SourceLabels: []string{"__name__"},
+					Regex:        "go_.*",
+				},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name            string
+		scrapeClasses   []monitoringv1.ScrapeClass
+		scrapeClassName *string
+		endpoint        monitoringv1.Endpoint
+		expected        string
+	}{
+		{
+			name:     "no scrape class",
+			expected: fmt.Sprintf(expected, "", "", " []"),
+		},
+		{
+			name:            "explicit scrape class",
+			scrapeClasses:   []monitoringv1.ScrapeClass{newScrapeClass(false)},
+			scrapeClassName: pointer.String("default-tls"),
+			expected:        fmt.Sprintf(expected, classTLSAndAuth, classRelabelings, classMetricRelabelings),
+		},
+		{
+			name:          "default scrape class",
+			scrapeClasses: []monitoringv1.ScrapeClass{newScrapeClass(true)},
+			expected:      fmt.Sprintf(expected, classTLSAndAuth, classRelabelings, classMetricRelabelings),
+		},
+		{
+			name:          "no default scrape class",
+			scrapeClasses: []monitoringv1.ScrapeClass{newScrapeClass(false)},
+			expected:      fmt.Sprintf(expected, "", "", " []"),
+		},
+		{
+			name:            "endpoint TLS and authentication take precedence",
+			scrapeClasses:   []monitoringv1.ScrapeClass{newScrapeClass(false)},
+			scrapeClassName: pointer.String("default-tls"),
+			endpoint: monitoringv1.Endpoint{
+				TLSConfig: &monitoringv1.TLSConfig{
+					SafeTLSConfig: monitoringv1.SafeTLSConfig{
+						InsecureSkipVerify: true,
+					},
+				},
+				BearerTokenFile: "/var/run/secrets/token",
+			},
+			expected: fmt.Sprintf(expected, `  tls_config:
+    insecure_skip_verify: true
+  bearer_token_file: /var/run/secrets/token
+`, classRelabelings, classMetricRelabelings),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
+
+			prometheus := monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					Version:       "v2.45.0",
+					ScrapeClasses: tc.scrapeClasses,
+					ServiceMonitorSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"group": "group1",
+						},
+					},
+				},
+			}
+
+			ep := tc.endpoint
+			ep.Port = "web"
+			ep.Interval = "30s"
+
+			serviceMonitor := monitoringv1.ServiceMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testservicemonitor1",
+					Namespace: "default",
+					Labels: map[string]string{
+						"group": "group1",
+					},
+				},
+				Spec: monitoringv1.ServiceMonitorSpec{
+					ScrapeClassName: tc.scrapeClassName,
+					Endpoints:       []monitoringv1.Endpoint{ep},
+				},
+			}
+
+			cfg, err := cg.GenerateConfig(
+				&prometheus,
+				map[string]*monitoringv1.ServiceMonitor{
+					"testservicemonitor1": &serviceMonitor,
+				},
+				nil,
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := string(cfg)
+			if tc.expected != result {
+				t.Logf("\n%s", pretty.Compare(tc.expected, result))
+				t.Fatal("expected Prometheus configuration and actual configuration do not match")
+			}
+		})
+	}
+}
+
+func TestScrapeProtocols(t *testing.T) {
+	expected := `global:
   evaluation_interval: 30s
   scrape_interval: 30s
   external_labels:
     prometheus: default/test
     prometheus_replica: $(POD_NAME)
-rule_files: []
+%srule_files: []
 scrape_configs:
-- job_name: podMonitor/default/testpodmonitor1/0
+- job_name: serviceMonitor/default/testservicemonitor1/0
   honor_labels: false
   kubernetes_sd_configs:
-  - role: pod
+  - role: endpoints
     namespaces:
       names:
       - default
@@ -5568,19 +7776,38 @@ scrape_configs:
     target_label: __tmp_prometheus_job_name
   - action: keep
     source_labels:
-    - __meta_kubernetes_pod_container_port_name
+    - __meta_kubernetes_endpoint_port_name
     regex: web
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Node;(.*)
+    replacement: ${1}
+    target_label: node
+  - source_labels:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Pod;(.*)
+    replacement: ${1}
+    target_label: pod
   - source_labels:
     - __meta_kubernetes_namespace
     target_label: namespace
   - source_labels:
-    - __meta_kubernetes_pod_container_name
-    target_label: container
+    - __meta_kubernetes_service_name
+    target_label: service
   - source_labels:
     - __meta_kubernetes_pod_name
     target_label: pod
-  - target_label: job
-    replacement: default/testpodmonitor1
+  - source_labels:
+    - __meta_kubernetes_pod_container_name
+    target_label: container
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: job
+    replacement: ${1}
   - target_label: endpoint
     replacement: web
   - source_labels:
@@ -5592,8 +7819,7 @@ scrape_configs:
     - __tmp_hash
     regex: $(SHARD)
     action: keep
-  label_name_length_limit: %d
-  metric_relabel_configs: []
+%s  metric_relabel_configs: []
 alerting:
   alert_relabel_configs:
   - action: labeldrop
@@ -5602,61 +7828,61 @@ alerting:
 `
 
 	for _, tc := range []struct {
-		version                      string
-		enforcedLabelNameLengthLimit int
-		labelNameLengthLimit         int
-		expected                     string
+		name                   string
+		version                string
+		scrapeProtocols        []monitoringv1.ScrapeProtocol
+		monitorScrapeProtocols []monitoringv1.ScrapeProtocol
+		scrapeNativeHistograms *bool
+		expected               string
 	}{
 		{
-			version:                      "v2.26.0",
-			enforcedLabelNameLengthLimit: -1,
-			labelNameLengthLimit:         -1,
-			expected:                     expectNoLimit,
-		},
-		{
-			version:                      "v2.27.0",
-			enforcedLabelNameLengthLimit: -1,
-			labelNameLengthLimit:         -1,
-			expected:                     expectNoLimit,
-		},
-		{
-			version:                      "v2.26.0",
-			enforcedLabelNameLengthLimit: 1000,
-			labelNameLengthLimit:         -1,
-			expected:                     expectNoLimit,
-		},
-		{
-			version:                      "v2.27.0",
-			enforcedLabelNameLengthLimit: 1000,
-			labelNameLengthLimit:         -1,
-			expected:                     fmt.Sprintf(expectLimit, 1000),
-		},
-		{
-			version:                      "v2.26.0",
-			enforcedLabelNameLengthLimit: 1000,
-			labelNameLengthLimit:         2000,
-			expected:                     expectNoLimit,
+			name:     "not set",
+			version:  "v2.49.0",
+			expected: fmt.Sprintf(expected, "", ""),
 		},
 		{
-			version:                      "v2.27.0",
-			enforcedLabelNameLengthLimit: 1000,
-			labelNameLengthLimit:         2000,
-			expected:                     fmt.Sprintf(expectLimit, 1000),
+			name:    "global and monitor values",
+			version: "v2.49.0",
+			scrapeProtocols: []monitoringv1.ScrapeProtocol{
+				monitoringv1.PrometheusText0_0_4,
+			},
+			monitorScrapeProtocols: []monitoringv1.ScrapeProtocol{
+				monitoringv1.OpenMetricsText1_0_0,
+				monitoringv1.PrometheusText0_0_4,
+			},
+			expected: fmt.Sprintf(expected, `  scrape_protocols:
+  - PrometheusText0.0.4
+`, `  scrape_protocols:
+  - OpenMetricsText1.0.0
+  - PrometheusText0.0.4
+`),
 		},
 		{
-			version:                      "v2.26.0",
-			enforcedLabelNameLengthLimit: 1000,
-			labelNameLengthLimit:         500,
-			expected:                     expectNoLimit,
+			name:    "scrapeNativeHistograms takes precedence",
+			version: "v2.49.0",
+			monitorScrapeProtocols: []monitoringv1.ScrapeProtocol{
+				monitoringv1.PrometheusText0_0_4,
+			},
+			scrapeNativeHistograms: pointer.Bool(false),
+			expected: fmt.Sprintf(expected, "", `  scrape_protocols:
+  - OpenMetricsText1.0.0
+  - OpenMetricsText0.0.1
+  - PrometheusText0.0.4
+`),
 		},
 		{
-			version:                      "v2.27.0",
-			enforcedLabelNameLengthLimit: 1000,
-			labelNameLengthLimit:         500,
-			expected:                     fmt.Sprintf(expectLimit, 500),
+			name:    "unsupported version",
+			version: "v2.48.0",
+			scrapeProtocols: []monitoringv1.ScrapeProtocol{
+				monitoringv1.PrometheusText0_0_4,
+			},
+			monitorScrapeProtocols: []monitoringv1.ScrapeProtocol{
+				monitoringv1.PrometheusProto,
+			},
+			expected: fmt.Sprintf(expected, "", ""),
 		},
 	} {
-		t.Run(fmt.Sprintf("%s enforcedLabelNameLengthLimit(%d) labelNameLengthLimit(%d)", tc.version, tc.enforcedLabelNameLengthLimit, tc.labelNameLengthLimit), func(t *testing.T) {
+		t.Run(tc.name, func(t *testing.T) {
 			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
 
 			prometheus := monitoringv1.Prometheus{
@@ -5665,7 +7891,8 @@ alerting:
 					Namespace: "default",
 				},
 				Spec: monitoringv1.PrometheusSpec{
-					Version: tc.version,
+					Version:         tc.version,
+					ScrapeProtocols: tc.scrapeProtocols,
 					ServiceMonitorSelector: &metav1.LabelSelector{
 						MatchLabels: map[string]string{
 							"group": "group1",
@@ -5673,39 +7900,35 @@ alerting:
 					},
 				},
 			}
-			if tc.enforcedLabelNameLengthLimit >= 0 {
-				i := uint64(tc.enforcedLabelNameLengthLimit)
-				prometheus.Spec.EnforcedLabelNameLengthLimit = &i
-			}
 
-			podMonitor := monitoringv1.PodMonitor{
+			serviceMonitor := monitoringv1.ServiceMonitor{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      "testpodmonitor1",
+					Name:      "testservicemonitor1",
 					Namespace: "default",
 					Labels: map[string]string{
 						"group": "group1",
 					},
 				},
-				Spec: monitoringv1.PodMonitorSpec{
-					PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{
+				Spec: monitoringv1.ServiceMonitorSpec{
+					ScrapeProtocols: tc.monitorScrapeProtocols,
+					Endpoints: []monitoringv1.Endpoint{
 						{
-							Port:     "web",
-							Interval: "30s",
+							Port:                   "web",
+							Interval:               "30s",
+							ScrapeNativeHistograms: tc.scrapeNativeHistograms,
 						},
 					},
 				},
 			}
-			if tc.labelNameLengthLimit >= 0 {
-				podMonitor.Spec.LabelNameLengthLimit = uint64(tc.labelNameLengthLimit)
-			}
 
 			cfg, err := cg.GenerateConfig(
 				&prometheus,
-				nil,
-				map[string]*monitoringv1.PodMonitor{
-					"testpodmonitor1": &podMonitor,
+				map[string]*monitoringv1.ServiceMonitor{
+					"testservicemonitor1": &serviceMonitor,
 				},
 				nil,
+				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
@@ -5725,8 +7948,8 @@ alerting:
 	}
 }
 
-func TestLabelValueLengthLimits(t *testing.T) {
-	expectNoLimit := `global:
+func TestServiceDiscoveryRole(t *testing.T) {
+	expected := `global:
   evaluation_interval: 30s
   scrape_interval: 30s
   external_labels:
@@ -5734,141 +7957,122 @@ func TestLabelValueLengthLimits(t *testing.T) {
     prometheus_replica: $(POD_NAME)
 rule_files: []
 scrape_configs:
-- job_name: probe/default/testprobe1
-  honor_timestamps: true
-  metrics_path: /probe
-  scheme: http
-  proxy_url: socks://myproxy:9095
-  params:
-    module:
-    - http_2xx
-  static_configs:
-  - targets:
-    - prometheus.io
-    - promcon.io
-    labels:
-      namespace: default
-      static: label
+- job_name: serviceMonitor/default/testservicemonitor1/0
+  honor_labels: false
+  kubernetes_sd_configs:
+  - role: endpoints
+    namespaces:
+      names:
+      - default
+  scrape_interval: 30s
   relabel_configs:
   - source_labels:
     - job
     target_label: __tmp_prometheus_job_name
+  - action: keep
+    source_labels:
+    - __meta_kubernetes_endpoint_port_name
+    regex: web
   - source_labels:
-    - __address__
-    target_label: __param_target
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Node;(.*)
+    replacement: ${1}
+    target_label: node
   - source_labels:
-    - __param_target
-    target_label: instance
-  - target_label: __address__
-    replacement: blackbox.exporter.io
-  metric_relabel_configs: []
-alerting:
-  alert_relabel_configs:
-  - action: labeldrop
-    regex: prometheus_replica
-  alertmanagers: []
-`
-
-	expectLimit := `global:
-  evaluation_interval: 30s
-  scrape_interval: 30s
-  external_labels:
-    prometheus: default/test
-    prometheus_replica: $(POD_NAME)
-rule_files: []
-scrape_configs:
-- job_name: probe/default/testprobe1
-  honor_timestamps: true
-  metrics_path: /probe
-  scheme: http
-  proxy_url: socks://myproxy:9095
-  params:
-    module:
-    - http_2xx
-  label_value_length_limit: %d
-  static_configs:
-  - targets:
-    - prometheus.io
-    - promcon.io
-    labels:
-      namespace: default
-      static: label
-  relabel_configs:
+    - __meta_kubernetes_endpoint_address_target_kind
+    - __meta_kubernetes_endpoint_address_target_name
+    separator: ;
+    regex: Pod;(.*)
+    replacement: ${1}
+    target_label: pod
   - source_labels:
-    - job
-    target_label: __tmp_prometheus_job_name
+    - __meta_kubernetes_namespace
+    target_label: namespace
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: service
+  - source_labels:
+    - __meta_kubernetes_pod_name
+    target_label: pod
+  - source_labels:
+    - __meta_kubernetes_pod_container_name
+    target_label: container
+  - source_labels:
+    - __meta_kubernetes_service_name
+    target_label: job
+    replacement: ${1}
+  - target_label: endpoint
+    replacement: web
   - source_labels:
     - __address__
-    target_label: __param_target
+    target_label: __tmp_hash
+    modulus: 1
+    action: hashmod
   - source_labels:
-    - __param_target
-    target_label: instance
-  - target_label: __address__
-    replacement: blackbox.exporter.io
+    - __tmp_hash
+    regex: $(SHARD)
+    action: keep
   metric_relabel_configs: []
 alerting:
   alert_relabel_configs:
   - action: labeldrop
     regex: prometheus_replica
-  alertmanagers: []
+  alertmanagers:
+  - path_prefix: /
+    scheme: http
+    kubernetes_sd_configs:
+    - role: endpoints
+      namespaces:
+        names:
+        - default
+    relabel_configs:
+    - action: keep
+      source_labels:
+      - __meta_kubernetes_service_name
+      regex: alertmanager-main
+    - action: keep
+      source_labels:
+      - __meta_kubernetes_endpoint_port_name
+      regex: web
 `
+	endpointSlice := strings.NewReplacer(
+		"role: endpoints", "role: endpointslice",
+		"__meta_kubernetes_endpoint_", "__meta_kubernetes_endpointslice_",
+	).Replace(expected)
 
 	for _, tc := range []struct {
-		version                       string
-		enforcedLabelValueLengthLimit int
-		labelValueLengthLimit         int
-		expected                      string
+		name     string
+		version  string
+		role     *monitoringv1.ServiceDiscoveryRole
+		expected string
 	}{
 		{
-			version:                       "v2.26.0",
-			enforcedLabelValueLengthLimit: -1,
-			labelValueLengthLimit:         -1,
-			expected:                      expectNoLimit,
-		},
-		{
-			version:                       "v2.27.0",
-			enforcedLabelValueLengthLimit: -1,
-			labelValueLengthLimit:         -1,
-			expected:                      expectNoLimit,
-		},
-		{
-			version:                       "v2.26.0",
-			enforcedLabelValueLengthLimit: 1000,
-			labelValueLengthLimit:         -1,
-			expected:                      expectNoLimit,
-		},
-		{
-			version:                       "v2.27.0",
-			enforcedLabelValueLengthLimit: 1000,
-			labelValueLengthLimit:         -1,
-			expected:                      fmt.Sprintf(expectLimit, 1000),
-		},
-		{
-			version:                       "v2.26.0",
-			enforcedLabelValueLengthLimit: 1000,
-			labelValueLengthLimit:         2000,
-			expected:                      expectNoLimit,
+			name:     "default",
+			version:  "v2.21.0",
+			expected: expected,
 		},
 		{
-			version:                       "v2.27.0",
-			enforcedLabelValueLengthLimit: 1000,
-			labelValueLengthLimit:         2000,
-			expected:                      fmt.Sprintf(expectLimit, 1000),
+			name:     "endpoints",
+			version:  "v2.21.0",
+			role:     serviceDiscoveryRole(monitoringv1.EndpointsRole),
+			expected: expected,
 		},
 		{
-			version:                       "v2.26.0",
-			enforcedLabelValueLengthLimit: 1000,
-			labelValueLengthLimit:         500,
-			expected:                      expectNoLimit,
+			name:     "endpointslice",
+			version:  "v2.21.0",
+			role:     serviceDiscoveryRole(monitoringv1.EndpointSliceRole),
+			expected: endpointSlice,
 		},
 		{
-			version:                       "v2.27.0",
-			enforcedLabelValueLengthLimit: 1000,
-			labelValueLengthLimit:         500,
-			expected:                      fmt.Sprintf(expectLimit, 500),
+			name:     "endpointslice with unsupported version",
+			version:  "v2.20.0",
+			role:     serviceDiscoveryRole(monitoringv1.EndpointSliceRole),
+			expected: expected,
 		},
 	} {
-		t.Run(fmt.Sprintf("%s enforcedLabelValueLengthLimit(%d) labelValueLengthLimit(%d)", tc.version, tc.enforcedLabelValueLengthLimit, tc.labelValueLengthLimit), func(t *testing.T) {
+		t.Run(tc.name, func(t *testing.T) {
 			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
 
 			prometheus := monitoringv1.Prometheus{
@@ -5877,59 +8081,51 @@ alerting:
 					Namespace: "default",
 				},
 				Spec: monitoringv1.PrometheusSpec{
-					Version: tc.version,
+					Version:              tc.version,
+					ServiceDiscoveryRole: tc.role,
 					ServiceMonitorSelector: &metav1.LabelSelector{
 						MatchLabels: map[string]string{
 							"group": "group1",
 						},
 					},
+					Alerting: &monitoringv1.AlertingSpec{
+						Alertmanagers: []monitoringv1.AlertmanagerEndpoints{
+							{
+								Name:      "alertmanager-main",
+								Namespace: "default",
+								Port:      intstr.FromString("web"),
+							},
+						},
+					},
 				},
 			}
-			if tc.enforcedLabelValueLengthLimit >= 0 {
-				i := uint64(tc.enforcedLabelValueLengthLimit)
-				prometheus.Spec.EnforcedLabelValueLengthLimit = &i
-			}
 
-			probe := monitoringv1.Probe{
+			serviceMonitor := monitoringv1.ServiceMonitor{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      "testprobe1",
-					Namespace: "default",
-					Labels: map[string]string{
-						"group": "group1",
-					},
-				},
-				Spec: monitoringv1.ProbeSpec{
-					ProberSpec: monitoringv1.ProberSpec{
-						Scheme:   "http",
-						URL:      "blackbox.exporter.io",
-						Path:     "/probe",
-						ProxyURL: "socks://myproxy:9095",
-					},
-					Module: "http_2xx",
-					Targets: monitoringv1.ProbeTargets{
-						StaticConfig: &monitoringv1.ProbeTargetStaticConfig{
-							Targets: []string{
-								"prometheus.io",
-								"promcon.io",
-							},
-							Labels: map[string]string{
-								"static": "label",
-							},
+					Name:      "testservicemonitor1",
+					Namespace: "default",
+					Labels: map[string]string{
+						"group": "group1",
+					},
+				},
+				Spec: monitoringv1.ServiceMonitorSpec{
+					Endpoints: []monitoringv1.Endpoint{
+						{
+							Port:     "web",
+							Interval: "30s",
 						},
 					},
 				},
 			}
-			if tc.labelValueLengthLimit >= 0 {
-				probe.Spec.LabelValueLengthLimit = uint64(tc.labelValueLengthLimit)
-			}
 
 			cfg, err := cg.GenerateConfig(
 				&prometheus,
+				map[string]*monitoringv1.ServiceMonitor{
+					"testservicemonitor1": &serviceMonitor,
+				},
+				nil,
 				nil,
 				nil,
-				map[string]*monitoringv1.Probe{
-					"testprobe1": &probe,
-				},
 				&assets.Store{},
 				nil,
 				nil,
@@ -5949,81 +8145,12 @@ alerting:
 	}
 }
 
-func TestBodySizeLimits(t *testing.T) {
-	expectNoLimit := `global:
-  evaluation_interval: 30s
-  scrape_interval: 30s
-  external_labels:
-    prometheus: default/test
-    prometheus_replica: $(POD_NAME)
-rule_files: []
-scrape_configs:
-- job_name: serviceMonitor/default/testservicemonitor1/0
-  honor_labels: false
-  kubernetes_sd_configs:
-  - role: endpoints
-    namespaces:
-      names:
-      - default
-  scrape_interval: 30s
-  relabel_configs:
-  - source_labels:
-    - job
-    target_label: __tmp_prometheus_job_name
-  - action: keep
-    source_labels:
-    - __meta_kubernetes_endpoint_port_name
-    regex: web
-  - source_labels:
-    - __meta_kubernetes_endpoint_address_target_kind
-    - __meta_kubernetes_endpoint_address_target_name
-    separator: ;
-    regex: Node;(.*)
-    replacement: ${1}
-    target_label: node
-  - source_labels:
-    - __meta_kubernetes_endpoint_address_target_kind
-    - __meta_kubernetes_endpoint_address_target_name
-    separator: ;
-    regex: Pod;(.*)
-    replacement: ${1}
-    target_label: pod
-  - source_labels:
-    - __meta_kubernetes_namespace
-    target_label: namespace
-  - source_labels:
-    - __meta_kubernetes_service_name
-    target_label: service
-  - source_labels:
-    - __meta_kubernetes_pod_name
-    target_label: pod
-  - source_labels:
-    - __meta_kubernetes_pod_container_name
-    target_label: container
-  - source_labels:
-    - __meta_kubernetes_service_name
-    target_label: job
-    replacement: ${1}
-  - target_label: endpoint
-    replacement: web
-  - source_labels:
-    - __address__
-    target_label: __tmp_hash
-    modulus: 1
-    action: hashmod
-  - source_labels:
-    - __tmp_hash
-    regex: $(SHARD)
-    action: keep
-  metric_relabel_configs: []
-alerting:
-  alert_relabel_configs:
-  - action: labeldrop
-    regex: prometheus_replica
-  alertmanagers: []
-`
+func serviceDiscoveryRole(role monitoringv1.ServiceDiscoveryRole) *monitoringv1.ServiceDiscoveryRole {
+	return &role
+}
 
-	expectLimit := `global:
+func TestHTTPClientOptions(t *testing.T) {
+	expected := `global:
   evaluation_interval: 30s
   scrape_interval: 30s
   external_labels:
@@ -6039,7 +8166,7 @@ scrape_configs:
       names:
       - default
   scrape_interval: 30s
-  relabel_configs:
+%s  relabel_configs:
   - source_labels:
     - job
     target_label: __tmp_prometheus_job_name
@@ -6088,7 +8215,6 @@ scrape_configs:
     - __tmp_hash
     regex: $(SHARD)
     action: keep
-  body_size_limit: %s
   metric_relabel_configs: []
 alerting:
   alert_relabel_configs:
@@ -6098,48 +8224,43 @@ alerting:
 `
 
 	for _, tc := range []struct {
-		version               string
-		enforcedBodySizeLimit string
-		expected              string
-		expectedErr           error
+		name            string
+		version         string
+		followRedirects *bool
+		enableHTTP2     *bool
+		expected        string
 	}{
 		{
-			version:               "v2.27.0",
-			enforcedBodySizeLimit: "1000MB",
-			expected:              expectNoLimit,
-		},
-		{
-			version:               "v2.28.0",
-			enforcedBodySizeLimit: "1000MB",
-			expected:              fmt.Sprintf(expectLimit, "1000MB"),
-		},
-		{
-			version:               "v2.28.0",
-			enforcedBodySizeLimit: "",
-			expected:              expectNoLimit,
-		},
-		{
-			version:               "v2.28.0",
-			enforcedBodySizeLimit: "100",
-			expectedErr:           errors.New("invalid enforcedBodySizeLimit value specified: units: unknown unit  in 100"),
+			name:     "not set",
+			version:  "v2.35.0",
+			expected: fmt.Sprintf(expected, ""),
 		},
 		{
-			version:               "v2.28.0",
-			enforcedBodySizeLimit: "200kb",
-			expectedErr:           errors.New("invalid enforcedBodySizeLimit value specified: units: unknown unit kb in 200kb"),
+			name:            "both set",
+			version:         "v2.35.0",
+			followRedirects: pointer.Bool(false),
+			enableHTTP2:     pointer.Bool(false),
+			expected: fmt.Sprintf(expected, `  follow_redirects: false
+  enable_http2: false
+`),
 		},
 		{
-			version:               "v2.28.0",
-			enforcedBodySizeLimit: "300 MB",
-			expectedErr:           errors.New("invalid enforcedBodySizeLimit value specified: units: unknown unit  MB in 300 MB"),
+			name:            "enableHttp2 unsupported",
+			version:         "v2.34.0",
+			followRedirects: pointer.Bool(true),
+			enableHTTP2:     pointer.Bool(false),
+			expected: fmt.Sprintf(expected, `  follow_redirects: true
+`),
 		},
 		{
-			version:               "v2.28.0",
-			enforcedBodySizeLimit: "150M",
-			expectedErr:           errors.New("invalid enforcedBodySizeLimit value specified: units: unknown unit M in 150M"),
+			name:            "unsupported version",
+			version:         "v2.25.0",
+			followRedirects: pointer.Bool(false),
+			enableHTTP2:     pointer.Bool(false),
+			expected:        fmt.Sprintf(expected, ""),
 		},
 	} {
-		t.Run(fmt.Sprintf("%s enforcedBodySizeLimit(%s)", tc.version, tc.enforcedBodySizeLimit), func(t *testing.T) {
+		t.Run(tc.name, func(t *testing.T) {
 			cg := NewConfigGenerator(log.NewLogfmtLogger(os.Stdout))
 
 			prometheus := monitoringv1.Prometheus{
@@ -6156,10 +8277,6 @@ alerting:
 					},
 				},
 			}
-			if tc.enforcedBodySizeLimit != "" {
-				i := tc.enforcedBodySizeLimit
-				prometheus.Spec.EnforcedBodySizeLimit = i
-			}
 
 			serviceMonitor := monitoringv1.ServiceMonitor{
 				ObjectMeta: metav1.ObjectMeta{
@@ -6172,8 +8289,10 @@ alerting:
 				Spec: monitoringv1.ServiceMonitorSpec{
 					Endpoints: []monitoringv1.Endpoint{
 						{
-							Port:     "web",
-							Interval: "30s",
+							Port:            "web",
+							Interval:        "30s",
+							FollowRedirects: tc.followRedirects,
+							EnableHttp2:     tc.enableHTTP2,
 						},
 					},
 				},
@@ -6186,25 +8305,177 @@ alerting:
 				},
 				nil,
 				nil,
+				nil,
 				&assets.Store{},
 				nil,
 				nil,
 				nil,
 				nil,
 			)
+			if err != nil {
+				t.Fatal(err)
+			}
 
-			if tc.expectedErr != nil {
-				if tc.expectedErr.Error() != err.Error() {
-					t.Logf("\n%s", pretty.Compare(tc.expectedErr.Error(), err.Error()))
-					t.Fatal("expected error and actual error do not match")
-				}
-			} else {
-				result := string(cfg)
-				if tc.expected != result {
-					t.Logf("\n%s", pretty.Compare(tc.expected, result))
-					t.Fatal("expected Prometheus configuration and actual configuration do not match")
-				}
+			result := string(cfg)
+			if tc.expected != result {
+				t.Logf("\n%s", pretty.Compare(tc.expected, result))
+				t.Fatal("expected Prometheus configuration and actual configuration do not match")
+			}
+		})
+	}
+}
+
+func TestExemplarsConfig(t *testing.T) {
+	for _, tc := range []struct {
+		version   string
+		exemplars *monitoringv1.Exemplars
+		expected  string
+	}{
+		{
+			version:   "v2.28.0",
+			exemplars: &monitoringv1.Exemplars{MaxSize: pointer.Int64Ptr(5000000)},
+			expected:  "",
+		},
+		{
+			version:   "v2.29.0",
+			exemplars: &monitoringv1.Exemplars{},
+			expected:  "",
+		},
+		{
+			version:   "v2.29.0",
+			exemplars: &monitoringv1.Exemplars{MaxSize: pointer.Int64Ptr(5000000)},
+			expected: `storage:
+  exemplars:
+    max_exemplars: 5000000
+`,
+		},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			cg := NewConfigGenerator(log.NewNopLogger())
+			cfg, err := cg.GenerateConfig(
+				&monitoringv1.Prometheus{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "default",
+					},
+					Spec: monitoringv1.PrometheusSpec{
+						Version:   tc.version,
+						Exemplars: tc.exemplars,
+					},
+				},
+				nil,
+				nil,
+				nil,
+				nil,
+				&assets.Store{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := `global:
+  evaluation_interval: 30s
+  scrape_interval: 30s
+  external_labels:
+    prometheus: default/test
+    prometheus_replica: $(POD_NAME)
+rule_files: []
+scrape_configs: []
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: prometheus_replica
+  alertmanagers: []
+` + tc.expected
+
+			result := string(cfg)
+			if diff := cmp.Diff(expected, result); diff != "" {
+				t.Fatalf("Unexpected result got(-) want(+)\n%s\n", diff)
 			}
 		})
 	}
 }
+
+func TestGenerateConfigFragmentCache(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: monitoringv1.PrometheusSpec{},
+	}
+
+	smon := &monitoringv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test",
+			Namespace:       "default",
+			UID:             "1234",
+			ResourceVersion: "1",
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			Endpoints: []monitoringv1.Endpoint{
+				{
+					Port: "web",
+				},
+			},
+		},
+	}
+
+	cg := NewConfigGenerator(log.NewNopLogger())
+	generate := func() string {
+		t.Helper()
+
+		cfg, err := cg.GenerateConfig(
+			p,
+			map[string]*monitoringv1.ServiceMonitor{"default/test": smon},
+			nil,
+			nil,
+			nil,
+			&assets.Store{},
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return string(cfg)
+	}
+
+	if cfg := generate(); !strings.Contains(cfg, "regex: web") {
+		t.Fatalf("expected the configuration to scrape the %q port, got:\n%s", "web", cfg)
+	}
+
+	// The fragment is reused as long as the resource version doesn't change.
+	smon.Spec.Endpoints[0].Port = "metrics"
+	if cfg := generate(); !strings.Contains(cfg, "regex: web") {
+		t.Fatalf("expected the cached fragment to be used, got:\n%s", cfg)
+	}
+
+	smon.ResourceVersion = "2"
+	if cfg := generate(); !strings.Contains(cfg, "regex: metrics") {
+		t.Fatalf("expected the fragment to be regenerated after an update of the ServiceMonitor, got:\n%s", cfg)
+	}
+
+	// Changes of the Prometheus spec invalidate all the fragments.
+	smon.Spec.Endpoints[0].Port = "http"
+	p.Spec.EnforcedNamespaceLabel = "namespace"
+	if cfg := generate(); !strings.Contains(cfg, "regex: http") {
+		t.Fatalf("expected the fragment to be regenerated after an update of the Prometheus spec, got:\n%s", cfg)
+	}
+
+	// The fragments of the monitors which aren't selected anymore are
+	// dropped.
+	if _, err := cg.GenerateConfig(p, nil, nil, nil, nil, &assets.Store{}, nil, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(cg.fragments[dependentFor(p)]); n != 0 {
+		t.Fatalf("expected no cached fragment, got %d", n)
+	}
+}
@@ -26,10 +26,13 @@ import (
 	thanostypes "github.com/thanos-io/thanos/pkg/store/storepb"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
 	namespacelabeler "github.com/prometheus-operator/prometheus-operator/pkg/namespace-labeler"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/ghodss/yaml"
 	"github.com/go-kit/log"
@@ -45,6 +48,12 @@ const labelPrometheusName = "prometheus-name"
 // large buffer.
 var maxConfigMapDataSize = int(float64(v1.MaxSecretSize) * 0.5)
 
+// MaxRuleFileSize returns the maximum size of the rule file generated from a
+// PrometheusRule object. Larger rule files can't be stored in a ConfigMap.
+func MaxRuleFileSize() int {
+	return maxConfigMapDataSize
+}
+
 func (c *Operator) createOrUpdateRuleConfigMaps(ctx context.Context, p *monitoringv1.Prometheus) ([]string, error) {
 	cClient := c.kclient.CoreV1().ConfigMaps(p.Namespace)
 
@@ -91,32 +100,10 @@ func (c *Operator) createOrUpdateRuleConfigMaps(ctx context.Context, p *monitori
 	}
 
 	newConfigMapNames := []string{}
+	applied := map[string]struct{}{}
 	for _, cm := range newConfigMaps {
 		newConfigMapNames = append(newConfigMapNames, cm.Name)
-	}
-
-	if len(currentConfigMaps) == 0 {
-		level.Debug(c.logger).Log(
-			"msg", "no PrometheusRule configmap found, creating new one",
-			"namespace", p.Namespace,
-			"prometheus", p.Name,
-		)
-		for _, cm := range newConfigMaps {
-			_, err = cClient.Create(ctx, &cm, metav1.CreateOptions{})
-			if err != nil {
-				return nil, errors.Wrapf(err, "failed to create ConfigMap '%v'", cm.Name)
-			}
-		}
-		return newConfigMapNames, nil
-	}
-
-	// Simply deleting old ConfigMaps and creating new ones for now. Could be
-	// replaced by logic that only deletes obsolete ConfigMaps in the future.
-	for _, cm := range currentConfigMaps {
-		err := cClient.Delete(ctx, cm.Name, metav1.DeleteOptions{})
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to delete current ConfigMap '%v'", cm.Name)
-		}
+		applied[cm.Name] = struct{}{}
 	}
 
 	level.Debug(c.logger).Log(
@@ -125,9 +112,20 @@ func (c *Operator) createOrUpdateRuleConfigMaps(ctx context.Context, p *monitori
 		"prometheus", p.Name,
 	)
 	for _, cm := range newConfigMaps {
-		_, err = cClient.Create(ctx, &cm, metav1.CreateOptions{})
+		if err := k8sutil.ApplyConfigMap(ctx, cClient, &cm, operator.ApplyConflictEventHandler(c.eventRecorder, p)); err != nil {
+			return nil, errors.Wrapf(err, "failed to apply ConfigMap '%v'", cm.Name)
+		}
+	}
+
+	// Delete the ConfigMaps which aren't needed anymore.
+	for _, cm := range currentConfigMaps {
+		if _, found := applied[cm.Name]; found {
+			continue
+		}
+
+		err := cClient.Delete(ctx, cm.Name, metav1.DeleteOptions{})
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to create new ConfigMap '%v'", cm.Name)
+			return nil, errors.Wrapf(err, "failed to delete obsolete ConfigMap '%v'", cm.Name)
 		}
 	}
 
@@ -225,6 +223,81 @@ func (c *Operator) selectRules(p *monitoringv1.Prometheus, namespaces []string)
 	return rules, nil
 }
 
+// ListSelectedRules returns, for each Prometheus object selecting the given
+// PrometheusRule, the other PrometheusRule objects selected by the same
+// Prometheus object. The result is keyed by the Prometheus object's key.
+func (c *Operator) ListSelectedRules(promRule *monitoringv1.PrometheusRule) (map[string][]*monitoringv1.PrometheusRule, error) {
+	var prometheuses []*monitoringv1.Prometheus
+	err := c.promInfs.ListAll(labels.Everything(), func(obj interface{}) {
+		prometheuses = append(prometheuses, obj.(*monitoringv1.Prometheus))
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list Prometheus objects")
+	}
+
+	selected := map[string][]*monitoringv1.PrometheusRule{}
+	for _, p := range prometheuses {
+		ruleSelector, err := metav1.LabelSelectorAsSelector(p.Spec.RuleSelector)
+		if err != nil {
+			return nil, errors.Wrap(err, "convert rule label selector to selector")
+		}
+
+		if !ruleSelector.Matches(labels.Set(promRule.Labels)) {
+			continue
+		}
+
+		namespaces, err := c.selectRuleNamespaces(p)
+		if err != nil {
+			return nil, err
+		}
+
+		var found bool
+		for _, ns := range namespaces {
+			if ns == promRule.Namespace {
+				found = true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		pKey, ok := c.keyFunc(p)
+		if !ok {
+			continue
+		}
+
+		for _, ns := range namespaces {
+			err := c.ruleInfs.ListAllByNamespace(ns, ruleSelector, func(obj interface{}) {
+				r := obj.(*monitoringv1.PrometheusRule)
+				if r.Namespace == promRule.Namespace && r.Name == promRule.Name {
+					return
+				}
+				selected[pKey] = append(selected[pKey], r)
+			})
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list PrometheusRule objects in namespace %s", ns)
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+// ListNamespaceRules returns the PrometheusRule objects of the given
+// namespace.
+func (c *Operator) ListNamespaceRules(namespace string) ([]*monitoringv1.PrometheusRule, error) {
+	var rules []*monitoringv1.PrometheusRule
+	err := c.ruleInfs.ListAllByNamespace(namespace, labels.Everything(), func(obj interface{}) {
+		rules = append(rules, obj.(*monitoringv1.PrometheusRule))
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list PrometheusRule objects in namespace %s", namespace)
+	}
+
+	return rules, nil
+}
+
 // makeRulesConfigMaps takes a Prometheus configuration and rule files and
 // returns a list of Kubernetes ConfigMaps to be later on mounted into the
 // Prometheus instance.
@@ -336,7 +409,7 @@ func GenerateContent(promRule monitoringv1.PrometheusRuleSpec, logger log.Logger
 
 // ValidateRule takes PrometheusRuleSpec and validates it using the upstream prometheus rule validator
 func ValidateRule(promRule monitoringv1.PrometheusRuleSpec) []error {
-	for i, group := range promRule.Groups {
+	for _, group := range promRule.Groups {
 		if group.PartialResponseStrategy == "" {
 			continue
 		}
@@ -345,14 +418,30 @@ func ValidateRule(promRule monitoringv1.PrometheusRuleSpec) []error {
 				fmt.Errorf("invalid partial_response_strategy %s value", group.PartialResponseStrategy),
 			}
 		}
-		// reset this as the upstream prometheus rule validator
-		// is not aware of the partial_response_strategy field
-		promRule.Groups[i].PartialResponseStrategy = ""
 	}
-	content, err := yaml.Marshal(promRule)
+	content, err := marshalUpstreamRules(promRule)
 	if err != nil {
-		return []error{errors.Wrap(err, "failed to marshal content")}
+		return []error{err}
 	}
 	_, errs := rulefmt.Parse(content)
 	return errs
 }
+
+// marshalUpstreamRules returns the rule groups in the format of the upstream
+// Prometheus rule files.
+func marshalUpstreamRules(promRule monitoringv1.PrometheusRuleSpec) ([]byte, error) {
+	groups := make([]monitoringv1.RuleGroup, len(promRule.Groups))
+	for i, group := range promRule.Groups {
+		// reset this as the upstream prometheus rule loader
+		// is not aware of the partial_response_strategy field
+		group.PartialResponseStrategy = ""
+		groups[i] = group
+	}
+	promRule.Groups = groups
+
+	content, err := yaml.Marshal(promRule)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal content")
+	}
+	return content, nil
+}
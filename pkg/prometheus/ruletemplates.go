@@ -0,0 +1,104 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/template"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+// templateDefs are the variables defined by Prometheus when expanding the
+// labels and annotations of the alerts.
+var templateDefs = []string{
+	"{{$labels := .Labels}}",
+	"{{$externalLabels := .ExternalLabels}}",
+	"{{$externalURL := .ExternalURL}}",
+	"{{$value := .Value}}",
+}
+
+// ValidateRuleTemplates expands the label and annotation templates of the
+// alerting rules the same way as Prometheus does when the alerts fire, with
+// the static labels of the rule as $labels and 0 as $value. It returns one
+// error per template which fails to parse or to execute.
+//
+// The errors returned by the template functions (e.g. humanize called with a
+// non-numeric label value) depend on the data of the firing alerts and are
+// ignored.
+func ValidateRuleTemplates(promRule monitoringv1.PrometheusRuleSpec) []error {
+	var errs []error
+	for _, g := range promRule.Groups {
+		for i, r := range g.Rules {
+			if r.Alert == "" {
+				continue
+			}
+
+			data := template.AlertTemplateData(r.Labels, map[string]string{}, "", 0)
+			for _, f := range []struct {
+				kind      string
+				templates map[string]string
+			}{
+				{kind: "label", templates: r.Labels},
+				{kind: "annotation", templates: r.Annotations},
+			} {
+				keys := make([]string, 0, len(f.templates))
+				for k := range f.templates {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+
+				for _, k := range keys {
+					if err := expandRuleTemplate(r.Alert, f.templates[k], data); err != nil {
+						errs = append(errs, errors.Errorf("group %q, rule %d, %q: invalid %s %q: %v", g.Name, i+1, r.Alert, f.kind, k, err))
+					}
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+func expandRuleTemplate(name, text string, data interface{}) error {
+	expander := template.NewTemplateExpander(
+		context.Background(),
+		strings.Join(append(templateDefs, text), ""),
+		"__alert_"+name,
+		data,
+		model.Now(),
+		// The queries return no result: the templates must handle empty
+		// results anyway.
+		func(context.Context, string, time.Time) (promql.Vector, error) {
+			return promql.Vector{}, nil
+		},
+		&url.URL{},
+	)
+
+	_, err := expander.Expand()
+	if err != nil && strings.Contains(err.Error(), "error calling ") {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"strings"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestValidateRuleTemplates(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		rule     monitoringv1.Rule
+		expected []string
+	}{
+		{
+			name: "valid templates",
+			rule: monitoringv1.Rule{
+				Alert:  "Test",
+				Expr:   intstr.FromString("up == 0"),
+				Labels: map[string]string{"severity": "critical", "instance": "{{ $labels.instance }}"},
+				Annotations: map[string]string{
+					"summary":     "{{ $labels.instance }} of {{ $externalLabels.cluster }} is down",
+					"description": "{{ $value | humanize }} targets down, see {{ $externalURL }}.",
+					"total":       `{{ with query "count(up)" }}{{ . | first | value }}{{ end }}`,
+				},
+			},
+		},
+		{
+			name: "data-dependent function error",
+			rule: monitoringv1.Rule{
+				Alert:       "Test",
+				Expr:        intstr.FromString("up == 0"),
+				Annotations: map[string]string{"summary": "{{ $labels.value | humanize }}"},
+			},
+		},
+		{
+			name: "recording rule",
+			rule: monitoringv1.Rule{
+				Record: "job:up:sum",
+				Expr:   intstr.FromString("sum by (job) (up)"),
+				Labels: map[string]string{"foo": "{{ $labels.job"},
+			},
+		},
+		{
+			name: "parse error",
+			rule: monitoringv1.Rule{
+				Alert:       "Test",
+				Expr:        intstr.FromString("up == 0"),
+				Annotations: map[string]string{"summary": "{{ $labels.instance }"},
+			},
+			expected: []string{`group "test", rule 1, "Test": invalid annotation "summary"`},
+		},
+		{
+			name: "unknown field",
+			rule: monitoringv1.Rule{
+				Alert:       "Test",
+				Expr:        intstr.FromString("up == 0"),
+				Labels:      map[string]string{"instance": "{{ .Lables.instance }}"},
+				Annotations: map[string]string{"summary": "{{ $lables.instance }}"},
+			},
+			expected: []string{
+				`group "test", rule 1, "Test": invalid label "instance"`,
+				`group "test", rule 1, "Test": invalid annotation "summary"`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateRuleTemplates(monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{Name: "test", Rules: []monitoringv1.Rule{tc.rule}}},
+			})
+
+			if len(errs) != len(tc.expected) {
+				t.Fatalf("expected %d errors, got %v", len(tc.expected), errs)
+			}
+
+			for i, err := range errs {
+				if !strings.HasPrefix(err.Error(), tc.expected[i]) {
+					t.Fatalf("expected error starting with %q, got %q", tc.expected[i], err)
+				}
+			}
+		})
+	}
+}
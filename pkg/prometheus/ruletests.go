@@ -0,0 +1,559 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/rules"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"gopkg.in/yaml.v2"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+const (
+	// maxRuleTestEvaluations caps the number of rule evaluations performed
+	// by a single test group to bound the resources consumed by the unit
+	// tests.
+	maxRuleTestEvaluations = 10000
+	// maxRuleTestSeries caps the number of input series of a single test
+	// group.
+	maxRuleTestSeries = 1000
+	// maxRuleTestSamples caps the number of input samples of a single test
+	// group once the expanding notations (e.g. `1+1x10`) are applied.
+	maxRuleTestSamples = 100000
+	// maxRuleTestQuerySamples is the maximum number of samples that a
+	// single query of the tests can load into memory.
+	maxRuleTestQuerySamples = 50000
+)
+
+// ruleTests is the format of the rule unit tests. It follows the format
+// supported by `promtool test rules` except that the rules are read from the
+// PrometheusRule resource instead of the `rule_files` field.
+type ruleTests struct {
+	EvaluationInterval model.Duration  `yaml:"evaluation_interval,omitempty"`
+	Tests              []ruleTestGroup `yaml:"tests"`
+}
+
+type ruleTestGroup struct {
+	Name           string            `yaml:"name,omitempty"`
+	Interval       model.Duration    `yaml:"interval,omitempty"`
+	InputSeries    []ruleTestSeries  `yaml:"input_series,omitempty"`
+	AlertRuleTests []alertTestCase   `yaml:"alert_rule_test,omitempty"`
+	PromQLExpTests []promqlTestCase  `yaml:"promql_expr_test,omitempty"`
+	ExternalLabels map[string]string `yaml:"external_labels,omitempty"`
+	ExternalURL    string            `yaml:"external_url,omitempty"`
+}
+
+type ruleTestSeries struct {
+	Series string `yaml:"series"`
+	Values string `yaml:"values"`
+}
+
+type alertTestCase struct {
+	EvalTime  model.Duration `yaml:"eval_time"`
+	Alertname string         `yaml:"alertname"`
+	ExpAlerts []expAlert     `yaml:"exp_alerts"`
+}
+
+type expAlert struct {
+	ExpLabels      map[string]string `yaml:"exp_labels"`
+	ExpAnnotations map[string]string `yaml:"exp_annotations"`
+}
+
+type promqlTestCase struct {
+	Expr       string         `yaml:"expr"`
+	EvalTime   model.Duration `yaml:"eval_time"`
+	ExpSamples []expSample    `yaml:"exp_samples"`
+}
+
+type expSample struct {
+	Labels string  `yaml:"labels"`
+	Value  float64 `yaml:"value"`
+}
+
+// labelsAndAnnotations holds the labels and annotations of an alert.
+type labelsAndAnnotations []labelAndAnnotation
+
+type labelAndAnnotation struct {
+	Labels      labels.Labels
+	Annotations labels.Labels
+}
+
+func (la labelsAndAnnotations) Len() int      { return len(la) }
+func (la labelsAndAnnotations) Swap(i, j int) { la[i], la[j] = la[j], la[i] }
+func (la labelsAndAnnotations) Less(i, j int) bool {
+	diff := labels.Compare(la[i].Labels, la[j].Labels)
+	if diff != 0 {
+		return diff < 0
+	}
+	return labels.Compare(la[i].Annotations, la[j].Annotations) < 0
+}
+
+func (la labelsAndAnnotations) String() string {
+	if len(la) == 0 {
+		return "[]"
+	}
+
+	s := make([]string, 0, len(la))
+	for _, a := range la {
+		s = append(s, fmt.Sprintf("{labels: %s, annotations: %s}", a.Labels, a.Annotations))
+	}
+
+	return "[" + strings.Join(s, ", ") + "]"
+}
+
+// RunRuleTests evaluates the rule groups of the PrometheusRule against the
+// unit tests. The tests use the format of `promtool test rules` (without the
+// `rule_files` and `group_eval_order` fields). It returns the list of
+// failures, if any.
+func RunRuleTests(promRule monitoringv1.PrometheusRuleSpec, tests string) []error {
+	var rt ruleTests
+	if err := yaml.UnmarshalStrict([]byte(tests), &rt); err != nil {
+		return []error{errors.Wrap(err, "failed to unmarshal rule tests")}
+	}
+
+	if len(rt.Tests) == 0 {
+		return []error{errors.New("no rule tests found")}
+	}
+
+	if rt.EvaluationInterval == 0 {
+		rt.EvaluationInterval = model.Duration(time.Minute)
+	}
+
+	content, err := marshalUpstreamRules(promRule)
+	if err != nil {
+		return []error{err}
+	}
+
+	groupOrder := make(map[string]int, len(promRule.Groups))
+	for i, g := range promRule.Groups {
+		groupOrder[g.Name] = i
+	}
+
+	// The Prometheus rule manager can only load rules from files.
+	f, err := ioutil.TempFile("", "prometheus-rules-*.yaml")
+	if err != nil {
+		return []error{errors.Wrap(err, "failed to create rule file")}
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return []error{errors.Wrap(err, "failed to write rule file")}
+	}
+
+	var errs []error
+	for i, tg := range rt.Tests {
+		if tg.Interval == 0 {
+			tg.Interval = model.Duration(time.Minute)
+		}
+
+		name := tg.Name
+		if name == "" {
+			name = fmt.Sprintf("tests[%d]", i)
+		}
+
+		for _, err := range tg.test(time.Duration(rt.EvaluationInterval), groupOrder, f.Name()) {
+			errs = append(errs, errors.Wrapf(err, "%s", name))
+		}
+	}
+
+	return errs
+}
+
+// test runs the unit tests of the group against the given rule file.
+func (tg *ruleTestGroup) test(evalInterval time.Duration, groupOrder map[string]int, ruleFile string) []error {
+	mint := time.Unix(0, 0).UTC()
+	maxt := mint.Add(tg.maxEvalTime())
+
+	if n := int64(maxt.Sub(mint) / evalInterval); n > maxRuleTestEvaluations {
+		return []error{errors.Errorf("too many evaluations (%d > %d), reduce the evaluation time of the tests", n, maxRuleTestEvaluations)}
+	}
+
+	series, err := tg.inputSeries(mint)
+	if err != nil {
+		return []error{err}
+	}
+
+	st, err := newRuleTestStorage()
+	if err != nil {
+		return []error{err}
+	}
+	defer st.close()
+
+	engine := promql.NewEngine(promql.EngineOpts{
+		Logger:                   log.NewNopLogger(),
+		MaxSamples:               maxRuleTestQuerySamples,
+		Timeout:                  100 * time.Second,
+		NoStepSubqueryIntervalFn: func(int64) int64 { return int64(evalInterval / time.Millisecond) },
+	})
+	ctx := context.Background()
+
+	opts := &rules.ManagerOptions{
+		QueryFunc:  rules.EngineQueryFunc(engine, st.db),
+		Appendable: st.db,
+		Context:    ctx,
+		NotifyFunc: func(ctx context.Context, expr string, alerts ...*rules.Alert) {},
+		Logger:     log.NewNopLogger(),
+	}
+	m := rules.NewManager(opts)
+
+	groupsMap, errs := m.LoadGroups(evalInterval, labels.FromMap(tg.ExternalLabels), tg.ExternalURL, ruleFile)
+	if errs != nil {
+		return errs
+	}
+
+	// Evaluate the groups in the order of the PrometheusRule resource.
+	groups := make([]*rules.Group, 0, len(groupsMap))
+	for _, g := range groupsMap {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groupOrder[groups[i].Name()] < groupOrder[groups[j].Name()]
+	})
+
+	alertTests := make(map[model.Duration][]alertTestCase)
+	for _, at := range tg.AlertRuleTests {
+		alertTests[at.EvalTime] = append(alertTests[at.EvalTime], at)
+	}
+
+	for ts := mint; ts.Before(maxt) || ts.Equal(maxt); ts = ts.Add(evalInterval) {
+		if err := st.appendTill(ctx, ts, series); err != nil {
+			return append(errs, err)
+		}
+
+		for _, g := range groups {
+			g.Eval(ctx, ts)
+			for _, r := range g.Rules() {
+				if r.LastError() != nil {
+					errs = append(errs, errors.Errorf("rule: %s, time: %s, err: %v",
+						r.Name(), ts.Sub(mint), r.LastError()))
+				}
+			}
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+
+		// Check the alerts only at the evaluation timestamps which are
+		// closest to the expected evaluation times.
+		for evalTime, tests := range alertTests {
+			t := mint.Add(time.Duration(evalTime))
+			if t.Before(ts) || !t.Before(ts.Add(evalInterval)) {
+				continue
+			}
+
+			for _, at := range tests {
+				if err := at.check(groups); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	for _, tc := range tg.PromQLExpTests {
+		if err := tc.check(ctx, mint, engine, st.db); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// inputSeries parses the input series of the group. The number of series and
+// samples is checked before the values are expanded.
+func (tg *ruleTestGroup) inputSeries(mint time.Time) ([]*ruleTestInputSeries, error) {
+	if len(tg.InputSeries) > maxRuleTestSeries {
+		return nil, errors.Errorf("too many input series (%d > %d)", len(tg.InputSeries), maxRuleTestSeries)
+	}
+
+	var n int
+	for i, is := range tg.InputSeries {
+		n += countSeriesValues(is.Values)
+		if n > maxRuleTestSamples {
+			return nil, errors.Errorf("input_series[%d]: too many input samples (> %d)", i, maxRuleTestSamples)
+		}
+	}
+
+	series := make([]*ruleTestInputSeries, 0, len(tg.InputSeries))
+	for i, is := range tg.InputSeries {
+		lbls, vals, err := parser.ParseSeriesDesc(is.Series + " " + is.Values)
+		if err != nil {
+			return nil, errors.Wrapf(err, "input_series[%d]", i)
+		}
+
+		s := &ruleTestInputSeries{labels: lbls}
+		ts := mint
+		for _, v := range vals {
+			if !v.Omitted {
+				s.points = append(s.points, promql.Point{T: timestamp(ts), V: v.Value})
+			}
+			ts = ts.Add(time.Duration(tg.Interval))
+		}
+		series = append(series, s)
+	}
+
+	return series, nil
+}
+
+// countSeriesValues returns an upper bound of the number of samples described
+// by the values of an input series without expanding them.
+func countSeriesValues(values string) int {
+	var n int
+	for _, v := range strings.Fields(values) {
+		n++
+
+		// The `a+bxN`, `a-bxN` and `_xN` notations expand to N+1 values.
+		i := strings.LastIndex(v, "x")
+		if i < 0 || i == len(v)-1 {
+			continue
+		}
+
+		times, err := strconv.ParseUint(v[i+1:], 10, 32)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return maxRuleTestSamples + 1
+			}
+			continue
+		}
+
+		if times > maxRuleTestSamples {
+			return maxRuleTestSamples + 1
+		}
+		n += int(times)
+	}
+
+	return n
+}
+
+// timestamp returns the time in milliseconds since the Unix epoch.
+func timestamp(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// ruleTestInputSeries holds the samples of an input series.
+type ruleTestInputSeries struct {
+	labels labels.Labels
+	points []promql.Point
+}
+
+// ruleTestStorage is the temporary TSDB in which the input series and the
+// rule results are written during the unit tests.
+type ruleTestStorage struct {
+	db  *tsdb.DB
+	dir string
+}
+
+func newRuleTestStorage() (*ruleTestStorage, error) {
+	dir, err := ioutil.TempDir("", "prometheus-rule-tests-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the test storage directory")
+	}
+
+	// The samples are appended in time order during the evaluation which
+	// requires a long appendable window.
+	opts := tsdb.DefaultOptions()
+	opts.MinBlockDuration = int64(24 * time.Hour / time.Millisecond)
+	opts.MaxBlockDuration = int64(24 * time.Hour / time.Millisecond)
+	opts.WALSegmentSize = -1
+	opts.NoLockfile = true
+
+	db, err := tsdb.Open(dir, log.NewNopLogger(), nil, opts, tsdb.NewDBStats())
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, errors.Wrap(err, "failed to open the test storage")
+	}
+	db.DisableCompactions()
+
+	return &ruleTestStorage{db: db, dir: dir}, nil
+}
+
+// appendTill appends the samples of the input series until the given time
+// (included) and removes them from the series.
+func (st *ruleTestStorage) appendTill(ctx context.Context, t time.Time, series []*ruleTestInputSeries) error {
+	maxt := timestamp(t)
+
+	app := st.db.Appender(ctx)
+	for _, s := range series {
+		var i int
+		for ; i < len(s.points) && s.points[i].T <= maxt; i++ {
+			if _, err := app.Append(0, s.labels, s.points[i].T, s.points[i].V); err != nil {
+				_ = app.Rollback()
+				return errors.Wrapf(err, "failed to append the samples of %s", s.labels)
+			}
+		}
+		s.points = s.points[i:]
+	}
+
+	return app.Commit()
+}
+
+func (st *ruleTestStorage) close() {
+	st.db.Close()
+	os.RemoveAll(st.dir)
+}
+
+// maxEvalTime returns the max evaluation time among all the tests.
+func (tg *ruleTestGroup) maxEvalTime() time.Duration {
+	var maxd model.Duration
+	for _, alert := range tg.AlertRuleTests {
+		if alert.EvalTime > maxd {
+			maxd = alert.EvalTime
+		}
+	}
+	for _, pet := range tg.PromQLExpTests {
+		if pet.EvalTime > maxd {
+			maxd = pet.EvalTime
+		}
+	}
+
+	return time.Duration(maxd)
+}
+
+// check verifies that the firing alerts match the expected alerts.
+func (at *alertTestCase) check(groups []*rules.Group) error {
+	var got labelsAndAnnotations
+	for _, g := range groups {
+		for _, r := range g.Rules() {
+			ar, ok := r.(*rules.AlertingRule)
+			if !ok || ar.Name() != at.Alertname {
+				continue
+			}
+
+			for _, a := range ar.ActiveAlerts() {
+				if a.State != rules.StateFiring {
+					continue
+				}
+
+				got = append(got, labelAndAnnotation{
+					Labels:      append(labels.Labels{}, a.Labels...),
+					Annotations: append(labels.Labels{}, a.Annotations...),
+				})
+			}
+		}
+	}
+
+	var exp labelsAndAnnotations
+	for _, a := range at.ExpAlerts {
+		lbls := labels.FromMap(a.ExpLabels)
+		// The alertname label is always present on the alerts.
+		lbls = labels.NewBuilder(lbls).Set(labels.AlertName, at.Alertname).Labels()
+
+		exp = append(exp, labelAndAnnotation{
+			Labels:      lbls,
+			Annotations: labels.FromMap(a.ExpAnnotations),
+		})
+	}
+
+	sort.Sort(got)
+	sort.Sort(exp)
+
+	if len(got) == 0 && len(exp) == 0 {
+		return nil
+	}
+
+	if !reflect.DeepEqual(exp, got) {
+		return errors.Errorf("alertname: %s, time: %s,\n        exp:%v,\n        got:%v",
+			at.Alertname, time.Duration(at.EvalTime), exp, got)
+	}
+
+	return nil
+}
+
+// check verifies that the result of the PromQL expression matches the
+// expected samples.
+func (tc *promqlTestCase) check(ctx context.Context, mint time.Time, engine *promql.Engine, q storage.Queryable) error {
+	vec, err := instantQuery(ctx, tc.Expr, mint.Add(time.Duration(tc.EvalTime)), engine, q)
+	if err != nil {
+		return errors.Errorf("expr: %q, time: %s, err: %v", tc.Expr, time.Duration(tc.EvalTime), err)
+	}
+
+	type sample struct {
+		Labels labels.Labels
+		Value  float64
+	}
+
+	got := make([]sample, 0, len(vec))
+	for _, s := range vec {
+		got = append(got, sample{Labels: s.Metric, Value: s.V})
+	}
+
+	exp := make([]sample, 0, len(tc.ExpSamples))
+	for i, s := range tc.ExpSamples {
+		lbls, err := parser.ParseMetric(s.Labels)
+		if err != nil {
+			return errors.Errorf("expr: %q, time: %s, exp_samples[%d]: invalid labels %q: %v",
+				tc.Expr, time.Duration(tc.EvalTime), i, s.Labels, err)
+		}
+		exp = append(exp, sample{Labels: lbls, Value: s.Value})
+	}
+
+	sort.Slice(got, func(i, j int) bool { return labels.Compare(got[i].Labels, got[j].Labels) < 0 })
+	sort.Slice(exp, func(i, j int) bool { return labels.Compare(exp[i].Labels, exp[j].Labels) < 0 })
+
+	if !reflect.DeepEqual(exp, got) {
+		return errors.Errorf("expr: %q, time: %s,\n        exp: %v\n        got: %v",
+			tc.Expr, time.Duration(tc.EvalTime), exp, got)
+	}
+
+	return nil
+}
+
+// instantQuery evaluates the PromQL expression at the given time and returns
+// the result as a vector.
+func instantQuery(ctx context.Context, qs string, t time.Time, engine *promql.Engine, q storage.Queryable) (promql.Vector, error) {
+	query, err := engine.NewInstantQuery(q, qs, t)
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	res := query.Exec(ctx)
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	switch v := res.Value.(type) {
+	case promql.Vector:
+		return v, nil
+	case promql.Scalar:
+		return promql.Vector{promql.Sample{
+			Point:  promql.Point{T: v.T, V: v.V},
+			Metric: labels.Labels{},
+		}}, nil
+	default:
+		return nil, errors.New("expression result is not a vector or scalar")
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestRunRuleTests(t *testing.T) {
+	spec := monitoringv1.PrometheusRuleSpec{
+		Groups: []monitoringv1.RuleGroup{
+			{
+				Name:                    "test.rules",
+				PartialResponseStrategy: "warn",
+				Rules: []monitoringv1.Rule{
+					{
+						Record: "job:up:sum",
+						Expr:   intstr.FromString("sum by (job) (up)"),
+					},
+					{
+						Alert: "InstanceDown",
+						Expr:  intstr.FromString("up == 0"),
+						For:   "5m",
+						Labels: map[string]string{
+							"severity": "critical",
+						},
+						Annotations: map[string]string{
+							"summary": "Instance {{ $labels.instance }} is down",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name  string
+		tests string
+		ok    bool
+	}{
+		{
+			name: "passing tests",
+			tests: `
+tests:
+- interval: 1m
+  input_series:
+  - series: 'up{job="node",instance="a"}'
+    values: '1 1 0 0 0 0 0 0 0 0 0'
+  alert_rule_test:
+  - eval_time: 3m
+    alertname: InstanceDown
+  - eval_time: 10m
+    alertname: InstanceDown
+    exp_alerts:
+    - exp_labels:
+        severity: critical
+        job: node
+        instance: a
+      exp_annotations:
+        summary: Instance a is down
+  promql_expr_test:
+  - expr: job:up:sum
+    eval_time: 10m
+    exp_samples:
+    - labels: 'job:up:sum{job="node"}'
+      value: 0
+`,
+			ok: true,
+		},
+		{
+			name: "unexpected pending alert",
+			tests: `
+tests:
+- interval: 1m
+  input_series:
+  - series: 'up{job="node",instance="a"}'
+    values: '1 1 0 0 0 0 0 0 0 0 0'
+  alert_rule_test:
+  - eval_time: 3m
+    alertname: InstanceDown
+    exp_alerts:
+    - exp_labels:
+        severity: critical
+        job: node
+        instance: a
+`,
+		},
+		{
+			name: "wrong sample value",
+			tests: `
+tests:
+- interval: 1m
+  input_series:
+  - series: 'up{job="node",instance="a"}'
+    values: '1 1 0 0 0 0 0 0 0 0 0'
+  promql_expr_test:
+  - expr: job:up:sum
+    eval_time: 10m
+    exp_samples:
+    - labels: 'job:up:sum{job="node"}'
+      value: 1
+`,
+		},
+		{
+			name: "unknown field",
+			tests: `
+tests:
+- interval: 1m
+  rule_files:
+  - rules.yaml
+`,
+		},
+		{
+			name:  "no tests",
+			tests: `evaluation_interval: 1m`,
+		},
+		{
+			name: "too many evaluations",
+			tests: `
+tests:
+- interval: 1m
+  input_series:
+  - series: 'up{job="node",instance="a"}'
+    values: '1+0x10'
+  promql_expr_test:
+  - expr: up
+    eval_time: 1000h
+`,
+		},
+		{
+			name: "too many input samples",
+			tests: `
+tests:
+- interval: 1m
+  input_series:
+  - series: 'up{job="node",instance="a"}'
+    values: '1+0x1000000000'
+  promql_expr_test:
+  - expr: up
+    eval_time: 1m
+`,
+		},
+		{
+			name: "invalid input series",
+			tests: `
+tests:
+- interval: 1m
+  input_series:
+  - series: 'up{job="node"'
+    values: '1 1'
+  promql_expr_test:
+  - expr: up
+    eval_time: 1m
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := RunRuleTests(spec, tc.tests)
+
+			if tc.ok {
+				if len(errs) != 0 {
+					t.Fatalf("expected no error, got %v", errs)
+				}
+				return
+			}
+
+			if len(errs) == 0 {
+				t.Fatal("expected errors, got none")
+			}
+		})
+	}
+}
+
+func TestCountSeriesValues(t *testing.T) {
+	for _, tc := range []struct {
+		values string
+		exp    int
+	}{
+		{values: "", exp: 0},
+		{values: "1 2 _ stale 3", exp: 5},
+		{values: "1+1x10", exp: 11},
+		{values: "0 _x3 1-1x2", exp: 8},
+		{values: "1+0x99999999999", exp: maxRuleTestSamples + 1},
+	} {
+		t.Run(tc.values, func(t *testing.T) {
+			if got := countSeriesValues(tc.values); got != tc.exp {
+				t.Fatalf("expected %d, got %d", tc.exp, got)
+			}
+		})
+	}
+}
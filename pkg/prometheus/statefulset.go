@@ -28,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/alecthomas/units"
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -43,14 +44,18 @@ const (
 	confDir                         = "/etc/prometheus/config"
 	confOutDir                      = "/etc/prometheus/config_out"
 	webConfigDir                    = "/etc/prometheus/web_config"
-	tlsAssetsDir                    = "/etc/prometheus/certs"
+	TLSAssetsDir                    = "/etc/prometheus/certs"
 	rulesDir                        = "/etc/prometheus/rules"
 	secretsDir                      = "/etc/prometheus/secrets/"
 	configmapsDir                   = "/etc/prometheus/configmaps/"
 	configFilename                  = "prometheus.yaml.gz"
 	configEnvsubstFilename          = "prometheus.env.yaml"
 	sSetInputHashName               = "prometheus-operator-input-hash"
+	sidecarSecretsHashAnnotation    = "operator.prometheus.io/sidecar-secrets-hash"
+	objstoreConfigHashAnnotation    = "operator.prometheus.io/objstore-config-hash"
 	defaultPortName                 = "web"
+	exemplarStorageFeature          = "exemplar-storage"
+	nativeHistogramsFeature         = "native-histograms"
 )
 
 var (
@@ -65,6 +70,13 @@ var (
 	shardLabelName                = "operator.prometheus.io/shard"
 	prometheusNameLabelName       = "operator.prometheus.io/name"
 	probeTimeoutSeconds     int32 = 3
+
+	// minExemplarsVersion is the first Prometheus version supporting the
+	// exemplar storage.
+	minExemplarsVersion = semver.MustParse("2.25.0")
+	// minNativeHistogramsVersion is the first Prometheus version supporting
+	// native histograms.
+	minNativeHistogramsVersion = semver.MustParse("2.40.0")
 )
 
 func expectedStatefulSetShardNames(
@@ -77,14 +89,14 @@ func expectedStatefulSetShardNames(
 	}
 
 	for i := int32(0); i < shards; i++ {
-		res = append(res, prometheusNameByShard(p.Name, i))
+		res = append(res, prometheusNameByShard(p, i))
 	}
 
 	return res
 }
 
-func prometheusNameByShard(name string, shard int32) string {
-	base := prefixedName(name)
+func prometheusNameByShard(p *monitoringv1.Prometheus, shard int32) string {
+	base := prefixedName(p)
 	if shard == 0 {
 		return base
 	}
@@ -122,6 +134,10 @@ func makeStatefulSet(
 	if p.Spec.Replicas != nil && *p.Spec.Replicas < 0 {
 		p.Spec.Replicas = &intZero
 	}
+	if rw := p.Spec.RemoteWriteToThanosReceive; rw != nil && rw.DisableLocalRetention {
+		// The samples are stored by Thanos Receive.
+		p.Spec.Retention = thanosReceiveRetention
+	}
 	if p.Spec.Retention == "" {
 		p.Spec.Retention = defaultRetention
 	}
@@ -198,7 +214,7 @@ func makeStatefulSet(
 	storageSpec := p.Spec.Storage
 	if storageSpec == nil {
 		statefulset.Spec.Template.Spec.Volumes = append(statefulset.Spec.Template.Spec.Volumes, v1.Volume{
-			Name: volumeName(p.Name),
+			Name: volumeName(&p),
 			VolumeSource: v1.VolumeSource{
 				EmptyDir: &v1.EmptyDirVolumeSource{},
 			},
@@ -206,15 +222,23 @@ func makeStatefulSet(
 	} else if storageSpec.EmptyDir != nil {
 		emptyDir := storageSpec.EmptyDir
 		statefulset.Spec.Template.Spec.Volumes = append(statefulset.Spec.Template.Spec.Volumes, v1.Volume{
-			Name: volumeName(p.Name),
+			Name: volumeName(&p),
 			VolumeSource: v1.VolumeSource{
 				EmptyDir: emptyDir,
 			},
 		})
+	} else if storageSpec.Ephemeral != nil {
+		ephemeral := storageSpec.Ephemeral
+		statefulset.Spec.Template.Spec.Volumes = append(statefulset.Spec.Template.Spec.Volumes, v1.Volume{
+			Name: volumeName(&p),
+			VolumeSource: v1.VolumeSource{
+				Ephemeral: ephemeral,
+			},
+		})
 	} else {
 		pvcTemplate := operator.MakeVolumeClaimTemplate(storageSpec.VolumeClaimTemplate)
 		if pvcTemplate.Name == "" {
-			pvcTemplate.Name = volumeName(p.Name)
+			pvcTemplate.Name = volumeName(&p)
 		}
 		if storageSpec.VolumeClaimTemplate.Spec.AccessModes == nil {
 			pvcTemplate.Spec.AccessModes = []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}
@@ -245,7 +269,7 @@ func makeConfigSecret(p *monitoringv1.Prometheus, config operator.Config) *v1.Se
 	boolTrue := true
 	return &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   configSecretName(p.Name),
+			Name:   configSecretName(p),
 			Labels: config.Labels.Merge(managedByOperatorLabels),
 			OwnerReferences: []metav1.OwnerReference{
 				{
@@ -271,9 +295,16 @@ func makeStatefulSetService(p *monitoringv1.Prometheus, config operator.Config)
 		p.Spec.PortName = defaultPortName
 	}
 
+	name, appName := governingServiceName, "prometheus"
+	labels := map[string]string{"operated-prometheus": "true"}
+	if isAgent(p) {
+		name, appName = agentGoverningServiceName, agentAppName
+		labels = map[string]string{"operated-prometheus-agent": "true"}
+	}
+
 	svc := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: governingServiceName,
+			Name: name,
 			OwnerReferences: []metav1.OwnerReference{
 				{
 					Name:       p.GetName(),
@@ -282,9 +313,7 @@ func makeStatefulSetService(p *monitoringv1.Prometheus, config operator.Config)
 					UID:        p.GetUID(),
 				},
 			},
-			Labels: config.Labels.Merge(map[string]string{
-				"operated-prometheus": "true",
-			}),
+			Labels: config.Labels.Merge(labels),
 		},
 		Spec: v1.ServiceSpec{
 			ClusterIP: "None",
@@ -296,7 +325,7 @@ func makeStatefulSetService(p *monitoringv1.Prometheus, config operator.Config)
 				},
 			},
 			Selector: map[string]string{
-				"app.kubernetes.io/name": "prometheus",
+				"app.kubernetes.io/name": appName,
 			},
 		},
 	}
@@ -333,26 +362,40 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 		return nil, errors.Errorf("unsupported Prometheus major version %s", version)
 	}
 
-	promArgs := []string{
-		"-web.console.templates=/etc/prometheus/consoles",
-		"-web.console.libraries=/etc/prometheus/console_libraries",
-	}
+	var promArgs []string
+	if isAgent(&p) {
+		if version.LT(minAgentVersion) {
+			return nil, errors.Errorf("the agent mode requires Prometheus >= %s, got %s", minAgentVersion, version)
+		}
 
-	retentionTimeFlag := "-storage.tsdb.retention="
-	if version.Minor >= 7 {
-		retentionTimeFlag = "-storage.tsdb.retention.time="
-		if p.Spec.RetentionSize != "" {
-			promArgs = append(promArgs,
-				fmt.Sprintf("-storage.tsdb.retention.size=%s", p.Spec.RetentionSize),
-			)
+		// The agent mode has no TSDB, no query engine and no web console.
+		promArgs = append(promArgs,
+			fmt.Sprintf("-config.file=%s", path.Join(confOutDir, configEnvsubstFilename)),
+			fmt.Sprintf("-storage.agent.path=%s", storageDir),
+			"-web.enable-lifecycle",
+		)
+	} else {
+		promArgs = append(promArgs,
+			"-web.console.templates=/etc/prometheus/consoles",
+			"-web.console.libraries=/etc/prometheus/console_libraries",
+		)
+
+		retentionTimeFlag := "-storage.tsdb.retention="
+		if version.Minor >= 7 {
+			retentionTimeFlag = "-storage.tsdb.retention.time="
+			if p.Spec.RetentionSize != "" {
+				promArgs = append(promArgs,
+					fmt.Sprintf("-storage.tsdb.retention.size=%s", p.Spec.RetentionSize),
+				)
+			}
 		}
+		promArgs = append(promArgs,
+			fmt.Sprintf("-config.file=%s", path.Join(confOutDir, configEnvsubstFilename)),
+			fmt.Sprintf("-storage.tsdb.path=%s", storageDir),
+			retentionTimeFlag+p.Spec.Retention,
+			"-web.enable-lifecycle",
+		)
 	}
-	promArgs = append(promArgs,
-		fmt.Sprintf("-config.file=%s", path.Join(confOutDir, configEnvsubstFilename)),
-		fmt.Sprintf("-storage.tsdb.path=%s", storageDir),
-		retentionTimeFlag+p.Spec.Retention,
-		"-web.enable-lifecycle",
-	)
 
 	if p.Spec.Query != nil && p.Spec.Query.LookbackDelta != nil {
 		promArgs = append(promArgs,
@@ -402,12 +445,16 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 		)
 	}
 
-	if p.Spec.EnableAdminAPI {
+	if p.Spec.EnableAdminAPI && !c.DisallowAdminAPI {
 		promArgs = append(promArgs, "-web.enable-admin-api")
 	}
 
-	if len(p.Spec.EnableFeatures) > 0 {
-		promArgs = append(promArgs, "-enable-feature="+strings.Join(p.Spec.EnableFeatures[:], ","))
+	if p.Spec.EnableRemoteWriteReceiver && !c.DisallowRemoteWriteReceiver && version.GTE(semver.MustParse("2.33.0")) {
+		promArgs = append(promArgs, "-web.enable-remote-write-receiver")
+	}
+
+	if features := enabledFeatures(p, version); len(features) > 0 {
+		promArgs = append(promArgs, "-enable-feature="+strings.Join(features, ","))
 	}
 
 	if p.Spec.ExternalURL != "" {
@@ -463,7 +510,7 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 			Name: "config",
 			VolumeSource: v1.VolumeSource{
 				Secret: &v1.SecretVolumeSource{
-					SecretName: configSecretName(p.Name),
+					SecretName: configSecretName(&p),
 				},
 			},
 		},
@@ -471,7 +518,7 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 			Name: "tls-assets",
 			VolumeSource: v1.VolumeSource{
 				Secret: &v1.SecretVolumeSource{
-					SecretName: tlsAssetsSecretName(p.Name),
+					SecretName: tlsAssetsSecretName(&p),
 				},
 			},
 		},
@@ -496,7 +543,7 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 		})
 	}
 
-	volName := volumeName(p.Name)
+	volName := volumeName(&p)
 	if p.Spec.Storage != nil {
 		if p.Spec.Storage.VolumeClaimTemplate.Name != "" {
 			volName = p.Spec.Storage.VolumeClaimTemplate.Name
@@ -512,7 +559,7 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 		{
 			Name:      "tls-assets",
 			ReadOnly:  true,
-			MountPath: tlsAssetsDir,
+			MountPath: TLSAssetsDir,
 		},
 		{
 			Name:      volName,
@@ -534,12 +581,16 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 	// With this we avoid redeploying prometheus when reconfiguring between
 	// HTTP and HTTPS and vice-versa.
 	if version.GTE(semver.MustParse("2.24.0")) {
-		var webTLSConfig *monitoringv1.WebTLSConfig
+		var (
+			webTLSConfig  *monitoringv1.WebTLSConfig
+			webHTTPConfig *monitoringv1.WebHTTPConfig
+		)
 		if p.Spec.Web != nil {
 			webTLSConfig = p.Spec.Web.TLSConfig
+			webHTTPConfig = p.Spec.Web.HTTPConfig
 		}
 
-		webConfig, err := webconfig.New(webConfigDir, WebConfigSecretName(p.Name), webTLSConfig)
+		webConfig, err := webconfig.New(webConfigDir, WebConfigSecretName(&p), webTLSConfig, webHTTPConfig)
 		if err != nil {
 			return nil, err
 		}
@@ -620,14 +671,21 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 		FailureThreshold: 120, // Allow up to 10m on startup for data recovery
 	}
 
+	appName := "prometheus"
+	serviceName := governingServiceName
+	if isAgent(&p) {
+		appName = agentAppName
+		serviceName = agentGoverningServiceName
+	}
+
 	podAnnotations := map[string]string{}
 	podLabels := map[string]string{
 		"app.kubernetes.io/version": version.String(),
 	}
 	podSelectorLabels := map[string]string{
 		// TODO(fpetkovski): remove `app` label after 0.50 release
-		"app":                          "prometheus",
-		"app.kubernetes.io/name":       "prometheus",
+		"app":                          appName,
+		"app.kubernetes.io/name":       appName,
 		"app.kubernetes.io/managed-by": "prometheus-operator",
 		"app.kubernetes.io/instance":   p.Name,
 		"prometheus":                   p.Name,
@@ -687,15 +745,16 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 		}
 
 		if p.Spec.Thanos.GRPCServerTLSConfig != nil {
-			tls := p.Spec.Thanos.GRPCServerTLSConfig
-			if tls.CertFile != "" {
-				thanosArgs = append(thanosArgs, "--grpc-server-tls-cert="+tls.CertFile)
+			certFile, keyFile, caFile := thanosGRPCServerTLSFiles(p.Namespace, p.Spec.Thanos.GRPCServerTLSConfig)
+			if certFile != "" {
+				thanosArgs = append(thanosArgs, "--grpc-server-tls-cert="+certFile)
 			}
-			if tls.KeyFile != "" {
-				thanosArgs = append(thanosArgs, "--grpc-server-tls-key="+tls.KeyFile)
+			if keyFile != "" {
+				thanosArgs = append(thanosArgs, "--grpc-server-tls-key="+keyFile)
 			}
-			if tls.CAFile != "" {
-				thanosArgs = append(thanosArgs, "--grpc-server-tls-client-ca="+tls.CAFile)
+			// The clients must present a certificate signed by the CA.
+			if caFile != "" {
+				thanosArgs = append(thanosArgs, "--grpc-server-tls-client-ca="+caFile)
 			}
 		}
 
@@ -714,7 +773,7 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 					ContainerPort: 10901,
 				},
 			},
-			Resources: p.Spec.Thanos.Resources,
+			Resources: c.ThanosSidecarConfig.Resources(p.Spec.Thanos.Resources),
 		}
 
 		for _, thanosSideCarVM := range p.Spec.Thanos.VolumeMounts {
@@ -724,7 +783,15 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 			})
 		}
 
-		if p.Spec.Thanos.ObjectStorageConfig != nil || p.Spec.Thanos.ObjectStorageConfigFile != nil {
+		if usesTLSAssets(p.Spec.Thanos.GRPCServerTLSConfig) {
+			container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+				Name:      "tls-assets",
+				ReadOnly:  true,
+				MountPath: TLSAssetsDir,
+			})
+		}
+
+		if thanosUploadEnabled(p.Spec.Thanos) {
 			if p.Spec.Thanos.ObjectStorageConfigFile != nil {
 				container.Args = append(container.Args, "--objstore.config-file="+*p.Spec.Thanos.ObjectStorageConfigFile)
 			} else {
@@ -749,6 +816,15 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 			// NOTE(bwplotka): As described in https://thanos.io/components/sidecar.md/ we have to turn off compaction of Prometheus
 			// to avoid races during upload, if the uploads are configured.
 			disableCompaction = true
+
+			if shipper := p.Spec.Thanos.Shipper; shipper != nil {
+				if shipper.UploadCompacted {
+					container.Args = append(container.Args, "--shipper.upload-compacted")
+				}
+				if shipper.UploadConcurrency != nil {
+					container.Args = append(container.Args, fmt.Sprintf("--shipper.upload-concurrency=%d", *shipper.UploadConcurrency))
+				}
+			}
 		}
 
 		if p.Spec.Thanos.TracingConfig != nil || len(p.Spec.Thanos.TracingConfigFile) > 0 {
@@ -872,10 +948,15 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to merge containers spec")
 	}
+
+	containers, err = appendSidecars(containers, p.Spec.Sidecars)
+	if err != nil {
+		return nil, err
+	}
 	// PodManagementPolicy is set to Parallel to mitigate issues in kubernetes: https://github.com/kubernetes/kubernetes/issues/60164
 	// This is also mentioned as one of limitations of StatefulSets: https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#limitations
 	return &appsv1.StatefulSetSpec{
-		ServiceName:         governingServiceName,
+		ServiceName:         serviceName,
 		Replicas:            p.Spec.Replicas,
 		PodManagementPolicy: appsv1.ParallelPodManagement,
 		UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
@@ -901,30 +982,159 @@ func makeStatefulSetSpec(p monitoringv1.Prometheus, c *operator.Config, shard in
 				Volumes:                       volumes,
 				Tolerations:                   p.Spec.Tolerations,
 				Affinity:                      p.Spec.Affinity,
-				TopologySpreadConstraints:     p.Spec.TopologySpreadConstraints,
+				TopologySpreadConstraints:     makeTopologySpreadConstraints(p, appName, shard),
+				HostAliases:                   operator.MakeHostAliases(p.Spec.HostAliases),
+				DNSPolicy:                     p.Spec.DNSPolicy,
+				DNSConfig:                     p.Spec.DNSConfig,
 			},
 		},
 	}, nil
 }
 
-func configSecretName(name string) string {
-	return prefixedName(name)
+// appendSidecars adds the sidecar containers after the other containers of
+// the pod and mounts the volumes shared with the Prometheus container.
+func appendSidecars(containers []v1.Container, sidecars []monitoringv1.Sidecar) ([]v1.Container, error) {
+	names := make(map[string]struct{}, len(containers)+len(sidecars))
+	for _, c := range containers {
+		names[c.Name] = struct{}{}
+	}
+
+	for _, sidecar := range sidecars {
+		if _, found := names[sidecar.Name]; found {
+			return nil, errors.Errorf("sidecar %q conflicts with an existing container", sidecar.Name)
+		}
+		names[sidecar.Name] = struct{}{}
+
+		c := *sidecar.Container.DeepCopy()
+		if sidecar.MountConfig {
+			c.VolumeMounts = append(c.VolumeMounts, v1.VolumeMount{
+				Name:      "config-out",
+				ReadOnly:  true,
+				MountPath: confOutDir,
+			})
+		}
+		if sidecar.MountTLSAssets {
+			c.VolumeMounts = append(c.VolumeMounts, v1.VolumeMount{
+				Name:      "tls-assets",
+				ReadOnly:  true,
+				MountPath: TLSAssetsDir,
+			})
+		}
+
+		containers = append(containers, c)
+	}
+
+	return containers, nil
+}
+
+// makeTopologySpreadConstraints returns the topology spread constraints of the
+// Prometheus pods. The operator-managed pod labels are added to the label
+// selector of the constraints defining additionalLabelSelectors.
+func makeTopologySpreadConstraints(p monitoringv1.Prometheus, appName string, shard int32) []v1.TopologySpreadConstraint {
+	if len(p.Spec.TopologySpreadConstraints) == 0 {
+		return nil
+	}
+
+	tsc := make([]v1.TopologySpreadConstraint, 0, len(p.Spec.TopologySpreadConstraints))
+	for _, c := range p.Spec.TopologySpreadConstraints {
+		constraint := v1.TopologySpreadConstraint(*c.CoreV1TopologySpreadConstraint.DeepCopy())
+		if c.AdditionalLabelSelectors == nil {
+			tsc = append(tsc, constraint)
+			continue
+		}
+
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = &metav1.LabelSelector{}
+		}
+		if constraint.LabelSelector.MatchLabels == nil {
+			constraint.LabelSelector.MatchLabels = map[string]string{}
+		}
+
+		constraint.LabelSelector.MatchLabels["app.kubernetes.io/name"] = appName
+		constraint.LabelSelector.MatchLabels["app.kubernetes.io/instance"] = p.Name
+		constraint.LabelSelector.MatchLabels[prometheusNameLabelName] = p.Name
+		if *c.AdditionalLabelSelectors == monitoringv1.ShardAndResourceNameLabelSelector {
+			constraint.LabelSelector.MatchLabels[shardLabelName] = fmt.Sprintf("%d", shard)
+		}
+
+		tsc = append(tsc, constraint)
+	}
+
+	return tsc
+}
+
+// enabledFeatures returns the list of feature flags to enable. Besides the
+// features listed by the user, it includes the features required by other
+// fields of the Prometheus spec.
+func enabledFeatures(p monitoringv1.Prometheus, version semver.Version) []string {
+	features := append([]string{}, p.Spec.EnableFeatures...)
+
+	addFeature := func(feature string) {
+		for _, f := range features {
+			if f == feature {
+				return
+			}
+		}
+		features = append(features, feature)
+	}
+
+	if p.Spec.Exemplars != nil && version.GTE(minExemplarsVersion) {
+		addFeature(exemplarStorageFeature)
+	}
+
+	if p.Spec.EnableNativeHistograms && version.GTE(minNativeHistogramsVersion) {
+		addFeature(nativeHistogramsFeature)
+	}
+
+	return features
+}
+
+func configSecretName(p *monitoringv1.Prometheus) string {
+	return prefixedName(p)
+}
+
+// objectStorageConfigSecret returns the Secret key selector of the object
+// storage configuration of the Thanos sidecar or nil when the configuration
+// isn't read from a Secret.
+func objectStorageConfigSecret(p *monitoringv1.Prometheus) *v1.SecretKeySelector {
+	if p.Spec.Thanos == nil || p.Spec.Thanos.ObjectStorageConfigFile != nil || !thanosUploadEnabled(p.Spec.Thanos) {
+		return nil
+	}
+
+	return p.Spec.Thanos.ObjectStorageConfig
 }
 
-func tlsAssetsSecretName(name string) string {
-	return fmt.Sprintf("%s-tls-assets", prefixedName(name))
+// thanosUploadEnabled returns whether the Thanos sidecar uploads the blocks
+// to the object storage.
+func thanosUploadEnabled(thanos *monitoringv1.ThanosSpec) bool {
+	if thanos.ObjectStorageConfig == nil && thanos.ObjectStorageConfigFile == nil {
+		return false
+	}
+
+	return thanos.Shipper == nil || !thanos.Shipper.DisableUpload
 }
 
-func WebConfigSecretName(name string) string {
-	return fmt.Sprintf("%s-web-config", prefixedName(name))
+func tlsAssetsSecretName(p *monitoringv1.Prometheus) string {
+	return fmt.Sprintf("%s-tls-assets", prefixedName(p))
 }
 
-func volumeName(name string) string {
-	return fmt.Sprintf("%s-db", prefixedName(name))
+func WebConfigSecretName(p *monitoringv1.Prometheus) string {
+	return fmt.Sprintf("%s-web-config", prefixedName(p))
 }
 
-func prefixedName(name string) string {
-	return fmt.Sprintf("prometheus-%s", name)
+func volumeName(p *monitoringv1.Prometheus) string {
+	return fmt.Sprintf("%s-db", prefixedName(p))
+}
+
+// prefixedName returns the name of the objects (StatefulSets, Secrets, ...)
+// generated for the given Prometheus or PrometheusAgent. The agent prefix
+// doesn't start with the Prometheus one so that the StatefulSet names of the
+// 2 resources never overlap.
+func prefixedName(p *monitoringv1.Prometheus) string {
+	if isAgent(p) {
+		return fmt.Sprintf("%s-%s", agentStatefulSetNamePrefix, p.Name)
+	}
+	return fmt.Sprintf("%s-%s", prometheusStatefulSetNamePrefix, p.Name)
 }
 
 func subPathForStorage(s *monitoringv1.StorageSpec) string {
@@ -935,3 +1145,23 @@ func subPathForStorage(s *monitoringv1.StorageSpec) string {
 
 	return "prometheus-db"
 }
+
+// retentionSizeExceedsStorage returns true when the retention size is greater
+// than the storage requested by the volume claim template.
+func retentionSizeExceedsStorage(p *monitoringv1.Prometheus) bool {
+	if p.Spec.RetentionSize == "" || p.Spec.Storage == nil {
+		return false
+	}
+
+	size, err := units.ParseBase2Bytes(p.Spec.RetentionSize)
+	if err != nil {
+		return false
+	}
+
+	storage, found := p.Spec.Storage.VolumeClaimTemplate.Spec.Resources.Requests[v1.ResourceStorage]
+	if !found {
+		return false
+	}
+
+	return int64(size) > storage.Value()
+}
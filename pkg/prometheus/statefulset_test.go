@@ -220,6 +220,33 @@ func TestStatefulSetEmptyDir(t *testing.T) {
 	}
 }
 
+func TestStatefulSetEphemeral(t *testing.T) {
+	storageClass := "storageclass"
+
+	ephemeral := v1.EphemeralVolumeSource{
+		VolumeClaimTemplate: &v1.PersistentVolumeClaimTemplate{
+			Spec: v1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClass,
+			},
+		},
+	}
+
+	sset, err := makeStatefulSet("test", monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			Storage: &monitoringv1.StorageSpec{
+				Ephemeral: &ephemeral,
+			},
+		},
+	}, defaultTestConfig, nil, "", 0)
+
+	require.NoError(t, err)
+	require.Empty(t, sset.Spec.VolumeClaimTemplates)
+
+	ssetVolumes := sset.Spec.Template.Spec.Volumes
+	require.NotNil(t, ssetVolumes[len(ssetVolumes)-1].VolumeSource.Ephemeral)
+	require.Equal(t, ephemeral, *ssetVolumes[len(ssetVolumes)-1].VolumeSource.Ephemeral)
+}
+
 func TestStatefulSetVolumeInitial(t *testing.T) {
 	expected := &appsv1.StatefulSet{
 		Spec: appsv1.StatefulSetSpec{
@@ -274,7 +301,7 @@ func TestStatefulSetVolumeInitial(t *testing.T) {
 							Name: "config",
 							VolumeSource: v1.VolumeSource{
 								Secret: &v1.SecretVolumeSource{
-									SecretName: configSecretName("volume-init-test"),
+									SecretName: "prometheus-volume-init-test",
 								},
 							},
 						},
@@ -282,7 +309,7 @@ func TestStatefulSetVolumeInitial(t *testing.T) {
 							Name: "tls-assets",
 							VolumeSource: v1.VolumeSource{
 								Secret: &v1.SecretVolumeSource{
-									SecretName: tlsAssetsSecretName("volume-init-test"),
+									SecretName: "prometheus-volume-init-test-tls-assets",
 								},
 							},
 						},
@@ -875,6 +902,93 @@ func TestThanosResourcesSet(t *testing.T) {
 	}
 }
 
+func TestThanosResourcesDefaults(t *testing.T) {
+	c := *defaultTestConfig
+	c.ThanosSidecarConfig = operator.ThanosSidecarConfig{
+		CPURequest:    "50m",
+		CPULimit:      "0",
+		MemoryRequest: "64Mi",
+		MemoryLimit:   "256Mi",
+	}
+
+	for _, tc := range []struct {
+		name      string
+		resources v1.ResourceRequirements
+		expected  v1.ResourceRequirements
+	}{
+		{
+			name: "defaults",
+			expected: v1.ResourceRequirements{
+				Limits: v1.ResourceList{
+					v1.ResourceMemory: resource.MustParse("256Mi"),
+				},
+				Requests: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("50m"),
+					v1.ResourceMemory: resource.MustParse("64Mi"),
+				},
+			},
+		},
+		{
+			name: "overridden",
+			resources: v1.ResourceRequirements{
+				Limits: v1.ResourceList{
+					v1.ResourceCPU: resource.MustParse("200m"),
+				},
+				Requests: v1.ResourceList{
+					v1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+			},
+			expected: v1.ResourceRequirements{
+				Limits: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("200m"),
+					v1.ResourceMemory: resource.MustParse("256Mi"),
+				},
+				Requests: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("50m"),
+					v1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+			},
+		},
+		{
+			name: "conflicting defaults",
+			resources: v1.ResourceRequirements{
+				Limits: v1.ResourceList{
+					v1.ResourceCPU: resource.MustParse("10m"),
+				},
+				Requests: v1.ResourceList{
+					v1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+			expected: v1.ResourceRequirements{
+				Limits: v1.ResourceList{
+					v1.ResourceCPU: resource.MustParse("10m"),
+				},
+				Requests: v1.ResourceList{
+					v1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sset, err := makeStatefulSet("test", monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					Thanos: &monitoringv1.ThanosSpec{
+						Resources: tc.resources,
+					},
+				},
+			}, &c, nil, "", 0)
+			if err != nil {
+				t.Fatalf("Unexpected error while making StatefulSet: %v", err)
+			}
+
+			actual := sset.Spec.Template.Spec.Containers[2].Resources
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("Unexpected resources defined. \n\nExpected: %v\n\nGot: %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
 func TestThanosNoObjectStorage(t *testing.T) {
 	sset, err := makeStatefulSet("test", monitoringv1.Prometheus{
 		Spec: monitoringv1.PrometheusSpec{
@@ -1088,6 +1202,122 @@ func TestThanosObjectStorageFile(t *testing.T) {
 	}
 }
 
+func TestThanosShipper(t *testing.T) {
+	concurrency := int32(4)
+
+	for _, tc := range []struct {
+		name           string
+		shipper        *monitoringv1.ThanosShipperSpec
+		expectedArgs   []string
+		unexpectedArgs []string
+		compactionOff  bool
+		objstoreEnvVar bool
+	}{
+		{
+			name:           "default",
+			expectedArgs:   []string{"--objstore.config=$(OBJSTORE_CONFIG)"},
+			unexpectedArgs: []string{"--shipper.upload-compacted"},
+			compactionOff:  true,
+			objstoreEnvVar: true,
+		},
+		{
+			name: "upload tuning",
+			shipper: &monitoringv1.ThanosShipperSpec{
+				UploadCompacted:   true,
+				UploadConcurrency: &concurrency,
+			},
+			expectedArgs: []string{
+				"--objstore.config=$(OBJSTORE_CONFIG)",
+				"--shipper.upload-compacted",
+				"--shipper.upload-concurrency=4",
+			},
+			compactionOff:  true,
+			objstoreEnvVar: true,
+		},
+		{
+			name: "upload disabled",
+			shipper: &monitoringv1.ThanosShipperSpec{
+				DisableUpload:     true,
+				UploadCompacted:   true,
+				UploadConcurrency: &concurrency,
+			},
+			unexpectedArgs: []string{
+				"--objstore.config=$(OBJSTORE_CONFIG)",
+				"--shipper.upload-compacted",
+				"--shipper.upload-concurrency=4",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					Thanos: &monitoringv1.ThanosSpec{
+						ObjectStorageConfig: &v1.SecretKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "thanos-objstore"},
+							Key:                  "objstore.yaml",
+						},
+						Shipper: tc.shipper,
+					},
+				},
+			}
+
+			sset, err := makeStatefulSet("test", p, defaultTestConfig, nil, "", 0)
+			if err != nil {
+				t.Fatalf("Unexpected error while making StatefulSet: %v", err)
+			}
+
+			var prom, sidecar v1.Container
+			for _, c := range sset.Spec.Template.Spec.Containers {
+				switch c.Name {
+				case "prometheus":
+					prom = c
+				case "thanos-sidecar":
+					sidecar = c
+				}
+			}
+
+			args := make(map[string]struct{}, len(sidecar.Args))
+			for _, arg := range sidecar.Args {
+				args[arg] = struct{}{}
+			}
+			for _, arg := range tc.expectedArgs {
+				if _, found := args[arg]; !found {
+					t.Fatalf("Thanos sidecar is missing expected argument %q, got %q", arg, sidecar.Args)
+				}
+			}
+			for _, arg := range tc.unexpectedArgs {
+				if _, found := args[arg]; found {
+					t.Fatalf("Thanos sidecar has unexpected argument %q", arg)
+				}
+			}
+
+			var compactionOff bool
+			for _, arg := range prom.Args {
+				if arg == "--storage.tsdb.max-block-duration=2h" {
+					compactionOff = true
+				}
+			}
+			if compactionOff != tc.compactionOff {
+				t.Fatalf("expected Prometheus compaction disabled to be %v, got %v", tc.compactionOff, compactionOff)
+			}
+
+			var objstoreEnvVar bool
+			for _, env := range sidecar.Env {
+				if env.Name == "OBJSTORE_CONFIG" {
+					objstoreEnvVar = true
+				}
+			}
+			if objstoreEnvVar != tc.objstoreEnvVar {
+				t.Fatalf("expected OBJSTORE_CONFIG env var to be %v, got %v", tc.objstoreEnvVar, objstoreEnvVar)
+			}
+
+			if tc.shipper != nil && tc.shipper.DisableUpload && objectStorageConfigSecret(&p) != nil {
+				t.Fatal("expected no object storage secret when uploads are disabled")
+			}
+		})
+	}
+}
+
 func TestThanosTracing(t *testing.T) {
 	testKey := "thanos-config-secret-test"
 
@@ -1196,6 +1426,50 @@ func TestThanosSideCarVolumes(t *testing.T) {
 	}
 }
 
+func TestThanosGRPCServerTLSConfig(t *testing.T) {
+	sset, err := makeStatefulSet("test", monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1"},
+		Spec: monitoringv1.PrometheusSpec{
+			Thanos: &monitoringv1.ThanosSpec{
+				GRPCServerTLSConfig: &monitoringv1.TLSConfig{
+					SafeTLSConfig: monitoringv1.SafeTLSConfig{
+						CA: monitoringv1.SecretOrConfigMap{
+							ConfigMap: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "grpc-ca"}, Key: "ca.crt"},
+						},
+						Cert: monitoringv1.SecretOrConfigMap{
+							Secret: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "grpc-tls"}, Key: "tls.crt"},
+						},
+						KeySecret: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "grpc-tls"}, Key: "tls.key"},
+					},
+				},
+			},
+		},
+	}, defaultTestConfig, nil, "", 0)
+	require.NoError(t, err)
+
+	var sidecar *v1.Container
+	for i, c := range sset.Spec.Template.Spec.Containers {
+		if c.Name == "thanos-sidecar" {
+			sidecar = &sset.Spec.Template.Spec.Containers[i]
+		}
+	}
+	require.NotNil(t, sidecar)
+
+	for _, arg := range []string{
+		"--grpc-server-tls-cert=/etc/prometheus/certs/secret_ns1_grpc-tls_tls.crt",
+		"--grpc-server-tls-key=/etc/prometheus/certs/secret_ns1_grpc-tls_tls.key",
+		"--grpc-server-tls-client-ca=/etc/prometheus/certs/configmap_ns1_grpc-ca_ca.crt",
+	} {
+		require.Contains(t, sidecar.Args, arg)
+	}
+
+	require.Contains(t, sidecar.VolumeMounts, v1.VolumeMount{
+		Name:      "tls-assets",
+		ReadOnly:  true,
+		MountPath: TLSAssetsDir,
+	})
+}
+
 func TestRetentionSize(t *testing.T) {
 	tests := []struct {
 		version              string
@@ -1239,16 +1513,85 @@ func TestRetentionSize(t *testing.T) {
 	}
 }
 
+func TestRetentionSizeExceedsStorage(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		retentionSize string
+		storage       *monitoringv1.StorageSpec
+		expected      bool
+	}{
+		{
+			name:          "no storage",
+			retentionSize: "10GB",
+		},
+		{
+			name:          "no retention size",
+			retentionSize: "",
+			storage:       storageSpec("1Gi"),
+		},
+		{
+			name:          "no storage request",
+			retentionSize: "10GB",
+			storage:       &monitoringv1.StorageSpec{},
+		},
+		{
+			name:          "retention size lower than the storage request",
+			retentionSize: "8GB",
+			storage:       storageSpec("10Gi"),
+		},
+		{
+			name:          "retention size equal to the storage request",
+			retentionSize: "10GB",
+			storage:       storageSpec("10Gi"),
+		},
+		{
+			name:          "retention size greater than the storage request",
+			retentionSize: "12GB",
+			storage:       storageSpec("10Gi"),
+			expected:      true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					RetentionSize: tc.retentionSize,
+					Storage:       tc.storage,
+				},
+			}
+
+			if got := retentionSizeExceedsStorage(p); got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func storageSpec(size string) *monitoringv1.StorageSpec {
+	return &monitoringv1.StorageSpec{
+		VolumeClaimTemplate: monitoringv1.EmbeddedPersistentVolumeClaim{
+			Spec: v1.PersistentVolumeClaimSpec{
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceStorage: resource.MustParse(size),
+					},
+				},
+			},
+		},
+	}
+}
+
 func TestRetention(t *testing.T) {
 	tests := []struct {
-		version              string
-		specRetention        string
-		expectedRetentionArg string
+		version               string
+		specRetention         string
+		disableLocalRetention bool
+		expectedRetentionArg  string
 	}{
-		{"v2.5.0", "", "--storage.tsdb.retention=24h"},
-		{"v2.5.0", "1d", "--storage.tsdb.retention=1d"},
-		{"v2.7.0", "", "--storage.tsdb.retention.time=24h"},
-		{"v2.7.0", "1d", "--storage.tsdb.retention.time=1d"},
+		{"v2.5.0", "", false, "--storage.tsdb.retention=24h"},
+		{"v2.5.0", "1d", false, "--storage.tsdb.retention=1d"},
+		{"v2.7.0", "", false, "--storage.tsdb.retention.time=24h"},
+		{"v2.7.0", "1d", false, "--storage.tsdb.retention.time=1d"},
+		{"v2.7.0", "", true, "--storage.tsdb.retention.time=2h"},
 	}
 
 	for _, test := range tests {
@@ -1256,6 +1599,10 @@ func TestRetention(t *testing.T) {
 			Spec: monitoringv1.PrometheusSpec{
 				Version:   test.version,
 				Retention: test.specRetention,
+				RemoteWriteToThanosReceive: &monitoringv1.RemoteWriteToThanosReceiveSpec{
+					Service:               monitoringv1.ThanosReceiveServiceReference{Name: "thanos-receive"},
+					DisableLocalRetention: test.disableLocalRetention,
+				},
 			},
 		}, defaultTestConfig, nil, "", 0)
 		if err != nil {
@@ -1810,6 +2157,182 @@ func TestEnableFeaturesWithMultipleFeature(t *testing.T) {
 	}
 }
 
+func TestDisallowedCapabilities(t *testing.T) {
+	for _, tc := range []struct {
+		name                        string
+		version                     string
+		disallowAdminAPI            bool
+		disallowRemoteWriteReceiver bool
+		expectedArgs                []string
+		unexpectedArgs              []string
+	}{
+		{
+			name:         "capabilities allowed",
+			version:      "v2.33.0",
+			expectedArgs: []string{"--web.enable-admin-api", "--web.enable-remote-write-receiver"},
+		},
+		{
+			name:             "admin API disallowed",
+			version:          "v2.33.0",
+			disallowAdminAPI: true,
+			expectedArgs:     []string{"--web.enable-remote-write-receiver"},
+			unexpectedArgs:   []string{"--web.enable-admin-api"},
+		},
+		{
+			name:                        "remote write receiver disallowed",
+			version:                     "v2.33.0",
+			disallowRemoteWriteReceiver: true,
+			expectedArgs:                []string{"--web.enable-admin-api"},
+			unexpectedArgs:              []string{"--web.enable-remote-write-receiver"},
+		},
+		{
+			name:           "remote write receiver with old version",
+			version:        "v2.32.0",
+			expectedArgs:   []string{"--web.enable-admin-api"},
+			unexpectedArgs: []string{"--web.enable-remote-write-receiver"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := *defaultTestConfig
+			config.DisallowAdminAPI = tc.disallowAdminAPI
+			config.DisallowRemoteWriteReceiver = tc.disallowRemoteWriteReceiver
+
+			sset, err := makeStatefulSet("test", monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					Version:                   tc.version,
+					EnableAdminAPI:            true,
+					EnableRemoteWriteReceiver: true,
+				},
+			}, &config, nil, "", 0)
+			require.NoError(t, err)
+
+			args := sset.Spec.Template.Spec.Containers[0].Args
+			for _, arg := range tc.expectedArgs {
+				require.Contains(t, args, arg)
+			}
+			for _, arg := range tc.unexpectedArgs {
+				require.NotContains(t, args, arg)
+			}
+		})
+	}
+}
+
+func TestEnableNativeHistograms(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		version  string
+		features []string
+		expected string
+	}{
+		{
+			name:     "native histograms supported",
+			version:  "v2.40.0",
+			expected: "--enable-feature=native-histograms",
+		},
+		{
+			name:     "native histograms appended to other features",
+			version:  "v2.40.0",
+			features: []string{"exemplar-storage"},
+			expected: "--enable-feature=exemplar-storage,native-histograms",
+		},
+		{
+			name:     "native histograms already enabled",
+			version:  "v2.40.0",
+			features: []string{"native-histograms", "exemplar-storage"},
+			expected: "--enable-feature=native-histograms,exemplar-storage",
+		},
+		{
+			name:    "native histograms not supported",
+			version: "v2.39.0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sset, err := makeStatefulSet("test", monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					Version:                tc.version,
+					EnableFeatures:         tc.features,
+					EnableNativeHistograms: true,
+				},
+			}, defaultTestConfig, nil, "", 0)
+
+			if err != nil {
+				t.Fatalf("Unexpected error while making StatefulSet: %v", err)
+			}
+
+			var got string
+			for _, flag := range sset.Spec.Template.Spec.Containers[0].Args {
+				if strings.HasPrefix(flag, "--enable-feature=") {
+					got = flag
+				}
+			}
+
+			if got != tc.expected {
+				t.Fatalf("expected feature flag %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestExemplarStorageFeature(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		version   string
+		exemplars *monitoringv1.Exemplars
+		features  []string
+		expected  string
+	}{
+		{
+			name:      "exemplars enabled",
+			version:   "v2.25.0",
+			exemplars: &monitoringv1.Exemplars{},
+			expected:  "--enable-feature=exemplar-storage",
+		},
+		{
+			name:      "exemplar storage already enabled",
+			version:   "v2.25.0",
+			exemplars: &monitoringv1.Exemplars{},
+			features:  []string{"exemplar-storage"},
+			expected:  "--enable-feature=exemplar-storage",
+		},
+		{
+			name:     "exemplars not set",
+			version:  "v2.25.0",
+			features: []string{"memory-snapshot-on-shutdown"},
+			expected: "--enable-feature=memory-snapshot-on-shutdown",
+		},
+		{
+			name:      "exemplars not supported",
+			version:   "v2.24.0",
+			exemplars: &monitoringv1.Exemplars{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sset, err := makeStatefulSet("test", monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					Version:        tc.version,
+					EnableFeatures: tc.features,
+					Exemplars:      tc.exemplars,
+				},
+			}, defaultTestConfig, nil, "", 0)
+
+			if err != nil {
+				t.Fatalf("Unexpected error while making StatefulSet: %v", err)
+			}
+
+			var got string
+			for _, flag := range sset.Spec.Template.Spec.Containers[0].Args {
+				if strings.HasPrefix(flag, "--enable-feature=") {
+					got = flag
+				}
+			}
+
+			if got != tc.expected {
+				t.Fatalf("expected feature flag %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
 func TestWebPageTitle(t *testing.T) {
 	pageTitle := "my-page-title"
 	sset, err := makeStatefulSet("test", monitoringv1.Prometheus{
@@ -1891,6 +2414,253 @@ func TestExpectStatefulSetMinReadySeconds(t *testing.T) {
 	}
 }
 
+func TestStatefulSetInitContainers(t *testing.T) {
+	sset, err := makeStatefulSet("test", monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			InitContainers: []v1.Container{
+				{
+					Name: "init-config-reloader",
+					Resources: v1.ResourceRequirements{
+						Limits: v1.ResourceList{
+							v1.ResourceMemory: resource.MustParse("100Mi"),
+						},
+					},
+				},
+				{
+					Name:  "prepare-volume",
+					Image: "busybox",
+				},
+			},
+		},
+	}, defaultTestConfig, nil, "", 0)
+	require.NoError(t, err)
+
+	initContainers := sset.Spec.Template.Spec.InitContainers
+	require.Len(t, initContainers, 2)
+	require.Equal(t, "init-config-reloader", initContainers[0].Name)
+	require.NotEmpty(t, initContainers[0].Image)
+	require.True(t, initContainers[0].Resources.Limits.Memory().Equal(resource.MustParse("100Mi")))
+	require.Equal(t, "prepare-volume", initContainers[1].Name)
+
+	_, err = makeStatefulSet("test", monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			InitContainers: []v1.Container{
+				{Name: "prepare-volume", Image: "busybox"},
+				{Name: "prepare-volume", Image: "alpine"},
+			},
+		},
+	}, defaultTestConfig, nil, "", 0)
+	require.Error(t, err)
+}
+
+func TestStatefulSetSidecars(t *testing.T) {
+	sset, err := makeStatefulSet("test", monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			Sidecars: []monitoringv1.Sidecar{
+				{
+					Container: v1.Container{
+						Name:  "auth-proxy",
+						Image: "auth-proxy:latest",
+					},
+					MountTLSAssets: true,
+				},
+				{
+					Container: v1.Container{
+						Name:  "config-shipper",
+						Image: "config-shipper:latest",
+					},
+					MountConfig: true,
+				},
+			},
+		},
+	}, defaultTestConfig, nil, "", 0)
+	require.NoError(t, err)
+
+	containers := sset.Spec.Template.Spec.Containers
+	require.Len(t, containers, 4)
+	require.Equal(t, "prometheus", containers[0].Name)
+	require.Equal(t, "config-reloader", containers[1].Name)
+
+	require.Equal(t, "auth-proxy", containers[2].Name)
+	require.Equal(t, []v1.VolumeMount{{Name: "tls-assets", ReadOnly: true, MountPath: TLSAssetsDir}}, containers[2].VolumeMounts)
+
+	require.Equal(t, "config-shipper", containers[3].Name)
+	require.Equal(t, []v1.VolumeMount{{Name: "config-out", ReadOnly: true, MountPath: confOutDir}}, containers[3].VolumeMounts)
+
+	_, err = makeStatefulSet("test", monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			Sidecars: []monitoringv1.Sidecar{
+				{
+					Container: v1.Container{
+						Name:  "config-reloader",
+						Image: "config-reloader:latest",
+					},
+				},
+			},
+		},
+	}, defaultTestConfig, nil, "", 0)
+	require.Error(t, err)
+}
+
+func TestStatefulSetHostAliases(t *testing.T) {
+	statefulSet, err := makeStatefulSet("test", monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			HostAliases: []monitoringv1.HostAlias{
+				{
+					IP:        "10.0.0.1",
+					Hostnames: []string{"foo.example.com", "bar.example.com"},
+				},
+			},
+		},
+	}, defaultTestConfig, nil, "", 0)
+	require.NoError(t, err)
+
+	expected := []v1.HostAlias{
+		{
+			IP:        "10.0.0.1",
+			Hostnames: []string{"foo.example.com", "bar.example.com"},
+		},
+	}
+	require.Equal(t, expected, statefulSet.Spec.Template.Spec.HostAliases)
+}
+
+func TestStatefulSetDNS(t *testing.T) {
+	dnsConfig := &v1.PodDNSConfig{
+		Nameservers: []string{"8.8.8.8"},
+		Searches:    []string{"svc.cluster.local"},
+	}
+
+	statefulSet, err := makeStatefulSet("test", monitoringv1.Prometheus{
+		Spec: monitoringv1.PrometheusSpec{
+			DNSPolicy: v1.DNSClusterFirstWithHostNet,
+			DNSConfig: dnsConfig,
+		},
+	}, defaultTestConfig, nil, "", 0)
+	require.NoError(t, err)
+
+	require.Equal(t, v1.DNSClusterFirstWithHostNet, statefulSet.Spec.Template.Spec.DNSPolicy)
+	require.Equal(t, dnsConfig, statefulSet.Spec.Template.Spec.DNSConfig)
+}
+
+func TestStatefulSetTopologySpreadConstraints(t *testing.T) {
+	onResource := monitoringv1.ResourceNameLabelSelector
+	onShard := monitoringv1.ShardAndResourceNameLabelSelector
+
+	for _, tc := range []struct {
+		name     string
+		tsc      []monitoringv1.TopologySpreadConstraint
+		expected []v1.TopologySpreadConstraint
+	}{
+		{
+			name: "no constraints",
+		},
+		{
+			name: "user-defined selector",
+			tsc: []monitoringv1.TopologySpreadConstraint{
+				{
+					CoreV1TopologySpreadConstraint: monitoringv1.CoreV1TopologySpreadConstraint{
+						MaxSkew:           1,
+						TopologyKey:       "topology.kubernetes.io/zone",
+						WhenUnsatisfiable: v1.DoNotSchedule,
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"foo": "bar"},
+						},
+					},
+				},
+			},
+			expected: []v1.TopologySpreadConstraint{
+				{
+					MaxSkew:           1,
+					TopologyKey:       "topology.kubernetes.io/zone",
+					WhenUnsatisfiable: v1.DoNotSchedule,
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+				},
+			},
+		},
+		{
+			name: "additional selector on resource",
+			tsc: []monitoringv1.TopologySpreadConstraint{
+				{
+					CoreV1TopologySpreadConstraint: monitoringv1.CoreV1TopologySpreadConstraint{
+						MaxSkew:           1,
+						TopologyKey:       "topology.kubernetes.io/zone",
+						WhenUnsatisfiable: v1.DoNotSchedule,
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"foo": "bar"},
+						},
+					},
+					AdditionalLabelSelectors: &onResource,
+				},
+			},
+			expected: []v1.TopologySpreadConstraint{
+				{
+					MaxSkew:           1,
+					TopologyKey:       "topology.kubernetes.io/zone",
+					WhenUnsatisfiable: v1.DoNotSchedule,
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"foo":                         "bar",
+							"app.kubernetes.io/name":      "prometheus",
+							"app.kubernetes.io/instance":  "test",
+							"operator.prometheus.io/name": "test",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "additional selector on shard",
+			tsc: []monitoringv1.TopologySpreadConstraint{
+				{
+					CoreV1TopologySpreadConstraint: monitoringv1.CoreV1TopologySpreadConstraint{
+						MaxSkew:           1,
+						TopologyKey:       "kubernetes.io/hostname",
+						WhenUnsatisfiable: v1.ScheduleAnyway,
+					},
+					AdditionalLabelSelectors: &onShard,
+				},
+			},
+			expected: []v1.TopologySpreadConstraint{
+				{
+					MaxSkew:           1,
+					TopologyKey:       "kubernetes.io/hostname",
+					WhenUnsatisfiable: v1.ScheduleAnyway,
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"app.kubernetes.io/name":       "prometheus",
+							"app.kubernetes.io/instance":   "test",
+							"operator.prometheus.io/name":  "test",
+							"operator.prometheus.io/shard": "1",
+						},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: monitoringv1.PrometheusSpec{
+					TopologySpreadConstraints: tc.tsc,
+				},
+			}
+
+			sset, err := makeStatefulSet("test", p, defaultTestConfig, nil, "", 1)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, sset.Spec.Template.Spec.TopologySpreadConstraints)
+
+			// The operator-managed labels shouldn't leak into the Prometheus object.
+			if len(tc.tsc) > 0 && tc.tsc[0].LabelSelector != nil {
+				require.NotContains(t, tc.tsc[0].LabelSelector.MatchLabels, "operator.prometheus.io/name")
+			}
+		})
+	}
+}
+
 func TestConfigReloader(t *testing.T) {
 	expectedShardNum := 0
 	baseSet, err := makeStatefulSet("test", monitoringv1.Prometheus{}, defaultTestConfig, nil, "", int32(expectedShardNum))
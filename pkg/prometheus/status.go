@@ -0,0 +1,236 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+// updateStatus updates the status subresource of the Prometheus object with
+// the state of its StatefulSets and the outcome of the last reconciliation.
+func (c *Operator) updateStatus(ctx context.Context, logger log.Logger, key string, p *monitoringv1.Prometheus, reconcileErr error) error {
+	ssets := make([]*appsv1.StatefulSet, len(expectedStatefulSetShardNames(p)))
+	for shard := range ssets {
+		obj, err := c.ssetInfs.Get(prometheusKeyToStatefulSetKey(key, shard))
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrap(err, "retrieving statefulset failed")
+		}
+		ssets[shard] = obj.(*appsv1.StatefulSet)
+	}
+
+	stalePods, err := c.staleCredentialsPods(ctx, p, ssets)
+	if err != nil {
+		return err
+	}
+
+	status := newPrometheusStatus(p, c.config, ssets, stalePods, reconcileErr, metav1.Now())
+	if p.Status != nil && equality.Semantic.DeepEqual(*p.Status, status) {
+		return nil
+	}
+
+	level.Debug(logger).Log("msg", "updating status")
+
+	pCopy := p.DeepCopy()
+	pCopy.Status = &status
+	if _, err := c.mclient.MonitoringV1().Prometheuses(p.Namespace).UpdateStatus(ctx, pCopy, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update the status")
+	}
+
+	return nil
+}
+
+// staleCredentialsPods returns the names of the pods which don't run with the
+// current object storage configuration of the Thanos sidecar. It returns nil
+// when the configuration isn't read from a Secret.
+func (c *Operator) staleCredentialsPods(ctx context.Context, p *monitoringv1.Prometheus, ssets []*appsv1.StatefulSet) ([]string, error) {
+	if objectStorageConfigSecret(p) == nil {
+		return nil, nil
+	}
+
+	pods, err := c.kclient.CoreV1().Pods(p.Namespace).List(ctx, ListOptions(p.Name))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pods")
+	}
+
+	var stale []string
+	for _, pod := range pods.Items {
+		shard, err := strconv.Atoi(pod.Labels[shardLabelName])
+		if err != nil || shard >= len(ssets) || ssets[shard] == nil {
+			continue
+		}
+
+		if pod.Annotations[objstoreConfigHashAnnotation] != ssets[shard].Spec.Template.Annotations[objstoreConfigHashAnnotation] {
+			stale = append(stale, pod.Name)
+		}
+	}
+	sort.Strings(stale)
+
+	return stale, nil
+}
+
+// newPrometheusStatus returns the status of the Prometheus object given the
+// StatefulSets of its shards (nil when missing), the pods running with stale
+// object storage credentials and the outcome of the last reconciliation. The
+// transition times of the conditions whose status didn't change are
+// preserved.
+func newPrometheusStatus(p *monitoringv1.Prometheus, config operator.Config, ssets []*appsv1.StatefulSet, stalePods []string, reconcileErr error, now metav1.Time) monitoringv1.PrometheusStatus {
+	status := monitoringv1.PrometheusStatus{Paused: p.Spec.Paused}
+
+	desired := minReplicas
+	if p.Spec.Replicas != nil {
+		desired = *p.Spec.Replicas
+	}
+
+	var notAvailable, degraded []string
+	for shard, sset := range ssets {
+		ss := monitoringv1.ShardStatus{ShardID: strconv.Itoa(shard)}
+		if sset != nil {
+			ss.Replicas = sset.Status.Replicas
+			ss.UpdatedReplicas = sset.Status.UpdatedReplicas
+			ss.AvailableReplicas = sset.Status.ReadyReplicas
+		}
+
+		expected := desired
+		if ss.Replicas > expected {
+			expected = ss.Replicas
+		}
+		if ss.AvailableReplicas < expected {
+			ss.UnavailableReplicas = expected - ss.AvailableReplicas
+		}
+
+		if ss.AvailableReplicas == 0 {
+			notAvailable = append(notAvailable, ss.ShardID)
+		}
+		if ss.UnavailableReplicas > 0 || ss.UpdatedReplicas < ss.Replicas {
+			degraded = append(degraded, ss.ShardID)
+		}
+
+		status.Replicas += ss.Replicas
+		status.UpdatedReplicas += ss.UpdatedReplicas
+		status.AvailableReplicas += ss.AvailableReplicas
+		status.UnavailableReplicas += ss.UnavailableReplicas
+		status.ShardStatuses = append(status.ShardStatuses, ss)
+
+		if sset != nil {
+			status.Shards++
+		}
+	}
+
+	// The scale subresource exposes the number of shards and the pods of
+	// all the shards so that autoscalers can compute per-pod metrics.
+	status.Selector = podSelector(p).String()
+
+	var previous []monitoringv1.Condition
+	if p.Status != nil {
+		previous = p.Status.Conditions
+	}
+
+	newCondition := func(t monitoringv1.ConditionType, s monitoringv1.ConditionStatus, reason, message string) monitoringv1.Condition {
+		return monitoringv1.Condition{
+			Type:               t,
+			Status:             s,
+			LastTransitionTime: now,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: p.Generation,
+		}
+	}
+
+	available := newCondition(monitoringv1.Available, monitoringv1.ConditionTrue, "AllShardsAvailable", "")
+	if len(notAvailable) > 0 {
+		available = newCondition(monitoringv1.Available, monitoringv1.ConditionFalse, "NoPodReady", fmt.Sprintf("No pod is ready for the shards: %s", strings.Join(notAvailable, ", ")))
+	}
+
+	degradedCond := newCondition(monitoringv1.Degraded, monitoringv1.ConditionFalse, "AllPodsReady", "")
+	if len(degraded) > 0 {
+		degradedCond = newCondition(monitoringv1.Degraded, monitoringv1.ConditionTrue, "SomePodsNotReady", fmt.Sprintf("Some pods aren't ready or up-to-date for the shards: %s", strings.Join(degraded, ", ")))
+	}
+
+	reconciled := newCondition(monitoringv1.Reconciled, monitoringv1.ConditionTrue, "ReconciliationSucceeded", "")
+	if reconcileErr != nil {
+		reconciled = newCondition(monitoringv1.Reconciled, monitoringv1.ConditionFalse, "ReconciliationFailed", reconcileErr.Error())
+	}
+
+	conditions := []monitoringv1.Condition{available, reconciled, degradedCond}
+
+	// The DisallowedCapabilities condition is only reported once a
+	// capability has been disabled.
+	disallowedCond := newCondition(monitoringv1.DisallowedCapabilities, monitoringv1.ConditionFalse, "AllCapabilitiesAllowed", "")
+	if disallowed := disallowedCapabilities(p, config); len(disallowed) > 0 {
+		disallowedCond = newCondition(monitoringv1.DisallowedCapabilities, monitoringv1.ConditionTrue, "DisabledByOperator", fmt.Sprintf("The following capabilities are disabled by the operator: %s", strings.Join(disallowed, ", ")))
+	}
+	if disallowedCond.Status == monitoringv1.ConditionTrue || findCondition(previous, monitoringv1.DisallowedCapabilities) != nil {
+		conditions = append(conditions, disallowedCond)
+	}
+
+	// The StaleCredentials condition is only reported when the object
+	// storage configuration of the Thanos sidecar is read from a Secret.
+	if objectStorageConfigSecret(p) != nil {
+		staleCond := newCondition(monitoringv1.StaleCredentials, monitoringv1.ConditionFalse, "CredentialsUpToDate", "")
+		if len(stalePods) > 0 {
+			staleCond = newCondition(monitoringv1.StaleCredentials, monitoringv1.ConditionTrue, "RolloutInProgress", fmt.Sprintf("The following pods run with outdated object storage credentials: %s", strings.Join(stalePods, ", ")))
+		}
+		conditions = append(conditions, staleCond)
+	}
+
+	for i := range conditions {
+		if prev := findCondition(previous, conditions[i].Type); prev != nil && prev.Status == conditions[i].Status {
+			conditions[i].LastTransitionTime = prev.LastTransitionTime
+		}
+	}
+	status.Conditions = conditions
+
+	return status
+}
+
+// podSelector returns the label selector matching the pods of all the shards
+// of the Prometheus object.
+func podSelector(p *monitoringv1.Prometheus) labels.Selector {
+	return labels.SelectorFromSet(labels.Set{
+		"app.kubernetes.io/name":       "prometheus",
+		"app.kubernetes.io/managed-by": "prometheus-operator",
+		"app.kubernetes.io/instance":   p.Name,
+		prometheusNameLabelName:        p.Name,
+	})
+}
+
+func findCondition(conditions []monitoringv1.Condition, t monitoringv1.ConditionType) *monitoringv1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == t {
+			return &conditions[i]
+		}
+	}
+
+	return nil
+}
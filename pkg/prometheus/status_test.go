@@ -0,0 +1,190 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+func TestNewPrometheusStatus(t *testing.T) {
+	replicas := int32(2)
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 3},
+		Spec: monitoringv1.PrometheusSpec{
+			Replicas: &replicas,
+		},
+	}
+
+	sset := func(replicas, updated, ready int32) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			Status: appsv1.StatefulSetStatus{
+				Replicas:        replicas,
+				UpdatedReplicas: updated,
+				ReadyReplicas:   ready,
+			},
+		}
+	}
+
+	earlier := metav1.NewTime(time.Unix(1000, 0))
+	now := metav1.NewTime(time.Unix(2000, 0))
+
+	for _, tc := range []struct {
+		name         string
+		ssets        []*appsv1.StatefulSet
+		thanos       *monitoringv1.ThanosSpec
+		stalePods    []string
+		reconcileErr error
+		previous     []monitoringv1.Condition
+		expected     map[monitoringv1.ConditionType]monitoringv1.ConditionStatus
+		unavailable  int32
+	}{
+		{
+			name:  "all pods ready",
+			ssets: []*appsv1.StatefulSet{sset(2, 2, 2), sset(2, 2, 2)},
+			expected: map[monitoringv1.ConditionType]monitoringv1.ConditionStatus{
+				monitoringv1.Available:  monitoringv1.ConditionTrue,
+				monitoringv1.Reconciled: monitoringv1.ConditionTrue,
+				monitoringv1.Degraded:   monitoringv1.ConditionFalse,
+			},
+		},
+		{
+			name:  "rolling update",
+			ssets: []*appsv1.StatefulSet{sset(2, 1, 1), sset(2, 2, 2)},
+			expected: map[monitoringv1.ConditionType]monitoringv1.ConditionStatus{
+				monitoringv1.Available:  monitoringv1.ConditionTrue,
+				monitoringv1.Reconciled: monitoringv1.ConditionTrue,
+				monitoringv1.Degraded:   monitoringv1.ConditionTrue,
+			},
+			unavailable: 1,
+		},
+		{
+			name:         "missing shard and reconciliation failure",
+			ssets:        []*appsv1.StatefulSet{sset(2, 2, 2), nil},
+			reconcileErr: errors.New("creating statefulset failed"),
+			expected: map[monitoringv1.ConditionType]monitoringv1.ConditionStatus{
+				monitoringv1.Available:  monitoringv1.ConditionFalse,
+				monitoringv1.Reconciled: monitoringv1.ConditionFalse,
+				monitoringv1.Degraded:   monitoringv1.ConditionTrue,
+			},
+			unavailable: 2,
+		},
+		{
+			name:  "previous disallowed capabilities",
+			ssets: []*appsv1.StatefulSet{sset(2, 2, 2), sset(2, 2, 2)},
+			previous: []monitoringv1.Condition{
+				{Type: monitoringv1.DisallowedCapabilities, Status: monitoringv1.ConditionTrue, LastTransitionTime: earlier},
+				{Type: monitoringv1.Available, Status: monitoringv1.ConditionTrue, LastTransitionTime: earlier},
+			},
+			expected: map[monitoringv1.ConditionType]monitoringv1.ConditionStatus{
+				monitoringv1.Available:              monitoringv1.ConditionTrue,
+				monitoringv1.Reconciled:             monitoringv1.ConditionTrue,
+				monitoringv1.Degraded:               monitoringv1.ConditionFalse,
+				monitoringv1.DisallowedCapabilities: monitoringv1.ConditionFalse,
+			},
+		},
+		{
+			name:   "object storage credentials up-to-date",
+			ssets:  []*appsv1.StatefulSet{sset(2, 2, 2), sset(2, 2, 2)},
+			thanos: &monitoringv1.ThanosSpec{ObjectStorageConfig: &v1.SecretKeySelector{Key: "objstore.yaml"}},
+			expected: map[monitoringv1.ConditionType]monitoringv1.ConditionStatus{
+				monitoringv1.Available:        monitoringv1.ConditionTrue,
+				monitoringv1.Reconciled:       monitoringv1.ConditionTrue,
+				monitoringv1.Degraded:         monitoringv1.ConditionFalse,
+				monitoringv1.StaleCredentials: monitoringv1.ConditionFalse,
+			},
+		},
+		{
+			name:      "object storage credentials rotation",
+			ssets:     []*appsv1.StatefulSet{sset(2, 1, 2), sset(2, 2, 2)},
+			thanos:    &monitoringv1.ThanosSpec{ObjectStorageConfig: &v1.SecretKeySelector{Key: "objstore.yaml"}},
+			stalePods: []string{"prometheus-test-0"},
+			expected: map[monitoringv1.ConditionType]monitoringv1.ConditionStatus{
+				monitoringv1.Available:        monitoringv1.ConditionTrue,
+				monitoringv1.Reconciled:       monitoringv1.ConditionTrue,
+				monitoringv1.Degraded:         monitoringv1.ConditionTrue,
+				monitoringv1.StaleCredentials: monitoringv1.ConditionTrue,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := p.DeepCopy()
+			p.Spec.Thanos = tc.thanos
+			if tc.previous != nil {
+				p.Status = &monitoringv1.PrometheusStatus{Conditions: tc.previous}
+			}
+
+			status := newPrometheusStatus(p, operator.Config{}, tc.ssets, tc.stalePods, tc.reconcileErr, now)
+
+			if len(status.ShardStatuses) != len(tc.ssets) {
+				t.Fatalf("expected %d shard statuses, got %d", len(tc.ssets), len(status.ShardStatuses))
+			}
+
+			var shards int32
+			for _, sset := range tc.ssets {
+				if sset != nil {
+					shards++
+				}
+			}
+			if status.Shards != shards {
+				t.Fatalf("expected %d shards, got %d", shards, status.Shards)
+			}
+
+			expectedSelector := "app.kubernetes.io/instance=test,app.kubernetes.io/managed-by=prometheus-operator,app.kubernetes.io/name=prometheus,operator.prometheus.io/name=test"
+			if status.Selector != expectedSelector {
+				t.Fatalf("expected selector %q, got %q", expectedSelector, status.Selector)
+			}
+
+			if status.UnavailableReplicas != tc.unavailable {
+				t.Fatalf("expected %d unavailable replicas, got %d", tc.unavailable, status.UnavailableReplicas)
+			}
+
+			if len(status.Conditions) != len(tc.expected) {
+				t.Fatalf("expected %d conditions, got %d", len(tc.expected), len(status.Conditions))
+			}
+
+			for _, c := range status.Conditions {
+				if c.Status != tc.expected[c.Type] {
+					t.Fatalf("expected condition %s to be %s, got %s", c.Type, tc.expected[c.Type], c.Status)
+				}
+
+				if c.ObservedGeneration != p.Generation {
+					t.Fatalf("expected condition %s to observe generation %d, got %d", c.Type, p.Generation, c.ObservedGeneration)
+				}
+
+				if c.Type == monitoringv1.Reconciled && tc.reconcileErr != nil && c.Message != tc.reconcileErr.Error() {
+					t.Fatalf("expected the reconciled condition to report %q, got %q", tc.reconcileErr, c.Message)
+				}
+
+				// The transition time is preserved when the status doesn't change.
+				expectedTime := now
+				if prev := findCondition(tc.previous, c.Type); prev != nil && prev.Status == c.Status {
+					expectedTime = earlier
+				}
+				if !c.LastTransitionTime.Equal(&expectedTime) {
+					t.Fatalf("expected condition %s to transition at %v, got %v", c.Type, expectedTime, c.LastTransitionTime)
+				}
+			}
+		})
+	}
+}
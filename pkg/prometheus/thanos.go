@@ -0,0 +1,154 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+// addThanosGRPCServerTLSAssets validates the TLS configuration of the gRPC
+// server of the Thanos sidecar and loads the certificates referenced from
+// Secrets and ConfigMaps into the store.
+//
+// When the server certificate is read from a Secret or a ConfigMap, a warning
+// event is emitted if it isn't valid for the names of the governing service
+// since the Thanos Querier wouldn't be able to verify it.
+func (c *Operator) addThanosGRPCServerTLSAssets(ctx context.Context, p *monitoringv1.Prometheus, store *assets.Store) error {
+	if p.Spec.Thanos == nil || p.Spec.Thanos.GRPCServerTLSConfig == nil {
+		return nil
+	}
+
+	tls := p.Spec.Thanos.GRPCServerTLSConfig
+	if err := validateThanosGRPCServerTLSConfig(tls); err != nil {
+		return errors.Wrap(err, "thanos gRPC server TLS config")
+	}
+
+	if err := store.AddTLSConfig(ctx, p.GetNamespace(), tls); err != nil {
+		return errors.Wrap(err, "thanos gRPC server TLS config")
+	}
+
+	if tls.Cert.Secret == nil && tls.Cert.ConfigMap == nil {
+		return nil
+	}
+
+	cert := store.TLSAssets[assets.TLSAssetKeyFromSelector(p.GetNamespace(), tls.Cert)]
+	if err := verifyThanosGRPCServerCertificate([]byte(cert), thanosGRPCServerNames(p, c.config.ClusterDomain)); err != nil {
+		level.Warn(c.logger).Log("msg", "invalid Thanos gRPC server certificate", "err", err, "prometheus", p.Name, "namespace", p.Namespace)
+		operator.RecordWarningEvent(c.eventRecorder, p, operator.InvalidConfigurationReason, "Invalid Thanos gRPC server certificate: %v", err)
+	}
+
+	return nil
+}
+
+// validateThanosGRPCServerTLSConfig checks that the gRPC server of the Thanos
+// sidecar has both a certificate and a key. Clients are verified against the
+// CA when defined which requires the server to use TLS.
+func validateThanosGRPCServerTLSConfig(tls *monitoringv1.TLSConfig) error {
+	hasCert := tls.Cert.Secret != nil || tls.Cert.ConfigMap != nil || tls.CertFile != ""
+	hasKey := tls.KeySecret != nil || tls.KeyFile != ""
+	hasCA := tls.CA.Secret != nil || tls.CA.ConfigMap != nil || tls.CAFile != ""
+
+	if hasCert != hasKey {
+		return errors.New("both the certificate and the key must be defined")
+	}
+
+	if hasCA && !hasCert {
+		return errors.New("the client CA requires the certificate and the key")
+	}
+
+	return nil
+}
+
+// thanosGRPCServerTLSFiles returns the paths of the certificate, key and
+// client CA files of the gRPC server of the Thanos sidecar. The files
+// referenced from Secrets and ConfigMaps are read from the TLS assets volume.
+func thanosGRPCServerTLSFiles(namespace string, tls *monitoringv1.TLSConfig) (cert, key, ca string) {
+	pathForSelector := func(sel monitoringv1.SecretOrConfigMap) string {
+		return path.Join(TLSAssetsDir, assets.TLSAssetKeyFromSelector(namespace, sel).String())
+	}
+
+	cert, key, ca = tls.CertFile, tls.KeyFile, tls.CAFile
+	if tls.Cert.Secret != nil || tls.Cert.ConfigMap != nil {
+		cert = pathForSelector(tls.Cert)
+	}
+	if tls.KeySecret != nil {
+		key = pathForSelector(monitoringv1.SecretOrConfigMap{Secret: tls.KeySecret})
+	}
+	if tls.CA.Secret != nil || tls.CA.ConfigMap != nil {
+		ca = pathForSelector(tls.CA)
+	}
+
+	return cert, key, ca
+}
+
+// usesTLSAssets returns whether the TLS configuration references Secrets or
+// ConfigMaps.
+func usesTLSAssets(tls *monitoringv1.TLSConfig) bool {
+	return tls != nil && (tls.CA != (monitoringv1.SecretOrConfigMap{}) || tls.Cert != (monitoringv1.SecretOrConfigMap{}) || tls.KeySecret != nil)
+}
+
+// thanosGRPCServerNames returns the names used by the Thanos Querier to reach
+// the Thanos sidecars: the governing service and the DNS names of the pods
+// (through DNS SRV records). The names of the first pod stand for the other
+// pods which should be covered by a wildcard certificate.
+func thanosGRPCServerNames(p *monitoringv1.Prometheus, clusterDomain string) []string {
+	suffixes := []string{
+		fmt.Sprintf("%s.%s.svc", governingServiceName, p.Namespace),
+	}
+	if clusterDomain != "" {
+		suffixes = append(suffixes, fmt.Sprintf("%s.%s.svc.%s", governingServiceName, p.Namespace, strings.TrimSuffix(clusterDomain, ".")))
+	}
+
+	names := []string{governingServiceName, fmt.Sprintf("%s.%s", governingServiceName, p.Namespace)}
+	for _, suffix := range suffixes {
+		names = append(names, suffix, fmt.Sprintf("%s-0.%s", prometheusNameByShard(p, 0), suffix))
+	}
+
+	return names
+}
+
+// verifyThanosGRPCServerCertificate checks that the PEM-encoded certificate
+// is valid for at least one of the names.
+func verifyThanosGRPCServerCertificate(data []byte, names []string) error {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return errors.New("failed to decode the PEM certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse the certificate")
+	}
+
+	for _, name := range names {
+		if cert.VerifyHostname(name) == nil {
+			return nil
+		}
+	}
+
+	return errors.Errorf("the certificate isn't valid for any of the names %s", strings.Join(names, ", "))
+}
@@ -0,0 +1,157 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestValidateThanosGRPCServerTLSConfig(t *testing.T) {
+	secret := &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "grpc-tls"}, Key: "tls.key"}
+
+	for _, tc := range []struct {
+		name string
+		tls  monitoringv1.TLSConfig
+		err  bool
+	}{
+		{
+			name: "files",
+			tls:  monitoringv1.TLSConfig{CertFile: "/tls.crt", KeyFile: "/tls.key", CAFile: "/ca.crt"},
+		},
+		{
+			name: "secrets",
+			tls: monitoringv1.TLSConfig{
+				SafeTLSConfig: monitoringv1.SafeTLSConfig{
+					Cert:      monitoringv1.SecretOrConfigMap{Secret: secret},
+					KeySecret: secret,
+				},
+			},
+		},
+		{
+			name: "certificate without key",
+			tls:  monitoringv1.TLSConfig{CertFile: "/tls.crt"},
+			err:  true,
+		},
+		{
+			name: "client CA without certificate",
+			tls:  monitoringv1.TLSConfig{CAFile: "/ca.crt"},
+			err:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateThanosGRPCServerTLSConfig(&tc.tls)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestThanosGRPCServerNames(t *testing.T) {
+	p := &monitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns1"},
+	}
+
+	expected := []string{
+		"prometheus-operated",
+		"prometheus-operated.ns1",
+		"prometheus-operated.ns1.svc",
+		"prometheus-test-0.prometheus-operated.ns1.svc",
+		"prometheus-operated.ns1.svc.cluster.local",
+		"prometheus-test-0.prometheus-operated.ns1.svc.cluster.local",
+	}
+	if diff := cmp.Diff(expected, thanosGRPCServerNames(p, "cluster.local.")); diff != "" {
+		t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestVerifyThanosGRPCServerCertificate(t *testing.T) {
+	names := []string{
+		"prometheus-operated.ns1.svc",
+		"prometheus-test-0.prometheus-operated.ns1.svc",
+	}
+
+	for _, tc := range []struct {
+		name     string
+		dnsNames []string
+		err      bool
+	}{
+		{
+			name:     "service name",
+			dnsNames: []string{"prometheus-operated.ns1.svc"},
+		},
+		{
+			name:     "wildcard pod names",
+			dnsNames: []string{"*.prometheus-operated.ns1.svc"},
+		},
+		{
+			name:     "other namespace",
+			dnsNames: []string{"prometheus-operated.ns2.svc", "*.prometheus-operated.ns2.svc"},
+			err:      true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tmpl := &x509.Certificate{
+				SerialNumber: big.NewInt(1),
+				Subject:      pkix.Name{CommonName: "thanos-sidecar"},
+				DNSNames:     tc.dnsNames,
+				NotBefore:    time.Now().Add(-time.Hour),
+				NotAfter:     time.Now().Add(time.Hour),
+			}
+			der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = verifyThanosGRPCServerCertificate(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), names)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
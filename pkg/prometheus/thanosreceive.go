@@ -0,0 +1,118 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+const (
+	thanosReceiveRemoteWriteName     = "thanos-receive"
+	defaultThanosReceivePort         = 19291
+	defaultThanosReceiveTenantHeader = "THANOS-TENANT"
+
+	// thanosReceiveRetention is the retention of the local TSDB when the
+	// local retention is disabled. It matches the minimum block duration.
+	thanosReceiveRetention = "2h"
+)
+
+// remoteWriteSpecs returns the remote write endpoints of the Prometheus
+// object including the Thanos Receive endpoint when defined.
+func remoteWriteSpecs(p *monitoringv1.Prometheus) []monitoringv1.RemoteWriteSpec {
+	if p.Spec.RemoteWriteToThanosReceive == nil {
+		return p.Spec.RemoteWrite
+	}
+
+	specs := make([]monitoringv1.RemoteWriteSpec, 0, len(p.Spec.RemoteWrite)+1)
+	specs = append(specs, p.Spec.RemoteWrite...)
+
+	return append(specs, thanosReceiveRemoteWriteSpec(p.Namespace, p.Spec.RemoteWriteToThanosReceive))
+}
+
+// thanosReceiveRemoteWriteSpec converts the Thanos Receive configuration into
+// the equivalent remote write endpoint.
+func thanosReceiveRemoteWriteSpec(namespace string, receive *monitoringv1.RemoteWriteToThanosReceiveSpec) monitoringv1.RemoteWriteSpec {
+	scheme := "http"
+	if receive.TLSConfig != nil {
+		scheme = "https"
+	}
+
+	if receive.Service.Namespace != "" {
+		namespace = receive.Service.Namespace
+	}
+
+	port := int32(defaultThanosReceivePort)
+	if receive.Service.Port != nil {
+		port = *receive.Service.Port
+	}
+
+	spec := monitoringv1.RemoteWriteSpec{
+		URL:       fmt.Sprintf("%s://%s.%s.svc:%d/api/v1/receive", scheme, receive.Service.Name, namespace, port),
+		Name:      thanosReceiveRemoteWriteName,
+		TLSConfig: receive.TLSConfig,
+	}
+
+	if receive.Tenant != "" {
+		header := defaultThanosReceiveTenantHeader
+		if receive.TenantHeader != "" {
+			header = receive.TenantHeader
+		}
+		spec.Headers = map[string]string{header: receive.Tenant}
+	}
+
+	// The external labels are applied before the write relabeling so the
+	// labels override them.
+	names := make([]string, 0, len(receive.ExternalLabels))
+	for name := range receive.ExternalLabels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		spec.WriteRelabelConfigs = append(spec.WriteRelabelConfigs, monitoringv1.RelabelConfig{
+			Action:      "replace",
+			TargetLabel: name,
+			Replacement: receive.ExternalLabels[name],
+		})
+	}
+
+	return spec
+}
+
+// validateRemoteWriteToThanosReceive checks that the Thanos Receive
+// configuration doesn't conflict with the other fields.
+func validateRemoteWriteToThanosReceive(p *monitoringv1.Prometheus) error {
+	receive := p.Spec.RemoteWriteToThanosReceive
+	if receive == nil {
+		return nil
+	}
+
+	for _, rw := range p.Spec.RemoteWrite {
+		if rw.Name == thanosReceiveRemoteWriteName {
+			return errors.Errorf("remote write name %q is reserved for remoteWriteToThanosReceive", thanosReceiveRemoteWriteName)
+		}
+	}
+
+	if receive.DisableLocalRetention && !isAgent(p) && (p.Spec.Retention != "" || p.Spec.RetentionSize != "") {
+		return errors.New("remoteWriteToThanosReceive.disableLocalRetention can't be set together with retention or retentionSize")
+	}
+
+	return nil
+}
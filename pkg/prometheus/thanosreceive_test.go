@@ -0,0 +1,184 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+)
+
+func TestRemoteWriteToThanosReceive(t *testing.T) {
+	port := int32(10908)
+
+	for _, tc := range []struct {
+		name     string
+		receive  *monitoringv1.RemoteWriteToThanosReceiveSpec
+		expected string
+	}{
+		{
+			name: "defaults",
+			receive: &monitoringv1.RemoteWriteToThanosReceiveSpec{
+				Service: monitoringv1.ThanosReceiveServiceReference{Name: "thanos-receive"},
+			},
+			expected: `remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+- url: http://thanos-receive.ns1.svc:19291/api/v1/receive
+  remote_timeout: 30s
+  name: thanos-receive
+`,
+		},
+		{
+			name: "tenant, TLS and external labels",
+			receive: &monitoringv1.RemoteWriteToThanosReceiveSpec{
+				Service: monitoringv1.ThanosReceiveServiceReference{
+					Name:      "thanos-receive",
+					Namespace: "thanos",
+					Port:      &port,
+				},
+				Tenant:       "team-a",
+				TenantHeader: "X-Tenant",
+				TLSConfig: &monitoringv1.TLSConfig{
+					SafeTLSConfig: monitoringv1.SafeTLSConfig{
+						CA: monitoringv1.SecretOrConfigMap{
+							Secret: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "tls"}, Key: "ca.crt"},
+						},
+					},
+				},
+				ExternalLabels: map[string]string{
+					"region":  "eu",
+					"cluster": "c1",
+				},
+			},
+			expected: `remote_write:
+- url: http://example.com
+  remote_timeout: 30s
+- url: https://thanos-receive.thanos.svc:10908/api/v1/receive
+  remote_timeout: 30s
+  headers:
+    X-Tenant: team-a
+  name: thanos-receive
+  write_relabel_configs:
+  - target_label: cluster
+    replacement: c1
+    action: replace
+  - target_label: region
+    replacement: eu
+    action: replace
+  tls_config:
+    insecure_skip_verify: false
+    ca_file: /etc/prometheus/certs/secret_ns1_tls_ca.crt
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &monitoringv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns1"},
+				Spec: monitoringv1.PrometheusSpec{
+					RemoteWrite:                []monitoringv1.RemoteWriteSpec{{URL: "http://example.com"}},
+					RemoteWriteToThanosReceive: tc.receive,
+				},
+			}
+
+			cfg, err := (&ConfigGenerator{}).GenerateRemoteWriteConfig(semver.MustParse("2.40.0"), p.Namespace, remoteWriteSpecs(p), &assets.Store{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, string(cfg)); diff != "" {
+				t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateRemoteWriteToThanosReceive(t *testing.T) {
+	receive := &monitoringv1.RemoteWriteToThanosReceiveSpec{
+		Service:               monitoringv1.ThanosReceiveServiceReference{Name: "thanos-receive"},
+		DisableLocalRetention: true,
+	}
+
+	for _, tc := range []struct {
+		name string
+		p    *monitoringv1.Prometheus
+		err  bool
+	}{
+		{
+			name: "valid",
+			p: &monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{RemoteWriteToThanosReceive: receive},
+			},
+		},
+		{
+			name: "reserved remote write name",
+			p: &monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					RemoteWrite:                []monitoringv1.RemoteWriteSpec{{URL: "http://example.com", Name: "thanos-receive"}},
+					RemoteWriteToThanosReceive: receive,
+				},
+			},
+			err: true,
+		},
+		{
+			name: "local retention disabled with retention",
+			p: &monitoringv1.Prometheus{
+				Spec: monitoringv1.PrometheusSpec{
+					Retention:                  "1d",
+					RemoteWriteToThanosReceive: receive,
+				},
+			},
+			err: true,
+		},
+		{
+			name: "local retention disabled with retention size in agent mode",
+			p: &monitoringv1.Prometheus{
+				TypeMeta: metav1.TypeMeta{Kind: monitoringv1alpha1.PrometheusAgentsKind},
+				Spec: monitoringv1.PrometheusSpec{
+					RetentionSize:              "1GB",
+					RemoteWriteToThanosReceive: receive,
+				},
+			},
+		},
+		{
+			name: "agent without remote write",
+			p: &monitoringv1.Prometheus{
+				TypeMeta: metav1.TypeMeta{Kind: monitoringv1alpha1.PrometheusAgentsKind},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateConfigInputs(tc.p)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
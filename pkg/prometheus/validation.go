@@ -0,0 +1,632 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/units"
+	"github.com/pkg/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	relabelReplace   = "replace"
+	relabelKeep      = "keep"
+	relabelDrop      = "drop"
+	relabelHashMod   = "hashmod"
+	relabelLabelMap  = "labelmap"
+	relabelLabelDrop = "labeldrop"
+	relabelLabelKeep = "labelkeep"
+)
+
+// relabelTarget matches the label names and templates accepted by Prometheus
+// for the 'target_label' and 'replacement' fields.
+// Copied from github.com/prometheus/prometheus/pkg/relabel/relabel.go
+var relabelTarget = regexp.MustCompile(`^(?:(?:[a-zA-Z_]|\$(?:\{\w+\}|\w+))+\w*)+$`)
+
+// ValidatePrometheus checks that the given Prometheus object is semantically
+// valid. It doesn't check the references to other objects (e.g. secrets)
+// which requires access to the Kubernetes API.
+func ValidatePrometheus(p *monitoringv1.Prometheus) error {
+	if err := validateByteSize(p.Spec.RetentionSize); err != nil {
+		return errors.Wrap(err, "invalid retentionSize")
+	}
+
+	if err := validateByteSize(p.Spec.EnforcedBodySizeLimit); err != nil {
+		return errors.Wrap(err, "invalid enforcedBodySizeLimit")
+	}
+
+	if err := validateScrapeClasses(p.Spec.ScrapeClasses); err != nil {
+		return errors.Wrap(err, "invalid scrapeClasses")
+	}
+
+	return nil
+}
+
+func validateScrapeClasses(scrapeClasses []monitoringv1.ScrapeClass) error {
+	var (
+		names        = make(map[string]struct{}, len(scrapeClasses))
+		defaultClass string
+	)
+
+	for _, sc := range scrapeClasses {
+		if sc.Name == "" {
+			return errors.New("scrape class name must not be empty")
+		}
+
+		if _, found := names[sc.Name]; found {
+			return errors.Errorf("duplicate scrape class %q", sc.Name)
+		}
+		names[sc.Name] = struct{}{}
+
+		if sc.Default != nil && *sc.Default {
+			if defaultClass != "" {
+				return errors.Errorf("scrape classes %q and %q are both marked as default", defaultClass, sc.Name)
+			}
+			defaultClass = sc.Name
+		}
+
+		if tls := sc.TLSConfig; tls != nil {
+			// The TLS secrets and configmaps would have to be in the namespace
+			// of the scrape object which isn't known in advance.
+			if tls.CA != (monitoringv1.SecretOrConfigMap{}) || tls.Cert != (monitoringv1.SecretOrConfigMap{}) || tls.KeySecret != nil {
+				return errors.Errorf("scrape class %q: only file references are supported in tlsConfig", sc.Name)
+			}
+		}
+
+		if err := ValidateRelabelConfigs(sc.Relabelings); err != nil {
+			return errors.Wrapf(err, "scrape class %q: invalid relabelings", sc.Name)
+		}
+
+		if err := ValidateRelabelConfigs(sc.MetricRelabelings); err != nil {
+			return errors.Wrapf(err, "scrape class %q: invalid metricRelabelings", sc.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateScrapeClassReference checks that the scrape class referenced by a
+// scrape object exists in the Prometheus object.
+func validateScrapeClassReference(p *monitoringv1.Prometheus, name *string) error {
+	if _, found := scrapeClassFor(p, name); !found {
+		return errors.Errorf("scrape class %q not found", *name)
+	}
+
+	return nil
+}
+
+// ValidateProbe checks that the given Probe object is semantically valid. It
+// doesn't check the references to other objects (e.g. secrets) which requires
+// access to the Kubernetes API.
+func ValidateProbe(probe *monitoringv1.Probe) error {
+	if err := validateProberSpec(probe.Spec.ProberSpec); err != nil {
+		return errors.Wrap(err, "invalid prober")
+	}
+
+	if strings.TrimSpace(probe.Spec.Module) != probe.Spec.Module {
+		return errors.Errorf("invalid module %q: leading and trailing whitespaces aren't allowed", probe.Spec.Module)
+	}
+
+	if err := validateDuration(probe.Spec.Interval); err != nil {
+		return errors.Wrap(err, "invalid interval")
+	}
+
+	if err := validateDuration(probe.Spec.ScrapeTimeout); err != nil {
+		return errors.Wrap(err, "invalid scrapeTimeout")
+	}
+
+	if err := validateProbeTargets(probe.Spec.Targets); err != nil {
+		return errors.Wrap(err, "invalid targets")
+	}
+
+	if err := ValidateRelabelConfigs(probe.Spec.MetricRelabelConfigs); err != nil {
+		return errors.Wrap(err, "invalid metricRelabelings")
+	}
+
+	if probe.Spec.OAuth2 != nil {
+		if err := probe.Spec.OAuth2.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := validateProxyConfig(probe.Spec.ProberSpec.ProxyURL, probe.Spec.ProberSpec.ProxyConfig); err != nil {
+		return errors.Wrap(err, "invalid prober")
+	}
+
+	return nil
+}
+
+// ValidateServiceMonitor checks that the durations and the relabel
+// configurations of the given ServiceMonitor object are valid.
+func ValidateServiceMonitor(sm *monitoringv1.ServiceMonitor) error {
+	for i, ep := range sm.Spec.Endpoints {
+		if err := validateScrapeEndpoint(ep.Interval, ep.ScrapeTimeout, ep.RelabelConfigs, ep.MetricRelabelConfigs); err != nil {
+			return errors.Wrapf(err, "endpoints[%d]", i)
+		}
+
+		if err := validateProxyConfig(operator.StringPtrValOrDefault(ep.ProxyURL, ""), ep.ProxyConfig); err != nil {
+			return errors.Wrapf(err, "endpoints[%d]", i)
+		}
+	}
+
+	return nil
+}
+
+// ValidatePodMonitor checks that the durations and the relabel
+// configurations of the given PodMonitor object are valid.
+func ValidatePodMonitor(pm *monitoringv1.PodMonitor) error {
+	for i, ep := range pm.Spec.PodMetricsEndpoints {
+		if err := validateScrapeEndpoint(ep.Interval, ep.ScrapeTimeout, ep.RelabelConfigs, ep.MetricRelabelConfigs); err != nil {
+			return errors.Wrapf(err, "podMetricsEndpoints[%d]", i)
+		}
+
+		if err := validateProxyConfig(operator.StringPtrValOrDefault(ep.ProxyURL, ""), ep.ProxyConfig); err != nil {
+			return errors.Wrapf(err, "podMetricsEndpoints[%d]", i)
+		}
+	}
+
+	return nil
+}
+
+// FieldError describes an invalid field of a resource.
+type FieldError struct {
+	// Path of the field (e.g. "spec.staticConfigs[0].targets[1]").
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// ValidateScrapeConfig checks that the given ScrapeConfig object is
+// semantically valid. It doesn't check the references to other objects (e.g.
+// secrets) which requires access to the Kubernetes API. The returned error is
+// the first invalid field reported by ScrapeConfigFieldErrors.
+func ValidateScrapeConfig(sc *monitoringv1alpha1.ScrapeConfig) error {
+	if errs := ScrapeConfigFieldErrors(sc); len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+// ScrapeConfigFieldErrors returns the invalid fields of the given
+// ScrapeConfig object, in the order of the spec.
+func ScrapeConfigFieldErrors(sc *monitoringv1alpha1.ScrapeConfig) []*FieldError {
+	var errs []*FieldError
+	invalid := func(err error, format string, args ...interface{}) {
+		errs = append(errs, &FieldError{Field: fmt.Sprintf(format, args...), Err: err})
+	}
+
+	for i, c := range sc.Spec.StaticConfigs {
+		for j, t := range c.Targets {
+			if err := validateStaticTarget(t); err != nil {
+				invalid(err, "spec.staticConfigs[%d].targets[%d]", i, j)
+			}
+		}
+
+		for name := range c.Labels {
+			if !model.LabelName(name).IsValid() {
+				invalid(errors.Errorf("invalid label name %q", name), "spec.staticConfigs[%d].labels", i)
+			}
+		}
+	}
+
+	for i, c := range sc.Spec.FileSDConfigs {
+		if len(c.Files) == 0 {
+			invalid(errors.New("at least one file must be specified"), "spec.fileSDConfigs[%d].files", i)
+		}
+
+		for j, f := range c.Files {
+			if err := validateSDFile(f); err != nil {
+				invalid(err, "spec.fileSDConfigs[%d].files[%d]", i, j)
+			}
+		}
+
+		if err := validateDuration(c.RefreshInterval); err != nil {
+			invalid(err, "spec.fileSDConfigs[%d].refreshInterval", i)
+		}
+	}
+
+	for i, c := range sc.Spec.HTTPSDConfigs {
+		u, err := url.Parse(c.URL)
+		switch {
+		case err != nil:
+			invalid(err, "spec.httpSDConfigs[%d].url", i)
+		case (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+			invalid(errors.Errorf("invalid url %q: it should be an absolute HTTP(S) URL", c.URL), "spec.httpSDConfigs[%d].url", i)
+		}
+
+		if err := validateDuration(c.RefreshInterval); err != nil {
+			invalid(err, "spec.httpSDConfigs[%d].refreshInterval", i)
+		}
+
+		for _, e := range validateHTTPClientAuth(c.BasicAuth, c.Authorization, c.TLSConfig) {
+			invalid(e.Err, "spec.httpSDConfigs[%d].%s", i, e.Field)
+		}
+	}
+
+	for i, c := range sc.Spec.DNSSDConfigs {
+		if len(c.Names) == 0 {
+			invalid(errors.New("at least one name must be specified"), "spec.dnsSDConfigs[%d].names", i)
+		}
+
+		switch c.Type {
+		case "", "SRV":
+		case "A", "AAAA", "MX":
+			if c.Port == nil {
+				invalid(errors.Errorf("port is required for %s queries", c.Type), "spec.dnsSDConfigs[%d].port", i)
+			}
+		default:
+			invalid(errors.Errorf("invalid type %q", c.Type), "spec.dnsSDConfigs[%d].type", i)
+		}
+
+		if err := validateDuration(c.RefreshInterval); err != nil {
+			invalid(err, "spec.dnsSDConfigs[%d].refreshInterval", i)
+		}
+	}
+
+	for i, c := range sc.Spec.KubernetesSDConfigs {
+		switch c.Role {
+		case kubernetesSDRolePod, kubernetesSDRoleService, kubernetesSDRoleEndpoint, kubernetesSDRoleEndpointSlice, kubernetesSDRoleNode, kubernetesSDRoleIngress:
+		default:
+			invalid(errors.Errorf("invalid role %q", c.Role), "spec.kubernetesSDConfigs[%d].role", i)
+		}
+	}
+
+	for i, rc := range sc.Spec.RelabelConfigs {
+		if rc == nil {
+			continue
+		}
+		if err := validateRelabelConfig(*rc); err != nil {
+			invalid(err, "spec.relabelings[%d]", i)
+		}
+	}
+
+	if sc.Spec.MetricsPath != "" && !strings.HasPrefix(sc.Spec.MetricsPath, "/") {
+		invalid(errors.Errorf("invalid path %q: it should start with '/'", sc.Spec.MetricsPath), "spec.metricsPath")
+	}
+
+	switch sc.Spec.Scheme {
+	case "", "http", "https":
+	default:
+		invalid(errors.Errorf("invalid scheme %q: it should be either 'http' or 'https'", sc.Spec.Scheme), "spec.scheme")
+	}
+
+	if err := validateDuration(sc.Spec.ScrapeInterval); err != nil {
+		invalid(err, "spec.scrapeInterval")
+	}
+
+	if err := validateDuration(sc.Spec.ScrapeTimeout); err != nil {
+		invalid(err, "spec.scrapeTimeout")
+	}
+
+	for _, e := range validateHTTPClientAuth(sc.Spec.BasicAuth, sc.Spec.Authorization, sc.Spec.TLSConfig) {
+		invalid(e.Err, "spec.%s", e.Field)
+	}
+
+	for i, rc := range sc.Spec.MetricRelabelConfigs {
+		if rc == nil {
+			continue
+		}
+		if err := validateRelabelConfig(*rc); err != nil {
+			invalid(err, "spec.metricRelabelings[%d]", i)
+		}
+	}
+
+	return errs
+}
+
+// validateStaticTarget checks that the target is an address in the form
+// 'host[:port]' like Prometheus expects for the __address__ label.
+func validateStaticTarget(t string) error {
+	if strings.TrimSpace(t) == "" {
+		return errors.New("empty target")
+	}
+
+	u, err := url.Parse("http://" + t)
+	if err != nil {
+		return errors.Wrapf(err, "invalid target %q", t)
+	}
+	if u.Host != t {
+		return errors.Errorf("invalid target %q: it should be in the form 'host[:port]'", t)
+	}
+
+	return nil
+}
+
+// validateHTTPClientAuth applies the same checks as Prometheus to the
+// authentication and TLS settings of an HTTP client. The references to the
+// secrets and configmaps aren't resolved. The fields of the returned errors
+// are relative to the HTTP client configuration.
+func validateHTTPClientAuth(basicAuth *monitoringv1.BasicAuth, authz *monitoringv1.SafeAuthorization, tlsConfig *monitoringv1.SafeTLSConfig) []*FieldError {
+	var errs []*FieldError
+
+	if authz != nil && authz.Credentials != nil {
+		if err := authz.Validate(); err != nil {
+			errs = append(errs, &FieldError{Field: "authorization", Err: err})
+		}
+
+		if basicAuth != nil {
+			errs = append(errs, &FieldError{Field: "authorization", Err: errors.New("basicAuth and authorization can't be set at the same time")})
+		}
+	}
+
+	if tlsConfig != nil {
+		if err := tlsConfig.Validate(); err != nil {
+			errs = append(errs, &FieldError{Field: "tlsConfig", Err: err})
+		}
+	}
+
+	return errs
+}
+
+// validateSDFile applies the same checks as Prometheus to the paths of the
+// file service discovery.
+func validateSDFile(f string) error {
+	switch filepath.Ext(f) {
+	case ".json", ".yml", ".yaml":
+	default:
+		return errors.Errorf("invalid file %q: only .json, .yml and .yaml files are supported", f)
+	}
+
+	if strings.Contains(filepath.Dir(f), "*") {
+		return errors.Errorf("invalid file %q: only the last path segment can contain '*'", f)
+	}
+
+	return nil
+}
+
+func validateScrapeEndpoint(interval, scrapeTimeout string, relabelings, metricRelabelings []*monitoringv1.RelabelConfig) error {
+	if err := validateDuration(interval); err != nil {
+		return errors.Wrap(err, "invalid interval")
+	}
+
+	if err := validateDuration(scrapeTimeout); err != nil {
+		return errors.Wrap(err, "invalid scrapeTimeout")
+	}
+
+	if err := ValidateRelabelConfigs(relabelings); err != nil {
+		return errors.Wrap(err, "invalid relabelings")
+	}
+
+	if err := ValidateRelabelConfigs(metricRelabelings); err != nil {
+		return errors.Wrap(err, "invalid metricRelabelings")
+	}
+
+	return nil
+}
+
+// validateProxyConfig applies the same checks as Prometheus to the proxy
+// settings.
+func validateProxyConfig(proxyURL string, pc monitoringv1.ProxyConfig) error {
+	fromEnvironment := pc.ProxyFromEnvironment != nil && *pc.ProxyFromEnvironment
+	noProxy := pc.NoProxy != nil && *pc.NoProxy != ""
+
+	if fromEnvironment {
+		if proxyURL != "" {
+			return errors.New("proxyUrl and proxyFromEnvironment can't be set at the same time")
+		}
+
+		if noProxy {
+			return errors.New("noProxy and proxyFromEnvironment can't be set at the same time")
+		}
+	}
+
+	if proxyURL == "" {
+		if noProxy {
+			return errors.New("noProxy requires proxyUrl to be set")
+		}
+
+		if len(pc.ProxyConnectHeader) > 0 && !fromEnvironment {
+			return errors.New("proxyConnectHeader requires either proxyUrl or proxyFromEnvironment to be set")
+		}
+	}
+
+	return nil
+}
+
+func validateProberSpec(prober monitoringv1.ProberSpec) error {
+	if prober.URL == "" {
+		return errors.New("url must be specified")
+	}
+
+	// The prober URL is used as the target address hence it shouldn't have
+	// any scheme or path.
+	u, err := url.Parse("http://" + prober.URL)
+	if err != nil {
+		return errors.Wrapf(err, "invalid url %q", prober.URL)
+	}
+	if u.Host != prober.URL {
+		return errors.Errorf("invalid url %q: it should be in the form 'host[:port]'", prober.URL)
+	}
+
+	switch prober.Scheme {
+	case "", "http", "https":
+	default:
+		return errors.Errorf("invalid scheme %q: it should be either 'http' or 'https'", prober.Scheme)
+	}
+
+	if prober.Path != "" && !strings.HasPrefix(prober.Path, "/") {
+		return errors.Errorf("invalid path %q: it should start with '/'", prober.Path)
+	}
+
+	if prober.ProxyURL != "" {
+		if _, err := url.Parse(prober.ProxyURL); err != nil {
+			return errors.Wrapf(err, "invalid proxyUrl %q", prober.ProxyURL)
+		}
+	}
+
+	return nil
+}
+
+func validateProbeTargets(targets monitoringv1.ProbeTargets) error {
+	if targets.StaticConfig == nil && targets.Ingress == nil {
+		return errors.New("either staticConfig or ingress must be specified")
+	}
+
+	if sc := targets.StaticConfig; sc != nil {
+		for i, t := range sc.Targets {
+			if strings.TrimSpace(t) == "" {
+				return errors.Errorf("staticConfig: empty target at index %d", i)
+			}
+
+			if _, err := url.Parse(t); err != nil {
+				return errors.Wrapf(err, "staticConfig: invalid target %q", t)
+			}
+		}
+
+		if sc.StaticFrom != nil {
+			if err := sc.StaticFrom.Validate(); err != nil {
+				return errors.Wrap(err, "staticConfig: invalid staticFrom")
+			}
+
+			if sc.StaticFrom.Secret == nil && sc.StaticFrom.ConfigMap == nil {
+				return errors.New("staticConfig: staticFrom requires either a secret or a configMap")
+			}
+		}
+
+		for name := range sc.Labels {
+			if !model.LabelName(name).IsValid() {
+				return errors.Errorf("staticConfig: invalid label name %q", name)
+			}
+		}
+
+		if err := ValidateRelabelConfigs(sc.RelabelConfigs); err != nil {
+			return errors.Wrap(err, "staticConfig")
+		}
+	}
+
+	if targets.Ingress != nil {
+		if err := ValidateRelabelConfigs(targets.Ingress.RelabelConfigs); err != nil {
+			return errors.Wrap(err, "ingress")
+		}
+	}
+
+	return nil
+}
+
+// ValidateRelabelConfigs applies the same checks as Prometheus when loading
+// the relabel configurations.
+func ValidateRelabelConfigs(rcs []*monitoringv1.RelabelConfig) error {
+	for i, rc := range rcs {
+		if rc == nil {
+			continue
+		}
+
+		if err := validateRelabelConfig(*rc); err != nil {
+			return errors.Wrapf(err, "relabeling[%d]", i)
+		}
+	}
+
+	return nil
+}
+
+// validateRelabelConfig applies the same checks as Prometheus when loading a
+// relabel configuration.
+func validateRelabelConfig(rc monitoringv1.RelabelConfig) error {
+	action := strings.ToLower(rc.Action)
+	if action == "" {
+		action = relabelReplace
+	}
+
+	switch action {
+	case relabelReplace, relabelKeep, relabelDrop, relabelHashMod, relabelLabelMap, relabelLabelDrop, relabelLabelKeep:
+	default:
+		return errors.Errorf("unknown relabel action %q", rc.Action)
+	}
+
+	for _, l := range rc.SourceLabels {
+		if !model.LabelName(l).IsValid() {
+			return errors.Errorf("invalid source label %q", l)
+		}
+	}
+
+	if rc.Regex != "" {
+		if _, err := regexp.Compile("^(?:" + rc.Regex + ")$"); err != nil {
+			return errors.Wrapf(err, "invalid regex %q", rc.Regex)
+		}
+	}
+
+	if action == relabelHashMod && rc.Modulus == 0 {
+		return errors.Errorf("relabel configuration for %s action requires non-zero 'modulus' value", action)
+	}
+
+	if (action == relabelReplace || action == relabelHashMod) && rc.TargetLabel == "" {
+		return errors.Errorf("relabel configuration for %s action requires 'targetLabel' value", action)
+	}
+
+	if action == relabelReplace && !relabelTarget.MatchString(rc.TargetLabel) {
+		return errors.Errorf("%q is invalid 'targetLabel' for %s action", rc.TargetLabel, action)
+	}
+
+	if action == relabelLabelMap && rc.Replacement != "" && !relabelTarget.MatchString(rc.Replacement) {
+		return errors.Errorf("%q is invalid 'replacement' for %s action", rc.Replacement, action)
+	}
+
+	if action == relabelHashMod && !model.LabelName(rc.TargetLabel).IsValid() {
+		return errors.Errorf("%q is invalid 'targetLabel' for %s action", rc.TargetLabel, action)
+	}
+
+	if action == relabelLabelDrop || action == relabelLabelKeep {
+		if len(rc.SourceLabels) > 0 ||
+			rc.TargetLabel != "" ||
+			rc.Modulus != 0 ||
+			(rc.Separator != "" && rc.Separator != ";") ||
+			(rc.Replacement != "" && rc.Replacement != "$1") {
+			return errors.Errorf("%s action requires only 'regex', and no other fields", action)
+		}
+	}
+
+	return nil
+}
+
+func validateDuration(d string) error {
+	if d == "" {
+		return nil
+	}
+
+	_, err := model.ParseDuration(d)
+	return err
+}
+
+// validateByteSize checks that the given string is a size in bytes which can
+// be parsed by Prometheus (e.g. `512MB`).
+func validateByteSize(s string) error {
+	if s == "" {
+		return nil
+	}
+
+	size, err := units.ParseBase2Bytes(s)
+	if err != nil {
+		return err
+	}
+
+	if size < 0 {
+		return errors.Errorf("%q must not be negative", s)
+	}
+
+	return nil
+}
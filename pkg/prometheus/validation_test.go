@@ -0,0 +1,775 @@
+// Copyright 2021 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestValidatePrometheus(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec monitoringv1.PrometheusSpec
+		ok   bool
+	}{
+		{
+			name: "no retention size",
+			ok:   true,
+		},
+		{
+			name: "valid retention size",
+			spec: monitoringv1.PrometheusSpec{RetentionSize: "512MB"},
+			ok:   true,
+		},
+		{
+			name: "valid retention size with binary prefix",
+			spec: monitoringv1.PrometheusSpec{RetentionSize: "1GiB"},
+			ok:   true,
+		},
+		{
+			name: "missing unit",
+			spec: monitoringv1.PrometheusSpec{RetentionSize: "512"},
+		},
+		{
+			name: "invalid unit",
+			spec: monitoringv1.PrometheusSpec{RetentionSize: "1Gi"},
+		},
+		{
+			name: "negative size",
+			spec: monitoringv1.PrometheusSpec{RetentionSize: "-1GB"},
+		},
+		{
+			name: "valid enforced body size limit",
+			spec: monitoringv1.PrometheusSpec{EnforcedBodySizeLimit: "100MB"},
+			ok:   true,
+		},
+		{
+			name: "invalid enforced body size limit",
+			spec: monitoringv1.PrometheusSpec{EnforcedBodySizeLimit: "100 MB"},
+		},
+		{
+			name: "valid scrape classes",
+			spec: monitoringv1.PrometheusSpec{
+				ScrapeClasses: []monitoringv1.ScrapeClass{
+					{
+						Name:    "default",
+						Default: pointer.Bool(true),
+						TLSConfig: &monitoringv1.TLSConfig{
+							CAFile: "/etc/prometheus/certs/ca.crt",
+						},
+					},
+					{
+						Name: "relabel",
+						Relabelings: []*monitoringv1.RelabelConfig{
+							{TargetLabel: "cluster", Replacement: "prod"},
+						},
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "duplicate scrape class names",
+			spec: monitoringv1.PrometheusSpec{
+				ScrapeClasses: []monitoringv1.ScrapeClass{
+					{Name: "foo"},
+					{Name: "foo"},
+				},
+			},
+		},
+		{
+			name: "more than one default scrape class",
+			spec: monitoringv1.PrometheusSpec{
+				ScrapeClasses: []monitoringv1.ScrapeClass{
+					{Name: "foo", Default: pointer.Bool(true)},
+					{Name: "bar", Default: pointer.Bool(true)},
+				},
+			},
+		},
+		{
+			name: "scrape class with TLS secret reference",
+			spec: monitoringv1.PrometheusSpec{
+				ScrapeClasses: []monitoringv1.ScrapeClass{
+					{
+						Name: "foo",
+						TLSConfig: &monitoringv1.TLSConfig{
+							SafeTLSConfig: monitoringv1.SafeTLSConfig{
+								KeySecret: &v1.SecretKeySelector{Key: "tls.key"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "scrape class with invalid relabeling",
+			spec: monitoringv1.PrometheusSpec{
+				ScrapeClasses: []monitoringv1.ScrapeClass{
+					{
+						Name: "foo",
+						MetricRelabelings: []*monitoringv1.RelabelConfig{
+							{Action: "foo"},
+						},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePrometheus(&monitoringv1.Prometheus{Spec: tc.spec})
+
+			if tc.ok && err != nil {
+				t.Fatalf("expecting no error but got %q", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatal("expecting error but got none")
+			}
+		})
+	}
+}
+
+func TestValidateProbe(t *testing.T) {
+	validStaticConfig := monitoringv1.ProbeTargets{
+		StaticConfig: &monitoringv1.ProbeTargetStaticConfig{
+			Targets: []string{"https://example.com"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name string
+		spec monitoringv1.ProbeSpec
+		ok   bool
+	}{
+		{
+			name: "valid static config",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "blackbox-exporter:9115"},
+				Module:     "http_2xx",
+				Targets:    validStaticConfig,
+			},
+			ok: true,
+		},
+		{
+			name: "valid ingress",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "blackbox-exporter:9115", Scheme: "https"},
+				Targets: monitoringv1.ProbeTargets{
+					Ingress: &monitoringv1.ProbeTargetIngress{},
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "missing prober URL",
+			spec: monitoringv1.ProbeSpec{
+				Targets: validStaticConfig,
+			},
+		},
+		{
+			name: "prober URL with scheme",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "http://blackbox-exporter:9115"},
+				Targets:    validStaticConfig,
+			},
+		},
+		{
+			name: "invalid prober scheme",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "blackbox-exporter:9115", Scheme: "ftp"},
+				Targets:    validStaticConfig,
+			},
+		},
+		{
+			name: "invalid module",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "blackbox-exporter:9115"},
+				Module:     "http_2xx ",
+				Targets:    validStaticConfig,
+			},
+		},
+		{
+			name: "invalid interval",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "blackbox-exporter:9115"},
+				Interval:   "30 seconds",
+				Targets:    validStaticConfig,
+			},
+		},
+		{
+			name: "missing targets",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "blackbox-exporter:9115"},
+			},
+		},
+		{
+			name: "valid static targets from configmap",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "blackbox-exporter:9115"},
+				Targets: monitoringv1.ProbeTargets{
+					StaticConfig: &monitoringv1.ProbeTargetStaticConfig{
+						StaticFrom: &monitoringv1.SecretOrConfigMap{
+							ConfigMap: &v1.ConfigMapKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "targets"},
+								Key:                  "targets.txt",
+							},
+						},
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "empty staticFrom",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "blackbox-exporter:9115"},
+				Targets: monitoringv1.ProbeTargets{
+					StaticConfig: &monitoringv1.ProbeTargetStaticConfig{
+						StaticFrom: &monitoringv1.SecretOrConfigMap{},
+					},
+				},
+			},
+		},
+		{
+			name: "empty static target",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "blackbox-exporter:9115"},
+				Targets: monitoringv1.ProbeTargets{
+					StaticConfig: &monitoringv1.ProbeTargetStaticConfig{
+						Targets: []string{""},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid static label",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "blackbox-exporter:9115"},
+				Targets: monitoringv1.ProbeTargets{
+					StaticConfig: &monitoringv1.ProbeTargetStaticConfig{
+						Targets: []string{"https://example.com"},
+						Labels:  map[string]string{"invalid-label": "foo"},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid ingress relabeling",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "blackbox-exporter:9115"},
+				Targets: monitoringv1.ProbeTargets{
+					Ingress: &monitoringv1.ProbeTargetIngress{
+						RelabelConfigs: []*monitoringv1.RelabelConfig{
+							{Action: "replace"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid metric relabeling",
+			spec: monitoringv1.ProbeSpec{
+				ProberSpec: monitoringv1.ProberSpec{URL: "blackbox-exporter:9115"},
+				Targets:    validStaticConfig,
+				MetricRelabelConfigs: []*monitoringv1.RelabelConfig{
+					{Action: "drop", Regex: "foo("},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateProbe(&monitoringv1.Probe{Spec: tc.spec})
+			if tc.ok {
+				if err != nil {
+					t.Fatalf("expecting no error but got %q", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expecting error but got none")
+			}
+		})
+	}
+}
+
+func TestValidateScrapeConfig(t *testing.T) {
+	port := int32(9100)
+
+	for _, tc := range []struct {
+		name string
+		spec monitoringv1alpha1.ScrapeConfigSpec
+		ok   bool
+	}{
+		{
+			name: "valid static config",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				StaticConfigs: []monitoringv1alpha1.StaticConfig{
+					{
+						Targets: []string{"example.com:9100"},
+						Labels:  map[string]string{"env": "prod"},
+					},
+				},
+				MetricsPath:    "/metrics",
+				Scheme:         "https",
+				ScrapeInterval: "30s",
+			},
+			ok: true,
+		},
+		{
+			name: "valid service discovery configs",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				FileSDConfigs: []monitoringv1alpha1.FileSDConfig{
+					{Files: []string{"/etc/prometheus/sd/*.json"}, RefreshInterval: "5m"},
+				},
+				HTTPSDConfigs: []monitoringv1alpha1.HTTPSDConfig{
+					{URL: "https://sd.example.com/targets"},
+				},
+				DNSSDConfigs: []monitoringv1alpha1.DNSSDConfig{
+					{Names: []string{"_node._tcp.example.com"}},
+					{Names: []string{"node.example.com"}, Type: "A", Port: &port},
+				},
+				KubernetesSDConfigs: []monitoringv1alpha1.KubernetesSDConfig{
+					{Role: "endpointslice"},
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "invalid scheme",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				Scheme: "ftp",
+			},
+		},
+		{
+			name: "invalid metrics path",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				MetricsPath: "metrics",
+			},
+		},
+		{
+			name: "invalid scrape interval",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				ScrapeInterval: "1 minute",
+			},
+		},
+		{
+			name: "empty static target",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				StaticConfigs: []monitoringv1alpha1.StaticConfig{
+					{Targets: []string{""}},
+				},
+			},
+		},
+		{
+			name: "invalid static label name",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				StaticConfigs: []monitoringv1alpha1.StaticConfig{
+					{
+						Targets: []string{"example.com:9100"},
+						Labels:  map[string]string{"in-valid": "foo"},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid file extension",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				FileSDConfigs: []monitoringv1alpha1.FileSDConfig{
+					{Files: []string{"/etc/prometheus/sd/targets.txt"}},
+				},
+			},
+		},
+		{
+			name: "wildcard in file directory",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				FileSDConfigs: []monitoringv1alpha1.FileSDConfig{
+					{Files: []string{"/etc/prometheus/*/targets.json"}},
+				},
+			},
+		},
+		{
+			name: "relative HTTP SD url",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				HTTPSDConfigs: []monitoringv1alpha1.HTTPSDConfig{
+					{URL: "/targets"},
+				},
+			},
+		},
+		{
+			name: "DNS A query without port",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				DNSSDConfigs: []monitoringv1alpha1.DNSSDConfig{
+					{Names: []string{"node.example.com"}, Type: "A"},
+				},
+			},
+		},
+		{
+			name: "invalid DNS query type",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				DNSSDConfigs: []monitoringv1alpha1.DNSSDConfig{
+					{Names: []string{"node.example.com"}, Type: "TXT"},
+				},
+			},
+		},
+		{
+			name: "invalid kubernetes role",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				KubernetesSDConfigs: []monitoringv1alpha1.KubernetesSDConfig{
+					{Role: "deployment"},
+				},
+			},
+		},
+		{
+			name: "invalid relabeling",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				RelabelConfigs: []*monitoringv1.RelabelConfig{
+					{Action: "replace"},
+				},
+			},
+		},
+		{
+			name: "static target with scheme",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				StaticConfigs: []monitoringv1alpha1.StaticConfig{
+					{Targets: []string{"http://example.com:9100"}},
+				},
+			},
+		},
+		{
+			name: "valid authorization and TLS config",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				Authorization: &monitoringv1.SafeAuthorization{
+					Type:        "Bearer",
+					Credentials: secretKey("token"),
+				},
+				TLSConfig: &monitoringv1.SafeTLSConfig{
+					CA: monitoringv1.SecretOrConfigMap{Secret: secretKey("ca")},
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "basic authorization type",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				Authorization: &monitoringv1.SafeAuthorization{
+					Type:        "Basic",
+					Credentials: secretKey("token"),
+				},
+			},
+		},
+		{
+			name: "basic auth and authorization",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				BasicAuth: &monitoringv1.BasicAuth{
+					Username: *secretKey("username"),
+					Password: *secretKey("password"),
+				},
+				Authorization: &monitoringv1.SafeAuthorization{
+					Credentials: secretKey("token"),
+				},
+			},
+		},
+		{
+			name: "HTTP SD client certificate without key",
+			spec: monitoringv1alpha1.ScrapeConfigSpec{
+				HTTPSDConfigs: []monitoringv1alpha1.HTTPSDConfig{
+					{
+						URL: "https://sd.example.com/targets",
+						TLSConfig: &monitoringv1.SafeTLSConfig{
+							Cert: monitoringv1.SecretOrConfigMap{Secret: secretKey("cert")},
+						},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateScrapeConfig(&monitoringv1alpha1.ScrapeConfig{Spec: tc.spec})
+			if tc.ok {
+				if err != nil {
+					t.Fatalf("expecting no error but got %q", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expecting error but got none")
+			}
+		})
+	}
+}
+
+func TestScrapeConfigFieldErrors(t *testing.T) {
+	errs := ScrapeConfigFieldErrors(&monitoringv1alpha1.ScrapeConfig{
+		Spec: monitoringv1alpha1.ScrapeConfigSpec{
+			StaticConfigs: []monitoringv1alpha1.StaticConfig{
+				{Targets: []string{"example.com:9100", "example.com/metrics"}},
+			},
+			RelabelConfigs: []*monitoringv1.RelabelConfig{
+				{SourceLabels: []string{"__address__"}, TargetLabel: "instance"},
+				{Action: "hashmod", TargetLabel: "shard"},
+			},
+			ScrapeInterval: "1 minute",
+			Authorization: &monitoringv1.SafeAuthorization{
+				Type:        "basic",
+				Credentials: secretKey("token"),
+			},
+		},
+	})
+
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+
+	expected := []string{
+		"spec.staticConfigs[0].targets[1]",
+		"spec.relabelings[1]",
+		"spec.scrapeInterval",
+		"spec.authorization",
+	}
+	if diff := cmp.Diff(expected, fields); diff != "" {
+		t.Fatalf("Unexpected fields (-want +got):\n%s", diff)
+	}
+}
+
+func secretKey(key string) *v1.SecretKeySelector {
+	return &v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: "secret"},
+		Key:                  key,
+	}
+}
+
+func TestValidateMonitors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		interval string
+		rcs      []*monitoringv1.RelabelConfig
+		mrcs     []*monitoringv1.RelabelConfig
+		proxyURL *string
+		proxy    monitoringv1.ProxyConfig
+		ok       bool
+	}{
+		{
+			name:     "valid",
+			interval: "30s",
+			rcs: []*monitoringv1.RelabelConfig{
+				{SourceLabels: []string{"__meta_kubernetes_pod_name"}, TargetLabel: "pod"},
+			},
+			mrcs: []*monitoringv1.RelabelConfig{
+				{Action: "drop", SourceLabels: []string{"__name__"}, Regex: "go_.*"},
+			},
+			ok: true,
+		},
+		{
+			name:     "invalid interval",
+			interval: "30",
+		},
+		{
+			name: "invalid relabeling",
+			rcs: []*monitoringv1.RelabelConfig{
+				{Action: "hashmod", SourceLabels: []string{"__address__"}, TargetLabel: "__tmp_hash"},
+			},
+		},
+		{
+			name: "invalid metric relabeling",
+			mrcs: []*monitoringv1.RelabelConfig{
+				{Action: "keep", SourceLabels: []string{"__name__"}, Regex: "(go_.*"},
+			},
+		},
+		{
+			name:     "valid proxy settings",
+			proxyURL: pointer.String("http://proxy.example.com"),
+			proxy: monitoringv1.ProxyConfig{
+				NoProxy: pointer.String("10.0.0.0/8"),
+			},
+			ok: true,
+		},
+		{
+			name:     "proxyFromEnvironment with proxyUrl",
+			proxyURL: pointer.String("http://proxy.example.com"),
+			proxy: monitoringv1.ProxyConfig{
+				ProxyFromEnvironment: pointer.Bool(true),
+			},
+		},
+		{
+			name: "noProxy without proxyUrl",
+			proxy: monitoringv1.ProxyConfig{
+				NoProxy: pointer.String("10.0.0.0/8"),
+			},
+		},
+		{
+			name: "proxyConnectHeader without proxy",
+			proxy: monitoringv1.ProxyConfig{
+				ProxyConnectHeader: map[string][]v1.SecretKeySelector{
+					"X-Proxy-Auth": {{LocalObjectReference: v1.LocalObjectReference{Name: "secret"}, Key: "token"}},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := map[string]error{
+				"ServiceMonitor": ValidateServiceMonitor(&monitoringv1.ServiceMonitor{
+					Spec: monitoringv1.ServiceMonitorSpec{
+						Endpoints: []monitoringv1.Endpoint{{
+							Interval:             tc.interval,
+							RelabelConfigs:       tc.rcs,
+							MetricRelabelConfigs: tc.mrcs,
+							ProxyURL:             tc.proxyURL,
+							ProxyConfig:          tc.proxy,
+						}},
+					},
+				}),
+				"PodMonitor": ValidatePodMonitor(&monitoringv1.PodMonitor{
+					Spec: monitoringv1.PodMonitorSpec{
+						PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{{
+							Interval:             tc.interval,
+							RelabelConfigs:       tc.rcs,
+							MetricRelabelConfigs: tc.mrcs,
+							ProxyURL:             tc.proxyURL,
+							ProxyConfig:          tc.proxy,
+						}},
+					},
+				}),
+			}
+
+			for kind, err := range errs {
+				if tc.ok && err != nil {
+					t.Fatalf("%s: expecting no error but got %q", kind, err)
+				}
+
+				if !tc.ok && err == nil {
+					t.Fatalf("%s: expecting error but got none", kind)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRelabelConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		rc   monitoringv1.RelabelConfig
+		ok   bool
+	}{
+		{
+			name: "default action",
+			rc: monitoringv1.RelabelConfig{
+				SourceLabels: []string{"__meta_kubernetes_pod_name"},
+				TargetLabel:  "pod",
+			},
+			ok: true,
+		},
+		{
+			name: "replace with template target label",
+			rc: monitoringv1.RelabelConfig{
+				Action:      "Replace",
+				Regex:       "(.+)",
+				TargetLabel: "label_${1}",
+			},
+			ok: true,
+		},
+		{
+			name: "replace without target label",
+			rc: monitoringv1.RelabelConfig{
+				Action:       "replace",
+				SourceLabels: []string{"__address__"},
+			},
+		},
+		{
+			name: "unknown action",
+			rc: monitoringv1.RelabelConfig{
+				Action: "delete",
+			},
+		},
+		{
+			name: "invalid source label",
+			rc: monitoringv1.RelabelConfig{
+				Action:       "keep",
+				SourceLabels: []string{"foo-bar"},
+			},
+		},
+		{
+			name: "invalid regex",
+			rc: monitoringv1.RelabelConfig{
+				Action: "keep",
+				Regex:  "(foo",
+			},
+		},
+		{
+			name: "valid hashmod",
+			rc: monitoringv1.RelabelConfig{
+				Action:       "hashmod",
+				SourceLabels: []string{"__address__"},
+				TargetLabel:  "__tmp_hash",
+				Modulus:      3,
+			},
+			ok: true,
+		},
+		{
+			name: "hashmod without modulus",
+			rc: monitoringv1.RelabelConfig{
+				Action:       "hashmod",
+				SourceLabels: []string{"__address__"},
+				TargetLabel:  "__tmp_hash",
+			},
+		},
+		{
+			name: "valid labelmap",
+			rc: monitoringv1.RelabelConfig{
+				Action: "labelmap",
+				Regex:  "__meta_kubernetes_pod_label_(.+)",
+			},
+			ok: true,
+		},
+		{
+			name: "valid labeldrop",
+			rc: monitoringv1.RelabelConfig{
+				Action: "labeldrop",
+				Regex:  "pod_template_hash",
+			},
+			ok: true,
+		},
+		{
+			name: "labelkeep with target label",
+			rc: monitoringv1.RelabelConfig{
+				Action:      "labelkeep",
+				Regex:       "job",
+				TargetLabel: "foo",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateRelabelConfig(tc.rc)
+			if tc.ok {
+				if err != nil {
+					t.Fatalf("expecting no error but got %q", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expecting error but got none")
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thanos
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+	"github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
+)
+
+const alertRelabelConfigFile = "alert-relabel-config.yaml"
+
+// validateAlertingSpec checks the alerting fields of the ThanosRuler object
+// which can't be validated by the CRD schema.
+func validateAlertingSpec(tr *monitoringv1.ThanosRuler) error {
+	if tr.Spec.AlertQueryURL != "" {
+		u, err := url.Parse(tr.Spec.AlertQueryURL)
+		if err != nil {
+			return errors.Wrap(err, "invalid alertQueryUrl")
+		}
+
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Errorf("invalid alertQueryUrl %q: must be an absolute HTTP or HTTPS URL", tr.Spec.AlertQueryURL)
+		}
+	}
+
+	if len(tr.Spec.AlertRelabelings) == 0 {
+		return nil
+	}
+
+	if tr.Spec.AlertRelabelConfigs != nil || tr.Spec.AlertRelabelConfigFile != nil {
+		return errors.New("alertRelabelings can't be set at the same time as alertRelabelConfigs or alertRelabelConfigFile")
+	}
+
+	return errors.Wrap(prometheus.ValidateRelabelConfigs(tr.Spec.AlertRelabelings), "invalid alertRelabelings")
+}
+
+// createOrUpdateAlertRelabelConfigSecret generates the alert relabeling
+// configuration of the ThanosRuler object into a secret. It returns the
+// generated configuration or nil when the ThanosRuler object doesn't define
+// alert relabelings.
+func (o *Operator) createOrUpdateAlertRelabelConfigSecret(ctx context.Context, tr *monitoringv1.ThanosRuler) ([]byte, error) {
+	if len(tr.Spec.AlertRelabelings) == 0 {
+		return nil, nil
+	}
+
+	conf, err := prometheus.GenerateRelabelConfigs(tr.Spec.AlertRelabelings)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate the alert relabeling configuration")
+	}
+
+	s := o.makeSecret(tr, alertRelabelConfigSecretName(tr.Name), map[string][]byte{alertRelabelConfigFile: conf})
+	if err := k8sutil.ApplySecret(ctx, o.kclient.CoreV1().Secrets(tr.Namespace), s, operator.ApplyConflictEventHandler(o.eventRecorder, tr)); err != nil {
+		return nil, errors.Wrapf(err, "failed to apply secret %q", s.Name)
+	}
+
+	return conf, nil
+}
+
+// alertRelabelConfigSelector returns the secret key selector of the alert
+// relabeling configuration used by the Thanos ruler or nil when none is
+// defined.
+func alertRelabelConfigSelector(tr *monitoringv1.ThanosRuler) *v1.SecretKeySelector {
+	if len(tr.Spec.AlertRelabelings) > 0 {
+		return &v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{
+				Name: alertRelabelConfigSecretName(tr.Name),
+			},
+			Key: alertRelabelConfigFile,
+		}
+	}
+
+	return tr.Spec.AlertRelabelConfigs
+}
+
+func alertRelabelConfigSecretName(name string) string {
+	return prefixedName(name) + "-alert-relabel-config"
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thanos
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestValidateAlertingSpec(t *testing.T) {
+	relabelFile := "/etc/thanos/relabel.yaml"
+
+	for _, tc := range []struct {
+		name string
+		spec monitoringv1.ThanosRulerSpec
+		err  bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			spec: monitoringv1.ThanosRulerSpec{
+				AlertQueryURL: "https://thanos.example.com/",
+				AlertRelabelings: []*monitoringv1.RelabelConfig{
+					{TargetLabel: "cluster", Replacement: "eu-1"},
+					{Action: "labeldrop", Regex: "replica"},
+				},
+			},
+		},
+		{
+			name: "relative query URL",
+			spec: monitoringv1.ThanosRulerSpec{
+				AlertQueryURL: "thanos.example.com",
+			},
+			err: true,
+		},
+		{
+			name: "unsupported query URL scheme",
+			spec: monitoringv1.ThanosRulerSpec{
+				AlertQueryURL: "ftp://thanos.example.com/",
+			},
+			err: true,
+		},
+		{
+			name: "invalid relabeling",
+			spec: monitoringv1.ThanosRulerSpec{
+				AlertRelabelings: []*monitoringv1.RelabelConfig{
+					{Action: "replace", SourceLabels: []string{"cluster"}},
+				},
+			},
+			err: true,
+		},
+		{
+			name: "relabelings with secret",
+			spec: monitoringv1.ThanosRulerSpec{
+				AlertRelabelings: []*monitoringv1.RelabelConfig{
+					{TargetLabel: "cluster", Replacement: "eu-1"},
+				},
+				AlertRelabelConfigs: &v1.SecretKeySelector{Key: "relabel.yaml"},
+			},
+			err: true,
+		},
+		{
+			name: "relabelings with file",
+			spec: monitoringv1.ThanosRulerSpec{
+				AlertRelabelings: []*monitoringv1.RelabelConfig{
+					{TargetLabel: "cluster", Replacement: "eu-1"},
+				},
+				AlertRelabelConfigFile: &relabelFile,
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateAlertingSpec(&monitoringv1.ThanosRuler{Spec: tc.spec})
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
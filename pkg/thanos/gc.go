@@ -0,0 +1,89 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thanos
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+// newGarbageCollector returns the garbage collector of the objects generated
+// for the ThanosRuler resources.
+func (o *Operator) newGarbageCollector() *operator.GarbageCollector {
+	return operator.NewGarbageCollector(
+		log.With(o.logger, "component", "garbage-collector"),
+		o.kclient,
+		o.metrics,
+		operator.GarbageCollectorOptions{
+			AllowList: o.config.Namespaces.ThanosRulerAllowList,
+			DenyList:  o.config.Namespaces.DenyList,
+			OwnsNamespace: func(ns string) bool {
+				return o.config.Namespaces.Sharding.OwnsNamespace(o.nsThanosRulerInf.GetStore(), ns)
+			},
+			OwnerKinds: []string{monitoringv1.ThanosRulerKind},
+			GetOwner:   o.getOwner,
+			Needed:     neededByThanosRuler,
+			DryRun:     o.config.GarbageCollectionDryRun,
+		},
+	)
+}
+
+// getOwner returns the ThanosRuler object from the API server. The
+// informers' caches can't be used because they only contain the objects
+// matching the instance selector.
+func (o *Operator) getOwner(ctx context.Context, kind, namespace, name string) (metav1.Object, error) {
+	if kind != monitoringv1.ThanosRulerKind {
+		return nil, errors.Errorf("unsupported kind %q", kind)
+	}
+
+	tr, err := o.mclient.MonitoringV1().ThanosRulers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tr, nil
+}
+
+// neededByThanosRuler returns false for the StatefulSets and the rule
+// ConfigMaps which don't match the name of the owner.
+func neededByThanosRuler(_ string, owner metav1.Object, obj operator.GeneratedObject) bool {
+	switch obj.Kind {
+	case operator.StatefulSetKind:
+		return obj.GetName() == prefixedName(owner.GetName())
+
+	case operator.ConfigMapKind:
+		name, found := obj.GetLabels()[labelThanosRulerName]
+		if !found {
+			return true
+		}
+
+		return name == owner.GetName() &&
+			strings.HasPrefix(obj.GetName(), thanosRuleConfigMapName(owner.GetName())+"-")
+	}
+
+	return true
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,8 +43,10 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 )
 
@@ -55,14 +58,17 @@ const (
 // Operator manages life cycle of Thanos deployments and
 // monitoring configurations.
 type Operator struct {
-	kclient kubernetes.Interface
-	mclient monitoringclient.Interface
-	logger  log.Logger
+	kclient       kubernetes.Interface
+	mclient       monitoringclient.Interface
+	logger        log.Logger
+	eventRecorder record.EventRecorder
 
 	thanosRulerInfs *informers.ForResource
 	cmapInfs        *informers.ForResource
 	ruleInfs        *informers.ForResource
 	ssetInfs        *informers.ForResource
+	// endpointSliceInfs is nil when the EndpointSlice API isn't served.
+	endpointSliceInfs *informers.ForResource
 
 	nsThanosRulerInf cache.SharedIndexInformer
 	nsRuleInf        cache.SharedIndexInformer
@@ -76,17 +82,20 @@ type Operator struct {
 
 // Config defines configuration parameters for the Operator.
 type Config struct {
-	Host                   string
-	TLSInsecure            bool
-	TLSConfig              rest.TLSClientConfig
-	ReloaderConfig         operator.ReloaderConfig
-	ThanosDefaultBaseImage string
-	Namespaces             operator.Namespaces
-	Labels                 operator.Labels
-	LocalHost              string
-	LogLevel               string
-	LogFormat              string
-	ThanosRulerSelector    string
+	Host                    string
+	TLSInsecure             bool
+	TLSConfig               rest.TLSClientConfig
+	ReloaderConfig          operator.ReloaderConfig
+	ThanosDefaultBaseImage  string
+	Namespaces              operator.Namespaces
+	Labels                  operator.Labels
+	LocalHost               string
+	LogLevel                string
+	LogFormat               string
+	ThanosRulerSelector     string
+	Workers                 int
+	GarbageCollection       bool
+	GarbageCollectionDryRun bool
 }
 
 // New creates a new controller.
@@ -111,36 +120,59 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 	}
 
 	o := &Operator{
-		kclient: client,
-		mclient: mclient,
-		logger:  logger,
-		queue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "thanos"),
-		metrics: operator.NewMetrics("thanos", r),
+		kclient:       client,
+		mclient:       mclient,
+		logger:        logger,
+		queue:         workqueue.NewNamedRateLimitingQueue(conf.WorkqueueRateLimiter.NewRateLimiter(), "thanos"),
+		metrics:       operator.NewMetrics("thanos", r),
+		eventRecorder: operator.NewEventRecorder(client, "thanos-controller"),
 		config: Config{
-			Host:                   conf.Host,
-			TLSInsecure:            conf.TLSInsecure,
-			TLSConfig:              conf.TLSConfig,
-			ReloaderConfig:         conf.ReloaderConfig,
-			ThanosDefaultBaseImage: conf.ThanosDefaultBaseImage,
-			Namespaces:             conf.Namespaces,
-			Labels:                 conf.Labels,
-			LocalHost:              conf.LocalHost,
-			LogLevel:               conf.LogLevel,
-			LogFormat:              conf.LogFormat,
-			ThanosRulerSelector:    conf.ThanosRulerSelector,
+			Host:                    conf.Host,
+			TLSInsecure:             conf.TLSInsecure,
+			TLSConfig:               conf.TLSConfig,
+			ReloaderConfig:          conf.ReloaderConfig,
+			ThanosDefaultBaseImage:  conf.ThanosDefaultBaseImage,
+			Namespaces:              conf.Namespaces,
+			Labels:                  conf.Labels,
+			LocalHost:               conf.LocalHost,
+			LogLevel:                conf.LogLevel,
+			LogFormat:               conf.LogFormat,
+			ThanosRulerSelector:     conf.ThanosRulerSelector,
+			Workers:                 conf.ThanosRulerWorkers,
+			GarbageCollection:       conf.GarbageCollection,
+			GarbageCollectionDryRun: conf.GarbageCollectionDryRun,
 		},
 	}
 
-	o.cmapInfs, err = informers.NewInformersForResource(
-		informers.NewKubeInformerFactories(
+	cmapListOptions := func(options *metav1.ListOptions) {
+		options.LabelSelector = labelThanosRulerName
+	}
+	cmapFactories := informers.NewKubeInformerFactories(
+		o.config.Namespaces.ThanosRulerAllowList,
+		o.config.Namespaces.DenyList,
+		o.kclient,
+		resyncPeriod,
+		cmapListOptions,
+	)
+	// The controller only needs the metadata of the ConfigMaps to trigger the
+	// reconciliations.
+	if conf.MetadataOnlyInformers {
+		mdClient, err := metadata.NewForConfig(cfg)
+		if err != nil {
+			return nil, errors.Wrap(err, "instantiating metadata client failed")
+		}
+
+		cmapFactories = informers.NewMetadataInformerFactories(
 			o.config.Namespaces.ThanosRulerAllowList,
 			o.config.Namespaces.DenyList,
-			o.kclient,
+			mdClient,
 			resyncPeriod,
-			func(options *metav1.ListOptions) {
-				options.LabelSelector = labelThanosRulerName
-			},
-		),
+			cmapListOptions,
+		)
+	}
+
+	o.cmapInfs, err = informers.NewInformersForResource(
+		cmapFactories,
 		v1.SchemeGroupVersion.WithResource(string(v1.ResourceConfigMaps)),
 	)
 	if err != nil {
@@ -197,6 +229,31 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 		return nil, errors.Wrap(err, "error creating statefulset informers")
 	}
 
+	// The EndpointSlices are watched to discover the Thanos Queriers
+	// selected by queryServiceSelector.
+	endpointSliceSupported, err := k8sutil.IsAPIGroupVersionResourceSupported(client.Discovery(), discoveryv1.SchemeGroupVersion.String(), "endpointslices")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check if the EndpointSlice resource is supported")
+	}
+
+	if endpointSliceSupported {
+		o.endpointSliceInfs, err = informers.NewInformersForResource(
+			informers.NewKubeInformerFactories(
+				o.config.Namespaces.ThanosRulerAllowList,
+				o.config.Namespaces.DenyList,
+				o.kclient,
+				resyncPeriod,
+				nil,
+			),
+			discoveryv1.SchemeGroupVersion.WithResource("endpointslices"),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating endpointslice informers")
+		}
+	} else {
+		level.Warn(o.logger).Log("msg", "the EndpointSlice resource isn't served by the API server, queryServiceSelector won't be supported")
+	}
+
 	newNamespaceInformer := func(o *Operator, allowList map[string]struct{}) cache.SharedIndexInformer {
 		// nsResyncPeriod is used to control how often the namespace informer
 		// should resync. If the unprivileged ListerWatcher is used, then the
@@ -230,15 +287,22 @@ func New(ctx context.Context, conf operator.Config, logger log.Logger, r prometh
 
 // waitForCacheSync waits for the informers' caches to be synced.
 func (o *Operator) waitForCacheSync(ctx context.Context) error {
-	for _, infs := range []struct {
+	type namedInformers struct {
 		name                 string
 		informersForResource *informers.ForResource
-	}{
+	}
+
+	resources := []namedInformers{
 		{"ThanosRuler", o.thanosRulerInfs},
 		{"ConfigMap", o.cmapInfs},
 		{"PrometheusRule", o.ruleInfs},
 		{"StatefulSet", o.ssetInfs},
-	} {
+	}
+	if o.endpointSliceInfs != nil {
+		resources = append(resources, namedInformers{"EndpointSlice", o.endpointSliceInfs})
+	}
+
+	for _, infs := range resources {
 		for _, inf := range infs.informersForResource.GetInformers() {
 			if !operator.WaitForNamedCacheSync(ctx, "thanos", log.With(o.logger, "informer", infs.name), inf.Informer()) {
 				return errors.Errorf("failed to sync cache for %s informer", infs.name)
@@ -284,6 +348,13 @@ func (o *Operator) addHandlers() {
 		DeleteFunc: o.handleStatefulSetDelete,
 		UpdateFunc: o.handleStatefulSetUpdate,
 	})
+	if o.endpointSliceInfs != nil {
+		o.endpointSliceInfs.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    o.handleEndpointSliceAdd,
+			DeleteFunc: o.handleEndpointSliceDelete,
+			UpdateFunc: o.handleEndpointSliceUpdate,
+		})
+	}
 
 	// The controller needs to watch the namespaces in which the rules live
 	// because a label change on a namespace may trigger a configuration
@@ -295,10 +366,10 @@ func (o *Operator) addHandlers() {
 	})
 }
 
-// Run the controller.
-func (o *Operator) Run(ctx context.Context) error {
-	defer o.queue.ShutDown()
-
+// StartInformers checks the connection to the API server, starts the
+// informers and waits for their caches to be synced. The informers run until
+// the context is canceled. It is called by all the replicas of the operator.
+func (o *Operator) StartInformers(ctx context.Context) error {
 	errChan := make(chan error)
 	go func() {
 		v, err := o.kclient.Discovery().ServerVersion()
@@ -320,8 +391,6 @@ func (o *Operator) Run(ctx context.Context) error {
 		return nil
 	}
 
-	go o.worker(ctx)
-
 	go o.thanosRulerInfs.Start(ctx.Done())
 	go o.cmapInfs.Start(ctx.Done())
 	go o.ruleInfs.Start(ctx.Done())
@@ -330,11 +399,31 @@ func (o *Operator) Run(ctx context.Context) error {
 		go o.nsThanosRulerInf.Run(ctx.Done())
 	}
 	go o.ssetInfs.Start(ctx.Done())
+	if o.endpointSliceInfs != nil {
+		go o.endpointSliceInfs.Start(ctx.Done())
+	}
 	if err := o.waitForCacheSync(ctx); err != nil {
 		return err
 	}
 	o.addHandlers()
 
+	return nil
+}
+
+// Run starts the reconciliation workers and the garbage collector and blocks
+// until the context is canceled. StartInformers must have returned before Run
+// is called. With leader election, only the leader calls Run.
+func (o *Operator) Run(ctx context.Context) error {
+	defer o.queue.ShutDown()
+
+	for i := 0; i < o.config.Workers; i++ {
+		go o.worker(ctx)
+	}
+
+	if o.config.GarbageCollection {
+		go o.newGarbageCollector().Run(ctx, operator.DefaultGarbageCollectionInterval)
+	}
+
 	o.metrics.Ready().Set(1)
 	<-ctx.Done()
 	return nil
@@ -408,12 +497,13 @@ func (o *Operator) handleConfigMapDelete(obj interface{}) {
 }
 
 func (o *Operator) handleConfigMapUpdate(old, cur interface{}) {
-	if old.(*v1.ConfigMap).ResourceVersion == cur.(*v1.ConfigMap).ResourceVersion {
+	oldMeta, ok := o.getObjectMeta(old)
+	if !ok {
 		return
 	}
 
 	meta, ok := o.getObjectMeta(cur)
-	if ok {
+	if ok && oldMeta.GetResourceVersion() != meta.GetResourceVersion() {
 		level.Debug(o.logger).Log("msg", "ConfigMap updated")
 		o.metrics.TriggerByCounter("ConfigMap", "update").Inc()
 
@@ -635,22 +725,81 @@ func (o *Operator) sync(ctx context.Context, key string) error {
 	tr.APIVersion = monitoringv1.SchemeGroupVersion.String()
 	tr.Kind = monitoringv1.ThanosRulerKind
 
-	if tr.Spec.Paused {
+	if !o.config.Namespaces.Sharding.OwnsNamespace(o.nsThanosRulerInf.GetStore(), tr.Namespace) {
+		level.Debug(o.logger).Log("msg", "skipping thanos-ruler assigned to another shard", "key", key)
 		return nil
 	}
 
 	logger := log.With(o.logger, "key", key)
+
+	if tr.Spec.Paused {
+		if uErr := o.updateStatus(ctx, logger, key, tr, nil, nil); uErr != nil {
+			level.Warn(logger).Log("msg", "failed to update the status", "err", uErr)
+		}
+		return nil
+	}
+
 	level.Info(logger).Log("msg", "sync thanos-ruler")
 
-	ruleConfigMapNames, err := o.createOrUpdateRuleConfigMaps(ctx, tr)
+	selection, err := o.syncThanosRuler(ctx, logger, key, tr)
+	if uErr := o.updateStatus(ctx, logger, key, tr, selection, err); uErr != nil {
+		level.Warn(logger).Log("msg", "failed to update the status", "err", uErr)
+	}
+
+	return err
+}
+
+// syncThanosRuler reconciles the rule ConfigMaps, the generated
+// configuration, the governing service and the StatefulSet of a ThanosRuler
+// object. It returns the PrometheusRule objects selected for the rule files
+// (nil when the selection didn't happen).
+func (o *Operator) syncThanosRuler(ctx context.Context, logger log.Logger, key string, tr *monitoringv1.ThanosRuler) (*ruleSelection, error) {
+	ruleConfigMapNames, selection, err := o.createOrUpdateRuleConfigMaps(ctx, tr)
 	if err != nil {
-		return err
+		operator.RecordWarningEvent(o.eventRecorder, tr, operator.ConfigGenerationFailedReason, "Failed to generate the rule ConfigMaps: %v", err)
+		return selection, err
+	}
+
+	if err := validateAlertingSpec(tr); err != nil {
+		operator.RecordWarningEvent(o.eventRecorder, tr, operator.InvalidConfigurationReason, "Invalid alerting configuration: %v", err)
+		return selection, err
+	}
+
+	if err := validateQuerySpec(tr); err != nil {
+		operator.RecordWarningEvent(o.eventRecorder, tr, operator.InvalidConfigurationReason, "Invalid query configuration: %v", err)
+		return selection, err
+	}
+
+	if err := o.createOrUpdateQuerySDConfigMap(ctx, tr); err != nil {
+		operator.RecordWarningEvent(o.eventRecorder, tr, operator.ConfigGenerationFailedReason, "Failed to generate the query service discovery configuration: %v", err)
+		return selection, errors.Wrap(err, "creating query service discovery configmap failed")
+	}
+
+	remoteWriteData, err := o.createOrUpdateRemoteWriteSecrets(ctx, tr)
+	if err != nil {
+		operator.RecordWarningEvent(o.eventRecorder, tr, operator.ConfigGenerationFailedReason, "Failed to generate the remote write configuration: %v", err)
+		return selection, errors.Wrap(err, "creating remote write secrets failed")
+	}
+
+	alertRelabelConfig, err := o.createOrUpdateAlertRelabelConfigSecret(ctx, tr)
+	if err != nil {
+		operator.RecordWarningEvent(o.eventRecorder, tr, operator.ConfigGenerationFailedReason, "Failed to generate the alert relabeling configuration: %v", err)
+		return selection, errors.Wrap(err, "creating alert relabeling secret failed")
+	}
+
+	// The Thanos ruler reads the generated configuration only at startup.
+	generatedConfig := make(map[string][]byte, len(remoteWriteData)+1)
+	for k, v := range remoteWriteData {
+		generatedConfig[k] = v
+	}
+	if alertRelabelConfig != nil {
+		generatedConfig[alertRelabelConfigFile] = alertRelabelConfig
 	}
 
 	// Create governing service if it doesn't exist.
 	svcClient := o.kclient.CoreV1().Services(tr.Namespace)
-	if err = k8sutil.CreateOrUpdateService(ctx, svcClient, makeStatefulSetService(tr, o.config)); err != nil {
-		return errors.Wrap(err, "synchronizing governing service failed")
+	if err = k8sutil.ApplyService(ctx, svcClient, makeStatefulSetService(tr, o.config), operator.ApplyConflictEventHandler(o.eventRecorder, tr)); err != nil {
+		return selection, errors.Wrap(err, "synchronizing governing service failed")
 	}
 
 	// Ensure we have a StatefulSet running Thanos deployed.
@@ -658,7 +807,7 @@ func (o *Operator) sync(ctx context.Context, key string) error {
 	obj, err := o.ssetInfs.Get(thanosKeyToStatefulSetKey(key))
 
 	if err != nil && !apierrors.IsNotFound(err) {
-		return errors.Wrap(err, "retrieving statefulset failed")
+		return selection, errors.Wrap(err, "retrieving statefulset failed")
 	}
 
 	exists := !apierrors.IsNotFound(err)
@@ -666,13 +815,17 @@ func (o *Operator) sync(ctx context.Context, key string) error {
 	if !exists {
 		sset, err := makeStatefulSet(tr, o.config, ruleConfigMapNames, "")
 		if err != nil {
-			return errors.Wrap(err, "making thanos statefulset config failed")
+			return selection, errors.Wrap(err, "making thanos statefulset config failed")
+		}
+		if err := setGeneratedConfigHash(sset, generatedConfig); err != nil {
+			return selection, err
 		}
 		operator.SanitizeSTS(sset)
-		if _, err := ssetClient.Create(ctx, sset, metav1.CreateOptions{}); err != nil {
-			return errors.Wrap(err, "creating thanos statefulset failed")
+		if err := k8sutil.ApplyStatefulSet(ctx, ssetClient, sset, operator.ApplyConflictEventHandler(o.eventRecorder, tr)); err != nil {
+			operator.RecordWarningEvent(o.eventRecorder, tr, operator.StatefulSetUpdateFailedReason, "Failed to create the StatefulSet %s: %v", sset.Name, err)
+			return selection, errors.Wrap(err, "creating thanos statefulset failed")
 		}
-		return nil
+		return selection, nil
 	}
 
 	spec := appsv1.StatefulSetSpec{}
@@ -681,14 +834,17 @@ func (o *Operator) sync(ctx context.Context, key string) error {
 		spec = ss.Spec
 	}
 
-	newSSetInputHash, err := createSSetInputHash(*tr, o.config, ruleConfigMapNames, spec)
+	newSSetInputHash, err := createSSetInputHash(*tr, o.config, ruleConfigMapNames, generatedConfig, spec)
 	if err != nil {
-		return err
+		return selection, err
 	}
 
 	sset, err := makeStatefulSet(tr, o.config, ruleConfigMapNames, newSSetInputHash)
 	if err != nil {
-		return errors.Wrap(err, "making the statefulset, to update, failed")
+		return selection, errors.Wrap(err, "making the statefulset, to update, failed")
+	}
+	if err := setGeneratedConfigHash(sset, generatedConfig); err != nil {
+		return selection, err
 	}
 
 	operator.SanitizeSTS(sset)
@@ -696,10 +852,10 @@ func (o *Operator) sync(ctx context.Context, key string) error {
 	oldSSetInputHash := obj.(*appsv1.StatefulSet).ObjectMeta.Annotations[sSetInputHashName]
 	if newSSetInputHash == oldSSetInputHash {
 		level.Debug(logger).Log("msg", "new statefulset generation inputs match current, skipping any actions")
-		return nil
+		return selection, nil
 	}
 
-	err = k8sutil.UpdateStatefulSet(ctx, ssetClient, sset)
+	err = k8sutil.ApplyStatefulSet(ctx, ssetClient, sset, operator.ApplyConflictEventHandler(o.eventRecorder, tr))
 	sErr, ok := err.(*apierrors.StatusError)
 
 	if ok && sErr.ErrStatus.Code == 422 && sErr.ErrStatus.Reason == metav1.StatusReasonInvalid {
@@ -714,16 +870,18 @@ func (o *Operator) sync(ctx context.Context, key string) error {
 		level.Info(logger).Log("msg", "recreating ThanosRuler StatefulSet because the update operation wasn't possible", "reason", strings.Join(failMsg, ", "))
 		propagationPolicy := metav1.DeletePropagationForeground
 		if err := ssetClient.Delete(ctx, sset.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil {
-			return errors.Wrap(err, "failed to delete StatefulSet to avoid forbidden action")
+			operator.RecordWarningEvent(o.eventRecorder, tr, operator.StatefulSetUpdateFailedReason, "Failed to delete the StatefulSet %s to recreate it: %v", sset.Name, err)
+			return selection, errors.Wrap(err, "failed to delete StatefulSet to avoid forbidden action")
 		}
-		return nil
+		return selection, nil
 	}
 
 	if err != nil {
-		return errors.Wrap(err, "updating StatefulSet failed")
+		operator.RecordWarningEvent(o.eventRecorder, tr, operator.StatefulSetUpdateFailedReason, "Failed to update the StatefulSet %s: %v", sset.Name, err)
+		return selection, errors.Wrap(err, "updating StatefulSet failed")
 	}
 
-	return nil
+	return selection, nil
 }
 
 // listMatchingNamespaces lists all the namespaces that match the provided
@@ -739,26 +897,45 @@ func (o *Operator) listMatchingNamespaces(selector labels.Selector) ([]string, e
 	return ns, nil
 }
 
-func createSSetInputHash(tr monitoringv1.ThanosRuler, c Config, ruleConfigMapNames []string, ss interface{}) (string, error) {
+func createSSetInputHash(tr monitoringv1.ThanosRuler, c Config, ruleConfigMapNames []string, generatedConfig map[string][]byte, ss interface{}) (string, error) {
 	hash, err := hashstructure.Hash(struct {
 		TR monitoringv1.ThanosRuler
 		C  Config
 		S  interface{}
 		R  []string `hash:"set"`
-	}{tr, c, ss, ruleConfigMapNames},
+		G  map[string][]byte
+	}{tr, c, ss, ruleConfigMapNames, generatedConfig},
 		nil,
 	)
 	if err != nil {
 		return "", errors.Wrap(
 			err,
-			"failed to calculate combined hash of ThanosRuler StatefulSet, ThanosRuler CRD, config and"+
-				" rule ConfigMap names",
+			"failed to calculate combined hash of ThanosRuler StatefulSet, ThanosRuler CRD, config,"+
+				" rule ConfigMap names and generated configuration",
 		)
 	}
 
 	return fmt.Sprintf("%d", hash), nil
 }
 
+// setGeneratedConfigHash adds the hash of the configuration generated by the
+// operator to the pod template. The Thanos ruler reads it only at startup so
+// changing the pod template triggers a rollout of the pods.
+func setGeneratedConfigHash(sset *appsv1.StatefulSet, data map[string][]byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	hash, err := hashstructure.Hash(data, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to calculate the hash of the generated configuration")
+	}
+
+	sset.Spec.Template.Annotations[generatedConfigHashAnnotation] = fmt.Sprintf("%d", hash)
+
+	return nil
+}
+
 func ListOptions(name string) metav1.ListOptions {
 	return metav1.ListOptions{
 		LabelSelector: fields.SelectorFromSet(fields.Set(map[string]string{
@@ -16,6 +16,8 @@ package thanos
 
 import (
 	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 )
 
 func TestListOptions(t *testing.T) {
@@ -26,3 +28,34 @@ func TestListOptions(t *testing.T) {
 		}
 	}
 }
+
+func TestGeneratedConfigHash(t *testing.T) {
+	tr := &monitoringv1.ThanosRuler{
+		Spec: monitoringv1.ThanosRulerSpec{
+			QueryEndpoints: emptyQueryEndpoints,
+		},
+	}
+
+	hash := func(data map[string][]byte) string {
+		sset, err := makeStatefulSet(tr, defaultTestConfig, nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := setGeneratedConfigHash(sset, data); err != nil {
+			t.Fatal(err)
+		}
+
+		return sset.Spec.Template.Annotations[generatedConfigHashAnnotation]
+	}
+
+	if h := hash(nil); h != "" {
+		t.Fatalf("expected no annotation without generated configuration, got %q", h)
+	}
+
+	h1 := hash(map[string][]byte{remoteWriteConfigFile: []byte("foo")})
+	h2 := hash(map[string][]byte{remoteWriteConfigFile: []byte("bar")})
+	if h1 == "" || h1 == h2 {
+		t.Fatalf("expected different annotations for different configurations, got %q and %q", h1, h2)
+	}
+}
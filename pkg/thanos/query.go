@@ -0,0 +1,283 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thanos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+)
+
+const (
+	querySDDir         = "/etc/thanos/query-sd"
+	queryConfigFile    = "query-config.yaml"
+	queryEndpointsFile = "query-endpoints.json"
+
+	defaultQueryServicePort = "http"
+)
+
+// validateQuerySpec checks that the Thanos Querier endpoints are defined by
+// only one of the query fields.
+func validateQuerySpec(tr *monitoringv1.ThanosRuler) error {
+	if tr.Spec.QueryServiceSelector == nil {
+		return nil
+	}
+
+	if tr.Spec.QueryConfig != nil || len(tr.Spec.QueryEndpoints) > 0 {
+		return errors.New("queryServiceSelector can't be set at the same time as queryConfig or queryEndpoints")
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(&tr.Spec.QueryServiceSelector.Selector); err != nil {
+		return errors.Wrap(err, "invalid queryServiceSelector")
+	}
+
+	return nil
+}
+
+// createOrUpdateQuerySDConfigMap writes the endpoints of the Thanos Queriers
+// selected by the ThanosRuler object into a ConfigMap read by the file
+// service discovery of the Thanos ruler. The ConfigMap isn't part of the
+// StatefulSet inputs since the Thanos ruler reloads the file on changes.
+func (o *Operator) createOrUpdateQuerySDConfigMap(ctx context.Context, tr *monitoringv1.ThanosRuler) error {
+	if tr.Spec.QueryServiceSelector == nil {
+		return nil
+	}
+
+	if o.endpointSliceInfs == nil {
+		return errors.New("queryServiceSelector requires the discovery.k8s.io/v1 EndpointSlice API")
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&tr.Spec.QueryServiceSelector.Selector)
+	if err != nil {
+		return errors.Wrap(err, "invalid queryServiceSelector")
+	}
+
+	var slices []*discoveryv1.EndpointSlice
+	err = o.endpointSliceInfs.ListAllByNamespace(tr.Namespace, selector, func(obj interface{}) {
+		slices = append(slices, obj.(*discoveryv1.EndpointSlice))
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list EndpointSlices")
+	}
+
+	endpoints := queryEndpointsFromSlices(slices, queryServicePort(tr))
+	if len(endpoints) == 0 {
+		level.Warn(o.logger).Log("msg", "no ready Thanos Querier endpoint found", "thanosruler", tr.Name, "namespace", tr.Namespace)
+	}
+
+	data, err := makeQuerySDFiles(endpoints)
+	if err != nil {
+		return err
+	}
+
+	cm := o.makeQuerySDConfigMap(tr, data)
+	if err := k8sutil.ApplyConfigMap(ctx, o.kclient.CoreV1().ConfigMaps(tr.Namespace), cm, operator.ApplyConflictEventHandler(o.eventRecorder, tr)); err != nil {
+		return errors.Wrapf(err, "failed to apply configmap %q", cm.Name)
+	}
+
+	return nil
+}
+
+// queryEndpointsFromSlices returns the sorted list of the ready endpoints
+// (host:port) exposing the given port name.
+func queryEndpointsFromSlices(slices []*discoveryv1.EndpointSlice, portName string) []string {
+	seen := map[string]struct{}{}
+	endpoints := []string{}
+
+	for _, slice := range slices {
+		if slice.AddressType != discoveryv1.AddressTypeIPv4 && slice.AddressType != discoveryv1.AddressTypeIPv6 {
+			continue
+		}
+
+		var port *int32
+		for _, p := range slice.Ports {
+			if p.Name != nil && *p.Name == portName {
+				port = p.Port
+				break
+			}
+		}
+		if port == nil {
+			continue
+		}
+
+		for _, ep := range slice.Endpoints {
+			// A nil value means that the endpoint is ready.
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				continue
+			}
+
+			for _, addr := range ep.Addresses {
+				endpoint := net.JoinHostPort(addr, fmt.Sprintf("%d", *port))
+				if _, found := seen[endpoint]; found {
+					continue
+				}
+				seen[endpoint] = struct{}{}
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+
+	sort.Strings(endpoints)
+
+	return endpoints
+}
+
+// makeQuerySDFiles returns the query configuration of the Thanos ruler and
+// the file service discovery targets.
+func makeQuerySDFiles(endpoints []string) (map[string]string, error) {
+	type targetGroup struct {
+		Targets []string `json:"targets"`
+	}
+
+	groups := []targetGroup{}
+	if len(endpoints) > 0 {
+		groups = append(groups, targetGroup{Targets: endpoints})
+	}
+
+	targets, err := json.Marshal(groups)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the Thanos Querier endpoints")
+	}
+
+	return map[string]string{
+		queryConfigFile:    makeQueryConfig(),
+		queryEndpointsFile: string(targets),
+	}, nil
+}
+
+func makeQueryConfig() string {
+	return fmt.Sprintf(`- file_sd_configs:
+  - files:
+    - %s
+    refresh_interval: 1m
+`, path.Join(querySDDir, queryEndpointsFile))
+}
+
+func (o *Operator) makeQuerySDConfigMap(tr *monitoringv1.ThanosRuler, data map[string]string) *v1.ConfigMap {
+	boolTrue := true
+
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   querySDConfigMapName(tr.Name),
+			Labels: o.config.Labels.Merge(managedByOperatorLabels),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         tr.APIVersion,
+					BlockOwnerDeletion: &boolTrue,
+					Controller:         &boolTrue,
+					Kind:               tr.Kind,
+					Name:               tr.Name,
+					UID:                tr.UID,
+				},
+			},
+		},
+		Data: data,
+	}
+}
+
+func (o *Operator) handleEndpointSliceAdd(obj interface{}) {
+	meta, ok := o.getObjectMeta(obj)
+	if ok {
+		level.Debug(o.logger).Log("msg", "EndpointSlice added")
+		o.metrics.TriggerByCounter("EndpointSlice", "add").Inc()
+
+		o.enqueueForQueryServices(meta.GetNamespace(), meta.GetLabels())
+	}
+}
+
+func (o *Operator) handleEndpointSliceDelete(obj interface{}) {
+	meta, ok := o.getObjectMeta(obj)
+	if ok {
+		level.Debug(o.logger).Log("msg", "EndpointSlice deleted")
+		o.metrics.TriggerByCounter("EndpointSlice", "delete").Inc()
+
+		o.enqueueForQueryServices(meta.GetNamespace(), meta.GetLabels())
+	}
+}
+
+func (o *Operator) handleEndpointSliceUpdate(old, cur interface{}) {
+	oldMeta, ok := o.getObjectMeta(old)
+	if !ok {
+		return
+	}
+
+	meta, ok := o.getObjectMeta(cur)
+	if ok && oldMeta.GetResourceVersion() != meta.GetResourceVersion() {
+		level.Debug(o.logger).Log("msg", "EndpointSlice updated")
+		o.metrics.TriggerByCounter("EndpointSlice", "update").Inc()
+
+		// The labels may have changed in which case the ThanosRuler
+		// objects selecting the previous labels need to be updated too.
+		o.enqueueForQueryServices(oldMeta.GetNamespace(), oldMeta.GetLabels())
+		o.enqueueForQueryServices(meta.GetNamespace(), meta.GetLabels())
+	}
+}
+
+// enqueueForQueryServices enqueues the ThanosRuler objects in the namespace
+// which select Thanos Querier Services with the given labels.
+func (o *Operator) enqueueForQueryServices(ns string, lset map[string]string) {
+	err := o.thanosRulerInfs.ListAllByNamespace(ns, labels.Everything(), func(obj interface{}) {
+		tr := obj.(*monitoringv1.ThanosRuler)
+		if tr.Spec.QueryServiceSelector == nil {
+			return
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(&tr.Spec.QueryServiceSelector.Selector)
+		if err != nil {
+			level.Error(o.logger).Log(
+				"err", errors.Wrap(err, "failed to convert QueryServiceSelector"),
+				"name", tr.Name,
+				"namespace", tr.Namespace,
+			)
+			return
+		}
+
+		if selector.Matches(labels.Set(lset)) {
+			o.enqueue(tr)
+		}
+	})
+	if err != nil {
+		level.Error(o.logger).Log(
+			"msg", "listing all ThanosRuler instances from cache failed",
+			"err", err,
+		)
+	}
+}
+
+func queryServicePort(tr *monitoringv1.ThanosRuler) string {
+	if tr.Spec.QueryServiceSelector.Port != "" {
+		return tr.Spec.QueryServiceSelector.Port
+	}
+
+	return defaultQueryServicePort
+}
+
+func querySDConfigMapName(name string) string {
+	return prefixedName(name) + "-query-sd"
+}
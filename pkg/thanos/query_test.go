@@ -0,0 +1,205 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thanos
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestQueryEndpointsFromSlices(t *testing.T) {
+	var (
+		boolTrue  = true
+		boolFalse = false
+		http      = "http"
+		grpc      = "grpc"
+		httpPort  = int32(9090)
+		grpcPort  = int32(10901)
+	)
+
+	ports := []discoveryv1.EndpointPort{
+		{Name: &grpc, Port: &grpcPort},
+		{Name: &http, Port: &httpPort},
+	}
+
+	slices := []*discoveryv1.EndpointSlice{
+		{
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports:       ports,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.2"}, Conditions: discoveryv1.EndpointConditions{Ready: &boolTrue}},
+				{Addresses: []string{"10.0.0.1"}},
+				{Addresses: []string{"10.0.0.3"}, Conditions: discoveryv1.EndpointConditions{Ready: &boolFalse}},
+			},
+		},
+		{
+			AddressType: discoveryv1.AddressTypeIPv6,
+			Ports:       ports,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"fd00::1"}},
+			},
+		},
+		{
+			// Duplicated endpoint during the migration of the endpoints
+			// between slices.
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports:       ports,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.1"}},
+			},
+		},
+		{
+			AddressType: discoveryv1.AddressTypeFQDN,
+			Ports:       ports,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"thanos-query.example.com"}},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		port     string
+		expected []string
+	}{
+		{
+			port:     "http",
+			expected: []string{"10.0.0.1:9090", "10.0.0.2:9090", "[fd00::1]:9090"},
+		},
+		{
+			port:     "grpc",
+			expected: []string{"10.0.0.1:10901", "10.0.0.2:10901", "[fd00::1]:10901"},
+		},
+		{
+			port:     "web",
+			expected: []string{},
+		},
+	} {
+		t.Run(tc.port, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, queryEndpointsFromSlices(slices, tc.port)); diff != "" {
+				t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMakeQuerySDFiles(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		endpoints []string
+		expected  string
+	}{
+		{
+			name:     "no endpoint",
+			expected: `[]`,
+		},
+		{
+			name:      "endpoints",
+			endpoints: []string{"10.0.0.1:9090", "10.0.0.2:9090"},
+			expected:  `[{"targets":["10.0.0.1:9090","10.0.0.2:9090"]}]`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			files, err := makeQuerySDFiles(tc.endpoints)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, files[queryEndpointsFile]); diff != "" {
+				t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+			}
+
+			expectedConfig := `- file_sd_configs:
+  - files:
+    - /etc/thanos/query-sd/query-endpoints.json
+    refresh_interval: 1m
+`
+			if diff := cmp.Diff(expectedConfig, files[queryConfigFile]); diff != "" {
+				t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateQuerySpec(t *testing.T) {
+	selector := &monitoringv1.QueryServiceSelector{
+		Selector: metav1.LabelSelector{
+			MatchLabels: map[string]string{"app.kubernetes.io/name": "thanos-query"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name string
+		spec monitoringv1.ThanosRulerSpec
+		err  bool
+	}{
+		{
+			name: "query endpoints",
+			spec: monitoringv1.ThanosRulerSpec{QueryEndpoints: []string{"thanos-query:9090"}},
+		},
+		{
+			name: "query service selector",
+			spec: monitoringv1.ThanosRulerSpec{QueryServiceSelector: selector},
+		},
+		{
+			name: "query service selector with query endpoints",
+			spec: monitoringv1.ThanosRulerSpec{
+				QueryServiceSelector: selector,
+				QueryEndpoints:       []string{"thanos-query:9090"},
+			},
+			err: true,
+		},
+		{
+			name: "query service selector with query config",
+			spec: monitoringv1.ThanosRulerSpec{
+				QueryServiceSelector: selector,
+				QueryConfig:          &v1.SecretKeySelector{Key: "query.yaml"},
+			},
+			err: true,
+		},
+		{
+			name: "invalid selector",
+			spec: monitoringv1.ThanosRulerSpec{
+				QueryServiceSelector: &monitoringv1.QueryServiceSelector{
+					Selector: metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "app", Operator: "Unknown"},
+						},
+					},
+				},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateQuerySpec(&monitoringv1.ThanosRuler{Spec: tc.spec})
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
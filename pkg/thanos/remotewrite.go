@@ -0,0 +1,111 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thanos
+
+import (
+	"context"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+	"github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
+)
+
+const remoteWriteConfigFile = "remote-write.yaml"
+
+// createOrUpdateRemoteWriteSecrets generates the remote write configuration
+// and the TLS assets secrets of the ThanosRuler object. It returns the data of
+// the secrets which is used to roll out the pods when it changes since the
+// Thanos ruler reads the configuration only at startup.
+//
+// Nothing is done when the ThanosRuler object doesn't define remote write
+// endpoints.
+func (o *Operator) createOrUpdateRemoteWriteSecrets(ctx context.Context, tr *monitoringv1.ThanosRuler) (map[string][]byte, error) {
+	if len(tr.Spec.RemoteWrite) == 0 {
+		return nil, nil
+	}
+
+	store := assets.NewStore(o.kclient.CoreV1(), o.kclient.CoreV1())
+	if err := prometheus.AddRemoteWriteAssets(ctx, store, tr.Namespace, tr.Spec.RemoteWrite); err != nil {
+		return nil, err
+	}
+
+	// The Thanos ruler embeds the Prometheus remote write client: the
+	// configuration is generated for the default Prometheus version.
+	conf, err := prometheus.NewConfigGenerator(o.logger).GenerateRemoteWriteConfig(
+		semver.MustParse(operator.DefaultPrometheusVersion),
+		tr.Namespace,
+		tr.Spec.RemoteWrite,
+		store,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate the remote write configuration")
+	}
+
+	data := map[string][]byte{remoteWriteConfigFile: conf}
+	tlsAssets := make(map[string][]byte, len(store.TLSAssets))
+	for key, asset := range store.TLSAssets {
+		tlsAssets[key.String()] = []byte(asset)
+		data[key.String()] = []byte(asset)
+	}
+
+	sClient := o.kclient.CoreV1().Secrets(tr.Namespace)
+	for _, s := range []*v1.Secret{
+		o.makeSecret(tr, remoteWriteConfigSecretName(tr.Name), map[string][]byte{remoteWriteConfigFile: conf}),
+		o.makeSecret(tr, tlsAssetsSecretName(tr.Name), tlsAssets),
+	} {
+		if err := k8sutil.ApplySecret(ctx, sClient, s, operator.ApplyConflictEventHandler(o.eventRecorder, tr)); err != nil {
+			return nil, errors.Wrapf(err, "failed to apply secret %q", s.Name)
+		}
+	}
+
+	return data, nil
+}
+
+func (o *Operator) makeSecret(tr *monitoringv1.ThanosRuler, name string, data map[string][]byte) *v1.Secret {
+	boolTrue := true
+
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: o.config.Labels.Merge(managedByOperatorLabels),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         tr.APIVersion,
+					BlockOwnerDeletion: &boolTrue,
+					Controller:         &boolTrue,
+					Kind:               tr.Kind,
+					Name:               tr.Name,
+					UID:                tr.UID,
+				},
+			},
+		},
+		Data: data,
+	}
+}
+
+func remoteWriteConfigSecretName(name string) string {
+	return prefixedName(name) + "-remote-write-config"
+}
+
+func tlsAssetsSecretName(name string) string {
+	return prefixedName(name) + "-tls-assets"
+}
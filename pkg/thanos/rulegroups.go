@@ -0,0 +1,143 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thanos
+
+import (
+	"github.com/ghodss/yaml"
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
+)
+
+// ruleGroupOverride is the parsed version of ThanosRuleGroupOverride.
+type ruleGroupOverride struct {
+	selector labels.Selector
+	groups   map[string]struct{}
+
+	partialResponseStrategy string
+	limit                   *int32
+}
+
+// thanosRuleGroup is a rule group in the format of the Thanos rule files
+// which supports more fields than the format known by the rule validator.
+type thanosRuleGroup struct {
+	monitoringv1.RuleGroup
+	Limit *int32 `json:"limit,omitempty"`
+}
+
+type thanosRuleFile struct {
+	Groups []thanosRuleGroup `json:"groups"`
+}
+
+func makeRuleGroupOverrides(tr *monitoringv1.ThanosRuler) ([]ruleGroupOverride, error) {
+	overrides := make([]ruleGroupOverride, 0, len(tr.Spec.RuleGroupOverrides))
+
+	for i, o := range tr.Spec.RuleGroupOverrides {
+		selector := labels.Everything()
+		if o.RuleSelector != nil {
+			var err error
+			selector, err = metav1.LabelSelectorAsSelector(o.RuleSelector)
+			if err != nil {
+				return nil, errors.Wrapf(err, "ruleGroupOverrides[%d]: invalid ruleSelector", i)
+			}
+		}
+
+		var groups map[string]struct{}
+		if len(o.Groups) > 0 {
+			groups = make(map[string]struct{}, len(o.Groups))
+			for _, g := range o.Groups {
+				groups[g] = struct{}{}
+			}
+		}
+
+		overrides = append(overrides, ruleGroupOverride{
+			selector:                selector,
+			groups:                  groups,
+			partialResponseStrategy: o.PartialResponseStrategy,
+			limit:                   o.Limit,
+		})
+	}
+
+	return overrides, nil
+}
+
+// matchRuleGroupOverride returns the first override matching the rule group
+// of the PrometheusRule object or nil.
+func matchRuleGroupOverride(overrides []ruleGroupOverride, rule *monitoringv1.PrometheusRule, group string) *ruleGroupOverride {
+	for i := range overrides {
+		if !overrides[i].selector.Matches(labels.Set(rule.Labels)) {
+			continue
+		}
+
+		if overrides[i].groups != nil {
+			if _, found := overrides[i].groups[group]; !found {
+				continue
+			}
+		}
+
+		return &overrides[i]
+	}
+
+	return nil
+}
+
+// generateRuleFileContent validates the rules of the PrometheusRule object
+// and returns the content of the rule file after applying the overrides.
+func generateRuleFileContent(rule *monitoringv1.PrometheusRule, overrides []ruleGroupOverride, logger log.Logger) (string, error) {
+	spec := *rule.Spec.DeepCopy()
+
+	var (
+		limits    = make([]*int32, len(spec.Groups))
+		hasLimits bool
+	)
+	for i := range spec.Groups {
+		o := matchRuleGroupOverride(overrides, rule, spec.Groups[i].Name)
+		if o == nil {
+			continue
+		}
+
+		if o.partialResponseStrategy != "" {
+			spec.Groups[i].PartialResponseStrategy = o.partialResponseStrategy
+		}
+
+		if o.limit != nil {
+			limits[i] = o.limit
+			hasLimits = true
+		}
+	}
+
+	content, err := prometheus.GenerateContent(spec, logger)
+	if err != nil || !hasLimits {
+		return content, err
+	}
+
+	// The limits are added after the validation since the rule validator
+	// doesn't know about the field.
+	file := thanosRuleFile{Groups: make([]thanosRuleGroup, len(spec.Groups))}
+	for i, g := range spec.Groups {
+		file.Groups[i] = thanosRuleGroup{RuleGroup: g, Limit: limits[i]}
+	}
+
+	b, err := yaml.Marshal(file)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal content")
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thanos
+
+import (
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestGenerateRuleFileContent(t *testing.T) {
+	limit := int32(10)
+
+	rule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "rules",
+			Labels: map[string]string{"team": "a"},
+		},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{
+					Name:                    "g1",
+					PartialResponseStrategy: "abort",
+					Rules: []monitoringv1.Rule{
+						{Alert: "A", Expr: intstr.FromString("vector(1)")},
+					},
+				},
+				{
+					Name: "g2",
+					Rules: []monitoringv1.Rule{
+						{Record: "r", Expr: intstr.FromString("vector(1)")},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		overrides []monitoringv1.ThanosRuleGroupOverride
+		expected  string
+		err       bool
+	}{
+		{
+			name: "no override",
+			expected: `groups:
+- name: g1
+  partial_response_strategy: abort
+  rules:
+  - alert: A
+    expr: vector(1)
+- name: g2
+  rules:
+  - expr: vector(1)
+    record: r
+`,
+		},
+		{
+			name: "partial response strategy for all groups",
+			overrides: []monitoringv1.ThanosRuleGroupOverride{
+				{PartialResponseStrategy: "warn"},
+			},
+			expected: `groups:
+- name: g1
+  partial_response_strategy: warn
+  rules:
+  - alert: A
+    expr: vector(1)
+- name: g2
+  partial_response_strategy: warn
+  rules:
+  - expr: vector(1)
+    record: r
+`,
+		},
+		{
+			name: "limit for one group",
+			overrides: []monitoringv1.ThanosRuleGroupOverride{
+				{
+					RuleSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+					Groups:       []string{"g2"},
+					Limit:        &limit,
+				},
+			},
+			expected: `groups:
+- name: g1
+  partial_response_strategy: abort
+  rules:
+  - alert: A
+    expr: vector(1)
+- limit: 10
+  name: g2
+  rules:
+  - expr: vector(1)
+    record: r
+`,
+		},
+		{
+			name: "first matching override wins",
+			overrides: []monitoringv1.ThanosRuleGroupOverride{
+				{
+					RuleSelector:            &metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}},
+					PartialResponseStrategy: "warn",
+				},
+				{
+					Groups:                  []string{"g1"},
+					PartialResponseStrategy: "warn",
+				},
+				{
+					PartialResponseStrategy: "abort",
+				},
+			},
+			expected: `groups:
+- name: g1
+  partial_response_strategy: warn
+  rules:
+  - alert: A
+    expr: vector(1)
+- name: g2
+  partial_response_strategy: abort
+  rules:
+  - expr: vector(1)
+    record: r
+`,
+		},
+		{
+			name: "invalid partial response strategy",
+			overrides: []monitoringv1.ThanosRuleGroupOverride{
+				{PartialResponseStrategy: "ignore"},
+			},
+			err: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			overrides, err := makeRuleGroupOverrides(&monitoringv1.ThanosRuler{
+				Spec: monitoringv1.ThanosRulerSpec{RuleGroupOverrides: tc.overrides},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			content, err := generateRuleFileContent(rule, overrides, log.NewNopLogger())
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, content); diff != "" {
+				t.Fatalf("Unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMakeRuleGroupOverridesInvalidSelector(t *testing.T) {
+	_, err := makeRuleGroupOverrides(&monitoringv1.ThanosRuler{
+		Spec: monitoringv1.ThanosRulerSpec{
+			RuleGroupOverrides: []monitoringv1.ThanosRuleGroupOverride{
+				{
+					RuleSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "team", Operator: "Unknown"},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+}
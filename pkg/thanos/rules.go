@@ -23,8 +23,9 @@ import (
 	"strings"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
 	namespacelabeler "github.com/prometheus-operator/prometheus-operator/pkg/namespace-labeler"
-	"github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,22 +42,28 @@ const labelThanosRulerName = "thanos-ruler-name"
 // large buffer.
 var maxConfigMapDataSize = int(float64(v1.MaxSecretSize) * 0.5)
 
-func (o *Operator) createOrUpdateRuleConfigMaps(ctx context.Context, t *monitoringv1.ThanosRuler) ([]string, error) {
+// createOrUpdateRuleConfigMaps generates the ConfigMaps holding the rule
+// files of the ThanosRuler object. It returns the names of the ConfigMaps and
+// the outcome of the PrometheusRule selection (nil when the selection didn't
+// happen).
+func (o *Operator) createOrUpdateRuleConfigMaps(ctx context.Context, t *monitoringv1.ThanosRuler) ([]string, *ruleSelection, error) {
 	cClient := o.kclient.CoreV1().ConfigMaps(t.Namespace)
 
 	namespaces, err := o.selectRuleNamespaces(t)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	newRules, err := o.selectRules(t, namespaces)
+	newRules, rejected, err := o.selectRules(t, namespaces)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	selection := &ruleSelection{selected: len(newRules), rejected: rejected}
+
 	currentConfigMapList, err := cClient.List(ctx, prometheusRulesConfigMapSelector(t.Name))
 	if err != nil {
-		return nil, err
+		return nil, selection, err
 	}
 	currentConfigMaps := currentConfigMapList.Items
 
@@ -78,41 +85,19 @@ func (o *Operator) createOrUpdateRuleConfigMaps(ctx context.Context, t *monitori
 		for _, cm := range currentConfigMaps {
 			currentConfigMapNames = append(currentConfigMapNames, cm.Name)
 		}
-		return currentConfigMapNames, nil
+		return currentConfigMapNames, selection, nil
 	}
 
 	newConfigMaps, err := makeRulesConfigMaps(t, newRules)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to make rules ConfigMaps")
+		return nil, selection, errors.Wrap(err, "failed to make rules ConfigMaps")
 	}
 
 	newConfigMapNames := []string{}
+	applied := map[string]struct{}{}
 	for _, cm := range newConfigMaps {
 		newConfigMapNames = append(newConfigMapNames, cm.Name)
-	}
-
-	if len(currentConfigMaps) == 0 {
-		level.Debug(o.logger).Log(
-			"msg", "no PrometheusRule configmap found, creating new one",
-			"namespace", t.Namespace,
-			"thanos", t.Name,
-		)
-		for _, cm := range newConfigMaps {
-			_, err = cClient.Create(ctx, &cm, metav1.CreateOptions{})
-			if err != nil {
-				return nil, errors.Wrapf(err, "failed to create ConfigMap '%v'", cm.Name)
-			}
-		}
-		return newConfigMapNames, nil
-	}
-
-	// Simply deleting old ConfigMaps and creating new ones for now. Could be
-	// replaced by logic that only deletes obsolete ConfigMaps in the future.
-	for _, cm := range currentConfigMaps {
-		err := cClient.Delete(ctx, cm.Name, metav1.DeleteOptions{})
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to delete current ConfigMap '%v'", cm.Name)
-		}
+		applied[cm.Name] = struct{}{}
 	}
 
 	level.Debug(o.logger).Log(
@@ -121,13 +106,24 @@ func (o *Operator) createOrUpdateRuleConfigMaps(ctx context.Context, t *monitori
 		"thanos", t.Name,
 	)
 	for _, cm := range newConfigMaps {
-		_, err = cClient.Create(ctx, &cm, metav1.CreateOptions{})
+		if err := k8sutil.ApplyConfigMap(ctx, cClient, &cm, operator.ApplyConflictEventHandler(o.eventRecorder, t)); err != nil {
+			return nil, selection, errors.Wrapf(err, "failed to apply ConfigMap '%v'", cm.Name)
+		}
+	}
+
+	// Delete the ConfigMaps which aren't needed anymore.
+	for _, cm := range currentConfigMaps {
+		if _, found := applied[cm.Name]; found {
+			continue
+		}
+
+		err := cClient.Delete(ctx, cm.Name, metav1.DeleteOptions{})
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to create new ConfigMap '%v'", cm.Name)
+			return nil, selection, errors.Wrapf(err, "failed to delete obsolete ConfigMap '%v'", cm.Name)
 		}
 	}
 
-	return newConfigMapNames, nil
+	return newConfigMapNames, selection, nil
 }
 
 func prometheusRulesConfigMapSelector(thanosRulerName string) metav1.ListOptions {
@@ -162,12 +158,22 @@ func (o *Operator) selectRuleNamespaces(p *monitoringv1.ThanosRuler) ([]string,
 	return namespaces, nil
 }
 
-func (o *Operator) selectRules(t *monitoringv1.ThanosRuler, namespaces []string) (map[string]string, error) {
+// selectRules returns the rule files generated from the valid PrometheusRule
+// objects selected by the ThanosRuler object, indexed by file name, and the
+// reasons why the other selected objects were rejected, indexed by
+// <namespace>/<name>.
+func (o *Operator) selectRules(t *monitoringv1.ThanosRuler, namespaces []string) (map[string]string, map[string]string, error) {
 	rules := map[string]string{}
+	rejected := map[string]string{}
 
 	ruleSelector, err := metav1.LabelSelectorAsSelector(t.Spec.RuleSelector)
 	if err != nil {
-		return rules, errors.Wrap(err, "convert rule label selector to selector")
+		return rules, nil, errors.Wrap(err, "convert rule label selector to selector")
+	}
+
+	overrides, err := makeRuleGroupOverrides(t)
+	if err != nil {
+		return rules, nil, err
 	}
 
 	nsLabeler := namespacelabeler.New(
@@ -177,27 +183,25 @@ func (o *Operator) selectRules(t *monitoringv1.ThanosRuler, namespaces []string)
 	)
 
 	for _, ns := range namespaces {
-		var marshalErr error
 		err := o.ruleInfs.ListAllByNamespace(ns, ruleSelector, func(obj interface{}) {
 			promRule := obj.(*monitoringv1.PrometheusRule).DeepCopy()
 
-			if err := nsLabeler.EnforceNamespaceLabel(promRule); err != nil {
-				marshalErr = err
-				return
-			}
-
-			content, err := prometheus.GenerateContent(promRule.Spec, o.logger)
+			content, err := o.generateRuleFile(promRule, nsLabeler, overrides)
 			if err != nil {
-				marshalErr = err
+				rejected[promRule.Namespace+"/"+promRule.Name] = err.Error()
+				level.Warn(o.logger).Log(
+					"msg", "skipping prometheusrule",
+					"error", err.Error(),
+					"prometheusrule", promRule.Name,
+					"namespace", promRule.Namespace,
+					"thanos", t.Name,
+				)
 				return
 			}
 			rules[fmt.Sprintf("%v-%v.yaml", promRule.Namespace, promRule.Name)] = content
 		})
 		if err != nil {
-			return nil, err
-		}
-		if marshalErr != nil {
-			return nil, marshalErr
+			return nil, nil, err
 		}
 	}
 
@@ -215,9 +219,19 @@ func (o *Operator) selectRules(t *monitoringv1.ThanosRuler, namespaces []string)
 
 	if tKey, ok := o.keyFunc(t); ok {
 		o.metrics.SetSelectedResources(tKey, monitoringv1.PrometheusRuleKind, len(rules))
-		o.metrics.SetRejectedResources(tKey, monitoringv1.PrometheusRuleKind, 0)
+		o.metrics.SetRejectedResources(tKey, monitoringv1.PrometheusRuleKind, len(rejected))
 	}
-	return rules, nil
+	return rules, rejected, nil
+}
+
+// generateRuleFile returns the content of the rule file generated from the
+// PrometheusRule object or an error if the object is invalid.
+func (o *Operator) generateRuleFile(promRule *monitoringv1.PrometheusRule, nsLabeler *namespacelabeler.Labeler, overrides []ruleGroupOverride) (string, error) {
+	if err := nsLabeler.EnforceNamespaceLabel(promRule); err != nil {
+		return "", err
+	}
+
+	return generateRuleFileContent(promRule, overrides, o.logger)
 }
 
 // makeRulesConfigMaps takes a ThanosRuler configuration and rule files and
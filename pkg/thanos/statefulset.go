@@ -24,6 +24,7 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
 	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
+	"github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -32,14 +33,15 @@ import (
 )
 
 const (
-	rulesDir                  = "/etc/thanos/rules"
-	storageDir                = "/thanos/data"
-	governingServiceName      = "thanos-ruler-operated"
-	defaultPortName           = "web"
-	defaultRetention          = "24h"
-	defaultEvaluationInterval = "15s"
-	defaultReplicaLabelName   = "thanos_ruler_replica"
-	sSetInputHashName         = "prometheus-operator-input-hash"
+	rulesDir                      = "/etc/thanos/rules"
+	storageDir                    = "/thanos/data"
+	governingServiceName          = "thanos-ruler-operated"
+	defaultPortName               = "web"
+	defaultRetention              = "24h"
+	defaultEvaluationInterval     = "15s"
+	defaultReplicaLabelName       = "thanos_ruler_replica"
+	sSetInputHashName             = "prometheus-operator-input-hash"
+	generatedConfigHashAnnotation = "operator.prometheus.io/generated-config-hash"
 )
 
 var (
@@ -121,6 +123,14 @@ func makeStatefulSet(tr *monitoringv1.ThanosRuler, config Config, ruleConfigMapN
 				EmptyDir: emptyDir,
 			},
 		})
+	} else if storageSpec.Ephemeral != nil {
+		ephemeral := storageSpec.Ephemeral
+		statefulset.Spec.Template.Spec.Volumes = append(statefulset.Spec.Template.Spec.Volumes, v1.Volume{
+			Name: volumeName(tr.Name),
+			VolumeSource: v1.VolumeSource{
+				Ephemeral: ephemeral,
+			},
+		})
 	} else {
 		pvcTemplate := operator.MakeVolumeClaimTemplate(storageSpec.VolumeClaimTemplate)
 		if pvcTemplate.Name == "" {
@@ -146,8 +156,8 @@ func makeStatefulSetSpec(tr *monitoringv1.ThanosRuler, config Config, ruleConfig
 	// details see https://github.com/prometheus-operator/prometheus-operator/issues/1659
 	tr = tr.DeepCopy()
 
-	if tr.Spec.QueryConfig == nil && len(tr.Spec.QueryEndpoints) < 1 {
-		return nil, errors.New(tr.GetName() + ": thanos ruler requires query config or at least one query endpoint to be specified")
+	if tr.Spec.QueryConfig == nil && len(tr.Spec.QueryEndpoints) < 1 && tr.Spec.QueryServiceSelector == nil {
+		return nil, errors.New(tr.GetName() + ": thanos ruler requires query config, a query service selector or at least one query endpoint to be specified")
 	}
 
 	trImagePath, err := operator.BuildImagePath(
@@ -218,7 +228,9 @@ func makeStatefulSetSpec(tr *monitoringv1.ThanosRuler, config Config, ruleConfig
 		trCLIArgs = append(trCLIArgs, fmt.Sprintf("--log.format=%s", tr.Spec.LogFormat))
 	}
 
-	if tr.Spec.QueryConfig != nil {
+	if tr.Spec.QueryServiceSelector != nil {
+		trCLIArgs = append(trCLIArgs, "--query.config-file="+path.Join(querySDDir, queryConfigFile))
+	} else if tr.Spec.QueryConfig != nil {
 		trCLIArgs = append(trCLIArgs, "--query.config=$(QUERY_CONFIG)")
 		trEnvVars = append(trEnvVars, v1.EnvVar{
 			Name: "QUERY_CONFIG",
@@ -261,6 +273,21 @@ func makeStatefulSetSpec(tr *monitoringv1.ThanosRuler, config Config, ruleConfig
 		})
 	}
 
+	if len(tr.Spec.RemoteWrite) > 0 {
+		trCLIArgs = append(trCLIArgs, "--remote-write.config=$(REMOTE_WRITE_CONFIG)")
+		trEnvVars = append(trEnvVars, v1.EnvVar{
+			Name: "REMOTE_WRITE_CONFIG",
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: remoteWriteConfigSecretName(tr.Name),
+					},
+					Key: remoteWriteConfigFile,
+				},
+			},
+		})
+	}
+
 	if tr.Spec.TracingConfig != nil {
 		trCLIArgs = append(trCLIArgs, "--tracing.config=$(TRACING_CONFIG)")
 		trEnvVars = append(trEnvVars, v1.EnvVar{
@@ -298,12 +325,12 @@ func makeStatefulSetSpec(tr *monitoringv1.ThanosRuler, config Config, ruleConfig
 
 	if tr.Spec.AlertRelabelConfigFile != nil {
 		trCLIArgs = append(trCLIArgs, "--alert.relabel-config-file="+*tr.Spec.AlertRelabelConfigFile)
-	} else if tr.Spec.AlertRelabelConfigs != nil {
+	} else if sel := alertRelabelConfigSelector(tr); sel != nil {
 		trCLIArgs = append(trCLIArgs, "--alert.relabel-config=$(ALERT_RELABEL_CONFIG)")
 		trEnvVars = append(trEnvVars, v1.EnvVar{
 			Name: "ALERT_RELABEL_CONFIG",
 			ValueFrom: &v1.EnvVarSource{
-				SecretKeyRef: tr.Spec.AlertRelabelConfigs,
+				SecretKeyRef: sel,
 			},
 		})
 	}
@@ -383,6 +410,42 @@ func makeStatefulSetSpec(tr *monitoringv1.ThanosRuler, config Config, ruleConfig
 		},
 	}
 
+	if len(tr.Spec.RemoteWrite) > 0 {
+		// The remote write configuration references the TLS assets with
+		// the same paths as for Prometheus.
+		trVolumes = append(trVolumes, v1.Volume{
+			Name: "tls-assets",
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: tlsAssetsSecretName(tr.Name),
+				},
+			},
+		})
+		trVolumeMounts = append(trVolumeMounts, v1.VolumeMount{
+			Name:      "tls-assets",
+			ReadOnly:  true,
+			MountPath: prometheus.TLSAssetsDir,
+		})
+	}
+
+	if tr.Spec.QueryServiceSelector != nil {
+		trVolumes = append(trVolumes, v1.Volume{
+			Name: "query-sd",
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: querySDConfigMapName(tr.Name),
+					},
+				},
+			},
+		})
+		trVolumeMounts = append(trVolumeMounts, v1.VolumeMount{
+			Name:      "query-sd",
+			ReadOnly:  true,
+			MountPath: querySDDir,
+		})
+	}
+
 	for _, name := range ruleConfigMapNames {
 		trVolumes = append(trVolumes, v1.Volume{
 			Name: name,
@@ -418,6 +481,13 @@ func makeStatefulSetSpec(tr *monitoringv1.ThanosRuler, config Config, ruleConfig
 		return nil, errors.Wrap(err, "failed to merge containers spec")
 	}
 
+	// There are no operator-generated init containers yet but merging them
+	// validates the user-defined ones.
+	initContainers, err := k8sutil.MergePatchContainers(nil, tr.Spec.InitContainers)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to merge init containers spec")
+	}
+
 	terminationGracePeriod := int64(120)
 
 	var minReadySeconds int32
@@ -448,12 +518,15 @@ func makeStatefulSetSpec(tr *monitoringv1.ThanosRuler, config Config, ruleConfig
 				ServiceAccountName:            tr.Spec.ServiceAccountName,
 				TerminationGracePeriodSeconds: &terminationGracePeriod,
 				Containers:                    containers,
-				InitContainers:                tr.Spec.InitContainers,
+				InitContainers:                initContainers,
 				Volumes:                       trVolumes,
 				SecurityContext:               tr.Spec.SecurityContext,
 				Tolerations:                   tr.Spec.Tolerations,
 				Affinity:                      tr.Spec.Affinity,
 				TopologySpreadConstraints:     tr.Spec.TopologySpreadConstraints,
+				HostAliases:                   operator.MakeHostAliases(tr.Spec.HostAliases),
+				DNSPolicy:                     tr.Spec.DNSPolicy,
+				DNSConfig:                     tr.Spec.DNSConfig,
 			},
 		},
 	}, nil
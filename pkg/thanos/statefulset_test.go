@@ -247,6 +247,39 @@ func TestTracing(t *testing.T) {
 	}
 }
 
+func TestRemoteWrite(t *testing.T) {
+	sset, err := makeStatefulSet(&monitoringv1.ThanosRuler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: monitoringv1.ThanosRulerSpec{
+			QueryEndpoints: emptyQueryEndpoints,
+			RemoteWrite: []monitoringv1.RemoteWriteSpec{
+				{URL: "http://receive.example.com/api/v1/receive"},
+			},
+		},
+	}, defaultTestConfig, nil, "")
+	require.NoError(t, err)
+
+	ruler := sset.Spec.Template.Spec.Containers[0]
+	require.Equal(t, "thanos-ruler", ruler.Name)
+	require.Contains(t, ruler.Args, "--remote-write.config=$(REMOTE_WRITE_CONFIG)")
+	require.Contains(t, ruler.Env, v1.EnvVar{
+		Name: "REMOTE_WRITE_CONFIG",
+		ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "thanos-ruler-test-remote-write-config"},
+				Key:                  "remote-write.yaml",
+			},
+		},
+	})
+	require.Contains(t, ruler.VolumeMounts, v1.VolumeMount{Name: "tls-assets", ReadOnly: true, MountPath: "/etc/prometheus/certs"})
+	require.Contains(t, sset.Spec.Template.Spec.Volumes, v1.Volume{
+		Name: "tls-assets",
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{SecretName: "thanos-ruler-test-tls-assets"},
+		},
+	})
+}
+
 func TestObjectStorage(t *testing.T) {
 	testKey := "thanos-objstore-config-secret"
 
@@ -429,6 +462,97 @@ func TestAlertRelabelFile(t *testing.T) {
 	}
 }
 
+func TestAlertRelabelings(t *testing.T) {
+	sset, err := makeStatefulSet(&monitoringv1.ThanosRuler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: monitoringv1.ThanosRulerSpec{
+			QueryEndpoints: emptyQueryEndpoints,
+			AlertRelabelings: []*monitoringv1.RelabelConfig{
+				{TargetLabel: "cluster", Replacement: "eu-1"},
+			},
+		},
+	}, defaultTestConfig, nil, "")
+	if err != nil {
+		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
+	}
+
+	var containsEnvVar bool
+	for _, env := range sset.Spec.Template.Spec.Containers[0].Env {
+		if env.Name == "ALERT_RELABEL_CONFIG" {
+			ref := env.ValueFrom.SecretKeyRef
+			if ref.Name == "thanos-ruler-test-alert-relabel-config" && ref.Key == alertRelabelConfigFile {
+				containsEnvVar = true
+				break
+			}
+		}
+	}
+	if !containsEnvVar {
+		t.Fatalf("Thanos ruler is missing expected ALERT_RELABEL_CONFIG env var referencing the generated secret")
+	}
+
+	var containsArg bool
+	const expectedArg = "--alert.relabel-config=$(ALERT_RELABEL_CONFIG)"
+	for _, arg := range sset.Spec.Template.Spec.Containers[0].Args {
+		if arg == expectedArg {
+			containsArg = true
+			break
+		}
+	}
+	if !containsArg {
+		t.Fatalf("Thanos ruler is missing expected argument: %s", expectedArg)
+	}
+}
+
+func TestQueryServiceSelector(t *testing.T) {
+	sset, err := makeStatefulSet(&monitoringv1.ThanosRuler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: monitoringv1.ThanosRulerSpec{
+			QueryServiceSelector: &monitoringv1.QueryServiceSelector{
+				Selector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"app.kubernetes.io/name": "thanos-query"},
+				},
+			},
+		},
+	}, defaultTestConfig, nil, "")
+	if err != nil {
+		t.Fatalf("Unexpected error while making StatefulSet: %v", err)
+	}
+
+	var containsArg bool
+	const expectedArg = "--query.config-file=/etc/thanos/query-sd/query-config.yaml"
+	for _, arg := range sset.Spec.Template.Spec.Containers[0].Args {
+		if arg == expectedArg {
+			containsArg = true
+		}
+		if strings.HasPrefix(arg, "--query=") {
+			t.Fatalf("Thanos ruler has unexpected argument: %s", arg)
+		}
+	}
+	if !containsArg {
+		t.Fatalf("Thanos ruler is missing expected argument: %s", expectedArg)
+	}
+
+	var containsVolume bool
+	for _, vol := range sset.Spec.Template.Spec.Volumes {
+		if vol.Name == "query-sd" && vol.ConfigMap != nil && vol.ConfigMap.Name == "thanos-ruler-test-query-sd" {
+			containsVolume = true
+		}
+	}
+	if !containsVolume {
+		t.Fatal("Thanos ruler is missing the query service discovery volume")
+	}
+
+	var containsMount bool
+	for _, mount := range sset.Spec.Template.Spec.Containers[0].VolumeMounts {
+		if mount.Name == "query-sd" && mount.MountPath == querySDDir {
+			containsMount = true
+		}
+	}
+	if !containsMount {
+		t.Fatal("Thanos ruler is missing the query service discovery volume mount")
+	}
+}
+
 func TestLabelsAndAlertDropLabels(t *testing.T) {
 	labelPrefix := "--label="
 	alertDropLabelPrefix := "--alert.label-drop="
@@ -1019,3 +1143,23 @@ func TestStatefulSetMinReadySeconds(t *testing.T) {
 		t.Fatalf("expected MinReadySeconds to be %d but got %d", expect, statefulSet.MinReadySeconds)
 	}
 }
+
+func TestStatefulSetDNS(t *testing.T) {
+	dnsConfig := &v1.PodDNSConfig{
+		Nameservers: []string{"8.8.8.8"},
+	}
+
+	tr := monitoringv1.ThanosRuler{
+		Spec: monitoringv1.ThanosRulerSpec{
+			QueryEndpoints: emptyQueryEndpoints,
+			DNSPolicy:      v1.DNSNone,
+			DNSConfig:      dnsConfig,
+		},
+	}
+
+	statefulSet, err := makeStatefulSetSpec(&tr, defaultTestConfig, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, v1.DNSNone, statefulSet.Template.Spec.DNSPolicy)
+	require.Equal(t, dnsConfig, statefulSet.Template.Spec.DNSConfig)
+}
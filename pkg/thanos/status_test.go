@@ -0,0 +1,208 @@
+// Copyright 2023 The prometheus-operator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thanos
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func TestNewThanosRulerStatus(t *testing.T) {
+	replicas := int32(3)
+	tr := &monitoringv1.ThanosRuler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 3},
+		Spec: monitoringv1.ThanosRulerSpec{
+			Replicas: &replicas,
+		},
+	}
+
+	sset := func(replicas, updated, ready int32) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			Status: appsv1.StatefulSetStatus{
+				Replicas:        replicas,
+				UpdatedReplicas: updated,
+				ReadyReplicas:   ready,
+			},
+		}
+	}
+
+	earlier := metav1.NewTime(time.Unix(1000, 0))
+	now := metav1.NewTime(time.Unix(2000, 0))
+
+	for _, tc := range []struct {
+		name             string
+		paused           bool
+		sset             *appsv1.StatefulSet
+		selection        *ruleSelection
+		reconcileErr     error
+		previous         *monitoringv1.ThanosRulerStatus
+		expected         map[monitoringv1.ConditionType]monitoringv1.ConditionStatus
+		expectedMessages map[monitoringv1.ConditionType]string
+		unavailable      int32
+		selected         int32
+	}{
+		{
+			name:      "all pods ready",
+			sset:      sset(3, 3, 3),
+			selection: &ruleSelection{selected: 2},
+			expected: map[monitoringv1.ConditionType]monitoringv1.ConditionStatus{
+				monitoringv1.Available:  monitoringv1.ConditionTrue,
+				monitoringv1.Reconciled: monitoringv1.ConditionTrue,
+				monitoringv1.Degraded:   monitoringv1.ConditionFalse,
+			},
+			selected: 2,
+		},
+		{
+			name:      "rolling update",
+			sset:      sset(3, 2, 2),
+			selection: &ruleSelection{},
+			expected: map[monitoringv1.ConditionType]monitoringv1.ConditionStatus{
+				monitoringv1.Available:  monitoringv1.ConditionTrue,
+				monitoringv1.Reconciled: monitoringv1.ConditionTrue,
+				monitoringv1.Degraded:   monitoringv1.ConditionTrue,
+			},
+			unavailable: 1,
+		},
+		{
+			name: "rejected PrometheusRules",
+			sset: sset(3, 3, 3),
+			selection: &ruleSelection{
+				selected: 1,
+				rejected: map[string]string{
+					"ns2/bar": "unexpected label",
+					"ns1/foo": "parse error",
+				},
+			},
+			expected: map[monitoringv1.ConditionType]monitoringv1.ConditionStatus{
+				monitoringv1.Available:         monitoringv1.ConditionTrue,
+				monitoringv1.Reconciled:        monitoringv1.ConditionTrue,
+				monitoringv1.Degraded:          monitoringv1.ConditionFalse,
+				monitoringv1.RejectedResources: monitoringv1.ConditionTrue,
+			},
+			expectedMessages: map[monitoringv1.ConditionType]string{
+				monitoringv1.RejectedResources: "2 resource(s) rejected: PrometheusRule ns1/foo: parse error; PrometheusRule ns2/bar: unexpected label",
+			},
+			selected: 1,
+		},
+		{
+			name:         "missing statefulset and rule generation failure",
+			reconcileErr: errors.New("failed to apply ConfigMap 'thanos-ruler-test-rulefiles-0'"),
+			previous: &monitoringv1.ThanosRulerStatus{
+				SelectedPrometheusRules: 4,
+				Conditions: []monitoringv1.Condition{
+					{Type: monitoringv1.RejectedResources, Status: monitoringv1.ConditionTrue, LastTransitionTime: earlier, Message: "previous"},
+				},
+			},
+			expected: map[monitoringv1.ConditionType]monitoringv1.ConditionStatus{
+				monitoringv1.Available:         monitoringv1.ConditionFalse,
+				monitoringv1.Reconciled:        monitoringv1.ConditionFalse,
+				monitoringv1.Degraded:          monitoringv1.ConditionTrue,
+				monitoringv1.RejectedResources: monitoringv1.ConditionTrue,
+			},
+			expectedMessages: map[monitoringv1.ConditionType]string{
+				monitoringv1.Reconciled:        "failed to apply ConfigMap 'thanos-ruler-test-rulefiles-0'",
+				monitoringv1.RejectedResources: "previous",
+			},
+			unavailable: 3,
+			selected:    4,
+		},
+		{
+			name:      "previously rejected PrometheusRules",
+			sset:      sset(3, 3, 3),
+			selection: &ruleSelection{selected: 2},
+			previous: &monitoringv1.ThanosRulerStatus{
+				Conditions: []monitoringv1.Condition{
+					{Type: monitoringv1.RejectedResources, Status: monitoringv1.ConditionTrue, LastTransitionTime: earlier},
+					{Type: monitoringv1.Available, Status: monitoringv1.ConditionTrue, LastTransitionTime: earlier},
+				},
+			},
+			expected: map[monitoringv1.ConditionType]monitoringv1.ConditionStatus{
+				monitoringv1.Available:         monitoringv1.ConditionTrue,
+				monitoringv1.Reconciled:        monitoringv1.ConditionTrue,
+				monitoringv1.Degraded:          monitoringv1.ConditionFalse,
+				monitoringv1.RejectedResources: monitoringv1.ConditionFalse,
+			},
+			selected: 2,
+		},
+		{
+			name:   "paused",
+			paused: true,
+			sset:   sset(3, 3, 3),
+			previous: &monitoringv1.ThanosRulerStatus{
+				SelectedPrometheusRules: 2,
+			},
+			expected: map[monitoringv1.ConditionType]monitoringv1.ConditionStatus{
+				monitoringv1.Available:  monitoringv1.ConditionTrue,
+				monitoringv1.Reconciled: monitoringv1.ConditionUnknown,
+				monitoringv1.Degraded:   monitoringv1.ConditionFalse,
+			},
+			selected: 2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := tr.DeepCopy()
+			tr.Spec.Paused = tc.paused
+			tr.Status = tc.previous
+
+			status := newThanosRulerStatus(tr, tc.sset, tc.selection, tc.reconcileErr, now)
+
+			if status.Paused != tc.paused {
+				t.Fatalf("expected paused to be %v, got %v", tc.paused, status.Paused)
+			}
+
+			if status.UnavailableReplicas != tc.unavailable {
+				t.Fatalf("expected %d unavailable replicas, got %d", tc.unavailable, status.UnavailableReplicas)
+			}
+
+			if status.SelectedPrometheusRules != tc.selected {
+				t.Fatalf("expected %d selected PrometheusRules, got %d", tc.selected, status.SelectedPrometheusRules)
+			}
+
+			if len(status.Conditions) != len(tc.expected) {
+				t.Fatalf("expected %d conditions, got %d", len(tc.expected), len(status.Conditions))
+			}
+
+			var previous []monitoringv1.Condition
+			if tc.previous != nil {
+				previous = tc.previous.Conditions
+			}
+
+			for _, c := range status.Conditions {
+				if c.Status != tc.expected[c.Type] {
+					t.Fatalf("expected condition %s to be %s, got %s", c.Type, tc.expected[c.Type], c.Status)
+				}
+
+				if msg, found := tc.expectedMessages[c.Type]; found && c.Message != msg {
+					t.Fatalf("expected condition %s to report %q, got %q", c.Type, msg, c.Message)
+				}
+
+				// The transition time is preserved when the status doesn't change.
+				expectedTime := now
+				if prev := findCondition(previous, c.Type); prev != nil && prev.Status == c.Status {
+					expectedTime = earlier
+				}
+				if !c.LastTransitionTime.Equal(&expectedTime) {
+					t.Fatalf("expected condition %s to transition at %v, got %v", c.Type, expectedTime, c.LastTransitionTime)
+				}
+			}
+		})
+	}
+}
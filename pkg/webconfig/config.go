@@ -35,13 +35,15 @@ var (
 // necessary TLS credentials.
 type Config struct {
 	tlsConfig      *monitoringv1.WebTLSConfig
+	httpConfig     *monitoringv1.WebHTTPConfig
 	tlsCredentials *tlsCredentials
+	basicAuthUsers map[string]string
 	mountingDir    string
 	secretName     string
 }
 
 // New creates a new Config.
-func New(mountingDir string, secretName string, tlsConfig *monitoringv1.WebTLSConfig) (*Config, error) {
+func New(mountingDir string, secretName string, tlsConfig *monitoringv1.WebTLSConfig, httpConfig *monitoringv1.WebHTTPConfig) (*Config, error) {
 	if err := tlsConfig.Validate(); err != nil {
 		return nil, err
 	}
@@ -53,12 +55,20 @@ func New(mountingDir string, secretName string, tlsConfig *monitoringv1.WebTLSCo
 
 	return &Config{
 		tlsConfig:      tlsConfig,
+		httpConfig:     httpConfig,
 		tlsCredentials: tlsCreds,
 		mountingDir:    mountingDir,
 		secretName:     secretName,
 	}, nil
 }
 
+// SetBasicAuthUsers sets the users allowed to access the web server with
+// basic authentication. The map's values are the bcrypt hashes of the
+// passwords.
+func (c *Config) SetBasicAuthUsers(users map[string]string) {
+	c.basicAuthUsers = users
+}
+
 // GetMountParameters returns volumes and volume mounts referencing the config file
 // and the associated TLS credentials.
 // In addition, GetMountParameters returns a web.config.file command line option pointing
@@ -107,11 +117,28 @@ func (c *Config) MakeConfigFileSecret(labels map[string]string, ownerReference m
 }
 
 func (c Config) generateConfigFileContents() ([]byte, error) {
-	tls := c.tlsConfig
-	if tls == nil {
+	if c.tlsConfig == nil && c.httpConfig == nil && len(c.basicAuthUsers) == 0 {
 		return []byte{}, nil
 	}
 
+	cfg := yaml.MapSlice{}
+	if c.tlsConfig != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "tls_server_config", Value: c.makeTLSServerConfig()})
+	}
+
+	if c.httpConfig != nil {
+		cfg = append(cfg, yaml.MapItem{Key: "http_server_config", Value: c.makeHTTPServerConfig()})
+	}
+
+	if len(c.basicAuthUsers) > 0 {
+		cfg = append(cfg, yaml.MapItem{Key: "basic_auth_users", Value: c.basicAuthUsers})
+	}
+
+	return yaml.Marshal(cfg)
+}
+
+func (c Config) makeTLSServerConfig() yaml.MapSlice {
+	tls := c.tlsConfig
 	tlsServerConfig := yaml.MapSlice{}
 	if certPath := c.tlsCredentials.getCertMountPath(); certPath != "" {
 		tlsServerConfig = append(tlsServerConfig, yaml.MapItem{Key: "cert_file", Value: certPath})
@@ -167,14 +194,41 @@ func (c Config) generateConfigFileContents() ([]byte, error) {
 		})
 	}
 
-	cfg := yaml.MapSlice{
-		{
-			Key:   "tls_server_config",
-			Value: tlsServerConfig,
-		},
+	return tlsServerConfig
+}
+
+func (c Config) makeHTTPServerConfig() yaml.MapSlice {
+	httpServerConfig := yaml.MapSlice{}
+	if c.httpConfig.HTTP2 != nil {
+		httpServerConfig = append(httpServerConfig, yaml.MapItem{Key: "http2", Value: *c.httpConfig.HTTP2})
 	}
 
-	return yaml.Marshal(cfg)
+	headers := c.httpConfig.Headers
+	if headers == nil {
+		return httpServerConfig
+	}
+
+	headersConfig := yaml.MapSlice{}
+	for _, h := range []struct {
+		name  string
+		value string
+	}{
+		{name: "Content-Security-Policy", value: headers.ContentSecurityPolicy},
+		{name: "Strict-Transport-Security", value: headers.StrictTransportSecurity},
+		{name: "X-Content-Type-Options", value: headers.XContentTypeOptions},
+		{name: "X-Frame-Options", value: headers.XFrameOptions},
+		{name: "X-XSS-Protection", value: headers.XXSSProtection},
+	} {
+		if h.value != "" {
+			headersConfig = append(headersConfig, yaml.MapItem{Key: h.name, Value: h.value})
+		}
+	}
+
+	if len(headersConfig) > 0 {
+		httpServerConfig = append(httpServerConfig, yaml.MapItem{Key: "headers", Value: headersConfig})
+	}
+
+	return httpServerConfig
 }
 
 func (c Config) makeArg(filePath string) string {
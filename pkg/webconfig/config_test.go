@@ -27,9 +27,11 @@ var falseVal = false
 
 func TestMakeSecret(t *testing.T) {
 	tc := []struct {
-		name         string
-		webTLSConfig *monitoringv1.WebTLSConfig
-		expectedData string
+		name           string
+		webTLSConfig   *monitoringv1.WebTLSConfig
+		webHTTPConfig  *monitoringv1.WebHTTPConfig
+		basicAuthUsers map[string]string
+		expectedData   string
 	}{
 		{
 			name:         "tls config not defined",
@@ -160,15 +162,71 @@ func TestMakeSecret(t *testing.T) {
   curve_preferences:
   - curve-1
   - curve-2
+`,
+		},
+		{
+			name: "HTTP config without TLS config",
+			webHTTPConfig: &monitoringv1.WebHTTPConfig{
+				HTTP2: &falseVal,
+			},
+			expectedData: `http_server_config:
+  http2: false
+`,
+		},
+		{
+			name: "TLS and HTTP config with headers",
+			webTLSConfig: &monitoringv1.WebTLSConfig{
+				Cert: monitoringv1.SecretOrConfigMap{
+					Secret: &v1.SecretKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{
+							Name: "test-secret",
+						},
+						Key: "tls.crt",
+					},
+				},
+				KeySecret: v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: "test-secret",
+					},
+					Key: "tls.key",
+				},
+			},
+			webHTTPConfig: &monitoringv1.WebHTTPConfig{
+				Headers: &monitoringv1.WebHTTPHeaders{
+					StrictTransportSecurity: "max-age=31536000; includeSubDomains",
+					XFrameOptions:           "Deny",
+					XContentTypeOptions:     "NoSniff",
+				},
+			},
+			expectedData: `tls_server_config:
+  cert_file: /web_certs_path_prefix/secret_test-secret_tls.crt
+  key_file: /web_certs_path_prefix/secret_test-secret_tls.key
+http_server_config:
+  headers:
+    Strict-Transport-Security: max-age=31536000; includeSubDomains
+    X-Content-Type-Options: NoSniff
+    X-Frame-Options: Deny
+`,
+		},
+		{
+			name: "basic auth users",
+			basicAuthUsers: map[string]string{
+				"bob":   "$2y$10$hash-bob",
+				"alice": "$2y$10$hash-alice",
+			},
+			expectedData: `basic_auth_users:
+  alice: $2y$10$hash-alice
+  bob: $2y$10$hash-bob
 `,
 		},
 	}
 
 	for _, tt := range tc {
-		config, err := webconfig.New("/web_certs_path_prefix", "test-secret", tt.webTLSConfig)
+		config, err := webconfig.New("/web_certs_path_prefix", "test-secret", tt.webTLSConfig, tt.webHTTPConfig)
 		if err != nil {
 			t.Fatal(err)
 		}
+		config.SetBasicAuthUsers(tt.basicAuthUsers)
 
 		secret, err := config.MakeConfigFileSecret(nil, metav1.OwnerReference{})
 		if err != nil {
@@ -307,7 +365,7 @@ func TestGetMountParameters(t *testing.T) {
 	}
 
 	for _, tt := range ts {
-		tlsAssets, err := webconfig.New("/etc/prometheus/web_config", "web-config", tt.tlsConfig)
+		tlsAssets, err := webconfig.New("/etc/prometheus/web_config", "web-config", tt.tlsConfig, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
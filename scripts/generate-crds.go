@@ -39,7 +39,11 @@ type crdGenerator struct {
 	CRDNames          []crdName
 	CRDAPIGroup       string
 	ControllerPath    string
-	CustomizeYAML     func(crdGenerator) error
+	// Paths relative to ControllerPath which are loaded by controller-gen.
+	// All the versions of a CRD need to be loaded at once to generate a
+	// multi-version CRD. Defaults to ".".
+	Paths         []string
+	CustomizeYAML func(crdGenerator) error
 }
 
 var (
@@ -71,9 +75,12 @@ var (
 			YAMLDir:           "./example/prometheus-operator-crd",
 			JsonnetDir:        "./jsonnet/prometheus-operator",
 			CRDAPIGroup:       "monitoring.coreos.com",
-			ControllerPath:    "./pkg/apis/monitoring/v1alpha1",
+			ControllerPath:    "./pkg/apis/monitoring",
+			Paths:             []string{"./v1alpha1", "./v1beta1"},
 			CRDNames: []crdName{
 				{"alertmanagerconfig", "alertmanagerconfigs"},
+				{"prometheusagent", "prometheusagents"},
+				{"scrapeconfig", "scrapeconfigs"},
 			},
 		},
 	}
@@ -84,9 +91,13 @@ func (generator crdGenerator) generateYAMLManifests() error {
 	if err != nil {
 		return errors.Wrapf(err, "absolute CRD output path %s", generator.YAMLDir)
 	}
+	paths := "."
+	if len(generator.Paths) > 0 {
+		paths = strings.Join(generator.Paths, ";")
+	}
 	cmd := exec.Command(controllergen,
 		generator.ControllerGenOpts,
-		"paths=.",
+		"paths="+paths,
 		"output:crd:dir="+outputDir,
 	)
 	cmd.Dir, err = filepath.Abs(generator.ControllerPath)
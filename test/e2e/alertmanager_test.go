@@ -15,8 +15,11 @@
 package e2e
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"strconv"
 	"strings"
 	"testing"
@@ -1027,8 +1030,9 @@ func testAlertmanagerConfigCRD(t *testing.T) {
 			return false, nil
 		}
 
-		if cfgSecret.Data["alertmanager.yaml"] == nil {
-			lastErr = errors.New("'alertmanager.yaml' key is missing in generated configuration secret")
+		cfg, err := generatedAlertmanagerConfig(cfgSecret)
+		if err != nil {
+			lastErr = err
 			return false, nil
 		}
 
@@ -1111,7 +1115,7 @@ receivers:
 templates: []
 `, configNs, configNs, configNs, configNs, configNs, configNs, configNs, configNs, configNs)
 
-		if diff := cmp.Diff(string(cfgSecret.Data["alertmanager.yaml"]), expected); diff != "" {
+		if diff := cmp.Diff(string(cfg), expected); diff != "" {
 			lastErr = errors.Errorf("got(-), want(+):\n%s", diff)
 			return false, nil
 		}
@@ -1137,8 +1141,9 @@ templates: []
 			return false, nil
 		}
 
-		if cfgSecret.Data["alertmanager.yaml"] == nil {
-			lastErr = errors.New("'alertmanager.yaml' key is missing in generated configuration secret")
+		cfg, err := generatedAlertmanagerConfig(cfgSecret)
+		if err != nil {
+			lastErr = err
 			return false, nil
 		}
 		expected := `global:
@@ -1159,7 +1164,7 @@ receivers:
 templates: []
 `
 
-		if diff := cmp.Diff(string(cfgSecret.Data["alertmanager.yaml"]), expected); diff != "" {
+		if diff := cmp.Diff(string(cfg), expected); diff != "" {
 			lastErr = errors.Errorf("got(-), want(+):\n%s", diff)
 			return false, nil
 		}
@@ -1229,13 +1234,14 @@ inhibit_rules:
 			return false, nil
 		}
 
-		if cfgSecret.Data["alertmanager.yaml"] == nil {
-			lastErr = errors.New("'alertmanager.yaml' key is missing")
+		cfg, err := generatedAlertmanagerConfig(cfgSecret)
+		if err != nil {
+			lastErr = err
 			return false, nil
 		}
 
-		if string(cfgSecret.Data["alertmanager.yaml"]) != yamlConfig {
-			lastErr = errors.Errorf("expected Alertmanager configuration %q, got %q", yamlConfig, cfgSecret.Data["alertmanager.yaml"])
+		if string(cfg) != yamlConfig {
+			lastErr = errors.Errorf("expected Alertmanager configuration %q, got %q", yamlConfig, cfg)
 			return false, nil
 		}
 
@@ -1353,7 +1359,7 @@ func testAMPreserveUserAddedMetadata(t *testing.T) {
 	}
 }
 
-func testAMRollbackManualChanges(t *testing.T) {
+func testAMManualChangesConflict(t *testing.T) {
 	t.Parallel()
 
 	testCtx := framework.NewTestCtx(t)
@@ -1374,19 +1380,47 @@ func testAMRollbackManualChanges(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// The update takes over the ownership of the replicas field: the
+	// operator doesn't force it back and reports the conflict instead.
 	replicas := int32(0)
 	sset.Spec.Replicas = &replicas
 	if _, err := ssetClient.Update(context.Background(), sset, metav1.UpdateOptions{}); err != nil {
 		t.Fatal(err)
 	}
 
-	if err := framework.WaitForAlertmanagerReady(context.Background(), ns, name, 0, false); err != nil {
-		t.Fatal(err)
+	var pollErr error
+	err = wait.Poll(5*time.Second, 2*time.Minute, func() (bool, error) {
+		events, err := framework.KubeClient.CoreV1().Events(ns).List(context.Background(), metav1.ListOptions{
+			FieldSelector: fields.SelectorFromSet(fields.Set{
+				"involvedObject.kind": monitoringv1.AlertmanagersKind,
+				"involvedObject.name": name,
+				"reason":              "FieldManagerConflict",
+			}).String(),
+		})
+		if err != nil {
+			pollErr = err
+			return false, nil
+		}
+
+		if len(events.Items) == 0 {
+			pollErr = errors.New("no FieldManagerConflict event")
+			return false, nil
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("%v: %v", err, pollErr)
 	}
 
-	if err := framework.WaitForAlertmanagerReady(context.Background(), ns, name, 3, false); err != nil {
+	sset, err = ssetClient.Get(context.Background(), "alertmanager-"+name, metav1.GetOptions{})
+	if err != nil {
 		t.Fatal(err)
 	}
+
+	if *sset.Spec.Replicas != 0 {
+		t.Fatalf("expected the manual change to be preserved, got %d replicas", *sset.Spec.Replicas)
+	}
 }
 
 func testAlertManagerMinReadySeconds(t *testing.T) {
@@ -1431,3 +1465,19 @@ func testAlertManagerMinReadySeconds(t *testing.T) {
 		t.Fatalf("expected MinReadySeconds to be %d but got %d", updated, amSS.Spec.MinReadySeconds)
 	}
 }
+
+// generatedAlertmanagerConfig returns the decompressed configuration of the
+// secret generated by the operator.
+func generatedAlertmanagerConfig(s *v1.Secret) ([]byte, error) {
+	data, found := s.Data["alertmanager.yaml.gz"]
+	if !found {
+		return nil, errors.New("'alertmanager.yaml.gz' key is missing in generated configuration secret")
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decompress the generated configuration")
+	}
+
+	return ioutil.ReadAll(r)
+}
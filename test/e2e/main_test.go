@@ -159,7 +159,7 @@ func testAllNSAlertmanager(t *testing.T) {
 		"AMAlertmanagerConfigCRD":         testAlertmanagerConfigCRD,
 		"AMUserDefinedAlertmanagerConfig": testUserDefinedAlertmanagerConfig,
 		"AMPreserveUserAddedMetadata":     testAMPreserveUserAddedMetadata,
-		"AMRollbackManualChanges":         testAMRollbackManualChanges,
+		"AMManualChangesConflict":         testAMManualChangesConflict,
 		"AMMinReadySeconds":               testAlertManagerMinReadySeconds,
 	}
 